@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -20,12 +21,16 @@ func NewCommand() *cobra.Command {
 		Use:   "acl",
 		Short: "Audit SharePoint permissions and access controls",
 		Long: `Audit SharePoint permissions to find external shares, broken inheritance,
-and anonymous links. Read-only — never modifies permissions.
+and anonymous links. Audits are read-only; revoke and remove-link change
+permissions only with --confirm and log every change.
 
 Example:
-  kit acl audit --site <site-id>
-  kit acl external --site <site-id>
-  kit acl broken --site <site-id>`,
+  kit acl audit --site <site>
+  kit acl audit --onedrive
+  kit acl external --site <site>
+  kit acl broken --site <site>
+  kit acl diff last-week.json this-week.json
+  kit acl revoke --site <site> --file <path> --user <email> --dry-run`,
 	}
 
 	cmd.AddCommand(newAuditCmd())
@@ -33,6 +38,11 @@ Example:
 	cmd.AddCommand(newBrokenCmd())
 	cmd.AddCommand(newUsersCmd())
 	cmd.AddCommand(newCheckCmd())
+	cmd.AddCommand(newRevokeCmd())
+	cmd.AddCommand(newRemoveLinkCmd())
+	cmd.AddCommand(newLogCmd())
+	cmd.AddCommand(newDiffCmd())
+	cmd.AddCommand(newPolicyCmd())
 
 	return cmd
 }
@@ -42,14 +52,41 @@ func newAuditCmd() *cobra.Command {
 		siteID  string
 		domain  string
 		output  string
+		labels   bool
+		policy   string
+		oneDrive bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "audit",
-		Short: "Audit all permissions on a SharePoint site",
+		Short: "Audit all permissions on a SharePoint site or your OneDrive",
+		Long: `Audit all permissions on a SharePoint site, or with --onedrive, on your
+own OneDrive to see what you have shared and with whom.
+
+With --policy, the report is also checked against sharing rules (links
+without expiration, anonymous edit links, external users who can edit) and
+the command exits non-zero when a violation reaches the policy's fail_on
+severity. Use --policy default for the built-in rules, or create an
+editable acl-policy.yaml with 'kit acl policy init'.`,
+		Example: `  kit acl audit --site Legal --domain contoso.com
+  kit acl audit --site Legal -o legal.xlsx
+  kit acl audit --site Legal --policy acl-policy.yaml --json
+  kit acl audit --onedrive`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if siteID == "" {
-				return fmt.Errorf("--site is required")
+			if siteID == "" && !oneDrive {
+				return fmt.Errorf("--site or --onedrive is required")
+			}
+			if siteID != "" && oneDrive {
+				return fmt.Errorf("use either --site or --onedrive, not both")
+			}
+			var pol *graph.ACLPolicy
+			if policy == "default" {
+				pol = graph.DefaultACLPolicy()
+			} else if policy != "" {
+				var err error
+				if pol, err = graph.LoadACLPolicy(policy); err != nil {
+					return err
+				}
 			}
 
 			client, err := auth.RequireAuth(cmd.Context())
@@ -58,27 +95,44 @@ func newAuditCmd() *cobra.Command {
 			}
 
 			a := graph.NewACL(client, domain)
-			report, err := a.AuditSitePermissions(cmd.Context(), siteID)
+			a.Labels = labels
+			var report *graph.ACLReport
+			if oneDrive {
+				report, err = a.AuditMyDrive(cmd.Context())
+			} else {
+				siteID, err = graph.NewSharePoint(client).ResolveSite(cmd.Context(), siteID)
+				if err != nil {
+					return err
+				}
+				report, err = a.AuditSitePermissions(cmd.Context(), siteID)
+			}
 			if err != nil {
 				return err
 			}
+			if pol != nil {
+				report.Violations = pol.Evaluate(report, time.Now())
+			}
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut || output != "" {
-				data, _ := json.MarshalIndent(report, "", "  ")
 				if output != "" {
-					if err := os.WriteFile(output, data, 0644); err != nil {
+					if err := writeReport(report, output); err != nil {
 						return err
 					}
 					fmt.Printf("Audit report saved to %s\n", output)
-					return nil
+					return policyResult(pol, report)
 				}
+				data, _ := json.MarshalIndent(report, "", "  ")
 				fmt.Println(string(data))
-				return nil
+				return policyResult(pol, report)
 			}
 
 			// Human-readable output
-			fmt.Printf("SharePoint ACL Audit: %s\n", report.Site)
+			if oneDrive {
+				fmt.Printf("ACL Audit: %s\n", report.Site)
+			} else {
+				fmt.Printf("SharePoint ACL Audit: %s\n", report.Site)
+			}
 			fmt.Printf("Generated: %s\n\n", report.GeneratedAt.Format("2006-01-02 15:04 MST"))
 
 			fmt.Println("Summary")
@@ -86,6 +140,9 @@ func newAuditCmd() *cobra.Command {
 			fmt.Printf("  Files with external sharing:   %d\n", report.ExternalShares)
 			fmt.Printf("  Files with unique permissions: %d\n", report.BrokenInheritance)
 			fmt.Printf("  Anonymous share links:         %d\n", report.AnonymousLinks)
+			if labels {
+				fmt.Printf("  Items without labels:          %d\n", len(graph.FindUnlabeled(report)))
+			}
 
 			external := graph.FindExternalShares(report)
 			if len(external) > 0 {
@@ -114,13 +171,44 @@ func newAuditCmd() *cobra.Command {
 				}
 			}
 
-			return nil
+			if labels {
+				fmt.Println("\nLabels")
+				tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintf(tw, "ITEM\tSENSITIVITY\tRETENTION\n")
+				for _, entry := range report.Entries {
+					if entry.Labels == nil {
+						fmt.Fprintf(tw, "%s\t(unreadable)\t\n", entry.Path)
+						continue
+					}
+					fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.Path, orDash(entry.Labels.Sensitivity), orDash(entry.Labels.Retention))
+				}
+				tw.Flush()
+			}
+
+			if pol != nil {
+				if len(report.Violations) == 0 {
+					fmt.Println("\nPolicy: no violations")
+				} else {
+					fmt.Printf("\nPolicy Violations (%d)\n", len(report.Violations))
+					tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintf(tw, "SEVERITY\tCHECK\tFILE\tDETAIL\n")
+					for _, v := range report.Violations {
+						fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", v.Severity, v.Check, v.Path, v.Message)
+					}
+					tw.Flush()
+				}
+			}
+
+			return policyResult(pol, report)
 		},
 	}
 
-	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site ID or URL")
+	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site name, URL, or ID")
 	cmd.Flags().StringVar(&domain, "domain", "", "Organization domain for external detection (e.g., company.com)")
-	cmd.Flags().StringVarP(&output, "output", "o", "", "Export report to file (JSON)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Export report to file (.json, .csv, or .xlsx)")
+	cmd.Flags().BoolVar(&labels, "labels", false, "Include sensitivity and retention labels")
+	cmd.Flags().StringVar(&policy, "policy", "", "Check against a policy file, or 'default' for the built-in rules")
+	cmd.Flags().BoolVar(&oneDrive, "onedrive", false, "Audit your own OneDrive instead of a site")
 
 	return cmd
 }
@@ -145,6 +233,10 @@ func newExternalCmd() *cobra.Command {
 			}
 
 			a := graph.NewACL(client, domain)
+			siteID, err = graph.NewSharePoint(client).ResolveSite(cmd.Context(), siteID)
+			if err != nil {
+				return err
+			}
 			report, err := a.AuditSitePermissions(cmd.Context(), siteID)
 			if err != nil {
 				return err
@@ -172,7 +264,7 @@ func newExternalCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site ID")
+	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site name, URL, or ID")
 	cmd.Flags().StringVar(&domain, "domain", "", "Organization domain")
 	return cmd
 }
@@ -194,6 +286,10 @@ func newBrokenCmd() *cobra.Command {
 			}
 
 			a := graph.NewACL(client, "")
+			siteID, err = graph.NewSharePoint(client).ResolveSite(cmd.Context(), siteID)
+			if err != nil {
+				return err
+			}
 			report, err := a.AuditSitePermissions(cmd.Context(), siteID)
 			if err != nil {
 				return err
@@ -219,16 +315,25 @@ func newBrokenCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site ID")
+	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site name, URL, or ID")
 	return cmd
 }
 
 func newUsersCmd() *cobra.Command {
-	var siteID string
+	var (
+		siteID   string
+		noExpand bool
+		depth    int
+	)
 
 	cmd := &cobra.Command{
 		Use:   "users",
 		Short: "List all users with access to a site",
+		Long: `List everyone with access to files on a site. Permissions granted to
+Microsoft Entra groups are expanded into the group's members, following
+nested groups up to --depth levels; VIA shows which group grants access.
+Nested groups deeper than that are listed as "(group, not expanded)": their
+members have access too.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if siteID == "" {
 				return fmt.Errorf("--site is required")
@@ -240,27 +345,25 @@ func newUsersCmd() *cobra.Command {
 			}
 
 			a := graph.NewACL(client, "")
+			siteID, err = graph.NewSharePoint(client).ResolveSite(cmd.Context(), siteID)
+			if err != nil {
+				return err
+			}
 			report, err := a.AuditSitePermissions(cmd.Context(), siteID)
 			if err != nil {
 				return err
 			}
 
-			// Collect unique users
-			users := make(map[string]string)
+			var perms []graph.Permission
 			for _, entry := range report.Entries {
-				for _, p := range entry.Permissions {
-					email := p.GetEmail()
-					if email != "" {
-						name := ""
-						if p.GrantedToV2 != nil && p.GrantedToV2.User != nil {
-							name = p.GrantedToV2.User.DisplayName
-						} else if p.GrantedTo != nil && p.GrantedTo.User != nil {
-							name = p.GrantedTo.User.DisplayName
-						}
-						users[email] = name
-					}
-				}
+				perms = append(perms, entry.Permissions...)
 			}
+			var groups *graph.GroupExpander
+			if !noExpand {
+				groups = graph.NewGroupExpander(client)
+				groups.MaxDepth = depth
+			}
+			users := graph.CollectAccess(cmd.Context(), perms, groups)
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut {
@@ -268,46 +371,130 @@ func newUsersCmd() *cobra.Command {
 			}
 
 			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(tw, "EMAIL\tNAME\n")
-			for email, name := range users {
-				fmt.Fprintf(tw, "%s\t%s\n", email, name)
+			fmt.Fprintf(tw, "EMAIL\tNAME\tVIA\n")
+			truncated := 0
+			for _, u := range users {
+				email := u.Email
+				switch {
+				case u.Truncated:
+					email = "(group, not expanded)"
+					truncated++
+				case u.Group:
+					email = "(group)"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", email, u.Name, strings.Join(u.Via, ", "))
 			}
 			tw.Flush()
+			if truncated > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: %d nested groups lie deeper than --depth %d; their members also have access — raise --depth to list them\n", truncated, depth)
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site ID")
+	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site name, URL, or ID")
+	cmd.Flags().BoolVar(&noExpand, "no-expand", false, "List groups by name instead of expanding their members")
+	cmd.Flags().IntVar(&depth, "depth", 3, "Levels of nested groups to expand")
 	return cmd
 }
 
 func newCheckCmd() *cobra.Command {
 	var (
 		siteID string
+		drive  string
 		file   string
+		domain string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "check",
 		Short: "Check who has access to a specific file",
+		Long: `Show the permissions on a single file or folder, without auditing the
+whole site.`,
+		Example: `  kit acl check --site Legal --file /Contracts/nda.docx --domain contoso.com
+  kit acl check --site Legal --drive Archive --file /2023 --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if siteID == "" || file == "" {
 				return fmt.Errorf("--site and --file are required")
 			}
+			ctx := cmd.Context()
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			sp := graph.NewSharePoint(client)
+			site, err := sp.ResolveSite(ctx, siteID)
+			if err != nil {
+				return err
+			}
+			driveID, err := sp.ResolveLibrary(ctx, site, drive)
+			if err != nil {
+				return err
+			}
+			entry, err := graph.NewACL(client, domain).CheckItem(ctx, driveID, file)
+			if err != nil {
+				return err
+			}
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]string{
-					"message": "check requires audit data — use 'kit acl audit' for full results",
-				})
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(entry)
+			}
+
+			fmt.Printf("Permissions on %s\n", entry.Path)
+			if len(entry.Permissions) == 0 {
+				fmt.Println("  (none)")
+				return nil
+			}
+			external := map[string]bool{}
+			for _, u := range entry.ExternalUsers {
+				external[u] = true
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "GRANTEE\tROLES\tSOURCE\tEXPIRES\tNOTE\n")
+			for _, p := range entry.Permissions {
+				source := "direct"
+				if p.IsInherited() {
+					source = "inherited"
+				}
+				expires := "-"
+				if p.ExpiresAt != nil {
+					expires = p.ExpiresAt.Format("2006-01-02")
+				}
+				var notes []string
+				if external[p.GetEmail()] {
+					notes = append(notes, "external")
+				}
+				if p.IsAnonymousLink() {
+					notes = append(notes, "anyone with the link")
+				}
+				if p.HasPassword {
+					notes = append(notes, "password")
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", p.Grantee(), orDash(strings.Join(p.Roles, ", ")), source, expires, strings.Join(notes, ", "))
 			}
+			tw.Flush()
 
-			fmt.Println("Use 'kit acl audit --site <id> --json' and search for the file path.")
+			if !entry.HasUniquePermissions {
+				fmt.Println("\nAll permissions are inherited from the parent folder or site.")
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site ID")
-	cmd.Flags().StringVar(&file, "file", "", "File path to check")
+	cmd.Flags().StringVar(&siteID, "site", "", "SharePoint site name, URL, or ID")
+	cmd.Flags().StringVar(&drive, "drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().StringVar(&file, "file", "", "Path of the file or folder in the library")
+	cmd.Flags().StringVar(&domain, "domain", "", "Organization domain for external detection (e.g., company.com)")
 	return cmd
 }
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
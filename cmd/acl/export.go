@@ -0,0 +1,185 @@
+package acl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/formats/xlsx"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+var permissionHeader = []string{"Path", "Permission ID", "Grantee", "Roles", "Link Scope", "External", "Inherited", "Expires"}
+
+// permissionRows flattens a report into one row per permission.
+func permissionRows(report *graph.ACLReport) [][]string {
+	rows := [][]string{permissionHeader}
+	for _, entry := range report.Entries {
+		external := map[string]bool{}
+		for _, u := range entry.ExternalUsers {
+			external[u] = true
+		}
+		for _, p := range entry.Permissions {
+			var scope, expires string
+			if p.Link != nil {
+				scope = p.Link.Scope
+			}
+			if p.ExpiresAt != nil {
+				expires = p.ExpiresAt.Format("2006-01-02")
+			}
+			rows = append(rows, []string{
+				entry.Path,
+				p.ID,
+				p.Grantee(),
+				strings.Join(p.Roles, ", "),
+				scope,
+				yesNo(external[p.GetEmail()] || p.IsAnonymousLink()),
+				yesNo(p.IsInherited()),
+				expires,
+			})
+		}
+	}
+	return rows
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// writeReport saves a report as JSON, CSV, or XLSX, chosen by the file
+// extension.
+func writeReport(report *graph.ACLReport, path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		w := csv.NewWriter(f)
+		if err := w.WriteAll(permissionRows(report)); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+
+	case ".xlsx":
+		wb := &xlsx.Workbook{Sheets: []xlsx.Sheet{
+			{Name: "Summary", Rows: [][]string{
+				{"Site", report.Site},
+				{"Generated", report.GeneratedAt.Format("2006-01-02 15:04 MST")},
+				{"Total files scanned", strconv.Itoa(report.TotalFiles)},
+				{"Files with external sharing", strconv.Itoa(report.ExternalShares)},
+				{"Files with unique permissions", strconv.Itoa(report.BrokenInheritance)},
+				{"Anonymous share links", strconv.Itoa(report.AnonymousLinks)},
+			}},
+			{Name: "Permissions", Rows: permissionRows(report)},
+		}}
+		var labels [][]string
+		for _, entry := range report.Entries {
+			if entry.Labels != nil {
+				labels = append(labels, []string{entry.Path, entry.Labels.Sensitivity, entry.Labels.Retention})
+			}
+		}
+		if len(report.Violations) > 0 {
+			rows := [][]string{{"Severity", "Check", "Path", "Permission ID", "Grantee", "Detail"}}
+			for _, v := range report.Violations {
+				rows = append(rows, []string{v.Severity, v.Check, v.Path, v.PermissionID, v.Grantee, v.Message})
+			}
+			wb.Sheets = append(wb.Sheets, xlsx.Sheet{Name: "Violations", Rows: rows})
+		}
+		if len(labels) > 0 {
+			rows := append([][]string{{"Path", "Sensitivity", "Retention"}}, labels...)
+			wb.Sheets = append(wb.Sheets, xlsx.Sheet{Name: "Labels", Rows: rows})
+		}
+		return xlsx.WriteFile(wb, path)
+
+	default:
+		data, _ := json.MarshalIndent(report, "", "  ")
+		return os.WriteFile(path, data, 0644)
+	}
+}
+
+func readReport(path string) (*graph.ACLReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report graph.ACLReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("could not parse %s — expected a JSON report from 'kit acl audit -o': %w", path, err)
+	}
+	return &report, nil
+}
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "Compare two audit reports",
+		Long: `Compare two JSON reports from 'kit acl audit -o' and show newly added
+external users, new anonymous links, and removed permissions.
+
+Run the audit on a schedule (e.g. weekly from cron) and diff against the
+previous report for compliance review.`,
+		Example: `  kit acl audit --site Legal -o legal-2026-10-09.json
+  kit acl audit --site Legal -o legal-2026-10-16.json
+  kit acl diff legal-2026-10-09.json legal-2026-10-16.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			old, err := readReport(args[0])
+			if err != nil {
+				return err
+			}
+			current, err := readReport(args[1])
+			if err != nil {
+				return err
+			}
+			diff := graph.DiffReports(old, current)
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(diff)
+			}
+
+			fmt.Printf("ACL changes: %s → %s\n",
+				old.GeneratedAt.Format("2006-01-02 15:04"), current.GeneratedAt.Format("2006-01-02 15:04"))
+			if diff.Empty() {
+				fmt.Println("\nNo changes")
+				return nil
+			}
+
+			red := color.New(color.FgRed).SprintFunc()
+			yellow := color.New(color.FgYellow).SprintFunc()
+			printDiffSection(red(fmt.Sprintf("New external users (%d)", len(diff.NewExternalUsers))), diff.NewExternalUsers)
+			printDiffSection(red(fmt.Sprintf("New anonymous links (%d)", len(diff.NewAnonymousLinks))), diff.NewAnonymousLinks)
+			printDiffSection(yellow(fmt.Sprintf("Removed permissions (%d)", len(diff.RemovedPermissions))), diff.RemovedPermissions)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func printDiffSection(title string, changes []graph.ACLChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", title)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "FILE\tGRANTEE\tROLES\n")
+	for _, c := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Path, c.Grantee, orDash(strings.Join(c.Roles, ", ")))
+	}
+	tw.Flush()
+}
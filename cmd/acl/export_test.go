@@ -0,0 +1,54 @@
+package acl
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func TestWriteReportCSV(t *testing.T) {
+	report := &graph.ACLReport{Entries: []graph.ACLEntry{{
+		Path:          "a.docx",
+		ExternalUsers: []string{"x@ext.com"},
+		Permissions: []graph.Permission{
+			{ID: "1", Roles: []string{"read"}, GrantedToV2: &graph.Principal{User: &graph.GraphUser{Email: "x@ext.com"}}},
+			{ID: "2", Roles: []string{"write"}, Link: &graph.PermLink{Scope: "organization", Type: "edit"}},
+		},
+	}}}
+	path := filepath.Join(t.TempDir(), "report.csv")
+	if err := writeReport(report, path); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want header + 2", len(rows))
+	}
+	if rows[1][2] != "x@ext.com" || rows[1][5] != "yes" {
+		t.Errorf("row 1 = %v", rows[1])
+	}
+	if rows[2][2] != "organization edit link" || rows[2][4] != "organization" || rows[2][5] != "no" {
+		t.Errorf("row 2 = %v", rows[2])
+	}
+}
+
+func TestWriteReportXLSX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.xlsx")
+	if err := writeReport(&graph.ACLReport{Site: "s"}, path); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Fatalf("xlsx not written: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package acl
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// policyResult fails the command when the report has violations at or
+// above the policy's fail_on severity, so CI jobs can gate on the audit.
+func policyResult(pol *graph.ACLPolicy, report *graph.ACLReport) error {
+	if pol == nil {
+		return nil
+	}
+	if failures := pol.Failures(report.Violations); len(failures) > 0 {
+		return fmt.Errorf("%d policy violations at or above %s severity", len(failures), pol.FailOn)
+	}
+	return nil
+}
+
+func newPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Manage the sharing policy checked by 'kit acl audit --policy'",
+	}
+
+	var force bool
+	initCmd := &cobra.Command{
+		Use:   "init [path]",
+		Short: "Write an editable acl-policy.yaml with the default rules",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "acl-policy.yaml"
+			if len(args) == 1 {
+				path = args[0]
+			}
+			if _, err := os.Stat(path); err == nil && !force {
+				return fmt.Errorf("%s already exists — use --force to overwrite", path)
+			}
+			if err := os.WriteFile(path, []byte(graph.ACLPolicyTemplate), 0644); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s — run 'kit acl audit --site <site> --policy %s'\n", path, path)
+			return nil
+		},
+	}
+	initCmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing file")
+
+	cmd.AddCommand(initCmd)
+	return cmd
+}
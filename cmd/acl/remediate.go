@@ -0,0 +1,299 @@
+package acl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// operation is one applied permission change, as recorded in the log.
+type operation struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Command      string    `json:"command"`
+	Site         string    `json:"site"`
+	File         string    `json:"file"`
+	PermissionID string    `json:"permissionId"`
+	Action       string    `json:"action"`
+	Description  string    `json:"description"`
+	Error        string    `json:"error,omitempty"`
+}
+
+func operationLogPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".kit", "acl-operations.log")
+}
+
+// logOperation appends op to the operation log. Failures are reported but
+// never undo a change that has already been made.
+func logOperation(op operation) {
+	path := operationLogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+		if f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err == nil {
+			defer f.Close()
+			data, _ := json.Marshal(op)
+			if _, err := f.Write(append(data, '\n')); err == nil {
+				return
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "warning: could not write %s\n", path)
+}
+
+// remediation holds the flags shared by revoke and remove-link.
+type remediation struct {
+	site, drive, file string
+	dryRun, confirm   bool
+}
+
+func (r *remediation) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&r.site, "site", "", "SharePoint site name, URL, or ID")
+	cmd.Flags().StringVar(&r.drive, "drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().StringVar(&r.file, "file", "", "Path of the file or folder in the library")
+	cmd.Flags().BoolVar(&r.dryRun, "dry-run", false, "Show what would change without changing anything")
+	cmd.Flags().BoolVar(&r.confirm, "confirm", false, "Apply the changes")
+}
+
+// run resolves the item, plans changes with plan, and applies them unless
+// this is a dry run.
+func (r *remediation) run(cmd *cobra.Command, name string, plan func([]graph.Permission) ([]graph.PermissionChange, int, error)) error {
+	if r.site == "" || r.file == "" {
+		return fmt.Errorf("--site and --file are required")
+	}
+	if !r.dryRun && !r.confirm {
+		return fmt.Errorf("%s changes permissions — preview with --dry-run, then rerun with --confirm", name)
+	}
+	ctx := cmd.Context()
+
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return err
+	}
+	sp := graph.NewSharePoint(client)
+	siteID, err := sp.ResolveSite(ctx, r.site)
+	if err != nil {
+		return err
+	}
+	driveID, err := sp.ResolveLibrary(ctx, siteID, r.drive)
+	if err != nil {
+		return err
+	}
+	item, err := sp.GetLibraryItem(ctx, siteID, driveID, r.file)
+	if err != nil {
+		return err
+	}
+
+	a := graph.NewACL(client, "")
+	perms, err := a.GetFilePermissions(ctx, siteID, driveID, item.ID)
+	if err != nil {
+		return err
+	}
+	changes, inherited, err := plan(perms)
+	if err != nil {
+		return err
+	}
+
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	if len(changes) == 0 {
+		if inherited > 0 {
+			return fmt.Errorf("nothing to change on %s — %d matching permissions are inherited; change them on the parent folder or site", r.file, inherited)
+		}
+		if jsonOut {
+			return json.NewEncoder(os.Stdout).Encode(map[string]any{"file": r.file, "changes": []graph.PermissionChange{}})
+		}
+		fmt.Printf("Nothing to change on %s\n", r.file)
+		return nil
+	}
+
+	if r.dryRun {
+		if jsonOut {
+			return json.NewEncoder(os.Stdout).Encode(map[string]any{"file": r.file, "dryRun": true, "changes": changes})
+		}
+		fmt.Printf("Dry run — %d changes on %s:\n", len(changes), r.file)
+		printChanges(changes)
+		if inherited > 0 {
+			fmt.Printf("\n%d inherited permissions must be changed on the parent\n", inherited)
+		}
+		return nil
+	}
+
+	var failed int
+	results := make([]operation, 0, len(changes))
+	for _, c := range changes {
+		op := operation{
+			Timestamp:    time.Now().UTC(),
+			Command:      name,
+			Site:         siteID,
+			File:         r.file,
+			PermissionID: c.PermissionID,
+			Action:       c.Action,
+			Description:  c.Description,
+		}
+		if err := a.ApplyPermissionChange(ctx, siteID, driveID, item.ID, c); err != nil {
+			op.Error = err.Error()
+			failed++
+		}
+		logOperation(op)
+		results = append(results, op)
+	}
+
+	if jsonOut {
+		if err := json.NewEncoder(os.Stdout).Encode(map[string]any{"file": r.file, "operations": results}); err != nil {
+			return err
+		}
+	} else {
+		for _, op := range results {
+			if op.Error != "" {
+				fmt.Fprintf(os.Stderr, "  failed: %s: %s\n", op.Description, op.Error)
+			} else {
+				fmt.Printf("  removed: %s\n", op.Description)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d changes failed — see %s", failed, len(changes), operationLogPath())
+	}
+	return nil
+}
+
+func printChanges(changes []graph.PermissionChange) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "PERMISSION\tACTION\tROLES\tDESCRIPTION\n")
+	for _, c := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.PermissionID, c.Action, strings.Join(c.Roles, ", "), c.Description)
+	}
+	tw.Flush()
+}
+
+func newRevokeCmd() *cobra.Command {
+	var (
+		r    remediation
+		user string
+	)
+	cmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke a user's access to a file or folder",
+		Long: `Revoke a user's direct access to a file or folder and remove them from
+sharing links on it. Links shared only with that user are deleted.
+
+Requires --confirm; preview the changes first with --dry-run. Applied changes
+are recorded in ~/.kit/acl-operations.log (see 'kit acl log').`,
+		Example: `  kit acl revoke --site Legal --file /Contracts/nda.docx --user ext@gmail.com --dry-run
+  kit acl revoke --site Legal --file /Contracts/nda.docx --user ext@gmail.com --confirm`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if user == "" {
+				return fmt.Errorf("--user is required")
+			}
+			return r.run(cmd, "revoke", func(perms []graph.Permission) ([]graph.PermissionChange, int, error) {
+				changes, inherited := graph.PlanRevoke(perms, user)
+				return changes, inherited, nil
+			})
+		},
+	}
+	r.addFlags(cmd)
+	cmd.Flags().StringVar(&user, "user", "", "Email address of the user to remove")
+	return cmd
+}
+
+func newRemoveLinkCmd() *cobra.Command {
+	var (
+		r      remediation
+		scope  string
+		linkID string
+	)
+	cmd := &cobra.Command{
+		Use:   "remove-link",
+		Short: "Delete sharing links on a file or folder",
+		Long: `Delete sharing links on a file or folder. Without --scope or --id, every
+link on the item is removed.
+
+Requires --confirm; preview the changes first with --dry-run. Applied changes
+are recorded in ~/.kit/acl-operations.log (see 'kit acl log').`,
+		Example: `  kit acl remove-link --site Legal --file /Contracts --scope anonymous --dry-run
+  kit acl remove-link --site Legal --file /Contracts --scope anonymous --confirm`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch scope {
+			case "", "anonymous", "organization", "users":
+			default:
+				return fmt.Errorf("invalid --scope %q — use anonymous, organization, or users", scope)
+			}
+			return r.run(cmd, "remove-link", func(perms []graph.Permission) ([]graph.PermissionChange, int, error) {
+				changes, inherited := graph.PlanRemoveLinks(perms, scope, linkID)
+				if linkID != "" && len(changes) == 0 && inherited == 0 {
+					return nil, 0, fmt.Errorf("no sharing link with ID %s on %s", linkID, r.file)
+				}
+				return changes, inherited, nil
+			})
+		},
+	}
+	r.addFlags(cmd)
+	cmd.Flags().StringVar(&scope, "scope", "", "Only links with this scope: anonymous, organization, or users")
+	cmd.Flags().StringVar(&linkID, "id", "", "Only the link with this permission ID")
+	return cmd
+}
+
+func newLogCmd() *cobra.Command {
+	var last int
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show permission changes made with revoke and remove-link",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(operationLogPath())
+			if os.IsNotExist(err) {
+				fmt.Println("No permission changes recorded")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			var ops []operation
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				var op operation
+				if json.Unmarshal(scanner.Bytes(), &op) == nil {
+					ops = append(ops, op)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				return err
+			}
+			if last > 0 && len(ops) > last {
+				ops = ops[len(ops)-last:]
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(ops)
+			}
+			if len(ops) == 0 {
+				fmt.Println("No permission changes recorded")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "TIME\tCOMMAND\tFILE\tCHANGE\tRESULT\n")
+			for _, op := range ops {
+				result := "ok"
+				if op.Error != "" {
+					result = "failed"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+					op.Timestamp.Local().Format("2006-01-02 15:04"), op.Command, op.File, op.Description, result)
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().IntVar(&last, "last", 20, "Show only the most recent entries (0 = all)")
+	return cmd
+}
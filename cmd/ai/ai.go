@@ -1,20 +1,78 @@
 // Package ai provides CLI commands for AI-powered document analysis.
 package ai
 
-import "github.com/spf13/cobra"
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/progress"
+)
+
+// Default chunk sizes, in tokens, for documents too long for one prompt.
+const (
+	defaultChunkTokens   = ai.DefaultChunkSize / 4
+	defaultOverlapTokens = ai.DefaultChunkOverlap / 4
+)
 
 // NewCommand returns the ai subcommand group.
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "ai",
 		Short: "AI-powered document analysis and transformation",
-		Long:  "Commands that use AI models to summarize, analyze, extract entities, and answer questions about documents.",
+		Long: `Commands that use AI models to summarize, analyze, extract entities, and answer questions about documents.
+
+Documents too long for one prompt are split into chunks of --chunk-size
+tokens (estimated at 4 characters per token), breaking between sections where
+possible. Each chunk is processed on its own, and the results are combined in a
+final prompt.`,
 	}
 
+	cmd.PersistentFlags().Int("chunk-size", defaultChunkTokens, "Maximum tokens per chunk for long documents")
+	cmd.PersistentFlags().Int("chunk-overlap", defaultOverlapTokens, "Tokens repeated between consecutive chunks")
+
 	cmd.AddCommand(newSummarizeCommand())
 	cmd.AddCommand(newAnalyzeCommand())
 	cmd.AddCommand(newExtractCommand())
 	cmd.AddCommand(newAskCommand())
+	cmd.AddCommand(newIndexCommand())
+	cmd.AddCommand(newFindCommand())
+	cmd.AddCommand(newUsageCommand())
 
 	return cmd
 }
+
+// condense makes input fit in one prompt: if it's longer than --chunk-size,
+// mapPrompt is run on each chunk first (see ai.Condense), with a spinner
+// showing progress.
+func condense(ctx context.Context, cmd *cobra.Command, provider ai.Provider, input, mapPrompt string) (*ai.Condensed, error) {
+	chunkSize, _ := cmd.Flags().GetInt("chunk-size")
+	overlap, _ := cmd.Flags().GetInt("chunk-overlap")
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("--chunk-size must be positive")
+	}
+
+	opts := ai.MapReduceOptions{
+		Chunk: ai.ChunkOptions{
+			MaxChunkSize: ai.TokensToChars(chunkSize),
+			Overlap:      ai.TokensToChars(overlap),
+		},
+		MapPrompt: mapPrompt,
+	}
+	if n := len(ai.ChunkDocument(input, opts.Chunk)); n > 1 {
+		spinner := progress.NewSpinner(fmt.Sprintf("Reading a long document in %d parts...", n))
+		spinner.Start()
+		defer spinner.Clear()
+		opts.Progress = func(done, total int) {
+			spinner.Update(fmt.Sprintf("Read %d of %d parts...", done, total))
+		}
+	}
+
+	c, err := ai.Condense(ctx, provider, input, opts)
+	if err != nil {
+		return nil, fmt.Errorf("AI inference failed: %w", err)
+	}
+	return c, nil
+}
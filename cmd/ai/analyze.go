@@ -6,15 +6,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/formats/convert"
 )
 
 const defaultAnalyzePrompt = "You are a data analyst. Analyze the following data and provide structured insights. Identify trends, anomalies, and key findings. Present your analysis in clear sections with supporting evidence from the data."
 
+const analyzePartPrompt = "You are a data analyst. Note the trends, anomalies, and key findings in this part of a longer data set or document, with the figures that support them. Cover only this part; your notes will be combined with those for the other parts."
+
 func newAnalyzeCommand() *cobra.Command {
 	var prompt string
 
@@ -33,9 +37,10 @@ func newAnalyzeCommand() *cobra.Command {
 				return err
 			}
 
-			systemPrompt := defaultAnalyzePrompt
+			systemPrompt, partPrompt := defaultAnalyzePrompt, analyzePartPrompt
 			if prompt != "" {
 				systemPrompt += "\n\nAdditional instructions: " + prompt
+				partPrompt += "\n\nAdditional instructions: " + prompt
 			}
 
 			provider, err := ai.NewProvider(providerName, modelName)
@@ -44,8 +49,15 @@ func newAnalyzeCommand() *cobra.Command {
 			}
 
 			ctx := context.Background()
+			condensed, err := condense(ctx, cmd, provider, input, partPrompt)
+			if err != nil {
+				return err
+			}
+			if condensed.Chunks > 1 {
+				systemPrompt += ai.ReduceNote
+			}
 			messages := []ai.Message{
-				{Role: "user", Content: input},
+				{Role: "user", Content: condensed.Text},
 			}
 
 			if jsonFlag {
@@ -57,7 +69,7 @@ func newAnalyzeCommand() *cobra.Command {
 				out := map[string]interface{}{
 					"analysis": result.Content,
 					"model":    result.Model,
-					"tokens":   result.InputTokens + result.OutputTokens,
+					"tokens":   result.InputTokens + result.OutputTokens + condensed.InputTokens + condensed.OutputTokens,
 				}
 
 				enc := json.NewEncoder(os.Stdout)
@@ -65,22 +77,8 @@ func newAnalyzeCommand() *cobra.Command {
 				return enc.Encode(out)
 			}
 
-			textCh, errCh, err := provider.Stream(ctx, systemPrompt, messages, ai.InferOptions{})
-			if err != nil {
-				return fmt.Errorf("AI inference failed: %w", err)
-			}
-
-			for text := range textCh {
-				fmt.Print(text)
-			}
-			fmt.Println()
-
-			select {
-			case err := <-errCh:
-				if err != nil {
-					return fmt.Errorf("streaming error: %w", err)
-				}
-			default:
+			if _, err := ai.Print(ctx, provider, os.Stdout, systemPrompt, messages, ai.InferOptions{}); err != nil {
+				return err
 			}
 
 			return nil
@@ -92,8 +90,18 @@ func newAnalyzeCommand() *cobra.Command {
 	return cmd
 }
 
+// readInput reads the document named by args, or stdin. Word, Excel,
+// PowerPoint, and HTML files are read as Markdown.
 func readInput(args []string) (string, error) {
 	if len(args) > 0 && args[0] != "-" {
+		switch strings.ToLower(filepath.Ext(args[0])) {
+		case ".docx", ".xlsx", ".pptx", ".html", ".htm":
+			text, err := convert.FileToMarkdown(args[0])
+			if err != nil {
+				return "", fmt.Errorf("could not read file %s: %w", args[0], err)
+			}
+			return text, nil
+		}
 		data, err := os.ReadFile(args[0])
 		if err != nil {
 			return "", fmt.Errorf("could not read file %s: %w", args[0], err)
@@ -13,6 +13,8 @@ import (
 
 const defaultAskPrompt = "You are a document question-answering assistant. Answer the user's question based on the document content provided. Only use information from the document. If the answer is not in the document, say so. Be concise and cite relevant sections when possible."
 
+const askPartPrompt = "You are helping answer a question about a long document, one part at a time. From this part, extract everything relevant to the question, quoting key passages and naming the sections they come from. If nothing in this part is relevant, reply only \"Nothing relevant.\"\n\nQuestion: %s"
+
 func newAskCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "ask <question> [file]",
@@ -32,20 +34,28 @@ func newAskCommand() *cobra.Command {
 				return err
 			}
 
-			userMessage := fmt.Sprintf("Document:\n%s\n\nQuestion: %s", input, question)
-
 			provider, err := ai.NewProvider(providerName, modelName)
 			if err != nil {
 				return err
 			}
 
 			ctx := context.Background()
+			systemPrompt := defaultAskPrompt
+			condensed, err := condense(ctx, cmd, provider, input, fmt.Sprintf(askPartPrompt, question))
+			if err != nil {
+				return err
+			}
+			if condensed.Chunks > 1 {
+				systemPrompt += ai.ReduceNote
+			}
+
+			userMessage := fmt.Sprintf("Document:\n%s\n\nQuestion: %s", condensed.Text, question)
 			messages := []ai.Message{
 				{Role: "user", Content: userMessage},
 			}
 
 			if jsonFlag {
-				result, err := provider.Infer(ctx, defaultAskPrompt, messages, ai.InferOptions{})
+				result, err := provider.Infer(ctx, systemPrompt, messages, ai.InferOptions{})
 				if err != nil {
 					return fmt.Errorf("AI inference failed: %w", err)
 				}
@@ -54,7 +64,7 @@ func newAskCommand() *cobra.Command {
 					"question": question,
 					"answer":   result.Content,
 					"model":    result.Model,
-					"tokens":   result.InputTokens + result.OutputTokens,
+					"tokens":   result.InputTokens + result.OutputTokens + condensed.InputTokens + condensed.OutputTokens,
 				}
 
 				enc := json.NewEncoder(os.Stdout)
@@ -62,22 +72,8 @@ func newAskCommand() *cobra.Command {
 				return enc.Encode(out)
 			}
 
-			textCh, errCh, err := provider.Stream(ctx, defaultAskPrompt, messages, ai.InferOptions{})
-			if err != nil {
-				return fmt.Errorf("AI inference failed: %w", err)
-			}
-
-			for text := range textCh {
-				fmt.Print(text)
-			}
-			fmt.Println()
-
-			select {
-			case err := <-errCh:
-				if err != nil {
-					return fmt.Errorf("streaming error: %w", err)
-				}
-			default:
+			if _, err := ai.Print(ctx, provider, os.Stdout, systemPrompt, messages, ai.InferOptions{}); err != nil {
+				return err
 			}
 
 			return nil
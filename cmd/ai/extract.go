@@ -13,20 +13,50 @@ import (
 
 const defaultExtractPrompt = "You are a precise entity extractor. Extract the requested fields from the following document. Return the results as a JSON object with the field names as keys. If a field cannot be found, set its value to null. Be exact — do not infer or guess values that are not present in the text."
 
+const extractPartPrompt = "You are a precise entity extractor. Extract the requested fields from this part of a longer document as a JSON object with the field names as keys, setting fields that don't appear in this part to null. Be exact — do not infer or guess values that are not present in the text.\n\nFields to extract: %s\n\nReturn ONLY valid JSON, no other text."
+
 func newExtractCommand() *cobra.Command {
-	var fields string
+	var (
+		fields     string
+		schemaPath string
+		retries    int
+	)
 
 	cmd := &cobra.Command{
 		Use:   "extract [file]",
 		Short: "Extract structured entities from a document using AI",
-		Long:  "Uses AI to extract specific fields (e.g., names, dates, amounts) from document text and returns structured JSON.",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Uses AI to extract specific fields (e.g., names, dates, amounts) from document text and returns structured JSON.
+
+With --schema, the output conforms to a JSON Schema: the response is validated
+against it, the model is asked to correct invalid output (up to --retries
+times), and only clean JSON is printed, so it can feed a pipeline. Types,
+properties, required, additionalProperties, items, enum, and the date,
+date-time, and email formats are checked.
+
+Examples:
+  kit ai extract --fields "parties,effective_date,amount" contract.docx
+  kit ai extract contract.docx --schema contract.schema.json | jq .parties`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			providerName, _ := cmd.Flags().GetString("provider")
 			modelName, _ := cmd.Flags().GetString("model")
 
-			if fields == "" {
-				return fmt.Errorf("--fields is required — specify comma-separated field names to extract\n\nExample: kit ai extract --fields \"name,date,amount\" contract.docx")
+			if fields == "" && schemaPath == "" {
+				return fmt.Errorf("--fields or --schema is required — specify comma-separated field names or a JSON Schema to extract\n\nExample: kit ai extract --fields \"name,date,amount\" contract.docx")
+			}
+			if fields != "" && schemaPath != "" {
+				return fmt.Errorf("use either --fields or --schema, not both")
+			}
+
+			var (
+				schema    *ai.Schema
+				rawSchema []byte
+				err       error
+			)
+			if schemaPath != "" {
+				if schema, rawSchema, err = ai.LoadSchema(schemaPath); err != nil {
+					return err
+				}
 			}
 
 			input, err := readInput(args)
@@ -34,16 +64,26 @@ func newExtractCommand() *cobra.Command {
 				return err
 			}
 
-			systemPrompt := defaultExtractPrompt + fmt.Sprintf("\n\nFields to extract: %s\n\nReturn ONLY valid JSON, no other text.", fields)
-
 			provider, err := ai.NewProvider(providerName, modelName)
 			if err != nil {
 				return err
 			}
 
 			ctx := context.Background()
+			if schema != nil {
+				return extractSchema(ctx, cmd, provider, input, schema, rawSchema, retries)
+			}
+
+			systemPrompt := defaultExtractPrompt + fmt.Sprintf("\n\nFields to extract: %s\n\nReturn ONLY valid JSON, no other text.", fields)
+			condensed, err := condense(ctx, cmd, provider, input, fmt.Sprintf(extractPartPrompt, fields))
+			if err != nil {
+				return err
+			}
+			if condensed.Chunks > 1 {
+				systemPrompt += ai.ReduceNote + mergeNote
+			}
 			messages := []ai.Message{
-				{Role: "user", Content: input},
+				{Role: "user", Content: condensed.Text},
 			}
 
 			result, err := provider.Infer(ctx, systemPrompt, messages, ai.InferOptions{})
@@ -65,7 +105,35 @@ func newExtractCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&fields, "fields", "", "Comma-separated field names to extract (required)")
+	cmd.Flags().StringVar(&fields, "fields", "", "Comma-separated field names to extract")
+	cmd.Flags().StringVar(&schemaPath, "schema", "", "JSON Schema file the output must conform to")
+	cmd.Flags().IntVar(&retries, "retries", ai.DefaultExtractRetries, "With --schema, times to ask again after invalid output")
 
 	return cmd
 }
+
+// mergeNote tells the model how to combine extractions from several parts.
+const mergeNote = " Merge the parts' values into one JSON object, preferring non-null values."
+
+// extractSchema extracts input to schema and prints the JSON.
+func extractSchema(ctx context.Context, cmd *cobra.Command, provider ai.Provider, input string, schema *ai.Schema, rawSchema []byte, retries int) error {
+	systemPrompt := ai.SchemaPrompt(rawSchema)
+	condensed, err := condense(ctx, cmd, provider, input, ai.SchemaPartPrompt(rawSchema))
+	if err != nil {
+		return err
+	}
+	if condensed.Chunks > 1 {
+		systemPrompt += ai.ReduceNote + mergeNote
+	}
+
+	ex, err := ai.ExtractSchema(ctx, provider, schema, systemPrompt, []ai.Message{
+		{Role: "user", Content: condensed.Text},
+	}, retries)
+	if err != nil {
+		return fmt.Errorf("extraction failed: %w", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ex.Data)
+}
@@ -0,0 +1,182 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/progress"
+	"github.com/klytics/m365kit/internal/semantic"
+)
+
+func newIndexCommand() *cobra.Command {
+	var (
+		recursive  bool
+		embedder   string
+		embedModel string
+		indexPath  string
+		rebuild    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "index <path>...",
+		Short: "Index documents for semantic search with kit ai find",
+		Long: `Extracts the text of documents (.docx, .xlsx, .pptx, .html, .txt, .md), splits
+it into sections, and stores an embedding of each section in a local index
+(~/.kit/index by default).
+
+Running it again only re-embeds files that changed, and drops indexed files
+that are gone. The "local" embedder works offline and matches wording; the
+others (openai, azure-openai, gemini, ollama) use a provider's embedding model
+and match meaning. An index holds vectors from one embedder: use --rebuild to
+switch.`,
+		Example: `  kit ai index ~/Documents -r
+  kit ai index ~/Contracts -r --embedder openai
+  kit ai index report.docx notes.md`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+
+			e, err := ai.NewEmbedder(embedder, embedModel)
+			if err != nil {
+				return err
+			}
+			ix, err := semantic.Load(indexPath)
+			if err != nil {
+				return err
+			}
+			if rebuild {
+				ix.Embedder = ""
+				ix.Files = make(map[string]*semantic.File)
+			}
+
+			var bar *progress.Bar
+			opts := semantic.UpdateOptions{
+				Recursive: recursive,
+				Progress: func(path string, done, total int) {
+					if bar == nil {
+						bar = progress.New("Indexing", total)
+					}
+					bar.Set(done, filepath.Base(path))
+				},
+			}
+			result, updateErr := ix.Update(context.Background(), e, args, opts)
+			if result == nil {
+				return updateErr
+			}
+			if bar != nil {
+				bar.Finish(fmt.Sprintf("Indexed %d files", result.Indexed))
+			}
+			// Keep whatever was embedded before an error
+			if err := ix.Save(); err != nil {
+				return err
+			}
+			if updateErr != nil {
+				return updateErr
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+
+			fmt.Printf("Indexed %d files (%d sections); %d unchanged, %d removed, %d failed\n",
+				result.Indexed, result.Sections, result.Unchanged, result.Removed, len(result.Failed))
+			for _, f := range result.Failed {
+				fmt.Fprintf(os.Stderr, "  %s: %s\n", f.Path, f.Error)
+			}
+			fmt.Printf("Index: %s (%d files, %d sections, %s)\n", ix.Path, len(ix.Files), ix.Sections(), ix.Embedder)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Index subdirectories too")
+	cmd.Flags().StringVar(&embedder, "embedder", "local", "Embedder: "+strings.Join(ai.Embedders, ", "))
+	cmd.Flags().StringVar(&embedModel, "embed-model", "", "Embedding model (or Azure OpenAI deployment); defaults per embedder")
+	cmd.Flags().StringVar(&indexPath, "index", semantic.DefaultPath(), "Index file")
+	cmd.Flags().BoolVar(&rebuild, "rebuild", false, "Discard the existing index and embed everything again")
+
+	return cmd
+}
+
+func newFindCommand() *cobra.Command {
+	var (
+		limit     int
+		files     bool
+		under     string
+		indexPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "find <query>",
+		Short: "Search indexed documents by meaning",
+		Long: `Ranks the sections of documents indexed with kit ai index by how closely
+they match the query, using the embedder the index was built with.`,
+		Example: `  kit ai find "indemnification obligations"
+  kit ai find "quarterly revenue forecast" --files --under ~/Documents/Finance
+  kit ai find "termination for convenience" --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			query := strings.Join(args, " ")
+
+			ix, err := semantic.Load(indexPath)
+			if err != nil {
+				return err
+			}
+			e, err := semantic.EmbedderFor(ix)
+			if err != nil {
+				return err
+			}
+			results, err := ix.Search(context.Background(), e, query, semantic.SearchOptions{
+				Limit:   limit,
+				PerFile: files,
+				Under:   under,
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				if results == nil {
+					results = []semantic.Result{}
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(results)
+			}
+
+			if len(results) == 0 {
+				fmt.Println("No matches.")
+				return nil
+			}
+			for i, r := range results {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("%.2f  %s\n", r.Score, r.Path)
+				if r.Heading != "" {
+					fmt.Printf("      § %s\n", r.Heading)
+				}
+				if !files {
+					fmt.Printf("      %s\n", r.Snippet)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Maximum results")
+	cmd.Flags().BoolVar(&files, "files", false, "List files, ranked by their best section")
+	cmd.Flags().StringVar(&under, "under", "", "Only search files in this directory")
+	cmd.Flags().StringVar(&indexPath, "index", semantic.DefaultPath(), "Index file")
+
+	return cmd
+}
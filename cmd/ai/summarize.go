@@ -15,11 +15,14 @@ import (
 
 const defaultSummarizePrompt = "You are a precise document analyst. Summarize the following document concisely, capturing key points, decisions, dates, and action items. Structure your summary with clear sections. Be factual and avoid speculation."
 
+const summarizePartPrompt = "You are a precise document analyst. Summarize this part of a longer document, keeping its key points, decisions, dates, names, figures, and action items. Include only what this part says; it will be combined with summaries of the other parts."
+
 type summarizeOutput struct {
 	Summary   string   `json:"summary"`
 	KeyPoints []string `json:"keyPoints,omitempty"`
 	Model     string   `json:"model"`
 	Tokens    int      `json:"tokens"`
+	Chunks    int      `json:"chunks,omitempty"`
 }
 
 func newSummarizeCommand() *cobra.Command {
@@ -38,11 +41,10 @@ func newSummarizeCommand() *cobra.Command {
 			// Read input
 			var input string
 			if len(args) > 0 && args[0] != "-" {
-				data, err := os.ReadFile(args[0])
-				if err != nil {
-					return fmt.Errorf("could not read file %s: %w", args[0], err)
+				var err error
+				if input, err = readInput(args); err != nil {
+					return err
 				}
-				input = string(data)
 			} else {
 				// Check if stdin has data
 				stat, _ := os.Stdin.Stat()
@@ -64,9 +66,10 @@ func newSummarizeCommand() *cobra.Command {
 			input = extractTextFromInput(input)
 
 			// Build system prompt
-			systemPrompt := defaultSummarizePrompt
+			systemPrompt, partPrompt := defaultSummarizePrompt, summarizePartPrompt
 			if focus != "" {
 				systemPrompt += fmt.Sprintf("\n\nFocus your summary on these areas: %s", focus)
+				partPrompt += fmt.Sprintf("\n\nFocus on these areas: %s", focus)
 			}
 
 			// Create provider
@@ -76,8 +79,17 @@ func newSummarizeCommand() *cobra.Command {
 			}
 
 			ctx := context.Background()
+
+			// Long documents are summarized part by part first
+			condensed, err := condense(ctx, cmd, provider, input, partPrompt)
+			if err != nil {
+				return err
+			}
+			if condensed.Chunks > 1 {
+				systemPrompt += ai.ReduceNote
+			}
 			messages := []ai.Message{
-				{Role: "user", Content: input},
+				{Role: "user", Content: condensed.Text},
 			}
 
 			if jsonFlag {
@@ -90,7 +102,10 @@ func newSummarizeCommand() *cobra.Command {
 				out := summarizeOutput{
 					Summary: result.Content,
 					Model:   result.Model,
-					Tokens:  result.InputTokens + result.OutputTokens,
+					Tokens:  result.InputTokens + result.OutputTokens + condensed.InputTokens + condensed.OutputTokens,
+				}
+				if condensed.Chunks > 1 {
+					out.Chunks = condensed.Chunks
 				}
 
 				enc := json.NewEncoder(os.Stdout)
@@ -99,23 +114,8 @@ func newSummarizeCommand() *cobra.Command {
 			}
 
 			// Streaming output
-			textCh, errCh, err := provider.Stream(ctx, systemPrompt, messages, ai.InferOptions{})
-			if err != nil {
-				return fmt.Errorf("AI inference failed: %w", err)
-			}
-
-			for text := range textCh {
-				fmt.Print(text)
-			}
-			fmt.Println()
-
-			// Check for streaming errors
-			select {
-			case err := <-errCh:
-				if err != nil {
-					return fmt.Errorf("streaming error: %w", err)
-				}
-			default:
+			if _, err := ai.Print(ctx, provider, os.Stdout, systemPrompt, messages, ai.InferOptions{}); err != nil {
+				return err
 			}
 
 			return nil
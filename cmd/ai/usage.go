@@ -0,0 +1,170 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/usage"
+)
+
+// usageOutput is kit ai usage --json.
+type usageOutput struct {
+	*usage.Report
+	From   string        `json:"from,omitempty"`
+	To     string        `json:"to,omitempty"`
+	Budget *usage.Budget `json:"budget,omitempty"`
+}
+
+func newUsageCommand() *cobra.Command {
+	var (
+		month string
+		since string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Report AI token usage and estimated cost",
+		Long: `Reports the tokens used and estimated cost of AI calls, by provider, model,
+and command. Every call kit makes to a provider is recorded in
+~/.kit/usage.jsonl (KIT_USAGE_LOG to move it, or KIT_USAGE_LOG=off to stop
+recording). Streamed responses don't report token counts, so theirs are
+estimated from the text (marked ~).
+
+Costs are estimated from list prices per million tokens; add or override
+them, and set a monthly budget to be warned about, in ~/.kit/config.yaml:
+
+  usage:
+    monthly_budget: 200      # USD; warn once spend reaches warn_at of it
+    warn_at: 0.8
+    prices:
+      gpt4o-prod: {input: 2.5, output: 10}   # e.g. an Azure deployment`,
+		Example: `  kit ai usage --month
+  kit ai usage --month=2026-09 --json
+  kit ai usage --since 2026-01-01`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := usage.LogPath()
+			if path == "" {
+				return fmt.Errorf("usage metering is off (KIT_USAGE_LOG=off)")
+			}
+			records, err := usage.Read(path)
+			if err != nil {
+				return fmt.Errorf("could not read usage ledger: %w", err)
+			}
+
+			now := time.Now()
+			var from, to time.Time
+			title := "AI Usage"
+			switch {
+			case month != "" && since != "":
+				return fmt.Errorf("use --month or --since, not both")
+			case month == "current":
+				from = usage.MonthStart(now)
+				title += " — " + from.Format("January 2006")
+			case month != "":
+				t, err := time.ParseInLocation("2006-01", month, time.Local)
+				if err != nil {
+					return fmt.Errorf("invalid --month %q (use YYYY-MM)", month)
+				}
+				from, to = t, t.AddDate(0, 1, 0)
+				title += " — " + from.Format("January 2006")
+			case since != "":
+				t, err := time.ParseInLocation("2006-01-02", since, time.Local)
+				if err != nil {
+					return fmt.Errorf("invalid --since date: %w (use YYYY-MM-DD)", err)
+				}
+				from = t
+				title += " since " + since
+			}
+
+			report := usage.Summarize(usage.Between(records, from, to), usage.LoadPrices())
+			// The budget applies to the current month
+			var budget *usage.Budget
+			if b := usage.LoadBudget(); b.Monthly > 0 && from.Equal(usage.MonthStart(now)) {
+				budget = &b
+			}
+
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			if jsonFlag {
+				out := usageOutput{Report: report, Budget: budget}
+				if !from.IsZero() {
+					out.From = from.Format(time.RFC3339)
+				}
+				if !to.IsZero() {
+					out.To = to.Format(time.RFC3339)
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+
+			if report.Calls == 0 {
+				fmt.Println("No AI usage recorded.")
+				return nil
+			}
+
+			fmt.Println(title)
+			fmt.Printf("Ledger: %s\n\n", path)
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "PROVIDER\tMODEL\tCOMMAND\tCALLS\tINPUT\tOUTPUT\tCOST\n")
+			for _, l := range report.Lines {
+				command := strings.TrimPrefix(l.Command, "kit ")
+				if command == "" {
+					command = "-"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", l.Provider, l.Model, command, l.Calls,
+					tokens(l.InputTokens, l.Estimated), tokens(l.OutputTokens, l.Estimated), cost(l.Cost, l.Priced))
+			}
+			fmt.Fprintf(tw, "TOTAL\t\t\t%d\t%s\t%s\t%s\n", report.Calls,
+				tokens(report.InputTokens, false), tokens(report.OutputTokens, false), cost(report.Cost, true))
+			tw.Flush()
+
+			if budget != nil {
+				fmt.Printf("\nBudget: $%.2f of $%.2f (%.0f%%)\n", report.Cost, budget.Monthly, report.Cost/budget.Monthly*100)
+				if warning := budget.Warning(report.Cost, now); warning != "" {
+					fmt.Printf("Warning: %s\n", warning)
+				}
+			}
+			if len(report.Unpriced) > 0 {
+				fmt.Printf("\nNo price for %s — not included in the total; set usage.prices in ~/.kit/config.yaml\n", strings.Join(report.Unpriced, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&month, "month", "", "Only this month, or another with --month=YYYY-MM")
+	cmd.Flags().Lookup("month").NoOptDefVal = "current"
+	cmd.Flags().StringVar(&since, "since", "", "Only usage since a date (YYYY-MM-DD)")
+
+	return cmd
+}
+
+// tokens formats a token count, with ~ if it was estimated.
+func tokens(n int, estimated bool) string {
+	s := fmt.Sprintf("%d", n)
+	if n >= 10000 {
+		s = fmt.Sprintf("%.1fk", float64(n)/1000)
+	}
+	if estimated {
+		s = "~" + s
+	}
+	return s
+}
+
+// cost formats a dollar cost, or "?" if the price is unknown.
+func cost(usd float64, priced bool) string {
+	if !priced {
+		return "?"
+	}
+	if usd > 0 && usd < 0.01 {
+		return "<$0.01"
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
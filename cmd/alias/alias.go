@@ -0,0 +1,145 @@
+// Package alias provides the "kit alias" CLI commands for command shortcuts.
+package alias
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	aliaspkg "github.com/klytics/m365kit/internal/alias"
+)
+
+// NewCommand creates the "alias" command with all subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Define shortcuts for kit commands",
+		Long: `Define shortcuts for kit commands. An alias stands for the start of a
+command; anything after it is added on:
+
+  kit alias set standup teams post --team Eng --channel general --stdin
+  echo "Shipped the release" | kit standup
+
+Aliases are saved to ~/.kit/aliases.yaml and work both as kit <alias> and
+in kit shell (where "alias standup = ..." defines one too). An alias
+can't have the name of a kit command.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listAliases(cmd)
+		},
+	}
+
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newSetCmd())
+	cmd.AddCommand(newRemoveCmd())
+
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List aliases",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listAliases(cmd)
+		},
+	}
+}
+
+func listAliases(cmd *cobra.Command) error {
+	aliases, err := aliaspkg.Load(aliaspkg.DefaultPath())
+	if err != nil {
+		return err
+	}
+
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(aliases.Entries)
+	}
+
+	if len(aliases.Entries) == 0 {
+		fmt.Println("No aliases. Define one with: kit alias set <name> <command>")
+		return nil
+	}
+	for _, name := range aliases.Names() {
+		fmt.Printf("%s = %s\n", name, aliases.Entries[name])
+	}
+	return nil
+}
+
+func newSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name> <command>...",
+		Short: "Define or replace an alias",
+		// The command's flags belong to the alias, not to set
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+				return cmd.Help()
+			}
+			if len(args) < 2 {
+				return fmt.Errorf("usage: kit alias set <name> <command>...")
+			}
+			name := args[0]
+			if c, _, err := cmd.Root().Find([]string{name}); err == nil && c != cmd.Root() {
+				return fmt.Errorf("%s is a kit command; pick another name", name)
+			}
+			aliases, err := aliaspkg.Load(aliaspkg.DefaultPath())
+			if err != nil {
+				return err
+			}
+			if err := aliases.Set(name, joinArgs(args[1:])); err != nil {
+				return err
+			}
+			if err := aliases.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("%s = %s\n", name, aliases.Entries[name])
+			return nil
+		},
+	}
+}
+
+func newRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Remove an alias",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			aliases, err := aliaspkg.Load(aliaspkg.DefaultPath())
+			if err != nil {
+				return err
+			}
+			if err := aliases.Remove(args[0]); err != nil {
+				return err
+			}
+			if err := aliases.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("Removed alias %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// joinArgs joins args back into a command, quoting those with spaces, so
+// aliaspkg.Split gets the same words.
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		switch {
+		case a == "" || strings.ContainsAny(a, " \t|'"):
+			quoted[i] = `"` + a + `"`
+		case strings.Contains(a, `"`):
+			quoted[i] = "'" + a + "'"
+		default:
+			quoted[i] = a
+		}
+	}
+	return strings.Join(quoted, " ")
+}
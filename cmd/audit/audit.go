@@ -13,6 +13,7 @@ import (
 
 	auditpkg "github.com/klytics/m365kit/internal/audit"
 	"github.com/klytics/m365kit/internal/config"
+	"github.com/klytics/m365kit/internal/graph"
 )
 
 // NewCommand creates the "audit" command with all subcommands.
@@ -20,10 +21,15 @@ func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "audit",
 		Short: "View and manage audit logs",
-		Long:  "View command audit logs, export to CSV, and manage log files.",
+		Long: `View command audit logs, export to CSV, and manage log files.
+
+kit audit self lists the changes kit itself made through Microsoft Graph
+— uploads, sent mail, posts, deletes, permission changes — from
+~/.kit/activity.log.`,
 	}
 
 	cmd.AddCommand(newLogCmd())
+	cmd.AddCommand(newSelfCmd())
 	cmd.AddCommand(newClearCmd())
 	cmd.AddCommand(newStatusCmd())
 
@@ -113,6 +119,94 @@ func newLogCmd() *cobra.Command {
 	return cmd
 }
 
+func newSelfCmd() *cobra.Command {
+	var (
+		since   string
+		last    int
+		method  string
+		command string
+		target  string
+		failed  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "self",
+		Short: "Show the changes kit made through Microsoft Graph",
+		Long: `Show the changes kit made through Microsoft Graph: every request that
+created, updated, or deleted data, with the command that made it, the
+endpoint, what it acted on, and Graph's answer. Reads are not recorded.
+
+The log is ~/.kit/activity.log (KIT_ACTIVITY_LOG to move it, or
+KIT_ACTIVITY_LOG=off to stop recording).
+
+Examples:
+  kit audit self --since 7d
+  kit audit self --method DELETE --since 2025-01-01
+  kit audit self --target /Reports --failed`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := auditpkg.ActivityLogPath()
+			if path == "" {
+				return fmt.Errorf("the activity log is off (KIT_ACTIVITY_LOG=off)")
+			}
+			activities, err := auditpkg.ReadActivity(path)
+			if err != nil {
+				return err
+			}
+
+			filter := auditpkg.ActivityFilter{Method: method, Command: command, Target: target, FailedOnly: failed}
+			if since != "" {
+				if filter.Since, err = graph.ParseSince(since, time.Now()); err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+			}
+			filtered := auditpkg.FilterActivity(activities, filter)
+			if last > 0 && len(filtered) > last {
+				filtered = filtered[len(filtered)-last:]
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(filtered)
+			}
+
+			if len(filtered) == 0 {
+				fmt.Println("No changes recorded.")
+				return nil
+			}
+
+			fmt.Printf("Graph Activity — %d Changes\n", len(filtered))
+			fmt.Printf("File: %s\n\n", path)
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "TIMESTAMP\tACCOUNT\tCOMMAND\tMETHOD\tENDPOINT\tTARGET\tRESULT\n")
+			for _, a := range filtered {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					a.Timestamp.Local().Format("2006-01-02 15:04:05"), orDash(a.Account), orDash(a.Command),
+					a.Method, a.Endpoint, orDash(a.Target), a.Result())
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only changes since an age (7d, 12h) or date (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&last, "last", 0, "Show only the last N changes")
+	cmd.Flags().StringVar(&method, "method", "", "Only changes with this HTTP method (POST, PUT, PATCH, DELETE)")
+	cmd.Flags().StringVar(&command, "command", "", "Filter by command name")
+	cmd.Flags().StringVar(&target, "target", "", "Filter by endpoint or target (substring)")
+	cmd.Flags().BoolVar(&failed, "failed", false, "Only changes Graph rejected or that failed to send")
+	return cmd
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func newClearCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "clear",
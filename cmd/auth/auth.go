@@ -5,15 +5,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/cloud"
+	"github.com/klytics/m365kit/internal/qr"
 )
 
 // NewCommand returns the auth command group.
@@ -23,10 +25,24 @@ func NewCommand() *cobra.Command {
 		Short: "Authenticate with Microsoft 365",
 		Long: `Manage Microsoft 365 authentication for OneDrive and SharePoint access.
 
-Setup:
+Run kit auth login to sign in. Without an app of your own, kit uses
+Microsoft's public "Microsoft Graph Command Line Tools" app, which your
+tenant may need to consent to once. To use your organization's app
+registration instead:
   1. Register an Azure AD app at portal.azure.com
   2. Set: export KIT_AZURE_CLIENT_ID="your-app-client-id"
-  3. Run: kit auth login`,
+     (or kit config set azure.client_id <id>, or --client-id at login)
+  3. Run: kit auth login
+
+For a national cloud (GCC High, DoD, China, Germany), set azure.cloud
+first, e.g.: kit config set azure.cloud usgov
+
+Profiles keep separate sign-ins, e.g. for several tenants. Pick one with
+--profile on any command, or KIT_PROFILE:
+  kit auth login --profile contoso --tenant contoso.onmicrosoft.com --client-id <id>
+  kit auth login --profile personal
+  kit onedrive ls --profile contoso
+  kit auth list`,
 	}
 
 	cmd.AddCommand(newLoginCommand())
@@ -34,42 +50,124 @@ Setup:
 	cmd.AddCommand(newStatusCommand())
 	cmd.AddCommand(newLogoutCommand())
 	cmd.AddCommand(newRefreshCommand())
+	cmd.AddCommand(newListCommand())
 
 	return cmd
 }
 
 func newLoginCommand() *cobra.Command {
-	return &cobra.Command{
+	var (
+		clientID, tenant string
+		addScopes        []string
+		browser          bool
+		showQR           bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "login",
-		Short: "Authenticate with Microsoft 365 (device code flow)",
+		Short: "Authenticate with Microsoft 365 (device code or browser)",
+		Long: `Sign in with the device code flow, or with --browser in your web
+browser (authorization code with PKCE and a localhost redirect). The
+browser flow is quicker on a desktop and satisfies conditional access
+policies that block device code sign-in; the app registration needs
+"http://localhost" as a "Mobile and desktop applications" redirect URI.
+With --qr the device code sign-in page is also shown as a QR code, to
+open on a phone.
+
+The token is saved for the active profile (--profile or KIT_PROFILE),
+along with --client-id and --tenant so later commands with that profile
+use them too.
+
+kit requests the permissions in auth.scopes (~/.kit/config.yaml or
+KIT_AUTH_SCOPES), or a default set covering every command. --add-scope
+asks for more and remembers them for the profile — for example when a
+command reports that the token lacks a permission:
+  kit auth login --add-scope Mail.ReadWrite
+  kit auth login --add-scope Sites.ReadWrite.All,Calendars.ReadWrite`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clientID := os.Getenv("KIT_AZURE_CLIENT_ID")
-			if clientID == "" {
-				return fmt.Errorf("KIT_AZURE_CLIENT_ID is not set\n\nSetup:\n  1. Register an Azure AD app at portal.azure.com\n  2. export KIT_AZURE_CLIENT_ID=\"your-app-client-id\"\n  3. kit auth login")
+			profile, err := auth.LoadProfile(auth.CurrentProfile())
+			if err != nil {
+				return err
+			}
+			if clientID != "" {
+				profile.ClientID = clientID
+			}
+			if tenant != "" {
+				profile.Tenant = tenant
+			}
+			for _, s := range addScopes {
+				profile.AddScopes(auth.ParseScopes(s)...)
+			}
+			if profile.ClientID == "" && auth.ClientID() == "" {
+				return fmt.Errorf("KIT_AZURE_CLIENT_ID is not set\n\nSetup:\n  1. Register an Azure AD app at portal.azure.com\n  2. export KIT_AZURE_CLIENT_ID=\"your-app-client-id\" (or pass --client-id)\n  3. kit auth login")
+			}
+			// Saved first: the sign-in uses the profile's tenant
+			if err := auth.SaveProfile(profile); err != nil {
+				return err
 			}
 
 			ctx := context.Background()
-			token, err := auth.DeviceCodeFlow(ctx, clientID)
+			var token *auth.Token
+			if browser {
+				token, err = auth.BrowserFlow(ctx, auth.ClientID(), auth.OpenBrowser)
+			} else {
+				prompt := auth.PrintDeviceCode
+				if showQR {
+					prompt = printDeviceCodeQR
+				}
+				token, err = auth.DeviceCodeFlow(ctx, auth.ClientID(), prompt)
+			}
 			if err != nil {
 				return err
 			}
+			// The profile may now be signed in to a different account
+			if err := auth.ClearCache(); err != nil {
+				return err
+			}
 
 			// Fetch user info
-			client := &http.Client{
-				Transport: &auth.BearerTransport{Token: token.AccessToken},
+			client, err := auth.NewClient(token.AccessToken)
+			if err != nil {
+				return err
 			}
 			name, email, err := auth.WhoAmI(ctx, client)
 			if err != nil {
 				fmt.Println("Authenticated (could not fetch user details)")
 				return nil
 			}
+			profile.Account, profile.DisplayName = email, name
+			if err := auth.SaveProfile(profile); err != nil {
+				return err
+			}
 
 			green := color.New(color.FgGreen)
 			green.Printf("Authenticated as %s (%s)\n", name, email)
-			fmt.Println("Token saved to ~/.kit/token.json")
+			if path, err := auth.TokenPath(); err == nil {
+				fmt.Printf("Token saved to %s\n", path)
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&clientID, "client-id", "", "Azure AD app client ID for this profile (default: $KIT_AZURE_CLIENT_ID, azure.client_id, or kit's built-in public client)")
+	cmd.Flags().StringVar(&tenant, "tenant", "", "Tenant ID or domain to sign in to (default: any)")
+	cmd.Flags().StringArrayVar(&addScopes, "add-scope", nil, "Also request these Graph permissions for the profile (repeatable, comma-separated)")
+	cmd.Flags().BoolVar(&browser, "browser", false, "Sign in in a web browser instead of with a device code")
+	cmd.Flags().BoolVar(&showQR, "qr", false, "Also show the device code sign-in page as a QR code")
+	cmd.MarkFlagsMutuallyExclusive("browser", "qr")
+
+	return cmd
+}
+
+// printDeviceCodeQR prints the device code with a QR code of the sign-in
+// page, for signing in on a phone.
+func printDeviceCodeQR(dc auth.DeviceCode) error {
+	if code, err := qr.Encode(dc.VerificationURI); err == nil {
+		fmt.Println()
+		fmt.Print(code.Terminal())
+		fmt.Println()
+	}
+	return auth.PrintDeviceCode(dc)
 }
 
 func newWhoAmICommand() *cobra.Command {
@@ -96,6 +194,7 @@ func newWhoAmICommand() *cobra.Command {
 				enc := json.NewEncoder(os.Stdout)
 				enc.SetIndent("", "  ")
 				return enc.Encode(map[string]any{
+					"profile":   auth.CurrentProfile(),
 					"name":      name,
 					"email":     email,
 					"expiresIn": int(token.ExpiresIn().Minutes()),
@@ -103,6 +202,9 @@ func newWhoAmICommand() *cobra.Command {
 			}
 
 			fmt.Printf("%s (%s)\n", name, email)
+			if profile := auth.CurrentProfile(); profile != auth.DefaultProfile {
+				fmt.Printf("Profile: %s\n", profile)
+			}
 			if token != nil {
 				fmt.Printf("Token expires in %d minutes\n", int(token.ExpiresIn().Minutes()))
 			}
@@ -112,9 +214,17 @@ func newWhoAmICommand() *cobra.Command {
 }
 
 func newStatusCommand() *cobra.Command {
-	return &cobra.Command{
+	var noProbe bool
+
+	cmd := &cobra.Command{
 		Use:   "status",
-		Short: "Show authentication status",
+		Short: "Show authentication status, granted permissions, and usable features",
+		Long: `Show who is signed in, the tenant and app, when the token expires, and
+the permissions it carries — read from the access token itself. Then
+probe Microsoft Graph for each area kit works with (OneDrive, SharePoint,
+mail, calendar, contacts, Teams, chats) and report which are usable,
+with the kit auth login --add-scope command for any that lack a
+permission. --no-probe skips the Graph calls.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 
@@ -124,59 +234,152 @@ func newStatusCommand() *cobra.Command {
 					enc := json.NewEncoder(os.Stdout)
 					enc.SetIndent("", "  ")
 					return enc.Encode(map[string]any{
+						"profile":       auth.CurrentProfile(),
 						"authenticated": false,
 						"error":         err.Error(),
 					})
 				}
-				fmt.Println("Not authenticated — run: kit auth login")
+				fmt.Println(err)
 				return nil
 			}
 
+			// RequireAuth refreshes a token that is expiring, so reload it
+			// afterwards to show the current one
+			ctx := context.Background()
+			client, authErr := auth.RequireAuth(ctx)
+			if authErr == nil {
+				if refreshed, err := auth.LoadToken(); err == nil {
+					token = refreshed
+				}
+			}
+
+			claims, _ := auth.DecodeClaims(token.AccessToken)
+			scopes := token.GrantedScopes()
+			if claims != nil && len(claims.Scopes()) > 0 {
+				scopes = claims.Scopes()
+			}
+
+			var name, email string
+			var features []auth.ProbeResult
+			if authErr == nil {
+				name, email, _ = auth.WhoAmI(ctx, client)
+				if !noProbe {
+					features = auth.ProbeFeatures(ctx, client, auth.Features)
+				}
+			}
+
 			if jsonFlag {
-				enc := json.NewEncoder(os.Stdout)
-				enc.SetIndent("", "  ")
-				return enc.Encode(map[string]any{
-					"authenticated": true,
+				status := map[string]any{
+					"profile":       auth.CurrentProfile(),
+					"authenticated": authErr == nil,
+					"cloud":         cloud.Current().Name,
 					"expired":       token.IsExpired(),
 					"expiresAt":     token.ExpiresAt.Format(time.RFC3339),
 					"expiresIn":     int(token.ExpiresIn().Minutes()),
 					"scopes":        auth.Scopes(),
-				})
+					"granted":       scopes,
+				}
+				if authErr != nil {
+					status["error"] = authErr.Error()
+				}
+				if email != "" {
+					status["name"], status["email"] = name, email
+				}
+				if claims != nil {
+					status["tenantId"], status["appId"] = claims.TenantID, claims.AppID
+				}
+				if _, source := auth.ResolveClientID(); source != "" {
+					status["clientIdSource"] = source
+				}
+				if features != nil {
+					status["features"] = features
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(status)
 			}
 
-			if token.IsExpired() {
-				color.New(color.FgRed).Println("Token expired — run: kit auth login")
+			if authErr != nil {
+				if token.IsExpired() {
+					color.New(color.FgRed).Printf("Token expired — %s\n", authErr)
+				} else {
+					color.New(color.FgRed).Println(authErr)
+				}
 				return nil
 			}
 
 			green := color.New(color.FgGreen)
 			green.Print("Authenticated")
-
-			// Try to get user info
-			ctx := context.Background()
-			client, authErr := auth.RequireAuth(ctx)
-			if authErr == nil {
-				if name, email, err := auth.WhoAmI(ctx, client); err == nil {
-					green.Printf(": %s (%s)", name, email)
-				}
+			if email != "" {
+				green.Printf(": %s (%s)", name, email)
+			} else if claims != nil && claims.Account() != "" {
+				green.Printf(": %s", claims.Account())
 			}
 			fmt.Println()
 
-			fmt.Printf("Token expires: %s (%d minutes)\n",
+			if profile := auth.CurrentProfile(); profile != auth.DefaultProfile {
+				fmt.Printf("Profile: %s\n", profile)
+			}
+			if c := cloud.Current(); c.Name != cloud.Global.Name {
+				fmt.Printf("Cloud: %s (%s)\n", c.Name, c.Description)
+			}
+			if claims != nil {
+				fmt.Printf("Tenant: %s\n", claims.TenantID)
+				fmt.Printf("App: %s\n", claims.AppID)
+			}
+			fmt.Printf("Token expires: %s (in %s)\n",
 				token.ExpiresAt.Format("2006-01-02 15:04"),
-				int(token.ExpiresIn().Minutes()))
+				formatRemaining(token.ExpiresIn()))
 
-			scopes := auth.Scopes()
+			// Tokens from before kit recorded granted scopes show what
+			// would be requested instead
+			if len(scopes) == 0 {
+				scopes = auth.Scopes()
+			}
 			filtered := make([]string, 0, len(scopes))
 			for _, s := range scopes {
-				if s != "offline_access" {
+				switch s {
+				case "offline_access", "openid", "profile", "email":
+				default:
 					filtered = append(filtered, s)
 				}
 			}
 			fmt.Printf("Scopes: %s\n", strings.Join(filtered, ", "))
-			return nil
+
+			if len(features) == 0 {
+				return nil
+			}
+			fmt.Println()
+			fmt.Println("Features:")
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, f := range features {
+				var icon, detail string
+				switch f.Status {
+				case auth.ProbeOK:
+					icon, detail = color.GreenString("✓"), f.Commands
+				case auth.ProbeUnavailable:
+					icon, detail = color.YellowString("!"), "not available: "+f.Message
+				default:
+					icon, detail = color.RedString("✗"), f.Message
+				}
+				fmt.Fprintf(tw, "  %s %s\t%s\n", icon, f.Feature, detail)
+			}
+			return tw.Flush()
 		},
 	}
+
+	cmd.Flags().BoolVar(&noProbe, "no-probe", false, "Skip the Graph calls that check which features are usable")
+
+	return cmd
+}
+
+// formatRemaining formats a countdown as "1h 05m" or "42m".
+func formatRemaining(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	if m >= 60 {
+		return fmt.Sprintf("%dh %02dm", m/60, m%60)
+	}
+	return fmt.Sprintf("%dm", m)
 }
 
 func newLogoutCommand() *cobra.Command {
@@ -187,7 +390,10 @@ func newLogoutCommand() *cobra.Command {
 			if err := auth.DeleteToken(); err != nil {
 				return err
 			}
-			fmt.Println("Logged out — token deleted")
+			if err := auth.ClearCache(); err != nil {
+				return err
+			}
+			fmt.Printf("Logged out of profile %s — token deleted\n", auth.CurrentProfile())
 			return nil
 		},
 	}
@@ -203,7 +409,7 @@ func newRefreshCommand() *cobra.Command {
 				return err
 			}
 
-			clientID := os.Getenv("KIT_AZURE_CLIENT_ID")
+			clientID := auth.ClientID()
 			if clientID == "" {
 				return fmt.Errorf("KIT_AZURE_CLIENT_ID not set — see: kit auth --help")
 			}
@@ -223,3 +429,71 @@ func newRefreshCommand() *cobra.Command {
 		},
 	}
 }
+
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List sign-in profiles and who is signed in to each",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			current := auth.CurrentProfile()
+
+			profiles, err := auth.ListProfiles()
+			if err != nil {
+				return err
+			}
+
+			type profileStatus struct {
+				Name     string `json:"name"`
+				Active   bool   `json:"active"`
+				Account  string `json:"account,omitempty"`
+				Tenant   string `json:"tenant,omitempty"`
+				ClientID string `json:"clientId,omitempty"`
+				SignedIn bool   `json:"signedIn"`
+			}
+			statuses := make([]profileStatus, 0, len(profiles))
+			for _, p := range profiles {
+				statuses = append(statuses, profileStatus{
+					Name:     p.Name,
+					Active:   p.Name == current,
+					Account:  p.Account,
+					Tenant:   p.Tenant,
+					ClientID: p.ClientID,
+					SignedIn: auth.HasToken(p.Name),
+				})
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(statuses)
+			}
+
+			if len(statuses) == 0 {
+				fmt.Println("No profiles — run: kit auth login")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "  PROFILE\tACCOUNT\tTENANT\tSTATUS")
+			for _, s := range statuses {
+				mark := " "
+				if s.Active {
+					mark = "*"
+				}
+				account, tenant, status := s.Account, s.Tenant, "signed in"
+				if account == "" {
+					account = "-"
+				}
+				if tenant == "" {
+					tenant = "any"
+				}
+				if !s.SignedIn {
+					status = "signed out"
+				}
+				fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", mark, s.Name, account, tenant, status)
+			}
+			return tw.Flush()
+		},
+	}
+}
@@ -0,0 +1,99 @@
+// Package cache provides the "kit cache" CLI commands for the Graph response cache.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	cachepkg "github.com/klytics/m365kit/internal/cache"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// NewCommand creates the "cache" command with all subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the Microsoft Graph response cache",
+		Long: `Manage the on-disk cache of read-only Microsoft Graph responses.
+
+The cache is off by default. Enable it with:
+  kit config set cache.enabled true     (or export KIT_CACHE=1)
+
+Cached listings are revalidated with ETags when Graph provides them, and
+reused when the network is unreachable. Set cache.ttl (or KIT_CACHE_TTL)
+to reuse entries younger than that without contacting Graph at all.
+Any upload, delete, or other change made through kit clears the cache.
+
+Each profile has its own cache (~/.kit/profiles/<name>/cache/graph; the
+default profile uses ~/.kit/cache/graph), and signing in or out clears it.`,
+	}
+
+	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newClearCmd())
+
+	return cmd
+}
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show cache location, entry count, and size",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := auth.CacheDir(auth.CurrentProfile())
+			if err != nil {
+				return err
+			}
+			stats, err := cachepkg.Stat(dir)
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(stats)
+			}
+
+			fmt.Printf("Cache dir: %s\n", stats.Dir)
+			fmt.Printf("Entries:   %d\n", stats.Entries)
+			fmt.Printf("Size:      %s\n", graph.FormatSize(stats.Bytes))
+			if stats.Entries > 0 {
+				fmt.Printf("Oldest:    %s\n", stats.Oldest.Format("2006-01-02 15:04"))
+				fmt.Printf("Newest:    %s\n", stats.Newest.Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+}
+
+func newClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Delete all cached responses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := auth.CacheDir(auth.CurrentProfile())
+			if err != nil {
+				return err
+			}
+			n, err := cachepkg.Clear(dir)
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"cleared": n})
+			}
+
+			fmt.Printf("Cleared %d cached responses\n", n)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,341 @@
+// Package calendar provides the "kit calendar" CLI commands for Outlook
+// calendar events.
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// NewCommand creates the "calendar" command with all subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "calendar",
+		Aliases:     []string{"cal"},
+		Short:       "List, create, and respond to Outlook calendar events",
+		Long:        "Access your Outlook calendar via Graph API to list upcoming events, block time, schedule meetings, and answer invitations.",
+		Annotations: map[string]string{auth.ScopesAnnotation: "Calendars.ReadWrite"},
+	}
+
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newCreateCmd())
+	cmd.AddCommand(newRespondCmd("accept", graph.ResponseAccept, "Accept an invitation"))
+	cmd.AddCommand(newRespondCmd("tentative", graph.ResponseTentative, "Tentatively accept an invitation"))
+	cmd.AddCommand(newRespondCmd("decline", graph.ResponseDecline, "Decline an invitation"))
+
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	var (
+		from  string
+		days  int
+		limit int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List upcoming events",
+		Example: `  kit calendar list
+  kit calendar list --from tomorrow --days 1
+  kit calendar list --from 2026-11-01 --days 30 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, err := parseWhen(from, time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid --from: %w", err)
+			}
+			if days < 1 {
+				return fmt.Errorf("--days must be at least 1")
+			}
+
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			events, err := graph.NewCalendar(client).ListEvents(cmd.Context(), start, start.AddDate(0, 0, days), limit)
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(events)
+			}
+
+			if len(events) == 0 {
+				fmt.Println("No events found.")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, " #\tWHEN\tSUBJECT\tORGANIZER\tRESPONSE\n")
+			for i, e := range events {
+				subj := e.Subject
+				if e.IsCanceled {
+					subj = "[canceled] " + subj
+				}
+				if len(subj) > 45 {
+					subj = subj[:42] + "..."
+				}
+				online := ""
+				if e.JoinURL != "" {
+					online = " 🎥"
+				}
+				fmt.Fprintf(tw, "%2d\t%s\t%s%s\t%s\t%s\n",
+					i+1, formatWhen(e), subj, online, e.Organizer, e.Response)
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "today", "Start of the range: today, tomorrow, or YYYY-MM-DD")
+	cmd.Flags().IntVar(&days, "days", 7, "Number of days to list")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of events to return (0 = all)")
+	return cmd
+}
+
+func newCreateCmd() *cobra.Command {
+	var (
+		subject   string
+		start     string
+		duration  time.Duration
+		attendees []string
+		location  string
+		body      string
+		showAs    string
+		online    bool
+		dryRun    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an event or block time",
+		Long: `Create a calendar event. Without --attendees this blocks time on your own
+calendar; with them, invitations are sent. --teams adds a Teams meeting link.`,
+		Example: `  kit calendar create --subject "Review Q3 contract.docx" --start "tomorrow 14:00" --duration 1h
+  kit calendar create --subject "Design sync" --start "2026-10-20 09:00" --attendees alice@company.com,bob@company.com --teams
+  kit calendar create --subject "Focus" --start +15m --duration 2h --show-as busy`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if subject == "" {
+				return fmt.Errorf("--subject is required")
+			}
+			if start == "" {
+				return fmt.Errorf("--start is required")
+			}
+			at, err := parseWhen(start, time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid --start: %w", err)
+			}
+
+			req := graph.EventRequest{
+				Subject:  subject,
+				Start:    at,
+				Duration: duration,
+				Body:     body,
+				Location: location,
+				ShowAs:   showAs,
+				Online:   online,
+			}
+			for _, a := range attendees {
+				for _, e := range strings.Split(a, ",") {
+					if e = strings.TrimSpace(e); e != "" {
+						req.Attendees = append(req.Attendees, e)
+					}
+				}
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if dryRun {
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]any{
+						"dryRun":    true,
+						"subject":   req.Subject,
+						"start":     req.Start,
+						"end":       req.Start.Add(req.Duration),
+						"attendees": req.Attendees,
+						"teams":     req.Online,
+					})
+				}
+				fmt.Println("--- Calendar Event Preview ---")
+				fmt.Printf("Subject:   %s\n", req.Subject)
+				fmt.Printf("When:      %s – %s\n", req.Start.Format("Mon 2006-01-02 15:04"), req.Start.Add(req.Duration).Format("15:04"))
+				if len(req.Attendees) > 0 {
+					fmt.Printf("Attendees: %s\n", strings.Join(req.Attendees, ", "))
+				}
+				if req.Online {
+					fmt.Println("Teams:     yes")
+				}
+				fmt.Println("--- Would create via Microsoft Graph API ---")
+				return nil
+			}
+
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			event, err := graph.NewCalendar(client).CreateEvent(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(event)
+			}
+
+			fmt.Printf("Created: %s (%s)\n", event.Subject, formatWhen(*event))
+			if event.JoinURL != "" {
+				fmt.Printf("Join:    %s\n", event.JoinURL)
+			}
+			if len(req.Attendees) > 0 {
+				fmt.Printf("Invited: %s\n", strings.Join(req.Attendees, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&subject, "subject", "", "Event subject (required)")
+	cmd.Flags().StringVar(&start, "start", "", `Start: now, +15m, 14:30, "tomorrow 09:00", or "2026-10-20 09:00" (required)`)
+	cmd.Flags().DurationVar(&duration, "duration", 30*time.Minute, "Event length")
+	cmd.Flags().StringArrayVar(&attendees, "attendees", nil, "Attendee emails (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&location, "location", "", "Location")
+	cmd.Flags().StringVar(&body, "body", "", "Event description")
+	cmd.Flags().StringVar(&showAs, "show-as", "", "Show as: free, tentative, busy, oof, workingElsewhere")
+	cmd.Flags().BoolVar(&online, "teams", false, "Add a Teams meeting link")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview without creating")
+	return cmd
+}
+
+func newRespondCmd(use, response, short string) *cobra.Command {
+	var (
+		comment  string
+		noNotify bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   use + " <index|id>",
+		Short: short,
+		Long: short + `. The index refers to the default 'kit calendar list' view
+(the next 7 days); an event ID from 'kit calendar list --json' also works.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			cal := graph.NewCalendar(client)
+			var event *graph.Event
+			if n, convErr := strconv.Atoi(args[0]); convErr == nil {
+				start, _ := parseWhen("today", time.Now())
+				events, err := cal.ListEvents(cmd.Context(), start, start.AddDate(0, 0, 7), n)
+				if err != nil {
+					return err
+				}
+				if n < 1 || n > len(events) {
+					return fmt.Errorf("event index %d out of range (%d events in the next 7 days)", n, len(events))
+				}
+				event = &events[n-1]
+			} else {
+				event, err = cal.GetEvent(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+			}
+
+			if event.Response == "organizer" {
+				return fmt.Errorf("you organized %q — there is no invitation to answer", event.Subject)
+			}
+			if err := cal.RespondToEvent(cmd.Context(), event.ID, response, comment, !noNotify); err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]string{"event": event.ID, "response": response})
+			}
+
+			fmt.Printf("%s: %s (%s)\n", responseLabel(response), event.Subject, formatWhen(*event))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&comment, "comment", "", "Message to the organizer")
+	cmd.Flags().BoolVar(&noNotify, "no-notify", false, "Don't send the response to the organizer")
+	return cmd
+}
+
+func responseLabel(response string) string {
+	switch response {
+	case graph.ResponseAccept:
+		return "Accepted"
+	case graph.ResponseTentative:
+		return "Tentatively accepted"
+	default:
+		return "Declined"
+	}
+}
+
+// formatWhen formats an event's time span in local time.
+func formatWhen(e graph.Event) string {
+	start, end := e.Start.Local(), e.End.Local()
+	if e.IsAllDay {
+		return start.Format("Mon 2006-01-02") + " all day"
+	}
+	if start.YearDay() == end.YearDay() && start.Year() == end.Year() {
+		return start.Format("Mon 2006-01-02 15:04") + "–" + end.Format("15:04")
+	}
+	return start.Format("Mon 2006-01-02 15:04") + " – " + end.Format("Mon 2006-01-02 15:04")
+}
+
+// parseWhen reads a point in time relative to now: "now", "+15m", "today",
+// "tomorrow", an optional "today"/"tomorrow" with a clock time, a bare
+// clock time (today), or a date with optional time.
+func parseWhen(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch {
+	case lower == "now":
+		return now.Truncate(time.Minute), nil
+	case strings.HasPrefix(s, "+"):
+		d, err := time.ParseDuration(s[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not an offset like +15m or +1h", s)
+		}
+		return now.Add(d).Truncate(time.Minute), nil
+	}
+
+	day := midnight
+	if rest, ok := strings.CutPrefix(lower, "tomorrow"); ok {
+		day, lower = midnight.AddDate(0, 0, 1), strings.TrimSpace(rest)
+	} else if rest, ok := strings.CutPrefix(lower, "today"); ok {
+		lower = strings.TrimSpace(rest)
+	} else if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	} else if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if lower == "" {
+		return day, nil
+	}
+	clock, err := time.Parse("15:04", lower)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`%q — use now, +15m, 14:30, "tomorrow 09:00", or "2026-10-20 09:00"`, s)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), nil
+}
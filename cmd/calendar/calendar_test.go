@@ -0,0 +1,39 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWhen(t *testing.T) {
+	now := time.Date(2026, 10, 16, 10, 17, 42, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", time.Date(2026, 10, 16, 10, 17, 0, 0, time.UTC)},
+		{"+1h", time.Date(2026, 10, 16, 11, 17, 0, 0, time.UTC)},
+		{"today", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"14:30", time.Date(2026, 10, 16, 14, 30, 0, 0, time.UTC)},
+		{"tomorrow", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{"Tomorrow 09:00", time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)},
+		{"2026-11-02", time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)},
+		{"2026-11-02 08:15", time.Date(2026, 11, 2, 8, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseWhen(tt.in, now)
+		if err != nil {
+			t.Errorf("parseWhen(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseWhen(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"soon", "+x", "tomorrow noon", "25:00"} {
+		if _, err := parseWhen(bad, now); err == nil {
+			t.Errorf("parseWhen(%q) should fail", bad)
+		}
+	}
+}
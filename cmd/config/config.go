@@ -10,7 +10,9 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/klytics/m365kit/internal/cloud"
 	"github.com/klytics/m365kit/internal/config"
+	"github.com/klytics/m365kit/internal/httpclient"
 )
 
 // NewCommand returns the config command group.
@@ -137,6 +139,23 @@ func newValidateCommand() *cobra.Command {
 			config.Load() // ensure loaded
 
 			issues := config.Validate()
+			if _, err := httpclient.New(); err != nil {
+				issues = append(issues, config.ConfigIssue{
+					Key:      "http",
+					Severity: "error",
+					Message:  "HTTP client settings are invalid: " + err.Error(),
+					Fix:      "kit config set http.<proxy|ca_bundle|timeout|tls_min_version> <value>",
+				})
+			}
+
+			if _, err := cloud.Load(); err != nil {
+				issues = append(issues, config.ConfigIssue{
+					Key:      "azure.cloud",
+					Severity: "error",
+					Message:  err.Error(),
+					Fix:      "kit config set azure.cloud <global|usgov|usgovdod|china|germany>",
+				})
+			}
 
 			if jsonFlag {
 				enc := json.NewEncoder(os.Stdout)
@@ -0,0 +1,89 @@
+// Package contacts provides the "kit contacts" CLI commands for finding
+// people and Outlook contacts.
+package contacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// NewCommand creates the "contacts" command with all subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "contacts",
+		Aliases: []string{"people"},
+		Short:   "Find people and Outlook contacts",
+		Long: `Search the people you work with, your organization's directory, and your
+Outlook contacts for email addresses, phone numbers, and job titles.
+
+Commands that take recipients (kit send, kit teams dm, kit outlook draft
+and forward) use the same search to accept a name such as --to "Anna K"
+in place of an email address.`,
+		Annotations: map[string]string{auth.ScopesAnnotation: "People.Read Contacts.Read"},
+	}
+
+	cmd.AddCommand(newSearchCmd())
+
+	return cmd
+}
+
+func newSearchCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "search <name or email>",
+		Short: "Search people and contacts by name or email",
+		Example: `  kit contacts search "Anna"
+  kit contacts search anna.k@contoso.com --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			p := graph.NewPeople(client)
+			p.Limit = limit
+			people, err := p.Search(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(people)
+			}
+
+			if len(people) == 0 {
+				fmt.Printf("No one found matching %q.\n", args[0])
+				return nil
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "NAME\tEMAIL\tPHONE\tTITLE\n")
+			for _, person := range people {
+				phone := ""
+				if len(person.Phones) > 0 {
+					phone = person.Phones[0]
+				}
+				title := person.JobTitle
+				if person.Company != "" {
+					title = strings.TrimPrefix(title+", "+person.Company, ", ")
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", person.DisplayName, person.Email, phone, title)
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 10, "Maximum results from each source")
+	return cmd
+}
@@ -2,6 +2,7 @@
 package convert
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 
 	conv "github.com/klytics/m365kit/internal/formats/convert"
+	"github.com/klytics/m365kit/internal/plugin"
+	"github.com/klytics/m365kit/internal/remote"
 )
 
 // NewCommand creates the "convert" command.
@@ -34,11 +37,16 @@ Supported conversions:
   .html → .docx
   .xlsx → .csv, .json, .md
 
+Text formats are printed when no output is given; a .docx converted from a
+remote input is saved in the current directory.
+
 Examples:
   kit convert document.docx --to md
   kit convert README.md --to docx --output README.docx
   kit convert data.xlsx --to csv --sheet Revenue
-  kit convert '*.docx' --to md --out-dir ./markdown/`,
+  kit convert '*.docx' --to md --out-dir ./markdown/
+  kit convert onedrive:/Reports/q1.docx --to md
+  kit convert notes.md --to docx -o sp:<site-id>/Documents/notes.docx`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if toFmt == "" {
@@ -47,23 +55,53 @@ Examples:
 
 			inputPattern := args[0]
 
+			ctx := context.Background()
+
 			// Check for glob pattern
 			if strings.Contains(inputPattern, "*") {
-				return batchConvert(inputPattern, toFmt, outDir)
+				return batchConvert(ctx, inputPattern, toFmt, outDir)
 			}
 
+			// Remote inputs are fetched to a temp file first
+			inputPath, cleanupIn, err := openInput(ctx, inputPattern)
+			if err != nil {
+				return err
+			}
+			defer cleanupIn()
+
 			// Single file conversion
 			outPath := output
+			base := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
 			if outPath == "" && outDir != "" {
-				base := strings.TrimSuffix(filepath.Base(inputPattern), filepath.Ext(inputPattern))
 				outPath = filepath.Join(outDir, base+"."+toFmt)
 			}
+			// A file written next to a remote input's temp copy would be
+			// deleted with it, so write it to the current directory
+			if outPath == "" && remote.IsRemote(inputPattern) && !printable[toFmt] {
+				outPath = base + "." + toFmt
+			}
+
+			localOut, cleanupOut, err := remote.StagingPath(outPath)
+			if err != nil {
+				return err
+			}
+			defer cleanupOut()
+
+			hook := convertHook{Input: inputPattern, File: inputPath, Output: outPath, Format: toFmt}
+			if err := plugin.RunHooks(ctx, plugin.HookPreConvert, hook); err != nil {
+				return err
+			}
 
-			result, err := conv.Convert(inputPattern, outPath, toFmt)
+			result, err := conv.Convert(inputPath, localOut, toFmt)
 			if err != nil {
 				return err
 			}
 
+			if _, err := remote.Save(ctx, localOut, outPath); err != nil {
+				return err
+			}
+			plugin.RunHooks(ctx, plugin.HookPostConvert, hook)
+
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut {
 				return json.NewEncoder(os.Stdout).Encode(map[string]string{
@@ -83,6 +121,11 @@ Examples:
 		},
 	}
 
+	// Conversions compiled into a custom build with the extension package
+	if exts := conv.Registered(); len(exts) > 0 {
+		cmd.Long += "\n\nAdded by extensions:\n  " + strings.Join(exts, "\n  ")
+	}
+
 	cmd.Flags().StringVar(&toFmt, "to", "", "Target format (md, html, txt, docx, csv, json)")
 	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file path")
 	cmd.Flags().StringVar(&sheet, "sheet", "", "Sheet name for XLSX conversion")
@@ -91,7 +134,23 @@ Examples:
 	return cmd
 }
 
-func batchConvert(pattern, toFmt, outDir string) error {
+// openInput fetches remote inputs; tests replace it.
+var openInput = remote.Open
+
+// printable are the formats printed to stdout when no output is given.
+// Others, like docx, are written to a file.
+var printable = map[string]bool{"md": true, "html": true, "txt": true, "csv": true, "json": true}
+
+// convertHook is the data passed to pre-convert and post-convert plugin
+// hooks.
+type convertHook struct {
+	Input  string `json:"input"`            // as given, possibly remote
+	File   string `json:"file"`             // local copy of the input
+	Output string `json:"output,omitempty"` // empty when printing to stdout
+	Format string `json:"format"`
+}
+
+func batchConvert(ctx context.Context, pattern, toFmt, outDir string) error {
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
 		return fmt.Errorf("invalid pattern: %w", err)
@@ -110,11 +169,18 @@ func batchConvert(pattern, toFmt, outDir string) error {
 		base := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
 		outPath := filepath.Join(outDir, base+"."+toFmt)
 
+		hook := convertHook{Input: inputPath, File: inputPath, Output: outPath, Format: toFmt}
+		if err := plugin.RunHooks(ctx, plugin.HookPreConvert, hook); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipped %s: %v\n", inputPath, err)
+			continue
+		}
+
 		_, err := conv.Convert(inputPath, outPath, toFmt)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not convert %s: %v\n", inputPath, err)
 			continue
 		}
+		plugin.RunHooks(ctx, plugin.HookPostConvert, hook)
 		fmt.Printf("Converted: %s → %s\n", inputPath, outPath)
 	}
 
@@ -0,0 +1,58 @@
+package convert
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConvertRemoteInputWithoutOutput(t *testing.T) {
+	orig := openInput
+	t.Cleanup(func() { openInput = orig })
+	// Fake a download: the input lives in a temp dir removed after the command
+	openInput = func(ctx context.Context, arg string) (string, func(), error) {
+		dir, err := os.MkdirTemp("", "kit-remote-*")
+		if err != nil {
+			return "", nil, err
+		}
+		local := filepath.Join(dir, filepath.Base(arg))
+		if err := os.WriteFile(local, []byte("# Notes\n\nHello.\n"), 0644); err != nil {
+			return "", nil, err
+		}
+		return local, func() { os.RemoveAll(dir) }, nil
+	}
+
+	for _, input := range []string{"onedrive:/notes.md", "onedrive:/page.html"} {
+		t.Run(input, func(t *testing.T) {
+			chdir(t, t.TempDir())
+			cmd := NewCommand()
+			cmd.Flags().Bool("json", false, "")
+			cmd.SetArgs([]string{input, "--to", "docx"})
+			if err := cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			name := map[string]string{"onedrive:/notes.md": "notes.docx", "onedrive:/page.html": "page.docx"}[input]
+			info, err := os.Stat(name)
+			if err != nil {
+				t.Fatalf("output was not kept: %v", err)
+			}
+			if info.Size() == 0 {
+				t.Error("output is empty")
+			}
+		})
+	}
+}
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
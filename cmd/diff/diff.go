@@ -128,26 +128,13 @@ func streamAISummary(result *docx.DiffResult, providerName, modelName string) er
 	}
 
 	ctx := context.Background()
-	textCh, errCh, err := provider.Stream(ctx, aiSummaryPrompt, []ai.Message{
-		{Role: "user", Content: changeSummary},
-	}, ai.InferOptions{MaxTokens: 512})
-	if err != nil {
-		return fmt.Errorf("AI summary failed: %w", err)
-	}
-
 	bold := color.New(color.Bold)
 	bold.Println("AI Summary:")
-	for text := range textCh {
-		fmt.Print(text)
+	messages := []ai.Message{
+		{Role: "user", Content: changeSummary},
 	}
-	fmt.Println()
-
-	select {
-	case err := <-errCh:
-		if err != nil {
-			return fmt.Errorf("streaming error: %w", err)
-		}
-	default:
+	if _, err := ai.Print(ctx, provider, os.Stdout, aiSummaryPrompt, messages, ai.InferOptions{MaxTokens: 512}); err != nil {
+		return err
 	}
 	return nil
 }
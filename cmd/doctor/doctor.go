@@ -10,6 +10,11 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/cloud"
+	"github.com/klytics/m365kit/internal/config"
 )
 
 // Check represents a single health check result.
@@ -113,7 +118,7 @@ func runChecks() []Check {
 	}
 
 	// Check auth token
-	tokenFile := configDir + "/token.json"
+	tokenFile, _ := auth.TokenPath()
 	if _, err := os.Stat(tokenFile); err == nil {
 		checks = append(checks, Check{
 			Name:    "Auth Token",
@@ -129,7 +134,13 @@ func runChecks() []Check {
 	}
 
 	// Check AI provider
-	if os.Getenv("ANTHROPIC_API_KEY") != "" {
+	if p := config.AIProvider(); p == "azure-openai" || p == "gemini" {
+		check := Check{Name: "AI Provider (" + p + ")", Status: "ok", Message: "configured"}
+		if _, err := ai.NewProvider(p, ""); err != nil {
+			check.Status, check.Message = "error", err.Error()
+		}
+		checks = append(checks, check)
+	} else if os.Getenv("ANTHROPIC_API_KEY") != "" {
 		checks = append(checks, Check{
 			Name:    "AI Provider (Anthropic)",
 			Status:  "ok",
@@ -153,17 +164,23 @@ func runChecks() []Check {
 			checks = append(checks, Check{
 				Name:    "AI Provider",
 				Status:  "warning",
-				Message: "No API key set — set ANTHROPIC_API_KEY or OPENAI_API_KEY for AI features",
+				Message: "No API key set — set ANTHROPIC_API_KEY or OPENAI_API_KEY, or configure azure-openai or gemini (kit config set provider ...), for AI features",
 			})
 		}
 	}
 
 	// Check Azure client ID
-	if os.Getenv("KIT_AZURE_CLIENT_ID") != "" {
+	if id, source := auth.ResolveClientID(); source == auth.ClientIDFromDefault {
 		checks = append(checks, Check{
 			Name:    "Azure Client ID",
 			Status:  "ok",
-			Message: "KIT_AZURE_CLIENT_ID set",
+			Message: "Using the built-in public client " + id + " — set KIT_AZURE_CLIENT_ID to use your own app",
+		})
+	} else if id != "" {
+		checks = append(checks, Check{
+			Name:    "Azure Client ID",
+			Status:  "ok",
+			Message: fmt.Sprintf("Set for profile %s (from %s)", auth.CurrentProfile(), source),
 		})
 	} else {
 		checks = append(checks, Check{
@@ -173,6 +190,21 @@ func runChecks() []Check {
 		})
 	}
 
+	// Check Microsoft cloud
+	if c, err := cloud.Load(); err != nil {
+		checks = append(checks, Check{
+			Name:    "Microsoft Cloud",
+			Status:  "error",
+			Message: err.Error(),
+		})
+	} else {
+		checks = append(checks, Check{
+			Name:    "Microsoft Cloud",
+			Status:  "ok",
+			Message: fmt.Sprintf("%s (%s)", c.Name, c.Description),
+		})
+	}
+
 	// Check git
 	if _, err := exec.LookPath("git"); err == nil {
 		checks = append(checks, Check{
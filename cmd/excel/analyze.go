@@ -97,22 +97,8 @@ func newAnalyzeCommand() *cobra.Command {
 				})
 			}
 
-			textCh, errCh, err := provider.Stream(ctx, systemPrompt, messages, ai.InferOptions{})
-			if err != nil {
-				return fmt.Errorf("AI inference failed: %w", err)
-			}
-
-			for text := range textCh {
-				fmt.Print(text)
-			}
-			fmt.Println()
-
-			select {
-			case err := <-errCh:
-				if err != nil {
-					return fmt.Errorf("streaming error: %w", err)
-				}
-			default:
+			if _, err := ai.Print(ctx, provider, os.Stdout, systemPrompt, messages, ai.InferOptions{}); err != nil {
+				return err
 			}
 
 			return nil
@@ -2,6 +2,7 @@
 package fs
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	fslib "github.com/klytics/m365kit/internal/fs"
+	"github.com/klytics/m365kit/internal/remote"
 )
 
 // NewCommand returns the fs command group.
@@ -176,7 +178,11 @@ func newDedupeCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "dedupe [directory]",
 		Short: "Find and remove duplicate Office documents",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Find and remove duplicate Office documents by content hash.
+
+Remote folders (onedrive:/... or sp:<site>/<library>/...) are downloaded to a
+temporary directory and reported only; nothing is deleted remotely.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 
@@ -185,6 +191,18 @@ func newDedupeCommand() *cobra.Command {
 				dir = args[0]
 			}
 
+			var remoteFiles map[string]string
+			if remote.IsRemote(dir) {
+				local, files, cleanup, err := remote.OpenDir(context.Background(), dir, recursive)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+				dir = local
+				remoteFiles = files
+				dryRun = true
+			}
+
 			result, err := fslib.Scan(dir, fslib.ScanOptions{
 				Recursive: recursive,
 				WithHash:  true,
@@ -195,6 +213,15 @@ func newDedupeCommand() *cobra.Command {
 
 			dupes := fslib.FindDuplicates(result.Files)
 
+			// Report remote paths instead of temp-dir paths
+			for gi := range dupes.Groups {
+				for fi, f := range dupes.Groups[gi].Files {
+					if rp, ok := remoteFiles[f.Path]; ok {
+						dupes.Groups[gi].Files[fi].Path = rp
+					}
+				}
+			}
+
 			if jsonFlag {
 				enc := json.NewEncoder(os.Stdout)
 				enc.SetIndent("", "  ")
@@ -2,19 +2,24 @@
 package onedrive
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/klytics/m365kit/internal/auth"
 	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/progress"
 )
 
 // NewCommand returns the onedrive command group.
@@ -22,21 +27,118 @@ func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "onedrive",
 		Short: "Manage OneDrive files",
-		Long:  "List, upload, download, search, and share files on Microsoft OneDrive.",
+		Long: `List, upload, download, search, and share files on Microsoft OneDrive.
+
+By default commands act on your own drive. Use --user to operate on another
+user's drive (requires delegated or admin access) or --drive for a drive ID,
+for example one returned by 'kit onedrive shared'.`,
+		Annotations: map[string]string{auth.ScopesAnnotation: "Files.ReadWrite"},
 	}
 
+	cmd.PersistentFlags().String("user", "", "Operate on another user's drive (user ID or UPN)")
+	cmd.PersistentFlags().String("drive", "", "Operate on a drive by ID")
+	cmd.MarkFlagsMutuallyExclusive("user", "drive")
+
 	cmd.AddCommand(newLsCommand())
 	cmd.AddCommand(newGetCommand())
 	cmd.AddCommand(newPutCommand())
 	cmd.AddCommand(newRecentCommand())
 	cmd.AddCommand(newSearchCommand())
 	cmd.AddCommand(newShareCommand())
+	cmd.AddCommand(newLinksCommand())
+	cmd.AddCommand(newRevokeCommand())
+	cmd.AddCommand(newQuotaCommand())
+	cmd.AddCommand(newDuCommand())
+	cmd.AddCommand(newSharedCommand())
+	cmd.AddCommand(newRmCommand())
+	cmd.AddCommand(newTrashCommand())
+
+	return cmd
+}
+
+// driveFor returns a OneDrive client for the drive selected by --user or --drive.
+func driveFor(cmd *cobra.Command, client *http.Client) *graph.OneDrive {
+	if driveID, _ := cmd.Flags().GetString("drive"); driveID != "" {
+		return graph.NewDriveByID(client, driveID)
+	}
+	if user, _ := cmd.Flags().GetString("user"); user != "" {
+		return graph.NewUserOneDrive(client, user)
+	}
+	return graph.NewOneDrive(client)
+}
+
+func newSharedCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shared",
+		Short: "List files and folders shared with you",
+		Long: `List files and folders other users have shared with you.
+
+Browse a shared folder with its drive ID:
+  kit onedrive ls --drive <drive-id> <path>`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+
+			od := graph.NewOneDrive(client)
+			od.Limit, _ = cmd.Flags().GetInt("limit")
+			items, err := od.ListSharedWithMe(ctx)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				type sharedItem struct {
+					graph.DriveItem
+					DriveID  string `json:"driveId"`
+					ItemID   string `json:"itemId"`
+					SharedBy string `json:"sharedBy"`
+					IsFolder bool   `json:"isFolder"`
+				}
+				out := make([]sharedItem, 0, len(items))
+				for _, item := range items {
+					out = append(out, sharedItem{
+						DriveItem: item,
+						DriveID:   item.RemoteDriveID,
+						ItemID:    item.RemoteID,
+						SharedBy:  item.SharedBy,
+						IsFolder:  item.IsFolder,
+					})
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+
+			if len(items) == 0 {
+				fmt.Println("Nothing has been shared with you")
+				return nil
+			}
 
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "TYPE\tNAME\tSHARED BY\tSIZE\tDRIVE ID\n")
+			for _, item := range items {
+				itemType := "file"
+				name := item.Name
+				if item.IsFolder {
+					itemType = "dir"
+					name = color.New(color.FgBlue, color.Bold).Sprint(name + "/")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", itemType, name, item.SharedBy, graph.FormatSize(item.Size), item.RemoteDriveID)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().Int("limit", 0, "Maximum number of items to return (0 = all)")
 	return cmd
 }
 
 func newLsCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "ls [path]",
 		Short: "List files in a OneDrive folder",
 		Args:  cobra.MaximumNArgs(1),
@@ -54,7 +156,8 @@ func newLsCommand() *cobra.Command {
 				folderPath = args[0]
 			}
 
-			od := graph.NewOneDrive(client)
+			od := driveFor(cmd, client)
+			od.Limit, _ = cmd.Flags().GetInt("limit")
 			items, err := od.ListFolder(ctx, folderPath)
 			if err != nil {
 				return err
@@ -92,6 +195,8 @@ func newLsCommand() *cobra.Command {
 			return w.Flush()
 		},
 	}
+	cmd.Flags().Int("limit", 0, "Maximum number of items to return (0 = all)")
+	return cmd
 }
 
 func newGetCommand() *cobra.Command {
@@ -99,9 +204,18 @@ func newGetCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "get <remote-path>",
 		Short: "Download a file from OneDrive",
-		Args:  cobra.ExactArgs(1),
+		Long: `Download a file from OneDrive.
+
+Downloads are written to <output>.partial and verified against the file hash
+reported by OneDrive before being moved into place. If a download is
+interrupted, rerun the same command to resume where it stopped.
+
+Progress (size, rate, ETA) is shown on a terminal. Use --quiet to hide it,
+or --progress-json for machine-readable progress events on stderr.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
+			quiet, _ := cmd.Flags().GetBool("quiet")
 			ctx := context.Background()
 
 			client, err := auth.RequireAuth(ctx)
@@ -114,7 +228,8 @@ func newGetCommand() *cobra.Command {
 				outputPath = filepath.Base(remotePath)
 			}
 
-			od := graph.NewOneDrive(client)
+			od := driveFor(cmd, client)
+			od.Progress = progress.ForTransfer(filepath.Base(remotePath), quiet, jsonFlag)
 			n, err := od.DownloadFile(ctx, remotePath, outputPath)
 			if err != nil {
 				return err
@@ -130,11 +245,15 @@ func newGetCommand() *cobra.Command {
 				})
 			}
 
+			if quiet {
+				return nil
+			}
 			fmt.Printf("Downloaded %s → %s (%s)\n", remotePath, outputPath, graph.FormatSize(n))
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Local output path (default: filename)")
+	cmd.Flags().BoolP("quiet", "q", false, "Suppress progress and summary output")
 	return cmd
 }
 
@@ -146,6 +265,7 @@ func newPutCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
+			quiet, _ := cmd.Flags().GetBool("quiet")
 			ctx := context.Background()
 
 			client, err := auth.RequireAuth(ctx)
@@ -158,7 +278,8 @@ func newPutCommand() *cobra.Command {
 				remotePath = filepath.Base(localPath)
 			}
 
-			od := graph.NewOneDrive(client)
+			od := driveFor(cmd, client)
+			od.Progress = progress.ForTransfer(filepath.Base(localPath), quiet, jsonFlag)
 			item, err := od.UploadFile(ctx, localPath, remotePath)
 			if err != nil {
 				return err
@@ -176,6 +297,9 @@ func newPutCommand() *cobra.Command {
 				})
 			}
 
+			if quiet {
+				return nil
+			}
 			fmt.Printf("Uploaded %s → %s (%s)\n", localPath, remotePath, graph.FormatSize(item.Size))
 			if item.WebURL != "" {
 				fmt.Printf("Web: %s\n", item.WebURL)
@@ -184,14 +308,22 @@ func newPutCommand() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&remotePath, "remote", "r", "", "Remote path (default: filename)")
+	cmd.Flags().BoolP("quiet", "q", false, "Suppress progress and summary output")
 	return cmd
 }
 
 func newRecentCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "recent",
 		Short: "List recently accessed files",
+		Long: `List files you recently opened or edited, across OneDrive and SharePoint.
+
+Microsoft Graph only tracks recent files for the signed-in user, so --user and
+--drive are not supported.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("user") || cmd.Flags().Changed("drive") {
+				return fmt.Errorf("recent lists only your own files — Graph has no recent files for another user or drive")
+			}
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			ctx := context.Background()
 
@@ -201,6 +333,7 @@ func newRecentCommand() *cobra.Command {
 			}
 
 			od := graph.NewOneDrive(client)
+			od.Limit, _ = cmd.Flags().GetInt("limit")
 			items, err := od.RecentFiles(ctx)
 			if err != nil {
 				return err
@@ -227,32 +360,83 @@ func newRecentCommand() *cobra.Command {
 			return w.Flush()
 		},
 	}
+	cmd.Flags().Int("limit", 0, "Maximum number of files to return (0 = all)")
+	return cmd
 }
 
 func newSearchCommand() *cobra.Command {
-	return &cobra.Command{
+	var exts []string
+	var after, before, folder string
+	var limit int
+	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search OneDrive files",
-		Args:  cobra.ExactArgs(1),
+		Long: `Search OneDrive files by name and content.
+
+Narrow results by file type, modification date, or folder:
+  kit onedrive search budget --ext xlsx,csv
+  kit onedrive search contract --modified-after 30d --in /Legal
+
+Dates accept a relative age (30d, 12h) or a date (2006-01-02 or RFC 3339).
+
+Graph's drive search doesn't filter by file type or date, so --ext,
+--modified-after, and --modified-before are applied to the results as they
+arrive; --limit counts the results that pass them. --in is part of the
+request.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			ctx := context.Background()
 
+			opts := graph.SearchOptions{
+				Extensions: exts,
+				Folder:     folder,
+				Limit:      limit,
+			}
+			now := time.Now()
+			if after != "" {
+				t, err := graph.ParseSince(after, now)
+				if err != nil {
+					return fmt.Errorf("invalid --modified-after: %w", err)
+				}
+				opts.ModifiedAfter = t
+			}
+			if before != "" {
+				t, err := graph.ParseSince(before, now)
+				if err != nil {
+					return fmt.Errorf("invalid --modified-before: %w", err)
+				}
+				opts.ModifiedBefore = t
+			}
+
 			client, err := auth.RequireAuth(ctx)
 			if err != nil {
 				return err
 			}
 
-			od := graph.NewOneDrive(client)
-			items, err := od.SearchFiles(ctx, args[0])
+			od := driveFor(cmd, client)
+			items, err := od.Search(ctx, args[0], opts)
 			if err != nil {
 				return err
 			}
 
 			if jsonFlag {
+				type searchResult struct {
+					graph.DriveItem
+					Path     string `json:"path"`
+					IsFolder bool   `json:"isFolder"`
+				}
+				out := make([]searchResult, 0, len(items))
+				for _, item := range items {
+					out = append(out, searchResult{
+						DriveItem: item,
+						Path:      displayParentPath(item.ParentPath),
+						IsFolder:  item.IsFolder,
+					})
+				}
 				enc := json.NewEncoder(os.Stdout)
 				enc.SetIndent("", "  ")
-				return enc.Encode(items)
+				return enc.Encode(out)
 			}
 
 			if len(items) == 0 {
@@ -261,25 +445,144 @@ func newSearchCommand() *cobra.Command {
 			}
 
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(w, "NAME\tSIZE\tPATH\n")
+			fmt.Fprintf(w, "NAME\tSIZE\tMODIFIED\tPATH\n")
 			for _, item := range items {
 				size := graph.FormatSize(item.Size)
-				path := item.ParentPath
-				if idx := strings.Index(path, ":"); idx >= 0 {
-					path = path[idx+1:]
-				}
-				fmt.Fprintf(w, "%s\t%s\t%s\n", item.Name, size, path)
+				modified := item.LastModifiedAt.Format("2006-01-02 15:04")
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Name, size, modified, displayParentPath(item.ParentPath))
 			}
 			return w.Flush()
 		},
 	}
+	cmd.Flags().StringSliceVar(&exts, "ext", nil, "Only files with these extensions (e.g. docx,pdf); filtered client-side")
+	cmd.Flags().StringVar(&after, "modified-after", "", "Only items modified after this date or age (e.g. 30d); filtered client-side")
+	cmd.Flags().StringVar(&before, "modified-before", "", "Only items modified before this date or age; filtered client-side")
+	cmd.Flags().StringVar(&folder, "in", "", "Only search within this folder (e.g. /Projects)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of results after filtering (0 = all)")
+	return cmd
+}
+
+// displayParentPath strips the "/drive/root:" prefix Graph puts on parent paths.
+func displayParentPath(p string) string {
+	if idx := strings.Index(p, ":"); idx >= 0 {
+		p = p[idx+1:]
+	}
+	if p == "" {
+		return "/"
+	}
+	return p
 }
 
 func newShareCommand() *cobra.Command {
-	var linkType string
+	var linkType, scope, expires, password string
+	var passwordStdin bool
 	cmd := &cobra.Command{
 		Use:   "share <remote-path>",
 		Short: "Create a sharing link for a file",
+		Long: `Create a sharing link for a OneDrive file.
+
+Examples:
+  kit onedrive share Reports/q1.xlsx
+  kit onedrive share Reports/q1.xlsx --scope org --expires 7d
+  kit onedrive share Reports/q1.xlsx --type edit --expires 2026-12-31 --password-stdin < pw.txt
+
+A password passed with --password is visible to other users in the process
+list. Prefer --password-stdin, or set KIT_SHARE_PASSWORD, which is used for
+anonymous links only.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			normScope, err := graph.NormalizeShareScope(scope)
+			if err != nil {
+				return err
+			}
+			if password, err = sharePassword(password, passwordStdin, os.Stdin, normScope == graph.ShareScopeAnonymous); err != nil {
+				return err
+			}
+
+			opts := graph.ShareOptions{
+				Type:     linkType,
+				Scope:    scope,
+				Password: password,
+			}
+			if expires != "" {
+				t, err := graph.ParseUntil(expires, time.Now())
+				if err != nil {
+					return fmt.Errorf("invalid --expires: %w", err)
+				}
+				opts.ExpiresAt = t
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+
+			od := driveFor(cmd, client)
+			link, err := od.CreateShareLink(ctx, args[0], opts)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				result := map[string]any{
+					"path":     args[0],
+					"type":     linkType,
+					"scope":    normScope,
+					"url":      link,
+					"password": password != "",
+				}
+				if !opts.ExpiresAt.IsZero() {
+					result["expiresAt"] = opts.ExpiresAt
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+
+			fmt.Printf("Share link (%s, %s): %s\n", linkType, normScope, link)
+			if !opts.ExpiresAt.IsZero() {
+				fmt.Printf("Expires: %s\n", opts.ExpiresAt.Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&linkType, "type", "view", "Link type: view | edit")
+	cmd.Flags().StringVar(&scope, "scope", "anonymous", "Link scope: anonymous | org | users")
+	cmd.Flags().StringVar(&expires, "expires", "", "Link expiration: duration (7d, 12h) or date (2006-01-02)")
+	cmd.Flags().StringVar(&password, "password", "", "Password for anonymous links (visible in the process list; see --password-stdin)")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "Read the link password from the first line of stdin")
+	cmd.MarkFlagsMutuallyExclusive("password", "password-stdin")
+	return cmd
+}
+
+// sharePassword returns the link password: the first line of stdin with
+// --password-stdin, else --password, else, for anonymous links,
+// KIT_SHARE_PASSWORD. Other links can't have a password, so the variable
+// is ignored rather than failing every organization or users link.
+func sharePassword(flag string, fromStdin bool, stdin io.Reader, anonymous bool) (string, error) {
+	if !fromStdin {
+		if flag != "" || !anonymous {
+			return flag, nil
+		}
+		return os.Getenv("KIT_SHARE_PASSWORD"), nil
+	}
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("could not read password from stdin: %w", err)
+	}
+	if line = strings.TrimRight(line, "\r\n"); line == "" {
+		return "", fmt.Errorf("--password-stdin: no password on stdin")
+	}
+	return line, nil
+}
+
+func newLinksCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "links <remote-path>",
+		Short: "List sharing links on a file",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
@@ -290,26 +593,154 @@ func newShareCommand() *cobra.Command {
 				return err
 			}
 
-			od := graph.NewOneDrive(client)
-			link, err := od.CreateShareLink(ctx, args[0], linkType)
+			od := driveFor(cmd, client)
+			links, err := od.ListShareLinks(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(links)
+			}
+
+			if len(links) == 0 {
+				fmt.Printf("No sharing links on %s\n", args[0])
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "ID\tTYPE\tSCOPE\tEXPIRES\tURL\n")
+			for _, p := range links {
+				expiry := "never"
+				if p.ExpiresAt != nil {
+					expiry = p.ExpiresAt.Format("2006-01-02")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.ID, p.Link.Type, p.Link.Scope, expiry, p.Link.URL)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newRevokeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <remote-path> <link-id>",
+		Short: "Revoke a sharing link on a file",
+		Long:  "Revoke a sharing link. Use 'kit onedrive links <path>' to find link IDs.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			client, err := auth.RequireAuth(ctx)
 			if err != nil {
 				return err
 			}
 
+			od := driveFor(cmd, client)
+			if err := od.RevokeShareLink(ctx, args[0], args[1]); err != nil {
+				return err
+			}
+
 			if jsonFlag {
 				enc := json.NewEncoder(os.Stdout)
 				enc.SetIndent("", "  ")
 				return enc.Encode(map[string]any{
-					"path": args[0],
-					"type": linkType,
-					"url":  link,
+					"path":    args[0],
+					"linkId":  args[1],
+					"revoked": true,
 				})
 			}
 
-			fmt.Printf("Share link (%s): %s\n", linkType, link)
+			fmt.Printf("Revoked link %s on %s\n", args[1], args[0])
 			return nil
 		},
 	}
-	cmd.Flags().StringVar(&linkType, "type", "view", "Link type: view | edit")
+}
+
+func newQuotaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "quota",
+		Short: "Show OneDrive storage usage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+
+			od := driveFor(cmd, client)
+			q, err := od.Quota(ctx)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(q)
+			}
+
+			fmt.Printf("Used:      %s of %s (%.1f%%)\n", graph.FormatSize(q.Used), graph.FormatSize(q.Total), q.UsedPct())
+			fmt.Printf("Remaining: %s\n", graph.FormatSize(q.Remaining))
+			fmt.Printf("Deleted:   %s (in recycle bin)\n", graph.FormatSize(q.Deleted))
+			if q.State != "" && q.State != "normal" {
+				color.New(color.FgYellow).Printf("State:     %s\n", q.State)
+			}
+			return nil
+		},
+	}
+}
+
+func newDuCommand() *cobra.Command {
+	var depth int
+	cmd := &cobra.Command{
+		Use:   "du [path]",
+		Short: "Show folder sizes recursively",
+		Long: `Walk a OneDrive folder recursively and report the total size of each subfolder.
+
+Examples:
+  kit onedrive du
+  kit onedrive du Projects --depth 2`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+
+			folderPath := "/"
+			if len(args) > 0 {
+				folderPath = args[0]
+			}
+
+			od := driveFor(cmd, client)
+			root, err := od.DiskUsage(ctx, folderPath)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(root)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "SIZE\tFILES\tPATH\n")
+			for _, n := range root.Flatten(depth) {
+				fmt.Fprintf(w, "%s\t%d\t%s\n", graph.FormatSize(n.Size), n.Files, n.Path)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().IntVar(&depth, "depth", 1, "Levels of subfolders to show (-1 for all)")
 	return cmd
 }
@@ -0,0 +1,66 @@
+package onedrive
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisplayParentPath(t *testing.T) {
+	tests := map[string]string{
+		"/drive/root:/Projects/2026": "/Projects/2026",
+		"/drive/root:":               "/",
+		"":                           "/",
+	}
+	for in, want := range tests {
+		if got := displayParentPath(in); got != want {
+			t.Errorf("displayParentPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDriveFlags(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"recent", "--user", "anna@contoso.com"}, "only your own files"},
+		{[]string{"ls", "--user", "anna@contoso.com", "--drive", "b!xyz"}, "none of the others"},
+	}
+	for _, tt := range tests {
+		cmd := NewCommand()
+		cmd.PersistentFlags().Bool("json", false, "")
+		cmd.SetArgs(tt.args)
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: err = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestSharePassword(t *testing.T) {
+	t.Setenv("KIT_SHARE_PASSWORD", "from-env")
+
+	tests := []struct {
+		flag      string
+		fromStdin bool
+		stdin     string
+		anonymous bool
+		want      string
+	}{
+		{"", true, "s3cret\r\nignored\n", true, "s3cret"},
+		{"", true, "s3cret", true, "s3cret"},
+		{"from-flag", false, "", true, "from-flag"},
+		{"", false, "", true, "from-env"},
+		{"", false, "", false, ""}, // organization and users links take no password
+	}
+	for _, tt := range tests {
+		got, err := sharePassword(tt.flag, tt.fromStdin, strings.NewReader(tt.stdin), tt.anonymous)
+		if err != nil || got != tt.want {
+			t.Errorf("sharePassword(%q, %v, %q, %v) = %q, %v; want %q", tt.flag, tt.fromStdin, tt.stdin, tt.anonymous, got, err, tt.want)
+		}
+	}
+
+	if _, err := sharePassword("", true, strings.NewReader("\n"), true); err == nil {
+		t.Error("expected error for an empty password on stdin")
+	}
+}
@@ -0,0 +1,236 @@
+package onedrive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newRmCommand() *cobra.Command {
+	var permanent, yes bool
+	cmd := &cobra.Command{
+		Use:   "rm <remote-path>",
+		Short: "Delete a file or folder (moves it to the recycle bin)",
+		Long: `Delete a file or folder from OneDrive.
+
+Deleted items go to the recycle bin; see 'kit onedrive trash'.
+--permanent skips the recycle bin and requires --yes.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if permanent && !yes {
+				return fmt.Errorf("--permanent cannot be undone — rerun with --yes to confirm")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+
+			od := driveFor(cmd, client)
+			if err := od.DeleteItem(ctx, args[0], permanent); err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{
+					"path":      args[0],
+					"deleted":   true,
+					"permanent": permanent,
+				})
+			}
+
+			if permanent {
+				fmt.Printf("Permanently deleted %s\n", args[0])
+			} else {
+				fmt.Printf("Deleted %s (restore with: kit onedrive trash restore %q)\n", args[0], path.Base(args[0]))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&permanent, "permanent", false, "Delete permanently instead of moving to the recycle bin")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm a permanent delete")
+	return cmd
+}
+
+func newTrashCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List, restore, or empty the OneDrive recycle bin",
+		Long: `List, restore, or empty the OneDrive recycle bin.
+
+Requires OneDrive for work or school. Items are identified by ID or by name;
+a name that matches several deleted items must be given as an ID instead.`,
+		Args: cobra.NoArgs,
+		RunE: runTrashList,
+	}
+	ls := &cobra.Command{
+		Use:   "ls",
+		Short: "List deleted items",
+		Args:  cobra.NoArgs,
+		RunE:  runTrashList,
+	}
+	for _, c := range []*cobra.Command{cmd, ls} {
+		c.Flags().Int("limit", 0, "Maximum number of items to return (0 = all)")
+	}
+	cmd.AddCommand(ls)
+	cmd.AddCommand(newTrashRestoreCommand())
+	cmd.AddCommand(newTrashEmptyCommand())
+	return cmd
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+	ctx := context.Background()
+
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return err
+	}
+
+	od := driveFor(cmd, client)
+	od.Limit, _ = cmd.Flags().GetInt("limit")
+	items, err := od.ListTrash(ctx)
+	if err != nil {
+		return err
+	}
+
+	if jsonFlag {
+		if items == nil {
+			items = []graph.TrashItem{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("Recycle bin is empty")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "NAME\tSIZE\tDELETED\tFROM\tID\n")
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			item.Name, graph.FormatSize(item.Size), item.DeletedAt.Local().Format("2006-01-02 15:04"), item.DeletedFrom, item.ID)
+	}
+	return w.Flush()
+}
+
+func newTrashRestoreCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <item>...",
+		Short: "Restore deleted items to their original location",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+
+			od := driveFor(cmd, client)
+			items, err := od.ListTrash(ctx)
+			if err != nil {
+				return err
+			}
+
+			selected, err := graph.ResolveTrash(items, args, "kit onedrive trash")
+			if err != nil {
+				return err
+			}
+
+			ids := make([]string, 0, len(selected))
+			for _, item := range selected {
+				ids = append(ids, item.ID)
+			}
+			if err := od.RestoreTrash(ctx, ids); err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"restored": selected})
+			}
+
+			for _, item := range selected {
+				fmt.Printf("Restored %s → %s\n", item.Name, item.DeletedFrom)
+			}
+			return nil
+		},
+	}
+}
+
+func newTrashEmptyCommand() *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "empty [item]...",
+		Short: "Permanently delete items from the recycle bin (all if none given)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if !yes {
+				return fmt.Errorf("emptying the recycle bin cannot be undone — rerun with --yes to confirm")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+
+			od := driveFor(cmd, client)
+			items, err := od.ListTrash(ctx)
+			if err != nil {
+				return err
+			}
+
+			selected := items
+			if len(args) > 0 {
+				if selected, err = graph.ResolveTrash(items, args, "kit onedrive trash"); err != nil {
+					return err
+				}
+			}
+
+			ids := make([]string, 0, len(selected))
+			var total int64
+			for _, item := range selected {
+				ids = append(ids, item.ID)
+				total += item.Size
+			}
+			if err := od.PurgeTrash(ctx, ids); err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{
+					"deleted": len(ids),
+					"bytes":   total,
+				})
+			}
+
+			fmt.Printf("Permanently deleted %d items (%s)\n", len(ids), graph.FormatSize(total))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm permanent deletion")
+	return cmd
+}
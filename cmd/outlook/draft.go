@@ -0,0 +1,256 @@
+package outlook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/formats/convert"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newDraftCmd() *cobra.Command {
+	var (
+		to       []string
+		cc       []string
+		bcc      []string
+		subject  string
+		body     string
+		bodyFile string
+		replyTo  int
+		replyAll bool
+		id       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "draft",
+		Short: "Save an email to Drafts for review instead of sending it",
+		Long: `Save a new message, or a reply to an inbox message, in your Drafts folder
+so it can be reviewed in Outlook before it goes out. --body-file reads the
+body from a file (- for stdin); Markdown (.md) is converted to HTML and
+.html files are used as-is. With --id an existing draft is updated instead.
+
+Review drafts with 'kit outlook draft list' and send one with
+'kit outlook draft send <index>'.`,
+		Example: `  kit outlook draft --to alice@company.com --subject "Renewal" --body-file reply.md
+  kit ai ask "Draft a polite decline to this proposal" proposal.docx | kit outlook draft --reply-to 1 --body-file -
+  kit outlook draft --id AAMkAD... --subject "Renewal (v2)"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d := graph.DraftRequest{
+				To:      splitAddresses(to),
+				Cc:      splitAddresses(cc),
+				Bcc:     splitAddresses(bcc),
+				Subject: subject,
+				Body:    body,
+			}
+			if bodyFile != "" {
+				if body != "" {
+					return fmt.Errorf("use --body or --body-file, not both")
+				}
+				var err error
+				if d.Body, d.HTML, err = readBodyFile(bodyFile); err != nil {
+					return err
+				}
+			}
+
+			if graph.NeedsLookup(d.To) || graph.NeedsLookup(d.Cc) || graph.NeedsLookup(d.Bcc) {
+				auth.RequireScopes("People.Read")
+			}
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+			for _, list := range []*[]string{&d.To, &d.Cc, &d.Bcc} {
+				if *list, err = graph.ResolveAddresses(cmd.Context(), client, *list, os.Stderr); err != nil {
+					return err
+				}
+			}
+			o := newOutlook(cmd, client)
+
+			var draft *graph.EmailMessage
+			switch {
+			case id != "":
+				draft, err = o.UpdateDraft(cmd.Context(), id, d)
+			case replyTo > 0:
+				msg, err := o.GetMessageByIndex(cmd.Context(), replyTo)
+				if err != nil {
+					return err
+				}
+				draft, err = o.CreateReplyDraft(cmd.Context(), msg.ID, d, replyAll)
+				if err != nil {
+					return err
+				}
+			default:
+				if len(d.To) == 0 {
+					return fmt.Errorf("--to is required (or --reply-to to draft a reply)")
+				}
+				if d.Subject == "" && d.Body == "" {
+					return fmt.Errorf("--subject or a body is required")
+				}
+				draft, err = o.CreateDraft(cmd.Context(), d)
+			}
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(draft)
+			}
+
+			verb := "Saved draft"
+			if id != "" {
+				verb = "Updated draft"
+			}
+			fmt.Printf("%s: %s\n", verb, draft.Subject)
+			if draft.WebLink != "" {
+				fmt.Printf("Review: %s\n", draft.WebLink)
+			}
+			fmt.Println("Send it from Outlook, or: kit outlook draft list, then kit outlook draft send <index>")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&to, "to", nil, "Recipient email or name (repeatable or comma-separated)")
+	cmd.Flags().StringArrayVar(&cc, "cc", nil, "Cc email (repeatable or comma-separated)")
+	cmd.Flags().StringArrayVar(&bcc, "bcc", nil, "Bcc email (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&subject, "subject", "", "Subject")
+	cmd.Flags().StringVar(&body, "body", "", "Body text")
+	cmd.Flags().StringVar(&bodyFile, "body-file", "", "Read the body from a file (.md, .html, or text; - for stdin)")
+	cmd.Flags().IntVar(&replyTo, "reply-to", 0, "Draft a reply to this inbox message (index)")
+	cmd.Flags().BoolVar(&replyAll, "reply-all", false, "With --reply-to, reply to all recipients")
+	cmd.Flags().StringVar(&id, "id", "", "Update this existing draft instead of creating one")
+
+	cmd.AddCommand(newDraftListCmd())
+	cmd.AddCommand(newDraftSendCmd())
+	return cmd
+}
+
+func newDraftListCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List messages in your Drafts folder",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			drafts, err := o.ListMessagesInFolder(cmd.Context(), "drafts", graph.InboxFilter{Limit: limit})
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(drafts)
+			}
+
+			if len(drafts) == 0 {
+				fmt.Println("No drafts.")
+				return nil
+			}
+			printMessages(drafts)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of drafts to return")
+	return cmd
+}
+
+func newDraftSendCmd() *cobra.Command {
+	var id string
+
+	cmd := &cobra.Command{
+		Use:         "send [index]",
+		Short:       "Send a draft",
+		Long:        "Send a draft by its index in 'kit outlook draft list', or by --id.",
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{auth.ScopesAnnotation: "Mail.Send"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			var draft *graph.EmailMessage
+			if id != "" {
+				draft, err = o.GetMessage(cmd.Context(), id)
+			} else if len(args) == 1 {
+				n, parseErr := strconv.Atoi(args[0])
+				if parseErr != nil {
+					return fmt.Errorf("invalid index: %s", args[0])
+				}
+				draft, err = o.GetMessageByIndexInFolder(cmd.Context(), "drafts", n)
+			} else {
+				return fmt.Errorf("provide an index or --id")
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := o.SendDraft(cmd.Context(), draft.ID); err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]string{"sent": draft.ID})
+			}
+
+			fmt.Printf("Sent: %s\n", draft.Subject)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "Draft message ID (alternative to index)")
+	return cmd
+}
+
+// readBodyFile reads a message body from a file, or stdin for "-".
+// Markdown is rendered to HTML; .html/.htm files are taken as HTML.
+func readBodyFile(path string) (string, bool, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("could not read body: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return convert.MarkdownToHTML(string(data)), true, nil
+	case ".html", ".htm":
+		return string(data), true, nil
+	}
+	return strings.TrimRight(string(data), "\n"), false, nil
+}
+
+// splitAddresses flattens repeatable, comma-separated address flags.
+func splitAddresses(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, e := range strings.Split(v, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
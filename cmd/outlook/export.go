@@ -0,0 +1,148 @@
+package outlook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newExportCmd() *cobra.Command {
+	var (
+		id     string
+		output string
+		folder string
+		since  string
+		limit  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [index]",
+		Short: "Save emails as .eml files (full MIME)",
+		Long: `Save an email in its full MIME form as an .eml file, with headers and
+attachments intact, for archiving or loading into e-discovery tools.
+
+With --folder every message in the folder is exported into the -o
+directory instead. File names are stable, so re-running the export only
+fetches messages that aren't there yet.`,
+		Example: `  kit outlook export 1 -o msg.eml
+  kit outlook export --folder Archive --since 2025-01-01 -o ./archive`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+			o := newOutlook(cmd, client)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			if folder != "" {
+				if len(args) > 0 || id != "" {
+					return fmt.Errorf("use an index/--id or --folder, not both")
+				}
+				f, err := o.ResolveFolder(cmd.Context(), folder)
+				if err != nil {
+					return err
+				}
+				opts := graph.MailExportOptions{FolderID: f.ID, Dir: output, Filter: graph.InboxFilter{Limit: limit}}
+				if since != "" {
+					t, err := time.Parse("2006-01-02", since)
+					if err != nil {
+						return fmt.Errorf("invalid --since date: %w (use YYYY-MM-DD)", err)
+					}
+					opts.Filter.Since = t
+				}
+				if opts.Dir == "" {
+					opts.Dir = "."
+				}
+				if !jsonOut {
+					opts.Progress = func(r graph.MailExportResult) {
+						switch {
+						case r.Error != "":
+							fmt.Fprintf(os.Stderr, "Warning: could not export %q: %s\n", r.Subject, r.Error)
+						case !r.Skipped:
+							fmt.Printf("Exported: %s\n", r.Path)
+						}
+					}
+				}
+
+				results, err := o.ExportMail(cmd.Context(), opts)
+				if err != nil && len(results) == 0 {
+					return err
+				}
+				if jsonOut {
+					if encErr := json.NewEncoder(os.Stdout).Encode(results); encErr != nil {
+						return encErr
+					}
+					return err
+				}
+				exported, skipped, failed := 0, 0, 0
+				for _, r := range results {
+					switch {
+					case r.Error != "":
+						failed++
+					case r.Skipped:
+						skipped++
+					default:
+						exported++
+					}
+				}
+				fmt.Printf("\n%d exported, %d already present, %d failed — %s\n", exported, skipped, failed, opts.Dir)
+				if err == nil && failed > 0 {
+					err = fmt.Errorf("%d of %d messages failed to export", failed, len(results))
+				}
+				return err
+			}
+
+			var msg *graph.EmailMessage
+			if id != "" {
+				msg, err = o.GetMessage(cmd.Context(), id)
+			} else if len(args) == 1 {
+				n, parseErr := strconv.Atoi(args[0])
+				if parseErr != nil {
+					return fmt.Errorf("invalid index: %s", args[0])
+				}
+				msg, err = o.GetMessageByIndex(cmd.Context(), n)
+			} else {
+				return fmt.Errorf("provide an index, --id, or --folder")
+			}
+			if err != nil {
+				return err
+			}
+
+			path := output
+			if path == "" {
+				path = graph.EMLFileName(*msg)
+			}
+			n, err := o.SaveMessageMIME(cmd.Context(), msg.ID, path)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(graph.MailExportResult{
+					MessageID: msg.ID,
+					Subject:   msg.Subject,
+					Path:      path,
+					Bytes:     n,
+				})
+			}
+
+			fmt.Printf("Exported: %s (%s)\n", path, formatSize(n))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "Message ID (alternative to index)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file, or directory with --folder (default: named after the message / current directory)")
+	cmd.Flags().StringVar(&folder, "folder", "", "Export every message in this folder")
+	cmd.Flags().StringVar(&since, "since", "", "With --folder, only emails since date (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "With --folder, maximum number of emails (0 = all)")
+	return cmd
+}
@@ -0,0 +1,129 @@
+package outlook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newFoldersCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "folders",
+		Short: "List mail folders",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			folders, err := o.ListFolders(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(folders)
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "FOLDER\tUNREAD\tTOTAL\n")
+			for _, f := range folders {
+				depth := strings.Count(f.Path, "/")
+				fmt.Fprintf(tw, "%s%s\t%d\t%d\n", strings.Repeat("  ", depth), f.DisplayName, f.UnreadItemCount, f.TotalItemCount)
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+}
+
+func newMoveCmd() *cobra.Command {
+	var (
+		to     string
+		folder string
+		id     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "move [index]",
+		Short: "Move an email to another folder",
+		Long: `Move an email to another folder. The index refers to the listing shown by
+'kit outlook inbox' (or 'kit outlook inbox --folder <name>' with --folder).
+Folders are given by name, path such as "Inbox/Projects", or ID.`,
+		Example: `  kit outlook move 3 --to Archive
+  kit outlook move 1 --folder "Inbox/Projects" --to "Deleted Items"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if to == "" {
+				return fmt.Errorf("--to is required")
+			}
+
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			dest, err := o.ResolveFolder(cmd.Context(), to)
+			if err != nil {
+				return err
+			}
+
+			var msg *graph.EmailMessage
+			if id != "" {
+				msg, err = o.GetMessage(cmd.Context(), id)
+			} else if len(args) == 1 {
+				n, parseErr := strconv.Atoi(args[0])
+				if parseErr != nil {
+					return fmt.Errorf("invalid index: %s", args[0])
+				}
+				folderID := ""
+				if folder != "" {
+					src, err := o.ResolveFolder(cmd.Context(), folder)
+					if err != nil {
+						return err
+					}
+					folderID = src.ID
+				}
+				msg, err = o.GetMessageByIndexInFolder(cmd.Context(), folderID, n)
+			} else {
+				return fmt.Errorf("provide an index or --id")
+			}
+			if err != nil {
+				return err
+			}
+
+			moved, err := o.MoveMessage(cmd.Context(), msg.ID, dest.ID)
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]string{
+					"moved":  msg.ID,
+					"newId":  moved.ID,
+					"folder": dest.Path,
+				})
+			}
+
+			fmt.Printf("Moved to %s: %s\n", dest.Path, msg.Subject)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Destination folder (required)")
+	cmd.Flags().StringVar(&folder, "folder", "", "Folder the index refers to (default: inbox listing)")
+	cmd.Flags().StringVar(&id, "id", "", "Message ID (alternative to index)")
+	return cmd
+}
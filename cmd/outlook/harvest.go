@@ -0,0 +1,100 @@
+package outlook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newHarvestCmd() *cobra.Command {
+	var (
+		from        string
+		subject     string
+		since       string
+		limit       int
+		officeOnly  bool
+		output      string
+		concurrency int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "harvest",
+		Short: "Download attachments from every matching email",
+		Long: `Download the attachments of every matching email in one go. Identical files
+are saved once (compared by SHA-256), name clashes get a " (2)" suffix, and
+manifest.json in the output directory records where every attachment came
+from. Re-running into the same directory only fetches new attachments.
+
+--from takes a full address or a domain such as vendor.com.`,
+		Example: `  kit outlook harvest --since 2025-01-01 --from vendor.com --office-only -o ./attachments
+  kit outlook harvest --subject invoice --concurrency 8 -o ./invoices --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := graph.InboxFilter{From: from, Subject: subject, Limit: limit}
+			if since != "" {
+				t, err := time.Parse("2006-01-02", since)
+				if err != nil {
+					return fmt.Errorf("invalid --since date: %w (use YYYY-MM-DD)", err)
+				}
+				filter.Since = t
+			}
+
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			opts := graph.HarvestOptions{
+				Filter:      filter,
+				OfficeOnly:  officeOnly,
+				Dir:         output,
+				Concurrency: concurrency,
+			}
+			if !jsonOut {
+				opts.Progress = func(e graph.HarvestEntry) {
+					switch {
+					case e.Error != "":
+						fmt.Fprintf(os.Stderr, "Warning: could not download %s: %s\n", e.Name, e.Error)
+					case e.Duplicate:
+						fmt.Printf("Duplicate:  %s (same as %s)\n", e.Name, e.Path)
+					default:
+						fmt.Printf("Downloaded: %s\n", filepath.Join(output, e.Path))
+					}
+				}
+			}
+
+			manifest, err := newOutlook(cmd, client).Harvest(cmd.Context(), opts)
+			if err != nil && manifest == nil {
+				return err
+			}
+
+			if jsonOut {
+				if encErr := json.NewEncoder(os.Stdout).Encode(manifest); encErr != nil {
+					return encErr
+				}
+				return err
+			}
+
+			fmt.Printf("\n%d files from %d messages (%d duplicates, %d failed) — manifest: %s\n",
+				manifest.Downloaded, manifest.Messages, manifest.Duplicates, manifest.Failed,
+				filepath.Join(output, graph.HarvestManifestName))
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Sender email or domain")
+	cmd.Flags().StringVar(&subject, "subject", "", "Filter by subject containing text")
+	cmd.Flags().StringVar(&since, "since", "", "Only emails since date (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of emails to read (0 = all)")
+	cmd.Flags().BoolVar(&officeOnly, "office-only", false, "Only download Office files (.docx/.xlsx/.pptx/.pdf)")
+	cmd.Flags().StringVarP(&output, "output", "o", ".", "Output directory")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Number of parallel downloads")
+	return cmd
+}
@@ -4,6 +4,7 @@ package outlook
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -13,28 +14,54 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/formats/convert"
 	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/progress"
 )
 
 // NewCommand creates the "outlook" command with all subcommands.
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "outlook",
-		Aliases: []string{"mail"},
-		Short:   "Read email, search, and process attachments from Outlook",
-		Long:    "Access Microsoft Outlook via Graph API to read emails, download attachments, and process Office files.",
+		Use:         "outlook",
+		Aliases:     []string{"mail"},
+		Short:       "Read email, search, and process attachments from Outlook",
+		Long:        "Access Microsoft Outlook via Graph API to read emails, download attachments, and process Office files.",
+		Annotations: map[string]string{auth.ScopesAnnotation: "Mail.ReadWrite"},
 	}
 
+	cmd.PersistentFlags().String("mailbox", "", "Work in a shared or delegated mailbox (address or user ID) instead of your own")
+
 	cmd.AddCommand(newInboxCmd())
 	cmd.AddCommand(newReadCmd())
 	cmd.AddCommand(newAttachmentsCmd())
 	cmd.AddCommand(newDownloadCmd())
+	cmd.AddCommand(newPreviewCmd())
+	cmd.AddCommand(newHarvestCmd())
+	cmd.AddCommand(newExportCmd())
 	cmd.AddCommand(newMarkReadCmd())
+	cmd.AddCommand(newMarkUnreadCmd())
+	cmd.AddCommand(newFlagCmd())
+	cmd.AddCommand(newUnflagCmd())
 	cmd.AddCommand(newReplyCmd())
+	cmd.AddCommand(newForwardCmd())
+	cmd.AddCommand(newFoldersCmd())
+	cmd.AddCommand(newMoveCmd())
+	cmd.AddCommand(newSearchCmd())
+	cmd.AddCommand(newDraftCmd())
+	cmd.AddCommand(newCategorizeCmd())
+	cmd.AddCommand(newCategoriesCmd())
+	cmd.AddCommand(newRulesCmd())
 
 	return cmd
 }
 
+// newOutlook returns an Outlook client for the mailbox chosen with --mailbox.
+func newOutlook(cmd *cobra.Command, client *http.Client) *graph.Outlook {
+	o := graph.NewOutlook(client)
+	o.Mailbox, _ = cmd.Flags().GetString("mailbox")
+	return o
+}
+
 func newInboxCmd() *cobra.Command {
 	var (
 		from          string
@@ -43,6 +70,7 @@ func newInboxCmd() *cobra.Command {
 		unread        bool
 		since         string
 		limit         int
+		folder        string
 	)
 
 	cmd := &cobra.Command{
@@ -70,8 +98,16 @@ func newInboxCmd() *cobra.Command {
 				filter.Since = t
 			}
 
-			o := graph.NewOutlook(client)
-			messages, err := o.ListInbox(cmd.Context(), filter)
+			o := newOutlook(cmd, client)
+			folderID := ""
+			if folder != "" {
+				f, err := o.ResolveFolder(cmd.Context(), folder)
+				if err != nil {
+					return err
+				}
+				folderID = f.ID
+			}
+			messages, err := o.ListMessagesInFolder(cmd.Context(), folderID, filter)
 			if err != nil {
 				return err
 			}
@@ -86,30 +122,7 @@ func newInboxCmd() *cobra.Command {
 				return nil
 			}
 
-			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(tw, " #\tFROM\tSUBJECT\tRECEIVED\tATTACH\n")
-			for i, msg := range messages {
-				unreadMark := " "
-				if !msg.IsRead {
-					unreadMark = "●"
-				}
-				attach := ""
-				if msg.HasAttachments {
-					attach = "📎"
-				}
-				subj := msg.Subject
-				if len(subj) > 45 {
-					subj = subj[:42] + "..."
-				}
-				fromAddr := msg.From.EmailAddress.Address
-				if len(fromAddr) > 30 {
-					fromAddr = fromAddr[:27] + "..."
-				}
-				fmt.Fprintf(tw, "%s%d\t%s\t%s\t%s\t%s\n",
-					unreadMark, i+1, fromAddr, subj,
-					graph.FormatEmailDate(msg.ReceivedAt), attach)
-			}
-			tw.Flush()
+			printMessages(messages)
 			return nil
 		},
 	}
@@ -120,24 +133,32 @@ func newInboxCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&unread, "unread", false, "Only unread emails")
 	cmd.Flags().StringVar(&since, "since", "", "Only emails since date (YYYY-MM-DD)")
 	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of emails to return")
+	cmd.Flags().StringVar(&folder, "folder", "", "List a mail folder by name, path, or ID instead")
 
 	return cmd
 }
 
 func newReadCmd() *cobra.Command {
-	var id string
+	var (
+		id   string
+		raw  bool
+		full bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "read [index]",
 		Short: "Read a specific email by index or ID",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Read an email. HTML bodies are rendered as Markdown (links, lists, tables,
+quotes), and the quoted history of a reply is folded away unless --full
+is given.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := auth.RequireAuth(cmd.Context())
 			if err != nil {
 				return err
 			}
 
-			o := graph.NewOutlook(client)
+			o := newOutlook(cmd, client)
 			var msg *graph.EmailMessage
 
 			if id != "" {
@@ -168,12 +189,27 @@ func newReadCmd() *cobra.Command {
 				fmt.Println("Attach:  Yes")
 			}
 			fmt.Println()
-			fmt.Println(msg.Body.Content)
+
+			body, hidden := msg.Body.Content, 0
+			if !raw {
+				if strings.EqualFold(msg.Body.ContentType, "html") {
+					body = convert.HTMLToMarkdown(body)
+				}
+				if !full {
+					body, hidden = convert.FoldQuotedReply(body)
+				}
+			}
+			fmt.Println(body)
+			if hidden > 0 {
+				fmt.Printf("\n[%d quoted lines hidden — use --full to show]\n", hidden)
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&id, "id", "", "Message ID (alternative to index)")
+	cmd.Flags().BoolVar(&raw, "raw", false, "Print the body as received (HTML is not rendered)")
+	cmd.Flags().BoolVar(&full, "full", false, "Show quoted reply history")
 	return cmd
 }
 
@@ -188,7 +224,7 @@ func newAttachmentsCmd() *cobra.Command {
 				return err
 			}
 
-			o := graph.NewOutlook(client)
+			o := newOutlook(cmd, client)
 			n, parseErr := strconv.Atoi(args[0])
 			if parseErr != nil {
 				return fmt.Errorf("invalid index: %s", args[0])
@@ -215,9 +251,9 @@ func newAttachmentsCmd() *cobra.Command {
 			}
 
 			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(tw, "NAME\tSIZE\tTYPE\n")
-			for _, att := range attachments {
-				fmt.Fprintf(tw, "%s\t%s\t%s\n", att.Name, formatSize(att.Size), att.ContentType)
+			fmt.Fprintf(tw, " #\tNAME\tSIZE\tTYPE\n")
+			for i, att := range attachments {
+				fmt.Fprintf(tw, "%2d\t%s\t%s\t%s\n", i+1, att.Name, formatSize(att.Size), att.ContentType)
 			}
 			tw.Flush()
 			return nil
@@ -225,29 +261,51 @@ func newAttachmentsCmd() *cobra.Command {
 	}
 }
 
+// downloadProgress returns a progress reporter for an attachment download,
+// or nil when the bar would be disabled anyway or clash with --json output.
+func downloadProgress(cmd *cobra.Command, label string) graph.TransferObserver {
+	t := progress.NewTransfer(label)
+	if !t.Enabled {
+		return nil
+	}
+	if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut && !t.JSON {
+		return nil
+	}
+	return t
+}
+
 func newDownloadCmd() *cobra.Command {
 	var (
+		id         string
 		output     string
 		officeOnly bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "download [index]",
-		Short: "Download attachments from an email",
-		Args:  cobra.ExactArgs(1),
+		Short: "Download attachments from an email by index or ID",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := auth.RequireAuth(cmd.Context())
 			if err != nil {
 				return err
 			}
 
-			o := graph.NewOutlook(client)
-			n, parseErr := strconv.Atoi(args[0])
-			if parseErr != nil {
-				return fmt.Errorf("invalid index: %s", args[0])
+			o := newOutlook(cmd, client)
+			var msg *graph.EmailMessage
+
+			if id != "" {
+				msg, err = o.GetMessage(cmd.Context(), id)
+			} else if len(args) == 1 {
+				n, parseErr := strconv.Atoi(args[0])
+				if parseErr != nil {
+					return fmt.Errorf("invalid index: %s", args[0])
+				}
+				msg, err = o.GetMessageByIndex(cmd.Context(), n)
+			} else {
+				return fmt.Errorf("provide an index or --id")
 			}
 
-			msg, err := o.GetMessageByIndex(cmd.Context(), n)
 			if err != nil {
 				return err
 			}
@@ -272,6 +330,7 @@ func newDownloadCmd() *cobra.Command {
 					continue
 				}
 
+				o.Progress = downloadProgress(cmd, att.Name)
 				path, err := o.DownloadAttachment(cmd.Context(), msg.ID, att.ID, output)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: could not download %s: %v\n", att.Name, err)
@@ -293,24 +352,35 @@ func newDownloadCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVar(&id, "id", "", "Message ID (alternative to index)")
 	cmd.Flags().StringVarP(&output, "output", "o", "", "Output directory (default: current)")
 	cmd.Flags().BoolVar(&officeOnly, "office-only", false, "Only download Office files (.docx/.xlsx/.pptx/.pdf)")
 
 	return cmd
 }
 
-func newMarkReadCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "mark-read [index]",
-		Short: "Mark an email as read",
-		Args:  cobra.ExactArgs(1),
+func newReplyCmd() *cobra.Command {
+	var (
+		body string
+		all  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:         "reply [index]",
+		Short:       "Reply to an email",
+		Args:        cobra.ExactArgs(1),
+		Annotations: map[string]string{auth.ScopesAnnotation: "Mail.Send"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if body == "" {
+				return fmt.Errorf("--body is required")
+			}
+
 			client, err := auth.RequireAuth(cmd.Context())
 			if err != nil {
 				return err
 			}
 
-			o := graph.NewOutlook(client)
+			o := newOutlook(cmd, client)
 			n, parseErr := strconv.Atoi(args[0])
 			if parseErr != nil {
 				return fmt.Errorf("invalid index: %s", args[0])
@@ -321,67 +391,131 @@ func newMarkReadCmd() *cobra.Command {
 				return err
 			}
 
-			if err := o.MarkAsRead(cmd.Context(), msg.ID); err != nil {
+			if all {
+				err = o.ReplyAll(cmd.Context(), msg.ID, body)
+			} else {
+				err = o.Reply(cmd.Context(), msg.ID, body)
+			}
+			if err != nil {
 				return err
 			}
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]string{"marked": msg.ID})
+				return json.NewEncoder(os.Stdout).Encode(map[string]any{"replied": msg.ID, "all": all})
 			}
 
-			fmt.Printf("Marked as read: %s\n", msg.Subject)
+			if all {
+				fmt.Printf("Replied to all: %s\n", msg.Subject)
+			} else {
+				fmt.Printf("Replied to: %s\n", msg.Subject)
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&body, "body", "", "Reply body text")
+	cmd.Flags().BoolVar(&all, "all", false, "Reply to the sender and all recipients")
+	return cmd
 }
 
-func newReplyCmd() *cobra.Command {
-	var body string
+func newForwardCmd() *cobra.Command {
+	var (
+		to      []string
+		comment string
+		id      string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "reply [index]",
-		Short: "Reply to an email",
-		Args:  cobra.ExactArgs(1),
+		Use:         "forward [index]",
+		Short:       "Forward an email",
+		Annotations: map[string]string{auth.ScopesAnnotation: "Mail.Send"},
+		Example: `  kit outlook forward 2 --to legal@company.com --comment "Can you review the attached NDA?"
+  kit outlook forward --id AAMkAD... --to alice@company.com,bob@company.com`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if body == "" {
-				return fmt.Errorf("--body is required")
+			recipients := splitAddresses(to)
+			if len(recipients) == 0 {
+				return fmt.Errorf("--to is required")
 			}
 
+			if graph.NeedsLookup(recipients) {
+				auth.RequireScopes("People.Read")
+			}
 			client, err := auth.RequireAuth(cmd.Context())
 			if err != nil {
 				return err
 			}
-
-			o := graph.NewOutlook(client)
-			n, parseErr := strconv.Atoi(args[0])
-			if parseErr != nil {
-				return fmt.Errorf("invalid index: %s", args[0])
+			if recipients, err = graph.ResolveAddresses(cmd.Context(), client, recipients, os.Stderr); err != nil {
+				return err
 			}
 
-			msg, err := o.GetMessageByIndex(cmd.Context(), n)
+			o := newOutlook(cmd, client)
+			var msg *graph.EmailMessage
+			if id != "" {
+				msg, err = o.GetMessage(cmd.Context(), id)
+			} else if len(args) == 1 {
+				n, parseErr := strconv.Atoi(args[0])
+				if parseErr != nil {
+					return fmt.Errorf("invalid index: %s", args[0])
+				}
+				msg, err = o.GetMessageByIndex(cmd.Context(), n)
+			} else {
+				return fmt.Errorf("provide an index or --id")
+			}
 			if err != nil {
 				return err
 			}
 
-			if err := o.Reply(cmd.Context(), msg.ID, body); err != nil {
+			if err := o.Forward(cmd.Context(), msg.ID, recipients, comment); err != nil {
 				return err
 			}
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]string{"replied": msg.ID})
+				return json.NewEncoder(os.Stdout).Encode(map[string]any{"forwarded": msg.ID, "to": recipients})
 			}
 
-			fmt.Printf("Replied to: %s\n", msg.Subject)
+			fmt.Printf("Forwarded to %s: %s\n", strings.Join(recipients, ", "), msg.Subject)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&body, "body", "", "Reply body text")
+	cmd.Flags().StringArrayVar(&to, "to", nil, "Recipient email or name (repeatable or comma-separated, required)")
+	cmd.Flags().StringVar(&comment, "comment", "", "Note to add above the forwarded message")
+	cmd.Flags().StringVar(&id, "id", "", "Message ID (alternative to index)")
 	return cmd
 }
 
+// printMessages prints a message listing as a numbered table; unread
+// messages are marked with a dot.
+func printMessages(messages []graph.EmailMessage) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, " #\tFROM\tSUBJECT\tRECEIVED\tATTACH\n")
+	for i, msg := range messages {
+		unreadMark := " "
+		if !msg.IsRead {
+			unreadMark = "●"
+		}
+		attach := ""
+		if msg.HasAttachments {
+			attach = "📎"
+		}
+		subj := msg.Subject
+		if len(subj) > 45 {
+			subj = subj[:42] + "..."
+		}
+		fromAddr := msg.From.EmailAddress.Address
+		if len(fromAddr) > 30 {
+			fromAddr = fromAddr[:27] + "..."
+		}
+		fmt.Fprintf(tw, "%s%d\t%s\t%s\t%s\t%s\n",
+			unreadMark, i+1, fromAddr, subj,
+			graph.FormatEmailDate(msg.ReceivedAt), attach)
+	}
+	tw.Flush()
+}
+
 func formatSize(bytes int64) string {
 	units := []string{"B", "KB", "MB", "GB"}
 	size := float64(bytes)
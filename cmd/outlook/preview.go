@@ -0,0 +1,182 @@
+package outlook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/formats/convert"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+const previewSummaryPrompt = "You are a precise document analyst. Summarize the following email attachment concisely, capturing key points, decisions, dates, figures, and action items. Be factual and avoid speculation."
+
+func newPreviewCmd() *cobra.Command {
+	var (
+		id         string
+		attachment string
+		summarize  bool
+		focus      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "preview [index]",
+		Short: "Show an email attachment as Markdown, or summarize it",
+		Long: `Download an attachment to a temporary file, convert it to Markdown, and
+print it — Word documents, every sheet of a workbook, slides, HTML, and
+text files. --attachment picks one by its number in "kit outlook
+attachments" or by name; by default the first previewable attachment
+is shown. --summarize passes it to the AI provider instead.
+
+Example:
+  kit outlook preview 1
+  kit outlook preview 1 --attachment 2
+  kit outlook preview 1 --attachment budget.xlsx --summarize
+  kit outlook preview 1 | kit ai ask "What is the total?"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			var msg *graph.EmailMessage
+
+			if id != "" {
+				msg, err = o.GetMessage(cmd.Context(), id)
+			} else if len(args) == 1 {
+				n, parseErr := strconv.Atoi(args[0])
+				if parseErr != nil {
+					return fmt.Errorf("invalid index: %s", args[0])
+				}
+				msg, err = o.GetMessageByIndex(cmd.Context(), n)
+			} else {
+				return fmt.Errorf("provide an index or --id")
+			}
+			if err != nil {
+				return err
+			}
+
+			attachments, err := o.ListAttachments(cmd.Context(), msg.ID)
+			if err != nil {
+				return err
+			}
+			att, err := pickAttachment(attachments, attachment)
+			if err != nil {
+				return err
+			}
+
+			dir, err := os.MkdirTemp("", "kit-preview-*")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, graph.AttachmentFileName(*att))
+			o.Progress = downloadProgress(cmd, att.Name)
+			if _, err := o.SaveAttachment(cmd.Context(), msg.ID, *att, path); err != nil {
+				return err
+			}
+			markdown, err := convert.FileToMarkdown(path)
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if !summarize {
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]string{
+						"messageId":  msg.ID,
+						"attachment": att.Name,
+						"markdown":   markdown,
+					})
+				}
+				fmt.Print(markdown)
+				if !strings.HasSuffix(markdown, "\n") {
+					fmt.Println()
+				}
+				return nil
+			}
+
+			providerName, _ := cmd.Flags().GetString("provider")
+			modelName, _ := cmd.Flags().GetString("model")
+			provider, err := ai.NewProvider(providerName, modelName)
+			if err != nil {
+				return err
+			}
+			system := previewSummaryPrompt
+			if focus != "" {
+				system += fmt.Sprintf("\n\nFocus your summary on these areas: %s", focus)
+			}
+			input := fmt.Sprintf("Attachment %q from the email %q:\n\n%s", att.Name, msg.Subject, markdown)
+			return summarizeAttachment(cmd.Context(), provider, system, input, jsonOut, msg.ID, att.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "Message ID (alternative to index)")
+	cmd.Flags().StringVar(&attachment, "attachment", "", "Attachment number (as in kit outlook attachments) or name")
+	cmd.Flags().BoolVar(&summarize, "summarize", false, "Summarize the attachment with the AI provider instead of printing it")
+	cmd.Flags().StringVar(&focus, "focus", "", "With --summarize: comma-separated focus areas (e.g. \"risks,dates\")")
+
+	return cmd
+}
+
+// pickAttachment selects an attachment by 1-based number or name, or the
+// first one that can be previewed when sel is empty.
+func pickAttachment(attachments []graph.Attachment, sel string) (*graph.Attachment, error) {
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("the email has no attachments")
+	}
+	if sel == "" {
+		for i := range attachments {
+			if !attachments[i].IsInline && convert.CanPreview(attachments[i].Name) {
+				return &attachments[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no attachment can be previewed (supported: .docx, .xlsx, .pptx, .html, and text)")
+	}
+	if n, err := strconv.Atoi(sel); err == nil {
+		if n < 1 || n > len(attachments) {
+			return nil, fmt.Errorf("attachment %d not found (the email has %d)", n, len(attachments))
+		}
+		return &attachments[n-1], nil
+	}
+	for i := range attachments {
+		if strings.EqualFold(attachments[i].Name, sel) {
+			return &attachments[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no attachment named %q", sel)
+}
+
+func summarizeAttachment(ctx context.Context, provider ai.Provider, system, input string, jsonOut bool, messageID, name string) error {
+	messages := []ai.Message{{Role: "user", Content: input}}
+
+	if jsonOut {
+		result, err := provider.Infer(ctx, system, messages, ai.InferOptions{})
+		if err != nil {
+			return fmt.Errorf("AI inference failed: %w", err)
+		}
+		return json.NewEncoder(os.Stdout).Encode(map[string]any{
+			"messageId":  messageID,
+			"attachment": name,
+			"summary":    result.Content,
+			"model":      result.Model,
+			"tokens":     result.InputTokens + result.OutputTokens,
+		})
+	}
+
+	if _, err := ai.Print(ctx, provider, os.Stdout, system, messages, ai.InferOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
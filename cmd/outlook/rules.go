@@ -0,0 +1,377 @@
+package outlook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newCategorizeCmd() *cobra.Command {
+	var (
+		add    []string
+		remove []string
+		clear  bool
+		id     string
+		folder string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "categorize [index]",
+		Short: "Add or remove categories on an email",
+		Long: `Add or remove Outlook categories on an email. Categories that don't exist
+yet are added to your category list so they show up with a color.`,
+		Example: `  kit outlook categorize 3 --add Invoices
+  kit outlook categorize 1 --add "Follow up" --remove Urgent
+  kit outlook categorize --id AAMkAD... --clear`,
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{auth.ScopesAnnotation: "MailboxSettings.ReadWrite"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			add, remove = splitAddresses(add), splitAddresses(remove)
+			if len(add) == 0 && len(remove) == 0 && !clear {
+				return fmt.Errorf("--add, --remove, or --clear is required")
+			}
+
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			var msg *graph.EmailMessage
+			if id != "" {
+				msg, err = o.GetMessage(cmd.Context(), id)
+			} else if len(args) == 1 {
+				n, parseErr := strconv.Atoi(args[0])
+				if parseErr != nil {
+					return fmt.Errorf("invalid index: %s", args[0])
+				}
+				folderID := ""
+				if folder != "" {
+					f, err := o.ResolveFolder(cmd.Context(), folder)
+					if err != nil {
+						return err
+					}
+					folderID = f.ID
+				}
+				msg, err = o.GetMessageByIndexInFolder(cmd.Context(), folderID, n)
+			} else {
+				return fmt.Errorf("provide an index or --id")
+			}
+			if err != nil {
+				return err
+			}
+
+			if len(add) > 0 {
+				if _, err := o.EnsureCategories(cmd.Context(), add); err != nil {
+					return err
+				}
+			}
+			current := msg.Categories
+			if clear {
+				current = nil
+			}
+			updated, err := o.SetCategories(cmd.Context(), msg.ID, graph.MergeCategories(current, add, remove))
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]any{"message": msg.ID, "categories": updated.Categories})
+			}
+
+			if len(updated.Categories) == 0 {
+				fmt.Printf("No categories: %s\n", msg.Subject)
+			} else {
+				fmt.Printf("Categories [%s]: %s\n", strings.Join(updated.Categories, ", "), msg.Subject)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&add, "add", nil, "Category to add (repeatable or comma-separated)")
+	cmd.Flags().StringArrayVar(&remove, "remove", nil, "Category to remove (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&clear, "clear", false, "Remove all existing categories first")
+	cmd.Flags().StringVar(&id, "id", "", "Message ID (alternative to index)")
+	cmd.Flags().StringVar(&folder, "folder", "", "Folder the index refers to (default: inbox listing)")
+	return cmd
+}
+
+func newCategoriesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:         "categories",
+		Short:       "List your Outlook categories",
+		Annotations: map[string]string{auth.ScopesAnnotation: "MailboxSettings.ReadWrite"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			categories, err := newOutlook(cmd, client).ListCategories(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(categories)
+			}
+
+			if len(categories) == 0 {
+				fmt.Println("No categories.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "CATEGORY\tCOLOR\n")
+			for _, c := range categories {
+				fmt.Fprintf(tw, "%s\t%s\n", c.DisplayName, c.Color)
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+}
+
+func newRulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "rules",
+		Short:       "List, create, and delete inbox rules",
+		Annotations: map[string]string{auth.ScopesAnnotation: "MailboxSettings.ReadWrite"},
+	}
+	cmd.AddCommand(newRulesListCmd())
+	cmd.AddCommand(newRulesCreateCmd())
+	cmd.AddCommand(newRulesDeleteCmd())
+	return cmd
+}
+
+func newRulesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List inbox rules in the order they run",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			rules, err := newOutlook(cmd, client).ListRules(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(rules)
+			}
+
+			if len(rules) == 0 {
+				fmt.Println("No inbox rules.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "SEQ\tNAME\tENABLED\tWHEN\tTHEN\n")
+			for _, r := range rules {
+				enabled := "yes"
+				if !r.IsEnabled {
+					enabled = "no"
+				}
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", r.Sequence, r.DisplayName, enabled, describeConditions(r.Conditions), describeActions(r.Actions))
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+}
+
+func newRulesCreateCmd() *cobra.Command {
+	var (
+		name            string
+		from            []string
+		senderContains  []string
+		subjectContains []string
+		bodyContains    []string
+		hasAttachments  bool
+		moveTo          string
+		categories      []string
+		markRead        bool
+		stop            bool
+		disabled        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an inbox rule",
+		Long: `Create an inbox rule that runs on incoming mail. All given conditions must
+match; every given action is applied.`,
+		Example: `  kit outlook rules create --name Invoices --subject-contains invoice --move-to "Inbox/Invoices" --category Invoices
+  kit outlook rules create --name "Vendor mail" --sender-contains vendor.com --category Vendors --mark-read`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+			rule := graph.MessageRule{
+				DisplayName: name,
+				IsEnabled:   !disabled,
+				Conditions: graph.RuleConditions{
+					SenderContains:  splitAddresses(senderContains),
+					SubjectContains: subjectContains,
+					BodyContains:    bodyContains,
+					HasAttachments:  hasAttachments,
+				},
+				Actions: graph.RuleActions{
+					AssignCategories:    splitAddresses(categories),
+					MarkAsRead:          markRead,
+					StopProcessingRules: stop,
+				},
+			}
+			for _, addr := range splitAddresses(from) {
+				rule.Conditions.FromAddresses = append(rule.Conditions.FromAddresses, graph.EmailRecipient{EmailAddress: graph.EmailAddr{Address: addr}})
+			}
+			if describeConditions(rule.Conditions) == "any message" {
+				return fmt.Errorf("at least one condition is required (--from, --sender-contains, --subject-contains, --body-contains, --has-attachments)")
+			}
+			if moveTo == "" && len(rule.Actions.AssignCategories) == 0 && !markRead {
+				return fmt.Errorf("at least one action is required (--move-to, --category, --mark-read)")
+			}
+
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			if moveTo != "" {
+				f, err := o.ResolveFolder(cmd.Context(), moveTo)
+				if err != nil {
+					return err
+				}
+				rule.Actions.MoveToFolder = f.ID
+			}
+			if len(rule.Actions.AssignCategories) > 0 {
+				if _, err := o.EnsureCategories(cmd.Context(), rule.Actions.AssignCategories); err != nil {
+					return err
+				}
+			}
+
+			created, err := o.CreateRule(cmd.Context(), rule)
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(created)
+			}
+
+			fmt.Printf("Rule created: %s\n", created.DisplayName)
+			fmt.Printf("  When: %s\n", describeConditions(rule.Conditions))
+			fmt.Printf("  Then: %s\n", describeActions(rule.Actions))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Rule name (required)")
+	cmd.Flags().StringArrayVar(&from, "from", nil, "Sender address (repeatable or comma-separated)")
+	cmd.Flags().StringArrayVar(&senderContains, "sender-contains", nil, "Text in the sender address, e.g. a domain (repeatable)")
+	cmd.Flags().StringArrayVar(&subjectContains, "subject-contains", nil, "Text in the subject (repeatable)")
+	cmd.Flags().StringArrayVar(&bodyContains, "body-contains", nil, "Text in the body (repeatable)")
+	cmd.Flags().BoolVar(&hasAttachments, "has-attachments", false, "Only messages with attachments")
+	cmd.Flags().StringVar(&moveTo, "move-to", "", "Move matching mail to this folder")
+	cmd.Flags().StringArrayVar(&categories, "category", nil, "Assign this category (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&markRead, "mark-read", false, "Mark matching mail as read")
+	cmd.Flags().BoolVar(&stop, "stop", false, "Don't run later rules on matching mail")
+	cmd.Flags().BoolVar(&disabled, "disabled", false, "Create the rule turned off")
+	return cmd
+}
+
+func newRulesDeleteCmd() *cobra.Command {
+	var confirm bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <name|id>",
+		Short: "Delete an inbox rule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !confirm {
+				return fmt.Errorf("deleting a rule cannot be undone — re-run with --confirm")
+			}
+
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			rule, err := o.ResolveRule(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if err := o.DeleteRule(cmd.Context(), rule.ID); err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]string{"deleted": rule.ID})
+			}
+
+			fmt.Printf("Rule deleted: %s\n", rule.DisplayName)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Confirm deletion")
+	return cmd
+}
+
+func describeConditions(c graph.RuleConditions) string {
+	var parts []string
+	for _, r := range c.FromAddresses {
+		parts = append(parts, "from "+r.EmailAddress.Address)
+	}
+	for _, s := range c.SenderContains {
+		parts = append(parts, "sender has "+strconv.Quote(s))
+	}
+	for _, s := range c.SubjectContains {
+		parts = append(parts, "subject has "+strconv.Quote(s))
+	}
+	for _, s := range c.BodyContains {
+		parts = append(parts, "body has "+strconv.Quote(s))
+	}
+	if c.HasAttachments {
+		parts = append(parts, "has attachments")
+	}
+	if len(parts) == 0 {
+		return "any message"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeActions(a graph.RuleActions) string {
+	var parts []string
+	if a.MoveToFolder != "" {
+		parts = append(parts, "move")
+	}
+	for _, c := range a.AssignCategories {
+		parts = append(parts, "categorize "+strconv.Quote(c))
+	}
+	if a.MarkAsRead {
+		parts = append(parts, "mark read")
+	}
+	if a.StopProcessingRules {
+		parts = append(parts, "stop")
+	}
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
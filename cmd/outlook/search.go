@@ -0,0 +1,68 @@
+package outlook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+)
+
+func newSearchCmd() *cobra.Command {
+	var (
+		folder string
+		limit  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search email on the server with KQL",
+		Long: `Search the whole mailbox (or one folder) on the server using Keyword
+Query Language, e.g. from:, to:, subject:, body:, hasattachment:, received:,
+and free text. Results are newest first; use --json for message IDs to pass
+to 'kit outlook read --id' or 'kit outlook move --id'.`,
+		Example: `  kit outlook search "from:legal hasattachment:true subject:NDA"
+  kit outlook search "received>=2026-01-01 invoice" --folder Archive --limit 100`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			o := newOutlook(cmd, client)
+			folderID := ""
+			if folder != "" {
+				f, err := o.ResolveFolder(cmd.Context(), folder)
+				if err != nil {
+					return err
+				}
+				folderID = f.ID
+			}
+
+			messages, err := o.SearchMessages(cmd.Context(), strings.Join(args, " "), folderID, limit)
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(messages)
+			}
+
+			if len(messages) == 0 {
+				fmt.Println("No messages found.")
+				return nil
+			}
+			printMessages(messages)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&folder, "folder", "", "Only search this mail folder")
+	cmd.Flags().IntVar(&limit, "limit", 25, "Maximum number of emails to return")
+	return cmd
+}
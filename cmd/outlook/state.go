@@ -0,0 +1,216 @@
+package outlook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// stateChange describes one of the read/flag commands: how to apply it, and
+// which messages --all considers (e.g. only unread ones for mark-read).
+type stateChange struct {
+	use, short string
+	verb       string // "Marked as read", shown before each subject or count
+	jsonKey    string
+	narrow     func(*graph.InboxFilter)
+	apply      func(ctx context.Context, o *graph.Outlook, ids []string) (int, error)
+}
+
+func newMarkReadCmd() *cobra.Command {
+	return newStateCmd(stateChange{
+		use:     "mark-read [index]",
+		short:   "Mark emails as read",
+		verb:    "Marked as read",
+		jsonKey: "marked",
+		narrow:  func(f *graph.InboxFilter) { f.UnreadOnly = true },
+		apply: func(ctx context.Context, o *graph.Outlook, ids []string) (int, error) {
+			return o.SetRead(ctx, ids, true)
+		},
+	})
+}
+
+func newMarkUnreadCmd() *cobra.Command {
+	return newStateCmd(stateChange{
+		use:     "mark-unread [index]",
+		short:   "Mark emails as unread",
+		verb:    "Marked as unread",
+		jsonKey: "marked",
+		apply: func(ctx context.Context, o *graph.Outlook, ids []string) (int, error) {
+			return o.SetRead(ctx, ids, false)
+		},
+	})
+}
+
+func newFlagCmd() *cobra.Command {
+	var complete bool
+	cmd := newStateCmd(stateChange{
+		use:     "flag [index]",
+		short:   "Flag emails for follow-up",
+		verb:    "Flagged",
+		jsonKey: "flagged",
+		apply: func(ctx context.Context, o *graph.Outlook, ids []string) (int, error) {
+			if complete {
+				return o.SetFlag(ctx, ids, graph.FlagComplete)
+			}
+			return o.SetFlag(ctx, ids, graph.FlagFlagged)
+		},
+	})
+	cmd.Flags().BoolVar(&complete, "complete", false, "Mark the flag complete instead")
+	return cmd
+}
+
+func newUnflagCmd() *cobra.Command {
+	return newStateCmd(stateChange{
+		use:     "unflag [index]",
+		short:   "Clear the follow-up flag on emails",
+		verb:    "Unflagged",
+		jsonKey: "unflagged",
+		narrow:  func(f *graph.InboxFilter) { f.FlaggedOnly = true },
+		apply: func(ctx context.Context, o *graph.Outlook, ids []string) (int, error) {
+			return o.SetFlag(ctx, ids, graph.FlagNotFlagged)
+		},
+	})
+}
+
+// newStateCmd builds a command that changes one email (by index or --id)
+// or, with --all, every matching email in a folder, batching the updates.
+func newStateCmd(change stateChange) *cobra.Command {
+	var (
+		id      string
+		all     bool
+		from    string
+		subject string
+		since   string
+		folder  string
+		limit   int
+		dryRun  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   change.use,
+		Short: change.short,
+		Long: change.short + ` — one by index or --id, or with --all every matching
+email in a folder (default: inbox). --from takes a full address, a
+prefix such as "notifications@", or a domain. Updates are sent 20 to a
+request.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && (id != "" || len(args) == 1) {
+				return fmt.Errorf("use an index, --id, or --all, not several")
+			}
+			if !all && (from != "" || subject != "" || since != "" || folder != "") {
+				return fmt.Errorf("--from, --subject, --since, and --folder need --all")
+			}
+
+			client, err := auth.RequireAuth(cmd.Context())
+			if err != nil {
+				return err
+			}
+			o := newOutlook(cmd, client)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			if !all {
+				var msg *graph.EmailMessage
+				if id != "" {
+					msg, err = o.GetMessage(cmd.Context(), id)
+				} else if len(args) == 1 {
+					n, parseErr := strconv.Atoi(args[0])
+					if parseErr != nil {
+						return fmt.Errorf("invalid index: %s", args[0])
+					}
+					msg, err = o.GetMessageByIndex(cmd.Context(), n)
+				} else {
+					return fmt.Errorf("provide an index, --id, or --all")
+				}
+				if err != nil {
+					return err
+				}
+				if dryRun {
+					fmt.Printf("Would change: %s\n", msg.Subject)
+					return nil
+				}
+				if _, err := change.apply(cmd.Context(), o, []string{msg.ID}); err != nil {
+					return err
+				}
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]string{change.jsonKey: msg.ID})
+				}
+				fmt.Printf("%s: %s\n", change.verb, msg.Subject)
+				return nil
+			}
+
+			filter := graph.InboxFilter{From: from, Subject: subject, Limit: limit}
+			if since != "" {
+				t, err := time.Parse("2006-01-02", since)
+				if err != nil {
+					return fmt.Errorf("invalid --since date: %w (use YYYY-MM-DD)", err)
+				}
+				filter.Since = t
+			}
+			if change.narrow != nil {
+				change.narrow(&filter)
+			}
+			folderID := "inbox"
+			if folder != "" {
+				f, err := o.ResolveFolder(cmd.Context(), folder)
+				if err != nil {
+					return err
+				}
+				folderID = f.ID
+			}
+
+			messages, err := o.FindMessages(cmd.Context(), folderID, filter)
+			if err != nil {
+				return err
+			}
+			ids := make([]string, len(messages))
+			for i, m := range messages {
+				ids[i] = m.ID
+			}
+
+			if dryRun {
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(messages)
+				}
+				if len(messages) == 0 {
+					fmt.Println("No messages match.")
+					return nil
+				}
+				printMessages(messages)
+				fmt.Printf("\n%d message(s) would change (dry run)\n", len(messages))
+				return nil
+			}
+
+			n, err := change.apply(cmd.Context(), o, ids)
+			if jsonOut {
+				if encErr := json.NewEncoder(os.Stdout).Encode(map[string]any{"matched": len(ids), "updated": n}); encErr != nil {
+					return encErr
+				}
+			} else if len(ids) == 0 {
+				fmt.Println("No messages match.")
+			} else {
+				fmt.Printf("%s: %d message(s)\n", change.verb, n)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "Message ID (alternative to index)")
+	cmd.Flags().BoolVar(&all, "all", false, "Change every matching email in the folder")
+	cmd.Flags().StringVar(&from, "from", "", "With --all: sender address, prefix (notifications@), or domain")
+	cmd.Flags().StringVar(&subject, "subject", "", "With --all: subject containing text")
+	cmd.Flags().StringVar(&since, "since", "", "With --all: only emails since date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&folder, "folder", "", "With --all: mail folder by name, path, or ID (default: inbox)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "With --all: change at most this many emails (0 = no limit)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without changing it")
+
+	return cmd
+}
@@ -2,10 +2,15 @@
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"runtime"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -22,6 +27,9 @@ func NewCommand() *cobra.Command {
 
 	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newInstallCmd())
+	cmd.AddCommand(newUpdateCmd())
+	cmd.AddCommand(newSearchCmd())
+	cmd.AddCommand(newInfoCmd())
 	cmd.AddCommand(newRemoveCmd())
 	cmd.AddCommand(newRunCmd())
 	cmd.AddCommand(newShowCmd())
@@ -31,7 +39,9 @@ func NewCommand() *cobra.Command {
 }
 
 func newListCmd() *cobra.Command {
-	return &cobra.Command{
+	var outdated bool
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List installed plugins",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -39,6 +49,9 @@ func newListCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if outdated {
+				return listOutdated(cmd, plugins)
+			}
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut {
@@ -82,6 +95,246 @@ func newListCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&outdated, "outdated", false, "Only list plugins with a newer version at their source")
+	return cmd
+}
+
+func listOutdated(cmd *cobra.Command, plugins []pluginpkg.Plugin) error {
+	outdated := pluginpkg.CheckOutdated(cmd.Context(), plugins)
+
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if outdated == nil {
+			outdated = []pluginpkg.Outdated{}
+		}
+		return enc.Encode(outdated)
+	}
+
+	if len(outdated) == 0 {
+		fmt.Println("All plugins are up to date.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "NAME\tINSTALLED\tLATEST\tSOURCE\n")
+	for _, o := range outdated {
+		latest := o.Latest
+		if o.Error != "" {
+			latest = "? (" + o.Error + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", o.Name, orDash(o.Installed), latest, o.Source)
+	}
+	tw.Flush()
+	fmt.Println("\nUpdate with: kit plugin update --all")
+	return nil
+}
+
+func newUpdateCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "update [name]",
+		Short: "Update plugins from the source they were installed from",
+		Long: `Reinstall a plugin from the source it was installed from — its local
+directory, or the latest release of its GitHub repository — when that
+source has a newer version. With --all, update every outdated plugin.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) == 1) {
+				return fmt.Errorf("name a plugin to update, or pass --all")
+			}
+			plugins, err := pluginpkg.Discover()
+			if err != nil {
+				return err
+			}
+			if !all {
+				var found []pluginpkg.Plugin
+				for _, p := range plugins {
+					if p.Name == args[0] {
+						found = append(found, p)
+					}
+				}
+				if len(found) == 0 {
+					return fmt.Errorf("plugin %q not found", args[0])
+				}
+				plugins = found
+			}
+
+			type result struct {
+				Name  string `json:"name"`
+				From  string `json:"from"`
+				To    string `json:"to"`
+				Error string `json:"error,omitempty"`
+			}
+			var results []result
+			var failed int
+			for _, o := range pluginpkg.CheckOutdated(cmd.Context(), plugins) {
+				if o.Error != "" {
+					results = append(results, result{Name: o.Name, From: o.Installed, Error: o.Error})
+					failed++
+					continue
+				}
+				p, from, err := pluginpkg.Update(cmd.Context(), o.Name)
+				if err != nil {
+					results = append(results, result{Name: o.Name, From: o.Installed, Error: err.Error()})
+					failed++
+					continue
+				}
+				results = append(results, result{Name: p.Name, From: from, To: p.Version})
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if results == nil {
+					results = []result{}
+				}
+				if err := enc.Encode(results); err != nil {
+					return err
+				}
+			} else if len(results) == 0 {
+				fmt.Println("All plugins are up to date.")
+			} else {
+				for _, r := range results {
+					if r.Error != "" {
+						fmt.Printf("✗ %s: %s\n", r.Name, r.Error)
+					} else {
+						fmt.Printf("✓ %s %s → %s\n", r.Name, orDash(r.From), r.To)
+					}
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d plugin(s) could not be updated", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Update every outdated plugin")
+	return cmd
+}
+
+func newSearchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "search [term]",
+		Short: "Search the plugin registry",
+		Long: `Search the plugin registry by name, description, and tags. The
+registry is a JSON index at plugins.registry in ~/.kit/config.yaml (or
+KIT_PLUGIN_REGISTRY), over HTTPS or as a file — see docs/plugins.md.
+
+  kit config set plugins.registry https://plugins.contoso.com/index.json
+  kit plugin search acl`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ix, err := pluginpkg.FetchIndex(cmd.Context())
+			if err != nil {
+				return err
+			}
+			term := ""
+			if len(args) == 1 {
+				term = args[0]
+			}
+			found := ix.Search(term)
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if found == nil {
+					found = []pluginpkg.IndexEntry{}
+				}
+				return enc.Encode(found)
+			}
+
+			if len(found) == 0 {
+				fmt.Printf("No plugins match %q.\n", term)
+				return nil
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "NAME\tLATEST\tDESCRIPTION\n")
+			for _, e := range found {
+				latest := "-"
+				if v, err := e.Latest(); err == nil {
+					latest = v.Version
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Name, latest, orDash(e.Description))
+			}
+			tw.Flush()
+			fmt.Println("\nInstall with: kit plugin install <name>")
+			return nil
+		},
+	}
+}
+
+func newInfoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info <name>",
+		Short: "Show a plugin's registry entry and versions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ix, err := pluginpkg.FetchIndex(cmd.Context())
+			if err != nil {
+				return err
+			}
+			e, err := ix.Find(args[0])
+			if err != nil {
+				return err
+			}
+
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(e)
+			}
+
+			fmt.Printf("Name:        %s\n", e.Name)
+			if e.Description != "" {
+				fmt.Printf("Description: %s\n", e.Description)
+			}
+			if e.Author != "" {
+				fmt.Printf("Author:      %s\n", e.Author)
+			}
+			if e.Homepage != "" {
+				fmt.Printf("Homepage:    %s\n", e.Homepage)
+			}
+			if len(e.Tags) > 0 {
+				fmt.Printf("Tags:        %s\n", strings.Join(e.Tags, ", "))
+			}
+			if installed, err := pluginpkg.Get(e.Name); err == nil {
+				fmt.Printf("Installed:   %s\n", orDash(installed.Version))
+			}
+			latest, _ := e.Latest()
+
+			fmt.Println("\nVersions:")
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "  VERSION\tNEEDS KIT\tRELEASED\t\n")
+			versions := append([]pluginpkg.IndexVersion(nil), e.Versions...)
+			sort.Slice(versions, func(i, j int) bool {
+				return pluginpkg.CompareVersions(versions[i].Version, versions[j].Version) > 0
+			})
+			for _, v := range versions {
+				mark := ""
+				if latest != nil && v.Version == latest.Version {
+					mark = "latest"
+				}
+				fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", v.Version, orDash(v.MinVersion), orDash(v.Released), mark)
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
 }
 
 func newInstallCmd() *cobra.Command {
@@ -89,11 +342,29 @@ func newInstallCmd() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "install <source>",
-		Short: "Install a plugin from a local path",
-		Args:  cobra.ExactArgs(1),
+		Short: "Install a plugin from a local path, GitHub release, or the registry",
+		Long: `Install a plugin from a local directory (with plugin.yaml), a local
+kit-<name> executable, a GitHub repository's release, or by name from the
+plugin registry (plugins.registry in config):
+
+  kit plugin install ./my-plugin/
+  kit plugin install github.com/contoso/kit-acl-report
+  kit plugin install github.com/contoso/kit-acl-report@v1.2.0
+  kit plugin install acl-report
+  kit plugin install acl-report@1.1.0
+
+A GitHub release needs an asset for your OS and architecture (e.g.
+kit-acl-report_linux_amd64.tar.gz): a .tar.gz of the plugin directory, or
+the executable itself, plus its SHA-256 in checksums.txt, SHA256SUMS, or
+<asset>.sha256. Registry downloads are checked against the index's sha256.
+A download without a checksum, or that does not match it, is refused.
+
+kit remembers the source, so kit plugin update can bring the plugin up to
+date later.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			source := args[0]
-			p, err := pluginpkg.Install(source)
+			p, err := pluginpkg.Install(cmd.Context(), source)
 			if err != nil {
 				return err
 			}
@@ -108,6 +379,9 @@ func newInstallCmd() *cobra.Command {
 				fmt.Printf(" v%s", p.Version)
 			}
 			fmt.Println()
+			if err := p.CheckCompatible(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
 			fmt.Printf("Run with: kit plugin run %s\n", p.Name)
 			return nil
 		},
@@ -140,14 +414,39 @@ func newRemoveCmd() *cobra.Command {
 }
 
 func newRunCmd() *cobra.Command {
-	return &cobra.Command{
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "run <name> [args...]",
 		Short: "Run a plugin",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Run a plugin with the context of this command: the active profile,
+the shell session's default site and team, --json and --verbose, and a
+temporary workspace directory, passed as KIT_* environment variables.
+
+With --timeout the plugin is interrupted when the time is up, and killed
+if it has not exited a few seconds later.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return pluginpkg.Run(cmd.Context(), args[0], args[1:])
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			inv := pluginpkg.NewInvocation(ctx)
+			if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut {
+				inv.JSON = true
+			}
+			if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+				inv.Verbose = true
+			}
+			return pluginpkg.Run(ctx, args[0], args[1:], inv)
 		},
 	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Stop the plugin after this long (e.g. 5m)")
+	return cmd
 }
 
 func newShowCmd() *cobra.Command {
@@ -180,6 +479,30 @@ func newShowCmd() *cobra.Command {
 			}
 			fmt.Printf("Type:        %s\n", p.Type)
 			fmt.Printf("Path:        %s\n", p.Path)
+			if p.Manifest != nil && len(p.Manifest.Hooks) > 0 {
+				fmt.Printf("Hooks:       %s\n", strings.Join(p.Manifest.Hooks, ", "))
+			}
+			if p.Manifest != nil {
+				if m := p.Manifest.MinVersion; m != "" {
+					fmt.Printf("Needs kit:   %s or newer\n", strings.TrimPrefix(m, "v"))
+				}
+				if r := p.Manifest.Requires; len(r.Env) > 0 || r.Auth || len(r.Scopes) > 0 {
+					var needs []string
+					if len(r.Env) > 0 {
+						needs = append(needs, "env "+strings.Join(r.Env, ", "))
+					}
+					if r.Auth || len(r.Scopes) > 0 {
+						needs = append(needs, "Microsoft 365 sign-in")
+					}
+					if len(r.Scopes) > 0 {
+						needs = append(needs, "scopes "+strings.Join(r.Scopes, ", "))
+					}
+					fmt.Printf("Requires:    %s\n", strings.Join(needs, "; "))
+				}
+				if err := p.CheckCompatible(); err != nil {
+					fmt.Printf("Status:      incompatible — %v\n", err)
+				}
+			}
 			if !p.InstalledAt.IsZero() {
 				fmt.Printf("Installed:   %s\n", p.InstalledAt.Format("2006-01-02 15:04"))
 			}
@@ -204,6 +527,9 @@ func newNewCmd() *cobra.Command {
 			}
 			if pluginType == "" {
 				pluginType = "shell"
+				if runtime.GOOS == "windows" {
+					pluginType = "powershell"
+				}
 			}
 			if outputDir == "" {
 				outputDir = "."
@@ -213,24 +539,26 @@ func newNewCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			execName := pluginpkg.ScaffoldExecutable(name, pluginType)
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut {
 				return json.NewEncoder(os.Stdout).Encode(map[string]string{
-					"name": name,
-					"type": pluginType,
-					"path": dir,
+					"name":       name,
+					"type":       pluginType,
+					"path":       dir,
+					"executable": execName,
 				})
 			}
 
 			fmt.Printf("Created plugin scaffold: %s/\n\n", dir)
 			fmt.Println("Files:")
 			fmt.Printf("  %s/plugin.yaml          — plugin metadata\n", name)
-			fmt.Printf("  %s/kit-%s     — plugin executable\n", name, name)
+			fmt.Printf("  %s/%s     — plugin executable\n", name, execName)
 			fmt.Printf("  %s/README.md             — usage docs\n", name)
 			fmt.Println()
 			fmt.Println("Next steps:")
-			fmt.Printf("  1. Edit %s/kit-%s\n", name, name)
+			fmt.Printf("  1. Edit %s/%s\n", name, execName)
 			fmt.Printf("  2. kit plugin install --local %s/\n", dir)
 			fmt.Printf("  3. kit %s <args>\n", name)
 			return nil
@@ -238,7 +566,7 @@ func newNewCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&name, "name", "", "Plugin name (required)")
-	cmd.Flags().StringVar(&pluginType, "type", "shell", "Plugin type: shell | go")
+	cmd.Flags().StringVar(&pluginType, "type", "", "Plugin type: shell | powershell | go (default: powershell on Windows, shell elsewhere)")
 	cmd.Flags().StringVarP(&outputDir, "output", "o", ".", "Output directory")
 	return cmd
 }
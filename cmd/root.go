@@ -6,14 +6,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	aipkg "github.com/klytics/m365kit/internal/ai"
+	aliaspkg "github.com/klytics/m365kit/internal/alias"
 	auditpkg "github.com/klytics/m365kit/internal/audit"
+	authpkg "github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/cmdlog"
 	"github.com/klytics/m365kit/internal/config"
+	redactpkg "github.com/klytics/m365kit/internal/redact"
 	shellpkg "github.com/klytics/m365kit/internal/shell"
 
 	"github.com/klytics/m365kit/cmd/acl"
@@ -22,6 +28,9 @@ import (
 	cmdaudit "github.com/klytics/m365kit/cmd/audit"
 	cmdauth "github.com/klytics/m365kit/cmd/auth"
 	"github.com/klytics/m365kit/cmd/batch"
+	cmdcache "github.com/klytics/m365kit/cmd/cache"
+	"github.com/klytics/m365kit/cmd/calendar"
+	"github.com/klytics/m365kit/cmd/contacts"
 	"github.com/klytics/m365kit/cmd/completion"
 	cmdconfig "github.com/klytics/m365kit/cmd/config"
 	cmdconvert "github.com/klytics/m365kit/cmd/convert"
@@ -33,6 +42,7 @@ import (
 	cmdorg "github.com/klytics/m365kit/cmd/org"
 	"github.com/klytics/m365kit/cmd/outlook"
 	"github.com/klytics/m365kit/cmd/pipeline"
+	cmdalias "github.com/klytics/m365kit/cmd/alias"
 	cmdplugin "github.com/klytics/m365kit/cmd/plugin"
 	"github.com/klytics/m365kit/cmd/pptx"
 	"github.com/klytics/m365kit/cmd/report"
@@ -55,12 +65,16 @@ const (
 )
 
 var (
-	jsonOutput bool
-	verbose    bool
-	modelName  string
-	provider   string
-	noColor    bool
-	noProgress bool
+	jsonOutput   bool
+	verbose      bool
+	modelName    string
+	provider     string
+	noColor      bool
+	noProgress   bool
+	noStream     bool
+	redactAI     bool
+	progressJSON bool
+	profile      string
 )
 
 // NewRootCommand creates and returns the root cobra command with all subcommands registered.
@@ -84,10 +98,14 @@ Read, write, analyze, transform, and automate .docx .xlsx .pptx from your termin
 	// Global persistent flags
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output as machine-readable JSON")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Enable debug logging")
-	rootCmd.PersistentFlags().StringVar(&modelName, "model", defaultModel(), "AI model name override")
-	rootCmd.PersistentFlags().StringVar(&provider, "provider", defaultProvider(), "AI provider: anthropic | openai | ollama")
+	rootCmd.PersistentFlags().StringVar(&modelName, "model", defaultModel(), "AI model name, or a capability: default | fast | long-context")
+	rootCmd.PersistentFlags().StringVar(&provider, "provider", defaultProvider(), "AI provider: anthropic | openai | azure-openai | gemini | ollama")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable ANSI color output")
 	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Disable progress bars")
+	rootCmd.PersistentFlags().BoolVar(&noStream, "no-stream", false, "Print AI responses when complete instead of as they arrive")
+	rootCmd.PersistentFlags().BoolVar(&redactAI, "redact", false, "Mask emails, phone numbers, SSNs, card numbers, and secrets in text sent to AI providers")
+	rootCmd.PersistentFlags().BoolVar(&progressJSON, "progress-json", false, "Report transfer progress as JSON lines on stderr")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Microsoft 365 sign-in profile to use (default: $KIT_PROFILE or \"default\")")
 
 	// Register subcommands
 	rootCmd.AddCommand(word.NewCommand())
@@ -98,6 +116,7 @@ Read, write, analyze, transform, and automate .docx .xlsx .pptx from your termin
 	rootCmd.AddCommand(pipeline.NewCommand())
 	rootCmd.AddCommand(batch.NewCommand())
 	rootCmd.AddCommand(cmdconfig.NewCommand())
+	rootCmd.AddCommand(cmdcache.NewCommand())
 	rootCmd.AddCommand(cmdconvert.NewCommand())
 	rootCmd.AddCommand(diff.NewCommand())
 	rootCmd.AddCommand(doctor.NewCommand())
@@ -105,6 +124,8 @@ Read, write, analyze, transform, and automate .docx .xlsx .pptx from your termin
 	rootCmd.AddCommand(send.NewCommand())
 	rootCmd.AddCommand(onedrive.NewCommand())
 	rootCmd.AddCommand(outlook.NewCommand())
+	rootCmd.AddCommand(calendar.NewCommand())
+	rootCmd.AddCommand(contacts.NewCommand())
 	rootCmd.AddCommand(sharepoint.NewCommand())
 	rootCmd.AddCommand(acl.NewCommand())
 	rootCmd.AddCommand(teams.NewCommand())
@@ -123,15 +144,27 @@ Read, write, analyze, transform, and automate .docx .xlsx .pptx from your termin
 	// Platform commands (v1.2)
 	rootCmd.AddCommand(cmdplugin.NewCommand())
 	rootCmd.AddCommand(cmdshell.NewCommand())
+	rootCmd.AddCommand(cmdalias.NewCommand())
 
 	// Wire shell runner: the shell REPL creates a fresh root command per eval
 	shellpkg.DefaultRunner = func(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+		restore, err := shellpkg.Redirect(shellpkg.StdioFrom(ctx), stdout)
+		if err != nil {
+			return err
+		}
+		defer restore()
 		inner := NewRootCommand()
 		inner.SetOut(stdout)
 		inner.SetErr(stderr)
-		inner.SetArgs(args)
-		return inner.ExecuteContext(ctx)
+		inner.SetArgs(applySessionDefaults(args, shellpkg.DefaultsFrom(ctx)))
+		err = inner.ExecuteContext(ctx)
+		if err != nil {
+			reportRedactions(stderr)
+		}
+		return err
 	}
+	shellpkg.DefaultLister = listForCompletion
+	shellpkg.JobCommand = shellJobCommand
 
 	// Audit logging: wrap PersistentPreRun to capture start time
 	origPreRun := rootCmd.PersistentPreRun
@@ -142,10 +175,34 @@ Read, write, analyze, transform, and automate .docx .xlsx .pptx from your termin
 		if noProgress {
 			os.Setenv("KIT_NO_PROGRESS", "1")
 		}
+		if noStream {
+			os.Setenv("KIT_NO_STREAM", "1")
+		}
+		if progressJSON {
+			os.Setenv("KIT_PROGRESS", "json")
+		}
+		if cmd.Flags().Changed("redact") {
+			// --redact=false overrides redact.enabled in the config
+			os.Setenv("KIT_REDACT", strconv.FormatBool(redactAI))
+		}
+		if profile != "" {
+			os.Setenv("KIT_PROFILE", profile)
+		}
+		var scopes []string
+		for c := cmd; c != nil; c = c.Parent() {
+			scopes = append(scopes, authpkg.ParseScopes(c.Annotations[authpkg.ScopesAnnotation])...)
+		}
+		if f := cmd.Flags().Lookup("mailbox"); f != nil && f.Changed {
+			scopes = append(scopes, authpkg.SharedMailboxScopes(scopes)...)
+		}
+		authpkg.SetRequiredScopes(scopes)
+		cmdlog.SetCommand(cmd.CommandPath())
 		cmd.SetContext(context.WithValue(cmd.Context(), auditStartKey, time.Now()))
 	}
 
 	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		reportRedactions(cmd.ErrOrStderr())
+
 		orgCfg, _ := config.LoadOrgConfig()
 		if orgCfg == nil || !orgCfg.Audit.Enabled {
 			return
@@ -170,6 +227,14 @@ type contextKey string
 
 const auditStartKey contextKey = "audit_start"
 
+// reportRedactions tells the user what was masked in text sent to AI
+// providers, with redaction on.
+func reportRedactions(w io.Writer) {
+	if summary := redactpkg.Summary(aipkg.TakeRedactions()); summary != "" {
+		fmt.Fprintf(w, "Redacted before sending to the AI provider: %s\n", summary)
+	}
+}
+
 func hostname() string {
 	h, _ := os.Hostname()
 	parts := strings.SplitN(h, ".", 2)
@@ -179,22 +244,40 @@ func hostname() string {
 // Execute runs the root command and handles any returned errors.
 func Execute() {
 	rootCmd := NewRootCommand()
+	args, err := expandAlias(rootCmd, os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	rootCmd.SetArgs(args)
 	if err := rootCmd.Execute(); err != nil {
+		reportRedactions(os.Stderr)
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		os.Exit(1)
 	}
 }
 
-func defaultModel() string {
-	if m := os.Getenv("KIT_MODEL"); m != "" {
-		return m
+// expandAlias expands args when they start with an alias (see kit alias)
+// rather than a kit command.
+func expandAlias(root *cobra.Command, args []string) ([]string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return args, nil
+	}
+	if c, _, err := root.Find(args[:1]); err == nil && c != root {
+		return args, nil
 	}
-	return "claude-sonnet-4-20250514"
+	aliases, err := aliaspkg.Load(aliaspkg.DefaultPath())
+	if err != nil {
+		return nil, err
+	}
+	return aliases.Expand(args)
+}
+
+func defaultModel() string {
+	// Empty lets the provider pick its default model
+	return os.Getenv("KIT_MODEL")
 }
 
 func defaultProvider() string {
-	if p := os.Getenv("KIT_PROVIDER"); p != "" {
-		return p
-	}
-	return "anthropic"
+	return config.AIProvider()
 }
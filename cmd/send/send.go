@@ -13,10 +13,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/auth"
 	"github.com/klytics/m365kit/internal/email"
 	"github.com/klytics/m365kit/internal/formats/docx"
 	"github.com/klytics/m365kit/internal/formats/pptx"
 	"github.com/klytics/m365kit/internal/formats/xlsx"
+	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/remote"
 )
 
 const aiDraftSystemPrompt = "You are a professional email assistant. Based on the attached document content, write a concise email body (under 150 words). Body only — no greeting, no subject line, no sign-off."
@@ -42,7 +45,9 @@ func NewCommand() *cobra.Command {
 Examples:
   kit send --to cfo@company.com --attach report.xlsx
   kit send --to cfo@company.com --attach report.xlsx --ai-draft
-  kit send --to cfo@company.com --attach report.xlsx --dry-run`,
+  kit send --to cfo@company.com --attach report.xlsx --dry-run
+  kit send --to cfo@company.com --attach onedrive:/Finance/report.xlsx
+  kit send --to "Anna K" --attach report.xlsx   # names are looked up in Microsoft 365`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			providerName, _ := cmd.Flags().GetString("provider")
@@ -55,9 +60,33 @@ Examples:
 				return fmt.Errorf("--attach is required — specify a file to attach")
 			}
 
+			// Remote attachments are fetched to a temp file first
+			if remote.IsRemote(attach) {
+				local, cleanup, err := remote.Open(context.Background(), attach)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+				attach = local
+			}
+
 			// Parse recipients
 			toList := parseEmails(to)
 			ccList := parseEmails(cc)
+			if graph.NeedsLookup(toList) || graph.NeedsLookup(ccList) {
+				ctx := context.Background()
+				auth.RequireScopes("People.Read")
+				client, err := auth.RequireAuth(ctx)
+				if err != nil {
+					return err
+				}
+				if toList, err = graph.ResolveAddresses(ctx, client, toList, os.Stderr); err != nil {
+					return err
+				}
+				if ccList, err = graph.ResolveAddresses(ctx, client, ccList, os.Stderr); err != nil {
+					return err
+				}
+			}
 
 			// Build message
 			msg := email.Message{
@@ -126,11 +155,11 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVar(&to, "to", "", "Comma-separated recipient email addresses (required)")
+	cmd.Flags().StringVar(&to, "to", "", "Comma-separated recipient email addresses or names (required)")
 	cmd.Flags().StringVar(&cc, "cc", "", "Comma-separated CC email addresses")
 	cmd.Flags().StringVar(&subject, "subject", "", "Email subject (default: attachment filename)")
 	cmd.Flags().StringVar(&body, "body", "", "Email body text")
-	cmd.Flags().StringVar(&attach, "attach", "", "Path to file to attach (required)")
+	cmd.Flags().StringVar(&attach, "attach", "", "Path or remote path (onedrive:/..., sp:...) of file to attach (required)")
 	cmd.Flags().BoolVar(&aiDraft, "ai-draft", false, "Use AI to draft the email body from the document content")
 	cmd.Flags().StringVar(&ctxHint, "context", "", "Context hint for AI drafting")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview email without sending")
@@ -0,0 +1,182 @@
+package sharepoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newMetaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "meta",
+		Short: "Read and write metadata columns on library files",
+		Long: `Read and write list item columns (e.g. "Status", "Owner") on files in a
+document library. Columns are addressed by internal name; person and lookup
+columns are set through "<Name>LookupId".`,
+		Example: `  kit sp meta get Marketing /Specs/plan.docx
+  kit sp meta set Marketing /Specs/plan.docx --field Status=Approved
+  kit sp meta set Marketing /Specs/plan.docx --field-json Score=42 --field-json OwnerLookupId=12`,
+	}
+	cmd.AddCommand(newMetaGetCommand())
+	cmd.AddCommand(newMetaSetCommand())
+	return cmd
+}
+
+func newMetaGetCommand() *cobra.Command {
+	var (
+		names []string
+		all   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "get <site> <remote-path>",
+		Short: "Show metadata columns of a library file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
+			fields, err := sp.GetItemFields(ctx, siteID, driveID, args[1])
+			if err != nil {
+				return err
+			}
+
+			if len(names) > 0 {
+				picked := make(map[string]any, len(names))
+				for _, n := range names {
+					v, ok := fields[n]
+					if !ok {
+						return fmt.Errorf("column %q not found on %s — run without --field to list columns", n, args[1])
+					}
+					picked[n] = v
+				}
+				fields = picked
+			} else if !all {
+				fields = graph.CustomFields(fields)
+			}
+			return printFields(cmd, fields)
+		},
+	}
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().StringSliceVar(&names, "field", nil, "Only show these columns (repeatable)")
+	cmd.Flags().BoolVar(&all, "all", false, "Include built-in SharePoint columns")
+	return cmd
+}
+
+func newMetaSetCommand() *cobra.Command {
+	var fieldArgs, jsonArgs []string
+	cmd := &cobra.Command{
+		Use:   "set <site> <remote-path>",
+		Short: "Set metadata columns on a library file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			updates, err := parseFieldArgs(fieldArgs, jsonArgs)
+			if err != nil {
+				return err
+			}
+			if len(updates) == 0 {
+				return fmt.Errorf("--field or --field-json is required")
+			}
+
+			ctx := context.Background()
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
+			fields, err := sp.SetItemFields(ctx, siteID, driveID, args[1], updates)
+			if err != nil {
+				return err
+			}
+
+			changed := make(map[string]any, len(updates))
+			for k := range updates {
+				changed[k] = fields[k]
+			}
+			if jsonFlag, _ := cmd.Flags().GetBool("json"); !jsonFlag {
+				fmt.Printf("Updated %d column(s) on %s\n", len(updates), args[1])
+			}
+			return printFields(cmd, changed)
+		},
+	}
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().StringArrayVar(&fieldArgs, "field", nil, "Column=value to set as text (repeatable)")
+	cmd.Flags().StringArrayVar(&jsonArgs, "field-json", nil, "Column=<JSON value> for numbers, booleans, or lists (repeatable)")
+	return cmd
+}
+
+// parseFieldArgs turns Name=value flags into a fields payload. --field
+// values are strings; --field-json values are decoded as JSON.
+func parseFieldArgs(fieldArgs, jsonArgs []string) (map[string]any, error) {
+	out := make(map[string]any)
+	for _, a := range fieldArgs {
+		name, value, ok := strings.Cut(a, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --field %q — expected Column=value", a)
+		}
+		out[name] = value
+	}
+	for _, a := range jsonArgs {
+		name, raw, ok := strings.Cut(a, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --field-json %q — expected Column=<JSON value>", a)
+		}
+		var v any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("invalid JSON value for %s: %w", name, err)
+		}
+		out[name] = v
+	}
+	return out, nil
+}
+
+// printFields prints columns sorted by name, or the map with --json.
+func printFields(cmd *cobra.Command, fields map[string]any) error {
+	if jsonFlag, _ := cmd.Flags().GetBool("json"); jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(fields)
+	}
+
+	if len(fields) == 0 {
+		fmt.Println("No custom columns set (use --all to include built-in columns)")
+		return nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "COLUMN\tVALUE\n")
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%s\n", k, formatFieldValue(fields[k]))
+	}
+	return w.Flush()
+}
+
+func formatFieldValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
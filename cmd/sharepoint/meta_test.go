@@ -0,0 +1,53 @@
+package sharepoint
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseFieldArgs(t *testing.T) {
+	got, err := parseFieldArgs(
+		[]string{"Status=Approved", "Note=a=b", "Code=007"},
+		[]string{"Score=42", "Reviewed=true", "Tags=[\"x\",\"y\"]"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got["Status"] != "Approved" || got["Note"] != "a=b" || got["Code"] != "007" {
+		t.Errorf("text fields = %v", got)
+	}
+	if got["Score"] != float64(42) || got["Reviewed"] != true {
+		t.Errorf("typed fields = %v", got)
+	}
+	if tags, ok := got["Tags"].([]any); !ok || len(tags) != 2 {
+		t.Errorf("Tags = %#v", got["Tags"])
+	}
+}
+
+func TestParseFieldArgsErrors(t *testing.T) {
+	if _, err := parseFieldArgs([]string{"Status"}, nil); err == nil {
+		t.Error("expected error for missing =")
+	}
+	if _, err := parseFieldArgs(nil, []string{"Score=forty"}); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestFormatFieldValue(t *testing.T) {
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{nil, ""},
+		{"Draft", "Draft"},
+		{json.Number("12.5"), "12.5"},
+		{true, "true"},
+		{[]any{"a", "b"}, `["a","b"]`},
+	}
+	for _, tt := range tests {
+		if got := formatFieldValue(tt.in); got != tt.want {
+			t.Errorf("formatFieldValue(%#v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package sharepoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/formats/convert"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newPageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "page",
+		Short: "Publish SharePoint modern pages",
+	}
+	cmd.AddCommand(newPagePublishCommand())
+	return cmd
+}
+
+func newPagePublishCommand() *cobra.Command {
+	var (
+		title, name      string
+		overwrite, draft bool
+	)
+	cmd := &cobra.Command{
+		Use:   "publish <site> <file.md>",
+		Short: "Publish a Markdown file as a SharePoint page",
+		Long: `Convert a Markdown file into a SharePoint modern page and publish it.
+
+Each level-2 section becomes its own text web part. The title defaults to a
+leading "# Heading" in the file, then to the file name. An existing page with
+the same name is only replaced with --overwrite.`,
+		Example: `  kit sp page publish Marketing notes.md --title "Release Notes"
+  kit sp page publish Engineering report.md --overwrite
+  kit sp page publish Engineering report.md --draft`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				return fmt.Errorf("could not read %s: %w", args[1], err)
+			}
+			heading, sections := convert.MarkdownSections(string(data))
+			if title == "" {
+				title = heading
+			}
+			if title == "" {
+				title = strings.TrimSuffix(filepath.Base(args[1]), filepath.Ext(args[1]))
+			}
+			if name == "" {
+				name = graph.PageName(title)
+			} else if !strings.HasSuffix(strings.ToLower(name), ".aspx") {
+				name += ".aspx"
+			}
+
+			sp, siteID, err := openSite(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			pages := graph.NewPages(sp.Client)
+
+			existing, err := pages.Find(ctx, siteID, name)
+			if err != nil {
+				return err
+			}
+			var page *graph.SitePage
+			switch {
+			case existing != nil && !overwrite:
+				return fmt.Errorf("page %s already exists — rerun with --overwrite to replace it", name)
+			case existing != nil:
+				page, err = pages.Update(ctx, siteID, existing.ID, title, sections)
+			default:
+				page, err = pages.Create(ctx, siteID, name, title, sections)
+			}
+			if err != nil {
+				return err
+			}
+			if page.ID == "" && existing != nil {
+				page.ID = existing.ID
+			}
+			if page.WebURL == "" && existing != nil {
+				page.WebURL = existing.WebURL
+			}
+
+			if !draft {
+				if err := pages.Publish(ctx, siteID, page.ID); err != nil {
+					return err
+				}
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{
+					"id":        page.ID,
+					"name":      name,
+					"title":     title,
+					"webUrl":    page.WebURL,
+					"sections":  len(sections),
+					"published": !draft,
+					"replaced":  existing != nil,
+				})
+			}
+
+			verb := "Published"
+			if draft {
+				verb = "Saved draft"
+			}
+			fmt.Printf("%s %q (%s, %d sections)\n", verb, title, name, len(sections))
+			if page.WebURL != "" {
+				fmt.Printf("  %s\n", page.WebURL)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&title, "title", "", "Page title (default: the file's first heading or name)")
+	cmd.Flags().StringVar(&name, "name", "", "Page file name (default: derived from the title)")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace the content of an existing page with the same name")
+	cmd.Flags().BoolVar(&draft, "draft", false, "Save the page without publishing it")
+	return cmd
+}
@@ -0,0 +1,111 @@
+package sharepoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newScaffoldCommand() *cobra.Command {
+	var (
+		from, to  string
+		toDrive   string
+		folder    string
+		withFiles bool
+		dryRun    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "scaffold --from <site> --to <site>",
+		Short: "Replicate a template site's folder structure into another site",
+		Long: `Copy the folder hierarchy of a template site's library into another site,
+e.g. when a new project site is spun up. With --with-files the template's
+documents are copied too. Folders and files that already exist in the
+destination are left untouched, so scaffold can be re-run safely.`,
+		Example: `  kit sp scaffold --from "Project Template" --to "Project Apollo"
+  kit sp scaffold --from Template --to Apollo --with-files --dry-run
+  kit sp scaffold --from Template --drive Templates --to Apollo --to-drive Documents --folder Onboarding`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+			if from == "" || to == "" {
+				return fmt.Errorf("--from and --to are required")
+			}
+
+			sp, srcSite, srcDrive, err := openLibrary(ctx, cmd, from)
+			if err != nil {
+				return fmt.Errorf("template: %w", err)
+			}
+			dstSite, err := sp.ResolveSite(ctx, to)
+			if err != nil {
+				return fmt.Errorf("destination: %w", err)
+			}
+			dstDrive, err := sp.ResolveLibrary(ctx, dstSite, toDrive)
+			if err != nil {
+				return fmt.Errorf("destination: %w", err)
+			}
+			if srcDrive == dstDrive {
+				return fmt.Errorf("template and destination are the same library")
+			}
+
+			steps, err := sp.Scaffold(ctx, srcSite, srcDrive, dstSite, dstDrive, graph.ScaffoldOptions{
+				Folder:    folder,
+				WithFiles: withFiles,
+				DryRun:    dryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			var failed int
+			for _, s := range steps {
+				if s.Error != "" {
+					failed++
+				}
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(steps); err != nil {
+					return err
+				}
+			} else if len(steps) == 0 {
+				fmt.Println("Template library has nothing to copy")
+			} else {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintf(w, "PATH\tTYPE\tACTION\n")
+				for _, s := range steps {
+					action := s.Action
+					if s.Error != "" {
+						action += ": " + s.Error
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", s.Path, s.Type, action)
+				}
+				w.Flush()
+				if dryRun {
+					fmt.Printf("\nDry run — %d items would be created\n", len(steps))
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d items failed", failed, len(steps))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "Template site (name, URL, or ID)")
+	cmd.Flags().StringVar(&to, "to", "", "Destination site (name, URL, or ID)")
+	cmd.Flags().String("drive", "", "Template library name or ID (default: first library)")
+	cmd.Flags().StringVar(&toDrive, "to-drive", "", "Destination library name or ID (default: first library)")
+	cmd.Flags().StringVar(&folder, "folder", "", "Only replicate this template folder")
+	cmd.Flags().BoolVar(&withFiles, "with-files", false, "Also copy the template's documents")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created without changing anything")
+	return cmd
+}
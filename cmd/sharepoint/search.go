@@ -0,0 +1,175 @@
+package sharepoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/progress"
+)
+
+func newSearchCommand() *cobra.Command {
+	var (
+		types         []string
+		after, before string
+		site          string
+		limit         int
+		downloadDir   string
+	)
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search documents across all SharePoint sites",
+		Long: `Search documents across every SharePoint site and OneDrive you can access,
+using Microsoft Search. Results are ranked by relevance. The query accepts
+KQL, e.g. author:"Jane Doe" or "exact phrase".`,
+		Example: `  kit sp search "quarterly budget" --type xlsx
+  kit sp search contract --modified-after 30d --site Legal
+  kit sp search onboarding --type docx,pdf --download ./hits`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			opts := graph.ContentSearchOptions{FileTypes: types, Limit: limit}
+			now := time.Now()
+			if after != "" {
+				t, err := graph.ParseSince(after, now)
+				if err != nil {
+					return fmt.Errorf("invalid --modified-after: %w", err)
+				}
+				opts.ModifiedAfter = t
+			}
+			if before != "" {
+				t, err := graph.ParseSince(before, now)
+				if err != nil {
+					return fmt.Errorf("invalid --modified-before: %w", err)
+				}
+				opts.ModifiedBefore = t
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			sp := graph.NewSharePoint(client)
+
+			if site != "" {
+				siteID, err := sp.ResolveSite(ctx, site)
+				if err != nil {
+					return err
+				}
+				s, err := sp.GetSite(ctx, siteID)
+				if err != nil {
+					return err
+				}
+				opts.SiteURL = s.WebURL
+			}
+
+			hits, err := sp.SearchContent(ctx, strings.Join(args, " "), opts)
+			if err != nil {
+				return err
+			}
+
+			if downloadDir != "" {
+				return downloadHits(cmd, sp, hits, downloadDir)
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(hits)
+			}
+
+			if len(hits) == 0 {
+				fmt.Println("No documents found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "#\tNAME\tMODIFIED\tBY\tSIZE\tURL\n")
+			for _, h := range hits {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+					h.Rank, h.Name, h.LastModifiedAt.Format("2006-01-02"), h.ModifiedBy, graph.FormatSize(h.Size), h.WebURL)
+			}
+			w.Flush()
+			fmt.Printf("\n%d results\n", len(hits))
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only these file types (e.g. docx,pdf)")
+	cmd.Flags().StringVar(&after, "modified-after", "", "Only documents modified after this date or age (e.g. 30d)")
+	cmd.Flags().StringVar(&before, "modified-before", "", "Only documents modified before this date or age")
+	cmd.Flags().StringVar(&site, "site", "", "Only search this site (name, URL, or ID)")
+	cmd.Flags().IntVar(&limit, "limit", 25, "Maximum number of results")
+	cmd.Flags().StringVar(&downloadDir, "download", "", "Download the results into this directory")
+	cmd.Flags().BoolP("quiet", "q", false, "Suppress progress output with --download")
+	return cmd
+}
+
+// downloadHits saves each hit into dir, numbering duplicate names.
+func downloadHits(cmd *cobra.Command, sp *graph.SharePoint, hits []graph.SearchHit, dir string) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	ctx := context.Background()
+
+	type result struct {
+		graph.SearchHit
+		Local string `json:"local"`
+		Bytes int64  `json:"bytes"`
+		Error string `json:"error,omitempty"`
+	}
+
+	used := make(map[string]int)
+	var results []result
+	var failed int
+	for _, h := range hits {
+		r := result{SearchHit: h, Local: filepath.Join(dir, uniqueName(used, h.Name))}
+		sp.Progress = progress.ForTransfer(h.Name, quiet, jsonFlag)
+		n, err := sp.DownloadHit(ctx, h, r.Local)
+		if err != nil {
+			r.Error = err.Error()
+			failed++
+			if !jsonFlag {
+				fmt.Fprintf(os.Stderr, "  %s: %s\n", h.Name, err)
+			}
+		} else {
+			r.Bytes = n
+			if !jsonFlag && !quiet {
+				fmt.Printf("Downloaded %s → %s (%s)\n", h.Name, r.Local, graph.FormatSize(n))
+			}
+		}
+		results = append(results, r)
+	}
+
+	if jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d results failed to download", failed, len(hits))
+	}
+	return nil
+}
+
+// uniqueName returns name, or "name (2).ext" etc. when already used.
+func uniqueName(used map[string]int, name string) string {
+	used[name]++
+	if used[name] == 1 {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + " (" + strconv.Itoa(used[name]) + ")" + ext
+}
@@ -0,0 +1,14 @@
+package sharepoint
+
+import "testing"
+
+func TestUniqueName(t *testing.T) {
+	used := map[string]int{}
+	got := []string{uniqueName(used, "plan.docx"), uniqueName(used, "plan.docx"), uniqueName(used, "notes"), uniqueName(used, "plan.docx")}
+	want := []string{"plan.docx", "plan (2).docx", "notes", "plan (3).docx"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("uniqueName #%d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -6,23 +6,61 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/klytics/m365kit/internal/auth"
 	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/progress"
 )
 
+// openSite authenticates and resolves a site name, web URL, or ID.
+func openSite(ctx context.Context, siteRef string) (*graph.SharePoint, string, error) {
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	sp := graph.NewSharePoint(client)
+	siteID, err := sp.ResolveSite(ctx, siteRef)
+	if err != nil {
+		return nil, "", err
+	}
+	return sp, siteID, nil
+}
+
+// openLibrary resolves the site and the --drive flag (a library name or
+// drive ID, defaulting to the site's first library).
+func openLibrary(ctx context.Context, cmd *cobra.Command, siteRef string) (*graph.SharePoint, string, string, error) {
+	sp, siteID, err := openSite(ctx, siteRef)
+	if err != nil {
+		return nil, "", "", err
+	}
+	drive, _ := cmd.Flags().GetString("drive")
+	driveID, err := sp.ResolveLibrary(ctx, siteID, drive)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return sp, siteID, driveID, nil
+}
+
 // NewCommand returns the sharepoint command group.
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "sharepoint",
 		Aliases: []string{"sp"},
 		Short:   "Manage SharePoint sites and document libraries",
-		Long:    "List sites, browse document libraries, and manage files on SharePoint.",
+		Long: `List sites, browse document libraries, and manage files on SharePoint.
+
+<site> may be a site name ("Marketing"), web URL, or site ID. Names match
+case-insensitively, exactly or by unique substring. --drive accepts a library
+name ("Documents", "Shared Documents") or drive ID.`,
+		Annotations: map[string]string{auth.ScopesAnnotation: "Sites.ReadWrite.All"},
 	}
 
 	cmd.AddCommand(newSitesCommand())
@@ -31,12 +69,22 @@ func NewCommand() *cobra.Command {
 	cmd.AddCommand(newGetCommand())
 	cmd.AddCommand(newPutCommand())
 	cmd.AddCommand(newAuditCommand())
+	cmd.AddCommand(newCheckoutCommand())
+	cmd.AddCommand(newCheckinCommand())
+	cmd.AddCommand(newDiscardCheckoutCommand())
+	cmd.AddCommand(newVersionsCommand())
+	cmd.AddCommand(newRestoreVersionCommand())
+	cmd.AddCommand(newMetaCommand())
+	cmd.AddCommand(newSearchCommand())
+	cmd.AddCommand(newScaffoldCommand())
+	cmd.AddCommand(newTrashCommand())
+	cmd.AddCommand(newPageCommand())
 
 	return cmd
 }
 
 func newSitesCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "sites [search-query]",
 		Short: "List SharePoint sites",
 		Args:  cobra.MaximumNArgs(1),
@@ -55,6 +103,7 @@ func newSitesCommand() *cobra.Command {
 			}
 
 			sp := graph.NewSharePoint(client)
+			sp.Limit, _ = cmd.Flags().GetInt("limit")
 			sites, err := sp.ListSites(ctx, query)
 			if err != nil {
 				return err
@@ -83,24 +132,25 @@ func newSitesCommand() *cobra.Command {
 			return w.Flush()
 		},
 	}
+	cmd.Flags().Int("limit", 0, "Maximum number of sites to return (0 = all)")
+	return cmd
 }
 
 func newLibsCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "libs <site-id>",
+		Use:   "libs <site>",
 		Short: "List document libraries for a SharePoint site",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			ctx := context.Background()
 
-			client, err := auth.RequireAuth(ctx)
+			sp, siteID, err := openSite(ctx, args[0])
 			if err != nil {
 				return err
 			}
 
-			sp := graph.NewSharePoint(client)
-			libs, err := sp.ListLibraries(ctx, args[0])
+			libs, err := sp.ListLibraries(ctx, siteID)
 			if err != nil {
 				return err
 			}
@@ -127,38 +177,28 @@ func newLibsCommand() *cobra.Command {
 }
 
 func newLsCommand() *cobra.Command {
-	var driveID string
+	var recursive, labels bool
 	cmd := &cobra.Command{
-		Use:   "ls <site-id> [path]",
+		Use:   "ls <site> [path]",
 		Short: "List files in a SharePoint document library",
 		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			ctx := context.Background()
 
-			client, err := auth.RequireAuth(ctx)
-			if err != nil {
-				return err
-			}
-
-			siteID := args[0]
 			folderPath := "/"
 			if len(args) > 1 {
 				folderPath = args[1]
 			}
 
-			sp := graph.NewSharePoint(client)
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
+			sp.Limit, _ = cmd.Flags().GetInt("limit")
 
-			// If no drive ID specified, use the first library
-			if driveID == "" {
-				libs, err := sp.ListLibraries(ctx, siteID)
-				if err != nil {
-					return fmt.Errorf("could not list libraries: %w", err)
-				}
-				if len(libs) == 0 {
-					return fmt.Errorf("no document libraries found on this site")
-				}
-				driveID = libs[0].ID
+			if recursive {
+				return listRecursive(ctx, sp, siteID, driveID, folderPath, labels, jsonFlag)
 			}
 
 			items, err := sp.ListLibraryFiles(ctx, siteID, driveID, folderPath)
@@ -166,9 +206,27 @@ func newLsCommand() *cobra.Command {
 				return err
 			}
 
+			var itemLabels []*graph.ItemLabels
+			if labels {
+				if itemLabels, err = readLabels(ctx, sp, driveID, items); err != nil {
+					return err
+				}
+			}
+
 			if jsonFlag {
 				enc := json.NewEncoder(os.Stdout)
 				enc.SetIndent("", "  ")
+				if labels {
+					type labeledItem struct {
+						graph.DriveItem
+						Labels *graph.ItemLabels `json:"labels"`
+					}
+					out := make([]labeledItem, len(items))
+					for i, item := range items {
+						out[i] = labeledItem{DriveItem: item, Labels: itemLabels[i]}
+					}
+					return enc.Encode(out)
+				}
 				return enc.Encode(items)
 			}
 
@@ -178,8 +236,8 @@ func newLsCommand() *cobra.Command {
 			}
 
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(w, "TYPE\tNAME\tSIZE\tMODIFIED\n")
-			for _, item := range items {
+			fmt.Fprintf(w, "TYPE\tNAME\tSIZE\tMODIFIED%s\n", labelHeader(labels))
+			for i, item := range items {
 				itemType := "file"
 				if item.IsFolder {
 					itemType = "dir"
@@ -193,49 +251,156 @@ func newLsCommand() *cobra.Command {
 				if item.IsFolder {
 					name = color.New(color.FgBlue, color.Bold).Sprint(name + "/")
 				}
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", itemType, name, size, modified)
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s%s\n", itemType, name, size, modified, labelColumns(itemLabels, i))
 			}
 			return w.Flush()
 		},
 	}
-	cmd.Flags().StringVar(&driveID, "drive", "", "Document library (drive) ID (default: first library)")
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().Int("limit", 0, "Maximum number of items to return (0 = all)")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "List subfolders recursively")
+	cmd.Flags().BoolVar(&labels, "labels", false, "Show sensitivity and retention labels")
 	return cmd
 }
 
+// listRecursive prints every item under folderPath with its relative path.
+func listRecursive(ctx context.Context, sp *graph.SharePoint, siteID, driveID, folderPath string, labels, jsonFlag bool) error {
+	entries, err := sp.WalkLibrary(ctx, siteID, driveID, folderPath)
+	if err != nil {
+		return err
+	}
+
+	var itemLabels []*graph.ItemLabels
+	if labels {
+		items := make([]graph.DriveItem, len(entries))
+		for i, e := range entries {
+			items[i] = e.DriveItem
+		}
+		if itemLabels, err = readLabels(ctx, sp, driveID, items); err != nil {
+			return err
+		}
+	}
+
+	if jsonFlag {
+		type treeItem struct {
+			graph.LibraryEntry
+			IsFolder bool              `json:"isFolder"`
+			Labels   *graph.ItemLabels `json:"labels,omitempty"`
+		}
+		out := make([]treeItem, 0, len(entries))
+		for i, e := range entries {
+			item := treeItem{LibraryEntry: e, IsFolder: e.IsFolder}
+			if labels {
+				item.Labels = itemLabels[i]
+			}
+			out = append(out, item)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("(empty)")
+		return nil
+	}
+
+	var files int
+	var total int64
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "TYPE\tPATH\tSIZE\tMODIFIED%s\n", labelHeader(labels))
+	for i, e := range entries {
+		itemType, size, name := "file", graph.FormatSize(e.Size), e.Path
+		if e.IsFolder {
+			itemType, size = "dir", ""
+			name = color.New(color.FgBlue, color.Bold).Sprint(name + "/")
+		} else {
+			files++
+			total += e.Size
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s%s\n", itemType, name, size, e.LastModifiedAt.Format("2006-01-02 15:04"), labelColumns(itemLabels, i))
+	}
+	w.Flush()
+	fmt.Printf("\n%d files, %d folders, %s\n", files, len(entries)-files, graph.FormatSize(total))
+	return nil
+}
+
+// readLabels returns the labels of each item, in order.
+func readLabels(ctx context.Context, sp *graph.SharePoint, driveID string, items []graph.DriveItem) ([]*graph.ItemLabels, error) {
+	reader := graph.NewLabelReader(sp.Client)
+	out := make([]*graph.ItemLabels, len(items))
+	for i, item := range items {
+		l, err := reader.ItemLabels(ctx, driveID, item)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = l
+	}
+	return out, nil
+}
+
+func labelHeader(labels bool) string {
+	if !labels {
+		return ""
+	}
+	return "\tSENSITIVITY\tRETENTION"
+}
+
+// labelColumns returns the label cells for row i, or "" without --labels.
+func labelColumns(labels []*graph.ItemLabels, i int) string {
+	if labels == nil {
+		return ""
+	}
+	sensitivity, retention := labels[i].Sensitivity, labels[i].Retention
+	if sensitivity == "" {
+		sensitivity = "-"
+	}
+	if retention == "" {
+		retention = "-"
+	}
+	return "\t" + sensitivity + "\t" + retention
+}
+
 func newGetCommand() *cobra.Command {
-	var driveID, outputPath string
+	var (
+		outputPath string
+		recursive  bool
+		workers    int
+	)
 	cmd := &cobra.Command{
-		Use:   "get <site-id> <remote-path>",
-		Short: "Download a file from a SharePoint library",
-		Args:  cobra.ExactArgs(2),
+		Use:   "get <site> <remote-path>",
+		Short: "Download a file (or a folder with --recursive) from a SharePoint library",
+		Long: `Download a file from a SharePoint document library.
+
+With --recursive, <remote-path> is a folder: every file below it is
+downloaded into the --output directory (default: the folder's name),
+recreating the subfolder structure. Files are fetched in parallel.`,
+		Example: `  kit sp get Marketing /Reports/Q3.xlsx
+  kit sp get --recursive Marketing /Reports -o reports/ --drive Documents`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
+			quiet, _ := cmd.Flags().GetBool("quiet")
 			ctx := context.Background()
 
-			client, err := auth.RequireAuth(ctx)
-			if err != nil {
-				return err
-			}
-
-			siteID := args[0]
 			remotePath := args[1]
 			if outputPath == "" {
 				outputPath = filepath.Base(remotePath)
+				if recursive && strings.Trim(remotePath, "/") == "" {
+					outputPath = "."
+				}
 			}
 
-			sp := graph.NewSharePoint(client)
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
 
-			if driveID == "" {
-				libs, err := sp.ListLibraries(ctx, siteID)
-				if err != nil {
-					return err
-				}
-				if len(libs) == 0 {
-					return fmt.Errorf("no document libraries found")
-				}
-				driveID = libs[0].ID
+			if recursive {
+				return downloadFolder(cmd, sp, siteID, driveID, remotePath, outputPath, workers)
 			}
 
+			sp.Progress = progress.ForTransfer(filepath.Base(remotePath), quiet, jsonFlag)
 			n, err := sp.DownloadFromLibrary(ctx, siteID, driveID, remotePath, outputPath)
 			if err != nil {
 				return err
@@ -252,49 +417,119 @@ func newGetCommand() *cobra.Command {
 				})
 			}
 
+			if quiet {
+				return nil
+			}
 			fmt.Printf("Downloaded %s → %s (%s)\n", remotePath, outputPath, graph.FormatSize(n))
 			return nil
 		},
 	}
-	cmd.Flags().StringVar(&driveID, "drive", "", "Document library (drive) ID")
-	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Local output path")
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Local output path (a directory with --recursive)")
+	cmd.Flags().BoolP("quiet", "q", false, "Suppress progress and summary output")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "Download a folder and everything below it")
+	cmd.Flags().IntVar(&workers, "workers", 4, "Parallel downloads with --recursive")
 	return cmd
 }
 
+// downloadFolder mirrors a library folder into localDir.
+func downloadFolder(cmd *cobra.Command, sp *graph.SharePoint, siteID, driveID, remotePath, localDir string, workers int) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	label := path.Base(strings.Trim(remotePath, "/"))
+	if label == "." || label == "" {
+		label = "library"
+	}
+	sp.Progress = progress.ForTransfer(label+"/", quiet, jsonFlag)
+	results, err := sp.DownloadLibraryFolder(context.Background(), siteID, driveID, remotePath, localDir, workers)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	var total int64
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			if !jsonFlag {
+				fmt.Fprintf(os.Stderr, "  %s: %s\n", r.Path, r.Error)
+			}
+			continue
+		}
+		total += r.Bytes
+	}
+
+	if jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{
+			"site":   siteID,
+			"remote": remotePath,
+			"local":  localDir,
+			"files":  results,
+			"bytes":  total,
+			"failed": failed,
+		}); err != nil {
+			return err
+		}
+	} else if !quiet {
+		fmt.Printf("Downloaded %d files from %s → %s (%s)\n", len(results)-failed, remotePath, localDir, graph.FormatSize(total))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed to download — rerun to resume", failed, len(results))
+	}
+	return nil
+}
+
 func newPutCommand() *cobra.Command {
-	var driveID, remotePath string
+	var (
+		remotePath string
+		recursive  bool
+		workers    int
+	)
 	cmd := &cobra.Command{
-		Use:   "put <site-id> <local-file>",
-		Short: "Upload a file to a SharePoint library",
-		Args:  cobra.ExactArgs(2),
+		Use:   "put <site> <local-path>",
+		Short: "Upload a file (or a folder with --recursive) to a SharePoint library",
+		Long: `Upload a file to a SharePoint document library. Files over 4MB are
+sent in fragments through an upload session.
+
+With --recursive, <local-path> is a folder: every file below it is uploaded
+under --remote (default: the folder's name), recreating the subfolder
+structure. Files are sent in parallel and existing files are replaced.`,
+		Example: `  kit sp put Marketing report.docx -r /Reports/report.docx
+  kit sp put --recursive https://contoso.sharepoint.com/sites/marketing ./migration -r /Archive/2024`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
+			quiet, _ := cmd.Flags().GetBool("quiet")
 			ctx := context.Background()
 
-			client, err := auth.RequireAuth(ctx)
+			localPath := args[1]
+
+			info, err := os.Stat(localPath)
 			if err != nil {
-				return err
+				return fmt.Errorf("could not open local path: %w", err)
+			}
+			if info.IsDir() && !recursive {
+				return fmt.Errorf("%s is a directory — use --recursive to upload a folder", localPath)
 			}
 
-			siteID := args[0]
-			localPath := args[1]
 			if remotePath == "" {
 				remotePath = filepath.Base(localPath)
 			}
 
-			sp := graph.NewSharePoint(client)
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
 
-			if driveID == "" {
-				libs, err := sp.ListLibraries(ctx, siteID)
-				if err != nil {
-					return err
-				}
-				if len(libs) == 0 {
-					return fmt.Errorf("no document libraries found")
-				}
-				driveID = libs[0].ID
+			if info.IsDir() {
+				return uploadFolder(cmd, sp, siteID, driveID, localPath, remotePath, workers)
 			}
 
+			sp.Progress = progress.ForTransfer(filepath.Base(localPath), quiet, jsonFlag)
 			item, err := sp.UploadToLibrary(ctx, siteID, driveID, remotePath, localPath)
 			if err != nil {
 				return err
@@ -312,6 +547,9 @@ func newPutCommand() *cobra.Command {
 				})
 			}
 
+			if quiet {
+				return nil
+			}
 			fmt.Printf("Uploaded %s → %s\n", localPath, remotePath)
 			if item.WebURL != "" {
 				fmt.Printf("Web: %s\n", item.WebURL)
@@ -319,50 +557,140 @@ func newPutCommand() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().StringVar(&driveID, "drive", "", "Document library (drive) ID")
-	cmd.Flags().StringVarP(&remotePath, "remote", "r", "", "Remote path")
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().StringVarP(&remotePath, "remote", "r", "", "Remote path (a folder with --recursive)")
+	cmd.Flags().BoolP("quiet", "q", false, "Suppress progress and summary output")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "Upload a folder and everything below it")
+	cmd.Flags().IntVar(&workers, "workers", 4, "Parallel uploads with --recursive")
 	return cmd
 }
 
+// uploadFolder uploads localDir into remoteDir and reports per-file results.
+func uploadFolder(cmd *cobra.Command, sp *graph.SharePoint, siteID, driveID, localDir, remoteDir string, workers int) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	sp.Progress = progress.ForTransfer(filepath.Base(localDir)+"/", quiet, jsonFlag)
+	results, err := sp.UploadFolderToLibrary(context.Background(), siteID, driveID, localDir, remoteDir, workers)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	var total int64
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			if !jsonFlag {
+				fmt.Fprintf(os.Stderr, "  %s: %s\n", r.Local, r.Error)
+			}
+			continue
+		}
+		total += r.Bytes
+	}
+
+	if jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{
+			"site":   siteID,
+			"local":  localDir,
+			"remote": remoteDir,
+			"files":  results,
+			"bytes":  total,
+			"failed": failed,
+		}); err != nil {
+			return err
+		}
+	} else if !quiet {
+		fmt.Printf("Uploaded %d files from %s → %s (%s)\n", len(results)-failed, localDir, remoteDir, graph.FormatSize(total))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed to upload — rerun to retry", failed, len(results))
+	}
+	return nil
+}
+
 func newAuditCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "audit <site-id>",
+	var since string
+	cmd := &cobra.Command{
+		Use:   "audit <site>",
 		Short: "Show recent activity on a SharePoint site",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show recent activity across every document library on a site.
+
+Entries are printed as they are fetched, newest first within each library,
+so large sites can be audited without holding the whole log in memory.`,
+		Example: `  kit sp audit Marketing --since 7d
+  kit sp audit Legal --since 2024-06-01 --json > activity.json`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			ctx := context.Background()
 
-			client, err := auth.RequireAuth(ctx)
-			if err != nil {
-				return err
+			var cutoff time.Time
+			if since != "" {
+				t, err := graph.ParseSince(since, time.Now())
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+				cutoff = t
 			}
 
-			sp := graph.NewSharePoint(client)
-			entries, err := sp.AuditSite(ctx, args[0])
+			sp, siteID, err := openSite(ctx, args[0])
 			if err != nil {
 				return err
 			}
+			sp.Limit, _ = cmd.Flags().GetInt("limit")
 
 			if jsonFlag {
-				enc := json.NewEncoder(os.Stdout)
-				enc.SetIndent("", "  ")
-				return enc.Encode(entries)
+				return streamAuditJSON(ctx, sp, siteID, cutoff)
 			}
 
-			if len(entries) == 0 {
-				fmt.Println("No recent activity")
+			count := 0
+			err = sp.AuditSite(ctx, siteID, cutoff, func(e graph.AuditEntry) error {
+				if count == 0 {
+					fmt.Printf("%-16s  %-10s  %-20s  %-20s  %s\n", "TIME", "ACTION", "USER", "LIBRARY", "PATH")
+				}
+				count++
+				fmt.Printf("%-16s  %-10s  %-20s  %-20s  %s\n",
+					e.OccurredAt.Local().Format("2006-01-02 15:04"), e.Action, e.Actor, e.Library, e.ItemPath)
 				return nil
+			})
+			if err != nil {
+				return err
 			}
-
-			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(w, "TIME\tACTION\tUSER\tFILE\n")
-			for _, e := range entries {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-					e.OccurredAt.Format("2006-01-02 15:04"),
-					e.Action, e.Actor, e.ItemName)
+			if count == 0 {
+				fmt.Println("No recent activity")
 			}
-			return w.Flush()
+			return nil
 		},
 	}
+	cmd.Flags().StringVar(&since, "since", "", "Only activity after this date or age (e.g. 7d)")
+	cmd.Flags().Int("limit", 0, "Maximum number of activity entries to return (0 = all)")
+	return cmd
+}
+
+// streamAuditJSON writes audit entries as a JSON array, one entry at a time.
+func streamAuditJSON(ctx context.Context, sp *graph.SharePoint, siteID string, since time.Time) error {
+	fmt.Print("[")
+	first := true
+	err := sp.AuditSite(ctx, siteID, since, func(e graph.AuditEntry) error {
+		data, err := json.MarshalIndent(e, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		if !first {
+			fmt.Print(",")
+		}
+		first = false
+		fmt.Printf("\n  %s", data)
+		return nil
+	})
+	if first {
+		fmt.Println("]")
+	} else {
+		fmt.Println("\n]")
+	}
+	return err
 }
@@ -0,0 +1,111 @@
+package sharepoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newTrashCommand() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "trash <site>",
+		Short: "List or restore items in a site's recycle bin",
+		Long: `List the items in a SharePoint site's recycle bin, or restore them with
+'kit sp trash restore'. Items are identified by ID or by name; a name that
+matches several deleted items must be given as an ID instead.`,
+		Example: `  kit sp trash Marketing
+  kit sp trash restore Marketing "Q3 Plan.docx"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			sp, siteID, err := openSite(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			sp.Limit = limit
+			items, err := sp.ListSiteTrash(ctx, siteID)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				if items == nil {
+					items = []graph.TrashItem{}
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(items)
+			}
+
+			if len(items) == 0 {
+				fmt.Println("Recycle bin is empty")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "NAME\tSIZE\tDELETED\tBY\tFROM\tID\n")
+			for _, item := range items {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					item.Name, graph.FormatSize(item.Size), item.DeletedAt.Local().Format("2006-01-02 15:04"), item.DeletedBy, item.DeletedFrom, item.ID)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of items to return (0 = all)")
+	cmd.AddCommand(newTrashRestoreCommand())
+	return cmd
+}
+
+func newTrashRestoreCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <site> <item>...",
+		Short: "Restore deleted items to their original location",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			sp, siteID, err := openSite(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			items, err := sp.ListSiteTrash(ctx, siteID)
+			if err != nil {
+				return err
+			}
+
+			selected, err := graph.ResolveTrash(items, args[1:], fmt.Sprintf("kit sp trash %q", args[0]))
+			if err != nil {
+				return err
+			}
+
+			ids := make([]string, 0, len(selected))
+			for _, item := range selected {
+				ids = append(ids, item.ID)
+			}
+			if err := sp.RestoreSiteTrash(ctx, siteID, ids); err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"restored": selected})
+			}
+
+			for _, item := range selected {
+				fmt.Printf("Restored %s → %s\n", item.Name, item.DeletedFrom)
+			}
+			return nil
+		},
+	}
+}
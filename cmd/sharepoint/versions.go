@@ -0,0 +1,174 @@
+package sharepoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// printAction prints a confirmation line, or a JSON object with --json.
+func printAction(cmd *cobra.Command, msg string, fields map[string]any) error {
+	if jsonFlag, _ := cmd.Flags().GetBool("json"); jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(fields)
+	}
+	fmt.Println(msg)
+	return nil
+}
+
+func newCheckoutCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "checkout <site> <remote-path>",
+		Short: "Check out a library file for editing",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
+			if err := sp.Checkout(ctx, siteID, driveID, args[1]); err != nil {
+				return err
+			}
+			return printAction(cmd, fmt.Sprintf("Checked out %s", args[1]), map[string]any{
+				"site": siteID, "path": args[1], "checkedOut": true,
+			})
+		},
+	}
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	return cmd
+}
+
+func newCheckinCommand() *cobra.Command {
+	var (
+		comment string
+		publish bool
+	)
+	cmd := &cobra.Command{
+		Use:   "checkin <site> <remote-path>",
+		Short: "Check in a checked-out library file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
+			if err := sp.Checkin(ctx, siteID, driveID, args[1], comment, publish); err != nil {
+				return err
+			}
+			msg := fmt.Sprintf("Checked in %s", args[1])
+			if publish {
+				msg += " (published)"
+			}
+			return printAction(cmd, msg, map[string]any{
+				"site": siteID, "path": args[1], "checkedOut": false, "comment": comment, "published": publish,
+			})
+		},
+	}
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().StringVarP(&comment, "message", "m", "", "Check-in comment")
+	cmd.Flags().BoolVar(&publish, "publish", false, "Check in as a published major version")
+	return cmd
+}
+
+func newDiscardCheckoutCommand() *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "discard-checkout <site> <remote-path>",
+		Short: "Release a checkout and discard its changes",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return fmt.Errorf("discarding a checkout loses changes made since check-out — rerun with --yes to confirm")
+			}
+			ctx := context.Background()
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
+			if err := sp.DiscardCheckout(ctx, siteID, driveID, args[1]); err != nil {
+				return err
+			}
+			return printAction(cmd, fmt.Sprintf("Discarded checkout of %s", args[1]), map[string]any{
+				"site": siteID, "path": args[1], "checkedOut": false, "discarded": true,
+			})
+		},
+	}
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm discarding changes")
+	return cmd
+}
+
+func newVersionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "versions <site> <remote-path>",
+		Short: "List versions of a library file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
+			sp.Limit, _ = cmd.Flags().GetInt("limit")
+			versions, err := sp.ListVersions(ctx, siteID, driveID, args[1])
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(versions)
+			}
+
+			if len(versions) == 0 {
+				fmt.Println("No versions found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "VERSION\tMODIFIED\tBY\tSIZE\n")
+			for _, v := range versions {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.ID, v.LastModifiedAt.Format("2006-01-02 15:04"), v.ModifiedBy, graph.FormatSize(v.Size))
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	cmd.Flags().Int("limit", 0, "Maximum number of versions to return (0 = all)")
+	return cmd
+}
+
+func newRestoreVersionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore-version <site> <remote-path> <version-id>",
+		Short: "Make a previous version the current version of a library file",
+		Long:  "Restore a previous version of a file. The current content is kept in the version history.",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			sp, siteID, driveID, err := openLibrary(ctx, cmd, args[0])
+			if err != nil {
+				return err
+			}
+			if err := sp.RestoreVersion(ctx, siteID, driveID, args[1], args[2]); err != nil {
+				return err
+			}
+			return printAction(cmd, fmt.Sprintf("Restored %s to version %s", args[1], args[2]), map[string]any{
+				"site": siteID, "path": args[1], "restored": args[2],
+			})
+		},
+	}
+	cmd.Flags().String("drive", "", "Document library name or ID (default: first library)")
+	return cmd
+}
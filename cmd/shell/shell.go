@@ -2,7 +2,10 @@
 package shell
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -14,6 +17,8 @@ func NewCommand() *cobra.Command {
 	var (
 		evalCmd string
 		siteURL string
+		team    string
+		folder  string
 	)
 
 	cmd := &cobra.Command{
@@ -22,15 +27,42 @@ func NewCommand() *cobra.Command {
 		Long: `Start an interactive REPL with persistent state and tab completion.
 
 Commands run without re-paying startup cost. Auth tokens persist across
-commands in the session. Tab completion works for all commands and flags.`,
+commands in the session. Tab completion works for all commands and flags.
+
+Commands can be piped into each other with |; each one reads the
+previous one's output as stdin:
+
+  kit> word read contract.docx | ai summarize | teams post --team Legal --channel general --stdin
+
+and a line's output written to a file with > (or appended with >>);
+"last --save <file>" saves the previous command's output. Output longer
+than the screen goes through $PAGER (less by default; PAGER=cat turns
+paging off):
+
+  kit> outlook inbox --json > inbox.json
+
+A line ending in & runs in the background, so the prompt comes back at
+once; "jobs" lists background jobs, "wait [n]" waits for them and shows
+their output, and the shell says when one finishes:
+
+  kit> onedrive get /Archive/2023.zip &`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			session, err := shellpkg.NewSession()
 			if err != nil {
 				return err
 			}
-			if siteURL != "" {
+			if cmd.Flags().Changed("sharepoint") {
 				session.DefaultSite = siteURL
 			}
+			if cmd.Flags().Changed("team") {
+				session.DefaultTeam = team
+			}
+			if cmd.Flags().Changed("folder") {
+				session.Folder = shellpkg.JoinFolder("", folder)
+				if session.Folder == "/" {
+					session.Folder = ""
+				}
+			}
 			if evalCmd != "" {
 				output, err := session.Eval(cmd.Context(), evalCmd)
 				if err != nil {
@@ -45,5 +77,81 @@ commands in the session. Tab completion works for all commands and flags.`,
 
 	cmd.Flags().StringVar(&evalCmd, "eval", "", "Run a single command and exit")
 	cmd.Flags().StringVar(&siteURL, "sharepoint", "", "Default SharePoint site URL")
+	cmd.Flags().StringVar(&team, "team", "", "Default team (instead of the saved one)")
+	cmd.Flags().StringVar(&folder, "folder", "", "Starting OneDrive folder (instead of the saved one)")
+	cmd.AddCommand(newRunCmd())
+	return cmd
+}
+
+func newRunCmd() *cobra.Command {
+	var (
+		failFast bool
+		vars     []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run <script.kit>",
+		Short: "Run a file of shell commands non-interactively",
+		Long: `Run a .kit script: shell lines run in order, as if typed at the kit>
+prompt, with pipes, variables, and use/cd. Scripts start from the saved
+variables and session context but don't change them.
+
+Lines starting with # are comments, and a line ending in \ continues on
+the next. "if <command>" runs the lines up to "else" or "end" when the
+command succeeds ("if ! <command>" when it fails); $? is 0 or 1 for the
+last line; "exit [status]" stops the script.
+
+A failing line is reported with its line number and the script carries
+on, exiting with an error at the end; --fail-fast stops at the first
+failure instead.
+
+To run a script directly, start it with:
+  #!/usr/bin/env -S kit shell run --fail-fast
+
+Example:
+  # publish.kit
+  use team Docs
+  if ! auth whoami
+    echo Not signed in; run kit auth login
+    exit 2
+  end
+  word read report.docx | ai summarize | teams post --channel general --stdin
+  echo Posted the $month summary
+
+  kit shell run publish.kit --var month=2026-10`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("could not open script: %w", err)
+			}
+			defer f.Close()
+
+			session, err := shellpkg.NewSession()
+			if err != nil {
+				return err
+			}
+			session.VarsFile, session.ContextFile = "", ""
+			for _, v := range vars {
+				name, value, ok := strings.Cut(v, "=")
+				if !ok {
+					return fmt.Errorf("--var %q: use name=value", v)
+				}
+				if err := session.SetVar(name, value); err != nil {
+					return err
+				}
+			}
+
+			err = session.RunScript(cmd.Context(), f, args[0], shellpkg.ScriptOptions{FailFast: failFast})
+			var exit *shellpkg.ExitError
+			if errors.As(err, &exit) {
+				os.Exit(exit.Status)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop at the first line that fails")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Set a script variable as name=value (repeatable)")
 	return cmd
 }
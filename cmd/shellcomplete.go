@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+	shellpkg "github.com/klytics/m365kit/internal/shell"
+	"github.com/klytics/m365kit/internal/template"
+)
+
+// listForCompletion lists the values the shell completes: team, channel,
+// and site names, template names, and the items in a OneDrive folder.
+// Everything but templates needs a signed-in profile; without one there is
+// nothing to complete.
+func listForCompletion(ctx context.Context, kind, arg string) ([]string, error) {
+	if kind == shellpkg.ListTemplates {
+		lib, err := template.LoadLibrary(template.DefaultLibraryDir())
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, t := range lib.List() {
+			names = append(names, t.Name)
+		}
+		return names, nil
+	}
+
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	switch kind {
+	case shellpkg.ListTeams:
+		teams, err := graph.NewTeams(client).ListTeams(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range teams {
+			names = append(names, t.DisplayName)
+		}
+	case shellpkg.ListChannels:
+		tc := graph.NewTeams(client)
+		teamID, err := tc.ResolveTeamID(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		channels, err := tc.ListChannels(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range channels {
+			names = append(names, c.DisplayName)
+		}
+	case shellpkg.ListSites:
+		sites, err := graph.NewSharePoint(client).ListSites(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sites {
+			names = append(names, s.DisplayName)
+		}
+	case shellpkg.ListOneDrive:
+		items, err := graph.NewOneDrive(client).ListFolder(ctx, strings.TrimPrefix(arg, "/"))
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.IsFolder {
+				names = append(names, item.Name+"/")
+			} else {
+				names = append(names, item.Name)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("nothing to list for %q", kind)
+	}
+	return names, nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	shellpkg "github.com/klytics/m365kit/internal/shell"
+)
+
+// applySessionDefaults fills the shell session's defaults into args: the
+// default team and site for --team and --site flags that were left out,
+// the site as the first argument of commands whose usage starts with
+// <site> when it is missing, and the current OneDrive folder for relative
+// OneDrive paths.
+func applySessionDefaults(args []string, d shellpkg.Defaults) []string {
+	if d == (shellpkg.Defaults{}) {
+		return args
+	}
+	probe := NewRootCommand()
+	c, rest, err := probe.Find(args)
+	if err != nil || c == probe {
+		return args
+	}
+	if err := c.ParseFlags(rest); err != nil {
+		return args // the real run reports it
+	}
+	positional := c.Flags().Args()
+	placeholders := usageArgs(c)
+	rest = append([]string(nil), rest...)
+
+	var extra []string
+	for _, f := range []struct{ name, value string }{{"team", d.Team}, {"site", d.Site}} {
+		if fl := c.Flags().Lookup(f.name); fl != nil && f.value != "" && !fl.Changed {
+			extra = append(extra, "--"+f.name+"="+f.value)
+		}
+	}
+
+	first := ""
+	if len(placeholders) > 0 {
+		first = placeholders[0]
+	}
+	required := 0
+	for _, p := range placeholders {
+		if strings.HasPrefix(p, "<") {
+			required++
+		}
+	}
+	if first == "<site>" && d.Site != "" && len(positional) < required {
+		rest = append([]string{d.Site}, rest...)
+	}
+
+	if d.Folder != "" && strings.HasPrefix(c.CommandPath(), probe.Name()+" onedrive ") {
+		switch {
+		case first != "<remote-path>" && first != "[path]":
+		case len(positional) == 0 && first == "[path]":
+			rest = append([]string{d.Folder}, rest...)
+		case len(positional) > 0:
+			for i, a := range rest {
+				if a == positional[0] {
+					rest[i] = shellpkg.JoinFolder(d.Folder, a)
+					break
+				}
+			}
+		}
+		if fl := c.Flags().Lookup("remote"); fl != nil && !fl.Changed && len(positional) > 0 {
+			extra = append(extra, "--remote="+shellpkg.JoinFolder(d.Folder, path.Base(strings.ReplaceAll(positional[0], `\`, "/"))))
+		}
+	}
+
+	out := strings.Fields(c.CommandPath())[1:]
+	out = append(out, extra...)
+	return append(out, rest...)
+}
+
+// usageArgs returns the argument placeholders in c's usage line, like
+// <site> and [path].
+func usageArgs(c *cobra.Command) []string {
+	var out []string
+	for _, f := range strings.Fields(c.Use)[1:] {
+		if strings.HasPrefix(f, "<") || strings.HasPrefix(f, "[") {
+			out = append(out, f)
+		}
+	}
+	return out
+}
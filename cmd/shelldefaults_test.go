@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	shellpkg "github.com/klytics/m365kit/internal/shell"
+)
+
+func TestApplySessionDefaults(t *testing.T) {
+	d := shellpkg.Defaults{Site: "Marketing", Team: "Engineering", Folder: "/Projects"}
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"teams", "channels"}, []string{"teams", "channels", "--team=Engineering"}},
+		{[]string{"teams", "channels", "--team", "Sales"}, []string{"teams", "channels", "--team", "Sales"}},
+		{[]string{"sharepoint", "ls"}, []string{"sharepoint", "ls", "Marketing"}},
+		{[]string{"sharepoint", "get", "Docs/a.docx"}, []string{"sharepoint", "get", "Marketing", "Docs/a.docx"}},
+		{[]string{"sharepoint", "get", "Legal", "Docs/a.docx"}, []string{"sharepoint", "get", "Legal", "Docs/a.docx"}},
+		{[]string{"onedrive", "ls", "--json"}, []string{"onedrive", "ls", "/Projects", "--json"}},
+		{[]string{"onedrive", "get", "plan.docx"}, []string{"onedrive", "get", "/Projects/plan.docx"}},
+		{[]string{"onedrive", "get", "/Other/plan.docx"}, []string{"onedrive", "get", "/Other/plan.docx"}},
+		{[]string{"onedrive", "put", "local/plan.docx"}, []string{"onedrive", "put", "--remote=/Projects/plan.docx", "local/plan.docx"}},
+		{[]string{"version"}, []string{"version"}},
+	}
+	for _, tt := range tests {
+		if got := applySessionDefaults(tt.args, d); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	if got := applySessionDefaults([]string{"teams", "channels"}, shellpkg.Defaults{}); !reflect.DeepEqual(got, []string{"teams", "channels"}) {
+		t.Errorf("without defaults: %q", got)
+	}
+}
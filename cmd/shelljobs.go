@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+
+	shellpkg "github.com/klytics/m365kit/internal/shell"
+)
+
+// shellJobCommand runs a shell line in the background as its own
+// "kit shell --eval" process, with the session's defaults.
+func shellJobCommand(line string, d shellpkg.Defaults) *exec.Cmd {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = os.Args[0]
+	}
+	return exec.Command(exe, "shell", "--eval", line,
+		"--sharepoint", d.Site, "--team", d.Team, "--folder", d.Folder)
+}
@@ -0,0 +1,433 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// adminPermissions is appended to the help of commands that need
+// administrator-consented Graph permissions.
+const adminPermissions = `
+Requires Graph permissions that usually need admin consent for the kit
+app registration: Team.Create, TeamSettings.ReadWrite.All,
+TeamMember.ReadWrite.All, Channel.Create, Channel.Delete.All, and
+ChannelMember.ReadWrite.All.`
+
+// splitEmails flattens repeatable, comma-separated email flags.
+func splitEmails(values []string) []string {
+	var emails []string
+	for _, v := range values {
+		for _, e := range strings.Split(v, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				emails = append(emails, e)
+			}
+		}
+	}
+	return emails
+}
+
+func newCreateTeamCommand() *cobra.Command {
+	var (
+		name        string
+		description string
+		visibility  string
+		owners      []string
+		members     []string
+		channels    []string
+	)
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a team",
+		Long: `Create a team, with its Microsoft 365 group and SharePoint site, and
+add owners, members, and standard channels. You become an owner.
+` + adminPermissions,
+		Example: `  kit teams create --name "Project Falcon" --owner alice@contoso.com --member bob@contoso.com,carol@contoso.com \
+    --channel Design --channel Releases`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+
+			if !jsonFlag {
+				fmt.Printf("Creating team %q (this can take a minute)...\n", name)
+			}
+			team, err := tc.CreateTeam(ctx, graph.TeamRequest{Name: name, Description: description, Visibility: visibility})
+			if err != nil {
+				return err
+			}
+
+			var added []*graph.TeamMember
+			for _, e := range splitEmails(owners) {
+				m, err := tc.AddMember(ctx, team.ID, e, true)
+				if err != nil {
+					return err
+				}
+				added = append(added, m)
+			}
+			for _, e := range splitEmails(members) {
+				m, err := tc.AddMember(ctx, team.ID, e, false)
+				if err != nil {
+					return err
+				}
+				added = append(added, m)
+			}
+			var created []*graph.Channel
+			for _, c := range channels {
+				ch, err := tc.CreateChannel(ctx, team.ID, graph.ChannelRequest{Name: c})
+				if err != nil {
+					return err
+				}
+				created = append(created, ch)
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"team": team, "members": added, "channels": created})
+			}
+			fmt.Printf("Team created: %s\n", team.DisplayName)
+			fmt.Printf("ID:  %s\n", team.ID)
+			if len(added) > 0 {
+				fmt.Printf("Added %d member(s)\n", len(added))
+			}
+			for _, ch := range created {
+				fmt.Printf("Channel created: #%s\n", ch.DisplayName)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "Team name (required)")
+	cmd.Flags().StringVar(&description, "description", "", "Team description")
+	cmd.Flags().StringVar(&visibility, "visibility", "private", "private or public")
+	cmd.Flags().StringArrayVar(&owners, "owner", nil, "Owner email (repeatable or comma-separated)")
+	cmd.Flags().StringArrayVar(&members, "member", nil, "Member email (repeatable or comma-separated)")
+	cmd.Flags().StringArrayVar(&channels, "channel", nil, "Standard channel to create (repeatable)")
+	return cmd
+}
+
+func newArchiveCommand() *cobra.Command {
+	var (
+		teamName  string
+		unarchive bool
+	)
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Archive (or unarchive) a team",
+		Long:  "Archive a team, making it read-only, or restore it with --unarchive.\n" + adminPermissions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			teamID, err := tc.ResolveTeamID(ctx, teamName)
+			if err != nil {
+				return err
+			}
+			if err := tc.ArchiveTeam(ctx, teamID, unarchive); err != nil {
+				return err
+			}
+			if unarchive {
+				fmt.Printf("Team %s unarchived\n", teamName)
+			} else {
+				fmt.Printf("Team %s archived\n", teamName)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().BoolVar(&unarchive, "unarchive", false, "Restore an archived team")
+	return cmd
+}
+
+func newMembersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "members",
+		Short: "List, add, and remove team members",
+	}
+	cmd.AddCommand(newMembersListCommand())
+	cmd.AddCommand(newMembersAddCommand())
+	cmd.AddCommand(newMembersRemoveCommand())
+	return cmd
+}
+
+func newMembersListCommand() *cobra.Command {
+	var teamName string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the members of a team",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			teamID, err := tc.ResolveTeamID(ctx, teamName)
+			if err != nil {
+				return err
+			}
+			members, err := tc.ListMembers(ctx, teamID)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(members)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "NAME\tEMAIL\tROLE\n")
+			for _, m := range members {
+				role := "member"
+				if m.IsOwner() {
+					role = "owner"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", m.DisplayName, m.Email, role)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	return cmd
+}
+
+func newMembersAddCommand() *cobra.Command {
+	var (
+		teamName string
+		users    []string
+		owner    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add members or owners to a team",
+		Long:  "Add members or owners to a team.\n" + adminPermissions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			emails := splitEmails(users)
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+			if len(emails) == 0 {
+				return fmt.Errorf("--user is required")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			teamID, err := tc.ResolveTeamID(ctx, teamName)
+			if err != nil {
+				return err
+			}
+			role := "member"
+			if owner {
+				role = "owner"
+			}
+			for _, e := range emails {
+				if _, err := tc.AddMember(ctx, teamID, e, owner); err != nil {
+					return err
+				}
+				fmt.Printf("Added %s as %s\n", e, role)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().StringArrayVar(&users, "user", nil, "User email (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&owner, "owner", false, "Add as owners")
+	return cmd
+}
+
+func newMembersRemoveCommand() *cobra.Command {
+	var (
+		teamName string
+		users    []string
+	)
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove members from a team",
+		Long:  "Remove members from a team.\n" + adminPermissions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			emails := splitEmails(users)
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+			if len(emails) == 0 {
+				return fmt.Errorf("--user is required")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			teamID, err := tc.ResolveTeamID(ctx, teamName)
+			if err != nil {
+				return err
+			}
+			for _, e := range emails {
+				if err := tc.RemoveMember(ctx, teamID, e); err != nil {
+					return err
+				}
+				fmt.Printf("Removed %s\n", e)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().StringArrayVar(&users, "user", nil, "User email (repeatable or comma-separated)")
+	return cmd
+}
+
+func newChannelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "channel",
+		Short: "Create and delete channels",
+	}
+	cmd.AddCommand(newChannelCreateCommand())
+	cmd.AddCommand(newChannelDeleteCommand())
+	return cmd
+}
+
+func newChannelCreateCommand() *cobra.Command {
+	var (
+		teamName    string
+		name        string
+		description string
+		private     bool
+		owners      []string
+		members     []string
+	)
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a standard or private channel",
+		Long: `Create a channel in a team. Private channels are visible only to the
+--owner and --member users you add (and you).
+` + adminPermissions,
+		Example: `  kit teams channel create --team "Project Falcon" --name Releases
+  kit teams channel create --team "Project Falcon" --name Leads --private --owner alice@contoso.com --member bob@contoso.com`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			teamID, err := tc.ResolveTeamID(ctx, teamName)
+			if err != nil {
+				return err
+			}
+			ch, err := tc.CreateChannel(ctx, teamID, graph.ChannelRequest{
+				Name:        name,
+				Description: description,
+				Private:     private,
+				Owners:      splitEmails(owners),
+				Members:     splitEmails(members),
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(ch)
+			}
+			kind := "Channel"
+			if private {
+				kind = "Private channel"
+			}
+			fmt.Printf("%s created: #%s\n", kind, ch.DisplayName)
+			fmt.Printf("ID:  %s\n", ch.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().StringVar(&name, "name", "", "Channel name (required)")
+	cmd.Flags().StringVar(&description, "description", "", "Channel description")
+	cmd.Flags().BoolVar(&private, "private", false, "Create a private channel")
+	cmd.Flags().StringArrayVar(&owners, "owner", nil, "Private channel owner email (repeatable or comma-separated)")
+	cmd.Flags().StringArrayVar(&members, "member", nil, "Private channel member email (repeatable or comma-separated)")
+	return cmd
+}
+
+func newChannelDeleteCommand() *cobra.Command {
+	var (
+		teamName    string
+		channelName string
+		confirm     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a channel",
+		Long: `Delete a channel. Deleted channels can be restored from the Teams admin
+center for 30 days. Requires --confirm.
+` + adminPermissions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+			if channelName == "" {
+				return fmt.Errorf("--channel is required")
+			}
+			if !confirm {
+				return fmt.Errorf("deleting #%s removes its conversations for everyone — rerun with --confirm", strings.TrimPrefix(channelName, "#"))
+			}
+
+			tc, teamID, channelID, err := resolveChannel(ctx, teamName, channelName)
+			if err != nil {
+				return err
+			}
+			if err := tc.DeleteChannel(ctx, teamID, channelID); err != nil {
+				return err
+			}
+			fmt.Printf("Channel #%s deleted\n", strings.TrimPrefix(channelName, "#"))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().StringVar(&channelName, "channel", "", "Channel name or ID (required)")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Confirm the deletion")
+	return cmd
+}
@@ -0,0 +1,187 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newBulkCommand() *cobra.Command {
+	var (
+		interval time.Duration
+		retries  int
+		parallel int
+		dryRun   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "bulk <file|->",
+		Short: "Post many channel messages with throttling and retry",
+		Long: `Post a batch of channel messages from a file (or - for stdin). Posts to
+the same channel are sent in order and spaced --interval apart; different
+channels are posted to in parallel. Requests Graph throttles (HTTP 429)
+are retried after the delay Graph asks for, so large fan-outs don't lose
+messages. A post that fails with 503 or 504 is reported, not retried: Graph
+may have posted it anyway.
+
+The input is JSON Lines or a JSON array of {"team", "channel", "message"}
+objects ("card" may hold an Adaptive Card instead of a message), or CSV with
+a team,channel,message header.`,
+		Example: `  kit teams bulk notifications.jsonl
+  kit batch './reports/*.docx' --action summarize --json | jq -c '.[] | {team:"Eng", channel:"reports", message:("Summarized " + .file)}' | kit teams bulk -
+  kit teams bulk alerts.csv --interval 2s --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("could not read posts: %w", err)
+			}
+			posts, err := parseBulkPosts(data)
+			if err != nil {
+				return err
+			}
+			if len(posts) == 0 {
+				return fmt.Errorf("no posts found in %s", args[0])
+			}
+
+			if dryRun {
+				if jsonFlag {
+					enc := json.NewEncoder(os.Stdout)
+					enc.SetIndent("", "  ")
+					return enc.Encode(map[string]any{"dryRun": true, "posts": posts})
+				}
+				fmt.Printf("--- %d Teams posts ---\n", len(posts))
+				for i, p := range posts {
+					text := p.Message
+					if len(p.Card) > 0 {
+						text = "[Adaptive Card]"
+					}
+					fmt.Printf("%3d  %s #%s: %s\n", i+1, p.Team, p.Channel, text)
+				}
+				fmt.Println("--- Would post via Microsoft Graph API ---")
+				return nil
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			q := &graph.PostQueue{Client: client, Interval: interval, MaxRetries: retries, Parallel: parallel}
+			var report func(graph.PostResult)
+			if !jsonFlag {
+				report = func(r graph.PostResult) {
+					switch {
+					case r.Status == "failed":
+						fmt.Fprintf(os.Stderr, "  ✗ %d %s #%s: %s\n", r.Index+1, r.Team, r.Channel, r.Error)
+					case r.Attempts > 1:
+						fmt.Printf("  ✓ %d %s #%s (after %d attempts)\n", r.Index+1, r.Team, r.Channel, r.Attempts)
+					default:
+						fmt.Printf("  ✓ %d %s #%s\n", r.Index+1, r.Team, r.Channel)
+					}
+				}
+			}
+			results := q.Run(ctx, posts, report)
+
+			failed := 0
+			for _, r := range results {
+				if r.Status == "failed" {
+					failed++
+				}
+			}
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(results); err != nil {
+					return err
+				}
+			} else {
+				fmt.Printf("\n%d posted, %d failed\n", len(results)-failed, failed)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d posts failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&interval, "interval", time.Second, "Minimum gap between posts to the same channel")
+	cmd.Flags().IntVar(&retries, "retries", 5, "Retries per post when Graph throttles")
+	cmd.Flags().IntVar(&parallel, "parallel", 4, "Channels to post to at once")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview without posting")
+	return cmd
+}
+
+// parseBulkPosts reads posts as a JSON array, JSON Lines, or CSV with a
+// team,channel,message header.
+func parseBulkPosts(data []byte) ([]graph.QueuedPost, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	var posts []graph.QueuedPost
+	switch trimmed[0] {
+	case '[':
+		if err := json.Unmarshal(trimmed, &posts); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		for dec.More() {
+			var p graph.QueuedPost
+			if err := dec.Decode(&p); err != nil {
+				return nil, fmt.Errorf("invalid JSON on post %d: %w", len(posts)+1, err)
+			}
+			posts = append(posts, p)
+		}
+	default:
+		records, err := csv.NewReader(bytes.NewReader(trimmed)).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		col := map[string]int{}
+		for i, h := range records[0] {
+			col[strings.ToLower(strings.TrimSpace(h))] = i
+		}
+		for _, name := range []string{"team", "channel", "message"} {
+			if _, ok := col[name]; !ok {
+				return nil, fmt.Errorf("CSV header must include team, channel, and message columns")
+			}
+		}
+		for _, rec := range records[1:] {
+			posts = append(posts, graph.QueuedPost{
+				Team:    rec[col["team"]],
+				Channel: rec[col["channel"]],
+				Message: rec[col["message"]],
+			})
+		}
+	}
+
+	for i, p := range posts {
+		if p.Team == "" || p.Channel == "" {
+			return nil, fmt.Errorf("post %d: team and channel are required", i+1)
+		}
+		if p.Message == "" && len(p.Card) == 0 {
+			return nil, fmt.Errorf("post %d: message or card is required", i+1)
+		}
+	}
+	return posts, nil
+}
@@ -0,0 +1,74 @@
+package teams
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// cardFlags holds the post flags that produce an Adaptive Card.
+type cardFlags struct {
+	file     string
+	template string
+	title    string
+	status   string
+	facts    []string
+	buttons  []string
+}
+
+func (c *cardFlags) add(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&c.file, "card", "", "Post an Adaptive Card from a JSON file")
+	cmd.Flags().StringVar(&c.template, "card-template", "", "Build a card from a template: "+strings.Join(graph.CardTemplates(), ", "))
+	cmd.Flags().StringVar(&c.title, "title", "", "Card title (with --card-template)")
+	cmd.Flags().StringVar(&c.status, "status", "info", "Card status: success, warning, failure, or info (status template)")
+	cmd.Flags().StringArrayVar(&c.facts, "fact", nil, "Card fact as Name=Value (repeatable)")
+	cmd.Flags().StringArrayVar(&c.buttons, "button", nil, "Card button as Label=URL (repeatable)")
+}
+
+func (c *cardFlags) set() bool { return c.file != "" || c.template != "" }
+
+// build returns the card, or nil when no card flags were given. With a
+// template, text becomes the card's body text.
+func (c *cardFlags) build(text string) (json.RawMessage, error) {
+	switch {
+	case c.file != "" && c.template != "":
+		return nil, fmt.Errorf("use either --card or --card-template, not both")
+	case c.file != "":
+		data, err := os.ReadFile(c.file)
+		if err != nil {
+			return nil, fmt.Errorf("could not read card: %w", err)
+		}
+		return graph.ParseCard(data)
+	case c.template != "":
+		spec := graph.CardSpec{Title: c.title, Text: text, Status: c.status}
+		for _, f := range c.facts {
+			name, value, err := splitPair(f, "--fact", "Name=Value")
+			if err != nil {
+				return nil, err
+			}
+			spec.Facts = append(spec.Facts, graph.CardFact{Title: name, Value: value})
+		}
+		for _, b := range c.buttons {
+			label, url, err := splitPair(b, "--button", "Label=URL")
+			if err != nil {
+				return nil, err
+			}
+			spec.Buttons = append(spec.Buttons, graph.CardButton{Title: label, URL: url})
+		}
+		return graph.BuildCard(c.template, spec)
+	}
+	return nil, nil
+}
+
+func splitPair(s, flag, form string) (string, string, error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return "", "", fmt.Errorf("invalid %s %q — use %s", flag, s, form)
+	}
+	return strings.TrimSpace(name), strings.TrimSpace(value), nil
+}
@@ -0,0 +1,199 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newChatCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chat",
+		Short: "List, create, and message 1:1 and group chats",
+	}
+	cmd.AddCommand(newChatListCommand())
+	cmd.AddCommand(newChatSendCommand())
+	cmd.AddCommand(newChatCreateCommand())
+	return cmd
+}
+
+func newChatListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your chats",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			tc.Limit, _ = cmd.Flags().GetInt("limit")
+			chats, err := tc.ListChats(ctx)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(chats)
+			}
+			if len(chats) == 0 {
+				fmt.Println("No chats found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "CHAT\tTYPE\tMEMBERS\tID\n")
+			for _, c := range chats {
+				name := c.Name()
+				if len(name) > 50 {
+					name = name[:47] + "..."
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", name, c.ChatType, len(c.Members), c.ID)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().Int("limit", 50, "Maximum number of chats to return (0 = all)")
+	return cmd
+}
+
+func newChatSendCommand() *cobra.Command {
+	var (
+		chatRef  string
+		message  string
+		useStdin bool
+		dryRun   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send a message to an existing chat",
+		Long: `Send a message to an existing chat. --chat is a chat ID, a chat topic
+(exact or partial), or the participants as comma-separated emails.`,
+		Example: `  kit teams chat send --chat "Release war room" -m "Rollout at 50%"
+  kit teams chat send --chat alice@contoso.com,bob@contoso.com -m "Quick sync?"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if chatRef == "" {
+				return fmt.Errorf("--chat is required")
+			}
+			if useStdin {
+				message = readStdin()
+			}
+			if message == "" {
+				return fmt.Errorf("--message or --stdin is required")
+			}
+
+			if dryRun {
+				if jsonFlag {
+					return json.NewEncoder(os.Stdout).Encode(map[string]any{"dryRun": true, "chat": chatRef, "message": message})
+				}
+				fmt.Println("--- Teams Chat Preview ---")
+				fmt.Printf("Chat:     %s\n", chatRef)
+				fmt.Printf("Message:  %s\n", message)
+				fmt.Println("--- Would send via Microsoft Graph API ---")
+				return nil
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			chat, err := tc.ResolveChat(ctx, chatRef)
+			if err != nil {
+				return err
+			}
+			msg, err := tc.SendChatMessage(ctx, chat.ID, message)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(msg)
+			}
+			name := chat.Name()
+			if name == "" {
+				name = chat.ID
+			}
+			fmt.Printf("Message sent to %s\n", name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&chatRef, "chat", "", "Chat ID, topic, or participant emails (required)")
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Message text")
+	cmd.Flags().BoolVar(&useStdin, "stdin", false, "Read message from stdin")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview without sending")
+	return cmd
+}
+
+func newChatCreateCommand() *cobra.Command {
+	var (
+		topic   string
+		members []string
+		message string
+	)
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Start a group chat",
+		Example: `  kit teams chat create --topic "Release war room" --member alice@contoso.com --member bob@contoso.com
+  kit teams chat create --topic "Incident 42" --member alice@contoso.com,bob@contoso.com -m "Bridge is open"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			var emails []string
+			for _, m := range members {
+				for _, e := range strings.Split(m, ",") {
+					if e = strings.TrimSpace(e); e != "" {
+						emails = append(emails, e)
+					}
+				}
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			chat, err := tc.CreateGroupChat(ctx, topic, emails)
+			if err != nil {
+				return err
+			}
+			if message != "" {
+				if _, err := tc.SendChatMessage(ctx, chat.ID, message); err != nil {
+					return fmt.Errorf("chat created (%s) but the message failed: %w", chat.ID, err)
+				}
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(chat)
+			}
+			fmt.Printf("Group chat created with %d members\n", len(emails)+1)
+			fmt.Printf("ID: %s\n", chat.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&topic, "topic", "", "Chat topic")
+	cmd.Flags().StringArrayVar(&members, "member", nil, "Member email (repeatable or comma-separated)")
+	cmd.Flags().StringVarP(&message, "message", "m", "", "First message to send")
+	return cmd
+}
@@ -0,0 +1,125 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newExportCommand() *cobra.Command {
+	var (
+		teamName    string
+		channelName string
+		outDir      string
+		since       string
+		until       string
+		noHosted    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a channel's messages for archiving",
+		Long: `Export the messages of a channel, with their reply threads, to a folder:
+
+  messages.json   every message and reply as returned by Graph
+  transcript.md   a readable Markdown transcript, grouped by day
+  hosted/         inline images and other hosted content
+
+--since and --until select threads by when they were started. Files
+shared in the channel live in its SharePoint folder and are linked, not
+copied. Reading channel messages requires the ChannelMessage.Read.All
+permission, which needs admin consent.`,
+		Example: `  kit teams export --team Engineering --channel general -o archive/general
+  kit teams export --team Legal --channel contracts --since 2026-01-01 --until 2026-07-01 -o q1-q2/`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+			if channelName == "" {
+				return fmt.Errorf("--channel is required")
+			}
+			if outDir == "" {
+				return fmt.Errorf("--output is required")
+			}
+			opts := graph.ExportOptions{Dir: outDir}
+			var err error
+			if since != "" {
+				if opts.Since, err = time.ParseInLocation("2006-01-02", since, time.Local); err != nil {
+					return fmt.Errorf("invalid --since date: %w (use YYYY-MM-DD)", err)
+				}
+			}
+			if until != "" {
+				if opts.Until, err = time.ParseInLocation("2006-01-02", until, time.Local); err != nil {
+					return fmt.Errorf("invalid --until date: %w (use YYYY-MM-DD)", err)
+				}
+			}
+			if noHosted {
+				opts.Dir = ""
+			}
+			if err := os.MkdirAll(outDir, 0755); err != nil {
+				return err
+			}
+
+			tc, teamID, channelID, err := resolveChannel(ctx, teamName, channelName)
+			if err != nil {
+				return err
+			}
+			messages, err := tc.ExportChannel(ctx, teamID, channelID, opts)
+			if err != nil {
+				return err
+			}
+			if messages == nil {
+				messages = []graph.ChannelMessage{}
+			}
+
+			data, _ := json.MarshalIndent(messages, "", "  ")
+			if err := os.WriteFile(filepath.Join(outDir, "messages.json"), data, 0644); err != nil {
+				return err
+			}
+			title := fmt.Sprintf("#%s — %s", strings.TrimPrefix(channelName, "#"), teamName)
+			if err := os.WriteFile(filepath.Join(outDir, "transcript.md"), []byte(graph.ChannelTranscript(title, messages)), 0644); err != nil {
+				return err
+			}
+
+			replies, hosted := 0, 0
+			for _, m := range messages {
+				replies += len(m.Replies)
+				hosted += len(m.HostedFiles)
+				for _, r := range m.Replies {
+					hosted += len(r.HostedFiles)
+				}
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{
+					"output":  outDir,
+					"threads": len(messages),
+					"replies": replies,
+					"hosted":  hosted,
+				})
+			}
+			fmt.Printf("Exported #%s to %s\n", strings.TrimPrefix(channelName, "#"), outDir)
+			fmt.Printf("  %d threads, %d replies, %d hosted files\n", len(messages), replies, hosted)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().StringVar(&channelName, "channel", "", "Channel name or ID (required)")
+	cmd.Flags().StringVarP(&outDir, "output", "o", "", "Output folder (required)")
+	cmd.Flags().StringVar(&since, "since", "", "Only threads started on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&until, "until", "", "Only threads started before this date (YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&noHosted, "no-hosted", false, "Skip downloading inline images and hosted content")
+	return cmd
+}
@@ -0,0 +1,178 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newMeetingCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "meeting",
+		Short:       "Create Teams meetings",
+		Annotations: map[string]string{auth.ScopesAnnotation: "Calendars.ReadWrite"},
+	}
+	cmd.AddCommand(newMeetingCreateCommand())
+	return cmd
+}
+
+func newMeetingCreateCommand() *cobra.Command {
+	var (
+		subject     string
+		start       string
+		duration    time.Duration
+		attendees   []string
+		agenda      string
+		teamName    string
+		channelName string
+		dryRun      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a Teams meeting and print its join link",
+		Long: `Create a Teams meeting on your calendar, invite the attendees, and
+print the join link. With --team and --channel the link is also posted
+to that channel.
+
+--start accepts "now", an offset like "+15m", a time today like "14:30",
+or a date and time like "2026-10-20 09:00".`,
+		Example: `  kit teams meeting create --subject "Quick sync" --start now --attendees alice@contoso.com
+  kit teams meeting create --subject "Review" --start "2026-10-20 09:00" --duration 45m \
+    --attendees alice@contoso.com,bob@contoso.com --team Engineering --channel general`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if subject == "" {
+				return fmt.Errorf("--subject is required")
+			}
+			if (teamName == "") != (channelName == "") {
+				return fmt.Errorf("--team and --channel must be used together")
+			}
+			startAt, err := parseMeetingStart(start, time.Now())
+			if err != nil {
+				return err
+			}
+			var emails []string
+			for _, a := range attendees {
+				if a = strings.TrimSpace(a); a != "" {
+					emails = append(emails, a)
+				}
+			}
+
+			if dryRun {
+				if jsonFlag {
+					enc := json.NewEncoder(os.Stdout)
+					enc.SetIndent("", "  ")
+					return enc.Encode(map[string]any{
+						"dryRun":    true,
+						"subject":   subject,
+						"start":     startAt,
+						"end":       startAt.Add(duration),
+						"attendees": emails,
+						"team":      teamName,
+						"channel":   channelName,
+					})
+				}
+				fmt.Println("--- Teams Meeting Preview ---")
+				fmt.Printf("Subject:    %s\n", subject)
+				fmt.Printf("When:       %s – %s\n", startAt.Format("Mon 2006-01-02 15:04"), startAt.Add(duration).Format("15:04"))
+				if len(emails) > 0 {
+					fmt.Printf("Attendees:  %s\n", strings.Join(emails, ", "))
+				}
+				if channelName != "" {
+					fmt.Printf("Post to:    #%s in %s\n", channelName, teamName)
+				}
+				fmt.Println("--- Would create via Microsoft Graph API ---")
+				return nil
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			meeting, err := tc.CreateMeeting(ctx, graph.MeetingRequest{
+				Subject:   subject,
+				Start:     startAt,
+				Duration:  duration,
+				Attendees: emails,
+				Body:      agenda,
+			})
+			if err != nil {
+				return err
+			}
+
+			if channelName != "" {
+				teamID, err := tc.ResolveTeamID(ctx, teamName)
+				if err != nil {
+					return err
+				}
+				channelID, err := tc.ResolveChannelID(ctx, teamID, channelName)
+				if err != nil {
+					return err
+				}
+				text := fmt.Sprintf("%s — %s\nJoin: %s", meeting.Subject, meeting.Start.Local().Format("Mon 2006-01-02 15:04"), meeting.JoinURL)
+				if _, err := tc.PostMessage(ctx, teamID, channelID, text); err != nil {
+					return fmt.Errorf("meeting created but posting the link failed: %w", err)
+				}
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(meeting)
+			}
+
+			fmt.Printf("Meeting created: %s\n", meeting.Subject)
+			fmt.Printf("When:  %s – %s\n", meeting.Start.Local().Format("Mon 2006-01-02 15:04"), meeting.End.Local().Format("15:04"))
+			if len(emails) > 0 {
+				fmt.Printf("Invited: %s\n", strings.Join(emails, ", "))
+			}
+			if channelName != "" {
+				fmt.Printf("Link posted to #%s\n", channelName)
+			}
+			fmt.Printf("Join:  %s\n", meeting.JoinURL)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&subject, "subject", "", "Meeting subject (required)")
+	cmd.Flags().StringVar(&start, "start", "now", `Start time: "now", "+15m", "14:30", or "2026-10-20 09:00"`)
+	cmd.Flags().DurationVar(&duration, "duration", 30*time.Minute, "Meeting length")
+	cmd.Flags().StringSliceVar(&attendees, "attendees", nil, "Attendee emails (comma-separated or repeatable)")
+	cmd.Flags().StringVar(&agenda, "agenda", "", "Agenda text for the invitation")
+	cmd.Flags().StringVar(&teamName, "team", "", "Also post the join link to this team's --channel")
+	cmd.Flags().StringVar(&channelName, "channel", "", "Channel to post the join link to")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview without creating")
+	return cmd
+}
+
+// parseMeetingStart reads --start relative to now.
+func parseMeetingStart(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "" || strings.EqualFold(s, "now"):
+		return now.Truncate(time.Minute), nil
+	case strings.HasPrefix(s, "+"):
+		d, err := time.ParseDuration(s[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --start offset %q — use e.g. +15m or +1h", s)
+		}
+		return now.Add(d).Truncate(time.Minute), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if clock, err := time.Parse("15:04", s); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --start %q — use now, +15m, 14:30, or \"2026-10-20 09:00\"", s)
+}
@@ -0,0 +1,173 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func newPresenceCommand() *cobra.Command {
+	var (
+		useStdin  bool
+		available bool
+	)
+	cmd := &cobra.Command{
+		Use:   "presence <email> [email...]",
+		Short: "Show whether users are available, busy, or away",
+		Long: `Show the Teams presence of one or more users. Emails may be given as
+arguments, comma-separated, or one per line with --stdin.
+
+With --available, only the emails of users who are online and available
+are printed, one per line, so scripts can route messages to whoever is
+around.`,
+		Example: `  kit teams presence alice@contoso.com
+  kit teams presence alice@contoso.com,bob@contoso.com,carol@contoso.com
+  kit teams dm --to "$(kit teams presence --available --stdin < oncall.txt | head -1)" --message "Can you take a look?"`,
+		Annotations: map[string]string{auth.ScopesAnnotation: "Presence.Read.All User.ReadBasic.All"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if useStdin {
+				args = append(args, strings.Fields(readStdin())...)
+			}
+			emails := splitEmails(args)
+			if len(emails) == 0 {
+				return fmt.Errorf("at least one email is required")
+			}
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			presence, err := graph.NewTeams(client).GetPresence(ctx, emails)
+			if err != nil {
+				return err
+			}
+
+			if available {
+				var online []graph.UserPresence
+				for _, p := range presence {
+					if p.IsAvailable() {
+						online = append(online, p)
+					}
+				}
+				presence = online
+			}
+
+			if jsonFlag {
+				if presence == nil {
+					presence = []graph.UserPresence{}
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(presence)
+			}
+			if available {
+				for _, p := range presence {
+					fmt.Println(p.Email)
+				}
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "NAME\tEMAIL\tAVAILABILITY\tACTIVITY\n")
+			for _, p := range presence {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.DisplayName, p.Email, p.Availability, p.Activity)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().BoolVar(&useStdin, "stdin", false, "Read emails from stdin")
+	cmd.Flags().BoolVar(&available, "available", false, "Print only the emails of available users")
+	return cmd
+}
+
+func newUsersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "users",
+		Short:       "Look up people in your organization",
+		Annotations: map[string]string{auth.ScopesAnnotation: "User.ReadBasic.All"},
+	}
+	cmd.AddCommand(newUsersSearchCommand())
+	return cmd
+}
+
+func newUsersSearchCommand() *cobra.Command {
+	var withPresence bool
+	cmd := &cobra.Command{
+		Use:   "search <name>",
+		Short: "Find users by name or email",
+		Long:  "Find users whose display name, first or last name, or email starts with <name>.",
+		Example: `  kit teams users search alice
+  kit teams users search "Alice Sm" --presence`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if withPresence {
+				auth.RequireScopes("Presence.Read.All")
+			}
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			tc := graph.NewTeams(client)
+			tc.Limit, _ = cmd.Flags().GetInt("limit")
+			users, err := tc.SearchUsers(ctx, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+
+			var presence []graph.UserPresence
+			if withPresence && len(users) > 0 {
+				emails := make([]string, len(users))
+				for i, u := range users {
+					emails[i] = u.Email
+				}
+				if presence, err = tc.GetPresence(ctx, emails); err != nil {
+					return err
+				}
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if presence != nil {
+					return enc.Encode(presence)
+				}
+				return enc.Encode(users)
+			}
+
+			if len(users) == 0 {
+				fmt.Println("No users found")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			if presence != nil {
+				fmt.Fprintf(w, "NAME\tEMAIL\tAVAILABILITY\n")
+				for _, p := range presence {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", p.DisplayName, p.Email, p.Availability)
+				}
+			} else {
+				fmt.Fprintf(w, "NAME\tEMAIL\n")
+				for _, u := range users {
+					fmt.Fprintf(w, "%s\t%s\n", u.DisplayName, u.Email)
+				}
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().BoolVar(&withPresence, "presence", false, "Include each user's availability")
+	cmd.Flags().Int("limit", 25, "Maximum number of users to return (0 = all)")
+	return cmd
+}
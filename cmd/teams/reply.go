@@ -0,0 +1,191 @@
+package teams
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// readStdin returns everything on standard input, without the trailing
+// newline.
+func readStdin() string {
+	scanner := bufio.NewScanner(os.Stdin)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resolveChannel signs in and resolves a team and channel by name or ID.
+func resolveChannel(ctx context.Context, teamName, channelName string) (*graph.Teams, string, string, error) {
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, "", "", err
+	}
+	tc := graph.NewTeams(client)
+	teamID, err := tc.ResolveTeamID(ctx, teamName)
+	if err != nil {
+		return nil, "", "", err
+	}
+	channelID, err := tc.ResolveChannelID(ctx, teamID, channelName)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return tc, teamID, channelID, nil
+}
+
+func newReplyCommand() *cobra.Command {
+	var (
+		teamName    string
+		channelName string
+		messageID   string
+		message     string
+		useStdin    bool
+		dryRun      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "reply",
+		Short: "Reply in the thread of a channel message",
+		Example: `  kit teams reply --team Engineering --channel general --to 1700000000000 --message "Deployed"
+  make test 2>&1 | tail -5 | kit teams reply --team Engineering --channel ci --to 1700000000000 --stdin`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+			if channelName == "" {
+				return fmt.Errorf("--channel is required")
+			}
+			if messageID == "" {
+				return fmt.Errorf("--to is required — the ID of the message to reply to (shown by 'kit teams post')")
+			}
+			if useStdin {
+				message = readStdin()
+			}
+			if message == "" {
+				return fmt.Errorf("--message or --stdin is required")
+			}
+
+			if dryRun {
+				if jsonFlag {
+					enc := json.NewEncoder(os.Stdout)
+					enc.SetIndent("", "  ")
+					return enc.Encode(map[string]any{
+						"dryRun":  true,
+						"team":    teamName,
+						"channel": channelName,
+						"to":      messageID,
+						"message": message,
+					})
+				}
+				fmt.Println("--- Teams Reply Preview ---")
+				fmt.Printf("Team:     %s\n", teamName)
+				fmt.Printf("Channel:  #%s\n", channelName)
+				fmt.Printf("Reply to: %s\n", messageID)
+				fmt.Printf("Message:  %s\n", message)
+				fmt.Println("--- Would post via Microsoft Graph API ---")
+				return nil
+			}
+
+			tc, teamID, channelID, err := resolveChannel(ctx, teamName, channelName)
+			if err != nil {
+				return err
+			}
+			msg, err := tc.ReplyToMessage(ctx, teamID, channelID, messageID, message)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(msg)
+			}
+
+			fmt.Printf("Reply posted in #%s\n", channelName)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().StringVar(&channelName, "channel", "", "Channel name or ID (required)")
+	cmd.Flags().StringVar(&messageID, "to", "", "ID of the message to reply to (required)")
+	cmd.Flags().StringVar(&message, "message", "", "Reply text")
+	cmd.Flags().BoolVar(&useStdin, "stdin", false, "Read reply from stdin")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview without posting")
+	return cmd
+}
+
+func newReactCommand() *cobra.Command {
+	var (
+		teamName    string
+		channelName string
+		messageID   string
+		replyID     string
+		remove      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "react <reaction>",
+		Short: "React to a channel message",
+		Long: `Add a reaction to a channel message, or to a reply with --reply. The
+reaction is an emoji, or one of: like, heart, laugh, surprised, sad, angry.`,
+		Example: `  kit teams react 👍 --team Engineering --channel general --to 1700000000000
+  kit teams react heart --team Engineering --channel general --to 1700000000000 --reply 1700000000500
+  kit teams react 👍 --team Engineering --channel general --to 1700000000000 --remove`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			if teamName == "" {
+				return fmt.Errorf("--team is required")
+			}
+			if channelName == "" {
+				return fmt.Errorf("--channel is required")
+			}
+			if messageID == "" {
+				return fmt.Errorf("--to is required — the ID of the message to react to")
+			}
+
+			tc, teamID, channelID, err := resolveChannel(ctx, teamName, channelName)
+			if err != nil {
+				return err
+			}
+			reaction := graph.ReactionEmoji(args[0])
+			if err := tc.SetReaction(ctx, teamID, channelID, messageID, replyID, reaction, remove); err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				return json.NewEncoder(os.Stdout).Encode(map[string]any{
+					"message":  messageID,
+					"reply":    replyID,
+					"reaction": reaction,
+					"removed":  remove,
+				})
+			}
+			if remove {
+				fmt.Printf("Removed %s\n", reaction)
+			} else {
+				fmt.Printf("Reacted %s\n", reaction)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().StringVar(&channelName, "channel", "", "Channel name or ID (required)")
+	cmd.Flags().StringVar(&messageID, "to", "", "ID of the message to react to (required)")
+	cmd.Flags().StringVar(&replyID, "reply", "", "React to this reply in the message's thread instead")
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove your reaction instead of adding it")
+	return cmd
+}
@@ -0,0 +1,193 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/schedule"
+)
+
+// schedulePost saves a post to run later instead of sending it now.
+func schedulePost(cmd *cobra.Command, at, cronExpr string, post *schedule.TeamsPost) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+
+	if at != "" && cronExpr != "" {
+		return fmt.Errorf("--at and --cron cannot be combined")
+	}
+	job := schedule.Job{Kind: schedule.KindTeamsPost, Cron: cronExpr, Post: post}
+	if at != "" {
+		c, when, err := schedule.ParseAt(at, time.Local)
+		if err != nil {
+			return err
+		}
+		if c != nil {
+			job.Cron = c.String()
+		} else {
+			job.NextRun = when
+		}
+	}
+	if post.Attach != "" {
+		abs, err := filepath.Abs(post.Attach)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return fmt.Errorf("cannot schedule attachment: %w", err)
+		}
+		post.Attach = abs
+	}
+
+	saved, err := schedule.DefaultStore().Add(job, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(saved)
+	}
+	fmt.Printf("Scheduled %s\n", saved.Describe())
+	fmt.Printf("ID:        %s\n", saved.ID)
+	if saved.Cron != "" {
+		fmt.Printf("Schedule:  %s\n", saved.Cron)
+	}
+	fmt.Printf("Next run:  %s\n", saved.NextRun.Format("Mon 2006-01-02 15:04"))
+	fmt.Println("Posts are sent while `kit watch start` or `kit teams schedule run` is running.")
+	return nil
+}
+
+func newScheduleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Manage scheduled and recurring posts",
+		Long: `Manage posts scheduled with "kit teams post --at" or "--cron".
+
+Scheduled posts are stored in ~/.kit/schedule.json and sent by the kit
+daemon ("kit watch start") or by "kit teams schedule run".`,
+	}
+	cmd.AddCommand(newScheduleListCommand())
+	cmd.AddCommand(newScheduleRemoveCommand())
+	cmd.AddCommand(newScheduleRunCommand())
+	return cmd
+}
+
+func newScheduleListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List scheduled posts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+
+			jobs, err := schedule.DefaultStore().Load()
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				if jobs == nil {
+					jobs = []schedule.Job{}
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(jobs)
+			}
+
+			if len(jobs) == 0 {
+				fmt.Println("No scheduled posts")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tNEXT RUN\tSCHEDULE\tPOST\tLAST ERROR")
+			for _, j := range jobs {
+				sched := j.Cron
+				if sched == "" {
+					sched = "once"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", j.ID, j.NextRun.Format("Mon 2006-01-02 15:04"), sched, j.Describe(), j.LastError)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newScheduleRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm <id>",
+		Aliases: []string{"remove"},
+		Short:   "Remove a scheduled post",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := schedule.DefaultStore().Remove(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Removed scheduled post %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newScheduleRunCommand() *cobra.Command {
+	var once bool
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Send scheduled posts as they come due (foreground)",
+		Long: `Run the scheduler in the foreground, sending posts as they come due.
+"kit watch start" runs the same scheduler alongside the file watcher.
+
+With --once, send whatever is due now and exit — useful from cron or a
+CI job.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := schedule.DefaultStore()
+
+			if once {
+				results, err := store.RunDue(context.Background(), time.Now(), schedule.Execute)
+				printScheduleResults(results, err)
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-sigCh
+				fmt.Println("\nStopping scheduler...")
+				cancel()
+			}()
+
+			fmt.Println("Running scheduled posts — press Ctrl+C to stop")
+			store.Loop(ctx, 30*time.Second, schedule.Execute, printScheduleResults)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&once, "once", false, "Send due posts and exit")
+	return cmd
+}
+
+// printScheduleResults reports what a scheduler pass did.
+func printScheduleResults(results []schedule.RunResult, err error) {
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			fmt.Fprintf(os.Stderr, "[schedule] %s skipped: missed run at %s\n", r.Job.ID, r.Job.NextRun.Format("2006-01-02 15:04"))
+		case r.Err != nil:
+			fmt.Fprintf(os.Stderr, "[schedule] %s failed: %v\n", r.Job.ID, r.Err)
+		default:
+			fmt.Printf("[schedule] %s posted: %s\n", r.Job.ID, r.Job.Describe())
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[schedule] %v\n", err)
+	}
+}
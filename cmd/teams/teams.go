@@ -2,25 +2,31 @@
 package teams
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/formats/convert"
 	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/progress"
+	"github.com/klytics/m365kit/internal/schedule"
 )
 
 // NewCommand returns the teams command group.
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "teams",
-		Short: "Microsoft Teams messaging and file sharing",
-		Long:  "List teams, post messages, share files, and send DMs via Microsoft Teams.",
+		Use:         "teams",
+		Short:       "Microsoft Teams messaging and file sharing",
+		Long:        "List and administer teams and channels, post messages and replies, react, share files, schedule posts and meetings, and send DMs and group chats via Microsoft Teams.",
+		Annotations: map[string]string{auth.ScopesAnnotation: "Team.ReadBasic.All"},
 	}
 
 	cmd.AddCommand(newListCommand())
@@ -28,12 +34,25 @@ func NewCommand() *cobra.Command {
 	cmd.AddCommand(newPostCommand())
 	cmd.AddCommand(newShareCommand())
 	cmd.AddCommand(newDMCommand())
+	cmd.AddCommand(newReplyCommand())
+	cmd.AddCommand(newReactCommand())
+	cmd.AddCommand(newChatCommand())
+	cmd.AddCommand(newScheduleCommand())
+	cmd.AddCommand(newMeetingCommand())
+	cmd.AddCommand(newCreateTeamCommand())
+	cmd.AddCommand(newArchiveCommand())
+	cmd.AddCommand(newMembersCommand())
+	cmd.AddCommand(newChannelCommand())
+	cmd.AddCommand(newPresenceCommand())
+	cmd.AddCommand(newUsersCommand())
+	cmd.AddCommand(newExportCommand())
+	cmd.AddCommand(newBulkCommand())
 
 	return cmd
 }
 
 func newListCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List your Teams",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -46,6 +65,7 @@ func newListCommand() *cobra.Command {
 			}
 
 			tc := graph.NewTeams(client)
+			tc.Limit, _ = cmd.Flags().GetInt("limit")
 			teams, err := tc.ListTeams(ctx)
 			if err != nil {
 				return err
@@ -77,6 +97,8 @@ func newListCommand() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().Int("limit", 0, "Maximum number of teams to return (0 = all)")
+	return cmd
 }
 
 func newChannelsCommand() *cobra.Command {
@@ -103,6 +125,7 @@ func newChannelsCommand() *cobra.Command {
 				return err
 			}
 
+			tc.Limit, _ = cmd.Flags().GetInt("limit")
 			channels, err := tc.ListChannels(ctx, teamID)
 			if err != nil {
 				return err
@@ -128,6 +151,7 @@ func newChannelsCommand() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&teamName, "team", "", "Team name or ID (required)")
+	cmd.Flags().Int("limit", 0, "Maximum number of channels to return (0 = all)")
 	return cmd
 }
 
@@ -139,36 +163,102 @@ func newPostCommand() *cobra.Command {
 		attachFile  string
 		useStdin    bool
 		dryRun      bool
+		card        cardFlags
+		mentions    []string
+		mentionAll  bool
+		webhook     string
+		at          string
+		cronExpr    string
+		msgTemplate string
+		sets        []string
 	)
 	cmd := &cobra.Command{
 		Use:   "post",
 		Short: "Post a message to a Teams channel",
+		Example: `  kit teams post --team Engineering --channel general --message "Report ready"
+  kit teams post --team Engineering --channel ci --card card.json
+  kit teams post --team Engineering --channel ci --card-template status --status failure \
+    --title "Nightly build failed" --fact Branch=main --button "View run=https://ci.example.com/42"
+  kit teams post --team Engineering --channel ops --mention alice@contoso.com --message "@alice@contoso.com disk is full"
+  kit teams post --team Engineering --channel ops --mention-channel --message "Maintenance at 18:00"
+  kit teams post --webhook "$TEAMS_WEBHOOK_URL" --card-template status --status success --title "Deployed"
+  kit teams post --team Engineering --channel general --at "weekdays 09:00" --message "Standup in 15 minutes"
+  kit teams post --team Engineering --channel general --cron "0 17 * * fri" --message "Please submit timesheets"
+  kit teams post --team Engineering --channel general --template weekly-update --set wins="Shipped v2" --set risks=None`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			ctx := context.Background()
 
-			if teamName == "" {
-				return fmt.Errorf("--team is required")
-			}
-			if channelName == "" {
-				return fmt.Errorf("--channel is required")
+			if webhook == "" {
+				if teamName == "" {
+					return fmt.Errorf("--team is required (or --webhook)")
+				}
+				if channelName == "" {
+					return fmt.Errorf("--channel is required (or --webhook)")
+				}
 			}
 
 			// Read message from stdin if --stdin
 			if useStdin {
-				scanner := bufio.NewScanner(os.Stdin)
-				var lines []byte
-				for scanner.Scan() {
-					if len(lines) > 0 {
-						lines = append(lines, '\n')
-					}
-					lines = append(lines, scanner.Bytes()...)
+				message = readStdin()
+			}
+
+			// A Markdown template becomes the message, sent as HTML
+			var htmlBody string
+			if msgTemplate != "" {
+				if message != "" {
+					return fmt.Errorf("--template cannot be combined with --message or --stdin")
+				}
+				if card.set() || attachFile != "" || len(mentions) > 0 || mentionAll {
+					return fmt.Errorf("--template cannot be combined with cards, --attach, or mentions")
 				}
-				message = string(lines)
+				rendered, err := renderMessageTemplate(msgTemplate, sets)
+				if err != nil {
+					return err
+				}
+				message = rendered
+				htmlBody = convert.MarkdownToHTML(rendered)
 			}
 
-			if message == "" && attachFile == "" {
-				return fmt.Errorf("--message or --attach is required")
+			if message == "" && attachFile == "" && !card.set() {
+				return fmt.Errorf("--message, --attach, --card, or --template is required")
+			}
+			cardJSON, err := card.build(message)
+			if err != nil {
+				return err
+			}
+			if cardJSON != nil && attachFile != "" {
+				return fmt.Errorf("--attach cannot be combined with a card — add a button linking to the file instead")
+			}
+			if card.template != "" {
+				message = "" // used as the card text
+			}
+			if (len(mentions) > 0 || mentionAll) && (cardJSON != nil || attachFile != "") {
+				return fmt.Errorf("--mention and --mention-channel work with text messages only")
+			}
+			if webhook != "" {
+				if attachFile != "" || len(mentions) > 0 || mentionAll {
+					return fmt.Errorf("--attach and mentions need Graph sign-in — they cannot be used with --webhook")
+				}
+				if cardJSON != nil && message != "" {
+					return fmt.Errorf("--message cannot be combined with --card when using --webhook — put the text in the card")
+				}
+			}
+			if (at != "" || cronExpr != "") && !dryRun {
+				return schedulePost(cmd, at, cronExpr, &schedule.TeamsPost{
+					Team:           teamName,
+					Channel:        channelName,
+					Webhook:        webhook,
+					Message:        message,
+					HTML:           htmlBody,
+					Card:           cardJSON,
+					Attach:         attachFile,
+					Mentions:       mentions,
+					MentionChannel: mentionAll,
+				})
+			}
+			if webhook != "" {
+				return postWebhook(cmd, webhook, message, cardJSON, dryRun)
 			}
 
 			if dryRun {
@@ -176,11 +266,15 @@ func newPostCommand() *cobra.Command {
 					enc := json.NewEncoder(os.Stdout)
 					enc.SetIndent("", "  ")
 					return enc.Encode(map[string]any{
-						"dryRun":  true,
-						"team":    teamName,
-						"channel": channelName,
-						"message": message,
-						"attach":  attachFile,
+						"dryRun":         true,
+						"team":           teamName,
+						"channel":        channelName,
+						"message":        message,
+						"html":           htmlBody,
+						"attach":         attachFile,
+						"card":           cardJSON,
+						"mention":        mentions,
+						"mentionChannel": mentionAll,
 					})
 				}
 				fmt.Println("--- Teams Post Preview ---")
@@ -192,6 +286,17 @@ func newPostCommand() *cobra.Command {
 				if attachFile != "" {
 					fmt.Printf("Attach:   %s\n", attachFile)
 				}
+				if len(mentions) > 0 {
+					fmt.Printf("Mention:  %s\n", strings.Join(mentions, ", "))
+				}
+				if mentionAll {
+					fmt.Printf("Mention:  #%s (channel)\n", channelName)
+				}
+				if cardJSON != nil {
+					var pretty bytes.Buffer
+					json.Indent(&pretty, cardJSON, "", "  ")
+					fmt.Printf("Card:\n%s\n", pretty.String())
+				}
 				fmt.Println("--- Would post via Microsoft Graph API ---")
 				return nil
 			}
@@ -212,7 +317,15 @@ func newPostCommand() *cobra.Command {
 			}
 
 			var msg *graph.ChatMessage
-			if attachFile != "" {
+			if htmlBody != "" {
+				msg, err = tc.PostHTMLMessage(ctx, teamID, channelID, htmlBody)
+			} else if cardJSON != nil {
+				msg, err = tc.PostCard(ctx, teamID, channelID, message, cardJSON)
+			} else if len(mentions) > 0 || mentionAll {
+				msg, err = tc.PostMentionMessage(ctx, teamID, channelID, message, mentions, mentionAll)
+			} else if attachFile != "" {
+				jsonFlag, _ := cmd.Flags().GetBool("json")
+				tc.Progress = progress.ForTransfer(filepath.Base(attachFile), false, jsonFlag)
 				msg, err = tc.PostMessageWithFile(ctx, teamID, channelID, message, attachFile)
 			} else {
 				msg, err = tc.PostMessage(ctx, teamID, channelID, message)
@@ -228,6 +341,7 @@ func newPostCommand() *cobra.Command {
 			}
 
 			fmt.Printf("Message posted to #%s\n", channelName)
+			fmt.Printf("ID:  %s\n", msg.ID)
 			if msg.WebURL != "" {
 				fmt.Printf("URL: %s\n", msg.WebURL)
 			}
@@ -240,6 +354,14 @@ func newPostCommand() *cobra.Command {
 	cmd.Flags().StringVar(&attachFile, "attach", "", "File to attach")
 	cmd.Flags().BoolVar(&useStdin, "stdin", false, "Read message from stdin")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview without posting")
+	card.add(cmd)
+	cmd.Flags().StringArrayVar(&mentions, "mention", nil, "@mention a user by email (repeatable); replaces @<email> in the message")
+	cmd.Flags().BoolVar(&mentionAll, "mention-channel", false, "@mention the channel to notify everyone following it")
+	cmd.Flags().StringVar(&webhook, "webhook", "", "Post to a Teams incoming webhook or Workflows URL instead (no sign-in)")
+	cmd.Flags().StringVar(&msgTemplate, "template", "", "Text or Markdown template (library name or file) to use as the message")
+	cmd.Flags().StringArrayVar(&sets, "set", nil, "Set a template variable (key=value, repeatable)")
+	cmd.Flags().StringVar(&at, "at", "", `Schedule the post: "09:00", "Mon 09:00", "weekdays 09:00", or "2026-10-20 09:00"`)
+	cmd.Flags().StringVar(&cronExpr, "cron", "", `Schedule a recurring post with a cron expression, e.g. "0 9 * * 1-5"`)
 	return cmd
 }
 
@@ -306,6 +428,7 @@ func newShareCommand() *cobra.Command {
 				return err
 			}
 
+			tc.Progress = progress.ForTransfer(filepath.Base(filePath), false, jsonFlag)
 			msg, err := tc.PostMessageWithFile(ctx, teamID, channelID, message, filePath)
 			if err != nil {
 				return err
@@ -331,24 +454,49 @@ func newShareCommand() *cobra.Command {
 
 func newDMCommand() *cobra.Command {
 	var (
-		toEmail    string
+		to         []string
 		message    string
 		attachFile string
 		dryRun     bool
 	)
 	cmd := &cobra.Command{
 		Use:   "dm",
-		Short: "Send a direct message to a user",
+		Short: "Send a direct message to one or more people",
+		Long: `Send a direct message. With several --to recipients the message goes to
+a group chat with exactly those people, reusing an existing untitled group
+chat when there is one.
+
+--attach uploads the file to your OneDrive ("Microsoft Teams Chat Files"),
+shares it with the recipients, and sends it as a file attachment.`,
+		Example: `  kit teams dm --to alice@contoso.com --message "Contract is ready"
+  kit teams dm --to "Anna K" --message "Lunch?"
+  kit teams dm --to alice@contoso.com,bob@contoso.com --message "Numbers attached" --attach q3.xlsx`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			ctx := context.Background()
 
-			if toEmail == "" {
+			emails := splitEmails(to)
+			if len(emails) == 0 {
 				return fmt.Errorf("--to is required")
 			}
 			if message == "" && attachFile == "" {
 				return fmt.Errorf("--message or --attach is required")
 			}
+			if attachFile != "" {
+				if _, err := os.Stat(attachFile); err != nil {
+					return fmt.Errorf("cannot attach: %w", err)
+				}
+			}
+			if graph.NeedsLookup(emails) {
+				auth.RequireScopes("People.Read")
+				client, err := auth.RequireAuth(ctx)
+				if err != nil {
+					return err
+				}
+				if emails, err = graph.ResolveAddresses(ctx, client, emails, os.Stderr); err != nil {
+					return err
+				}
+			}
 
 			if dryRun {
 				if jsonFlag {
@@ -356,13 +504,13 @@ func newDMCommand() *cobra.Command {
 					enc.SetIndent("", "  ")
 					return enc.Encode(map[string]any{
 						"dryRun":  true,
-						"to":      toEmail,
+						"to":      emails,
 						"message": message,
 						"attach":  attachFile,
 					})
 				}
 				fmt.Println("--- Teams DM Preview ---")
-				fmt.Printf("To:       %s\n", toEmail)
+				fmt.Printf("To:       %s\n", strings.Join(emails, ", "))
 				if message != "" {
 					fmt.Printf("Message:  %s\n", message)
 				}
@@ -379,13 +527,19 @@ func newDMCommand() *cobra.Command {
 			}
 
 			tc := graph.NewTeams(client)
-
-			msgText := message
-			if attachFile != "" && msgText == "" {
-				msgText = "Shared a file: " + attachFile
+			chat, err := tc.DirectChat(ctx, emails)
+			if err != nil {
+				return err
 			}
 
-			msg, err := tc.SendDirectMessage(ctx, toEmail, msgText)
+			var msg *graph.ChatMessage
+			if attachFile != "" {
+				jsonFlag, _ := cmd.Flags().GetBool("json")
+				tc.Progress = progress.ForTransfer(filepath.Base(attachFile), false, jsonFlag)
+				msg, err = tc.SendChatFile(ctx, chat.ID, emails, message, attachFile)
+			} else {
+				msg, err = tc.SendChatMessage(ctx, chat.ID, message)
+			}
 			if err != nil {
 				return err
 			}
@@ -396,11 +550,14 @@ func newDMCommand() *cobra.Command {
 				return enc.Encode(msg)
 			}
 
-			fmt.Printf("DM sent to %s\n", toEmail)
+			fmt.Printf("DM sent to %s\n", strings.Join(emails, ", "))
+			if attachFile != "" {
+				fmt.Printf("Attached: %s\n", filepath.Base(attachFile))
+			}
 			return nil
 		},
 	}
-	cmd.Flags().StringVar(&toEmail, "to", "", "Recipient email (required)")
+	cmd.Flags().StringArrayVar(&to, "to", nil, "Recipient email or name (required; repeatable or comma-separated)")
 	cmd.Flags().StringVar(&message, "message", "", "Message text")
 	cmd.Flags().StringVar(&attachFile, "attach", "", "File to attach")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview without sending")
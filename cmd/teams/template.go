@@ -0,0 +1,48 @@
+package teams
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tmpl "github.com/klytics/m365kit/internal/template"
+)
+
+// renderMessageTemplate fills a text or Markdown template, given by library
+// name or file path, with --set values and returns the Markdown.
+func renderMessageTemplate(name string, sets []string) (string, error) {
+	values := make(map[string]string)
+	for _, s := range sets {
+		k, v, ok := strings.Cut(s, "=")
+		if !ok {
+			return "", fmt.Errorf("invalid --set format: %q (expected key=value)", s)
+		}
+		values[k] = v
+	}
+
+	path := name
+	if _, err := os.Stat(path); err != nil {
+		lib, err := tmpl.LoadLibrary(tmpl.DefaultLibraryDir())
+		if err != nil {
+			return "", err
+		}
+		t, err := lib.Get(name)
+		if err != nil {
+			return "", fmt.Errorf("template %q not found — register it with: kit template add %s <file.md>", name, name)
+		}
+		path = t.Path
+	}
+	if !tmpl.IsTextTemplate(path) {
+		return "", fmt.Errorf("template %q is not a text or Markdown template (.md, .txt)", name)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read template: %w", err)
+	}
+	text, _, missing := tmpl.ApplyText(string(data), values)
+	if len(missing) > 0 {
+		return "", fmt.Errorf("template %q needs values for: %s — pass them with --set name=value", name, strings.Join(missing, ", "))
+	}
+	return strings.TrimSpace(text), nil
+}
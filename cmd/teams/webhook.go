@@ -0,0 +1,49 @@
+package teams
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/httpclient"
+)
+
+// postWebhook posts a message or card to a webhook URL. The URL is a
+// secret, so it is never echoed back.
+func postWebhook(cmd *cobra.Command, webhookURL, message string, card json.RawMessage, dryRun bool) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+
+	if dryRun {
+		if jsonFlag {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(map[string]any{"dryRun": true, "webhook": true, "message": message, "card": card})
+		}
+		fmt.Println("--- Teams Webhook Preview ---")
+		if message != "" {
+			fmt.Printf("Message:  %s\n", message)
+		}
+		if card != nil {
+			fmt.Println("Card:     (Adaptive Card)")
+		}
+		fmt.Println("--- Would post to the webhook URL ---")
+		return nil
+	}
+
+	client, err := httpclient.New()
+	if err != nil {
+		return err
+	}
+	if err := graph.PostWebhook(cmd.Context(), client, webhookURL, message, card); err != nil {
+		return err
+	}
+
+	if jsonFlag {
+		return json.NewEncoder(os.Stdout).Encode(map[string]any{"posted": true, "webhook": true})
+	}
+	fmt.Println("Message posted via webhook")
+	return nil
+}
@@ -2,14 +2,18 @@
 package template
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
+	"github.com/klytics/m365kit/internal/plugin"
+	"github.com/klytics/m365kit/internal/remote"
 	tmpl "github.com/klytics/m365kit/internal/template"
 )
 
@@ -19,7 +23,7 @@ func NewCommand() *cobra.Command {
 		Use:     "template",
 		Aliases: []string{"tmpl"},
 		Short:   "Manage document templates with variable substitution",
-		Long:    "Create, manage, and apply document templates with {{variable}} placeholders.",
+		Long:    "Create, manage, and apply document and message templates with {{variable}} placeholders.",
 	}
 
 	cmd.AddCommand(newListCmd())
@@ -128,6 +132,16 @@ func newShowCmd() *cobra.Command {
 	return cmd
 }
 
+// templateHook is the data passed to pre-template-apply and
+// post-template-apply plugin hooks.
+type templateHook struct {
+	Template string            `json:"template"` // as given: a path or library name
+	File     string            `json:"file"`     // local template file
+	Output   string            `json:"output"`
+	Values   map[string]string `json:"values"`
+	Missing  []string          `json:"missing,omitempty"` // post only: variables left unfilled
+}
+
 func newApplyCmd() *cobra.Command {
 	var (
 		outputPath string
@@ -144,7 +158,10 @@ Variables can be provided via --set flags:
   kit template apply contract.docx --set name="John Doe" --set date="2025-01-01" -o filled.docx
 
 Or apply a registered template by name:
-  kit template apply invoice --set client="Acme Corp" --set amount="$5,000" -o invoice.docx`,
+  kit template apply invoice --set client="Acme Corp" --set amount="$5,000" -o invoice.docx
+
+Templates and outputs may also be remote paths:
+  kit template apply onedrive:/Templates/nda.docx --set party="Acme" -o onedrive:/Contracts/nda-acme.docx`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Parse --set values
@@ -157,11 +174,22 @@ Or apply a registered template by name:
 				values[parts[0]] = parts[1]
 			}
 
+			ctx := context.Background()
 			input := args[0]
 			templatePath := input
 
-			// Check if it's a library template name (no file extension)
-			if !strings.HasSuffix(input, ".docx") {
+			if remote.IsRemote(input) {
+				local, cleanup, err := remote.Open(ctx, input)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+				templatePath = local
+				if outputPath == "" {
+					outputPath = strings.TrimSuffix(filepath.Base(local), ".docx") + "_filled.docx"
+				}
+			} else if !strings.HasSuffix(input, ".docx") {
+				// Check if it's a library template name (no file extension)
 				lib, err := tmpl.LoadLibrary(tmpl.DefaultLibraryDir())
 				if err == nil {
 					if t, err := lib.Get(input); err == nil {
@@ -171,8 +199,8 @@ Or apply a registered template by name:
 			}
 
 			if outputPath == "" {
-				base := strings.TrimSuffix(templatePath, ".docx")
-				outputPath = base + "_filled.docx"
+				ext := filepath.Ext(templatePath)
+				outputPath = strings.TrimSuffix(templatePath, ext) + "_filled" + ext
 			}
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
@@ -205,11 +233,31 @@ Or apply a registered template by name:
 				return nil
 			}
 
-			result, err := tmpl.Apply(templatePath, values, outputPath)
+			localOut, cleanupOut, err := remote.StagingPath(outputPath)
+			if err != nil {
+				return err
+			}
+			defer cleanupOut()
+
+			hook := templateHook{Template: input, File: templatePath, Output: outputPath, Values: values}
+			if err := plugin.RunHooks(ctx, plugin.HookPreTemplateApply, hook); err != nil {
+				return err
+			}
+
+			result, err := tmpl.Apply(templatePath, values, localOut)
 			if err != nil {
 				return err
 			}
 
+			if remote.IsRemote(outputPath) {
+				if _, err := remote.Save(ctx, localOut, outputPath); err != nil {
+					return err
+				}
+				result.OutputPath = outputPath
+			}
+			hook.Missing = result.MissingNames
+			plugin.RunHooks(ctx, plugin.HookPostTemplateApply, hook)
+
 			if jsonOut {
 				return json.NewEncoder(os.Stdout).Encode(result)
 			}
@@ -237,8 +285,11 @@ func newAddCmd() *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:   "add <name> <file.docx>",
-		Short: "Register a document as a template in the library",
+		Use:   "add <name> <file.docx|file.md|file.txt>",
+		Short: "Register a document or a text/Markdown message as a template in the library",
+		Long: `Register a template in the library. Word documents are applied with
+"kit template apply"; text and Markdown templates can also be used as
+message bodies, e.g. "kit teams post --template <name>".`,
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dir := libraryDir
@@ -6,12 +6,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/plugin"
+	"github.com/klytics/m365kit/internal/remote"
+	"github.com/klytics/m365kit/internal/schedule"
 	w "github.com/klytics/m365kit/internal/watch"
 )
 
@@ -21,11 +31,18 @@ func NewCommand() *cobra.Command {
 		Use:   "watch",
 		Short: "Monitor directories for document changes and auto-process",
 		Long: `Watch directories for new or modified Office documents and trigger
-automated processing based on configured rules.
+automated processing based on configured rules. While running, the
+watcher also sends posts scheduled with "kit teams post --at/--cron".
+
+"kit watch mail" runs a kit command for each new email that matches a
+filter, as soon as it lands.
 
 Example:
   kit watch start ./contracts --ext docx --action log
+  kit watch mail --filter from:alerts@ --action "outlook download -o ./inbound"
   kit watch status
+  kit watch log --since 1h --status error
+  kit watch failed --reprocess
   kit watch stop`,
 	}
 
@@ -33,6 +50,9 @@ Example:
 	cmd.AddCommand(newStopCmd())
 	cmd.AddCommand(newStatusCmd())
 	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newLogCmd())
+	cmd.AddCommand(newFailedCmd())
+	cmd.AddCommand(newMailCmd())
 
 	return cmd
 }
@@ -42,25 +62,136 @@ func newStartCmd() *cobra.Command {
 		extensions []string
 		recursive  bool
 		actionName string
+		options    map[string]string
+		rulesFile  string
 		debounce   int
+		stable     int
+		workers    int
+		daemon     bool
+		service    string
+		poll       time.Duration
+		downloads  string
+		minSize    string
+		maxSize    string
+		namePat    string
+		contains   string
+		retries    int
+		backoff    string
+		metrics    string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "start <directory> [directory...]",
 		Short: "Start watching directories for document changes",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Watch directories and run an action for each new or modified file.
+
+Directories may be OneDrive or SharePoint folders (onedrive:/Incoming,
+sp:<site>/<library>/Incoming). These are polled for changes every
+--poll; files a rule matches are downloaded to --download-dir and
+handled like local files.
+
+Actions and their --option settings:
+  log              print the match (default)
+  template         output, template, values, var.<name>
+  convert          to, output
+  copy, move       dest, overwrite
+  command          run, timeout
+  teams-post       team, channel, message, attach
+  onedrive-upload  dest
+
+Paths, messages, and commands may use {path}, {dir}, {name}, {stem},
+{ext}, {date}, and {time}. Without a template option, the watched file
+is the template; with one, a watched .json file supplies the values.
+In a command, placeholders are quoted for the shell.
+
+--min-size, --max-size, --name (a regular expression for the file name),
+and --contains (text the document must contain, case-insensitive) narrow
+which files the action runs for. Documents are only opened for --contains
+once every other condition holds.
+
+For several rules, put them in a JSON file and pass --rules: a list of
+{"id", "pattern", "extensions", "minSize", "maxSize", "name", "contains",
+"action": {"type", "options"}}. Sizes are bytes or strings like "50KB".
+A rule may list "actions" instead of one "action"; they run in order, each
+on the file the previous one produced (a convert or template output, or a
+moved file), stopping at the first failure. Rules are tried from the
+highest "priority" down, and the first that matches handles the file;
+set "continue": true on a rule to let the rules after it run as well.
+"concurrency" caps how many files a rule handles at once. An action may
+set "retries" and "backoff" (see --retries).
+
+A failed action is tried --retries more times, waiting --backoff before
+the first retry and twice as long before each one after, so a short
+Graph outage doesn't lose the file. Files that still fail are added to
+the dead-letter list shown by "kit watch failed".
+
+Files are handled by --workers at a time; the rest wait in a queue, so
+copying in hundreds of files doesn't start hundreds of actions at once.
+
+A file is handled once its size and modification time have stayed the
+same for --stable-checks debounce intervals (and, on Windows, once no
+other program has it open), so documents still being saved or copied
+are not picked up half-written.
+
+--daemon starts the watcher in the background, logging to
+~/.kit/watch.log; "kit watch status" and "kit watch stop" reach it over
+a local socket. --service writes a definition that starts the same
+watcher at login instead: a systemd user unit, a launchd agent, or a
+Windows logon task.
+
+--metrics serves Prometheus metrics at /metrics: files seen, processed,
+and failed per rule, queue length, and a histogram of how long each
+rule's actions take. A port alone (--metrics 9464) listens on localhost
+only.
+
+Examples:
+  kit watch start ./inbox --ext docx --action convert --option to=md --option output=./markdown/{stem}.md
+  kit watch start ./scans --ext pdf --action onedrive-upload --option dest=/Scans/{date}
+  kit watch start ./reports --action teams-post --option team=Finance --option channel=General --option attach=true
+  kit watch start ./data --ext json --action template --option template=invoice --option output=./out/{stem}.docx
+  kit watch start ./drop --action command --option run="echo {name} >> seen.log"
+  kit watch start ./shared --rules watch-rules.json
+  kit watch start ./inbox --ext docx --contains INVOICE --min-size 50KB --action move --option dest=./invoices
+  kit watch start ./scans --name '^scan_\d+' --max-size 10MB --action onedrive-upload --option dest=/Scans
+  kit watch start onedrive:/Incoming --ext xlsx --action command --option run="kit report generate --template q.docx --data {path}"
+  kit watch start sp:Finance/Documents/Invoices -r --ext pdf --action copy --option dest=./invoices/
+  kit watch start ./inbox --ext pdf --action move --option dest=./filed --daemon
+  kit watch start ./inbox --rules watch-rules.json --daemon --metrics 9464
+  kit watch start ./inbox --ext pdf --action move --option dest=./filed --service systemd`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(extensions) == 0 {
 				extensions = []string{".docx", ".xlsx", ".pptx", ".csv", ".json"}
 			}
 
-			rules := []w.Rule{
-				{
-					ID:         "default",
-					Extensions: extensions,
-					Action:     w.Action{Name: actionName, Type: actionName},
-					Enabled:    true,
-				},
+			rule := w.Rule{
+				ID:         "default",
+				Extensions: extensions,
+				Name:       namePat,
+				Contains:   contains,
+				Action:     w.Action{Name: actionName, Type: actionName, Options: options, Retries: retries, Backoff: backoff},
+				Enabled:    true,
+			}
+			for _, size := range []struct {
+				flag  string
+				value string
+				dest  *w.ByteSize
+			}{{"min-size", minSize, &rule.MinSize}, {"max-size", maxSize, &rule.MaxSize}} {
+				if size.value == "" {
+					continue
+				}
+				n, err := w.ParseSize(size.value)
+				if err != nil {
+					return fmt.Errorf("--%s: %w", size.flag, err)
+				}
+				*size.dest = w.ByteSize(n)
+			}
+			rules := []w.Rule{rule}
+			if rulesFile != "" {
+				var err error
+				if rules, err = loadRules(rulesFile); err != nil {
+					return err
+				}
 			}
 
 			config := w.WatchConfig{
@@ -68,6 +199,37 @@ func newStartCmd() *cobra.Command {
 				Rules:       rules,
 				Recursive:   recursive,
 				Debounce:    debounce,
+
+				StableChecks: stable,
+				Workers:      workers,
+
+				PollInterval: int(poll / time.Second),
+				DownloadDir:  downloads,
+			}
+			if err := config.Validate(); err != nil {
+				return err
+			}
+
+			configDir := w.DefaultConfigDir()
+			if service != "" {
+				return writeService(cmd, service, configDir, startArgs(cmd, args))
+			}
+			if st, err := w.QueryStatus(configDir); err == nil {
+				return fmt.Errorf("a watcher is already running (PID %d); stop it with 'kit watch stop'", st.PID)
+			}
+			if daemon {
+				pid, err := w.Detach(configDir, startArgs(cmd, args))
+				if err != nil {
+					return err
+				}
+				jsonOut, _ := cmd.Flags().GetBool("json")
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]any{"pid": pid, "log": w.LogFile(configDir)})
+				}
+				fmt.Printf("Watcher started in the background (PID %d)\n", pid)
+				fmt.Printf("  Log:  %s\n", w.LogFile(configDir))
+				fmt.Println("  Stop: kit watch stop")
+				return nil
 			}
 
 			watcher, err := w.New(config)
@@ -75,13 +237,13 @@ func newStartCmd() *cobra.Command {
 				return err
 			}
 
-			watcher.Handler = func(path string, rule w.Rule) error {
-				fmt.Printf("[%s] %s → %s\n", rule.Action.Name, path, "processed")
-				return nil
+			watcher.Log = w.NewEventLog(configDir)
+			watcher.Failed = w.NewDeadLetters(configDir)
+			watcher.OnEvent = func(evt w.Event) {
+				plugin.RunHooks(context.Background(), plugin.HookOnWatchEvent, watchHook{Source: "file", Event: &evt})
 			}
 
 			// Write PID
-			configDir := w.DefaultConfigDir()
 			if err := w.WritePIDFile(configDir); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: could not write PID file: %v\n", err)
 			}
@@ -90,8 +252,12 @@ func newStartCmd() *cobra.Command {
 			// Save config for status command
 			w.SaveConfig(configDir, config)
 
-			fmt.Printf("Watching %d directory(ies) for %s files\n",
-				len(args), strings.Join(extensions, ", "))
+			if rulesFile != "" {
+				fmt.Printf("Watching %d directory(ies) with %d rule(s) from %s\n", len(args), len(rules), rulesFile)
+			} else {
+				fmt.Printf("Watching %d directory(ies) for %s files\n",
+					len(args), strings.Join(extensions, ", "))
+			}
 			fmt.Println("Press Ctrl+C to stop")
 
 			ctx, cancel := context.WithCancel(context.Background())
@@ -106,24 +272,188 @@ func newStartCmd() *cobra.Command {
 				cancel()
 			}()
 
+			// Answer kit watch status and stop
+			go func() {
+				if err := watcher.ServeControl(ctx, configDir, cancel); err != nil {
+					watcher.Logger.Printf("Control socket: %v", err)
+				}
+			}()
+
+			if metrics != "" {
+				go func() {
+					if err := watcher.ServeMetrics(ctx, metrics); err != nil {
+						watcher.Logger.Printf("Metrics: %v", err)
+					}
+				}()
+			}
+
+			// Send scheduled posts (kit teams post --at/--cron) while running
+			go schedule.DefaultStore().Loop(ctx, 30*time.Second, schedule.Execute, func(results []schedule.RunResult, err error) {
+				for _, r := range results {
+					switch {
+					case r.Skipped:
+						watcher.Logger.Printf("Scheduled job %s skipped: missed run at %s", r.Job.ID, r.Job.NextRun.Format("2006-01-02 15:04"))
+					case r.Err != nil:
+						watcher.Logger.Printf("Scheduled job %s failed: %v", r.Job.ID, r.Err)
+					default:
+						watcher.Logger.Printf("Scheduled job %s ran: %s", r.Job.ID, r.Job.Describe())
+					}
+				}
+				if err != nil {
+					watcher.Logger.Printf("Scheduler error: %v", err)
+				}
+			})
+
 			return watcher.Start(ctx)
 		},
 	}
 
 	cmd.Flags().StringSliceVar(&extensions, "ext", nil, "File extensions to watch (default: .docx,.xlsx,.pptx,.csv,.json)")
 	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Watch directories recursively")
-	cmd.Flags().StringVar(&actionName, "action", "log", "Action to perform: log, template, command")
+	cmd.Flags().StringVar(&actionName, "action", "log", "Action to perform: "+strings.Join(w.ActionTypes(), ", "))
+	cmd.Flags().StringToStringVar(&options, "option", nil, "Action option as key=value (repeatable)")
+	cmd.Flags().StringVar(&minSize, "min-size", "", "Only files at least this large (e.g. 50KB)")
+	cmd.Flags().StringVar(&maxSize, "max-size", "", "Only files at most this large (e.g. 10MB)")
+	cmd.Flags().StringVar(&namePat, "name", "", "Only files whose name matches this regular expression")
+	cmd.Flags().StringVar(&contains, "contains", "", "Only documents containing this text (case-insensitive)")
+	cmd.Flags().IntVar(&retries, "retries", 0, "Times to retry a failed action before the file is given up on")
+	cmd.Flags().StringVar(&backoff, "backoff", "2s", "Wait before the first retry; doubles for each retry after")
+	cmd.Flags().StringVar(&rulesFile, "rules", "", "JSON file of watch rules (replaces --ext, --action, --option, and the file conditions)")
 	cmd.Flags().IntVar(&debounce, "debounce", 500, "Debounce interval in milliseconds")
+	cmd.Flags().IntVar(&stable, "stable-checks", 1, "Debounce intervals a file must stay unchanged before it is handled")
+	cmd.Flags().IntVar(&workers, "workers", 4, "How many files to handle at once; the rest are queued")
+	cmd.Flags().DurationVar(&poll, "poll", time.Minute, "How often to check OneDrive and SharePoint folders for changes")
+	cmd.Flags().StringVar(&downloads, "download-dir", "", "Where to download files from OneDrive and SharePoint folders (default ~/.kit/watch-downloads)")
+	cmd.Flags().BoolVar(&daemon, "daemon", false, "Run the watcher in the background")
+	cmd.Flags().StringVar(&metrics, "metrics", "", "Serve Prometheus metrics on this address (e.g. 9464 or 127.0.0.1:9464)")
+	cmd.Flags().StringVar(&service, "service", "", "Write a login service for this watcher instead of starting it: "+strings.Join(w.ServiceKinds, ", "))
+	cmd.MarkFlagsMutuallyExclusive("daemon", "service")
 
 	return cmd
 }
 
+// startArgs rebuilds the kit watch start command line, without --daemon
+// and --service, for a background watcher or service to run. Directories
+// are made absolute.
+func startArgs(cmd *cobra.Command, dirs []string) []string {
+	args := []string{"watch", "start"}
+	for _, d := range dirs {
+		if abs, err := filepath.Abs(d); err == nil && !remote.IsRemote(d) {
+			d = abs
+		}
+		args = append(args, d)
+	}
+	flags := cmd.Flags()
+	if exts, _ := flags.GetStringSlice("ext"); flags.Changed("ext") {
+		args = append(args, "--ext="+strings.Join(exts, ","))
+	}
+	for _, name := range []string{"recursive", "min-size", "max-size", "name", "contains", "action", "retries", "backoff", "rules", "debounce", "stable-checks", "workers", "poll", "download-dir", "metrics"} {
+		if flags.Changed(name) {
+			args = append(args, "--"+name+"="+flags.Lookup(name).Value.String())
+		}
+	}
+	options, _ := flags.GetStringToString("option")
+	keys := make([]string, 0, len(options))
+	for k := range options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--option="+optionArg(k, options[k]))
+	}
+	return args
+}
+
+// optionArg formats an --option value so it parses back to key=value:
+// with more than one "=", the flag reads its value as CSV.
+func optionArg(key, value string) string {
+	pair := key + "=" + value
+	if strings.Contains(value, "=") && strings.ContainsAny(value, ",\"") {
+		return `"` + strings.ReplaceAll(pair, `"`, `""`) + `"`
+	}
+	return pair
+}
+
+// writeService writes a login service of the given kind that runs the
+// watcher with args.
+func writeService(cmd *cobra.Command, kind, configDir string, args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("could not locate kit: %w", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	svc, err := w.NewService(kind, configDir, exe, args, wd)
+	if err != nil {
+		return err
+	}
+	if err := svc.Write(); err != nil {
+		return fmt.Errorf("could not write service: %w", err)
+	}
+
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	if jsonOut {
+		return json.NewEncoder(os.Stdout).Encode(svc)
+	}
+	fmt.Printf("Wrote %s service to %s\n", kind, svc.Path)
+	fmt.Printf("  Enable:  %s\n", svc.Enable)
+	fmt.Printf("  Disable: %s\n", svc.Disable)
+	return nil
+}
+
+// loadRules reads a JSON list of watch rules. Rules are enabled unless
+// they say otherwise, and get an ID from their position when they have
+// none.
+func loadRules(path string) ([]w.Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read rules: %w", err)
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	rules := make([]w.Rule, len(raw))
+	for i, r := range raw {
+		rules[i].Enabled = true
+		if err := json.Unmarshal(r, &rules[i]); err != nil {
+			return nil, fmt.Errorf("invalid rule %d in %s: %w", i+1, path, err)
+		}
+		if rules[i].ID == "" {
+			rules[i].ID = fmt.Sprintf("rule-%d", i+1)
+		}
+		if rules[i].Action.Name == "" {
+			rules[i].Action.Name = rules[i].Action.Type
+		}
+		for j := range rules[i].Actions {
+			if rules[i].Actions[j].Name == "" {
+				rules[i].Actions[j].Name = rules[i].Actions[j].Type
+			}
+		}
+	}
+	return rules, nil
+}
+
 func newStopCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "stop",
 		Short: "Stop the running watcher",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			configDir := w.DefaultConfigDir()
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			// Ask the watcher over its control socket, then fall back to
+			// signalling the process in the PID file
+			if st, err := w.QueryStatus(configDir); err == nil {
+				if err := w.RequestStop(configDir, 10*time.Second); err != nil {
+					return err
+				}
+				waitForExit(st.PID, 5*time.Second)
+				return printStopped(jsonOut, st.PID)
+			}
+
 			pid, err := w.ReadPIDFile(configDir)
 			if err != nil {
 				return fmt.Errorf("no watcher running (PID file not found)")
@@ -140,19 +470,34 @@ func newStopCmd() *cobra.Command {
 			}
 
 			w.RemovePIDFile(configDir)
+			return printStopped(jsonOut, pid)
+		},
+	}
+}
 
-			jsonOut, _ := cmd.Flags().GetBool("json")
-			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]any{
-					"stopped": true,
-					"pid":     pid,
-				})
-			}
+// waitForExit waits up to timeout for process pid to exit, so the
+// watcher has finished cleaning up when stop returns.
+func waitForExit(pid int, timeout time.Duration) {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return
+	}
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		if process.Signal(syscall.Signal(0)) != nil {
+			return
+		}
+	}
+}
 
-			fmt.Printf("Stopped watcher (PID %d)\n", pid)
-			return nil
-		},
+func printStopped(jsonOut bool, pid int) error {
+	if jsonOut {
+		return json.NewEncoder(os.Stdout).Encode(map[string]any{
+			"stopped": true,
+			"pid":     pid,
+		})
 	}
+	fmt.Printf("Stopped watcher (PID %d)\n", pid)
+	return nil
 }
 
 func newStatusCmd() *cobra.Command {
@@ -161,6 +506,32 @@ func newStatusCmd() *cobra.Command {
 		Short: "Show the current watcher status",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			configDir := w.DefaultConfigDir()
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			// A running watcher reports its live state
+			if st, err := w.QueryStatus(configDir); err == nil {
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(st)
+				}
+				fmt.Printf("Watcher is running (PID %d)\n", st.PID)
+				if started, err := time.Parse(time.RFC3339, st.StartedAt); err == nil {
+					fmt.Printf("  Started:     %s (%s ago)\n", started.Format("2006-01-02 15:04"), time.Since(started).Round(time.Second))
+				}
+				fmt.Printf("  Directories: %s\n", strings.Join(st.Directories, ", "))
+				fmt.Printf("  Rules:       %d\n", st.Rules)
+				fmt.Printf("  Recursive:   %v\n", st.Recursive)
+				fmt.Printf("  Events:      %d (%d errors)\n", st.EventCount, st.ErrorCount)
+				if st.Queued+st.Active > 0 {
+					fmt.Printf("  Queue:       %d handling, %d waiting\n", st.Active, st.Queued)
+				}
+				if e := st.LastEvent; e != nil {
+					fmt.Printf("  Last event:  %s %s (%s)\n", e.Time.Format("15:04:05"), e.Path, e.Status)
+				}
+				if st.Metrics != "" {
+					fmt.Printf("  Metrics:     %s\n", st.Metrics)
+				}
+				return nil
+			}
 
 			pid, err := w.ReadPIDFile(configDir)
 			running := err == nil
@@ -180,8 +551,6 @@ func newStatusCmd() *cobra.Command {
 				}
 			}
 
-			jsonOut, _ := cmd.Flags().GetBool("json")
-
 			if !running {
 				if jsonOut {
 					return json.NewEncoder(os.Stdout).Encode(map[string]any{"running": false})
@@ -235,13 +604,437 @@ func newConfigCmd() *cobra.Command {
 
 			fmt.Printf("Directories: %s\n", strings.Join(config.Directories, ", "))
 			fmt.Printf("Recursive:   %v\n", config.Recursive)
-			fmt.Printf("Debounce:    %dms\n", config.Debounce)
+			fmt.Printf("Debounce:    %dms (stable for %d)\n", config.Debounce, max(config.StableChecks, 1))
 			fmt.Printf("Rules:       %d\n", len(config.Rules))
 			for _, r := range config.Rules {
-				fmt.Printf("  [%s] ext=%v action=%s enabled=%v\n",
-					r.ID, r.Extensions, r.Action.Name, r.Enabled)
+				fmt.Printf("  [%s] ext=%v action=%s enabled=%v",
+					r.ID, r.Extensions, r.ActionNames(), r.Enabled)
+				if r.Priority != 0 {
+					fmt.Printf(" priority=%d", r.Priority)
+				}
+				if r.Continue {
+					fmt.Print(" continue")
+				}
+				fmt.Println()
 			}
 			return nil
 		},
 	}
 }
+
+func newLogCmd() *cobra.Command {
+	var (
+		since  string
+		status string
+		rule   string
+		path   string
+		last   int
+		retry  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show the events the watcher has handled, and retry failures",
+		Long: `Show the files kit watch start has acted on, from the event log in
+~/.kit/watch-events.jsonl. The log is rotated at 5MB, keeping three
+old files.
+
+--retry runs the action again for each failed file that hasn't been
+processed since, using the rules of the last "kit watch start". Retries
+are logged too.
+
+Example:
+  kit watch log --since 1h --status error
+  kit watch log --rule invoices --last 20
+  kit watch log --since 1d --retry`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configDir := w.DefaultConfigDir()
+			eventLog := w.NewEventLog(configDir)
+			events, err := eventLog.Read()
+			if err != nil {
+				return fmt.Errorf("could not read event log: %w", err)
+			}
+
+			filter := w.EventFilter{Status: status, RuleID: rule, Path: path}
+			if since != "" {
+				if filter.Since, err = graph.ParseSince(since, time.Now()); err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+			}
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			if retry {
+				config, err := w.LoadConfig(configDir)
+				if err != nil {
+					return fmt.Errorf("no watcher configuration found (run 'kit watch start' first)")
+				}
+				failed := w.FilterEvents(w.Unresolved(events), filter)
+				results := make([]w.Event, 0, len(failed))
+				for _, e := range failed {
+					evt := w.Retry(cmd.Context(), *config, e)
+					if err := eventLog.Append(evt); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: could not log retry: %v\n", err)
+					}
+					results = append(results, evt)
+				}
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(results)
+				}
+				if len(results) == 0 {
+					fmt.Println("No failed events to retry.")
+					return nil
+				}
+				fixed := 0
+				for _, e := range results {
+					if e.Status == "error" {
+						fmt.Printf("✗ %s: %s\n", e.Path, e.Error)
+					} else {
+						fixed++
+						fmt.Printf("✓ %s\n", e.Path)
+					}
+				}
+				fmt.Printf("\nRetried %d event(s): %d processed, %d failed\n", len(results), fixed, len(results)-fixed)
+				return nil
+			}
+
+			filtered := w.FilterEvents(events, filter)
+			if last > 0 && len(filtered) > last {
+				filtered = filtered[len(filtered)-last:]
+			}
+
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(filtered)
+			}
+
+			if len(filtered) == 0 {
+				fmt.Println("No watch events recorded.")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "TIME\tRULE\tACTION\tSTATUS\tFILE\n")
+			for _, e := range filtered {
+				result := e.Status
+				if e.Error != "" {
+					result += ": " + e.Error
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+					e.Time.Local().Format("2006-01-02 15:04:05"), e.RuleID, e.Action, result, e.Path)
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only events since an age (1h, 7d) or date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&status, "status", "", "Only events with this status: processed, error")
+	cmd.Flags().StringVar(&rule, "rule", "", "Only events of this rule ID")
+	cmd.Flags().StringVar(&path, "path", "", "Only events for files whose path contains this text")
+	cmd.Flags().IntVar(&last, "last", 0, "Show only the last N events")
+	cmd.Flags().BoolVar(&retry, "retry", false, "Run the action again for failed events that are still unresolved")
+
+	return cmd
+}
+
+func newFailedCmd() *cobra.Command {
+	var (
+		rule      string
+		reprocess bool
+		clearList bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "failed",
+		Short: "List files whose actions kept failing, and reprocess them",
+		Long: `List the dead-letter list in ~/.kit/watch-failed.json: files whose rule
+failed even after the retries of its actions.
+
+--reprocess runs each file's rule again, using the rules of the last
+"kit watch start". Files that succeed leave the list; the rest stay, with
+their failure counted. Reprocessing is logged like other watch events.
+--clear empties the list without running anything.
+
+Example:
+  kit watch failed
+  kit watch failed --reprocess
+  kit watch failed --rule invoices --clear`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configDir := w.DefaultConfigDir()
+			failed := w.NewDeadLetters(configDir)
+			list, err := failed.Load()
+			if err != nil {
+				return fmt.Errorf("could not read dead-letter list: %w", err)
+			}
+			if rule != "" {
+				var kept []w.DeadLetter
+				for _, dl := range list {
+					if dl.RuleID == rule {
+						kept = append(kept, dl)
+					}
+				}
+				list = kept
+			}
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			switch {
+			case clearList:
+				for _, dl := range list {
+					if err := failed.Remove(dl); err != nil {
+						return err
+					}
+				}
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]any{"cleared": len(list)})
+				}
+				fmt.Printf("Cleared %d failed file(s)\n", len(list))
+				return nil
+
+			case reprocess:
+				config, err := w.LoadConfig(configDir)
+				if err != nil {
+					return fmt.Errorf("no watcher configuration found (run 'kit watch start' first)")
+				}
+				eventLog := w.NewEventLog(configDir)
+				results := make([]w.Event, 0, len(list))
+				for _, dl := range list {
+					evt, err := failed.Reprocess(cmd.Context(), *config, dl)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: could not update dead-letter list: %v\n", err)
+					}
+					if err := eventLog.Append(evt); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: could not log reprocessing: %v\n", err)
+					}
+					results = append(results, evt)
+				}
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(results)
+				}
+				if len(results) == 0 {
+					fmt.Println("No failed files to reprocess.")
+					return nil
+				}
+				fixed := 0
+				for _, e := range results {
+					if e.Status == "error" {
+						fmt.Printf("✗ %s: %s\n", e.Path, e.Error)
+					} else {
+						fixed++
+						fmt.Printf("✓ %s\n", e.Path)
+					}
+				}
+				fmt.Printf("\nReprocessed %d file(s): %d processed, %d still failing\n", len(results), fixed, len(results)-fixed)
+				return nil
+			}
+
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if list == nil {
+					list = []w.DeadLetter{}
+				}
+				return enc.Encode(list)
+			}
+			if len(list) == 0 {
+				fmt.Println("No failed files.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "LAST FAILED\tRULE\tFAILURES\tFILE\tERROR\n")
+			for _, dl := range list {
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n",
+					dl.LastAt.Local().Format("2006-01-02 15:04:05"), dl.RuleID, dl.Failures, dl.Path, dl.Error)
+			}
+			tw.Flush()
+			fmt.Println("\nRun 'kit watch failed --reprocess' to try them again.")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rule, "rule", "", "Only files of this rule ID")
+	cmd.Flags().BoolVar(&reprocess, "reprocess", false, "Run the rule again for each failed file")
+	cmd.Flags().BoolVar(&clearList, "clear", false, "Remove the files from the list without running anything")
+	cmd.MarkFlagsMutuallyExclusive("reprocess", "clear")
+
+	return cmd
+}
+
+func newMailCmd() *cobra.Command {
+	var (
+		filters   []string
+		action    string
+		folder    string
+		mailbox   string
+		interval  time.Duration
+		listen    string
+		publicURL string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mail",
+		Short: "Run a kit command for each new email matching a filter",
+		Long: `Watch a mailbox for new email and run a kit command for each message
+that matches --filter. The message ID is passed with --id unless the
+action uses the {id} placeholder; {subject} and {from} are also
+replaced, and KIT_MESSAGE_ID, KIT_MESSAGE_SUBJECT and KIT_MESSAGE_FROM
+are set for the command.
+
+Filters: from:<text>, subject:<text>, has:attachment, is:unread. Quote
+values with spaces: subject:"daily report".
+
+By default the folder is polled every --interval. With --listen and
+--public-url, kit subscribes to Graph change notifications instead and
+reacts within seconds; --public-url must be an HTTPS address that
+reaches --listen (e.g. a tunnel). If Graph rejects the subscription,
+kit falls back to polling.
+
+Example:
+  kit watch mail --filter from:alerts@ --action "outlook download -o ./inbound"
+  kit watch mail --filter "has:attachment subject:invoice" --folder Invoices --action "outlook download"
+  kit watch mail --filter from:ceo@ --listen :8080 --public-url https://abc.ngrok.app --action "outlook read"`,
+		Annotations: map[string]string{auth.ScopesAnnotation: "Mail.Read"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(action) == "" {
+				return fmt.Errorf("--action is required")
+			}
+			if (listen == "") != (publicURL == "") {
+				return fmt.Errorf("--listen and --public-url must be used together")
+			}
+			filter, err := w.ParseMailFilter(filters)
+			if err != nil {
+				return err
+			}
+			exe, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("could not locate kit: %w", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client, err := auth.RequireAuth(ctx)
+			if err != nil {
+				return err
+			}
+			o := graph.NewOutlook(client)
+			o.Mailbox = mailbox
+
+			folderID := ""
+			if folder != "" {
+				f, err := o.ResolveFolder(ctx, folder)
+				if err != nil {
+					return err
+				}
+				folderID = f.ID
+			}
+
+			watcher := w.NewMailWatcher(o, folderID, filter)
+			watcher.Interval = interval
+			watcher.Handler = func(ctx context.Context, msg graph.EmailMessage) error {
+				watcher.Logger.Printf("New mail from %s: %s", msg.From.EmailAddress.Address, msg.Subject)
+				err := runMailAction(ctx, exe, action, msg)
+				hook := watchHook{Source: "mail", Message: &mailHook{ID: msg.ID, From: msg.From.EmailAddress.Address, Subject: msg.Subject, Action: action, Status: "processed"}}
+				if err != nil {
+					hook.Message.Status, hook.Message.Error = "error", err.Error()
+				}
+				plugin.RunHooks(ctx, plugin.HookOnWatchEvent, hook)
+				return err
+			}
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-sigCh
+				fmt.Println("\nStopping mail watcher...")
+				cancel()
+			}()
+
+			fmt.Printf("Watching %s for new mail → kit %s\n", describeFolder(folder, mailbox), action)
+			fmt.Println("Press Ctrl+C to stop")
+
+			if listen != "" {
+				return watcher.Listen(ctx, listen, publicURL)
+			}
+			return watcher.Run(ctx)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&filters, "filter", nil, "Only act on mail matching these terms (from:, subject:, has:attachment, is:unread)")
+	cmd.Flags().StringVar(&action, "action", "", "kit command to run for each message (e.g. \"outlook download\")")
+	cmd.Flags().StringVar(&folder, "folder", "", "Mail folder by name, path, or ID (default: inbox)")
+	cmd.Flags().StringVar(&mailbox, "mailbox", "", "Watch a shared or delegated mailbox (address or user ID)")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "How often to poll for new mail")
+	cmd.Flags().StringVar(&listen, "listen", "", "Address to receive Graph notifications on (e.g. :8080)")
+	cmd.Flags().StringVar(&publicURL, "public-url", "", "Public HTTPS URL that reaches --listen")
+
+	return cmd
+}
+
+func describeFolder(folder, mailbox string) string {
+	if folder == "" {
+		folder = "inbox"
+	}
+	if mailbox != "" {
+		return mailbox + " " + folder
+	}
+	return folder
+}
+
+// watchHook is the data passed to on-watch-event plugin hooks: a file
+// event from kit watch start, or a message from kit watch mail.
+type watchHook struct {
+	Source  string    `json:"source"` // "file" or "mail"
+	Event   *w.Event  `json:"event,omitempty"`
+	Message *mailHook `json:"message,omitempty"`
+}
+
+type mailHook struct {
+	ID      string `json:"id"`
+	From    string `json:"from"`
+	Subject string `json:"subject"`
+	Action  string `json:"action"`
+	Status  string `json:"status"` // "processed" or "error"
+	Error   string `json:"error,omitempty"`
+}
+
+// runMailAction runs action as a kit subcommand for msg.
+func runMailAction(ctx context.Context, exe, action string, msg graph.EmailMessage) error {
+	args := mailActionArgs(action, msg)
+	c := exec.CommandContext(ctx, exe, args...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		"KIT_MESSAGE_ID="+msg.ID,
+		"KIT_MESSAGE_SUBJECT="+msg.Subject,
+		"KIT_MESSAGE_FROM="+msg.From.EmailAddress.Address,
+	)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("kit %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// mailActionArgs splits action into arguments and fills in the message
+// placeholders, appending --id when the action doesn't use {id}.
+func mailActionArgs(action string, msg graph.EmailMessage) []string {
+	replacer := strings.NewReplacer(
+		"{id}", msg.ID,
+		"{subject}", msg.Subject,
+		"{from}", msg.From.EmailAddress.Address,
+	)
+	fields := strings.Fields(action)
+	if len(fields) > 0 && fields[0] == "kit" {
+		fields = fields[1:]
+	}
+	args := make([]string, len(fields))
+	for i, f := range fields {
+		args[i] = replacer.Replace(f)
+	}
+	if !strings.Contains(action, "{id}") {
+		args = append(args, "--id", msg.ID)
+	}
+	return args
+}
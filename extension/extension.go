@@ -0,0 +1,125 @@
+// Package extension adds document formats to kit in-process, for companies
+// building their own kit distribution. Unlike exec plugins, extensions are
+// compiled in and run without starting a subprocess.
+//
+// Register converters and formatters from an init function in your own
+// main package, then run the kit CLI:
+//
+//	package main
+//
+//	import (
+//		"github.com/klytics/m365kit/cmd"
+//		"github.com/klytics/m365kit/extension"
+//	)
+//
+//	func init() {
+//		extension.RegisterConverter("rtf", "docx", rtfToDocx)
+//		extension.RegisterFormatter("confluence", renderConfluence)
+//	}
+//
+//	func main() {
+//		cmd.Execute()
+//	}
+//
+// kit convert then accepts the new formats:
+//
+//	kit convert letter.rtf --to docx
+//	kit convert spec.docx --to confluence
+package extension
+
+import (
+	"github.com/klytics/m365kit/internal/formats/convert"
+	"github.com/klytics/m365kit/internal/formats/docx"
+)
+
+// A Converter converts the file at input. It either writes output itself
+// and returns "", or returns the converted text, which kit writes to
+// output, or prints when output is "".
+type Converter func(input, output string) (string, error)
+
+// RegisterConverter adds a conversion between two formats, named by file
+// extension without the dot (e.g. "rtf"). It replaces a built-in
+// conversion of the same formats. Call it before running the CLI; it
+// panics when a format is empty or c is nil.
+func RegisterConverter(from, to string, c Converter) {
+	convert.Register(from, to, convert.Converter(c))
+}
+
+// A Formatter renders a document as text in its format.
+type Formatter func(doc *Document) (string, error)
+
+// RegisterFormatter adds the output format name for every document kit
+// can read (.docx, Markdown, and HTML): kit parses the input and hands
+// the formatter its content. It panics when name is empty or f is nil.
+func RegisterFormatter(name string, f Formatter) {
+	if f == nil {
+		panic("extension: nil formatter " + name)
+	}
+	c := func(input, _ string) (string, error) {
+		doc, err := convert.ParseDocument(input)
+		if err != nil {
+			return "", err
+		}
+		return f(fromDocx(doc))
+	}
+	for _, from := range convert.DocumentFormats {
+		convert.Register(from, name, c)
+	}
+}
+
+// Conversions returns the conversions added by extensions, as
+// "from → to".
+func Conversions() []string {
+	return convert.Registered()
+}
+
+// BlockKind is the kind of a document block.
+type BlockKind string
+
+const (
+	Heading   BlockKind = "heading"
+	Paragraph BlockKind = "paragraph"
+	ListItem  BlockKind = "list_item"
+	Table     BlockKind = "table"
+)
+
+// Document is the content of a document, as handed to formatters.
+type Document struct {
+	Title  string
+	Author string
+	Blocks []Block
+}
+
+// Block is a heading, paragraph, list item, or table.
+type Block struct {
+	Kind  BlockKind
+	Text  string     // empty for tables
+	Level int        // heading level (1-9), or list nesting level
+	Rows  [][]string // table cells, row by row
+}
+
+func fromDocx(d *docx.Document) *Document {
+	doc := &Document{Title: d.Metadata.Title, Author: d.Metadata.Creator}
+	for _, n := range d.Nodes {
+		b := Block{Text: n.Text, Level: n.Level}
+		switch n.Type {
+		case docx.NodeHeading:
+			b.Kind = Heading
+		case docx.NodeListItem:
+			b.Kind = ListItem
+		case docx.NodeTable:
+			b.Kind, b.Text = Table, ""
+			for _, row := range n.Children {
+				cells := make([]string, 0, len(row.Children))
+				for _, cell := range row.Children {
+					cells = append(cells, cell.Text)
+				}
+				b.Rows = append(b.Rows, cells)
+			}
+		default:
+			b.Kind = Paragraph
+		}
+		doc.Blocks = append(doc.Blocks, b)
+	}
+	return doc
+}
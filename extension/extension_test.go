@@ -0,0 +1,82 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klytics/m365kit/internal/formats/convert"
+)
+
+func TestRegisterFormatter(t *testing.T) {
+	var got *Document
+	RegisterFormatter("outline-test", func(doc *Document) (string, error) {
+		got = doc
+		var b strings.Builder
+		for _, blk := range doc.Blocks {
+			if blk.Kind == Heading {
+				b.WriteString(strings.Repeat("  ", blk.Level-1) + blk.Text + "\n")
+			}
+		}
+		return b.String(), nil
+	})
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "spec.md")
+	os.WriteFile(in, []byte("# Spec\n\nIntro.\n\n## Scope\n\n- one\n\n| a | b |\n|---|---|\n| 1 | 2 |\n"), 0644)
+
+	out := filepath.Join(dir, "spec.outline")
+	result, err := convert.Convert(in, out, "outline-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "Spec\n  Scope\n" {
+		t.Errorf("result = %q", result)
+	}
+	if data, _ := os.ReadFile(out); string(data) != result {
+		t.Errorf("output file = %q", data)
+	}
+
+	kinds := make(map[BlockKind]int)
+	for _, b := range got.Blocks {
+		kinds[b.Kind]++
+		if b.Kind == Table && (len(b.Rows) != 2 || b.Rows[1][1] != "2") {
+			t.Errorf("table rows = %q", b.Rows)
+		}
+	}
+	if kinds[Heading] != 2 || kinds[Paragraph] != 1 || kinds[ListItem] != 1 || kinds[Table] != 1 {
+		t.Errorf("blocks = %+v", got.Blocks)
+	}
+}
+
+func TestRegisterConverter(t *testing.T) {
+	RegisterConverter("shout-test", "txt", func(input, output string) (string, error) {
+		data, err := os.ReadFile(input)
+		return strings.ToUpper(string(data)), err
+	})
+
+	in := filepath.Join(t.TempDir(), "note.shout-test")
+	os.WriteFile(in, []byte("hello"), 0644)
+	result, err := convert.Convert(in, "", "txt")
+	if err != nil || result != "HELLO" {
+		t.Fatalf("Convert = %q, %v", result, err)
+	}
+
+	found := false
+	for _, c := range Conversions() {
+		found = found || c == "shout-test → txt"
+	}
+	if !found {
+		t.Errorf("Conversions() = %q", Conversions())
+	}
+}
+
+func TestRegisterConverterInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an empty format")
+		}
+	}()
+	RegisterConverter("", "txt", func(string, string) (string, error) { return "", nil })
+}
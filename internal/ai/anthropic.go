@@ -159,7 +159,7 @@ func (p *AnthropicProvider) Stream(ctx context.Context, system string, messages
 	req.Header.Set("x-api-key", p.apiKey)
 	req.Header.Set("anthropic-version", anthropicAPIVersion)
 
-	resp, err := p.client.Do(req)
+	resp, err := streamClient(p.client).Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -195,11 +195,18 @@ func (p *AnthropicProvider) Stream(ctx context.Context, system string, messages
 					Type string `json:"type"`
 					Text string `json:"text"`
 				} `json:"delta"`
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
 			}
 
 			if err := json.Unmarshal([]byte(data), &event); err != nil {
 				continue
 			}
+			if event.Type == "error" {
+				errCh <- fmt.Errorf("API error: %s", event.Error.Message)
+				return
+			}
 
 			if event.Type == "content_block_delta" && event.Delta.Text != "" {
 				select {
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultAzureAPIVersion = "2024-06-01"
+
+// AzureOpenAIConfig locates an Azure OpenAI resource and its deployments.
+type AzureOpenAIConfig struct {
+	// Endpoint is the resource URL, like https://contoso.openai.azure.com.
+	Endpoint string
+	APIKey   string
+	// APIVersion is the api-version query parameter; empty uses
+	// 2024-06-01.
+	APIVersion string
+	// Deployments maps capabilities to deployment names; "default" is
+	// used when no model is given.
+	Deployments map[Capability]string
+}
+
+// AzureOpenAIProvider implements the Provider interface for models
+// deployed in Azure OpenAI. The API is OpenAI's, but addressed by
+// deployment rather than model name.
+type AzureOpenAIProvider struct {
+	*OpenAIProvider
+}
+
+// NewAzureOpenAIProvider creates a provider for the Azure OpenAI resource
+// in cfg. model is a deployment name or a capability (see Capability); ""
+// means the default deployment.
+func NewAzureOpenAIProvider(cfg AzureOpenAIConfig, model string) (*AzureOpenAIProvider, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("no Azure OpenAI endpoint — set AZURE_OPENAI_ENDPOINT or azure_openai.endpoint in ~/.kit/config.yaml")
+	}
+	deployment := model
+	if model == "" || IsCapability(model) {
+		c := Capability(model)
+		if model == "" {
+			c = CapabilityDefault
+		}
+		deployment = cfg.Deployments[c]
+		if deployment == "" {
+			deployment = cfg.Deployments[CapabilityDefault]
+		}
+		if deployment == "" {
+			return nil, fmt.Errorf("no Azure OpenAI deployment for %q — set AZURE_OPENAI_DEPLOYMENT or azure_openai.deployment (or azure_openai.deployments.%s) in ~/.kit/config.yaml", c, c)
+		}
+	}
+	version := cfg.APIVersion
+	if version == "" {
+		version = defaultAzureAPIVersion
+	}
+	endpoint := strings.TrimRight(cfg.Endpoint, "/")
+
+	return &AzureOpenAIProvider{&OpenAIProvider{
+		apiKey: cfg.APIKey,
+		model:  deployment,
+		client: &http.Client{Timeout: 120 * time.Second},
+		url: func(deployment string) string {
+			return endpoint + "/openai/deployments/" + url.PathEscape(deployment) +
+				"/chat/completions?api-version=" + url.QueryEscape(version)
+		},
+		authorize: func(req *http.Request) {
+			req.Header.Set("api-key", cfg.APIKey)
+		},
+	}}, nil
+}
+
+// Name returns the provider identifier.
+func (p *AzureOpenAIProvider) Name() string {
+	return "azure-openai"
+}
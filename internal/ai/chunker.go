@@ -1,7 +1,9 @@
 package ai
 
 import (
+	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -9,6 +11,10 @@ const (
 	DefaultChunkSize = 100000
 	// DefaultChunkOverlap is the number of overlapping characters between chunks.
 	DefaultChunkOverlap = 500
+
+	// charsPerToken is the rough number of characters in a token, used to
+	// estimate sizes without a tokenizer for every provider.
+	charsPerToken = 4
 )
 
 // ChunkOptions configures how documents are split into chunks.
@@ -17,51 +23,129 @@ type ChunkOptions struct {
 	Overlap      int
 }
 
+// EstimateTokens returns a rough token count for text.
+func EstimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// TokensToChars converts a token count to the characters ChunkOptions
+// measures in.
+func TokensToChars(tokens int) int {
+	return tokens * charsPerToken
+}
+
+// Chunk is one part of a document.
+type Chunk struct {
+	Text string
+	// Heading is the Markdown heading of the section the chunk starts in,
+	// when it doesn't start with one itself, so each part has its context.
+	Heading string
+}
+
+// headingRe matches a Markdown heading line, as kit word read prints them.
+var headingRe = regexp.MustCompile(`(?m)^#{1,6} .*$`)
+
 // ChunkText splits text into overlapping chunks for processing by AI models
 // that have input token limits.
 func ChunkText(text string, opts ChunkOptions) []string {
+	chunks := ChunkDocument(text, opts)
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = c.Text
+	}
+	return texts
+}
+
+// ChunkDocument splits text into chunks of at most opts.MaxChunkSize
+// characters. It breaks before a heading where it can, so sections stay
+// whole, then at a paragraph, sentence, line, or word; chunks that don't
+// start at a heading repeat the last opts.Overlap characters of the one
+// before.
+func ChunkDocument(text string, opts ChunkOptions) []Chunk {
 	if opts.MaxChunkSize <= 0 {
 		opts.MaxChunkSize = DefaultChunkSize
 	}
 	if opts.Overlap <= 0 {
 		opts.Overlap = DefaultChunkOverlap
 	}
+	// Each chunk must move past the last, and breaks are never in its
+	// first half
+	if opts.Overlap > opts.MaxChunkSize/4 {
+		opts.Overlap = opts.MaxChunkSize / 4
+	}
 
 	if len(text) <= opts.MaxChunkSize {
-		return []string{text}
+		return []Chunk{{Text: text}}
 	}
 
-	var chunks []string
+	var chunks []Chunk
 	start := 0
-
-	for start < len(text) {
+	for {
 		end := start + opts.MaxChunkSize
-		if end > len(text) {
+		atHeading := false
+		if end >= len(text) {
 			end = len(text)
+		} else {
+			end, atHeading = breakPoint(text, start, end, opts.MaxChunkSize/2)
 		}
 
-		// Try to break at a paragraph boundary
-		if end < len(text) {
-			breakPoint := strings.LastIndex(text[start:end], "\n\n")
-			if breakPoint > opts.MaxChunkSize/2 {
-				end = start + breakPoint + 2
-			} else {
-				// Fall back to sentence boundary
-				breakPoint = strings.LastIndex(text[start:end], ". ")
-				if breakPoint > opts.MaxChunkSize/2 {
-					end = start + breakPoint + 2
-				}
-			}
+		chunk := Chunk{Text: text[start:end]}
+		if !headingRe.MatchString(firstLine(chunk.Text)) {
+			chunk.Heading = headingBefore(text, start)
 		}
+		chunks = append(chunks, chunk)
 
-		chunks = append(chunks, text[start:end])
-
-		// Move start forward, accounting for overlap
-		start = end - opts.Overlap
-		if start >= len(text) {
+		if end == len(text) {
 			break
 		}
+		start = end
+		if !atHeading {
+			start = runeStart(text, end-opts.Overlap)
+		}
 	}
-
 	return chunks
 }
+
+// breakPoint returns where to end the chunk text[start:end]: before the last
+// heading, or after the last paragraph, sentence, line, or word, past min
+// characters in. It reports whether the break is before a heading.
+func breakPoint(text string, start, end, min int) (int, bool) {
+	window := text[start:end]
+
+	if locs := headingRe.FindAllStringIndex(window, -1); len(locs) > 0 {
+		if last := locs[len(locs)-1][0]; last > min {
+			return start + last, true
+		}
+	}
+	for _, sep := range []string{"\n\n", ". ", "\n", " "} {
+		if i := strings.LastIndex(window, sep); i > min {
+			return start + i + len(sep), false
+		}
+	}
+	return runeStart(text, end), false
+}
+
+// runeStart moves i back to the start of the UTF-8 sequence it falls in, so
+// chunks never split a character.
+func runeStart(text string, i int) int {
+	for i > 0 && i < len(text) && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
+
+// headingBefore returns the last heading line that starts before pos.
+func headingBefore(text string, pos int) string {
+	locs := headingRe.FindAllStringIndex(text[:pos], -1)
+	if len(locs) == 0 {
+		return ""
+	}
+	last := locs[len(locs)-1]
+	// The match may run past pos; take the whole line
+	return strings.TrimSpace(firstLine(text[last[0]:]))
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
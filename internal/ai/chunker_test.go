@@ -0,0 +1,166 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkTextShort(t *testing.T) {
+	chunks := ChunkText("short text", ChunkOptions{})
+	if len(chunks) != 1 || chunks[0] != "short text" {
+		t.Errorf("chunks = %q", chunks)
+	}
+}
+
+func TestChunkTextCoversText(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, "Sentence number %d is here. ", i)
+		if i%10 == 9 {
+			b.WriteString("\n\n")
+		}
+	}
+	text := b.String()
+
+	chunks := ChunkText(text, ChunkOptions{MaxChunkSize: 1000, Overlap: 100})
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if len(c) > 1000 {
+			t.Errorf("chunk %d has %d characters", i, len(c))
+		}
+	}
+	if !strings.HasPrefix(text, chunks[0]) || !strings.HasSuffix(text, chunks[len(chunks)-1]) {
+		t.Error("chunks don't start and end with the text")
+	}
+	// The last chunk isn't repeated as a tail of overlap
+	if last := chunks[len(chunks)-1]; len(last) <= 100 && strings.HasSuffix(chunks[len(chunks)-2], last) {
+		t.Error("last chunk is only overlap")
+	}
+}
+
+func TestChunkDocumentBreaksAtHeadings(t *testing.T) {
+	section := strings.Repeat("Some words in a paragraph. ", 20) + "\n\n"
+	text := "# Contract\n\n" + section + "## Payment Terms\n\n" + section + section + "## Termination\n\n" + section
+
+	chunks := ChunkDocument(text, ChunkOptions{MaxChunkSize: 1000, Overlap: 50})
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	if !strings.HasPrefix(chunks[1].Text, "## ") {
+		t.Errorf("second chunk should start at a heading, starts %q", firstLine(chunks[1].Text))
+	}
+	for _, c := range chunks[1:] {
+		if !strings.HasPrefix(c.Text, "#") && c.Heading == "" {
+			t.Errorf("chunk without heading context: %q", firstLine(c.Text))
+		}
+	}
+}
+
+func TestChunkDocumentHeadingContext(t *testing.T) {
+	text := "# Intro\n\nHi.\n\n## Liability\n\n" + strings.Repeat("The supplier is liable. ", 100)
+	chunks := ChunkDocument(text, ChunkOptions{MaxChunkSize: 800, Overlap: 40})
+	last := chunks[len(chunks)-1]
+	if last.Heading != "## Liability" {
+		t.Errorf("Heading = %q, want %q", last.Heading, "## Liability")
+	}
+}
+
+func TestChunkDocumentKeepsRunesWhole(t *testing.T) {
+	text := strings.Repeat("日本語のテキスト", 500)
+	for _, c := range ChunkText(text, ChunkOptions{MaxChunkSize: 1001, Overlap: 33}) {
+		if !utf8.ValidString(c) {
+			t.Fatal("chunk splits a character")
+		}
+	}
+}
+
+func TestChunkDocumentLargeOverlap(t *testing.T) {
+	// An overlap as big as the chunk must not stop chunking from advancing
+	text := strings.Repeat("word ", 1000)
+	chunks := ChunkText(text, ChunkOptions{MaxChunkSize: 200, Overlap: 500})
+	if len(chunks) > 50 {
+		t.Errorf("got %d chunks for 5000 characters", len(chunks))
+	}
+}
+
+// partsProvider answers each prompt with a short note naming the part.
+type partsProvider struct {
+	mu      sync.Mutex
+	systems []string
+	fail    int // fail the call for this part, if > 0
+}
+
+func (p *partsProvider) Name() string { return "parts" }
+
+func (p *partsProvider) Infer(ctx context.Context, system string, messages []Message, opts InferOptions) (*InferResult, error) {
+	p.mu.Lock()
+	p.systems = append(p.systems, system)
+	p.mu.Unlock()
+
+	var i, n int
+	fmt.Sscanf(messages[0].Content, "Part %d of %d", &i, &n)
+	if i == p.fail {
+		return nil, errors.New("rate limited")
+	}
+	return &InferResult{Content: fmt.Sprintf("notes %d/%d", i, n), InputTokens: 10, OutputTokens: 2}, nil
+}
+
+func (p *partsProvider) Stream(ctx context.Context, system string, messages []Message, opts InferOptions) (<-chan string, <-chan error, error) {
+	return nil, nil, errors.New("not streamed")
+}
+
+func TestCondenseFits(t *testing.T) {
+	p := &partsProvider{}
+	c, err := Condense(context.Background(), p, "a short document", MapReduceOptions{MapPrompt: "summarize"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Text != "a short document" || c.Chunks != 1 || len(p.systems) != 0 {
+		t.Errorf("condensed = %+v, %d calls", c, len(p.systems))
+	}
+}
+
+func TestCondenseMapsParts(t *testing.T) {
+	p := &partsProvider{}
+	text := strings.Repeat("A line of the contract.\n", 500)
+	c, err := Condense(context.Background(), p, text, MapReduceOptions{
+		Chunk:     ChunkOptions{MaxChunkSize: 2000, Overlap: 100},
+		MapPrompt: "summarize this part",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Chunks < 5 {
+		t.Fatalf("Chunks = %d", c.Chunks)
+	}
+	if len(p.systems) != c.Chunks || p.systems[0] != "summarize this part" {
+		t.Errorf("%d calls for %d chunks", len(p.systems), c.Chunks)
+	}
+	// Results are joined in document order
+	first := strings.Index(c.Text, fmt.Sprintf("notes 1/%d", c.Chunks))
+	last := strings.Index(c.Text, fmt.Sprintf("notes %d/%d", c.Chunks, c.Chunks))
+	if first < 0 || last < first {
+		t.Errorf("parts out of order:\n%s", c.Text)
+	}
+	if c.InputTokens != 10*c.Chunks || c.OutputTokens != 2*c.Chunks {
+		t.Errorf("tokens = %d/%d", c.InputTokens, c.OutputTokens)
+	}
+}
+
+func TestCondenseError(t *testing.T) {
+	p := &partsProvider{fail: 2}
+	text := strings.Repeat("A line of the contract.\n", 500)
+	_, err := Condense(context.Background(), p, text, MapReduceOptions{
+		Chunk: ChunkOptions{MaxChunkSize: 2000},
+	})
+	if err == nil || !strings.Contains(err.Error(), "part 2 of") {
+		t.Errorf("expected error naming the part, got %v", err)
+	}
+}
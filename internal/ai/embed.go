@@ -0,0 +1,414 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/klytics/m365kit/internal/config"
+)
+
+const (
+	openaiEmbeddingsURL      = "https://api.openai.com/v1/embeddings"
+	defaultOpenAIEmbedModel  = "text-embedding-3-small"
+	defaultGeminiEmbedModel  = "text-embedding-004"
+	defaultOllamaEmbedModel  = "nomic-embed-text"
+	localEmbedModel          = "hash-512"
+	localEmbedDims           = 512
+	embedBatchSize           = 64
+	azureEmbeddingDeployment = "embedding"
+)
+
+// Embedders lists the embedder names NewEmbedder accepts. "local" needs no
+// provider: it hashes words into vectors, so it matches wording rather than
+// meaning, but works offline and costs nothing.
+var Embedders = []string{"local", "openai", "azure-openai", "gemini", "ollama"}
+
+// Embedder turns text into vectors whose closeness reflects how related the
+// texts are.
+type Embedder interface {
+	// Name identifies the embedder and model, like "openai/text-embedding-3-small";
+	// vectors from different embedders can't be compared.
+	Name() string
+	// Embed returns one vector per text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewEmbedder creates the embedder for a provider name (see Embedders). model
+// is the embedding model, or "" for the provider's default; for Azure OpenAI
+// it is a deployment, defaulting to azure_openai.deployments.embedding.
+// Calls to a provider are recorded in the usage ledger, and, with redaction
+// on, texts are masked before they are sent.
+func NewEmbedder(name, model string) (Embedder, error) {
+	e, err := newEmbedder(name, model)
+	if err != nil {
+		return nil, err
+	}
+	if _, local := e.(localEmbedder); local {
+		return e, nil
+	}
+	e = meterEmbedder(e)
+	r, err := redactor()
+	if err != nil {
+		return nil, err
+	}
+	if r != nil {
+		e = &redactedEmbedder{Embedder: e, r: r}
+	}
+	return e, nil
+}
+
+func newEmbedder(name, model string) (Embedder, error) {
+	switch name = strings.ToLower(name); name {
+	case "local":
+		return localEmbedder{}, nil
+	case "openai":
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
+		}
+		if model == "" {
+			model = defaultOpenAIEmbedModel
+		}
+		return &openaiEmbedder{
+			name:   "openai/" + model,
+			url:    openaiEmbeddingsURL,
+			model:  model,
+			client: &http.Client{Timeout: 120 * time.Second},
+			authorize: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer "+apiKey)
+			},
+		}, nil
+	case "azure-openai":
+		cfg, err := azureConfig()
+		if err != nil {
+			return nil, err
+		}
+		if model == "" {
+			model = os.Getenv("AZURE_OPENAI_EMBEDDING_DEPLOYMENT")
+		}
+		if model == "" {
+			model = cfg.Deployments[azureEmbeddingDeployment]
+		}
+		if model == "" {
+			return nil, fmt.Errorf("no Azure OpenAI embedding deployment — set AZURE_OPENAI_EMBEDDING_DEPLOYMENT or azure_openai.deployments.embedding in ~/.kit/config.yaml")
+		}
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("no Azure OpenAI endpoint — set AZURE_OPENAI_ENDPOINT or azure_openai.endpoint in ~/.kit/config.yaml")
+		}
+		version := cfg.APIVersion
+		if version == "" {
+			version = defaultAzureAPIVersion
+		}
+		return &openaiEmbedder{
+			name: "azure-openai/" + model,
+			url: strings.TrimRight(cfg.Endpoint, "/") + "/openai/deployments/" + url.PathEscape(model) +
+				"/embeddings?api-version=" + url.QueryEscape(version),
+			client: &http.Client{Timeout: 120 * time.Second},
+			authorize: func(req *http.Request) {
+				req.Header.Set("api-key", cfg.APIKey)
+			},
+		}, nil
+	case "gemini":
+		apiKey, err := config.GetAPIKey("gemini")
+		if err != nil {
+			return nil, err
+		}
+		if model == "" {
+			model = defaultGeminiEmbedModel
+		}
+		return &geminiEmbedder{apiKey: apiKey, model: model, baseURL: geminiAPIURL, client: &http.Client{Timeout: 120 * time.Second}}, nil
+	case "ollama":
+		host := os.Getenv("OLLAMA_HOST")
+		if host == "" {
+			host = "http://localhost:11434"
+		}
+		if model == "" {
+			model = defaultOllamaEmbedModel
+		}
+		return &ollamaEmbedder{host: host, model: model, client: &http.Client{Timeout: 300 * time.Second}}, nil
+	case "anthropic":
+		return nil, fmt.Errorf("Anthropic has no embeddings API — use --embedder with one of: %s", strings.Join(Embedders, ", "))
+	default:
+		return nil, fmt.Errorf("unknown embedder %q — supported embedders: %s", name, strings.Join(Embedders, ", "))
+	}
+}
+
+// embedInBatches calls embed on texts in batches of at most size.
+func embedInBatches(ctx context.Context, texts []string, size int, embed func(context.Context, []string) ([][]float32, error)) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += size {
+		end := min(start+size, len(texts))
+		batch, err := embed(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("embedding API returned %d vectors for %d texts", len(batch), end-start)
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// postJSON posts body as JSON and decodes the response into out.
+func postJSON(ctx context.Context, client *http.Client, endpoint string, body any, authorize func(*http.Request), out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("could not marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorize != nil {
+		authorize(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("could not parse response: %w", err)
+	}
+	return nil
+}
+
+// openaiEmbedder calls the OpenAI embeddings API, or Azure OpenAI's.
+type openaiEmbedder struct {
+	name      string
+	url       string
+	model     string
+	client    *http.Client
+	authorize func(*http.Request)
+}
+
+func (e *openaiEmbedder) Name() string { return e.name }
+
+func (e *openaiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return embedInBatches(ctx, texts, embedBatchSize, func(ctx context.Context, batch []string) ([][]float32, error) {
+		reqBody := map[string]any{"input": batch}
+		if e.model != "" {
+			reqBody["model"] = e.model
+		}
+		var resp struct {
+			Data []struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			} `json:"data"`
+		}
+		if err := postJSON(ctx, e.client, e.url, reqBody, e.authorize, &resp); err != nil {
+			return nil, err
+		}
+		vectors := make([][]float32, len(batch))
+		for _, d := range resp.Data {
+			if d.Index < 0 || d.Index >= len(vectors) {
+				return nil, fmt.Errorf("embedding API returned index %d for %d texts", d.Index, len(batch))
+			}
+			vectors[d.Index] = d.Embedding
+		}
+		for i, v := range vectors {
+			if v == nil {
+				return nil, fmt.Errorf("embedding API returned no vector for text %d", i)
+			}
+		}
+		return vectors, nil
+	})
+}
+
+// geminiEmbedder calls Gemini's batchEmbedContents.
+type geminiEmbedder struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+func (e *geminiEmbedder) Name() string { return "gemini/" + e.model }
+
+func (e *geminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return embedInBatches(ctx, texts, embedBatchSize, func(ctx context.Context, batch []string) ([][]float32, error) {
+		type request struct {
+			Model   string        `json:"model"`
+			Content geminiContent `json:"content"`
+		}
+		var reqBody struct {
+			Requests []request `json:"requests"`
+		}
+		for _, t := range batch {
+			reqBody.Requests = append(reqBody.Requests, request{
+				Model:   "models/" + e.model,
+				Content: geminiContent{Parts: []geminiPart{{Text: t}}},
+			})
+		}
+		var resp struct {
+			Embeddings []struct {
+				Values []float32 `json:"values"`
+			} `json:"embeddings"`
+		}
+		endpoint := e.baseURL + url.PathEscape(e.model) + ":batchEmbedContents"
+		authorize := func(req *http.Request) { req.Header.Set("x-goog-api-key", e.apiKey) }
+		if err := postJSON(ctx, e.client, endpoint, reqBody, authorize, &resp); err != nil {
+			return nil, err
+		}
+		vectors := make([][]float32, len(resp.Embeddings))
+		for i, emb := range resp.Embeddings {
+			vectors[i] = emb.Values
+		}
+		return vectors, nil
+	})
+}
+
+// ollamaEmbedder calls a local Ollama server's /api/embed.
+type ollamaEmbedder struct {
+	host   string
+	model  string
+	client *http.Client
+}
+
+func (e *ollamaEmbedder) Name() string { return "ollama/" + e.model }
+
+func (e *ollamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return embedInBatches(ctx, texts, embedBatchSize, func(ctx context.Context, batch []string) ([][]float32, error) {
+		var resp struct {
+			Embeddings [][]float32 `json:"embeddings"`
+		}
+		err := postJSON(ctx, e.client, e.host+"/api/embed", map[string]any{"model": e.model, "input": batch}, nil, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("Ollama at %s: %w", e.host, err)
+		}
+		return resp.Embeddings, nil
+	})
+}
+
+// localEmbedder hashes each text's words and word pairs into a fixed-size
+// vector (the "hashing trick"), weighting repeated terms sublinearly. It
+// finds passages that share a query's words, in any order, without a model.
+type localEmbedder struct{}
+
+func (localEmbedder) Name() string { return "local/" + localEmbedModel }
+
+func (localEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, t := range texts {
+		vectors[i] = hashEmbed(t)
+	}
+	return vectors, nil
+}
+
+func hashEmbed(text string) []float32 {
+	counts := make(map[string]int)
+	words := termsOf(text)
+	for i, w := range words {
+		counts[w]++
+		if i > 0 {
+			counts[words[i-1]+" "+w]++
+		}
+	}
+
+	v := make([]float32, localEmbedDims)
+	for term, n := range counts {
+		h := fnv.New32a()
+		h.Write([]byte(term))
+		sum := h.Sum32()
+		weight := float32(1 + math.Log(float64(n)))
+		// The sign bit spreads collisions out rather than piling them up
+		if sum&(1<<31) != 0 {
+			weight = -weight
+		}
+		v[sum%localEmbedDims] += weight
+	}
+	Normalize(v)
+	return v
+}
+
+// termsOf returns text's words, lowercased and lightly stemmed, without
+// common stop words.
+func termsOf(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if stopWords[f] {
+			continue
+		}
+		terms = append(terms, stem(f))
+	}
+	return terms
+}
+
+// stem strips common English suffixes so "obligations" matches
+// "obligation" and "indemnified" matches "indemnify".
+func stem(w string) string {
+	for _, suffix := range []string{"ies", "ied", "ing", "ed", "es", "s"} {
+		if len(w) > len(suffix)+3 && strings.HasSuffix(w, suffix) {
+			w = strings.TrimSuffix(w, suffix)
+			if suffix == "ies" || suffix == "ied" {
+				w += "y"
+			}
+			return w
+		}
+	}
+	return w
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "were": true, "will": true, "with": true,
+}
+
+// Normalize scales v to unit length, so a dot product is cosine similarity.
+func Normalize(v []float32) {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+}
+
+// Cosine returns the cosine similarity of two vectors of the same length.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
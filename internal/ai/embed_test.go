@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klytics/m365kit/internal/usage"
+)
+
+func TestLocalEmbedder(t *testing.T) {
+	e, err := NewEmbedder("local", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vectors, err := e.Embed(context.Background(), []string{
+		"Indemnification obligations of the supplier",
+		"The supplier's obligation to indemnify the customer against claims",
+		"Quarterly revenue forecast for EMEA",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 3 || len(vectors[0]) != localEmbedDims {
+		t.Fatalf("got %d vectors of %d dimensions", len(vectors), len(vectors[0]))
+	}
+	related, unrelated := Cosine(vectors[0], vectors[1]), Cosine(vectors[0], vectors[2])
+	if related <= unrelated {
+		t.Errorf("related texts scored %.2f, unrelated %.2f", related, unrelated)
+	}
+	if e.Name() != "local/hash-512" {
+		t.Errorf("Name() = %q", e.Name())
+	}
+}
+
+func TestNewEmbedderErrors(t *testing.T) {
+	if _, err := NewEmbedder("anthropic", ""); err == nil || !strings.Contains(err.Error(), "no embeddings API") {
+		t.Errorf("expected error for anthropic, got %v", err)
+	}
+	if _, err := NewEmbedder("word2vec", ""); err == nil || !strings.Contains(err.Error(), "ollama") {
+		t.Errorf("expected error listing embedders, got %v", err)
+	}
+}
+
+func TestOpenAIEmbedder(t *testing.T) {
+	var got struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		// Answer out of order: vectors are placed by index
+		fmt.Fprint(w, `{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`)
+	}))
+	defer srv.Close()
+
+	ledger := filepath.Join(t.TempDir(), "usage.jsonl")
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("KIT_REDACT", "0")
+	t.Setenv("KIT_USAGE_LOG", ledger)
+	e, err := NewEmbedder("openai", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.(*meteredEmbedder).Embedder.(*openaiEmbedder).url = srv.URL
+
+	vectors, err := e.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("vectors = %v", vectors)
+	}
+	if got.Model != defaultOpenAIEmbedModel || len(got.Input) != 2 || auth != "Bearer sk-test" {
+		t.Errorf("request = %+v, Authorization %q", got, auth)
+	}
+	if e.Name() != "openai/text-embedding-3-small" {
+		t.Errorf("Name() = %q", e.Name())
+	}
+
+	// The call is metered like a provider's, so kit ai usage reports it
+	records, _ := usage.Read(ledger)
+	if len(records) != 1 || records[0].Provider != "openai" || records[0].Model != "text-embedding-3-small" || records[0].InputTokens == 0 {
+		t.Errorf("usage records = %+v", records)
+	}
+}
+
+func TestEmbedInBatches(t *testing.T) {
+	var sizes []int
+	texts := make([]string, 150)
+	vectors, err := embedInBatches(context.Background(), texts, embedBatchSize, func(ctx context.Context, batch []string) ([][]float32, error) {
+		sizes = append(sizes, len(batch))
+		return make([][]float32, len(batch)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 150 || fmt.Sprint(sizes) != "[64 64 22]" {
+		t.Errorf("%d vectors in batches %v", len(vectors), sizes)
+	}
+}
+
+func TestGeminiEmbedder(t *testing.T) {
+	var path, key string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, key = r.URL.Path, r.Header.Get("x-goog-api-key")
+		fmt.Fprint(w, `{"embeddings":[{"values":[0.5,0.5]}]}`)
+	}))
+	defer srv.Close()
+
+	e := &geminiEmbedder{apiKey: "g-key", model: defaultGeminiEmbedModel, baseURL: srv.URL + "/v1beta/models/", client: srv.Client()}
+	vectors, err := e.Embed(context.Background(), []string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 1 || vectors[0][1] != 0.5 {
+		t.Errorf("vectors = %v", vectors)
+	}
+	if path != "/v1beta/models/text-embedding-004:batchEmbedContents" || key != "g-key" {
+		t.Errorf("path %q, key %q", path, key)
+	}
+}
+
+func TestOllamaEmbedder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"embeddings":[[1,2,3]]}`)
+	}))
+	defer srv.Close()
+
+	t.Setenv("OLLAMA_HOST", srv.URL)
+	t.Setenv("KIT_USAGE_LOG", "off")
+	e, err := NewEmbedder("ollama", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vectors, err := e.Embed(context.Background(), []string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 1 || len(vectors[0]) != 3 || e.Name() != "ollama/nomic-embed-text" {
+		t.Errorf("%s: vectors = %v", e.Name(), vectors)
+	}
+}
@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultExtractRetries is how many times ExtractSchema asks again after a
+// response that isn't valid.
+const DefaultExtractRetries = 2
+
+// maxReportedProblems bounds how many schema problems are sent back to the
+// model or shown in an error.
+const maxReportedProblems = 20
+
+const schemaExtractPrompt = "You are a precise data extractor. Extract data from the document into JSON that conforms to this JSON Schema:\n\n%s\n\nWhere the document doesn't give a value, use null if the schema allows it and otherwise leave the property out unless it is required. Be exact — do not infer or guess values that are not present in the text. Write dates as YYYY-MM-DD and amounts as plain numbers. Return ONLY the JSON, with no other text and no code fences."
+
+const schemaExtractPartPrompt = "You are a precise data extractor. Extract whatever this part of a longer document gives for the JSON Schema below, as JSON in the same shape, using null for values not in this part. Be exact — do not infer or guess values that are not present in the text. Return ONLY the JSON.\n\n%s"
+
+// SchemaPrompt returns the system prompt for extracting to the JSON Schema
+// schema (the schema file's contents).
+func SchemaPrompt(schema []byte) string {
+	return fmt.Sprintf(schemaExtractPrompt, strings.TrimSpace(string(schema)))
+}
+
+// SchemaPartPrompt returns the map prompt (see Condense) for extracting to
+// schema from a document too long for one prompt.
+func SchemaPartPrompt(schema []byte) string {
+	return fmt.Sprintf(schemaExtractPartPrompt, strings.TrimSpace(string(schema)))
+}
+
+// Extraction is data extracted by ExtractSchema.
+type Extraction struct {
+	Data         any
+	Model        string
+	Attempts     int
+	InputTokens  int
+	OutputTokens int
+}
+
+// ExtractSchema asks the model for JSON that matches schema. Each response
+// is parsed and validated; if it isn't valid, the problems are sent back
+// and the model asked to correct it, up to retries more times.
+func ExtractSchema(ctx context.Context, p Provider, schema *Schema, system string, messages []Message, retries int) (*Extraction, error) {
+	if retries < 0 {
+		retries = 0
+	}
+	ex := &Extraction{}
+	messages = append([]Message(nil), messages...)
+	var problems []string
+	for ex.Attempts < retries+1 {
+		ex.Attempts++
+		result, err := p.Infer(ctx, system, messages, InferOptions{Temperature: 0})
+		if err != nil {
+			return nil, err
+		}
+		ex.Model = result.Model
+		ex.InputTokens += result.InputTokens
+		ex.OutputTokens += result.OutputTokens
+
+		data, err := ParseJSONResponse(result.Content)
+		if err != nil {
+			problems = []string{err.Error()}
+		} else if problems = schema.Validate(data); len(problems) == 0 {
+			ex.Data = data
+			return ex, nil
+		}
+
+		messages = append(messages,
+			Message{Role: "assistant", Content: result.Content},
+			Message{Role: "user", Content: "That response does not match the schema:\n- " + strings.Join(limitProblems(problems), "\n- ") +
+				"\n\nReturn the corrected JSON only."},
+		)
+	}
+	return nil, fmt.Errorf("response did not match the schema after %d attempts:\n  %s", ex.Attempts, strings.Join(limitProblems(problems), "\n  "))
+}
+
+func limitProblems(problems []string) []string {
+	if len(problems) <= maxReportedProblems {
+		return problems
+	}
+	return append(problems[:maxReportedProblems:maxReportedProblems], fmt.Sprintf("... and %d more", len(problems)-maxReportedProblems))
+}
@@ -0,0 +1,234 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	geminiAPIURL       = "https://generativelanguage.googleapis.com/v1beta/models/"
+	defaultGeminiModel = "gemini-2.5-pro"
+)
+
+// GeminiProvider implements the Provider interface for Google Gemini models.
+type GeminiProvider struct {
+	apiKey  string
+	model   string
+	client  *http.Client
+	baseURL string
+}
+
+// NewGeminiProvider creates a new Gemini provider with the given API key and model.
+func NewGeminiProvider(apiKey, model string) *GeminiProvider {
+	if model == "" {
+		model = defaultGeminiModel
+	}
+	return &GeminiProvider{
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: 120 * time.Second},
+		baseURL: geminiAPIURL,
+	}
+}
+
+// Name returns the provider identifier.
+func (p *GeminiProvider) Name() string {
+	return "gemini"
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent  `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent `json:"contents"`
+	GenerationConfig  struct {
+		MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
+		Temperature     float64 `json:"temperature,omitempty"`
+		TopP            float64 `json:"topP,omitempty"`
+	} `json:"generationConfig"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+	ModelVersion string `json:"modelVersion"`
+	Error        *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// text returns the response's text: the parts of its first candidate.
+func (r *geminiResponse) text() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, part := range r.Candidates[0].Content.Parts {
+		b.WriteString(part.Text)
+	}
+	return b.String()
+}
+
+func (p *GeminiProvider) newRequest(ctx context.Context, system string, messages []Message, opts InferOptions, method string) (*http.Request, string, error) {
+	model := p.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+
+	var reqBody geminiRequest
+	if system != "" {
+		reqBody.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: system}}}
+	}
+	for _, m := range messages {
+		role := m.Role
+		if role == "assistant" {
+			role = "model" // Gemini's name for the assistant
+		}
+		reqBody.Contents = append(reqBody.Contents, geminiContent{Role: role, Parts: []geminiPart{{Text: m.Content}}})
+	}
+	reqBody.GenerationConfig.MaxOutputTokens = opts.MaxTokens
+	reqBody.GenerationConfig.Temperature = opts.Temperature
+	reqBody.GenerationConfig.TopP = opts.TopP
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not marshal request: %w", err)
+	}
+
+	endpoint := p.baseURL + url.PathEscape(model) + ":" + method
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", p.apiKey)
+	return req, model, nil
+}
+
+// Infer sends a prompt to Gemini and returns the complete response.
+func (p *GeminiProvider) Infer(ctx context.Context, system string, messages []Message, opts InferOptions) (*InferResult, error) {
+	req, model, err := p.newRequest(ctx, system, messages, opts, "generateContent")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	var apiResp geminiResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("could not parse response: %w", err)
+	}
+	if apiResp.Error != nil {
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, apiResp.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if len(apiResp.Candidates) == 0 {
+		return nil, fmt.Errorf("API returned no candidates")
+	}
+
+	if apiResp.ModelVersion != "" {
+		model = apiResp.ModelVersion
+	}
+	return &InferResult{
+		Content:      apiResp.text(),
+		Model:        model,
+		InputTokens:  apiResp.UsageMetadata.PromptTokenCount,
+		OutputTokens: apiResp.UsageMetadata.CandidatesTokenCount,
+	}, nil
+}
+
+// Stream sends a prompt to Gemini and returns a channel of streamed text chunks.
+func (p *GeminiProvider) Stream(ctx context.Context, system string, messages []Message, opts InferOptions) (<-chan string, <-chan error, error) {
+	req, _, err := p.newRequest(ctx, system, messages, opts, "streamGenerateContent")
+	if err != nil {
+		return nil, nil, err
+	}
+	q := req.URL.Query()
+	q.Set("alt", "sse")
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := streamClient(p.client).Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	textCh := make(chan string, 64)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(textCh)
+		defer close(errCh)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+
+			var event geminiResponse
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				continue
+			}
+			if event.Error != nil {
+				errCh <- fmt.Errorf("API error: %s", event.Error.Message)
+				return
+			}
+
+			if text := event.text(); text != "" {
+				select {
+				case textCh <- text:
+				case <-ctx.Done():
+					errCh <- ctx.Err()
+					return
+				}
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errCh <- err
+		}
+	}()
+
+	return textCh, errCh, nil
+}
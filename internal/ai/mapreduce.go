@@ -0,0 +1,156 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultMapConcurrency is how many chunks are sent to the model at once.
+	defaultMapConcurrency = 4
+	// maxReduceRounds bounds how many times the results of the parts are
+	// themselves split and condensed before giving up.
+	maxReduceRounds = 3
+)
+
+// ReduceNote is added to a command's system prompt when the document was
+// condensed, so the model knows it is reading the results for each part.
+const ReduceNote = "\n\nThe document was too long to send at once, so it was split into parts and each part was processed separately. Below are the results for each part, in document order. Treat them together as the whole document, and combine them into a single answer rather than answering part by part."
+
+// MapReduceOptions configures Condense.
+type MapReduceOptions struct {
+	Chunk ChunkOptions
+	// MapPrompt is the system prompt for each part, like "summarize this
+	// part of a document".
+	MapPrompt string
+	// Concurrency is how many parts are sent at once; 0 means 4.
+	Concurrency int
+	// Progress, if set, is called as each part is done.
+	Progress func(done, total int)
+}
+
+// Condensed is a document made small enough for one prompt.
+type Condensed struct {
+	// Text is the document itself when it fit, or else the map prompt's
+	// results for each of its parts.
+	Text string
+	// Chunks is how many parts the document was split into; 1 if it fit.
+	Chunks       int
+	InputTokens  int
+	OutputTokens int
+}
+
+// Condense returns text unchanged when it fits in one chunk. Otherwise it
+// splits it with ChunkDocument, runs opts.MapPrompt on each part, and joins
+// the results in order, repeating until they fit. When Chunks > 1, callers
+// add ReduceNote to their prompt.
+func Condense(ctx context.Context, p Provider, text string, opts MapReduceOptions) (*Condensed, error) {
+	c := &Condensed{Text: text, Chunks: 1}
+	for round := 0; ; round++ {
+		chunks := ChunkDocument(c.Text, opts.Chunk)
+		if len(chunks) == 1 {
+			return c, nil
+		}
+		if round == maxReduceRounds {
+			return nil, fmt.Errorf("document is still too long after condensing its parts %d times — raise the chunk size", round)
+		}
+		if round == 0 {
+			c.Chunks = len(chunks)
+		}
+
+		results, err := mapChunks(ctx, p, chunks, opts, c)
+		if err != nil {
+			return nil, err
+		}
+		c.Text = joinParts(results)
+	}
+}
+
+// mapChunks runs the map prompt on each chunk, opts.Concurrency at a time,
+// adding the tokens used to c.
+func mapChunks(ctx context.Context, p Provider, chunks []Chunk, opts MapReduceOptions, c *Condensed) ([]string, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultMapConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		done     int
+	)
+	results := make([]string, len(chunks))
+	sem := make(chan struct{}, concurrency)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk Chunk) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			result, err := p.Infer(ctx, opts.MapPrompt, []Message{
+				{Role: "user", Content: partMessage(chunk, i, len(chunks))},
+			}, InferOptions{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("part %d of %d: %w", i+1, len(chunks), err)
+					cancel()
+				}
+				return
+			}
+			results[i] = result.Content
+			c.InputTokens += result.InputTokens
+			c.OutputTokens += result.OutputTokens
+			done++
+			if opts.Progress != nil {
+				opts.Progress(done, len(chunks))
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// partMessage is the user message for the i'th of n chunks.
+func partMessage(chunk Chunk, i, n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Part %d of %d of the document", i+1, n)
+	if chunk.Heading != "" {
+		fmt.Fprintf(&b, ", continuing the section %q", strings.TrimLeft(chunk.Heading, "# "))
+	}
+	b.WriteString(":\n\n")
+	b.WriteString(chunk.Text)
+	return b.String()
+}
+
+// joinParts joins the results for each part under a heading per part.
+func joinParts(results []string) string {
+	var b strings.Builder
+	for i, r := range results {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "--- Part %d of %d ---\n\n%s", i+1, len(results), strings.TrimSpace(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klytics/m365kit/internal/usage"
+)
+
+// metered wraps a Provider to record each call's tokens in the usage
+// ledger (see package usage), and to warn on stderr, once per run, when
+// the month's estimated spend reaches the budget's warning point.
+type metered struct {
+	Provider
+	model  string
+	ledger string
+}
+
+// meter wraps p to record its usage, unless KIT_USAGE_LOG=off. model is
+// the model p uses when InferOptions doesn't name one.
+func meter(p Provider, model string) Provider {
+	ledger := usage.LogPath()
+	if ledger == "" {
+		return p
+	}
+	return &metered{Provider: p, model: model, ledger: ledger}
+}
+
+func (m *metered) Infer(ctx context.Context, system string, messages []Message, opts InferOptions) (*InferResult, error) {
+	result, err := m.Provider.Infer(ctx, system, messages, opts)
+	if err != nil {
+		return nil, err
+	}
+	r := usage.Record{
+		Provider:     m.Name(),
+		Model:        result.Model,
+		InputTokens:  result.InputTokens,
+		OutputTokens: result.OutputTokens,
+	}
+	if r.Model == "" {
+		r.Model = m.modelFor(opts)
+	}
+	if r.InputTokens == 0 && r.OutputTokens == 0 {
+		r.InputTokens, r.OutputTokens = promptTokens(system, messages), EstimateTokens(result.Content)
+		r.Estimated = true
+	}
+	m.record(r)
+	return result, nil
+}
+
+// Stream records the call once the stream ends. Streams don't report token
+// counts, so they are estimated from the prompt and the text received.
+func (m *metered) Stream(ctx context.Context, system string, messages []Message, opts InferOptions) (<-chan string, <-chan error, error) {
+	textCh, errCh, err := m.Provider.Stream(ctx, system, messages, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		var b strings.Builder
+		defer func() {
+			m.record(usage.Record{
+				Provider:     m.Name(),
+				Model:        m.modelFor(opts),
+				InputTokens:  promptTokens(system, messages),
+				OutputTokens: EstimateTokens(b.String()),
+				Estimated:    true,
+			})
+		}()
+		for text := range textCh {
+			b.WriteString(text)
+			select {
+			case out <- text:
+			case <-ctx.Done():
+				for range textCh {
+				}
+				return
+			}
+		}
+	}()
+	return out, errCh, nil
+}
+
+func (m *metered) modelFor(opts InferOptions) string {
+	if opts.Model != "" {
+		return opts.Model
+	}
+	return m.model
+}
+
+// promptTokens estimates the tokens in a prompt.
+func promptTokens(system string, messages []Message) int {
+	n := EstimateTokens(system)
+	for _, msg := range messages {
+		n += EstimateTokens(msg.Content)
+	}
+	return n
+}
+
+// budgetChecked is set once the budget has been checked in this run, so a
+// command making many calls warns once.
+var budgetChecked sync.Once
+
+func (m *metered) record(r usage.Record) {
+	record(m.ledger, r)
+}
+
+// record appends r to ledger and checks the budget.
+func record(ledger string, r usage.Record) {
+	usage.Append(ledger, r)
+	budgetChecked.Do(func() {
+		budget := usage.LoadBudget()
+		if budget.Monthly <= 0 {
+			return
+		}
+		now := time.Now()
+		spent, err := usage.MonthSpend(ledger, usage.LoadPrices(), now)
+		if err != nil {
+			return
+		}
+		if warning := budget.Warning(spent, now); warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s (see kit ai usage --month)\n", warning)
+		}
+	})
+}
+
+// meteredEmbedder wraps an Embedder to record each call in the usage ledger
+// as metered does for providers. Embedding APIs bill only their input,
+// which is estimated from the texts sent.
+type meteredEmbedder struct {
+	Embedder
+	ledger string
+}
+
+// meterEmbedder wraps e to record its usage, unless KIT_USAGE_LOG=off.
+func meterEmbedder(e Embedder) Embedder {
+	ledger := usage.LogPath()
+	if ledger == "" {
+		return e
+	}
+	return &meteredEmbedder{Embedder: e, ledger: ledger}
+}
+
+func (m *meteredEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, err := m.Embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	provider, model, _ := strings.Cut(m.Name(), "/")
+	tokens := 0
+	for _, t := range texts {
+		tokens += EstimateTokens(t)
+	}
+	record(m.ledger, usage.Record{Provider: provider, Model: model, InputTokens: tokens, Estimated: true})
+	return vectors, nil
+}
+
+// modelOf returns the model a provider created by NewProvider uses by
+// default.
+func modelOf(p Provider) string {
+	switch p := p.(type) {
+	case *AnthropicProvider:
+		return p.model
+	case *OpenAIProvider:
+		return p.model
+	case *AzureOpenAIProvider:
+		return p.model
+	case *GeminiProvider:
+		return p.model
+	case *OllamaProvider:
+		return p.model
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/klytics/m365kit/internal/usage"
+)
+
+func TestMeteredInfer(t *testing.T) {
+	ledger := filepath.Join(t.TempDir(), "usage.jsonl")
+	p := &metered{Provider: &scriptedProvider{responses: []string{"ok"}}, model: "scripted-default", ledger: ledger}
+
+	if _, err := p.Infer(context.Background(), "system", []Message{{Role: "user", Content: "hi"}}, InferOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	records, _ := usage.Read(ledger)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	r := records[0]
+	if r.Provider != "scripted" || r.Model != "scripted-1" || r.InputTokens != 100 || r.OutputTokens != 20 || r.Estimated {
+		t.Errorf("record = %+v", r)
+	}
+}
+
+func TestMeteredStreamEstimates(t *testing.T) {
+	ledger := filepath.Join(t.TempDir(), "usage.jsonl")
+	p := &metered{Provider: &fakeProvider{chunks: []string{"four", "char"}}, model: "fake-model", ledger: ledger}
+
+	text, err := StreamTo(context.Background(), p, io.Discard, "", []Message{{Role: "user", Content: "12345678"}}, InferOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "fourchar" {
+		t.Errorf("text = %q", text)
+	}
+	records, _ := usage.Read(ledger)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	r := records[0]
+	if r.Model != "fake-model" || r.InputTokens != 2 || r.OutputTokens != 2 || !r.Estimated {
+		t.Errorf("record = %+v", r)
+	}
+}
+
+func TestMeterOff(t *testing.T) {
+	t.Setenv("KIT_USAGE_LOG", "off")
+	p := &scriptedProvider{}
+	if meter(p, "") != Provider(p) {
+		t.Error("provider was wrapped with metering off")
+	}
+}
@@ -0,0 +1,72 @@
+package ai
+
+import "fmt"
+
+// Capability names what a command needs from a model rather than a
+// specific model, so the same --model works with every provider: "kit ai
+// summarize --model fast" uses Haiku on Anthropic, gpt-4o-mini on OpenAI,
+// and whichever deployment azure_openai.deployments.fast names on Azure.
+type Capability string
+
+// Model capabilities.
+const (
+	CapabilityDefault     Capability = "default"
+	CapabilityFast        Capability = "fast"
+	CapabilityLongContext Capability = "long-context"
+)
+
+// Capabilities lists the capabilities in the order help text shows them.
+var Capabilities = []Capability{CapabilityDefault, CapabilityFast, CapabilityLongContext}
+
+// capabilityModels is each provider's model for each capability. Azure
+// OpenAI isn't here: its models are the deployments an organization
+// created, set in the config.
+var capabilityModels = map[string]map[Capability]string{
+	"anthropic": {
+		CapabilityDefault:     defaultAnthropicModel,
+		CapabilityFast:        "claude-3-5-haiku-20241022",
+		CapabilityLongContext: defaultAnthropicModel,
+	},
+	"openai": {
+		CapabilityDefault:     defaultGPTModel,
+		CapabilityFast:        "gpt-4o-mini",
+		CapabilityLongContext: "gpt-4.1",
+	},
+	"gemini": {
+		CapabilityDefault:     defaultGeminiModel,
+		CapabilityFast:        "gemini-2.5-flash",
+		CapabilityLongContext: defaultGeminiModel,
+	},
+	"ollama": {
+		CapabilityDefault:     defaultOllamaModel,
+		CapabilityFast:        defaultOllamaModel,
+		CapabilityLongContext: defaultOllamaModel,
+	},
+}
+
+// IsCapability reports whether name is a capability rather than a model.
+func IsCapability(name string) bool {
+	for _, c := range Capabilities {
+		if string(c) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveModel returns the model provider should use for model: model
+// itself, or for a capability name or "", the provider's model for that
+// capability.
+func ResolveModel(provider, model string) (string, error) {
+	c := Capability(model)
+	if model == "" {
+		c = CapabilityDefault
+	} else if !IsCapability(model) {
+		return model, nil
+	}
+	models, ok := capabilityModels[provider]
+	if !ok {
+		return "", fmt.Errorf("no models known for provider %q", provider)
+	}
+	return models[c], nil
+}
@@ -149,7 +149,7 @@ func (p *OllamaProvider) Stream(ctx context.Context, system string, messages []M
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := p.client.Do(req)
+	resp, err := streamClient(p.client).Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not connect to Ollama at %s — is Ollama running?", p.host)
 	}
@@ -22,6 +22,11 @@ type OpenAIProvider struct {
 	apiKey string
 	model  string
 	client *http.Client
+
+	// url returns the chat completions URL for a model, and authorize adds
+	// the API key to a request; Azure OpenAI does both differently.
+	url       func(model string) string
+	authorize func(req *http.Request)
 }
 
 // NewOpenAIProvider creates a new OpenAI provider with the given API key and model.
@@ -33,6 +38,10 @@ func NewOpenAIProvider(apiKey, model string) *OpenAIProvider {
 		apiKey: apiKey,
 		model:  model,
 		client: &http.Client{Timeout: 120 * time.Second},
+		url:    func(string) string { return openaiAPIURL },
+		authorize: func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+apiKey)
+		},
 	}
 }
 
@@ -93,13 +102,13 @@ func (p *OpenAIProvider) Infer(ctx context.Context, system string, messages []Me
 		return nil, fmt.Errorf("could not marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", openaiAPIURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url(model), bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("could not create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	p.authorize(req)
 
 	resp, err := p.client.Do(req)
 	if err != nil {
@@ -163,15 +172,15 @@ func (p *OpenAIProvider) Stream(ctx context.Context, system string, messages []M
 		return nil, nil, fmt.Errorf("could not marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", openaiAPIURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url(model), bytes.NewReader(body))
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	p.authorize(req)
 
-	resp, err := p.client.Do(req)
+	resp, err := streamClient(p.client).Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -207,11 +216,18 @@ func (p *OpenAIProvider) Stream(ctx context.Context, system string, messages []M
 						Content string `json:"content"`
 					} `json:"delta"`
 				} `json:"choices"`
+				Error *struct {
+					Message string `json:"message"`
+				} `json:"error"`
 			}
 
 			if err := json.Unmarshal([]byte(data), &event); err != nil {
 				continue
 			}
+			if event.Error != nil {
+				errCh <- fmt.Errorf("API error: %s", event.Error.Message)
+				return
+			}
 
 			if len(event.Choices) > 0 && event.Choices[0].Delta.Content != "" {
 				select {
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/klytics/m365kit/internal/config"
 )
 
 // Message represents a single message in a conversation with an AI model.
@@ -42,9 +44,39 @@ type Provider interface {
 	Name() string
 }
 
-// NewProvider creates a provider instance based on the provider name.
+// Providers lists the supported provider names.
+var Providers = []string{"anthropic", "openai", "azure-openai", "gemini", "ollama"}
+
+// NewProvider creates a provider instance based on the provider name. model
+// is a model name, a capability (see Capability), or "" for the provider's
+// default model. Its calls are recorded in the usage ledger, and, with
+// redaction on (see package redact), prompts are masked before they are sent.
 func NewProvider(name string, model string) (Provider, error) {
-	switch strings.ToLower(name) {
+	p, err := newProvider(name, model)
+	if err != nil {
+		return nil, err
+	}
+	p = meter(p, modelOf(p))
+	r, err := redactor()
+	if err != nil {
+		return nil, err
+	}
+	if r != nil {
+		p = &redacted{Provider: p, r: r}
+	}
+	return p, nil
+}
+
+func newProvider(name string, model string) (Provider, error) {
+	name = strings.ToLower(name)
+	if name != "azure-openai" {
+		resolved, err := ResolveModel(name, model)
+		if err == nil {
+			model = resolved
+		}
+	}
+
+	switch name {
 	case "anthropic":
 		apiKey := os.Getenv("ANTHROPIC_API_KEY")
 		if apiKey == "" {
@@ -57,6 +89,18 @@ func NewProvider(name string, model string) (Provider, error) {
 			return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
 		}
 		return NewOpenAIProvider(apiKey, model), nil
+	case "azure-openai":
+		cfg, err := azureConfig()
+		if err != nil {
+			return nil, err
+		}
+		return NewAzureOpenAIProvider(cfg, model)
+	case "gemini":
+		apiKey, err := config.GetAPIKey("gemini")
+		if err != nil {
+			return nil, err
+		}
+		return NewGeminiProvider(apiKey, model), nil
 	case "ollama":
 		host := os.Getenv("OLLAMA_HOST")
 		if host == "" {
@@ -64,6 +108,36 @@ func NewProvider(name string, model string) (Provider, error) {
 		}
 		return NewOllamaProvider(host, model), nil
 	default:
-		return nil, fmt.Errorf("unknown AI provider %q — supported providers: anthropic, openai, ollama", name)
+		return nil, fmt.Errorf("unknown AI provider %q — supported providers: %s", name, strings.Join(Providers, ", "))
+	}
+}
+
+// azureConfig reads the Azure OpenAI settings: AZURE_OPENAI_* environment
+// variables first, then azure_openai in ~/.kit/config.yaml.
+func azureConfig() (AzureOpenAIConfig, error) {
+	apiKey, err := config.GetAPIKey("azure-openai")
+	if err != nil {
+		return AzureOpenAIConfig{}, err
+	}
+	cfg := AzureOpenAIConfig{APIKey: apiKey, Deployments: make(map[Capability]string)}
+	if file, err := config.Load(); err == nil {
+		cfg.Endpoint = file.AzureOpenAI.Endpoint
+		cfg.APIVersion = file.AzureOpenAI.APIVersion
+		for c, deployment := range file.AzureOpenAI.Deployments {
+			cfg.Deployments[Capability(c)] = deployment
+		}
+		if file.AzureOpenAI.Deployment != "" {
+			cfg.Deployments[CapabilityDefault] = file.AzureOpenAI.Deployment
+		}
+	}
+	if v := os.Getenv("AZURE_OPENAI_ENDPOINT"); v != "" {
+		cfg.Endpoint = v
+	}
+	if v := os.Getenv("AZURE_OPENAI_API_VERSION"); v != "" {
+		cfg.APIVersion = v
+	}
+	if v := os.Getenv("AZURE_OPENAI_DEPLOYMENT"); v != "" {
+		cfg.Deployments[CapabilityDefault] = v
 	}
+	return cfg, nil
 }
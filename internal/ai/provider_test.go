@@ -0,0 +1,213 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		provider, model, want string
+	}{
+		{"anthropic", "", defaultAnthropicModel},
+		{"openai", "fast", "gpt-4o-mini"},
+		{"gemini", "long-context", "gemini-2.5-pro"},
+		{"gemini", "fast", "gemini-2.5-flash"},
+		{"openai", "gpt-4-turbo", "gpt-4-turbo"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveModel(tt.provider, tt.model)
+		if err != nil {
+			t.Fatalf("ResolveModel(%q, %q): %v", tt.provider, tt.model, err)
+		}
+		if got != tt.want {
+			t.Errorf("ResolveModel(%q, %q) = %q, want %q", tt.provider, tt.model, got, tt.want)
+		}
+	}
+
+	if _, err := ResolveModel("nope", "fast"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestNewProviderUnknown(t *testing.T) {
+	_, err := NewProvider("bard", "")
+	if err == nil || !strings.Contains(err.Error(), "azure-openai") {
+		t.Errorf("expected error listing providers, got %v", err)
+	}
+}
+
+func TestAzureOpenAIProvider(t *testing.T) {
+	var gotPath, gotVersion, gotKey, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotVersion = r.URL.Query().Get("api-version")
+		gotKey = r.Header.Get("api-key")
+		gotAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"hello"}}],"model":"gpt-4o-mini","usage":{"prompt_tokens":3,"completion_tokens":1}}`)
+	}))
+	defer srv.Close()
+
+	cfg := AzureOpenAIConfig{
+		Endpoint: srv.URL + "/",
+		APIKey:   "secret",
+		Deployments: map[Capability]string{
+			CapabilityDefault: "gpt4o-prod",
+			CapabilityFast:    "gpt4o-mini-prod",
+		},
+	}
+	p, err := NewAzureOpenAIProvider(cfg, "fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "azure-openai" {
+		t.Errorf("Name() = %q", p.Name())
+	}
+
+	result, err := p.Infer(context.Background(), "", []Message{{Role: "user", Content: "hi"}}, InferOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Content != "hello" {
+		t.Errorf("Content = %q, want %q", result.Content, "hello")
+	}
+	if gotPath != "/openai/deployments/gpt4o-mini-prod/chat/completions" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotVersion != defaultAzureAPIVersion {
+		t.Errorf("api-version = %q", gotVersion)
+	}
+	if gotKey != "secret" || gotAuth != "" {
+		t.Errorf("api-key = %q, Authorization = %q", gotKey, gotAuth)
+	}
+}
+
+func TestAzureOpenAIDeployment(t *testing.T) {
+	cfg := AzureOpenAIConfig{
+		Endpoint:    "https://contoso.openai.azure.com",
+		Deployments: map[Capability]string{CapabilityDefault: "gpt4o-prod"},
+	}
+
+	// A capability without its own deployment falls back to the default
+	p, err := NewAzureOpenAIProvider(cfg, "long-context")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.model != "gpt4o-prod" {
+		t.Errorf("model = %q, want gpt4o-prod", p.model)
+	}
+
+	// Anything else is a deployment name
+	p, err = NewAzureOpenAIProvider(cfg, "my-deployment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.model != "my-deployment" {
+		t.Errorf("model = %q, want my-deployment", p.model)
+	}
+
+	if _, err := NewAzureOpenAIProvider(AzureOpenAIConfig{Endpoint: cfg.Endpoint}, ""); err == nil {
+		t.Error("expected error with no deployments")
+	}
+	if _, err := NewAzureOpenAIProvider(AzureOpenAIConfig{}, "x"); err == nil {
+		t.Error("expected error with no endpoint")
+	}
+}
+
+func TestGeminiInfer(t *testing.T) {
+	var got geminiRequest
+	var gotPath, gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("x-goog-api-key")
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, `{"candidates":[{"content":{"role":"model","parts":[{"text":"Hel"},{"text":"lo"}]}}],"usageMetadata":{"promptTokenCount":7,"candidatesTokenCount":2},"modelVersion":"gemini-2.5-flash-001"}`)
+	}))
+	defer srv.Close()
+
+	p := NewGeminiProvider("key", "gemini-2.5-flash")
+	p.baseURL = srv.URL + "/models/"
+
+	messages := []Message{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+		{Role: "user", Content: "again"},
+	}
+	result, err := p.Infer(context.Background(), "be brief", messages, InferOptions{MaxTokens: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Content != "Hello" || result.Model != "gemini-2.5-flash-001" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.InputTokens != 7 || result.OutputTokens != 2 {
+		t.Errorf("tokens = %d/%d, want 7/2", result.InputTokens, result.OutputTokens)
+	}
+	if gotPath != "/models/gemini-2.5-flash:generateContent" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotKey != "key" {
+		t.Errorf("x-goog-api-key = %q", gotKey)
+	}
+	if got.SystemInstruction == nil || got.SystemInstruction.Parts[0].Text != "be brief" {
+		t.Errorf("systemInstruction = %+v", got.SystemInstruction)
+	}
+	if len(got.Contents) != 3 || got.Contents[1].Role != "model" {
+		t.Errorf("contents = %+v", got.Contents)
+	}
+	if got.GenerationConfig.MaxOutputTokens != 100 {
+		t.Errorf("maxOutputTokens = %d", got.GenerationConfig.MaxOutputTokens)
+	}
+}
+
+func TestGeminiInferError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"code":400,"message":"API key not valid"}}`)
+	}))
+	defer srv.Close()
+
+	p := NewGeminiProvider("bad", "")
+	p.baseURL = srv.URL + "/models/"
+	_, err := p.Infer(context.Background(), "", []Message{{Role: "user", Content: "hi"}}, InferOptions{})
+	if err == nil || !strings.Contains(err.Error(), "API key not valid") {
+		t.Errorf("expected API error, got %v", err)
+	}
+}
+
+func TestGeminiStream(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, text := range []string{"one ", "two"} {
+			fmt.Fprintf(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":%q}]}}]}\n\n", text)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewGeminiProvider("key", "")
+	p.baseURL = srv.URL + "/models/"
+	textCh, errCh, err := p.Stream(context.Background(), "", []Message{{Role: "user", Content: "count"}}, InferOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for text := range textCh {
+		b.WriteString(text)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "one two" {
+		t.Errorf("streamed %q, want %q", b.String(), "one two")
+	}
+	if gotQuery != "alt=sse" {
+		t.Errorf("query = %q, want alt=sse", gotQuery)
+	}
+}
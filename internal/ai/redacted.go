@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"sync"
+
+	"github.com/klytics/m365kit/internal/redact"
+)
+
+// runRedactor is the redactor for the current command, shared by its
+// providers and embedders so a value gets the same placeholder in every
+// call.
+var runRedactor struct {
+	sync.Mutex
+	r *redact.Redactor
+}
+
+// redactor returns runRedactor, creating it if needed, or nil if redaction
+// is off.
+func redactor() (*redact.Redactor, error) {
+	if !redact.Enabled() {
+		return nil, nil
+	}
+	runRedactor.Lock()
+	defer runRedactor.Unlock()
+	if runRedactor.r == nil {
+		r, err := redact.Load()
+		if err != nil {
+			return nil, err
+		}
+		runRedactor.r = r
+	}
+	return runRedactor.r, nil
+}
+
+// TakeRedactions returns what was masked in text sent to providers since
+// the last call, and starts afresh, with new placeholders, for the next
+// command.
+func TakeRedactions() []redact.Finding {
+	runRedactor.Lock()
+	defer runRedactor.Unlock()
+	if runRedactor.r == nil {
+		return nil
+	}
+	findings := runRedactor.r.Findings()
+	runRedactor.r = nil
+	return findings
+}
+
+// redacted wraps a Provider to mask the prompt (see package redact) before
+// it is sent.
+type redacted struct {
+	Provider
+	r *redact.Redactor
+}
+
+func (p *redacted) Infer(ctx context.Context, system string, messages []Message, opts InferOptions) (*InferResult, error) {
+	return p.Provider.Infer(ctx, p.r.Redact(system), p.messages(messages), opts)
+}
+
+func (p *redacted) Stream(ctx context.Context, system string, messages []Message, opts InferOptions) (<-chan string, <-chan error, error) {
+	return p.Provider.Stream(ctx, p.r.Redact(system), p.messages(messages), opts)
+}
+
+func (p *redacted) messages(messages []Message) []Message {
+	masked := make([]Message, len(messages))
+	for i, m := range messages {
+		masked[i] = Message{Role: m.Role, Content: p.r.Redact(m.Content)}
+	}
+	return masked
+}
+
+// redactedEmbedder wraps an Embedder to mask texts before they are sent.
+type redactedEmbedder struct {
+	Embedder
+	r *redact.Redactor
+}
+
+func (e *redactedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	masked := make([]string, len(texts))
+	for i, t := range texts {
+		masked[i] = e.r.Redact(t)
+	}
+	return e.Embedder.Embed(ctx, masked)
+}
@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewProviderRedacts(t *testing.T) {
+	var sent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		fmt.Fprint(w, `{"model":"llama3.1","message":{"role":"assistant","content":"Email [EMAIL_1]."},"done":true}`)
+	}))
+	defer srv.Close()
+
+	t.Setenv("OLLAMA_HOST", srv.URL)
+	t.Setenv("KIT_USAGE_LOG", filepath.Join(t.TempDir(), "usage.jsonl"))
+	t.Setenv("KIT_REDACT", "1")
+	defer TakeRedactions()
+
+	p, err := NewProvider("ollama", "llama3.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.Infer(context.Background(), "Who should I contact?", []Message{
+		{Role: "user", Content: "Questions to jane.doe@contoso.com or 206-555-0142; cc jane.doe@contoso.com."},
+	}, InferOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sent, "contoso.com") || strings.Contains(sent, "555-0142") {
+		t.Errorf("request leaked personal data: %s", sent)
+	}
+	if !strings.Contains(sent, "[EMAIL_1] or [PHONE_1]; cc [EMAIL_1]") {
+		t.Errorf("request = %s", sent)
+	}
+
+	findings := TakeRedactions()
+	if len(findings) != 2 || findings[0].Kind != "email" || findings[0].Occurrences != 2 {
+		t.Errorf("findings = %+v", findings)
+	}
+	if TakeRedactions() != nil {
+		t.Error("findings weren't reset")
+	}
+}
+
+func TestNewProviderRedactionOff(t *testing.T) {
+	t.Setenv("KIT_REDACT", "0")
+	t.Setenv("KIT_USAGE_LOG", "off")
+	p, err := NewProvider("ollama", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*redacted); ok {
+		t.Error("provider redacts with redaction off")
+	}
+}
@@ -0,0 +1,224 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema that extraction checks responses
+// against: type (one or a list), properties, required,
+// additionalProperties: false, items, enum, and the date, date-time, and
+// email formats. Other keywords are passed to the model but not checked.
+type Schema struct {
+	Type                 schemaType         `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+}
+
+// schemaType is a schema's "type": a single name or a list of them.
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = schemaType{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or a list of strings")
+	}
+	*t = many
+	return nil
+}
+
+func (t schemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// LoadSchema reads a JSON Schema from a file.
+func LoadSchema(path string) (*Schema, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read schema: %w", err)
+	}
+	s, err := ParseSchema(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schema %s: %w", path, err)
+	}
+	return s, data, nil
+}
+
+// ParseSchema parses a JSON Schema.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	for _, t := range s.Type {
+		if !knownTypes[t] {
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return &s, nil
+}
+
+var knownTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+var formats = map[string]*regexp.Regexp{
+	"date":      regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`),
+	"date-time": regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:\d{2})$`),
+	"email":     regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`),
+}
+
+// Validate checks v, as decoded by encoding/json, against the schema and
+// returns what is wrong with it, each problem prefixed with its path ($ for
+// the root).
+func (s *Schema) Validate(v any) []string {
+	var problems []string
+	s.validate("$", v, &problems)
+	return problems
+}
+
+func (s *Schema) validate(path string, v any, problems *[]string) {
+	if s == nil {
+		return
+	}
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), jsonType(v)))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		*problems = append(*problems, fmt.Sprintf("%s: %s is not one of %s", path, compactJSON(v), compactJSON(s.Enum)))
+	}
+
+	switch v := v.(type) {
+	case string:
+		if re, ok := formats[s.Format]; ok && !re.MatchString(v) {
+			*problems = append(*problems, fmt.Sprintf("%s: %q is not a valid %s", path, v, s.Format))
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*problems = append(*problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					*problems = append(*problems, fmt.Sprintf("%s: unexpected property %q", path, name))
+				}
+				continue
+			}
+			prop.validate(path+"."+name, v[name], problems)
+		}
+	case []any:
+		for i, item := range v {
+			s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+		}
+	}
+}
+
+// matches reports whether v has one of the types.
+func (t schemaType) matches(v any) bool {
+	got := jsonType(v)
+	for _, want := range t {
+		switch {
+		case want == got:
+			return true
+		case want == "number" && got == "integer":
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType returns the JSON Schema type of a decoded JSON value.
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func inEnum(v any, enum []any) bool {
+	got := compactJSON(v)
+	for _, e := range enum {
+		if compactJSON(e) == got {
+			return true
+		}
+	}
+	return false
+}
+
+func compactJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// ParseJSONResponse decodes the JSON in a model's response, ignoring a
+// Markdown code fence or text around it.
+func ParseJSONResponse(content string) (any, error) {
+	content = strings.TrimSpace(content)
+	if i := strings.Index(content, "```"); i >= 0 {
+		fenced := content[i+3:]
+		fenced = strings.TrimPrefix(fenced, "json")
+		if j := strings.Index(fenced, "```"); j >= 0 {
+			content = strings.TrimSpace(fenced[:j])
+		}
+	}
+	if start := strings.IndexAny(content, "{["); start > 0 {
+		content = content[start:]
+	}
+	if end := strings.LastIndexAny(content, "}]"); end >= 0 && end < len(content)-1 {
+		content = content[:end+1]
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(content)))
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("response has text after its JSON")
+	}
+	return v, nil
+}
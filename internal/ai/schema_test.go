@@ -0,0 +1,224 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const contractSchema = `{
+  "type": "object",
+  "required": ["parties", "effective_date", "amount"],
+  "additionalProperties": false,
+  "properties": {
+    "parties": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string"},
+          "role": {"enum": ["buyer", "seller"]}
+        }
+      }
+    },
+    "effective_date": {"type": "string", "format": "date"},
+    "amount": {"type": ["number", "null"]},
+    "notice_email": {"type": "string", "format": "email"}
+  }
+}`
+
+func mustParseSchema(t *testing.T, s string) *Schema {
+	t.Helper()
+	schema, err := ParseSchema([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema := mustParseSchema(t, contractSchema)
+
+	tests := []struct {
+		name string
+		json string
+		want []string // substrings of the expected problems, in order
+	}{
+		{
+			name: "valid",
+			json: `{"parties":[{"name":"Contoso","role":"buyer"}],"effective_date":"2024-03-01","amount":125000.5}`,
+		},
+		{
+			name: "null allowed",
+			json: `{"parties":[],"effective_date":"2024-03-01","amount":null}`,
+		},
+		{
+			name: "missing required",
+			json: `{"parties":[],"amount":1}`,
+			want: []string{`$: missing required property "effective_date"`},
+		},
+		{
+			name: "wrong types",
+			json: `{"parties":{"name":"x"},"effective_date":"2024-03-01","amount":"$1,000"}`,
+			want: []string{"$.amount: expected number or null, got string", "$.parties: expected array, got object"},
+		},
+		{
+			name: "nested items",
+			json: `{"parties":[{"role":"lender"}],"effective_date":"2024-03-01","amount":1}`,
+			want: []string{`$.parties[0]: missing required property "name"`, `$.parties[0].role: "lender" is not one of`},
+		},
+		{
+			name: "formats",
+			json: `{"parties":[],"effective_date":"March 1, 2024","amount":1,"notice_email":"legal at contoso"}`,
+			want: []string{`$.effective_date: "March 1, 2024" is not a valid date`, `$.notice_email: "legal at contoso" is not a valid email`},
+		},
+		{
+			name: "additional properties",
+			json: `{"parties":[],"effective_date":"2024-03-01","amount":1,"summary":"..."}`,
+			want: []string{`$: unexpected property "summary"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseJSONResponse(tt.json)
+			if err != nil {
+				t.Fatal(err)
+			}
+			problems := schema.Validate(v)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("problems = %q, want %d", problems, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %d = %q, want %q", i, problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaIntegerIsNumber(t *testing.T) {
+	schema := mustParseSchema(t, `{"type":"object","properties":{"n":{"type":"integer"},"x":{"type":"number"}}}`)
+	v, _ := ParseJSONResponse(`{"n": 2.5, "x": 3}`)
+	problems := schema.Validate(v)
+	if len(problems) != 1 || !strings.Contains(problems[0], "$.n: expected integer, got number") {
+		t.Errorf("problems = %q", problems)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	if _, err := ParseSchema([]byte(`{"type":"text"}`)); err == nil {
+		t.Error("expected error for unknown type")
+	}
+	if _, err := ParseSchema([]byte(`{"type":`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contract.schema.json")
+	os.WriteFile(path, []byte(contractSchema), 0644)
+	schema, raw, err := LoadSchema(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schema.Required) != 3 || string(raw) != contractSchema {
+		t.Errorf("schema = %+v", schema)
+	}
+	if !strings.Contains(SchemaPrompt(raw), `"effective_date"`) {
+		t.Error("prompt doesn't include the schema")
+	}
+}
+
+func TestParseJSONResponse(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`{"a":1}`, `{"a":1}`},
+		{"```json\n{\"a\":1}\n```", `{"a":1}`},
+		{"Here is the data:\n{\"a\":1}\nLet me know if you need more.", `{"a":1}`},
+		{`[1,2]`, `[1,2]`},
+	}
+	for _, tt := range tests {
+		v, err := ParseJSONResponse(tt.content)
+		if err != nil {
+			t.Errorf("ParseJSONResponse(%q): %v", tt.content, err)
+			continue
+		}
+		if got := compactJSON(v); got != tt.want {
+			t.Errorf("ParseJSONResponse(%q) = %s, want %s", tt.content, got, tt.want)
+		}
+	}
+
+	if _, err := ParseJSONResponse("I could not find any parties."); err == nil {
+		t.Error("expected error for prose")
+	}
+}
+
+// scriptedProvider answers Infer with each of responses in turn.
+type scriptedProvider struct {
+	mu        sync.Mutex
+	responses []string
+	calls     [][]Message
+}
+
+func (p *scriptedProvider) Name() string { return "scripted" }
+
+func (p *scriptedProvider) Infer(ctx context.Context, system string, messages []Message, opts InferOptions) (*InferResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, messages)
+	content := p.responses[0]
+	if len(p.responses) > 1 {
+		p.responses = p.responses[1:]
+	}
+	return &InferResult{Content: content, Model: "scripted-1", InputTokens: 100, OutputTokens: 20}, nil
+}
+
+func (p *scriptedProvider) Stream(ctx context.Context, system string, messages []Message, opts InferOptions) (<-chan string, <-chan error, error) {
+	return nil, nil, nil
+}
+
+func TestExtractSchemaRetries(t *testing.T) {
+	schema := mustParseSchema(t, contractSchema)
+	p := &scriptedProvider{responses: []string{
+		`{"parties":[{"name":"Contoso"}],"effective_date":"1 March 2024","amount":"125,000"}`,
+		`{"parties":[{"name":"Contoso"}],"effective_date":"2024-03-01","amount":125000}`,
+	}}
+
+	ex, err := ExtractSchema(context.Background(), p, schema, SchemaPrompt([]byte(contractSchema)), []Message{
+		{Role: "user", Content: "The agreement between Contoso ..."},
+	}, DefaultExtractRetries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ex.Attempts != 2 || ex.InputTokens != 200 || ex.OutputTokens != 40 {
+		t.Errorf("extraction = %+v", ex)
+	}
+	if got := ex.Data.(map[string]any)["amount"]; got != 125000.0 {
+		t.Errorf("amount = %v", got)
+	}
+
+	// The retry shows the model its answer and what was wrong with it
+	retry := p.calls[1]
+	if len(retry) != 3 || retry[1].Role != "assistant" || !strings.Contains(retry[2].Content, "$.effective_date") {
+		t.Errorf("retry messages = %+v", retry)
+	}
+}
+
+func TestExtractSchemaGivesUp(t *testing.T) {
+	schema := mustParseSchema(t, contractSchema)
+	p := &scriptedProvider{responses: []string{"Sorry, I can't help with that."}}
+	_, err := ExtractSchema(context.Background(), p, schema, "", []Message{{Role: "user", Content: "x"}}, 1)
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") || !strings.Contains(err.Error(), "not valid JSON") {
+		t.Errorf("expected error after 2 attempts, got %v", err)
+	}
+	if len(p.calls) != 2 {
+		t.Errorf("%d calls, want 2", len(p.calls))
+	}
+}
@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klytics/m365kit/internal/progress"
+)
+
+// streamHeaderTimeout bounds how long a stream waits for the response to
+// start. Once it has, the stream runs as long as text keeps arriving.
+const streamHeaderTimeout = 120 * time.Second
+
+// streamClient returns a copy of client for streaming. http.Client.Timeout
+// covers reading the whole body, so a long answer would be cut off; instead
+// only the wait for the response headers is bounded, and ctx ends the rest.
+func streamClient(client *http.Client) *http.Client {
+	c := *client
+	c.Timeout = 0
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = streamHeaderTimeout
+	c.Transport = transport
+	return &c
+}
+
+// StreamTo streams the response to a prompt to w as it arrives and returns
+// the complete text.
+func StreamTo(ctx context.Context, p Provider, w io.Writer, system string, messages []Message, opts InferOptions) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	textCh, errCh, err := p.Stream(ctx, system, messages, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for text := range textCh {
+		b.WriteString(text)
+		if _, err := io.WriteString(w, text); err != nil {
+			// Stop the stream and let its goroutine finish
+			cancel()
+			for range textCh {
+			}
+			return b.String(), err
+		}
+	}
+	if err := <-errCh; err != nil {
+		return b.String(), fmt.Errorf("stream interrupted: %w", err)
+	}
+	return b.String(), nil
+}
+
+// StreamingDisabled reports whether responses should be printed whole rather
+// than streamed: --no-stream sets KIT_NO_STREAM=1.
+func StreamingDisabled() bool {
+	return os.Getenv("KIT_NO_STREAM") == "1"
+}
+
+// Print writes the response to a prompt to w, followed by a newline, and
+// returns it. The text is streamed as it arrives, with a spinner on stderr
+// until the first of it does; with streaming disabled it waits for the
+// whole response.
+func Print(ctx context.Context, p Provider, w io.Writer, system string, messages []Message, opts InferOptions) (string, error) {
+	spinner := progress.NewSpinner(fmt.Sprintf("Waiting for %s...", p.Name()))
+	spinner.Start()
+
+	if StreamingDisabled() {
+		result, err := p.Infer(ctx, system, messages, opts)
+		spinner.Clear()
+		if err != nil {
+			return "", fmt.Errorf("AI inference failed: %w", err)
+		}
+		_, err = fmt.Fprintln(w, result.Content)
+		return result.Content, err
+	}
+
+	text, err := StreamTo(ctx, p, &firstWrite{w: w, before: spinner.Clear}, system, messages, opts)
+	spinner.Clear()
+	if text != "" {
+		fmt.Fprintln(w)
+	}
+	if err != nil {
+		return text, fmt.Errorf("AI inference failed: %w", err)
+	}
+	return text, nil
+}
+
+// firstWrite is a writer that calls before ahead of its first write.
+type firstWrite struct {
+	w      io.Writer
+	before func()
+	once   sync.Once
+}
+
+func (f *firstWrite) Write(p []byte) (int, error) {
+	f.once.Do(f.before)
+	return f.w.Write(p)
+}
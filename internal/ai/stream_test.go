@@ -0,0 +1,138 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeProvider streams chunks and then err, and answers Infer with the
+// chunks joined.
+type fakeProvider struct {
+	chunks   []string
+	err      error
+	inferred bool
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) Infer(ctx context.Context, system string, messages []Message, opts InferOptions) (*InferResult, error) {
+	f.inferred = true
+	return &InferResult{Content: strings.Join(f.chunks, ""), Model: "fake-1"}, f.err
+}
+
+func (f *fakeProvider) Stream(ctx context.Context, system string, messages []Message, opts InferOptions) (<-chan string, <-chan error, error) {
+	textCh := make(chan string)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(textCh)
+		defer close(errCh)
+		for _, c := range f.chunks {
+			select {
+			case textCh <- c:
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			}
+		}
+		if f.err != nil {
+			errCh <- f.err
+		}
+	}()
+	return textCh, errCh, nil
+}
+
+func TestStreamTo(t *testing.T) {
+	p := &fakeProvider{chunks: []string{"Key ", "points", ": none"}}
+	var out bytes.Buffer
+	text, err := StreamTo(context.Background(), p, &out, "", nil, InferOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Key points: none" || out.String() != text {
+		t.Errorf("text = %q, out = %q", text, out.String())
+	}
+}
+
+func TestStreamToError(t *testing.T) {
+	p := &fakeProvider{chunks: []string{"partial"}, err: errors.New("overloaded")}
+	var out bytes.Buffer
+	text, err := StreamTo(context.Background(), p, &out, "", nil, InferOptions{})
+	if err == nil || !strings.Contains(err.Error(), "overloaded") {
+		t.Fatalf("expected stream error, got %v", err)
+	}
+	if text != "partial" {
+		t.Errorf("text = %q, want the partial response", text)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestStreamToWriteErrorStopsStream(t *testing.T) {
+	p := &fakeProvider{chunks: []string{"a", "b", "c", "d"}}
+	done := make(chan error, 1)
+	go func() {
+		_, err := StreamTo(context.Background(), p, failingWriter{}, "", nil, InferOptions{})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "broken pipe") {
+			t.Errorf("expected write error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("StreamTo did not return after a write error")
+	}
+}
+
+func TestPrint(t *testing.T) {
+	t.Setenv("KIT_NO_PROGRESS", "1")
+	p := &fakeProvider{chunks: []string{"one ", "two"}}
+	var out bytes.Buffer
+	text, err := Print(context.Background(), p, &out, "", nil, InferOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "one two" || out.String() != "one two\n" {
+		t.Errorf("text = %q, out = %q", text, out.String())
+	}
+	if p.inferred {
+		t.Error("Print used Infer with streaming enabled")
+	}
+}
+
+func TestPrintNoStream(t *testing.T) {
+	t.Setenv("KIT_NO_PROGRESS", "1")
+	t.Setenv("KIT_NO_STREAM", "1")
+	p := &fakeProvider{chunks: []string{"one ", "two"}}
+	var out bytes.Buffer
+	if _, err := Print(context.Background(), p, &out, "", nil, InferOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !p.inferred {
+		t.Error("Print streamed with KIT_NO_STREAM=1")
+	}
+	if out.String() != "one two\n" {
+		t.Errorf("out = %q", out.String())
+	}
+}
+
+func TestStreamClientHasNoOverallTimeout(t *testing.T) {
+	client := &http.Client{Timeout: time.Second}
+	c := streamClient(client)
+	if c.Timeout != 0 {
+		t.Errorf("stream client Timeout = %v, want 0", c.Timeout)
+	}
+	if client.Timeout != time.Second {
+		t.Error("streamClient modified the original client")
+	}
+	if c.Transport.(*http.Transport).ResponseHeaderTimeout != streamHeaderTimeout {
+		t.Error("stream client has no response header timeout")
+	}
+}
@@ -0,0 +1,181 @@
+// Package alias stores user-defined command shortcuts, like
+// "standup = teams post --team Eng --channel general --stdin", in
+// ~/.kit/aliases.yaml. Both the shell and the top-level CLI expand them.
+package alias
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxDepth bounds how many aliases may expand into each other.
+const maxDepth = 10
+
+// Aliases maps alias names to the commands they stand for, without the
+// leading "kit".
+type Aliases struct {
+	Path    string
+	Entries map[string]string
+}
+
+// DefaultPath returns ~/.kit/aliases.yaml.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".kit", "aliases.yaml")
+	}
+	return filepath.Join(home, ".kit", "aliases.yaml")
+}
+
+// Load reads the aliases at path. A missing file means none.
+func Load(path string) (*Aliases, error) {
+	a := &Aliases{Path: path, Entries: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read aliases at %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &a.Entries); err != nil {
+		return nil, fmt.Errorf("invalid aliases file %s: %w", path, err)
+	}
+	if a.Entries == nil {
+		a.Entries = make(map[string]string)
+	}
+	return a, nil
+}
+
+// Save writes the aliases back to Path.
+func (a *Aliases) Save() error {
+	data, err := yaml.Marshal(a.Entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(a.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(a.Path, data, 0644)
+}
+
+// Set defines name as command, replacing any alias of that name. It does
+// not save.
+func (a *Aliases) Set(name, command string) error {
+	if !ValidName(name) {
+		return fmt.Errorf("invalid alias name %q (use letters, digits, - and _)", name)
+	}
+	command = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(command), "kit "))
+	words, err := Split(command)
+	if err != nil {
+		return fmt.Errorf("alias %s: %w", name, err)
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("alias %s: no command", name)
+	}
+	a.Entries[name] = command
+	return nil
+}
+
+// Remove deletes the alias name. It does not save.
+func (a *Aliases) Remove(name string) error {
+	if _, ok := a.Entries[name]; !ok {
+		return fmt.Errorf("no alias %q", name)
+	}
+	delete(a.Entries, name)
+	return nil
+}
+
+// Names returns the alias names, sorted.
+func (a *Aliases) Names() []string {
+	names := make([]string, 0, len(a.Entries))
+	for name := range a.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand replaces args[0] with the command it is an alias for, keeping
+// the rest of args after it. An alias may start with another alias, but
+// not with itself. Args that don't start with an alias come back as they
+// are.
+func (a *Aliases) Expand(args []string) ([]string, error) {
+	seen := make(map[string]bool)
+	for len(args) > 0 {
+		command, ok := a.Entries[args[0]]
+		if !ok || seen[args[0]] {
+			return args, nil
+		}
+		if len(seen) == maxDepth {
+			return nil, fmt.Errorf("alias %s: too many nested aliases", args[0])
+		}
+		seen[args[0]] = true
+		words, err := Split(command)
+		if err != nil {
+			return nil, fmt.Errorf("alias %s: %w", args[0], err)
+		}
+		args = append(words, args[1:]...)
+	}
+	return args, nil
+}
+
+// ValidName reports whether name can be an alias: letters, digits, - and
+// _, not starting with -.
+func ValidName(name string) bool {
+	if name == "" || name[0] == '-' {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Split splits an alias's command into words. Words may be quoted with
+// single or double quotes. Aliases stand for one command, so a | is an
+// error.
+func Split(command string) ([]string, error) {
+	var (
+		words []string
+		cur   strings.Builder
+		inArg bool
+		quote rune
+	)
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '|':
+			return nil, fmt.Errorf("an alias can't contain a pipeline (quote the |)")
+		case r == ' ' || r == '\t':
+			if inArg {
+				words = append(words, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,66 @@
+package alias
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSetSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.yaml")
+	a, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Set("standup", `kit teams post --team "R&D Eng" --channel general --stdin`); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Entries["standup"]; got != `teams post --team "R&D Eng" --channel general --stdin` {
+		t.Errorf("saved alias = %q", got)
+	}
+	if err := b.Remove("standup"); err != nil || len(b.Names()) != 0 {
+		t.Errorf("Remove: %v, left %v", err, b.Names())
+	}
+	if err := b.Remove("standup"); err == nil {
+		t.Error("expected an error removing a missing alias")
+	}
+
+	for _, bad := range [][2]string{{"-x", "version"}, {"a b", "version"}, {"x", ""}, {"x", "word read | ai summarize"}, {"x", `teams post --message "oops`}} {
+		if err := a.Set(bad[0], bad[1]); err == nil {
+			t.Errorf("Set(%q, %q): expected an error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	a := &Aliases{Entries: map[string]string{
+		"standup": `teams post --team "R&D Eng" --stdin`,
+		"su":      "standup --channel general",
+		"ls":      "ls --json", // refers to itself: not expanded again
+		"loop1":   "loop2",
+		"loop2":   "loop1",
+	}}
+	tests := []struct {
+		args, want []string
+	}{
+		{[]string{"su", "--dry-run"}, []string{"teams", "post", "--team", "R&D Eng", "--stdin", "--channel", "general", "--dry-run"}},
+		{[]string{"ls"}, []string{"ls", "--json"}},
+		{[]string{"word", "read", "a.docx"}, []string{"word", "read", "a.docx"}},
+		{[]string{"loop1"}, []string{"loop1"}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got, err := a.Expand(tt.args)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Expand(%q) = %q, %v; want %q", tt.args, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/cmdlog"
+)
+
+// Activity is one change kit made through Microsoft Graph. Where Entry
+// records commands, Activity records what they did to the tenant, so an
+// admin can answer "what did this tool change".
+type Activity struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Profile    string    `json:"profile,omitempty"`
+	Account    string    `json:"account,omitempty"`
+	Command    string    `json:"command,omitempty"`
+	Method     string    `json:"method"`
+	Endpoint   string    `json:"endpoint"`         // Graph path, without the query
+	Target     string    `json:"target,omitempty"` // e.g. the OneDrive path or mailbox
+	Status     int       `json:"status,omitempty"` // HTTP status; 0 if the request failed to send
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Succeeded reports whether Graph accepted the change.
+func (a Activity) Succeeded() bool {
+	return a.Error == "" && a.Status >= 200 && a.Status < 300
+}
+
+// Result describes the outcome for display, e.g. "201" or "failed: EOF".
+func (a Activity) Result() string {
+	if a.Error != "" {
+		return "failed: " + a.Error
+	}
+	return strconv.Itoa(a.Status)
+}
+
+// ActivityLogPath returns ~/.kit/activity.log, or KIT_ACTIVITY_LOG if set.
+// KIT_ACTIVITY_LOG=off turns the log off.
+func ActivityLogPath() string {
+	if p := os.Getenv("KIT_ACTIVITY_LOG"); p != "" {
+		if p == "off" {
+			return ""
+		}
+		return p
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".kit", "activity.log")
+}
+
+// RecordActivity appends a to the activity log. Like Logger.Log it is
+// best-effort: a log that cannot be written never fails the change itself.
+func RecordActivity(a Activity) {
+	path := ActivityLogPath()
+	if path == "" {
+		return
+	}
+	if a.Command == "" {
+		a.Command = cmdlog.Command()
+	}
+	cmdlog.Append(path, a)
+}
+
+// ReadActivity reads all activities from the log at path.
+func ReadActivity(path string) ([]Activity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var activities []Activity
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var a Activity
+		if err := json.Unmarshal([]byte(line), &a); err != nil {
+			continue // skip malformed lines
+		}
+		activities = append(activities, a)
+	}
+	return activities, nil
+}
+
+// ActivityFilter selects activities; zero fields match everything.
+type ActivityFilter struct {
+	Since      time.Time
+	Method     string // e.g. "DELETE"
+	Command    string // substring of the command path
+	Target     string // substring of the endpoint or target
+	FailedOnly bool
+}
+
+// FilterActivity returns the activities matching f.
+func FilterActivity(activities []Activity, f ActivityFilter) []Activity {
+	var result []Activity
+	for _, a := range activities {
+		if !f.Since.IsZero() && a.Timestamp.Before(f.Since) {
+			continue
+		}
+		if f.Method != "" && !strings.EqualFold(a.Method, f.Method) {
+			continue
+		}
+		if f.Command != "" && !strings.Contains(a.Command, f.Command) {
+			continue
+		}
+		if f.Target != "" && !strings.Contains(strings.ToLower(a.Endpoint+" "+a.Target), strings.ToLower(f.Target)) {
+			continue
+		}
+		if f.FailedOnly && a.Succeeded() {
+			continue
+		}
+		result = append(result, a)
+	}
+	return result
+}
+
+// readOnlyPosts are Graph POST actions that read rather than change data.
+var readOnlyPosts = []string{"/search/query", "/getSchedule", "/findMeetingTimes", "/getMemberGroups", "/checkMemberGroups"}
+
+// IsMutation reports whether a Graph request with method and path changes
+// data and so belongs in the activity log.
+func IsMutation(method, path string) bool {
+	switch method {
+	case "POST":
+		for _, p := range readOnlyPosts {
+			if strings.HasSuffix(path, p) {
+				return false
+			}
+		}
+		return true
+	case "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+// IsBatch reports whether a Graph request is a JSON $batch call. Its
+// sub-requests are recorded one by one with BatchActivities rather than as
+// a single opaque POST.
+func IsBatch(method, path string) bool {
+	return method == "POST" && strings.HasSuffix(path, "/$batch")
+}
+
+// BatchActivities returns an activity for each sub-request of a $batch
+// call that changes data, given the batch's path and its request and
+// response bodies. Each copies base and takes its method, endpoint, target
+// and status from its sub-request. When the batch as a whole failed,
+// response is nil and every activity keeps base's status and error.
+func BatchActivities(base Activity, path string, request, response []byte) []Activity {
+	var batch struct {
+		Requests []struct {
+			ID     string `json:"id"`
+			Method string `json:"method"`
+			URL    string `json:"url"`
+		} `json:"requests"`
+	}
+	if json.Unmarshal(request, &batch) != nil {
+		return nil
+	}
+	var result struct {
+		Responses []struct {
+			ID     string `json:"id"`
+			Status int    `json:"status"`
+		} `json:"responses"`
+	}
+	status := make(map[string]int)
+	if response != nil && json.Unmarshal(response, &result) == nil {
+		for _, r := range result.Responses {
+			status[r.ID] = r.Status
+		}
+	}
+
+	// Sub-request URLs are relative to the batch's API version
+	version := strings.TrimSuffix(path, "/$batch")
+	var activities []Activity
+	for _, r := range batch.Requests {
+		endpoint, _, _ := strings.Cut(r.URL, "?")
+		endpoint = version + "/" + strings.TrimPrefix(endpoint, "/")
+		method := strings.ToUpper(r.Method)
+		if !IsMutation(method, endpoint) {
+			continue
+		}
+		a := base
+		a.Method, a.Endpoint, a.Target = method, endpoint, TargetOf(endpoint)
+		if response != nil {
+			a.Status = status[r.ID]
+		}
+		activities = append(activities, a)
+	}
+	return activities
+}
+
+// TargetOf returns a readable name for what a Graph path acts on: the
+// OneDrive or SharePoint path of an item addressed by path, or the
+// mailbox of another user. Paths addressed by ID return "".
+func TargetOf(path string) string {
+	if i := strings.Index(path, "root:/"); i >= 0 {
+		rest := path[i+len("root:/"):]
+		if j := strings.Index(rest, ":"); j >= 0 {
+			rest = rest[:j]
+		}
+		if p, err := url.PathUnescape(rest); err == nil {
+			rest = p
+		}
+		return "/" + rest
+	}
+	if rest, ok := strings.CutPrefix(versionless(path), "/users/"); ok {
+		user, _, _ := strings.Cut(rest, "/")
+		if u, err := url.PathUnescape(user); err == nil {
+			return u
+		}
+		return user
+	}
+	return ""
+}
+
+// versionless strips the /v1.0 or /beta prefix from a Graph path.
+func versionless(path string) string {
+	for _, v := range []string{"/v1.0", "/beta"} {
+		if rest, ok := strings.CutPrefix(path, v); ok {
+			return rest
+		}
+	}
+	return path
+}
@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klytics/m365kit/internal/cmdlog"
+)
+
+func TestRecordAndReadActivity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.log")
+	t.Setenv("KIT_ACTIVITY_LOG", path)
+	cmdlog.SetCommand("kit onedrive upload")
+	defer cmdlog.SetCommand("")
+
+	RecordActivity(Activity{Timestamp: time.Now(), Method: "PUT", Endpoint: "/v1.0/me/drive/root:/a.txt:/content", Status: 201})
+	RecordActivity(Activity{Timestamp: time.Now(), Method: "DELETE", Endpoint: "/v1.0/me/drive/items/1", Command: "kit onedrive rm", Error: "EOF"})
+
+	activities, err := ReadActivity(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 2 {
+		t.Fatalf("got %d activities", len(activities))
+	}
+	if activities[0].Command != "kit onedrive upload" || !activities[0].Succeeded() {
+		t.Errorf("first = %+v", activities[0])
+	}
+	if activities[1].Command != "kit onedrive rm" || activities[1].Result() != "failed: EOF" {
+		t.Errorf("second = %+v", activities[1])
+	}
+}
+
+func TestRecordActivityOff(t *testing.T) {
+	t.Setenv("KIT_ACTIVITY_LOG", "off")
+	if ActivityLogPath() != "" {
+		t.Error("expected no path")
+	}
+	RecordActivity(Activity{Method: "POST"}) // must not panic or write
+}
+
+func TestFilterActivity(t *testing.T) {
+	now := time.Now()
+	activities := []Activity{
+		{Timestamp: now.Add(-10 * 24 * time.Hour), Method: "POST", Endpoint: "/v1.0/me/sendMail", Status: 202},
+		{Timestamp: now.Add(-time.Hour), Method: "DELETE", Endpoint: "/v1.0/me/drive/items/1", Status: 404},
+		{Timestamp: now, Method: "PUT", Endpoint: "/v1.0/me/drive/root:/Reports/q3.xlsx:/content", Target: "/Reports/q3.xlsx", Status: 201},
+	}
+
+	if got := FilterActivity(activities, ActivityFilter{Since: now.Add(-7 * 24 * time.Hour)}); len(got) != 2 {
+		t.Errorf("since: got %d", len(got))
+	}
+	if got := FilterActivity(activities, ActivityFilter{Method: "delete"}); len(got) != 1 {
+		t.Errorf("method: got %d", len(got))
+	}
+	if got := FilterActivity(activities, ActivityFilter{Target: "/reports"}); len(got) != 1 {
+		t.Errorf("target: got %d", len(got))
+	}
+	if got := FilterActivity(activities, ActivityFilter{FailedOnly: true}); len(got) != 1 || got[0].Status != 404 {
+		t.Errorf("failed: got %+v", got)
+	}
+}
+
+func TestIsMutation(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         bool
+	}{
+		{"GET", "/v1.0/me/messages", false},
+		{"POST", "/v1.0/me/sendMail", true},
+		{"POST", "/v1.0/search/query", false},
+		{"PATCH", "/v1.0/me/messages/1", true},
+		{"DELETE", "/v1.0/me/drive/items/1/permissions/2", true},
+	}
+	for _, tt := range tests {
+		if got := IsMutation(tt.method, tt.path); got != tt.want {
+			t.Errorf("IsMutation(%s %s) = %v", tt.method, tt.path, got)
+		}
+	}
+}
+
+func TestTargetOf(t *testing.T) {
+	tests := []struct{ path, want string }{
+		{"/v1.0/me/drive/root:/Reports/Q3%20Summary.docx:/content", "/Reports/Q3 Summary.docx"},
+		{"/v1.0/drives/b!x/root:/Shared/a.txt:/createUploadSession", "/Shared/a.txt"},
+		{"/v1.0/users/anna@contoso.com/sendMail", "anna@contoso.com"},
+		{"/v1.0/me/drive/items/01ABC", ""},
+	}
+	for _, tt := range tests {
+		if got := TargetOf(tt.path); got != tt.want {
+			t.Errorf("TargetOf(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestBatchActivities(t *testing.T) {
+	request := []byte(`{"requests":[
+		{"id":"1","method":"PATCH","url":"/me/messages/a?$select=id"},
+		{"id":"2","method":"GET","url":"/me/messages/b"},
+		{"id":"3","method":"POST","url":"/me/messages/c/move"}]}`)
+	response := []byte(`{"responses":[{"id":"3","status":201},{"id":"1","status":429}]}`)
+
+	got := BatchActivities(Activity{Profile: "contoso"}, "/v1.0/$batch", request, response)
+	if len(got) != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	if got[0].Method != "PATCH" || got[0].Endpoint != "/v1.0/me/messages/a" || got[0].Status != 429 || got[0].Profile != "contoso" {
+		t.Errorf("first = %+v", got[0])
+	}
+	if got[1].Method != "POST" || got[1].Endpoint != "/v1.0/me/messages/c/move" || got[1].Status != 201 {
+		t.Errorf("second = %+v", got[1])
+	}
+
+	// A batch that failed as a whole marks every change with its error
+	failed := BatchActivities(Activity{Error: "EOF"}, "/v1.0/$batch", request, nil)
+	if len(failed) != 2 || failed[0].Result() != "failed: EOF" {
+		t.Errorf("failed = %+v", failed)
+	}
+}
@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// browserTimeout is how long BrowserFlow waits for the user to finish
+// signing in.
+const browserTimeout = 5 * time.Minute
+
+// OpenBrowser opens url in the user's default browser.
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// BrowserFlow signs in with the authorization code flow and PKCE: it
+// listens on a localhost port for the redirect, has open show the sign-in
+// page, and exchanges the returned code for a token. The app registration
+// needs "http://localhost" as a mobile and desktop redirect URI.
+func BrowserFlow(ctx context.Context, clientID string, open func(url string) error) (*Token, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("no client ID — set KIT_AZURE_CLIENT_ID or pass --client-id to kit auth login\nSee: kit auth --help")
+	}
+
+	verifier, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not start the sign-in listener: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://localhost:%d", ln.Addr().(*net.TCPAddr).Port)
+
+	type result struct {
+		code string
+		err  error
+	}
+	done := make(chan result, 1)
+	server := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("code") == "" && q.Get("error") == "" {
+				http.NotFound(w, r) // e.g. the browser asking for /favicon.ico
+				return
+			}
+			var res result
+			switch {
+			case q.Get("state") != state:
+				res.err = fmt.Errorf("sign-in response did not match this request — run: %s --browser to try again", loginCommand())
+			case q.Get("error") != "":
+				res.err = fmt.Errorf("authentication failed: %s — %s", q.Get("error"), q.Get("error_description"))
+			default:
+				res.code = q.Get("code")
+			}
+
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if res.err != nil {
+				fmt.Fprintf(w, "<html><body><h3>Sign-in failed</h3><p>%s</p></body></html>", html.EscapeString(res.err.Error()))
+			} else {
+				io.WriteString(w, "<html><body><h3>Signed in to kit</h3><p>You can close this tab.</p></body></html>")
+			}
+			select {
+			case done <- res:
+			default:
+			}
+		}),
+	}
+	go server.Serve(ln)
+	defer server.Close()
+
+	params := url.Values{
+		"client_id":             {clientID},
+		"response_type":         {"code"},
+		"redirect_uri":          {redirectURI},
+		"response_mode":         {"query"},
+		"scope":                 {scopeParam()},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+		"prompt":                {"select_account"},
+	}
+	authURL := authority() + "/authorize?" + params.Encode()
+	if err := open(authURL); err != nil {
+		fmt.Printf("Could not open a browser (%v)\n", err)
+	}
+	fmt.Printf("If the browser did not open, visit:\n  %s\n", authURL)
+	fmt.Println("Waiting for sign-in...")
+
+	var res result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(browserTimeout):
+		return nil, fmt.Errorf("browser sign-in timed out — run: %s --browser to try again", loginCommand())
+	case res = <-done:
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	token, err := redeemCode(clientID, res.code, redirectURI, verifier)
+	if err != nil {
+		return nil, err
+	}
+	if err := SaveToken(token); err != nil {
+		return nil, fmt.Errorf("authenticated but could not save token: %w", err)
+	}
+	return token, nil
+}
+
+// redeemCode exchanges an authorization code and its PKCE verifier for a
+// token.
+func redeemCode(clientID, code, redirectURI, verifier string) (*Token, error) {
+	resp, err := postForm(authority()+"/token", url.Values{
+		"client_id":     {clientID},
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+		"scope":         {scopeParam()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("could not parse token response: %w", err)
+	}
+	if tr.Error != "" {
+		return nil, fmt.Errorf("authentication failed: %s — %s", tr.Error, tr.ErrorDesc)
+	}
+
+	return &Token{
+		AccessToken:  tr.AccessToken,
+		RefreshToken: tr.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second),
+		TokenType:    tr.TokenType,
+		Scope:        tr.Scope,
+	}, nil
+}
+
+// randomString returns n random bytes, base64url-encoded.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
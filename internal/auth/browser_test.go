@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBrowserFlow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KIT_PROFILE", "")
+	TokenPathOverride = filepath.Join(t.TempDir(), "token.json")
+	defer func() { TokenPathOverride = "" }()
+
+	var challenge string
+	login := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/common/oauth2/v2.0/token" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if r.Form.Get("grant_type") != "authorization_code" || r.Form.Get("code") != "the-code" ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			t.Errorf("token request = %v", r.Form)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "at", "refresh_token": "rt", "expires_in": 3600, "token_type": "Bearer"})
+	}))
+	defer login.Close()
+	defer func(base string) { loginBase = base }(loginBase)
+	loginBase = login.URL
+
+	// Stands in for the browser: checks the sign-in URL, then follows the
+	// redirect as Azure AD would after the user signs in.
+	open := func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		q := u.Query()
+		if !strings.HasSuffix(u.Path, "/common/oauth2/v2.0/authorize") || q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "app" {
+			t.Errorf("authorize URL = %s", authURL)
+		}
+		challenge = q.Get("code_challenge")
+		go func() {
+			resp, err := http.Get(q.Get("redirect_uri") + "/?code=the-code&state=" + url.QueryEscape(q.Get("state")))
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	token, err := BrowserFlow(context.Background(), "app", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "at" || token.RefreshToken != "rt" {
+		t.Errorf("token = %+v", token)
+	}
+	if saved, err := LoadToken(); err != nil || saved.AccessToken != "at" {
+		t.Errorf("saved token = %+v, %v", saved, err)
+	}
+}
+
+func TestBrowserFlowRejectsWrongState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	open := func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		go func() {
+			resp, err := http.Get(u.Query().Get("redirect_uri") + "/?code=x&state=forged")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+	_, err := BrowserFlow(context.Background(), "app", open)
+	if err == nil || !strings.Contains(err.Error(), "did not match") {
+		t.Errorf("err = %v", err)
+	}
+}
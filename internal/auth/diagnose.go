@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Claims are the fields of an access token that kit auth status reports.
+type Claims struct {
+	TenantID          string `json:"tid"`
+	AppID             string `json:"appid"`
+	Audience          string `json:"aud"`
+	Name              string `json:"name"`
+	UPN               string `json:"upn"`
+	PreferredUsername string `json:"preferred_username"`
+	Scope             string `json:"scp"`
+	IssuedAt          int64  `json:"iat"`
+	ExpiresAt         int64  `json:"exp"`
+}
+
+// Account returns the signed-in account's user name.
+func (c *Claims) Account() string {
+	if c.UPN != "" {
+		return c.UPN
+	}
+	return c.PreferredUsername
+}
+
+// Scopes returns the delegated permissions in the token.
+func (c *Claims) Scopes() []string {
+	return ParseScopes(c.Scope)
+}
+
+// Expiry returns when the token expires.
+func (c *Claims) Expiry() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// DecodeClaims reads the claims of a JWT access token without verifying
+// its signature — they are for display only. Tokens for personal Microsoft
+// accounts are opaque and return an error.
+func DecodeClaims(accessToken string) (*Claims, error) {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("the access token is not a JWT (personal Microsoft accounts use opaque tokens)")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the access token: %w", err)
+	}
+	var c Claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, fmt.Errorf("could not parse the access token claims: %w", err)
+	}
+	return &c, nil
+}
+
+// Feature is a kit feature that kit auth status checks with a Graph call.
+type Feature struct {
+	Name     string // e.g. "OneDrive"
+	Commands string // the commands that depend on it
+	Path     string // a cheap Graph GET that needs the same access
+}
+
+// Features are the probes kit auth status runs.
+var Features = []Feature{
+	{Name: "Profile", Commands: "kit auth whoami", Path: "/me?$select=id"},
+	{Name: "OneDrive", Commands: "kit onedrive", Path: "/me/drive?$select=id"},
+	{Name: "SharePoint", Commands: "kit sharepoint, kit acl", Path: "/sites/root?$select=id"},
+	{Name: "Mail", Commands: "kit outlook, kit watch mail", Path: "/me/mailFolders/inbox?$select=id"},
+	{Name: "Calendar", Commands: "kit calendar", Path: "/me/events?$top=1&$select=id"},
+	{Name: "Contacts", Commands: "kit contacts", Path: "/me/contacts?$top=1&$select=id"},
+	{Name: "Teams", Commands: "kit teams", Path: "/me/joinedTeams?$select=id"},
+	{Name: "Chats", Commands: "kit teams dm, kit teams chat", Path: "/me/chats?$top=1&$select=id"},
+}
+
+// Probe statuses.
+const (
+	ProbeOK          = "ok"
+	ProbeDenied      = "denied"      // the token lacks a permission
+	ProbeUnavailable = "unavailable" // e.g. no mailbox or license for the service
+	ProbeError       = "error"
+)
+
+// probeTimeout bounds each feature probe.
+const probeTimeout = 15 * time.Second
+
+// ProbeResult is the outcome of probing one feature.
+type ProbeResult struct {
+	Feature  string `json:"feature"`
+	Commands string `json:"commands"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Scope    string `json:"scope,omitempty"` // the permission to add, when denied
+}
+
+// ProbeFeatures calls each of features concurrently with client (from
+// RequireAuth) and reports which ones the signed-in user can use.
+func ProbeFeatures(ctx context.Context, client *http.Client, features []Feature) []ProbeResult {
+	results := make([]ProbeResult, len(features))
+	var wg sync.WaitGroup
+	for i, f := range features {
+		wg.Add(1)
+		go func(i int, f Feature) {
+			defer wg.Done()
+			results[i] = probe(ctx, client, f)
+		}(i, f)
+	}
+	wg.Wait()
+	return results
+}
+
+func probe(ctx context.Context, client *http.Client, f Feature) ProbeResult {
+	result := ProbeResult{Feature: f.Name, Commands: f.Commands}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", GraphBaseURL()+f.Path, nil)
+	if err != nil {
+		result.Status, result.Message = ProbeError, err.Error()
+		return result
+	}
+	resp, err := client.Do(req)
+	var scopeErr *ScopeError
+	switch {
+	case errors.As(err, &scopeErr):
+		result.Status, result.Scope = ProbeDenied, scopeErr.Scope
+		result.Message = fmt.Sprintf("missing permission %s — run: %s --add-scope %s", scopeErr.Scope, loginCommand(), scopeErr.Scope)
+		return result
+	case err != nil:
+		result.Status, result.Message = ProbeError, err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	var graphErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.Unmarshal(body, &graphErr)
+	result.Message = graphErr.Error.Message
+
+	switch {
+	case resp.StatusCode < 300:
+		result.Status = ProbeOK
+	case resp.StatusCode == http.StatusForbidden:
+		result.Status = ProbeDenied
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusBadRequest:
+		// Graph answers this way when the user has no mailbox, OneDrive,
+		// or SharePoint license
+		result.Status = ProbeUnavailable
+	default:
+		result.Status = ProbeError
+		if result.Message == "" {
+			result.Message = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		}
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDecodeClaims(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"tid":"t-1","appid":"app-1","upn":"anna@contoso.com","scp":"Mail.Read User.Read","exp":1900000000}`))
+	claims, err := DecodeClaims("eyJhbGciOiJub25lIn0." + payload + ".sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.TenantID != "t-1" || claims.AppID != "app-1" || claims.Account() != "anna@contoso.com" {
+		t.Errorf("claims = %+v", claims)
+	}
+	if scopes := claims.Scopes(); len(scopes) != 2 || scopes[0] != "Mail.Read" {
+		t.Errorf("scopes = %v", scopes)
+	}
+	if claims.Expiry().Unix() != 1900000000 {
+		t.Errorf("expiry = %v", claims.Expiry())
+	}
+
+	if _, err := DecodeClaims("EwBwA8l6BAAU-opaque"); err == nil {
+		t.Error("expected an error for an opaque token")
+	}
+}
+
+// graphResponses answers each path with a canned status and Graph error.
+type graphResponses map[string]struct {
+	status int
+	code   string
+}
+
+func (g graphResponses) RoundTrip(req *http.Request) (*http.Response, error) {
+	r, ok := g[req.URL.Path]
+	if !ok {
+		r.status = http.StatusOK
+	}
+	body := `{}`
+	if r.code != "" {
+		body = `{"error":{"code":"` + r.code + `","message":"` + r.code + ` message"}}`
+	}
+	return &http.Response{StatusCode: r.status, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+func TestProbeFeatures(t *testing.T) {
+	t.Setenv("KIT_PROFILE", "")
+	base := graphResponses{
+		"/v1.0/sites/root":           {http.StatusForbidden, "Authorization_RequestDenied"},
+		"/v1.0/me/mailFolders/inbox": {http.StatusNotFound, "MailboxNotEnabledForRESTAPI"},
+		"/v1.0/me/chats":             {http.StatusInternalServerError, ""},
+	}
+	client := &http.Client{Transport: &BearerTransport{Token: "tok", Granted: []string{"User.Read", "Files.ReadWrite"}, Base: base}}
+
+	features := []Feature{
+		{Name: "Profile", Path: "/me?$select=id"},
+		{Name: "SharePoint", Path: "/sites/root?$select=id"},
+		{Name: "Mail", Path: "/me/mailFolders/inbox?$select=id"},
+		{Name: "Chats", Path: "/me/chats?$top=1"},
+	}
+	results := ProbeFeatures(context.Background(), client, features)
+
+	want := map[string]string{"Profile": ProbeOK, "SharePoint": ProbeDenied, "Mail": ProbeUnavailable, "Chats": ProbeError}
+	for _, r := range results {
+		if r.Status != want[r.Feature] {
+			t.Errorf("%s: status = %s (%s), want %s", r.Feature, r.Status, r.Message, want[r.Feature])
+		}
+	}
+	if results[1].Scope != "Sites.ReadWrite.All" || !strings.Contains(results[1].Message, "kit auth login --add-scope Sites.ReadWrite.All") {
+		t.Errorf("SharePoint = %+v", results[1])
+	}
+}
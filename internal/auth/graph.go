@@ -10,26 +10,32 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
+
+	"github.com/klytics/m365kit/internal/cloud"
+	"github.com/klytics/m365kit/internal/filelock"
+	"github.com/klytics/m365kit/internal/httpclient"
 )
 
 const (
-	graphBaseURL   = "https://graph.microsoft.com/v1.0"
-	authorityBase  = "https://login.microsoftonline.com/common/oauth2/v2.0"
-	defaultScopes  = "Files.ReadWrite Sites.ReadWrite.All User.Read Chat.ReadWrite ChannelMessage.Send Team.ReadBasic.All Mail.Read Mail.ReadWrite offline_access"
+	defaultScopes  = "Files.ReadWrite Sites.ReadWrite.All User.Read Chat.ReadWrite ChannelMessage.Send Team.ReadBasic.All Mail.Read Mail.ReadWrite Mail.Send offline_access"
 	tokenFileName  = "token.json"
 	refreshWindow  = 5 * time.Minute
 	pollInterval   = 5 * time.Second
 	deviceTimeout  = 5 * time.Minute
 )
 
+// loginBase overrides the configured cloud's Microsoft identity platform
+// host; tests point it at a local server.
+var loginBase string
+
 // Token holds the OAuth 2.0 tokens from Microsoft.
 type Token struct {
 	AccessToken  string    `json:"access_token"`
 	RefreshToken string    `json:"refresh_token"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	TokenType    string    `json:"token_type"`
+	Scope        string    `json:"scope,omitempty"` // Granted scopes, space-separated
 }
 
 // IsExpired returns true if the token has expired.
@@ -61,20 +67,66 @@ type tokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int    `json:"expires_in"`
 	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
 	Error        string `json:"error"`
 	ErrorDesc    string `json:"error_description"`
 }
 
-// DeviceCodeFlow initiates the OAuth device code flow.
-func DeviceCodeFlow(ctx context.Context, clientID string) (*Token, error) {
+// authority returns the identity platform endpoint for the active
+// profile's tenant, or the multi-tenant "common" endpoint.
+func authority() string {
+	tenant := "common"
+	if p, err := LoadProfile(CurrentProfile()); err == nil && p.Tenant != "" {
+		tenant = p.Tenant
+	}
+	base := loginBase
+	if base == "" {
+		base = cloud.Current().LoginURL
+	}
+	return base + "/" + url.PathEscape(tenant) + "/oauth2/v2.0"
+}
+
+// postForm posts to the identity platform through the configured HTTP client,
+// so proxy and CA settings apply to sign-in as well as Graph calls.
+func postForm(endpoint string, data url.Values) (*http.Response, error) {
+	client, err := httpclient.New()
+	if err != nil {
+		return nil, err
+	}
+	return client.PostForm(endpoint, data)
+}
+
+// DeviceCode is what the user needs to finish a device code sign-in.
+type DeviceCode struct {
+	UserCode        string    `json:"userCode"`
+	VerificationURI string    `json:"verificationUri"`
+	Message         string    `json:"message"` // Microsoft's instructions, localized
+	ExpiresAt       time.Time `json:"expiresAt"`
+}
+
+// DevicePrompt shows a device code to the user. Commands print it; the
+// shell, a watch daemon, or a server can instead send it as an event, a
+// Teams message, or a log line. An error cancels the sign-in.
+type DevicePrompt func(DeviceCode) error
+
+// PrintDeviceCode is the DevicePrompt that writes the code to stdout.
+func PrintDeviceCode(dc DeviceCode) error {
+	fmt.Printf("Open %s and enter code: %s\n", dc.VerificationURI, dc.UserCode)
+	fmt.Println("Waiting for authorization...")
+	return nil
+}
+
+// DeviceCodeFlow initiates the OAuth device code flow, showing the code
+// with prompt (PrintDeviceCode if nil) while it waits for the user.
+func DeviceCodeFlow(ctx context.Context, clientID string, prompt DevicePrompt) (*Token, error) {
 	if clientID == "" {
-		return nil, fmt.Errorf("KIT_AZURE_CLIENT_ID is not set — register an Azure AD app and set this environment variable\nSee: kit auth --help")
+		return nil, fmt.Errorf("no client ID — set KIT_AZURE_CLIENT_ID or pass --client-id to kit auth login\nSee: kit auth --help")
 	}
 
 	// Step 1: Request device code
-	resp, err := http.PostForm(authorityBase+"/devicecode", url.Values{
+	resp, err := postForm(authority()+"/devicecode", url.Values{
 		"client_id": {clientID},
-		"scope":     {defaultScopes},
+		"scope":     {scopeParam()},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not contact Microsoft login service: %w", err)
@@ -92,8 +144,21 @@ func DeviceCodeFlow(ctx context.Context, clientID string) (*Token, error) {
 	}
 
 	// Step 2: Display instructions
-	fmt.Printf("Open %s and enter code: %s\n", dcResp.VerificationURI, dcResp.UserCode)
-	fmt.Println("Waiting for authorization...")
+	if prompt == nil {
+		prompt = PrintDeviceCode
+	}
+	expiresAt := time.Now().Add(deviceTimeout)
+	if dcResp.ExpiresIn > 0 {
+		expiresAt = time.Now().Add(time.Duration(dcResp.ExpiresIn) * time.Second)
+	}
+	if err := prompt(DeviceCode{
+		UserCode:        dcResp.UserCode,
+		VerificationURI: dcResp.VerificationURI,
+		Message:         dcResp.Message,
+		ExpiresAt:       expiresAt,
+	}); err != nil {
+		return nil, err
+	}
 
 	// Step 3: Poll for token
 	interval := pollInterval
@@ -110,7 +175,7 @@ func DeviceCodeFlow(ctx context.Context, clientID string) (*Token, error) {
 		}
 
 		if time.Now().After(deadline) {
-			return nil, fmt.Errorf("device code authorization timed out — run: kit auth login to try again")
+			return nil, fmt.Errorf("device code authorization timed out — run: %s to try again", loginCommand())
 		}
 
 		token, err := pollToken(clientID, dcResp.DeviceCode)
@@ -134,7 +199,7 @@ func DeviceCodeFlow(ctx context.Context, clientID string) (*Token, error) {
 }
 
 func pollToken(clientID, deviceCode string) (*Token, error) {
-	resp, err := http.PostForm(authorityBase+"/token", url.Values{
+	resp, err := postForm(authority()+"/token", url.Values{
 		"client_id":   {clientID},
 		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
 		"device_code": {deviceCode},
@@ -155,7 +220,7 @@ func pollToken(clientID, deviceCode string) (*Token, error) {
 			return nil, fmt.Errorf(tr.Error)
 		}
 		if tr.Error == "expired_token" {
-			return nil, fmt.Errorf("authorization code expired — run: kit auth login to try again")
+			return nil, fmt.Errorf("authorization code expired — run: %s to try again", loginCommand())
 		}
 		return nil, fmt.Errorf("authentication failed: %s — %s", tr.Error, tr.ErrorDesc)
 	}
@@ -165,6 +230,7 @@ func pollToken(clientID, deviceCode string) (*Token, error) {
 		RefreshToken: tr.RefreshToken,
 		ExpiresAt:    time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second),
 		TokenType:    tr.TokenType,
+		Scope:        tr.Scope,
 	}, nil
 }
 
@@ -173,15 +239,42 @@ func RefreshIfNeeded(ctx context.Context, t *Token, clientID string) (*Token, er
 	if !t.NeedsRefresh() {
 		return t, nil
 	}
+	return refresh(ctx, t, clientID)
+}
+
+// refresh redeems the token's refresh token for a new one and saves it.
+// It holds the token file's lock throughout, and returns the saved token
+// instead when another process refreshed t while it waited.
+func refresh(ctx context.Context, t *Token, clientID string) (*Token, error) {
+	path, err := TokenPath()
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := filelock.Lock(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if saved, err := LoadToken(); err == nil && saved.AccessToken != t.AccessToken && !saved.NeedsRefresh() {
+		return saved, nil
+	}
+
 	if t.RefreshToken == "" {
-		return nil, fmt.Errorf("token expired and no refresh token available — run: kit auth login")
+		return nil, fmt.Errorf("token expired and no refresh token available — run: %s", loginCommand())
 	}
 
-	resp, err := http.PostForm(authorityBase+"/token", url.Values{
+	// Ask again for what was granted, so a scope added to the config
+	// since sign-in does not make the refresh fail for want of consent.
+	scope := t.Scope
+	if scope == "" {
+		scope = scopeParam()
+	}
+	resp, err := postForm(authority()+"/token", url.Values{
 		"client_id":     {clientID},
 		"grant_type":    {"refresh_token"},
 		"refresh_token": {t.RefreshToken},
-		"scope":         {defaultScopes},
+		"scope":         {scope},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("token refresh request failed: %w", err)
@@ -195,7 +288,7 @@ func RefreshIfNeeded(ctx context.Context, t *Token, clientID string) (*Token, er
 	}
 
 	if tr.Error != "" {
-		return nil, fmt.Errorf("token refresh failed: %s — run: kit auth login", tr.ErrorDesc)
+		return nil, fmt.Errorf("token refresh failed: %s — run: %s", tr.ErrorDesc, loginCommand())
 	}
 
 	newToken := &Token{
@@ -203,6 +296,7 @@ func RefreshIfNeeded(ctx context.Context, t *Token, clientID string) (*Token, er
 		RefreshToken: tr.RefreshToken,
 		ExpiresAt:    time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second),
 		TokenType:    tr.TokenType,
+		Scope:        tr.Scope,
 	}
 
 	if err := SaveToken(newToken); err != nil {
@@ -221,9 +315,14 @@ func tokenDir() (string, error) {
 	return filepath.Join(home, ".kit"), nil
 }
 
-// tokenPath returns the full path to the token file.
-func tokenPath() (string, error) {
-	dir, err := tokenDir()
+// TokenPath returns the path to the active profile's token file:
+// ~/.kit/token.json for the default profile, and
+// ~/.kit/profiles/<name>/token.json for the others.
+func TokenPath() (string, error) {
+	if TokenPathOverride != "" {
+		return TokenPathOverride, nil
+	}
+	dir, err := profileDir(CurrentProfile())
 	if err != nil {
 		return "", err
 	}
@@ -233,39 +332,43 @@ func tokenPath() (string, error) {
 // TokenPathOverride allows tests to override the token path.
 var TokenPathOverride string
 
-func resolvedTokenPath() (string, error) {
-	if TokenPathOverride != "" {
-		return TokenPathOverride, nil
-	}
-	return tokenPath()
-}
-
-// LoadToken loads the saved token from ~/.kit/token.json.
+// LoadToken loads the active profile's saved token.
 func LoadToken() (*Token, error) {
-	path, err := resolvedTokenPath()
+	path, err := TokenPath()
 	if err != nil {
 		return nil, err
 	}
 
+	info, err := os.Stat(path)
+	if err == nil {
+		if t, ok := cacheLookup(path, info); ok {
+			return t, nil
+		}
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("not authenticated — run: kit auth login")
+			return nil, fmt.Errorf("not authenticated — run: %s", loginCommand())
 		}
 		return nil, fmt.Errorf("could not read token file: %w", err)
 	}
 
 	var t Token
 	if err := json.Unmarshal(data, &t); err != nil {
-		return nil, fmt.Errorf("token file is corrupted — run: kit auth login")
+		return nil, fmt.Errorf("token file is corrupted — run: %s", loginCommand())
+	}
+	if info != nil {
+		cacheStore(path, info, &t)
 	}
 
 	return &t, nil
 }
 
-// SaveToken persists the token to ~/.kit/token.json with 0600 permissions.
+// SaveToken persists the active profile's token with 0600 permissions,
+// replacing the file atomically.
 func SaveToken(t *Token) error {
-	path, err := resolvedTokenPath()
+	path, err := TokenPath()
 	if err != nil {
 		return err
 	}
@@ -280,19 +383,21 @@ func SaveToken(t *Token) error {
 		return fmt.Errorf("could not marshal token: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := writeFileAtomic(path, data, 0600); err != nil {
 		return fmt.Errorf("could not write token file: %w", err)
 	}
+	cacheDelete(path)
 
 	return nil
 }
 
-// DeleteToken removes the token file.
+// DeleteToken removes the active profile's token file.
 func DeleteToken() error {
-	path, err := resolvedTokenPath()
+	path, err := TokenPath()
 	if err != nil {
 		return err
 	}
+	cacheDelete(path)
 	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("could not delete token: %w", err)
 	}
@@ -301,7 +406,7 @@ func DeleteToken() error {
 
 // WhoAmI returns the display name and email of the authenticated user.
 func WhoAmI(ctx context.Context, client *http.Client) (string, string, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", graphBaseURL+"/me", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", GraphBaseURL()+"/me", nil)
 	if err != nil {
 		return "", "", err
 	}
@@ -334,12 +439,8 @@ func WhoAmI(ctx context.Context, client *http.Client) (string, string, error) {
 	return user.DisplayName, email, nil
 }
 
-// GraphBaseURL returns the base URL for Graph API calls.
+// GraphBaseURL returns the base URL for Graph API calls in the configured
+// cloud.
 func GraphBaseURL() string {
-	return graphBaseURL
-}
-
-// Scopes returns the OAuth scopes as a display-friendly slice.
-func Scopes() []string {
-	return strings.Split(defaultScopes, " ")
+	return cloud.Current().GraphV1()
 }
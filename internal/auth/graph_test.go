@@ -2,10 +2,18 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/klytics/m365kit/internal/audit"
 )
 
 func TestLoadTokenMissingFile(t *testing.T) {
@@ -157,6 +165,9 @@ func TestRequireAuthNoClientID(t *testing.T) {
 	}
 
 	t.Setenv("KIT_AZURE_CLIENT_ID", "")
+	// A build that ships without a default client ID
+	defer func(id string) { DefaultClientID = id }(DefaultClientID)
+	DefaultClientID = ""
 
 	ctx := context.Background()
 	_, err := RequireAuth(ctx)
@@ -170,7 +181,7 @@ func TestRequireAuthNoClientID(t *testing.T) {
 
 func TestDeviceCodeFlowNoClientID(t *testing.T) {
 	ctx := context.Background()
-	_, err := DeviceCodeFlow(ctx, "")
+	_, err := DeviceCodeFlow(ctx, "", nil)
 	if err == nil {
 		t.Fatal("expected error with empty client ID")
 	}
@@ -179,6 +190,41 @@ func TestDeviceCodeFlowNoClientID(t *testing.T) {
 	}
 }
 
+func TestDeviceCodeFlowPrompt(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KIT_PROFILE", "")
+
+	login := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/common/oauth2/v2.0/devicecode" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"device_code": "dc", "user_code": "ABCD-EFGH", "verification_uri": "https://microsoft.com/devicelogin",
+			"expires_in": 900, "interval": 5, "message": "To sign in, ...",
+		})
+	}))
+	defer login.Close()
+	defer func(base string) { loginBase = base }(loginBase)
+	loginBase = login.URL
+
+	// The prompt gets the code, and its error stops the flow before polling
+	declined := errors.New("declined")
+	var got DeviceCode
+	_, err := DeviceCodeFlow(context.Background(), "app", func(dc DeviceCode) error {
+		got = dc
+		return declined
+	})
+	if !errors.Is(err, declined) {
+		t.Fatalf("err = %v", err)
+	}
+	if got.UserCode != "ABCD-EFGH" || got.VerificationURI != "https://microsoft.com/devicelogin" || got.Message != "To sign in, ..." {
+		t.Errorf("prompt got %+v", got)
+	}
+	if until := time.Until(got.ExpiresAt); until < 14*time.Minute || until > 15*time.Minute {
+		t.Errorf("ExpiresAt is %s away", until)
+	}
+}
+
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -187,3 +233,110 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+type headerRecorder struct{ auth []string }
+
+func (h *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.auth = append(h.auth, req.Header.Get("Authorization"))
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestBearerTransportOnlyGraph(t *testing.T) {
+	rec := &headerRecorder{}
+	client := &http.Client{Transport: &BearerTransport{Token: "tok", Base: rec}}
+
+	for _, u := range []string{
+		"https://graph.microsoft.com/v1.0/me",
+		"https://contoso.sharepoint.com/_api/v2.0/drives/d/items/i/uploadSession?guid=x",
+	} {
+		resp, err := client.Get(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if rec.auth[0] != "Bearer tok" {
+		t.Errorf("Graph request Authorization = %q", rec.auth[0])
+	}
+	if rec.auth[1] != "" {
+		t.Errorf("pre-authenticated URL should not carry a token, got %q", rec.auth[1])
+	}
+}
+
+func TestBearerTransportRecordsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.log")
+	t.Setenv("KIT_ACTIVITY_LOG", path)
+
+	client := &http.Client{Transport: &BearerTransport{
+		Token:    "tok",
+		Base:     &headerRecorder{},
+		Activity: &audit.Activity{Profile: "contoso", Account: "anna@contoso.com"},
+	}}
+	for _, req := range []struct{ method, url string }{
+		{"GET", "https://graph.microsoft.com/v1.0/me/drive/root:/a.txt"},
+		{"PUT", "https://graph.microsoft.com/v1.0/me/drive/root:/a.txt:/content"},
+		{"PUT", "https://contoso.sharepoint.com/_api/v2.0/drives/d/items/i/uploadSession?guid=x"},
+	} {
+		r, _ := http.NewRequest(req.method, req.url, nil)
+		resp, err := client.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	// Only the Graph change is recorded
+	activities, err := audit.ReadActivity(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 1 {
+		t.Fatalf("recorded %+v", activities)
+	}
+	a := activities[0]
+	if a.Method != "PUT" || a.Endpoint != "/v1.0/me/drive/root:/a.txt:/content" || a.Target != "/a.txt" ||
+		a.Status != http.StatusOK || a.Account != "anna@contoso.com" || a.Timestamp.IsZero() {
+		t.Errorf("activity = %+v", a)
+	}
+}
+
+func TestBearerTransportRecordsBatchItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.log")
+	t.Setenv("KIT_ACTIVITY_LOG", path)
+
+	const result = `{"responses":[{"id":"1","status":200},{"id":"2","status":204},{"id":"3","status":404}]}`
+	client := &http.Client{Transport: &BearerTransport{
+		Token:    "tok",
+		Base:     offlineGraph{body: result},
+		Activity: &audit.Activity{Account: "anna@contoso.com"},
+	}}
+	batch := `{"requests":[
+		{"id":"1","method":"GET","url":"/me/messages/a"},
+		{"id":"2","method":"PATCH","url":"/me/messages/b"},
+		{"id":"3","method":"DELETE","url":"/users/bob@contoso.com/messages/c"}]}`
+	resp, err := client.Post("https://graph.microsoft.com/v1.0/$batch", "application/json", strings.NewReader(batch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != result {
+		t.Errorf("caller got body %q", body)
+	}
+
+	// The read and the batch call itself are not recorded
+	activities, err := audit.ReadActivity(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 2 {
+		t.Fatalf("recorded %+v", activities)
+	}
+	if a := activities[0]; a.Method != "PATCH" || a.Endpoint != "/v1.0/me/messages/b" || a.Status != 204 || a.Account != "anna@contoso.com" {
+		t.Errorf("first = %+v", a)
+	}
+	if a := activities[1]; a.Method != "DELETE" || a.Target != "bob@contoso.com" || a.Status != 404 {
+		t.Errorf("second = %+v", a)
+	}
+}
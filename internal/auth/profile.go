@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/viper"
+
+	"github.com/klytics/m365kit/internal/cache"
+	"github.com/klytics/m365kit/internal/config"
+)
+
+// DefaultProfile is the profile used when neither --profile nor KIT_PROFILE
+// names one. Its files stay directly in ~/.kit, where kit kept its single
+// token before profiles existed.
+const DefaultProfile = "default"
+
+const profileFileName = "profile.json"
+
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Profile is a named sign-in: an account in a tenant, with the Azure AD app
+// used to sign in to it and any permissions requested beyond the configured
+// set. Each profile keeps its own token.
+type Profile struct {
+	Name        string   `json:"-"`
+	ClientID    string   `json:"client_id,omitempty"`
+	Tenant      string   `json:"tenant,omitempty"` // Tenant ID or domain; empty for any ("common")
+	Account     string   `json:"account,omitempty"`
+	DisplayName string   `json:"display_name,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"` // Added with kit auth login --add-scope
+}
+
+// CurrentProfile returns the name of the active profile: KIT_PROFILE (set by
+// the global --profile flag), or DefaultProfile.
+func CurrentProfile() string {
+	if name := os.Getenv("KIT_PROFILE"); name != "" {
+		return name
+	}
+	return DefaultProfile
+}
+
+// ValidateProfileName checks that name can be used as a profile directory.
+func ValidateProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q — use letters, digits, '.', '-', or '_'", name)
+	}
+	return nil
+}
+
+// profileDir returns the directory holding a profile's files.
+func profileDir(name string) (string, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	dir, err := tokenDir()
+	if err != nil {
+		return "", err
+	}
+	if name == DefaultProfile {
+		return dir, nil
+	}
+	return filepath.Join(dir, "profiles", name), nil
+}
+
+// CacheDir returns the directory of a profile's Graph response cache:
+// ~/.kit/cache/graph for the default profile, and
+// ~/.kit/profiles/<name>/cache/graph for the others.
+func CacheDir(name string) (string, error) {
+	dir, err := profileDir(name)
+	if err != nil {
+		return "", err
+	}
+	return cache.DirFor(dir), nil
+}
+
+// ClearCache deletes the active profile's cached Graph responses, so
+// nothing fetched under one sign-in is served after the next.
+func ClearCache() error {
+	dir, err := CacheDir(CurrentProfile())
+	if err != nil {
+		return err
+	}
+	_, err = cache.Clear(dir)
+	return err
+}
+
+// LoadProfile reads a profile's settings. A profile that was never saved
+// is returned empty rather than as an error.
+func LoadProfile(name string) (*Profile, error) {
+	dir, err := profileDir(name)
+	if err != nil {
+		return nil, err
+	}
+	p := &Profile{Name: name}
+	data, err := os.ReadFile(filepath.Join(dir, profileFileName))
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read profile %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("profile %s is corrupted: %w", name, err)
+	}
+	return p, nil
+}
+
+// SaveProfile writes a profile's settings with 0600 permissions.
+func SaveProfile(p *Profile) error {
+	dir, err := profileDir(p.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("could not create profile directory: %w", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, profileFileName), data, 0600); err != nil {
+		return fmt.Errorf("could not write profile %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// ListProfiles returns every profile that has settings or a token, sorted
+// by name with the default profile first.
+func ListProfiles() ([]Profile, error) {
+	dir, err := tokenDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if profileExists(dir) {
+		names = append(names, DefaultProfile)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not list profiles: %w", err)
+	}
+	var others []string
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != DefaultProfile && ValidateProfileName(e.Name()) == nil && profileExists(filepath.Join(dir, "profiles", e.Name())) {
+			others = append(others, e.Name())
+		}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+
+	profiles := make([]Profile, 0, len(names))
+	for _, name := range names {
+		p, err := LoadProfile(name)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *p)
+	}
+	return profiles, nil
+}
+
+func profileExists(dir string) bool {
+	for _, f := range []string{profileFileName, tokenFileName} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultClientID is the public client used when no app of your own is
+// configured, so kit auth login works without registering one. It is
+// Microsoft's first-party "Microsoft Graph Command Line Tools" app, which
+// any tenant can consent to; distributions can ship their own with
+// -ldflags "-X github.com/klytics/m365kit/internal/auth.DefaultClientID=<id>".
+var DefaultClientID = "14d82eec-204b-4c2f-b7e8-296a70dab67e"
+
+// Client ID sources, as reported by ResolveClientID.
+const (
+	ClientIDFromProfile = "profile"
+	ClientIDFromEnv     = "KIT_AZURE_CLIENT_ID"
+	ClientIDFromConfig  = "azure.client_id"
+	ClientIDFromDefault = "default"
+)
+
+// ClientID returns the Azure AD app client ID for the active profile.
+func ClientID() string {
+	id, _ := ResolveClientID()
+	return id
+}
+
+// ResolveClientID returns the Azure AD app client ID for the active profile
+// and where it came from: the one saved with the profile, then
+// KIT_AZURE_CLIENT_ID, then azure.client_id in ~/.kit/config.yaml, then
+// DefaultClientID.
+func ResolveClientID() (id, source string) {
+	if p, err := LoadProfile(CurrentProfile()); err == nil && p.ClientID != "" {
+		return p.ClientID, ClientIDFromProfile
+	}
+	if id := os.Getenv("KIT_AZURE_CLIENT_ID"); id != "" {
+		return id, ClientIDFromEnv
+	}
+	config.Load()
+	if id := viper.GetString("azure.client_id"); id != "" {
+		return id, ClientIDFromConfig
+	}
+	if DefaultClientID != "" {
+		return DefaultClientID, ClientIDFromDefault
+	}
+	return "", ""
+}
+
+// loginCommand is the command that signs in to the active profile, for
+// error messages.
+func loginCommand() string {
+	if name := CurrentProfile(); name != DefaultProfile {
+		return "kit auth login --profile " + name
+	}
+	return "kit auth login"
+}
+
+// HasToken reports whether a profile has a saved token.
+func HasToken(name string) bool {
+	dir, err := profileDir(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, tokenFileName))
+	return err == nil
+}
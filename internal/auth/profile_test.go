@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klytics/m365kit/internal/cache"
+)
+
+func TestProfileTokenPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KIT_PROFILE", "")
+
+	path, err := TokenPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, ".kit", "token.json"); path != want {
+		t.Errorf("default token path = %s, want %s", path, want)
+	}
+
+	t.Setenv("KIT_PROFILE", "contoso")
+	path, _ = TokenPath()
+	if want := filepath.Join(home, ".kit", "profiles", "contoso", "token.json"); path != want {
+		t.Errorf("contoso token path = %s, want %s", path, want)
+	}
+
+	t.Setenv("KIT_PROFILE", "../evil")
+	if _, err := TokenPath(); err == nil {
+		t.Error("expected error for a profile name with a path")
+	}
+}
+
+func TestProfilesKeepSeparateTokens(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, name := range []string{"default", "contoso", "personal"} {
+		t.Setenv("KIT_PROFILE", name)
+		if err := SaveToken(&Token{AccessToken: "token-" + name, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SaveProfile(&Profile{Name: "contoso", Tenant: "contoso.onmicrosoft.com", Account: "anna@contoso.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("KIT_PROFILE", "contoso")
+	tok, err := LoadToken()
+	if err != nil || tok.AccessToken != "token-contoso" {
+		t.Fatalf("contoso token = %+v, %v", tok, err)
+	}
+	if got := authority(); got != "https://login.microsoftonline.com/contoso.onmicrosoft.com/oauth2/v2.0" {
+		t.Errorf("authority = %s", got)
+	}
+
+	t.Setenv("KIT_PROFILE", "personal")
+	if got := authority(); got != "https://login.microsoftonline.com/common/oauth2/v2.0" {
+		t.Errorf("authority without tenant = %s", got)
+	}
+	if err := DeleteToken(); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	// personal has neither a token nor settings left
+	if len(names) != 2 || names[0] != "default" || names[1] != "contoso" {
+		t.Errorf("profiles = %v", names)
+	}
+	if profiles[1].Account != "anna@contoso.com" || !HasToken("contoso") || HasToken("personal") {
+		t.Errorf("contoso = %+v", profiles[1])
+	}
+}
+
+func TestClientIDPrecedence(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KIT_AZURE_CLIENT_ID", "")
+	t.Setenv("KIT_PROFILE", "contoso")
+
+	if id, source := ResolveClientID(); id != DefaultClientID || source != ClientIDFromDefault {
+		t.Errorf("ResolveClientID() = %q, %q, want the built-in default", id, source)
+	}
+	t.Setenv("KIT_AZURE_CLIENT_ID", "env-app")
+	if got := ClientID(); got != "env-app" {
+		t.Errorf("ClientID() = %q, want the environment's", got)
+	}
+	SaveProfile(&Profile{Name: "contoso", ClientID: "contoso-app"})
+	if got := ClientID(); got != "contoso-app" {
+		t.Errorf("ClientID() = %q, want the profile's", got)
+	}
+}
+
+func TestAuthorityFollowsCloud(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KIT_PROFILE", "")
+	t.Setenv("KIT_AZURE_CLOUD", "usgov")
+
+	if got := authority(); got != "https://login.microsoftonline.us/common/oauth2/v2.0" {
+		t.Errorf("authority = %s", got)
+	}
+	if got := GraphBaseURL(); got != "https://graph.microsoft.us/v1.0" {
+		t.Errorf("GraphBaseURL = %s", got)
+	}
+}
+
+// offlineGraph answers with body, or fails like an unreachable network
+// when body is empty.
+type offlineGraph struct{ body string }
+
+func (g offlineGraph) RoundTrip(req *http.Request) (*http.Response, error) {
+	if g.body == "" {
+		return nil, errors.New("network unreachable")
+	}
+	h := http.Header{"Content-Type": {"application/json"}}
+	return &http.Response{StatusCode: http.StatusOK, Header: h, Body: io.NopCloser(strings.NewReader(g.body)), Request: req}, nil
+}
+
+func TestProfilesKeepSeparateCaches(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KIT_CACHE", "1")
+	const me = "https://graph.microsoft.com/v1.0/me/messages"
+
+	get := func(profile, account, body string) (string, error) {
+		t.Helper()
+		t.Setenv("KIT_PROFILE", profile)
+		if err := SaveProfile(&Profile{Name: profile, Account: account}); err != nil {
+			t.Fatal(err)
+		}
+		client, err := NewClient("token-" + profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct := client.Transport.(*BearerTransport).Base.(*cache.Transport)
+		ct.Base = offlineGraph{body: body}
+		resp, err := client.Get(me)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return string(data), nil
+	}
+
+	if _, err := get("contoso", "anna@contoso.com", `{"value":["contoso mail"]}`); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := get("fabrikam", "anna@fabrikam.com", ""); err == nil {
+		t.Errorf("fabrikam was served %s from contoso's cache", got)
+	}
+	if got, err := get("contoso", "anna@contoso.com", ""); err != nil || got != `{"value":["contoso mail"]}` {
+		t.Errorf("contoso offline: %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".kit", "profiles", "contoso", "cache", "graph")); err != nil {
+		t.Errorf("expected contoso's cache in its profile directory: %v", err)
+	}
+
+	// Signing in again, possibly as someone else, starts afresh
+	if err := ClearCache(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := get("contoso", "anna@contoso.com", ""); err == nil {
+		t.Errorf("served %s after the cache was cleared", got)
+	}
+}
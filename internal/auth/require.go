@@ -1,49 +1,213 @@
 package auth
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
-	"os"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/audit"
+	"github.com/klytics/m365kit/internal/cache"
+	"github.com/klytics/m365kit/internal/cloud"
+	"github.com/klytics/m365kit/internal/httpclient"
 )
 
-// BearerTransport injects the Bearer token into every HTTP request.
+// BearerTransport injects the Bearer token into requests to Microsoft Graph
+// (in any cloud; see package cloud).
+// Pre-authenticated URLs on other hosts (download URLs, upload sessions) are
+// sent without it — upload sessions reject fragments that carry a token.
+// A 403 that reports a missing permission becomes a *ScopeError suggesting
+// the scope to add.
+//
+// With a Source, the token comes from it instead of Token: it is refreshed
+// before it expires, and a request answered with 401 is retried once with
+// a renewed token.
 type BearerTransport struct {
-	Token string
-	Base  http.RoundTripper
+	Token   string
+	Granted []string // Scopes the token was issued with, if known
+	Source  *TokenSource
+	Base    http.RoundTripper
+
+	// Activity, if set, is the profile and account that Graph requests
+	// changing data are recorded under in the activity log.
+	Activity *audit.Activity
 }
 
 // RoundTrip implements http.RoundTripper.
 func (t *BearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req2 := req.Clone(req.Context())
-	req2.Header.Set("Authorization", "Bearer "+t.Token)
 	base := t.Base
 	if base == nil {
 		base = http.DefaultTransport
 	}
-	return base.RoundTrip(req2)
+	if !cloud.IsGraphHost(req.URL.Host) {
+		return base.RoundTrip(req)
+	}
+	if t.Activity != nil && audit.IsBatch(req.Method, req.URL.Path) {
+		return t.roundTripBatch(req, base)
+	}
+	if t.Activity == nil || !audit.IsMutation(req.Method, req.URL.Path) {
+		return t.roundTrip(req, base)
+	}
+
+	start := time.Now()
+	resp, err := t.roundTrip(req, base)
+	a := *t.Activity
+	a.Timestamp, a.DurationMs = start, time.Since(start).Milliseconds()
+	a.Method, a.Endpoint, a.Target = req.Method, req.URL.Path, audit.TargetOf(req.URL.Path)
+	if err != nil {
+		a.Error = err.Error()
+	} else {
+		a.Status = resp.StatusCode
+	}
+	audit.RecordActivity(a)
+	return resp, err
+}
+
+// roundTripBatch sends a $batch call and records each of its sub-requests
+// that changes data, with the status Graph gave that sub-request.
+func (t *BearerTransport) roundTripBatch(req *http.Request, base http.RoundTripper) (*http.Response, error) {
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	} else if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	start := time.Now()
+	resp, err := t.roundTrip(req, base)
+	a := *t.Activity
+	a.Timestamp, a.DurationMs = start, time.Since(start).Milliseconds()
+
+	var result []byte
+	switch {
+	case err != nil:
+		a.Error = err.Error()
+	case resp.StatusCode != http.StatusOK:
+		a.Status = resp.StatusCode
+	default:
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			resp, err = nil, fmt.Errorf("could not read batch response: %w", readErr)
+			a.Error = err.Error()
+			break
+		}
+		result = data
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	for _, item := range audit.BatchActivities(a, req.URL.Path, body, result) {
+		audit.RecordActivity(item)
+	}
+	return resp, err
+}
+
+func (t *BearerTransport) roundTrip(req *http.Request, base http.RoundTripper) (*http.Response, error) {
+	token := t.Token
+	if t.Source != nil {
+		tok, err := t.Source.Token(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		token = tok.AccessToken
+	}
+
+	resp, err := base.RoundTrip(withBearer(req, token))
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && t.Source != nil && (req.Body == nil || req.GetBody != nil) {
+		tok, renewErr := t.Source.Renew(req.Context(), token)
+		if renewErr != nil {
+			return resp, nil
+		}
+		retry := withBearer(req, tok.AccessToken)
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+		resp.Body.Close()
+		resp, err = base.RoundTrip(retry)
+	}
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		return resp, err
+	}
+	return checkScope(req, resp, t.Granted)
 }
 
-// RequireAuth loads and validates the auth token, returning an authenticated HTTP client.
+// withBearer returns a copy of req that carries token.
+func withBearer(req *http.Request, token string) *http.Request {
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", "Bearer "+token)
+	return req2
+}
+
+// RequireAuth loads and validates the auth token, returning an authenticated
+// HTTP client that keeps the token fresh for as long as it is used.
 func RequireAuth(ctx context.Context) (*http.Client, error) {
+	if _, err := cloud.Load(); err != nil {
+		return nil, fmt.Errorf("%w\nFix: kit config set azure.cloud <name>, or set KIT_AZURE_CLOUD", err)
+	}
+
 	token, err := LoadToken()
 	if err != nil {
-		return nil, fmt.Errorf("not authenticated — run: kit auth login\n(requires KIT_AZURE_CLIENT_ID environment variable)")
+		return nil, err
 	}
 
-	clientID := os.Getenv("KIT_AZURE_CLIENT_ID")
+	clientID := ClientID()
 	if clientID == "" {
-		return nil, fmt.Errorf("KIT_AZURE_CLIENT_ID not set — see: kit auth --help")
+		return nil, fmt.Errorf("no client ID for profile %s — set KIT_AZURE_CLIENT_ID or run: %s --client-id <id>", CurrentProfile(), loginCommand())
 	}
 
 	token, err = RefreshIfNeeded(ctx, token, clientID)
 	if err != nil {
-		return nil, fmt.Errorf("token refresh failed: %w\nRun: kit auth login", err)
+		return nil, fmt.Errorf("token refresh failed: %w\nRun: %s", err, loginCommand())
 	}
 
-	client := &http.Client{
-		Transport: &BearerTransport{Token: token.AccessToken},
+	if missing := token.MissingScopes(requiredScopes); len(missing) > 0 {
+		return nil, fmt.Errorf("this command needs the %s permission, which profile %s was not granted — run: %s --add-scope %s",
+			strings.Join(missing, ", "), CurrentProfile(), loginCommand(), strings.Join(missing, ","))
 	}
 
+	client, err := NewClient(token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	transport := client.Transport.(*BearerTransport)
+	transport.Granted = token.GrantedScopes()
+	transport.Source = NewTokenSource(token, clientID)
+	transport.Activity = &audit.Activity{Profile: CurrentProfile()}
+	if p, err := LoadProfile(CurrentProfile()); err == nil {
+		transport.Activity.Account = p.Account
+	}
+	return client, nil
+}
+
+// NewClient returns an HTTP client that sends accessToken as a Bearer token,
+// with proxy, CA bundle, and timeout settings applied from config.
+func NewClient(accessToken string) (*http.Client, error) {
+	client, err := httpclient.New()
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP settings: %w\nCheck: kit config validate", err)
+	}
+	if ct, ok := client.Transport.(*cache.Transport); ok {
+		// Keep each profile's and account's responses apart
+		if ct.Dir, err = CacheDir(CurrentProfile()); err != nil {
+			return nil, err
+		}
+		if p, err := LoadProfile(CurrentProfile()); err == nil {
+			ct.Account = p.Account
+		}
+	}
+	client.Transport = &BearerTransport{Token: accessToken, Base: client.Transport}
 	return client, nil
 }
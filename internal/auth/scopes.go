@@ -0,0 +1,261 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/klytics/m365kit/internal/config"
+)
+
+// ScopesAnnotation is the cobra annotation listing the Graph permissions a
+// command needs, separated by spaces. The root command collects it from the
+// command and its parents and passes it to SetRequiredScopes.
+const ScopesAnnotation = "kit.scopes"
+
+// alwaysScopes are requested whatever the configuration: User.Read for
+// kit auth whoami, and offline_access for a refresh token.
+var alwaysScopes = []string{"User.Read", "offline_access"}
+
+// requiredScopes are the permissions the running command needs.
+var requiredScopes []string
+
+// SetRequiredScopes records the permissions the running command needs, so
+// RequireAuth can check the token for them before any Graph call.
+func SetRequiredScopes(scopes []string) {
+	requiredScopes = scopes
+}
+
+// RequireScopes adds permissions the running command needs only in some
+// cases, e.g. People.Read when a recipient is given by name. Call it before
+// RequireAuth so a missing one is reported before any Graph call.
+func RequireScopes(scopes ...string) {
+	requiredScopes = mergeScopes(requiredScopes, scopes)
+}
+
+// SharedMailboxScopes returns the .Shared variants of the mail permissions
+// in scopes, which working in another user's mailbox needs instead.
+func SharedMailboxScopes(scopes []string) []string {
+	var shared []string
+	for _, s := range scopes {
+		switch strings.ToLower(s) {
+		case "mail.read", "mail.readwrite", "mail.send":
+			shared = append(shared, s+".Shared")
+		}
+	}
+	return shared
+}
+
+// ParseScopes splits a scope list separated by spaces or commas.
+func ParseScopes(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// Scopes returns the permissions kit requests when signing in to the
+// active profile: auth.scopes in ~/.kit/config.yaml (or KIT_AUTH_SCOPES),
+// else the default set, plus any added to the profile with
+// kit auth login --add-scope.
+func Scopes() []string {
+	configured := os.Getenv("KIT_AUTH_SCOPES")
+	if configured == "" {
+		config.Load()
+		configured = strings.Join(viper.GetStringSlice("auth.scopes"), " ")
+	}
+	if configured == "" {
+		configured = defaultScopes
+	}
+
+	scopes := ParseScopes(configured)
+	if p, err := LoadProfile(CurrentProfile()); err == nil {
+		scopes = append(scopes, p.Scopes...)
+	}
+	return mergeScopes(scopes, alwaysScopes)
+}
+
+// AddScopes adds permissions to request for the profile at its next
+// sign-in, skipping any it already has.
+func (p *Profile) AddScopes(scopes ...string) {
+	p.Scopes = mergeScopes(p.Scopes, scopes)
+}
+
+// scopeParam returns Scopes in the form of an OAuth scope parameter.
+func scopeParam() string {
+	return strings.Join(Scopes(), " ")
+}
+
+// mergeScopes appends the scopes in add that are not in scopes,
+// ignoring case.
+func mergeScopes(scopes, add []string) []string {
+	var out []string
+	for _, s := range append(append([]string{}, scopes...), add...) {
+		if !containsScope(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func containsScope(scopes []string, s string) bool {
+	for _, have := range scopes {
+		if strings.EqualFold(have, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// GrantedScopes returns the permissions the token was issued with, or nil
+// for tokens saved before kit recorded them.
+func (t *Token) GrantedScopes() []string {
+	return ParseScopes(t.Scope)
+}
+
+// MissingScopes returns the scopes in want that the token was not granted.
+// A broader permission satisfies a narrower one: Mail.ReadWrite covers
+// Mail.Read, and Files.ReadWrite.All covers Files.ReadWrite. Tokens that do
+// not record their scopes are assumed to have everything.
+func (t *Token) MissingScopes(want []string) []string {
+	granted := t.GrantedScopes()
+	if len(granted) == 0 {
+		return nil
+	}
+	var missing []string
+	for _, s := range want {
+		if !scopeSatisfied(granted, s) && !containsScope(missing, s) {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+func scopeSatisfied(granted []string, want string) bool {
+	readWrite := strings.Replace(want, ".Read", ".ReadWrite", 1)
+	for _, s := range []string{want, want + ".All", readWrite, readWrite + ".All"} {
+		if containsScope(granted, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeError reports a Graph request that was refused because the token
+// lacks a permission.
+type ScopeError struct {
+	Scope   string // the permission that would allow the request
+	Message string // Graph's error message
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("access denied: %s\nThe token may not include the %s permission — run: %s --add-scope %s",
+		e.Message, e.Scope, loginCommand(), e.Scope)
+}
+
+// maxErrorBody caps how much of a 403 response is read to classify it.
+const maxErrorBody = 64 << 10
+
+// checkScope turns a 403 from Graph that reports a missing permission into
+// a *ScopeError naming the scope to add. Other responses, and refusals the
+// granted scopes should already allow (e.g. a file the user may not open),
+// are returned unchanged.
+func checkScope(req *http.Request, resp *http.Response, granted []string) (*http.Response, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var graphErr struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &graphErr) != nil || !insufficientScope(graphErr.Error.Code, graphErr.Error.Message) {
+		return resp, nil
+	}
+
+	candidates := requiredScopes
+	if len(candidates) == 0 {
+		if s := scopeForPath(req.Method, req.URL.Path); s != "" {
+			candidates = []string{s}
+		}
+	}
+	for _, s := range candidates {
+		if len(granted) == 0 || !scopeSatisfied(granted, s) {
+			return nil, &ScopeError{Scope: s, Message: graphErr.Error.Message}
+		}
+	}
+	return resp, nil
+}
+
+// insufficientScope reports whether a Graph error means the token lacks a
+// permission rather than the user lacking access to a resource.
+func insufficientScope(code, message string) bool {
+	switch code {
+	case "ErrorAccessDenied", "Authorization_RequestDenied":
+		return true
+	}
+	message = strings.ToLower(message)
+	return strings.Contains(message, "scope") || strings.Contains(message, "insufficient privileges")
+}
+
+// scopeForPath guesses the permission a Graph request needs from its path,
+// for commands that do not declare their scopes.
+func scopeForPath(method, path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/v1.0"), "/"), "/")
+	switch {
+	case len(segments) > 0 && segments[0] == "me":
+		segments = segments[1:]
+	case len(segments) > 1 && segments[0] == "users":
+		segments = segments[2:]
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	last := segments[len(segments)-1]
+
+	switch segments[0] {
+	case "sendMail":
+		return "Mail.Send"
+	case "messages", "mailFolders":
+		switch last {
+		case "send", "reply", "replyAll", "forward":
+			return "Mail.Send"
+		}
+		return "Mail.ReadWrite"
+	case "mailboxSettings":
+		return "MailboxSettings.ReadWrite"
+	case "events", "calendar", "calendars", "calendarView":
+		return "Calendars.ReadWrite"
+	case "contacts", "contactFolders":
+		return "Contacts.Read"
+	case "people":
+		return "People.Read"
+	case "drive":
+		return "Files.ReadWrite"
+	case "drives", "sites":
+		return "Sites.ReadWrite.All"
+	case "chats":
+		return "Chat.ReadWrite"
+	case "teams", "joinedTeams":
+		if last == "messages" || last == "replies" {
+			if method == http.MethodGet {
+				return "ChannelMessage.Read.All"
+			}
+			return "ChannelMessage.Send"
+		}
+		return "Team.ReadBasic.All"
+	case "presence":
+		return "Presence.Read.All"
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScopesFromConfigAndProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KIT_PROFILE", "contoso")
+	t.Setenv("KIT_AUTH_SCOPES", "Files.Read, Mail.Read")
+
+	p := &Profile{Name: "contoso"}
+	p.AddScopes("Mail.ReadWrite", "mail.read")
+	p.AddScopes("Mail.ReadWrite")
+	if err := SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(Scopes(), " ")
+	if got != "Files.Read Mail.Read Mail.ReadWrite User.Read offline_access" {
+		t.Errorf("Scopes() = %s", got)
+	}
+}
+
+func TestScopesFromConfigFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KIT_PROFILE", "")
+	t.Setenv("KIT_AUTH_SCOPES", "")
+	os.MkdirAll(filepath.Join(home, ".kit"), 0700)
+	os.WriteFile(filepath.Join(home, ".kit", "config.yaml"), []byte("auth:\n  scopes: [Files.Read, Calendars.Read]\n"), 0600)
+
+	got := strings.Join(Scopes(), " ")
+	if got != "Files.Read Calendars.Read User.Read offline_access" {
+		t.Errorf("Scopes() = %s", got)
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	token := &Token{Scope: "Mail.ReadWrite Files.ReadWrite.All User.Read"}
+	missing := token.MissingScopes([]string{"Mail.Read", "Files.ReadWrite", "Mail.Send", "Mail.Send"})
+	if len(missing) != 1 || missing[0] != "Mail.Send" {
+		t.Errorf("MissingScopes = %v", missing)
+	}
+
+	// Tokens saved before scopes were recorded are not second-guessed
+	if missing := (&Token{}).MissingScopes([]string{"Mail.Send"}); missing != nil {
+		t.Errorf("MissingScopes without recorded scopes = %v", missing)
+	}
+}
+
+func TestRequireAuthReportsMissingScope(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KIT_PROFILE", "")
+	t.Setenv("KIT_AZURE_CLIENT_ID", "app")
+	SaveToken(&Token{AccessToken: "at", ExpiresAt: time.Now().Add(time.Hour), Scope: "User.Read Mail.Read"})
+
+	SetRequiredScopes([]string{"Mail.ReadWrite"})
+	defer SetRequiredScopes(nil)
+
+	_, err := RequireAuth(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "kit auth login --add-scope Mail.ReadWrite") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// forbidden answers every request with a Graph 403.
+type forbidden struct{ code, message string }
+
+func (f forbidden) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := `{"error":{"code":"` + f.code + `","message":"` + f.message + `"}}`
+	return &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+func TestBearerTransportSuggestsScope(t *testing.T) {
+	client := &http.Client{Transport: &BearerTransport{Token: "tok", Base: forbidden{"ErrorAccessDenied", "Access is denied."}}}
+	_, err := client.Get("https://graph.microsoft.com/v1.0/me/mailFolders/inbox/messages")
+	var scopeErr *ScopeError
+	if !errors.As(err, &scopeErr) || scopeErr.Scope != "Mail.ReadWrite" {
+		t.Fatalf("err = %v", err)
+	}
+	if !strings.Contains(err.Error(), "--add-scope Mail.ReadWrite") {
+		t.Errorf("err = %v", err)
+	}
+
+	// With the scope granted, the 403 is about the resource, not the token
+	client.Transport.(*BearerTransport).Granted = []string{"Mail.ReadWrite"}
+	resp, err := client.Get("https://graph.microsoft.com/v1.0/me/mailFolders/inbox/messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden || !strings.Contains(string(body), "Access is denied") {
+		t.Errorf("response = %d %s", resp.StatusCode, body)
+	}
+
+	// A file the user may not open is left to the caller
+	client = &http.Client{Transport: &BearerTransport{Token: "tok", Base: forbidden{"accessDenied", "The caller does not have permission"}}}
+	resp, err = client.Get("https://graph.microsoft.com/v1.0/me/drive/root:/secret.docx")
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestScopeForPath(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"GET", "/v1.0/me/messages", "Mail.ReadWrite"},
+		{"POST", "/v1.0/users/anna@contoso.com/messages/abc/reply", "Mail.Send"},
+		{"POST", "/v1.0/me/sendMail", "Mail.Send"},
+		{"GET", "/v1.0/me/calendarView", "Calendars.ReadWrite"},
+		{"GET", "/v1.0/sites/root", "Sites.ReadWrite.All"},
+		{"GET", "/v1.0/teams/t/channels/c/messages", "ChannelMessage.Read.All"},
+		{"POST", "/v1.0/teams/t/channels/c/messages", "ChannelMessage.Send"},
+		{"GET", "/v1.0/me", ""},
+	}
+	for _, tt := range tests {
+		if got := scopeForPath(tt.method, tt.path); got != tt.want {
+			t.Errorf("scopeForPath(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSharedMailboxScopes(t *testing.T) {
+	got := SharedMailboxScopes([]string{"Mail.ReadWrite", "Mail.Send", "Files.ReadWrite"})
+	if strings.Join(got, " ") != "Mail.ReadWrite.Shared Mail.Send.Shared" {
+		t.Errorf("SharedMailboxScopes = %v", got)
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	SetRequiredScopes([]string{"Mail.Send"})
+	defer SetRequiredScopes(nil)
+	RequireScopes("People.Read", "mail.send")
+	if got := strings.Join(requiredScopes, " "); got != "Mail.Send People.Read" {
+		t.Errorf("requiredScopes = %s", got)
+	}
+}
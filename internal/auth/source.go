@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"sync"
+)
+
+// TokenSource hands out the active profile's access token for the life of
+// a command, refreshing it as it nears expiry so long-running jobs (batch
+// runs, watchers, large transfers) outlive the token they started with. It
+// is safe for concurrent use.
+type TokenSource struct {
+	clientID string
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewTokenSource returns a TokenSource that starts from token and refreshes
+// it with clientID.
+func NewTokenSource(token *Token, clientID string) *TokenSource {
+	return &TokenSource{clientID: clientID, token: token}
+}
+
+// Token returns a token that is valid for at least the refresh window,
+// refreshing the current one first if needed.
+func (s *TokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := RefreshIfNeeded(ctx, s.token, s.clientID)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// Renew refreshes the token after Graph rejected stale, the access token a
+// request was sent with. When another request has renewed it in the
+// meantime, the newer token is returned without refreshing again.
+func (s *TokenSource) Renew(ctx context.Context, stale string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.AccessToken != stale {
+		return s.token, nil
+	}
+	token, err := refresh(ctx, s.token, s.clientID)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
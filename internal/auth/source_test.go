@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer issues "fresh-1", "fresh-2", ... for every refresh.
+func tokenServer(t *testing.T) *atomic.Int32 {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KIT_PROFILE", "")
+
+	var refreshes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" {
+			t.Errorf("grant_type = %s", r.Form.Get("grant_type"))
+		}
+		n := refreshes.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("fresh-%d", n), "refresh_token": "rt", "expires_in": 3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	old := loginBase
+	loginBase = server.URL
+	t.Cleanup(func() { loginBase = old })
+	return &refreshes
+}
+
+// graphStub accepts only the token in valid and records request bodies.
+type graphStub struct {
+	valid  string
+	bodies []string
+}
+
+func (g *graphStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		g.bodies = append(g.bodies, string(b))
+	}
+	status := http.StatusOK
+	if req.Header.Get("Authorization") != "Bearer "+g.valid {
+		status = http.StatusUnauthorized
+	}
+	return &http.Response{StatusCode: status, Body: http.NoBody, Request: req}, nil
+}
+
+func TestBearerTransportRefreshesExpiringToken(t *testing.T) {
+	refreshes := tokenServer(t)
+	expiring := &Token{AccessToken: "old", RefreshToken: "rt", ExpiresAt: time.Now().Add(time.Minute)}
+	stub := &graphStub{valid: "fresh-1"}
+	client := &http.Client{Transport: &BearerTransport{Source: NewTokenSource(expiring, "app"), Base: stub}}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get("https://graph.microsoft.com/v1.0/me")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d", resp.StatusCode)
+		}
+	}
+	if n := refreshes.Load(); n != 1 {
+		t.Errorf("refreshed %d times, want 1", n)
+	}
+	if saved, _ := LoadToken(); saved == nil || saved.AccessToken != "fresh-1" {
+		t.Errorf("refreshed token was not saved: %+v", saved)
+	}
+}
+
+func TestBearerTransportRetriesAfter401(t *testing.T) {
+	refreshes := tokenServer(t)
+	// Looks valid locally, but Graph has stopped accepting it
+	revoked := &Token{AccessToken: "old", RefreshToken: "rt", ExpiresAt: time.Now().Add(time.Hour)}
+	stub := &graphStub{valid: "fresh-1"}
+	client := &http.Client{Transport: &BearerTransport{Source: NewTokenSource(revoked, "app"), Base: stub}}
+
+	resp, err := client.Post("https://graph.microsoft.com/v1.0/me/sendMail", "application/json", strings.NewReader(`{"message":{}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+	if refreshes.Load() != 1 || len(stub.bodies) != 2 || stub.bodies[1] != `{"message":{}}` {
+		t.Errorf("refreshes = %d, bodies = %q", refreshes.Load(), stub.bodies)
+	}
+
+	// A token Graph keeps rejecting is retried only once
+	stub.valid = "never"
+	resp, err = client.Get("https://graph.microsoft.com/v1.0/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || refreshes.Load() != 2 {
+		t.Errorf("status = %d after %d refreshes", resp.StatusCode, refreshes.Load())
+	}
+}
+
+func TestTokenSourceRenewsOnce(t *testing.T) {
+	refreshes := tokenServer(t)
+	source := NewTokenSource(&Token{AccessToken: "old", RefreshToken: "rt", ExpiresAt: time.Now().Add(time.Hour)}, "app")
+
+	// Two requests rejected with the same token renew it only once
+	first, err := source.Renew(context.Background(), "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := source.Renew(context.Background(), "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.AccessToken != "fresh-1" || second.AccessToken != "fresh-1" || refreshes.Load() != 1 {
+		t.Errorf("tokens %s, %s after %d refreshes", first.AccessToken, second.AccessToken, refreshes.Load())
+	}
+}
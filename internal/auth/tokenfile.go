@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Several kit processes can share a token — a watch daemon and commands run
+// by hand — and refresh it at the same moment. Writes go to a temporary
+// file renamed over token.json, so a reader never sees half a token, and a
+// refresh holds token.json.lock so only one process redeems the refresh
+// token while the others wait and reuse the result (see package filelock).
+
+// writeFileAtomic replaces path with data by writing a temporary file in
+// the same directory and renaming it into place.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// tokenCache keeps parsed tokens by path, so the many LoadToken calls of a
+// long run do not re-read the file. An entry is used only while the file's
+// modification time and size are unchanged, so a token written by another
+// process is picked up.
+var tokenCache = struct {
+	sync.Mutex
+	entries map[string]cachedToken
+}{entries: map[string]cachedToken{}}
+
+type cachedToken struct {
+	token   Token
+	modTime time.Time
+	size    int64
+}
+
+func cacheLookup(path string, info os.FileInfo) (*Token, bool) {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	e, ok := tokenCache.entries[path]
+	if !ok || !e.modTime.Equal(info.ModTime()) || e.size != info.Size() {
+		return nil, false
+	}
+	t := e.token
+	return &t, true
+}
+
+// cacheStore records t as the token read from path when it had info. Should
+// the file have been replaced between the stat and the read, the entry
+// simply never matches.
+func cacheStore(path string, info os.FileInfo, t *Token) {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	tokenCache.entries[path] = cachedToken{token: *t, modTime: info.ModTime(), size: info.Size()}
+}
+
+func cacheDelete(path string) {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	delete(tokenCache.entries, path)
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentRefreshRedeemsOnce(t *testing.T) {
+	refreshes := tokenServer(t)
+	expiring := &Token{AccessToken: "old", RefreshToken: "rt", ExpiresAt: time.Now().Add(time.Minute)}
+	if err := SaveToken(expiring); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each source stands in for a separate kit process holding the token
+	var wg sync.WaitGroup
+	tokens := make([]string, 4)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tok, err := NewTokenSource(expiring, "app").Token(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			tokens[i] = tok.AccessToken
+		}(i)
+	}
+	wg.Wait()
+
+	if n := refreshes.Load(); n != 1 {
+		t.Errorf("refreshed %d times, want 1", n)
+	}
+	for _, tok := range tokens {
+		if tok != "fresh-1" {
+			t.Errorf("tokens = %v", tokens)
+			break
+		}
+	}
+}
+
+func TestSaveTokenIsAtomicAndCached(t *testing.T) {
+	dir := t.TempDir()
+	TokenPathOverride = filepath.Join(dir, "token.json")
+	defer func() { TokenPathOverride = "" }()
+
+	if err := SaveToken(&Token{AccessToken: "first", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+	if info, _ := os.Stat(TokenPathOverride); info.Mode().Perm() != 0600 {
+		t.Errorf("token file mode = %v", info.Mode().Perm())
+	}
+	if tok, err := LoadToken(); err != nil || tok.AccessToken != "first" {
+		t.Fatalf("LoadToken = %+v, %v", tok, err)
+	}
+
+	// Another process writes a new token; the cached one must not be served
+	data := []byte(`{"access_token":"written-elsewhere","expires_at":"2099-01-01T00:00:00Z"}`)
+	if err := os.WriteFile(TokenPathOverride, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	os.Chtimes(TokenPathOverride, later, later)
+	if tok, err := LoadToken(); err != nil || tok.AccessToken != "written-elsewhere" {
+		t.Errorf("LoadToken after external write = %+v, %v", tok, err)
+	}
+}
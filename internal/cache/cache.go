@@ -0,0 +1,262 @@
+// Package cache provides an opt-in disk cache for read-only Microsoft Graph
+// responses. Cached entries are revalidated with If-None-Match when Graph
+// supplied an ETag, and served as-is when the network is unreachable.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/cloud"
+)
+
+// Header is set on responses served from the cache. Its value is "hit"
+// (fresh or revalidated) or "stale" (served because the network failed).
+const Header = "X-Kit-Cache"
+
+// Entry is a cached response.
+type Entry struct {
+	URL         string    `json:"url"`
+	ETag        string    `json:"etag,omitempty"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	StoredAt    time.Time `json:"stored_at"`
+}
+
+// Transport is an http.RoundTripper that caches GET responses from Graph.
+//
+// It sits below the transport that adds the access token, so it can't tell
+// accounts apart by itself: give each profile its own Dir (see DirFor) and
+// set Account, which is part of every key, so one account's responses are
+// never served to another.
+type Transport struct {
+	Base    http.RoundTripper
+	Dir     string
+	Account string
+	// TTL serves entries younger than this without contacting Graph.
+	// Zero always revalidates.
+	TTL time.Duration
+
+	now func() time.Time
+}
+
+// DefaultDir returns ~/.kit/cache/graph, the cache of the default profile.
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return DirFor(".kit")
+	}
+	return DirFor(filepath.Join(home, ".kit"))
+}
+
+// DirFor returns the cache directory inside a profile's directory.
+func DirFor(profileDir string) string {
+	return filepath.Join(profileDir, "cache", "graph")
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if !cacheable(req) {
+		resp, err := base.RoundTrip(req)
+		// Writes make cached listings stale; drop everything rather than
+		// guess which entries a change affects.
+		if err == nil && req.Method != "GET" && cloud.IsGraphHost(req.URL.Host) && resp.StatusCode < 400 {
+			Clear(t.Dir)
+		}
+		return resp, err
+	}
+
+	key := Key(t.Account, req.URL.String())
+	entry, _ := t.load(key)
+
+	if entry != nil && t.TTL > 0 && t.clock().Sub(entry.StoredAt) < t.TTL {
+		return entry.response(req, "hit"), nil
+	}
+
+	if entry != nil && entry.ETag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		if entry != nil && req.Context().Err() == nil {
+			return entry.response(req, "stale"), nil
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		entry.StoredAt = t.clock()
+		t.store(key, entry)
+		return entry.response(req, "hit"), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.store(key, &Entry{
+		URL:         req.URL.String(),
+		ETag:        resp.Header.Get("ETag"),
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+		StoredAt:    t.clock(),
+	})
+	return resp, nil
+}
+
+// cacheable reports whether req is a plain GET to Graph. Ranged requests
+// and content downloads are excluded; file content is served from
+// SharePoint hosts and is never stored.
+func cacheable(req *http.Request) bool {
+	return req.Method == "GET" &&
+		cloud.IsGraphHost(req.URL.Host) &&
+		req.Header.Get("Range") == "" &&
+		!strings.HasSuffix(req.URL.Path, "/content") &&
+		!strings.HasSuffix(req.URL.Path, "/$value")
+}
+
+// Key returns the cache file name for a URL fetched by account.
+func Key(account, rawURL string) string {
+	sum := sha256.Sum256([]byte(account + "\n" + rawURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *Transport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (t *Transport) load(key string) (*Entry, error) {
+	data, err := os.ReadFile(filepath.Join(t.Dir, key+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (t *Transport) store(key string, e *Entry) {
+	if err := os.MkdirAll(t.Dir, 0700); err != nil {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	// Write then rename so a concurrent reader never sees a partial entry
+	tmp := filepath.Join(t.Dir, key+".tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	os.Rename(tmp, filepath.Join(t.Dir, key+".json"))
+}
+
+func (e *Entry) response(req *http.Request, state string) *http.Response {
+	h := http.Header{}
+	h.Set("Content-Type", e.ContentType)
+	if e.ETag != "" {
+		h.Set("ETag", e.ETag)
+	}
+	h.Set(Header, state)
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// Stats summarizes the cache directory.
+type Stats struct {
+	Dir     string    `json:"dir"`
+	Entries int       `json:"entries"`
+	Bytes   int64     `json:"bytes"`
+	Oldest  time.Time `json:"oldest,omitempty"`
+	Newest  time.Time `json:"newest,omitempty"`
+}
+
+// Stat returns the number and total size of entries in dir.
+func Stat(dir string) (*Stats, error) {
+	s := &Stats{Dir: dir}
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read cache directory: %w", err)
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		s.Entries++
+		s.Bytes += info.Size()
+		if mt := info.ModTime(); s.Oldest.IsZero() || mt.Before(s.Oldest) {
+			s.Oldest = mt
+		}
+		if mt := info.ModTime(); mt.After(s.Newest) {
+			s.Newest = mt
+		}
+	}
+	return s, nil
+}
+
+// Clear removes all cached entries in dir and returns how many were removed.
+func Clear(dir string) (int, error) {
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not read cache directory: %w", err)
+	}
+	n := 0
+	for _, f := range files {
+		name := f.Name()
+		if !strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".tmp") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err == nil && strings.HasSuffix(name, ".json") {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGraph answers requests in-process and records what it saw.
+type fakeGraph struct {
+	calls       int
+	ifNoneMatch string
+	etag        string
+	body        string
+	fail        bool
+}
+
+func (f *fakeGraph) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.calls++
+	f.ifNoneMatch = req.Header.Get("If-None-Match")
+	if f.fail {
+		return nil, errors.New("network unreachable")
+	}
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	if f.etag != "" {
+		h.Set("ETag", f.etag)
+	}
+	if f.etag != "" && f.ifNoneMatch == f.etag {
+		return &http.Response{StatusCode: http.StatusNotModified, Header: h, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: h, Body: io.NopCloser(strings.NewReader(f.body))}, nil
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), resp.Header.Get(Header)
+}
+
+const listURL = "https://graph.microsoft.com/v1.0/me/drive/root/children"
+
+func TestRevalidatesWithETag(t *testing.T) {
+	fake := &fakeGraph{etag: `"v1"`, body: `{"value":[1]}`}
+	client := &http.Client{Transport: &Transport{Base: fake, Dir: t.TempDir()}}
+
+	body, state := get(t, client, listURL)
+	if body != `{"value":[1]}` || state != "" {
+		t.Fatalf("first request: body=%q state=%q", body, state)
+	}
+
+	body, state = get(t, client, listURL)
+	if fake.ifNoneMatch != `"v1"` {
+		t.Errorf("expected If-None-Match %q, got %q", `"v1"`, fake.ifNoneMatch)
+	}
+	if body != `{"value":[1]}` || state != "hit" {
+		t.Errorf("revalidated request: body=%q state=%q", body, state)
+	}
+}
+
+func TestTTLSkipsNetwork(t *testing.T) {
+	fake := &fakeGraph{body: `{"value":[]}`}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tr := &Transport{Base: fake, Dir: t.TempDir(), TTL: time.Minute, now: func() time.Time { return now }}
+	client := &http.Client{Transport: tr}
+
+	get(t, client, listURL)
+	_, state := get(t, client, listURL)
+	if fake.calls != 1 || state != "hit" {
+		t.Errorf("expected second request served from cache, calls=%d state=%q", fake.calls, state)
+	}
+
+	now = now.Add(2 * time.Minute)
+	get(t, client, listURL)
+	if fake.calls != 2 {
+		t.Errorf("expected expired entry to be refetched, calls=%d", fake.calls)
+	}
+}
+
+func TestServesStaleWhenOffline(t *testing.T) {
+	fake := &fakeGraph{body: `{"value":["cached"]}`}
+	client := &http.Client{Transport: &Transport{Base: fake, Dir: t.TempDir()}}
+
+	get(t, client, listURL)
+	fake.fail = true
+
+	body, state := get(t, client, listURL)
+	if body != `{"value":["cached"]}` || state != "stale" {
+		t.Errorf("offline request: body=%q state=%q", body, state)
+	}
+
+	if _, err := client.Get(listURL + "?other=1"); err == nil {
+		t.Error("expected error for uncached URL while offline")
+	}
+}
+
+func TestSkipsNonGraphAndContent(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeGraph{body: `{}`}
+	client := &http.Client{Transport: &Transport{Base: fake, Dir: dir}}
+
+	get(t, client, "https://contoso.sharepoint.com/download.aspx")
+	get(t, client, "https://graph.microsoft.com/v1.0/me/drive/items/1/content")
+
+	stats, err := Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 0 {
+		t.Errorf("expected nothing cached, got %d entries", stats.Entries)
+	}
+}
+
+func TestWriteClearsCache(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeGraph{body: `{}`}
+	client := &http.Client{Transport: &Transport{Base: fake, Dir: dir}}
+
+	get(t, client, listURL)
+	if stats, _ := Stat(dir); stats.Entries != 1 {
+		t.Fatalf("expected 1 entry, got %d", stats.Entries)
+	}
+
+	req, _ := http.NewRequest("DELETE", "https://graph.microsoft.com/v1.0/me/drive/items/1", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if stats, _ := Stat(dir); stats.Entries != 0 {
+		t.Errorf("expected cache cleared after DELETE, got %d entries", stats.Entries)
+	}
+}
+
+func TestClear(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeGraph{body: `{}`}
+	client := &http.Client{Transport: &Transport{Base: fake, Dir: dir}}
+	get(t, client, listURL)
+	get(t, client, listURL+"?page=2")
+
+	n, err := Clear(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 cleared, got %d", n)
+	}
+
+	if n, err := Clear(dir + "/missing"); err != nil || n != 0 {
+		t.Errorf("Clear on missing dir = %d, %v", n, err)
+	}
+}
+
+func TestAccountsDoNotShareEntries(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeGraph{body: `{"value":["anna"]}`}
+	anna := &http.Client{Transport: &Transport{Base: fake, Dir: dir, Account: "anna@contoso.com"}}
+	get(t, anna, listURL)
+
+	fake.fail = true
+	bob := &http.Client{Transport: &Transport{Base: fake, Dir: dir, Account: "bob@contoso.com"}}
+	if _, err := bob.Get(listURL); err == nil {
+		t.Error("expected bob's request to miss anna's entry")
+	}
+	if body, state := get(t, anna, listURL); body != `{"value":["anna"]}` || state != "stale" {
+		t.Errorf("anna offline: body=%q state=%q", body, state)
+	}
+}
@@ -0,0 +1,142 @@
+// Package cloud defines the sign-in and Microsoft Graph endpoints of the
+// Microsoft clouds kit can work with: the global service and the national
+// clouds for US government, China, and Germany.
+package cloud
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/klytics/m365kit/internal/config"
+)
+
+// Cloud is a Microsoft cloud's pair of endpoints.
+type Cloud struct {
+	Name        string // Preset name, as used in azure.cloud
+	Description string
+	LoginURL    string // Microsoft identity platform host
+	GraphURL    string // Microsoft Graph host, without a version
+}
+
+// Global is the worldwide Microsoft 365 service, including GCC tenants.
+var Global = Cloud{
+	Name:        "global",
+	Description: "Worldwide Microsoft 365, including GCC",
+	LoginURL:    "https://login.microsoftonline.com",
+	GraphURL:    "https://graph.microsoft.com",
+}
+
+var presets = []Cloud{
+	Global,
+	{
+		Name:        "usgov",
+		Description: "Microsoft 365 GCC High",
+		LoginURL:    "https://login.microsoftonline.us",
+		GraphURL:    "https://graph.microsoft.us",
+	},
+	{
+		Name:        "usgovdod",
+		Description: "Microsoft 365 DoD",
+		LoginURL:    "https://login.microsoftonline.us",
+		GraphURL:    "https://dod-graph.microsoft.us",
+	},
+	{
+		Name:        "china",
+		Description: "Microsoft 365 operated by 21Vianet",
+		LoginURL:    "https://login.chinacloudapi.cn",
+		GraphURL:    "https://microsoftgraph.chinacloudapi.cn",
+	},
+	{
+		Name:        "germany",
+		Description: "Microsoft Cloud Deutschland",
+		LoginURL:    "https://login.microsoftonline.de",
+		GraphURL:    "https://graph.microsoft.de",
+	},
+}
+
+// aliases maps the other names the clouds go by to their presets.
+var aliases = map[string]string{
+	"public":     "global",
+	"commercial": "global",
+	"gcc":        "global",
+	"gcchigh":    "usgov",
+	"gcc-high":   "usgov",
+	"dod":        "usgovdod",
+	"21vianet":   "china",
+}
+
+// Presets returns the known clouds.
+func Presets() []Cloud {
+	return append([]Cloud(nil), presets...)
+}
+
+// Lookup returns the cloud with the given preset name or alias, ignoring
+// case. An empty name is the global cloud.
+func Lookup(name string) (Cloud, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Global, nil
+	}
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	for _, c := range presets {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	names := make([]string, len(presets))
+	for i, c := range presets {
+		names[i] = c.Name
+	}
+	return Global, fmt.Errorf("unknown cloud %q — use one of: %s", name, strings.Join(names, ", "))
+}
+
+// configured reads azure.cloud from ~/.kit/config.yaml once per process.
+var configured = sync.OnceValue(func() string {
+	config.Load()
+	return viper.GetString("azure.cloud")
+})
+
+// Load returns the cloud selected with KIT_AZURE_CLOUD, or azure.cloud in
+// ~/.kit/config.yaml, defaulting to the global cloud.
+func Load() (Cloud, error) {
+	name := os.Getenv("KIT_AZURE_CLOUD")
+	if name == "" {
+		name = configured()
+	}
+	return Lookup(name)
+}
+
+// Current is Load for callers that cannot report an error: an unknown
+// name falls back to the global cloud. Commands that call Graph check the
+// setting with Load first (see auth.RequireAuth).
+func Current() Cloud {
+	c, _ := Load()
+	return c
+}
+
+// GraphV1 returns the cloud's Graph v1.0 endpoint.
+func (c Cloud) GraphV1() string {
+	return c.GraphURL + "/v1.0"
+}
+
+// GraphBeta returns the cloud's Graph beta endpoint.
+func (c Cloud) GraphBeta() string {
+	return c.GraphURL + "/beta"
+}
+
+// IsGraphHost reports whether host is the Microsoft Graph host of any
+// known cloud.
+func IsGraphHost(host string) bool {
+	for _, c := range presets {
+		if strings.TrimPrefix(c.GraphURL, "https://") == host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package cloud
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name, want, graph string
+	}{
+		{"", "global", "https://graph.microsoft.com"},
+		{"GCC", "global", "https://graph.microsoft.com"},
+		{"gcc-high", "usgov", "https://graph.microsoft.us"},
+		{"DoD", "usgovdod", "https://dod-graph.microsoft.us"},
+		{"21vianet", "china", "https://microsoftgraph.chinacloudapi.cn"},
+		{"germany", "germany", "https://graph.microsoft.de"},
+	}
+	for _, tt := range tests {
+		c, err := Lookup(tt.name)
+		if err != nil {
+			t.Fatalf("Lookup(%q): %v", tt.name, err)
+		}
+		if c.Name != tt.want || c.GraphURL != tt.graph {
+			t.Errorf("Lookup(%q) = %s %s", tt.name, c.Name, c.GraphURL)
+		}
+	}
+
+	if _, err := Lookup("mars"); err == nil || !strings.Contains(err.Error(), "usgov") {
+		t.Errorf("unknown cloud error = %v", err)
+	}
+}
+
+func TestLoadFromEnvironment(t *testing.T) {
+	t.Setenv("KIT_AZURE_CLOUD", "usgov")
+	c := Current()
+	if c.GraphV1() != "https://graph.microsoft.us/v1.0" || c.LoginURL != "https://login.microsoftonline.us" {
+		t.Errorf("Current() = %+v", c)
+	}
+
+	t.Setenv("KIT_AZURE_CLOUD", "mars")
+	if _, err := Load(); err == nil {
+		t.Error("expected an error for an unknown cloud")
+	}
+	if Current().Name != "global" {
+		t.Error("Current() should fall back to the global cloud")
+	}
+}
+
+func TestIsGraphHost(t *testing.T) {
+	for host, want := range map[string]bool{
+		"graph.microsoft.com":               true,
+		"dod-graph.microsoft.us":            true,
+		"microsoftgraph.chinacloudapi.cn":   true,
+		"contoso.sharepoint.com":            false,
+		"graph.microsoft.com.attacker.test": false,
+	} {
+		if got := IsGraphHost(host); got != want {
+			t.Errorf("IsGraphHost(%s) = %v", host, got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package cmdlog holds what kit's local JSON-lines logs (the activity log
+// and the AI usage ledger) share: the command being run, which each line is
+// attributed to, and best-effort appends.
+package cmdlog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// current is the command being run, set by the root command.
+var current struct {
+	sync.Mutex
+	path string
+}
+
+// SetCommand records the command path (e.g. "kit onedrive upload") that
+// later log lines are attributed to.
+func SetCommand(path string) {
+	current.Lock()
+	defer current.Unlock()
+	current.path = path
+}
+
+// Command returns the command path set by SetCommand.
+func Command() string {
+	current.Lock()
+	defer current.Unlock()
+	return current.path
+}
+
+// appendMu serializes appends from concurrent calls in one process; lines
+// from separate processes rely on O_APPEND.
+var appendMu sync.Mutex
+
+// Append writes v as one JSON line at the end of the file at path,
+// creating it if needed. It is best-effort: a log that cannot be written
+// never fails the work being logged.
+func Append(path string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	appendMu.Lock()
+	defer appendMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(data)
+}
@@ -0,0 +1,41 @@
+package cmdlog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAppendConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "test.jsonl")
+	SetCommand("kit test")
+	defer SetCommand("")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			Append(path, map[string]any{"n": i, "command": Command()})
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("got %d lines, want 20", len(lines))
+	}
+	for _, line := range lines {
+		var v struct{ Command string }
+		if err := json.Unmarshal([]byte(line), &v); err != nil || v.Command != "kit test" {
+			t.Errorf("line %q: %+v, %v", line, v, err)
+		}
+	}
+}
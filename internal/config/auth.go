@@ -29,6 +29,28 @@ func GetAPIKey(provider string) (string, error) {
 		}
 		return "", fmt.Errorf("OPENAI_API_KEY not found — set it via environment variable or in ~/.kit/config.yaml")
 
+	case "azure-openai":
+		if key := os.Getenv("AZURE_OPENAI_API_KEY"); key != "" {
+			return key, nil
+		}
+		cfg, err := Load()
+		if err == nil && cfg.APIKeys.AzureOpenAI != "" {
+			return cfg.APIKeys.AzureOpenAI, nil
+		}
+		return "", fmt.Errorf("AZURE_OPENAI_API_KEY not found — set it via environment variable or api_keys.azure_openai in ~/.kit/config.yaml")
+
+	case "gemini":
+		for _, env := range []string{"GEMINI_API_KEY", "GOOGLE_API_KEY"} {
+			if key := os.Getenv(env); key != "" {
+				return key, nil
+			}
+		}
+		cfg, err := Load()
+		if err == nil && cfg.APIKeys.Gemini != "" {
+			return cfg.APIKeys.Gemini, nil
+		}
+		return "", fmt.Errorf("GEMINI_API_KEY not found — set it via environment variable or api_keys.gemini in ~/.kit/config.yaml (keys: https://aistudio.google.com/apikey)")
+
 	default:
 		return "", fmt.Errorf("no API key management for provider %q", provider)
 	}
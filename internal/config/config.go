@@ -13,18 +13,51 @@ type Config struct {
 	Provider string `mapstructure:"provider"`
 	Model    string `mapstructure:"model"`
 	APIKeys  struct {
-		Anthropic string `mapstructure:"anthropic"`
-		OpenAI    string `mapstructure:"openai"`
+		Anthropic   string `mapstructure:"anthropic"`
+		OpenAI      string `mapstructure:"openai"`
+		AzureOpenAI string `mapstructure:"azure_openai"`
+		Gemini      string `mapstructure:"gemini"`
 	} `mapstructure:"api_keys"`
 	Ollama struct {
 		Host string `mapstructure:"host"`
 	} `mapstructure:"ollama"`
+	// AzureOpenAI configures the azure-openai provider. Deployments maps
+	// model capabilities (default, fast, long-context) to deployment names;
+	// Deployment is the default one.
+	AzureOpenAI struct {
+		Endpoint    string            `mapstructure:"endpoint"`
+		APIVersion  string            `mapstructure:"api_version"`
+		Deployment  string            `mapstructure:"deployment"`
+		Deployments map[string]string `mapstructure:"deployments"`
+	} `mapstructure:"azure_openai"`
+	// Usage configures AI usage metering. MonthlyBudget, in US dollars,
+	// turns on a warning once estimated spend for the month reaches WarnAt
+	// (a fraction of it, default 0.8); Prices adds or overrides per-model
+	// prices, keyed by model or Azure OpenAI deployment name.
+	Usage struct {
+		MonthlyBudget float64               `mapstructure:"monthly_budget"`
+		WarnAt        float64               `mapstructure:"warn_at"`
+		Prices        map[string]ModelPrice `mapstructure:"prices"`
+	} `mapstructure:"usage"`
+	// Redact configures masking of personal data and secrets in text sent
+	// to AI providers: Enabled turns it on without --redact, and Patterns
+	// adds regular expressions to mask, keyed by a name for the report.
+	Redact struct {
+		Enabled  bool              `mapstructure:"enabled"`
+		Patterns map[string]string `mapstructure:"patterns"`
+	} `mapstructure:"redact"`
 	Output struct {
 		Format string `mapstructure:"format"`
 		Color  bool   `mapstructure:"color"`
 	} `mapstructure:"output"`
 }
 
+// ModelPrice is what a model costs, in US dollars per million tokens.
+type ModelPrice struct {
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
+}
+
 // Load reads the configuration from ~/.kit/config.yaml and environment variables.
 func Load() (*Config, error) {
 	configDir := configDir()
@@ -54,6 +87,22 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
+// AIProvider returns the AI provider to use when --provider isn't given:
+// $KIT_PROVIDER, then provider in ~/.kit/config.yaml, then ai.provider in
+// the org config, then anthropic.
+func AIProvider() string {
+	if p := os.Getenv("KIT_PROVIDER"); p != "" {
+		return p
+	}
+	if cfg, err := Load(); err == nil && viper.InConfig("provider") && cfg.Provider != "" {
+		return cfg.Provider
+	}
+	if org, _ := LoadOrgConfig(); org != nil && org.AI.Provider != "" {
+		return org.AI.Provider
+	}
+	return "anthropic"
+}
+
 func configDir() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
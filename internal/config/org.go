@@ -94,9 +94,9 @@ func ValidateOrgConfig(cfg *OrgConfig) []string {
 		issues = append(issues, fmt.Sprintf("audit.level must be 'command' or 'verbose', got %q", cfg.Audit.Level))
 	}
 	if cfg.AI.Provider != "" {
-		valid := map[string]bool{"anthropic": true, "openai": true, "ollama": true}
+		valid := map[string]bool{"anthropic": true, "openai": true, "azure-openai": true, "gemini": true, "ollama": true}
 		if !valid[cfg.AI.Provider] {
-			issues = append(issues, fmt.Sprintf("ai.provider must be anthropic, openai, or ollama, got %q", cfg.AI.Provider))
+			issues = append(issues, fmt.Sprintf("ai.provider must be anthropic, openai, azure-openai, gemini, or ollama, got %q", cfg.AI.Provider))
 		}
 	}
 	return issues
@@ -196,6 +196,25 @@ func Validate() []ConfigIssue {
 				Fix:      "export OPENAI_API_KEY=sk-...",
 			})
 		}
+	case "azure-openai", "gemini":
+		if _, err := GetAPIKey(provider); err != nil {
+			issues = append(issues, ConfigIssue{
+				Key:      "ai.provider",
+				Severity: "error",
+				Message:  fmt.Sprintf("provider is %q but %s", provider, err),
+			})
+		}
+		if provider == "azure-openai" {
+			cfg, _ := Load()
+			if os.Getenv("AZURE_OPENAI_ENDPOINT") == "" && (cfg == nil || cfg.AzureOpenAI.Endpoint == "") {
+				issues = append(issues, ConfigIssue{
+					Key:      "azure_openai.endpoint",
+					Severity: "error",
+					Message:  "provider is \"azure-openai\" but no endpoint is set",
+					Fix:      "kit config set azure_openai.endpoint https://<resource>.openai.azure.com\nkit config set azure_openai.deployment <deployment>",
+				})
+			}
+		}
 	case "ollama":
 		issues = append(issues, ConfigIssue{
 			Key:      "ai.provider",
@@ -265,6 +284,15 @@ func ToEnv() map[string]string {
 	if u := viper.GetString("smtp.username"); u != "" {
 		env["KIT_SMTP_USERNAME"] = u
 	}
+	if p := viper.GetString("http.proxy"); p != "" {
+		env["KIT_HTTP_PROXY"] = p
+	}
+	if c := viper.GetString("http.ca_bundle"); c != "" {
+		env["KIT_CA_BUNDLE"] = c
+	}
+	if t := viper.GetString("http.timeout"); t != "" {
+		env["KIT_HTTP_TIMEOUT"] = t
+	}
 
 	return env
 }
@@ -354,6 +382,17 @@ func ShowConfig() string {
 		sb.WriteString("\n")
 	}
 
+	// HTTP client (proxy / TLS)
+	if viper.IsSet("http") {
+		sb.WriteString("HTTP\n")
+		for _, key := range []string{"proxy", "ca_bundle", "timeout", "tls_min_version", "insecure_skip_verify"} {
+			if v := viper.GetString("http." + key); v != "" {
+				sb.WriteString(fmt.Sprintf("  %-10s %s\n", key+":", v))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	return sb.String()
 }
 
@@ -0,0 +1,51 @@
+// Package filelock serializes access to a file shared by several kit
+// processes, such as the token a watch daemon and commands run by hand
+// both refresh, or the schedule the daemon runs while jobs are added.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	suffix   = ".lock"
+	timeout  = 30 * time.Second
+	stale    = time.Minute // a lock this old was left by a crashed process
+	interval = 50 * time.Millisecond
+)
+
+// Lock takes an exclusive lock next to path by creating path.lock, and
+// returns the function that releases it. It waits up to 30 seconds for
+// another process to release the lock, and breaks a lock older than a
+// minute, so it is meant for short reads and writes.
+func Lock(path string) (func(), error) {
+	lock := path + suffix
+	if err := os.MkdirAll(filepath.Dir(lock), 0700); err != nil {
+		return nil, fmt.Errorf("could not create %s: %w", filepath.Dir(lock), err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("could not lock %s: %w", filepath.Base(path), err)
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > stale {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another kit process — if none is running, delete %s", filepath.Base(path), lock)
+		}
+		time.Sleep(interval)
+	}
+}
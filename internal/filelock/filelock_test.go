@@ -0,0 +1,25 @@
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStaleLockIsBroken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	lock := path + suffix
+	os.WriteFile(lock, []byte("12345\n"), 0600)
+	old := time.Now().Add(-2 * stale)
+	os.Chtimes(lock, old, old)
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
@@ -9,7 +9,8 @@ import (
 	"strings"
 )
 
-// SupportedConversions lists all supported from→to format pairs.
+// SupportedConversions lists all supported from→to format pairs,
+// including those added with Register.
 var SupportedConversions = map[string][]string{
 	"docx": {"md", "html", "txt"},
 	"md":   {"docx"},
@@ -41,13 +42,36 @@ func Convert(inputPath, outputPath, toFmt string) (string, error) {
 	var result string
 	var err error
 
+	if c := registered(fromFmt, toFmt); c != nil {
+		result, err = c(inputPath, outputPath)
+	} else {
+		result, err = convertBuiltin(fromFmt, toFmt, inputPath, outputPath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if outputPath != "" && result != "" {
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(outputPath, []byte(result), 0644); err != nil {
+			return "", fmt.Errorf("could not write %s: %w", outputPath, err)
+		}
+		return result, nil
+	}
+
+	return result, nil
+}
+
+func convertBuiltin(fromFmt, toFmt, inputPath, outputPath string) (string, error) {
 	switch fromFmt + "→" + toFmt {
 	case "docx→md":
-		result, err = DocxToMarkdown(inputPath)
+		return DocxToMarkdown(inputPath)
 	case "docx→html":
-		result, err = DocxToHTML(inputPath)
+		return DocxToHTML(inputPath)
 	case "docx→txt":
-		result, err = DocxToText(inputPath)
+		return DocxToText(inputPath)
 	case "md→docx":
 		input, readErr := os.ReadFile(inputPath)
 		if readErr != nil {
@@ -67,30 +91,14 @@ func Convert(inputPath, outputPath, toFmt string) (string, error) {
 		}
 		return "", HTMLToDocx(string(input), outputPath)
 	case "xlsx→csv":
-		result, err = XlsxToCSV(inputPath, "")
+		return XlsxToCSV(inputPath, "")
 	case "xlsx→json":
-		result, err = XlsxToJSON(inputPath, "")
+		return XlsxToJSON(inputPath, "")
 	case "xlsx→md":
-		result, err = XlsxToMarkdown(inputPath, "")
+		return XlsxToMarkdown(inputPath, "")
 	default:
 		return "", fmt.Errorf("conversion %s → %s not implemented", fromFmt, toFmt)
 	}
-
-	if err != nil {
-		return "", err
-	}
-
-	if outputPath != "" && result != "" {
-		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-			return "", err
-		}
-		if err := os.WriteFile(outputPath, []byte(result), 0644); err != nil {
-			return "", fmt.Errorf("could not write %s: %w", outputPath, err)
-		}
-		return result, nil
-	}
-
-	return result, nil
 }
 
 func detectFormat(path string) string {
@@ -107,6 +115,6 @@ func detectFormat(path string) string {
 	case ".txt":
 		return "txt"
 	default:
-		return ""
+		return registeredFormat(strings.TrimPrefix(ext, "."))
 	}
 }
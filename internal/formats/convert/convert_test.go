@@ -365,3 +365,178 @@ func TestConvertDocxToTextContent(t *testing.T) {
 		t.Error("expected 'multiple paragraphs' in text output")
 	}
 }
+
+func TestMarkdownToHTML(t *testing.T) {
+	md := "## Notes\n\nSee **bold**, *em*, `a<b` and [docs](https://x.com?a=1&b=2)\ncontinued.\n\n" +
+		"- one\n- two\n  - nested\n\n1. first\n\n> quoted\n\n```go\nx := <y>\n```\n\n| A | B |\n|---|---|\n| 1 | 2 |\n"
+	got := MarkdownToHTML(md)
+
+	for _, want := range []string{
+		"<h2>Notes</h2>",
+		`<p>See <strong>bold</strong>, <em>em</em>, <code>a&lt;b</code> and <a href="https://x.com?a=1&amp;b=2">docs</a> continued.</p>`,
+		"<li>two\n<ul>\n<li>nested</li>\n</ul>\n</li>",
+		"<ol>\n<li>first</li>\n</ol>",
+		"<blockquote>\n<p>quoted</p>\n</blockquote>",
+		`<pre><code class="language-go">x := &lt;y&gt;</code></pre>`,
+		"<tr><th>A</th><th>B</th></tr>\n<tr><td>1</td><td>2</td></tr>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestMarkdownSections(t *testing.T) {
+	md := "\n# Release *Notes*\n\nIntro\n\n## Fixes\n\n```\n## not a heading\n```\n\n## Features\n\nNew things\n"
+	title, sections := MarkdownSections(md)
+	if title != "Release Notes" {
+		t.Errorf("title = %q", title)
+	}
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d: %q", len(sections), sections)
+	}
+	if sections[0] != "<p>Intro</p>\n" || !strings.HasPrefix(sections[2], "<h2>Features</h2>") {
+		t.Errorf("unexpected sections: %q", sections)
+	}
+	if !strings.Contains(sections[1], "<pre><code>## not a heading</code></pre>") {
+		t.Errorf("fenced heading should stay in its section: %q", sections[1])
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	in := `<html><head><style>p { color: red }</style></head><body>
+<div>Hi&nbsp;Bob,</div>
+<p>See <a href="https://nam12.safelinks.protection.outlook.com/?url=https%3A%2F%2Fexample.com%2Fq&amp;data=x">the report</a>
+and <b> note </b>this: <a href="mailto:ann@contoso.com">ann@contoso.com</a></p>
+<ul><li>One</li><li>Two<ul><li>Nested</li></ul></li></ul>
+<ol start="3"><li>Third</li></ol>
+<table><tr><th>Name</th><th>Qty</th></tr><tr><td>Apples</td><td>3</td></tr></table>
+<table role="presentation"><tr><td>Layout</td><td>cell</td></tr></table>
+<pre>  keep   spacing</pre>
+<img src="cid:logo" alt="Contoso"><img src="https://t.example/pixel.gif">
+</body></html>`
+	got := HTMLToMarkdown(in)
+
+	for _, want := range []string{
+		"Hi Bob,",
+		"See [the report](https://example.com/q) and **note** this: ann@contoso.com",
+		"- One\n- Two\n  - Nested",
+		"3. Third",
+		"| Name | Qty |\n| --- | --- |\n| Apples | 3 |",
+		"Layout cell",
+		"```\n  keep   spacing\n```",
+		"[image: Contoso]",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "color: red") || strings.Contains(got, "pixel") {
+		t.Errorf("style or tracking image leaked:\n%s", got)
+	}
+}
+
+func TestHTMLToMarkdownWithImages(t *testing.T) {
+	in := `<p>Hi <at id="0">Ann</at>, see <img src="cid:chart" alt="Chart"> and <img src="cid:logo"></p>`
+	got := HTMLToMarkdownWithImages(in, func(src string) string {
+		if src == "cid:chart" {
+			return "images/chart.png"
+		}
+		return ""
+	})
+	if want := "Hi @Ann, see ![Chart](images/chart.png) and"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestHTMLToMarkdownQuotedHistory(t *testing.T) {
+	in := `<div>Sounds good.</div><div id="appendonsend"></div><hr>` +
+		`<div id="divRplyFwdMsg"><b>From:</b> Bob<br><b>Sent:</b> Monday</div><div>Original text</div>`
+	got := HTMLToMarkdown(in)
+	if want := "Sounds good.\n\n> ---\n>\n> **From:** Bob\n> **Sent:** Monday\n> Original text"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	body, hidden := FoldQuotedReply(got)
+	if body != "Sounds good." || hidden != 4 {
+		t.Errorf("folded to %q, %d hidden", body, hidden)
+	}
+}
+
+func TestFoldQuotedReply(t *testing.T) {
+	tests := []struct {
+		in, want string
+		hidden   int
+	}{
+		{"Thanks!\n\nOn Mon, Ann wrote:\n> hi\n> there\n", "Thanks!", 3},
+		{"Done.\n\n-----Original Message-----\nFrom: Ann\nhi", "Done.", 3},
+		{"Done.\n________________________________\nFrom: Ann\nSent: Monday", "Done.", 3},
+		{"No quote here.\n\n---\nJust a rule", "No quote here.\n\n---\nJust a rule", 0},
+		{"> all quoted", "> all quoted", 0},
+	}
+	for _, tt := range tests {
+		got, hidden := FoldQuotedReply(tt.in)
+		if got != tt.want || hidden != tt.hidden {
+			t.Errorf("FoldQuotedReply(%q) = %q, %d; want %q, %d", tt.in, got, hidden, tt.want, tt.hidden)
+		}
+	}
+}
+
+func TestFileToMarkdownWorkbook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.xlsx")
+	wb := &xlsx.Workbook{
+		Sheets: []xlsx.Sheet{
+			{Name: "Q1", Rows: [][]string{{"Item", "Cost"}, {"Laptops", "1200"}}},
+			{Name: "Q2", Rows: [][]string{{"Item", "Cost"}, {"Desks", "300"}}},
+		},
+	}
+	if err := xlsx.WriteFile(wb, path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FileToMarkdown(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## Q1", "| Laptops | 1200 |", "## Q2", "| Desks | 300 |"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestFileToMarkdownDocxAndHTML(t *testing.T) {
+	dir := t.TempDir()
+	docxPath := createTestDocx(t, dir, []docx.Node{
+		{Type: docx.NodeHeading, Level: 1, Text: "Contract"},
+		{Type: docx.NodeParagraph, Text: "Terms apply."},
+	})
+	got, err := FileToMarkdown(docxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "# Contract") || !strings.Contains(got, "Terms apply.") {
+		t.Errorf("docx preview:\n%s", got)
+	}
+
+	htmlPath := filepath.Join(dir, "note.html")
+	os.WriteFile(htmlPath, []byte("<p>See <b>this</b></p>"), 0644)
+	if got, err := FileToMarkdown(htmlPath); err != nil || !strings.Contains(got, "See **this**") {
+		t.Errorf("html preview = %q, %v", got, err)
+	}
+
+	if _, err := FileToMarkdown(filepath.Join(dir, "scan.pdf")); err == nil || !strings.Contains(err.Error(), "cannot preview .pdf") {
+		t.Errorf("pdf: err = %v", err)
+	}
+}
+
+func TestCanPreview(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Report.DOCX": true, "deck.pptx": true, "data.csv": true, "page.htm": true,
+		"scan.pdf": false, "photo.png": false, "archive.zip": false,
+	} {
+		if got := CanPreview(name); got != want {
+			t.Errorf("CanPreview(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package convert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klytics/m365kit/internal/formats/docx"
+)
+
+// Converter converts the file at inputPath. It either writes outputPath
+// itself and returns "", or returns the converted text for Convert to
+// write (or print, when outputPath is empty).
+type Converter func(inputPath, outputPath string) (string, error)
+
+var (
+	extMu      sync.RWMutex
+	converters = map[string]Converter{} // "from→to"
+)
+
+// Register adds a conversion between the formats from and to, named by
+// file extension without the dot (e.g. "rtf", "md"). It replaces a
+// built-in conversion of the same pair. Custom kit builds call it, through
+// the public extension package, before running the CLI.
+func Register(from, to string, c Converter) {
+	from, to = normalizeFormat(from), normalizeFormat(to)
+	if from == "" || to == "" || c == nil {
+		panic(fmt.Sprintf("convert: invalid registration %q → %q", from, to))
+	}
+
+	extMu.Lock()
+	defer extMu.Unlock()
+	converters[from+"→"+to] = c
+	for _, f := range SupportedConversions[from] {
+		if f == to {
+			return
+		}
+	}
+	SupportedConversions[from] = append(SupportedConversions[from], to)
+}
+
+// Registered returns the conversions added with Register as "from → to",
+// sorted.
+func Registered() []string {
+	extMu.RLock()
+	defer extMu.RUnlock()
+	pairs := make([]string, 0, len(converters))
+	for pair := range converters {
+		pairs = append(pairs, strings.Replace(pair, "→", " → ", 1))
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+func registered(from, to string) Converter {
+	extMu.RLock()
+	defer extMu.RUnlock()
+	return converters[from+"→"+to]
+}
+
+// registeredFormat returns ext when some registered conversion reads it.
+func registeredFormat(ext string) string {
+	extMu.RLock()
+	defer extMu.RUnlock()
+	for pair := range converters {
+		if strings.HasPrefix(pair, ext+"→") {
+			return ext
+		}
+	}
+	return ""
+}
+
+// DocumentFormats are the formats ParseDocument reads.
+var DocumentFormats = []string{"docx", "md", "html"}
+
+// ParseDocument reads a .docx, Markdown, or HTML file into the document
+// model.
+func ParseDocument(path string) (*docx.Document, error) {
+	format := detectFormat(path)
+	switch format {
+	case "docx":
+		doc, err := docx.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse docx: %w", err)
+		}
+		return doc, nil
+	case "md", "html":
+		input, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", path, err)
+		}
+		if format == "md" {
+			return parseMarkdown(string(input)), nil
+		}
+		return parseHTML(string(input)), nil
+	}
+	return nil, fmt.Errorf("cannot read %s as a document (use .docx, .md, or .html)", filepath.Base(path))
+}
+
+func normalizeFormat(f string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(f), "."))
+}
@@ -0,0 +1,549 @@
+package convert
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	mdSpaceRe          = regexp.MustCompile(`[\s\x{00a0}]+`)
+	mdBlankLinesRe     = regexp.MustCompile(`\n{3,}`)
+	originalMsgRe      = regexp.MustCompile(`(?i)^-{2,}\s*original message\s*-{2,}$`)
+	quoteIntroRe       = regexp.MustCompile(`(?i)\bwrote:$`)
+	quoteMarkerIDs     = map[string]bool{"appendonsend": true, "divRplyFwdMsg": true, "mail-editor-reference-message-container": true}
+	quoteMarkerClasses = []string{"gmail_quote", "moz-cite-prefix"}
+)
+
+// HTMLToMarkdown converts HTML, such as an email body, to Markdown for
+// reading in a terminal. It keeps headings, emphasis, links, lists, simple
+// tables, block quotes, and preformatted text. Layout tables are flattened,
+// and scripts, styles, and images without alt text are dropped.
+//
+// Quoted reply history, recognized by the markers Outlook, Gmail, and
+// Thunderbird put in replies, is rendered as a trailing block quote so
+// FoldQuotedReply can hide it.
+func HTMLToMarkdown(input string) string {
+	return htmlToMarkdown(input, nil)
+}
+
+// HTMLToMarkdownWithImages is HTMLToMarkdown with images kept as Markdown
+// images: image maps each <img> src to the location to show, such as a
+// file the image was saved to, or "" to render it as HTMLToMarkdown does.
+func HTMLToMarkdownWithImages(input string, image func(src string) string) string {
+	return htmlToMarkdown(input, image)
+}
+
+func htmlToMarkdown(input string, image func(src string) string) string {
+	doc, err := html.Parse(strings.NewReader(input))
+	if err != nil {
+		return input
+	}
+	quoted := splitQuotedHistory(doc)
+
+	r := &mdRenderer{image: image}
+	r.children(doc)
+	out := r.String()
+	if quoted != nil {
+		q := &mdRenderer{image: image}
+		q.children(quoted)
+		if s := q.String(); s != "" {
+			out = strings.TrimSpace(out + "\n\n" + prefixLines(s, "> ", ">"))
+		}
+	}
+	return out
+}
+
+// FoldQuotedReply removes the quoted history at the end of an email: a
+// trailing block of ">" lines (with its "… wrote:" line), an
+// "-----Original Message-----" block, or a separator line followed by a
+// "From:" header. It returns the remaining text and the number of lines
+// hidden, not counting blank ones. Text that is entirely quoted is
+// returned unchanged.
+func FoldQuotedReply(text string) (string, int) {
+	lines := strings.Split(strings.TrimRight(text, "\n\t "), "\n")
+	cut := len(lines)
+
+	i := len(lines)
+	for i > 0 && (strings.HasPrefix(lines[i-1], ">") || strings.TrimSpace(lines[i-1]) == "") {
+		i--
+	}
+	if i < len(lines) {
+		cut = i
+		if cut > 0 && quoteIntroRe.MatchString(strings.TrimSpace(lines[cut-1])) {
+			cut--
+		}
+	}
+
+	for j, line := range lines[:cut] {
+		t := strings.TrimSpace(line)
+		if originalMsgRe.MatchString(t) || (isSeparatorLine(t) && nextLineIsFrom(lines[j+1:])) {
+			cut = j
+			break
+		}
+	}
+
+	kept := strings.TrimRight(strings.Join(lines[:cut], "\n"), "\n\t ")
+	if cut == len(lines) || strings.TrimSpace(kept) == "" {
+		return text, 0
+	}
+	hidden := 0
+	for _, l := range lines[cut:] {
+		if strings.Trim(l, "> \t") != "" {
+			hidden++
+		}
+	}
+	return kept, hidden
+}
+
+func isSeparatorLine(t string) bool {
+	return t == "---" || (len(t) >= 8 && strings.Trim(t, "_") == "")
+}
+
+func nextLineIsFrom(lines []string) bool {
+	for _, l := range lines {
+		if t := strings.TrimSpace(l); t != "" {
+			return strings.HasPrefix(strings.TrimLeft(t, "*"), "From:")
+		}
+	}
+	return false
+}
+
+// mdRenderer accumulates Markdown for a tree of HTML nodes. Nested blocks
+// (list items, quotes, table cells) are rendered by child renderers and then
+// prefixed or flattened.
+type mdRenderer struct {
+	b         strings.Builder
+	pre       int  // inside <pre>: whitespace is kept
+	listDepth int  // nested lists are not separated by blank lines
+	midLine   bool // rendering inline content that continues a line
+
+	image func(src string) string // see HTMLToMarkdownWithImages
+}
+
+func (r *mdRenderer) String() string {
+	lines := strings.Split(r.b.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.Trim(mdBlankLinesRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"), "\n")
+}
+
+func (r *mdRenderer) atLineStart() bool {
+	if r.b.Len() == 0 {
+		return !r.midLine
+	}
+	return strings.HasSuffix(r.b.String(), "\n")
+}
+
+// newline ends the current line, if any.
+func (r *mdRenderer) newline() {
+	if !r.atLineStart() {
+		r.b.WriteString("\n")
+	}
+}
+
+// paragraph separates blocks with a blank line.
+func (r *mdRenderer) paragraph() {
+	r.newline()
+	if s := r.b.String(); s != "" && !strings.HasSuffix(s, "\n\n") {
+		r.b.WriteString("\n")
+	}
+}
+
+func (r *mdRenderer) text(s string) {
+	if r.pre > 0 {
+		r.b.WriteString(s)
+		return
+	}
+	s = mdSpaceRe.ReplaceAllString(s, " ")
+	if r.atLineStart() {
+		s = strings.TrimLeft(s, " ")
+	}
+	r.b.WriteString(s)
+}
+
+func (r *mdRenderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.node(c)
+	}
+}
+
+// block renders n's children on their own, as for a list item or quote.
+func (r *mdRenderer) block(n *html.Node) string {
+	s := &mdRenderer{pre: r.pre, listDepth: r.listDepth, image: r.image}
+	s.children(n)
+	return s.String()
+}
+
+// inline renders n's children as a continuation of the current line,
+// keeping leading and trailing spaces.
+func (r *mdRenderer) inline(n *html.Node) string {
+	s := &mdRenderer{pre: r.pre, listDepth: r.listDepth, midLine: !r.atLineStart(), image: r.image}
+	s.children(n)
+	return s.b.String()
+}
+
+func (r *mdRenderer) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.DocumentNode:
+		r.children(n)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.Data {
+	case "head", "script", "style", "title", "meta", "link", "noscript", "template":
+	case "br":
+		r.b.WriteString("\n")
+	case "hr":
+		r.paragraph()
+		r.b.WriteString("---")
+		r.paragraph()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.paragraph()
+		if t := strings.Join(strings.Fields(r.block(n)), " "); t != "" {
+			r.b.WriteString(strings.Repeat("#", int(n.Data[1]-'0')) + " " + t)
+		}
+		r.paragraph()
+	case "p":
+		r.paragraph()
+		r.children(n)
+		r.paragraph()
+	case "div", "section", "article", "header", "footer", "main", "nav", "aside", "center", "address", "figure", "tr", "dl", "dt", "dd":
+		r.newline()
+		r.children(n)
+		r.newline()
+	case "b", "strong":
+		r.emphasis(n, "**")
+	case "i", "em":
+		r.emphasis(n, "*")
+	case "s", "strike", "del":
+		r.emphasis(n, "~~")
+	case "code", "tt", "kbd", "samp":
+		if r.pre > 0 {
+			r.children(n)
+		} else {
+			r.emphasis(n, "`")
+		}
+	case "pre":
+		r.paragraph()
+		r.b.WriteString("```\n")
+		r.pre++
+		r.children(n)
+		r.pre--
+		r.newline()
+		r.b.WriteString("```")
+		r.paragraph()
+	case "a":
+		r.link(n)
+	case "img":
+		r.img(n)
+	case "at":
+		// Teams @mentions
+		inner := r.inline(n)
+		if t := strings.TrimSpace(inner); t != "" {
+			lead, trail := r.spaceAround(inner)
+			r.b.WriteString(lead + "@" + t + trail)
+		}
+	case "ul", "ol":
+		r.list(n)
+	case "li":
+		r.listItem(n, "- ")
+	case "blockquote":
+		r.paragraph()
+		if q := r.block(n); q != "" {
+			r.b.WriteString(prefixLines(q, "> ", ">"))
+		}
+		r.paragraph()
+	case "table":
+		r.table(n)
+	case "td", "th":
+		// Cells of layout tables read left to right.
+		r.children(n)
+		r.text(" ")
+	default:
+		r.children(n)
+	}
+}
+
+func (r *mdRenderer) emphasis(n *html.Node, mark string) {
+	inner := r.inline(n)
+	t := strings.TrimSpace(inner)
+	if t == "" || strings.Contains(t, "\n") {
+		r.b.WriteString(inner)
+		return
+	}
+	lead, trail := r.spaceAround(inner)
+	r.b.WriteString(lead + mark + t + mark + trail)
+}
+
+func (r *mdRenderer) img(n *html.Node) {
+	alt := strings.TrimSpace(attr(n, "alt"))
+	if r.image != nil {
+		if target := r.image(attr(n, "src")); target != "" {
+			if alt == "" {
+				alt = "image"
+			}
+			r.text("![" + alt + "](" + target + ")")
+			return
+		}
+	}
+	if alt != "" {
+		r.text("[image: " + alt + "]")
+	}
+}
+
+func (r *mdRenderer) link(n *html.Node) {
+	href := unwrapSafeLink(strings.TrimSpace(attr(n, "href")))
+	inner := r.inline(n)
+	t := strings.TrimSpace(inner)
+	lead, trail := r.spaceAround(inner)
+	lower := strings.ToLower(href)
+	switch {
+	case href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(lower, "javascript:") || strings.Contains(t, "\n"):
+		r.b.WriteString(inner)
+	case t == "":
+		// Image-only links without alt text carry nothing to read.
+	case t == href || "mailto:"+t == href || "http://"+t == href || "https://"+t == href || "https://"+t+"/" == href:
+		r.b.WriteString(lead + t + trail)
+	default:
+		r.b.WriteString(lead + "[" + t + "](" + href + ")" + trail)
+	}
+}
+
+func (r *mdRenderer) list(n *html.Node) {
+	r.listBreak()
+	num, _ := strconv.Atoi(attr(n, "start"))
+	if num == 0 {
+		num = 1
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.Data {
+		case "li":
+			marker := "- "
+			if n.Data == "ol" {
+				marker = strconv.Itoa(num) + ". "
+				num++
+			}
+			r.listItem(c, marker)
+		case "ul", "ol":
+			// Outlook nests lists directly inside lists rather than in an item.
+			s := &mdRenderer{pre: r.pre, listDepth: r.listDepth + 1, image: r.image}
+			s.node(c)
+			r.newline()
+			r.b.WriteString(prefixLines(s.String(), "  ", ""))
+			r.newline()
+		default:
+			r.node(c)
+		}
+	}
+	r.listBreak()
+}
+
+func (r *mdRenderer) listBreak() {
+	if r.listDepth > 0 {
+		r.newline()
+	} else {
+		r.paragraph()
+	}
+}
+
+func (r *mdRenderer) listItem(n *html.Node, marker string) {
+	r.newline()
+	s := &mdRenderer{pre: r.pre, listDepth: r.listDepth + 1, image: r.image}
+	s.children(n)
+	lines := strings.Split(s.String(), "\n")
+	pad := strings.Repeat(" ", len(marker))
+	for i, l := range lines {
+		switch {
+		case i == 0:
+			lines[i] = marker + l
+		case l != "":
+			lines[i] = pad + l
+		}
+	}
+	r.b.WriteString(strings.Join(lines, "\n"))
+	r.newline()
+}
+
+// table renders a data table as a GFM table. Tables used for page layout,
+// as most HTML email is, are flattened into their text instead.
+func (r *mdRenderer) table(n *html.Node) {
+	rows := tableRows(n)
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	layout := len(rows) < 2 || width < 2 || attr(n, "role") == "presentation" ||
+		findNode(n.FirstChild, func(c *html.Node) bool { return c.Type == html.ElementNode && c.Data == "table" }) != nil
+	if layout {
+		r.newline()
+		r.children(n)
+		r.newline()
+		return
+	}
+
+	r.paragraph()
+	for i, row := range rows {
+		cells := make([]string, width)
+		for j, cell := range row {
+			text := strings.Join(strings.Fields(r.block(cell)), " ")
+			cells[j] = strings.ReplaceAll(text, "|", `\|`)
+		}
+		r.b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			r.b.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	r.paragraph()
+}
+
+// tableRows returns the cells of each row of a table, not descending into
+// nested tables.
+func tableRows(table *html.Node) [][]*html.Node {
+	var rows [][]*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "thead", "tbody", "tfoot":
+				walk(c)
+			case "tr":
+				var row []*html.Node
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+						row = append(row, cell)
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	walk(table)
+	return rows
+}
+
+// splitQuotedHistory detaches the first quote marker and everything after
+// it in document order, returning it under a new container node, or nil if
+// the document has no quoted history.
+func splitQuotedHistory(doc *html.Node) *html.Node {
+	marker := findNode(doc, isQuoteMarker)
+	if marker == nil {
+		return nil
+	}
+	quoted := &html.Node{Type: html.ElementNode, Data: "div"}
+	move := func(from *html.Node) {
+		for c := from; c != nil; {
+			next := c.NextSibling
+			c.Parent.RemoveChild(c)
+			quoted.AppendChild(c)
+			c = next
+		}
+	}
+	parent := marker.Parent
+	move(marker)
+	for parent != nil && parent.Type == html.ElementNode && parent.Data != "body" && parent.Data != "html" {
+		next, up := parent.NextSibling, parent.Parent
+		if next != nil {
+			move(next)
+		}
+		parent = up
+	}
+	return quoted
+}
+
+func isQuoteMarker(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if quoteMarkerIDs[attr(n, "id")] {
+		return true
+	}
+	if n.Data == "blockquote" && attr(n, "type") == "cite" {
+		return true
+	}
+	for _, class := range strings.Fields(attr(n, "class")) {
+		for _, marker := range quoteMarkerClasses {
+			if class == marker {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findNode returns the first node at or after n, in document order, for
+// which match is true.
+func findNode(n *html.Node, match func(*html.Node) bool) *html.Node {
+	for ; n != nil; n = n.NextSibling {
+		if match(n) {
+			return n
+		}
+		if found := findNode(n.FirstChild, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// unwrapSafeLink returns the original URL of an Outlook Safe Links
+// redirect, or href unchanged.
+func unwrapSafeLink(href string) string {
+	u, err := url.Parse(href)
+	if err != nil || !strings.HasSuffix(u.Host, "safelinks.protection.outlook.com") {
+		return href
+	}
+	if target := u.Query().Get("url"); target != "" {
+		return target
+	}
+	return href
+}
+
+// spaceAround returns the single spaces to keep around trimmed inline
+// content, dropping a leading one that would double an existing space.
+func (r *mdRenderer) spaceAround(s string) (lead, trail string) {
+	if strings.TrimLeft(s, " ") != s && !r.atLineStart() && !strings.HasSuffix(r.b.String(), " ") {
+		lead = " "
+	}
+	if strings.TrimRight(s, " ") != s {
+		trail = " "
+	}
+	return lead, trail
+}
+
+// prefixLines prefixes every line of s, using empty for blank lines.
+func prefixLines(s, prefix, empty string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l == "" {
+			lines[i] = empty
+		} else {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,230 @@
+package convert
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mdCodeSpanRe = regexp.MustCompile("`([^`]+)`")
+	mdLinkRe     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldRe     = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdItalicRe   = regexp.MustCompile(`\*(.+?)\*`)
+	mdHeadingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	mdBulletRe   = regexp.MustCompile(`^[-*+]\s+`)
+)
+
+// MarkdownToHTML renders Markdown as an HTML fragment. It supports headings,
+// paragraphs, nested lists, block quotes, fenced code, GFM tables, rules,
+// and inline bold, italic, code, and links.
+func MarkdownToHTML(input string) string {
+	var b strings.Builder
+	renderMarkdownBlocks(&b, strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n"))
+	return b.String()
+}
+
+// MarkdownSections renders Markdown as one HTML fragment per level-2
+// section, for publishing as separate page sections. A leading level-1
+// heading is returned as the title instead of being rendered.
+func MarkdownSections(input string) (string, []string) {
+	lines := strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	var title string
+	if len(lines) > 0 {
+		if m := mdHeadingRe.FindStringSubmatch(strings.TrimSpace(lines[0])); m != nil && len(m[1]) == 1 {
+			title = stripFormatting(m[2])
+			lines = lines[1:]
+		}
+	}
+
+	var sections []string
+	var chunk []string
+	flush := func() {
+		if html := MarkdownToHTML(strings.Join(chunk, "\n")); html != "" {
+			sections = append(sections, html)
+		}
+		chunk = nil
+	}
+	inFence := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if isFence(trimmed) {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(trimmed, "## ") && len(chunk) > 0 {
+			flush()
+		}
+		chunk = append(chunk, line)
+	}
+	flush()
+	return title, sections
+}
+
+func renderMarkdownBlocks(b *strings.Builder, lines []string) {
+	i := 0
+	for i < len(lines) {
+		trimmed := strings.TrimSpace(lines[i])
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case isFence(trimmed):
+			lang := strings.TrimSpace(strings.TrimLeft(trimmed, "`~"))
+			var code []string
+			for i++; i < len(lines) && !isFence(strings.TrimSpace(lines[i])); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // closing fence
+			if lang != "" {
+				b.WriteString(`<pre><code class="language-` + htmlEscape(lang) + `">`)
+			} else {
+				b.WriteString("<pre><code>")
+			}
+			b.WriteString(htmlEscape(strings.Join(code, "\n")))
+			b.WriteString("</code></pre>\n")
+
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			b.WriteString("<hr>\n")
+			i++
+
+		case mdHeadingRe.MatchString(trimmed):
+			m := mdHeadingRe.FindStringSubmatch(trimmed)
+			tag := "h" + string(rune('0'+len(m[1])))
+			b.WriteString("<" + tag + ">" + renderInline(m[2]) + "</" + tag + ">\n")
+			i++
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && isSeparatorRow(strings.TrimSpace(lines[i+1])):
+			b.WriteString("<table>\n<tr>")
+			for _, cell := range parseTableRow(trimmed) {
+				b.WriteString("<th>" + renderInline(cell) + "</th>")
+			}
+			b.WriteString("</tr>\n")
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				b.WriteString("<tr>")
+				for _, cell := range parseTableRow(strings.TrimSpace(lines[i])) {
+					b.WriteString("<td>" + renderInline(cell) + "</td>")
+				}
+				b.WriteString("</tr>\n")
+			}
+			b.WriteString("</table>\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+			}
+			b.WriteString("<blockquote>\n")
+			renderMarkdownBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+
+		case listMarker(trimmed) != "":
+			i = renderList(b, lines, i)
+
+		default:
+			var para []string
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if t == "" || (len(para) > 0 && startsBlock(t)) {
+					break
+				}
+				para = append(para, t)
+			}
+			b.WriteString("<p>" + renderInline(strings.Join(para, " ")) + "</p>\n")
+		}
+	}
+}
+
+// renderList renders the list starting at lines[i] and returns the index of
+// the first line after it. Indented lines belong to the preceding item.
+func renderList(b *strings.Builder, lines []string, i int) int {
+	kind := listMarker(strings.TrimSpace(lines[i]))
+	b.WriteString("<" + kind + ">\n")
+	for i < len(lines) {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || isIndented(line) || listMarker(trimmed) != kind {
+			break
+		}
+		text := stripListMarker(trimmed)
+
+		var body []string
+		for i++; i < len(lines) && isIndented(lines[i]); i++ {
+			body = append(body, strings.TrimLeft(lines[i], " \t"))
+		}
+
+		b.WriteString("<li>")
+		var nested []string
+		for j, l := range body {
+			if listMarker(l) != "" {
+				nested = body[j:]
+				break
+			}
+			text += " " + l
+		}
+		b.WriteString(renderInline(text))
+		if len(nested) > 0 {
+			b.WriteString("\n")
+			renderMarkdownBlocks(b, nested)
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + kind + ">\n")
+	return i
+}
+
+// renderInline escapes text and applies inline code, links, and emphasis.
+func renderInline(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range mdCodeSpanRe.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(renderEmphasis(text[last:loc[0]]))
+		b.WriteString("<code>" + htmlEscape(text[loc[2]:loc[3]]) + "</code>")
+		last = loc[1]
+	}
+	b.WriteString(renderEmphasis(text[last:]))
+	return b.String()
+}
+
+func renderEmphasis(text string) string {
+	text = htmlEscape(text)
+	text = mdLinkRe.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	text = mdBoldRe.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = mdItalicRe.ReplaceAllString(text, "<em>$1</em>")
+	return text
+}
+
+// listMarker returns "ul" or "ol" if the line starts a list item.
+func listMarker(trimmed string) string {
+	switch {
+	case mdBulletRe.MatchString(trimmed) && trimmed != "---" && trimmed != "***":
+		return "ul"
+	case orderedListRe.MatchString(trimmed):
+		return "ol"
+	}
+	return ""
+}
+
+func stripListMarker(trimmed string) string {
+	if loc := mdBulletRe.FindStringIndex(trimmed); loc != nil {
+		return trimmed[loc[1]:]
+	}
+	return strings.TrimSpace(trimmed[strings.Index(trimmed, ".")+1:])
+}
+
+func startsBlock(trimmed string) bool {
+	return isFence(trimmed) || mdHeadingRe.MatchString(trimmed) || strings.HasPrefix(trimmed, ">") ||
+		listMarker(trimmed) != "" || trimmed == "---" || trimmed == "***" || trimmed == "___"
+}
+
+func isFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+}
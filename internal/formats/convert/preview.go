@@ -0,0 +1,102 @@
+package convert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klytics/m365kit/internal/formats/pptx"
+	"github.com/klytics/m365kit/internal/formats/xlsx"
+)
+
+// textExtensions are shown as they are by FileToMarkdown.
+var textExtensions = map[string]bool{
+	".txt": true, ".md": true, ".markdown": true, ".csv": true,
+	".json": true, ".xml": true, ".log": true, ".yaml": true, ".yml": true,
+}
+
+// CanPreview reports whether FileToMarkdown can render a file with this name.
+func CanPreview(name string) bool {
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".docx", ".xlsx", ".pptx", ".html", ".htm":
+		return true
+	default:
+		return textExtensions[ext]
+	}
+}
+
+// FileToMarkdown renders a document as Markdown for reading in a terminal
+// or passing to an AI command: Word documents as Markdown, every sheet of a
+// workbook as a table, slides as sections, and HTML as converted by
+// HTMLToMarkdown. Text files are returned as they are.
+func FileToMarkdown(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".docx":
+		return DocxToMarkdown(path)
+	case ".xlsx":
+		return workbookToMarkdown(path)
+	case ".pptx":
+		pres, err := pptx.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return presentationToMarkdown(pres), nil
+	}
+
+	if ext != ".html" && ext != ".htm" && !textExtensions[ext] {
+		return "", fmt.Errorf("cannot preview %s files (supported: .docx, .xlsx, .pptx, .html, and text)", ext)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s: %w", path, err)
+	}
+	if ext == ".html" || ext == ".htm" {
+		return HTMLToMarkdown(string(data)), nil
+	}
+	return string(data), nil
+}
+
+func workbookToMarkdown(path string) (string, error) {
+	wb, err := xlsx.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read xlsx: %w", err)
+	}
+	var b strings.Builder
+	for i := range wb.Sheets {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", wb.Sheets[i].Name)
+		if table := sheetToMarkdown(&wb.Sheets[i]); table != "" {
+			b.WriteString(table)
+		} else {
+			b.WriteString("(empty)\n")
+		}
+	}
+	return b.String(), nil
+}
+
+func presentationToMarkdown(pres *pptx.Presentation) string {
+	var b strings.Builder
+	for i, slide := range pres.Slides {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if slide.Title != "" {
+			fmt.Fprintf(&b, "## Slide %d: %s\n\n", slide.Number, slide.Title)
+		} else {
+			fmt.Fprintf(&b, "## Slide %d\n\n", slide.Number)
+		}
+		for _, text := range slide.TextContent {
+			if text != slide.Title {
+				fmt.Fprintf(&b, "- %s\n", text)
+			}
+		}
+		if len(slide.Notes) > 0 {
+			fmt.Fprintf(&b, "\n> Notes: %s\n", strings.Join(slide.Notes, " "))
+		}
+	}
+	return b.String()
+}
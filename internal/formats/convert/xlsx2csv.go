@@ -59,8 +59,14 @@ func XlsxToMarkdown(inputPath, sheetName string) (string, error) {
 		return "", err
 	}
 
+	return sheetToMarkdown(sheet), nil
+}
+
+// sheetToMarkdown renders a sheet as a Markdown table, its first row as the
+// header.
+func sheetToMarkdown(sheet *xlsx.Sheet) string {
 	if len(sheet.Rows) < 1 {
-		return "", nil
+		return ""
 	}
 
 	headers := sheet.Rows[0]
@@ -91,7 +97,7 @@ func XlsxToMarkdown(inputPath, sheetName string) (string, error) {
 		b.WriteString(" |\n")
 	}
 
-	return b.String(), nil
+	return b.String()
 }
 
 func getSheet(inputPath, sheetName string) (*xlsx.Sheet, error) {
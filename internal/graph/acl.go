@@ -18,9 +18,13 @@ type Permission struct {
 	GrantedTo     *Principal `json:"grantedTo,omitempty"`
 	GrantedToV2   *Principal `json:"grantedToV2,omitempty"`
 	Link          *PermLink  `json:"link,omitempty"`
+	ExpiresAt     *time.Time `json:"expirationDateTime,omitempty"`
+	HasPassword   bool       `json:"hasPassword,omitempty"`
 	InheritedFrom *struct {
 		ID string `json:"id"`
 	} `json:"inheritedFrom,omitempty"`
+	// GrantedToIdentitiesV2 lists the recipients of a sharing link.
+	GrantedToIdentitiesV2 []Principal `json:"grantedToIdentitiesV2,omitempty"`
 }
 
 // IsInherited returns true if this permission is inherited from a parent.
@@ -92,6 +96,8 @@ type ACLReport struct {
 	BrokenInheritance int        `json:"brokenInheritance"`
 	AnonymousLinks    int        `json:"anonymousLinks"`
 	Entries           []ACLEntry `json:"entries"`
+	// Violations is filled in when the report is evaluated against a policy.
+	Violations []PolicyViolation `json:"violations,omitempty"`
 }
 
 // ACLEntry represents the permissions on a single file.
@@ -100,12 +106,15 @@ type ACLEntry struct {
 	Permissions          []Permission `json:"permissions"`
 	HasUniquePermissions bool         `json:"hasUniquePermissions"`
 	ExternalUsers        []string     `json:"externalUsers,omitempty"`
+	IsFolder             bool         `json:"isFolder,omitempty"`
+	Labels               *ItemLabels  `json:"labels,omitempty"`
 }
 
 // ACL provides SharePoint permissions audit operations.
 type ACL struct {
 	Client    *http.Client
 	OrgDomain string // e.g., "company.com" — used for external detection
+	Labels    bool   // also read sensitivity and retention labels
 }
 
 // NewACL creates a new ACL client.
@@ -113,40 +122,16 @@ func NewACL(client *http.Client, orgDomain string) *ACL {
 	return &ACL{Client: client, OrgDomain: orgDomain}
 }
 
-type permissionsResponse struct {
-	Value []Permission `json:"value"`
-}
-
 // GetFilePermissions returns permissions for a specific file.
 func (a *ACL) GetFilePermissions(ctx context.Context, siteID, driveID, itemID string) ([]Permission, error) {
-	endpoint := graphBase + "/sites/" + siteID + "/drives/" + driveID + "/items/" + url.PathEscape(itemID) + "/permissions"
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := a.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("could not get permissions: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("get permissions failed (%d): %s", resp.StatusCode, string(body))
-	}
-
-	var result permissionsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("could not parse permissions: %w", err)
-	}
-	return result.Value, nil
+	endpoint := graphBase() + "/sites/" + siteID + "/drives/" + driveID + "/items/" + url.PathEscape(itemID) + "/permissions"
+	return GetAll[Permission](ctx, a.Client, endpoint, "get permissions", 0)
 }
 
 // AuditSitePermissions scans files in a site's default drive and returns an ACL report.
 func (a *ACL) AuditSitePermissions(ctx context.Context, siteID string) (*ACLReport, error) {
 	// Get the default drive
-	endpoint := graphBase + "/sites/" + siteID + "/drive"
+	endpoint := graphBase() + "/sites/" + siteID + "/drive"
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
@@ -172,51 +157,81 @@ func (a *ACL) AuditSitePermissions(ctx context.Context, siteID string) (*ACLRepo
 
 // AuditDrive scans all files in a drive and returns their permissions.
 func (a *ACL) AuditDrive(ctx context.Context, siteID, driveID string) (*ACLReport, error) {
-	// List all items in the drive
-	endpoint := graphBase + "/sites/" + siteID + "/drives/" + driveID + "/root/children"
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	return a.auditDrive(ctx, graphBase()+"/sites/"+siteID+"/drives/"+driveID, siteID, driveID)
+}
+
+// AuditMyDrive scans the signed-in user's OneDrive. Without an OrgDomain,
+// users outside the owner's email domain count as external.
+func (a *ACL) AuditMyDrive(ctx context.Context) (*ACLReport, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", graphBase()+"/me/drive?$select=id,owner", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := a.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("could not list drive items: %w", err)
+		return nil, fmt.Errorf("could not get OneDrive: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var itemsResp struct {
-		Value []struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-		} `json:"value"`
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("get OneDrive failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+	var drive struct {
+		ID    string `json:"id"`
+		Owner struct {
+			User *GraphUser `json:"user"`
+		} `json:"owner"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&drive); err != nil || drive.ID == "" {
+		return nil, fmt.Errorf("could not determine your OneDrive")
+	}
+
+	label := "OneDrive"
+	if u := drive.Owner.User; u != nil {
+		if u.DisplayName != "" {
+			label += " (" + u.DisplayName + ")"
+		}
+		if a.OrgDomain == "" {
+			a.OrgDomain = emailDomain(u.Email)
+		}
+	}
+	return a.auditDrive(ctx, graphBase()+"/drives/"+drive.ID, label, drive.ID)
+}
+
+// auditDrive scans the top-level items of the drive at base, a Graph URL
+// such as .../sites/{site}/drives/{drive}.
+func (a *ACL) auditDrive(ctx context.Context, base, site, driveID string) (*ACLReport, error) {
+	// List all items in the drive
+	items, err := GetAll[DriveItem](ctx, a.Client, base+"/root/children", "list drive items", 0)
+	if err != nil {
+		return nil, err
 	}
-	json.NewDecoder(resp.Body).Decode(&itemsResp)
 
 	report := &ACLReport{
-		Site:        siteID,
+		Site:        site,
 		GeneratedAt: time.Now(),
 	}
+	var labels *LabelReader
+	if a.Labels {
+		labels = NewLabelReader(a.Client)
+	}
 
-	for _, item := range itemsResp.Value {
-		perms, err := a.GetFilePermissions(ctx, siteID, driveID, item.ID)
+	for _, item := range items {
+		perms, err := GetAll[Permission](ctx, a.Client, base+"/items/"+url.PathEscape(item.ID)+"/permissions", "get permissions", 0)
 		if err != nil {
 			continue
 		}
 
-		entry := ACLEntry{
-			Path:        item.Name,
-			Permissions: perms,
+		entry := a.newEntry(item.Name, perms)
+		entry.IsFolder = item.IsFolder
+		if labels != nil {
+			if l, err := labels.ItemLabels(ctx, driveID, item); err == nil {
+				entry.Labels = l
+			}
 		}
-
-		// Analyze permissions
 		for _, p := range perms {
-			if !p.IsInherited() {
-				entry.HasUniquePermissions = true
-			}
-			if p.IsExternal(a.OrgDomain) {
-				entry.ExternalUsers = append(entry.ExternalUsers, p.GetEmail())
-			}
 			if p.IsAnonymousLink() {
 				report.AnonymousLinks++
 			}
@@ -236,6 +251,31 @@ func (a *ACL) AuditDrive(ctx context.Context, siteID, driveID string) (*ACLRepor
 	return report, nil
 }
 
+// newEntry analyzes the permissions on one item.
+func (a *ACL) newEntry(path string, perms []Permission) ACLEntry {
+	entry := ACLEntry{Path: path, Permissions: perms}
+	for _, p := range perms {
+		if !p.IsInherited() {
+			entry.HasUniquePermissions = true
+		}
+		if p.IsExternal(a.OrgDomain) {
+			entry.ExternalUsers = append(entry.ExternalUsers, p.GetEmail())
+		}
+	}
+	return entry
+}
+
+// CheckItem reads the permissions on a single file or folder by path,
+// without auditing the rest of the library.
+func (a *ACL) CheckItem(ctx context.Context, driveID, itemPath string) (*ACLEntry, error) {
+	perms, err := GetAll[Permission](ctx, a.Client, driveItemURL(driveID, itemPath)+":/permissions", "get permissions", 0)
+	if err != nil {
+		return nil, err
+	}
+	entry := a.newEntry(strings.Trim(itemPath, "/"), perms)
+	return &entry, nil
+}
+
 // FindExternalShares returns entries with external user access.
 func FindExternalShares(report *ACLReport) []ACLEntry {
 	var result []ACLEntry
@@ -258,6 +298,18 @@ func FindBrokenInheritance(report *ACLReport) []ACLEntry {
 	return result
 }
 
+// FindUnlabeled returns entries whose labels were read but that carry
+// neither a sensitivity nor a retention label.
+func FindUnlabeled(report *ACLReport) []ACLEntry {
+	var result []ACLEntry
+	for _, entry := range report.Entries {
+		if entry.Labels != nil && entry.Labels.Sensitivity == "" && entry.Labels.Retention == "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
 // CountAnonymousLinks returns the total anonymous link count.
 func CountAnonymousLinks(report *ACLReport) int {
 	return report.AnonymousLinks
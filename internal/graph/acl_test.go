@@ -1,8 +1,10 @@
 package graph
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -87,6 +89,22 @@ func TestFindBrokenInheritance(t *testing.T) {
 	}
 }
 
+func TestFindUnlabeled(t *testing.T) {
+	report := &ACLReport{
+		Entries: []ACLEntry{
+			{Path: "doc1.docx", Labels: &ItemLabels{Sensitivity: "Confidential"}},
+			{Path: "doc2.docx", Labels: &ItemLabels{}},
+			{Path: "Contracts", IsFolder: true, Labels: &ItemLabels{Retention: "Keep 7 years"}},
+			{Path: "doc3.xlsx"},
+		},
+	}
+
+	result := FindUnlabeled(report)
+	if len(result) != 1 || result[0].Path != "doc2.docx" {
+		t.Errorf("expected only doc2.docx unlabeled, got %+v", result)
+	}
+}
+
 func TestACLReportAggregation(t *testing.T) {
 	report := &ACLReport{
 		TotalFiles:        5,
@@ -184,3 +202,63 @@ func TestACLReportJSON(t *testing.T) {
 		t.Errorf("TotalFiles = %d", decoded.TotalFiles)
 	}
 }
+
+func TestAuditMyDrive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/me/drive":
+			w.Write([]byte(`{"id":"od1","owner":{"user":{"displayName":"Alice","email":"alice@contoso.com"}}}`))
+		case "/v1.0/drives/od1/root/children":
+			w.Write([]byte(`{"value":[{"id":"i1","name":"plan.docx"}]}`))
+		case "/v1.0/drives/od1/items/i1/permissions":
+			w.Write([]byte(`{"value":[
+				{"id":"p1","roles":["owner"],"grantedToV2":{"user":{"email":"alice@contoso.com"}}},
+				{"id":"p2","roles":["write"],"grantedToV2":{"user":{"email":"friend@gmail.com"}}},
+				{"id":"p3","roles":["read"],"link":{"scope":"anonymous","type":"view"}}
+			]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	a := NewACL(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}, "")
+	report, err := a.AuditMyDrive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Site != "OneDrive (Alice)" || report.TotalFiles != 1 || report.AnonymousLinks != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if ext := report.Entries[0].ExternalUsers; len(ext) != 1 || ext[0] != "friend@gmail.com" {
+		t.Errorf("external users = %v, want only friend@gmail.com", ext)
+	}
+}
+
+func TestCheckItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/drives/d1/root:/Contracts/nda.docx:/permissions" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"value":[
+			{"id":"p1","roles":["read"],"grantedToV2":{"user":{"email":"x@ext.com"}}},
+			{"id":"p2","roles":["write"],"grantedToV2":{"user":{"email":"bob@contoso.com"}},"inheritedFrom":{"id":"root"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	a := NewACL(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}, "contoso.com")
+	entry, err := a.CheckItem(context.Background(), "d1", "/Contracts/nda.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Path != "Contracts/nda.docx" || len(entry.Permissions) != 2 || !entry.HasUniquePermissions {
+		t.Errorf("entry = %+v", entry)
+	}
+	if len(entry.ExternalUsers) != 1 || entry.ExternalUsers[0] != "x@ext.com" {
+		t.Errorf("external users = %v", entry.ExternalUsers)
+	}
+}
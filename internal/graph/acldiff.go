@@ -0,0 +1,102 @@
+package graph
+
+import "sort"
+
+// ACLChange is one finding in an ACLDiff.
+type ACLChange struct {
+	Path         string   `json:"path"`
+	Grantee      string   `json:"grantee"`
+	Roles        []string `json:"roles,omitempty"`
+	PermissionID string   `json:"permissionId,omitempty"`
+}
+
+// ACLDiff lists what changed between two audits of the same site.
+type ACLDiff struct {
+	NewExternalUsers   []ACLChange `json:"newExternalUsers"`
+	NewAnonymousLinks  []ACLChange `json:"newAnonymousLinks"`
+	RemovedPermissions []ACLChange `json:"removedPermissions"`
+}
+
+// Empty reports whether the diff found no changes.
+func (d *ACLDiff) Empty() bool {
+	return len(d.NewExternalUsers) == 0 && len(d.NewAnonymousLinks) == 0 && len(d.RemovedPermissions) == 0
+}
+
+// Grantee describes who a permission grants access to: an email, a group
+// name, or the kind of sharing link.
+func (p Permission) Grantee() string {
+	if email := p.GetEmail(); email != "" {
+		return email
+	}
+	for _, pr := range p.Principals() {
+		switch {
+		case pr.User != nil && pr.User.Email != "":
+			return pr.User.Email
+		case pr.User != nil:
+			return pr.User.DisplayName
+		case pr.Group != nil:
+			return pr.Group.DisplayName
+		}
+	}
+	if p.Link != nil {
+		return p.Link.Scope + " " + p.Link.Type + " link"
+	}
+	return ""
+}
+
+// DiffReports compares an earlier audit with a later one, reporting newly
+// added external users and anonymous links, and permissions that were
+// removed. Permissions are matched by file path and permission ID.
+func DiffReports(old, new *ACLReport) *ACLDiff {
+	type key struct{ path, id string }
+	oldPerms := map[key]bool{}
+	oldExternal := map[string]map[string]bool{}
+	for _, e := range old.Entries {
+		for _, p := range e.Permissions {
+			oldPerms[key{e.Path, p.ID}] = true
+		}
+		oldExternal[e.Path] = map[string]bool{}
+		for _, u := range e.ExternalUsers {
+			oldExternal[e.Path][u] = true
+		}
+	}
+
+	diff := &ACLDiff{}
+	newPerms := map[key]bool{}
+	for _, e := range new.Entries {
+		for _, u := range e.ExternalUsers {
+			if !oldExternal[e.Path][u] {
+				change := ACLChange{Path: e.Path, Grantee: u}
+				for _, p := range e.Permissions {
+					if p.GetEmail() == u {
+						change.Roles, change.PermissionID = p.Roles, p.ID
+					}
+				}
+				diff.NewExternalUsers = append(diff.NewExternalUsers, change)
+			}
+		}
+		for _, p := range e.Permissions {
+			newPerms[key{e.Path, p.ID}] = true
+			if p.IsAnonymousLink() && !oldPerms[key{e.Path, p.ID}] {
+				diff.NewAnonymousLinks = append(diff.NewAnonymousLinks, ACLChange{
+					Path: e.Path, Grantee: p.Grantee(), Roles: p.Roles, PermissionID: p.ID,
+				})
+			}
+		}
+	}
+
+	for _, e := range old.Entries {
+		for _, p := range e.Permissions {
+			if !newPerms[key{e.Path, p.ID}] {
+				diff.RemovedPermissions = append(diff.RemovedPermissions, ACLChange{
+					Path: e.Path, Grantee: p.Grantee(), Roles: p.Roles, PermissionID: p.ID,
+				})
+			}
+		}
+	}
+
+	for _, list := range [][]ACLChange{diff.NewExternalUsers, diff.NewAnonymousLinks, diff.RemovedPermissions} {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	}
+	return diff
+}
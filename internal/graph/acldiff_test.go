@@ -0,0 +1,43 @@
+package graph
+
+import "testing"
+
+func TestDiffReports(t *testing.T) {
+	user := func(email string) *Principal { return &Principal{User: &GraphUser{Email: email}} }
+	old := &ACLReport{Entries: []ACLEntry{
+		{Path: "a.docx", ExternalUsers: []string{"x@ext.com"}, Permissions: []Permission{
+			{ID: "1", Roles: []string{"read"}, GrantedToV2: user("x@ext.com")},
+			{ID: "2", Roles: []string{"write"}, GrantedToV2: user("bob@contoso.com")},
+		}},
+		{Path: "b.docx", Permissions: []Permission{
+			{ID: "3", Link: &PermLink{Scope: "anonymous", Type: "view"}},
+		}},
+	}}
+	new := &ACLReport{Entries: []ACLEntry{
+		{Path: "a.docx", ExternalUsers: []string{"x@ext.com", "y@ext.com"}, Permissions: []Permission{
+			{ID: "1", Roles: []string{"read"}, GrantedToV2: user("x@ext.com")},
+			{ID: "4", Roles: []string{"write"}, GrantedToV2: user("y@ext.com")},
+		}},
+		{Path: "b.docx", Permissions: []Permission{
+			{ID: "3", Link: &PermLink{Scope: "anonymous", Type: "view"}},
+			{ID: "5", Roles: []string{"read"}, Link: &PermLink{Scope: "anonymous", Type: "edit"}},
+		}},
+	}}
+
+	d := DiffReports(old, new)
+	if len(d.NewExternalUsers) != 1 || d.NewExternalUsers[0].Grantee != "y@ext.com" || d.NewExternalUsers[0].Roles[0] != "write" {
+		t.Errorf("new external users = %+v", d.NewExternalUsers)
+	}
+	if len(d.NewAnonymousLinks) != 1 || d.NewAnonymousLinks[0].PermissionID != "5" || d.NewAnonymousLinks[0].Grantee != "anonymous edit link" {
+		t.Errorf("new anonymous links = %+v", d.NewAnonymousLinks)
+	}
+	if len(d.RemovedPermissions) != 1 || d.RemovedPermissions[0].Grantee != "bob@contoso.com" {
+		t.Errorf("removed permissions = %+v", d.RemovedPermissions)
+	}
+	if d.Empty() {
+		t.Error("expected a non-empty diff")
+	}
+	if !DiffReports(new, new).Empty() {
+		t.Error("expected no changes between identical reports")
+	}
+}
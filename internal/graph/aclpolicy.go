@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy check names.
+const (
+	CheckLinkExpiration = "link-without-expiration"
+	CheckAnonymousEdit  = "anonymous-edit-link"
+	CheckExternalWrite  = "external-write"
+)
+
+// severities in increasing order.
+var severities = []string{"low", "medium", "high", "critical"}
+
+func severityRank(s string) int {
+	for i, v := range severities {
+		if strings.EqualFold(v, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+// PolicyCheck configures one check in an ACL policy.
+type PolicyCheck struct {
+	Enabled  *bool  `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Severity string `yaml:"severity" json:"severity"`
+	// Scopes limits link-without-expiration to these link scopes.
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	// MaxDays also flags links that expire more than this many days out.
+	MaxDays int `yaml:"max_days,omitempty" json:"max_days,omitempty"`
+	// AllowDomains exempts partner domains from external-write.
+	AllowDomains []string `yaml:"allow_domains,omitempty" json:"allow_domains,omitempty"`
+}
+
+func (c PolicyCheck) enabled() bool { return c.Enabled == nil || *c.Enabled }
+
+// ACLPolicy is the set of sharing rules an audit is evaluated against,
+// usually read from acl-policy.yaml.
+type ACLPolicy struct {
+	// FailOn is the lowest severity that counts as a failure.
+	FailOn string `yaml:"fail_on" json:"fail_on"`
+	// IgnorePaths are glob patterns for top-level files and folders to
+	// skip, matched against the item name.
+	IgnorePaths []string               `yaml:"ignore_paths,omitempty" json:"ignore_paths,omitempty"`
+	Checks      map[string]PolicyCheck `yaml:"checks" json:"checks"`
+}
+
+// PolicyViolation is one permission that breaks a policy check.
+type PolicyViolation struct {
+	Check        string `json:"check"`
+	Severity     string `json:"severity"`
+	Path         string `json:"path"`
+	PermissionID string `json:"permissionId"`
+	Grantee      string `json:"grantee"`
+	Message      string `json:"message"`
+}
+
+// DefaultACLPolicy returns the built-in policy used when no policy file
+// exists.
+func DefaultACLPolicy() *ACLPolicy {
+	return &ACLPolicy{
+		FailOn: "high",
+		Checks: map[string]PolicyCheck{
+			CheckLinkExpiration: {Severity: "medium", Scopes: []string{"anonymous", "users"}},
+			CheckAnonymousEdit:  {Severity: "high"},
+			CheckExternalWrite:  {Severity: "high"},
+		},
+	}
+}
+
+// LoadACLPolicy reads a policy file. Checks the file leaves out keep
+// their defaults.
+func LoadACLPolicy(path string) (*ACLPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read policy %s: %w", path, err)
+	}
+	var file ACLPolicy
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+
+	policy := DefaultACLPolicy()
+	if file.FailOn != "" {
+		policy.FailOn = file.FailOn
+	}
+	policy.IgnorePaths = file.IgnorePaths
+	for name, check := range file.Checks {
+		def, ok := policy.Checks[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid policy %s: unknown check %q", path, name)
+		}
+		if check.Severity == "" {
+			check.Severity = def.Severity
+		}
+		if check.Scopes == nil {
+			check.Scopes = def.Scopes
+		}
+		policy.Checks[name] = check
+	}
+
+	if severityRank(policy.FailOn) < 0 {
+		return nil, fmt.Errorf("invalid policy %s: fail_on %q — use %s", path, policy.FailOn, strings.Join(severities, ", "))
+	}
+	for name, check := range policy.Checks {
+		if severityRank(check.Severity) < 0 {
+			return nil, fmt.Errorf("invalid policy %s: %s severity %q — use %s", path, name, check.Severity, strings.Join(severities, ", "))
+		}
+	}
+	return policy, nil
+}
+
+// Evaluate checks every permission in a report against the policy and
+// returns the violations, most severe first.
+func (pol *ACLPolicy) Evaluate(report *ACLReport, now time.Time) []PolicyViolation {
+	var out []PolicyViolation
+	for _, entry := range report.Entries {
+		if pol.ignored(entry.Path) {
+			continue
+		}
+		external := map[string]bool{}
+		for _, u := range entry.ExternalUsers {
+			external[strings.ToLower(u)] = true
+		}
+		for _, p := range entry.Permissions {
+			flag := func(check, msg string) {
+				out = append(out, PolicyViolation{
+					Check:        check,
+					Severity:     strings.ToLower(pol.Checks[check].Severity),
+					Path:         entry.Path,
+					PermissionID: p.ID,
+					Grantee:      p.Grantee(),
+					Message:      msg,
+				})
+			}
+
+			if c, ok := pol.Checks[CheckLinkExpiration]; ok && c.enabled() && p.Link != nil && containsFold(c.Scopes, p.Link.Scope) {
+				switch {
+				case p.ExpiresAt == nil:
+					flag(CheckLinkExpiration, p.Link.Scope+" link never expires")
+				case c.MaxDays > 0 && p.ExpiresAt.After(now.AddDate(0, 0, c.MaxDays)):
+					flag(CheckLinkExpiration, fmt.Sprintf("%s link expires %s, more than %d days out",
+						p.Link.Scope, p.ExpiresAt.Format("2006-01-02"), c.MaxDays))
+				}
+			}
+
+			if c, ok := pol.Checks[CheckAnonymousEdit]; ok && c.enabled() && p.IsAnonymousLink() &&
+				(p.Link.Type == "edit" || hasWriteRole(p.Roles)) {
+				flag(CheckAnonymousEdit, "anyone with the link can edit")
+			}
+
+			if c, ok := pol.Checks[CheckExternalWrite]; ok && c.enabled() && hasWriteRole(p.Roles) {
+				email := p.GetEmail()
+				if external[strings.ToLower(email)] && !containsFold(c.AllowDomains, emailDomain(email)) {
+					flag(CheckExternalWrite, "external user "+email+" can edit")
+				}
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return severityRank(out[i].Severity) > severityRank(out[j].Severity)
+	})
+	return out
+}
+
+// Failures returns the violations at or above the policy's fail_on
+// severity.
+func (pol *ACLPolicy) Failures(violations []PolicyViolation) []PolicyViolation {
+	min := severityRank(pol.FailOn)
+	var out []PolicyViolation
+	for _, v := range violations {
+		if severityRank(v.Severity) >= min {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (pol *ACLPolicy) ignored(p string) bool {
+	for _, pattern := range pol.IgnorePaths {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasWriteRole(roles []string) bool {
+	for _, r := range roles {
+		if r == "write" || r == "owner" {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func emailDomain(email string) string {
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		return email[i+1:]
+	}
+	return ""
+}
+
+// ACLPolicyTemplate is a commented acl-policy.yaml with the default
+// settings.
+const ACLPolicyTemplate = `# kit acl policy — evaluated by 'kit acl audit --policy acl-policy.yaml'.
+# Severities: low, medium, high, critical.
+
+# The audit exits non-zero when any violation is at or above this severity.
+fail_on: high
+
+# Files and folders to skip (glob patterns, matched against the item name).
+# The audit covers the top level of the library, so skipping a folder
+# skips the permissions set on the folder itself.
+ignore_paths: []
+#  - "Public"
+#  - "*.tmp"
+
+checks:
+  # Sharing links with no expiration date.
+  link-without-expiration:
+    severity: medium
+    scopes: [anonymous, users]
+    # max_days: 90   # also flag links that expire more than 90 days out
+
+  # Anonymous ("anyone with the link") links that allow editing.
+  anonymous-edit-link:
+    severity: high
+
+  # External users with write or owner roles.
+  external-write:
+    severity: high
+    allow_domains: []
+    # - partner.com
+`
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func policyReport(expires time.Time) *ACLReport {
+	user := func(email string) *Principal { return &Principal{User: &GraphUser{Email: email}} }
+	return &ACLReport{Entries: []ACLEntry{
+		{Path: "a.docx", ExternalUsers: []string{"x@ext.com", "p@partner.com"}, Permissions: []Permission{
+			{ID: "1", Roles: []string{"write"}, GrantedToV2: user("x@ext.com")},
+			{ID: "2", Roles: []string{"write"}, GrantedToV2: user("p@partner.com")},
+			{ID: "3", Roles: []string{"read"}, GrantedToV2: user("bob@contoso.com")},
+		}},
+		{Path: "b.docx", Permissions: []Permission{
+			{ID: "4", Roles: []string{"write"}, Link: &PermLink{Scope: "anonymous", Type: "edit"}},
+			{ID: "5", Roles: []string{"read"}, Link: &PermLink{Scope: "users", Type: "view"}, ExpiresAt: &expires},
+			{ID: "6", Roles: []string{"read"}, Link: &PermLink{Scope: "organization", Type: "view"}},
+		}},
+		{Path: "Public", IsFolder: true, Permissions: []Permission{
+			{ID: "7", Roles: []string{"write"}, Link: &PermLink{Scope: "anonymous", Type: "edit"}},
+		}},
+	}}
+}
+
+func TestPolicyEvaluateDefaults(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	got := DefaultACLPolicy().Evaluate(policyReport(now.AddDate(1, 0, 0)), now)
+
+	count := map[string]int{}
+	for _, v := range got {
+		count[v.Check]++
+	}
+	// link-without-expiration: links 4 and 7; anonymous-edit: 4 and 7;
+	// external-write: 1 and 2.
+	if count[CheckLinkExpiration] != 2 || count[CheckAnonymousEdit] != 2 || count[CheckExternalWrite] != 2 {
+		t.Errorf("violations = %+v", got)
+	}
+	if got[0].Severity != "high" || got[len(got)-1].Severity != "medium" {
+		t.Errorf("expected most severe first, got %+v", got)
+	}
+}
+
+func TestLoadACLPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acl-policy.yaml")
+	os.WriteFile(path, []byte(`fail_on: critical
+ignore_paths: ["Pub*"]
+checks:
+  link-without-expiration:
+    max_days: 90
+  anonymous-edit-link:
+    severity: critical
+  external-write:
+    allow_domains: [partner.com]
+`), 0644)
+
+	pol, err := LoadACLPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	got := pol.Evaluate(policyReport(now.AddDate(1, 0, 0)), now)
+
+	var ids []string
+	for _, v := range got {
+		ids = append(ids, v.Check+":"+v.PermissionID)
+	}
+	want := []string{"anonymous-edit-link:4", "external-write:1", "link-without-expiration:4", "link-without-expiration:5"}
+	if len(ids) != len(want) {
+		t.Fatalf("violations = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("violation %d = %s, want %s", i, ids[i], want[i])
+		}
+	}
+	if f := pol.Failures(got); len(f) != 1 || f[0].Check != CheckAnonymousEdit {
+		t.Errorf("failures = %+v", f)
+	}
+}
+
+func TestLoadACLPolicyInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"unknown-check.yaml": "checks:\n  no-such-check:\n    severity: high\n",
+		"bad-severity.yaml":  "checks:\n  external-write:\n    severity: urgent\n",
+		"bad-fail-on.yaml":   "fail_on: never\n",
+	} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(body), 0644)
+		if _, err := LoadACLPolicy(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestACLPolicyTemplateLoads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acl-policy.yaml")
+	os.WriteFile(path, []byte(ACLPolicyTemplate), 0644)
+	if _, err := LoadACLPolicy(path); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,144 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PermissionChange is one planned change to an item's permissions.
+type PermissionChange struct {
+	PermissionID string   `json:"permissionId"`
+	Action       string   `json:"action"` // "delete" or "revokeGrant"
+	Description  string   `json:"description"`
+	Roles        []string `json:"roles,omitempty"`
+	// Grantees are the emails removed from a shared link ("revokeGrant").
+	Grantees []string `json:"grantees,omitempty"`
+}
+
+// PlanRevoke returns the changes that remove user's access to an item:
+// direct grants are deleted and the user is removed from sharing links.
+// Inherited permissions cannot be changed on the item and are counted
+// separately so callers can point at the parent instead.
+func PlanRevoke(perms []Permission, user string) (changes []PermissionChange, inherited int) {
+	for _, p := range perms {
+		direct := strings.EqualFold(p.GetEmail(), user)
+		var viaLink bool
+		for _, id := range p.GrantedToIdentitiesV2 {
+			if id.User != nil && strings.EqualFold(id.User.Email, user) {
+				viaLink = true
+			}
+		}
+		if !direct && !viaLink {
+			continue
+		}
+		if p.IsInherited() {
+			inherited++
+			continue
+		}
+
+		switch {
+		case direct:
+			changes = append(changes, PermissionChange{
+				PermissionID: p.ID,
+				Action:       "delete",
+				Description:  "direct access for " + user,
+				Roles:        p.Roles,
+			})
+		case len(p.GrantedToIdentitiesV2) == 1:
+			// The user is the link's only recipient, so remove the link.
+			changes = append(changes, PermissionChange{
+				PermissionID: p.ID,
+				Action:       "delete",
+				Description:  describeLink(p) + " shared only with " + user,
+				Roles:        p.Roles,
+			})
+		default:
+			changes = append(changes, PermissionChange{
+				PermissionID: p.ID,
+				Action:       "revokeGrant",
+				Description:  "remove " + user + " from " + describeLink(p),
+				Roles:        p.Roles,
+				Grantees:     []string{user},
+			})
+		}
+	}
+	return changes, inherited
+}
+
+// PlanRemoveLinks returns the changes that delete an item's sharing links.
+// scope ("anonymous", "organization", "users") and linkID narrow the
+// selection when set; inherited links are counted but not included.
+func PlanRemoveLinks(perms []Permission, scope, linkID string) (changes []PermissionChange, inherited int) {
+	for _, p := range perms {
+		if p.Link == nil {
+			continue
+		}
+		if scope != "" && !strings.EqualFold(p.Link.Scope, scope) {
+			continue
+		}
+		if linkID != "" && p.ID != linkID {
+			continue
+		}
+		if p.IsInherited() {
+			inherited++
+			continue
+		}
+		changes = append(changes, PermissionChange{
+			PermissionID: p.ID,
+			Action:       "delete",
+			Description:  describeLink(p),
+			Roles:        p.Roles,
+		})
+	}
+	return changes, inherited
+}
+
+func describeLink(p Permission) string {
+	return p.Link.Scope + " " + p.Link.Type + " link"
+}
+
+// ApplyPermissionChange performs a planned change on an item.
+func (a *ACL) ApplyPermissionChange(ctx context.Context, siteID, driveID, itemID string, c PermissionChange) error {
+	endpoint := graphBase() + "/sites/" + siteID + "/drives/" + driveID + "/items/" + url.PathEscape(itemID) +
+		"/permissions/" + url.PathEscape(c.PermissionID)
+
+	var req *http.Request
+	var err error
+	switch c.Action {
+	case "delete":
+		req, err = http.NewRequestWithContext(ctx, "DELETE", endpoint, nil)
+	case "revokeGrant":
+		grantees := make([]map[string]string, 0, len(c.Grantees))
+		for _, email := range c.Grantees {
+			grantees = append(grantees, map[string]string{"email": email})
+		}
+		payload, _ := json.Marshal(map[string]any{"grantees": grantees})
+		req, err = http.NewRequestWithContext(ctx, "POST", endpoint+"/revokeGrants", bytes.NewReader(payload))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	default:
+		return fmt.Errorf("unknown permission change %q", c.Action)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("permission change request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s failed (HTTP %d): %s", c.Description, resp.StatusCode, string(body))
+	}
+	return nil
+}
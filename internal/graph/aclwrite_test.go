@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func aclTestPermissions() []Permission {
+	user := func(email string) *Principal { return &Principal{User: &GraphUser{Email: email}} }
+	inherited := &struct {
+		ID string `json:"id"`
+	}{ID: "parent"}
+	return []Permission{
+		{ID: "p1", Roles: []string{"write"}, GrantedToV2: user("ext@gmail.com")},
+		{ID: "p2", Roles: []string{"read"}, Link: &PermLink{Scope: "users", Type: "view"},
+			GrantedToIdentitiesV2: []Principal{*user("EXT@gmail.com"), *user("bob@contoso.com")}},
+		{ID: "p3", Roles: []string{"read"}, Link: &PermLink{Scope: "users", Type: "edit"},
+			GrantedToIdentitiesV2: []Principal{*user("ext@gmail.com")}},
+		{ID: "p4", Roles: []string{"read"}, GrantedToV2: user("ext@gmail.com"), InheritedFrom: inherited},
+		{ID: "p5", Roles: []string{"read"}, Link: &PermLink{Scope: "anonymous", Type: "view"}},
+		{ID: "p6", Roles: []string{"read"}, Link: &PermLink{Scope: "anonymous", Type: "view"}, InheritedFrom: inherited},
+	}
+}
+
+func TestPlanRevoke(t *testing.T) {
+	changes, inherited := PlanRevoke(aclTestPermissions(), "ext@gmail.com")
+	if inherited != 1 {
+		t.Errorf("inherited = %d, want 1", inherited)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if changes[0].PermissionID != "p1" || changes[0].Action != "delete" {
+		t.Errorf("direct grant change = %+v", changes[0])
+	}
+	if changes[1].PermissionID != "p2" || changes[1].Action != "revokeGrant" || changes[1].Grantees[0] != "ext@gmail.com" {
+		t.Errorf("shared link change = %+v", changes[1])
+	}
+	if changes[2].PermissionID != "p3" || changes[2].Action != "delete" {
+		t.Errorf("single-recipient link change = %+v", changes[2])
+	}
+
+	if changes, _ := PlanRevoke(aclTestPermissions(), "nobody@x.com"); len(changes) != 0 {
+		t.Errorf("expected no changes for unknown user, got %+v", changes)
+	}
+}
+
+func TestPlanRemoveLinks(t *testing.T) {
+	changes, inherited := PlanRemoveLinks(aclTestPermissions(), "anonymous", "")
+	if len(changes) != 1 || changes[0].PermissionID != "p5" || inherited != 1 {
+		t.Errorf("anonymous links = %+v (inherited %d)", changes, inherited)
+	}
+
+	changes, _ = PlanRemoveLinks(aclTestPermissions(), "", "")
+	if len(changes) != 3 {
+		t.Errorf("expected 3 removable links, got %+v", changes)
+	}
+
+	changes, _ = PlanRemoveLinks(aclTestPermissions(), "", "p3")
+	if len(changes) != 1 || changes[0].Description != "users edit link" {
+		t.Errorf("link by ID = %+v", changes)
+	}
+}
+
+func TestApplyPermissionChange(t *testing.T) {
+	var calls []string
+	var grantees []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == "POST" {
+			var body struct {
+				Grantees []struct {
+					Email string `json:"email"`
+				} `json:"grantees"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			for _, g := range body.Grantees {
+				grantees = append(grantees, g.Email)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"id": "p2"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	a := NewACL(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}, "")
+	ctx := context.Background()
+	if err := a.ApplyPermissionChange(ctx, "s", "d", "i", PermissionChange{PermissionID: "p1", Action: "delete"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.ApplyPermissionChange(ctx, "s", "d", "i", PermissionChange{PermissionID: "p2", Action: "revokeGrant", Grantees: []string{"ext@gmail.com"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"DELETE /v1.0/sites/s/drives/d/items/i/permissions/p1",
+		"POST /v1.0/sites/s/drives/d/items/i/permissions/p2/revokeGrants",
+	}
+	if len(calls) != 2 || calls[0] != want[0] || calls[1] != want[1] {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if len(grantees) != 1 || grantees[0] != "ext@gmail.com" {
+		t.Errorf("grantees = %v", grantees)
+	}
+}
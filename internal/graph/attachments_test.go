@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreamAttachment(t *testing.T) {
+	content := strings.Repeat("x", 100_000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/me/messages/m1/attachments/a1/$value" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	obs := &recordingObserver{}
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}, Progress: obs}
+	var buf bytes.Buffer
+	n, err := o.StreamAttachment(context.Background(), "m1", Attachment{ID: "a1", Name: "big.zip", Size: 100_000}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(content)) || buf.String() != content {
+		t.Errorf("wrote %d bytes", n)
+	}
+	if obs.total != int64(len(content)) || obs.added != n || !obs.finished {
+		t.Errorf("progress = %+v", obs)
+	}
+}
+
+func TestStreamAttachmentFallsBackToJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$value") {
+			http.Error(w, "not supported", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(Attachment{ID: "a1", Name: "a.txt", ContentBytes: base64.StdEncoding.EncodeToString([]byte("hello"))})
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	var buf bytes.Buffer
+	if _, err := o.StreamAttachment(context.Background(), "m1", Attachment{ID: "a1", Name: "a.txt"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "hello" {
+		t.Errorf("content = %q", buf.String())
+	}
+}
+
+func TestStreamReferenceAttachment(t *testing.T) {
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: "http://127.0.0.1:0", wrapped: http.DefaultTransport}}}
+	att := Attachment{ODataType: ReferenceAttachmentType, ID: "a1", Name: "Budget.xlsx"}
+	_, err := o.StreamAttachment(context.Background(), "m1", att, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "link to a cloud file") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestAttachmentFileName(t *testing.T) {
+	tests := []struct {
+		att  Attachment
+		want string
+	}{
+		{Attachment{Name: "report.pdf"}, "report.pdf"},
+		{Attachment{Name: `..\..\evil.exe`}, "evil.exe"},
+		{Attachment{Name: "../x.docx"}, "x.docx"},
+		{Attachment{Name: ""}, "attachment"},
+		{Attachment{ODataType: ItemAttachmentType, Name: "Re: Budget"}, "Re: Budget.eml"},
+	}
+	for _, tt := range tests {
+		if got := AttachmentFileName(tt.att); got != tt.want {
+			t.Errorf("AttachmentFileName(%q) = %q, want %q", tt.att.Name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxBatchSize is the most requests Graph accepts in one $batch call.
+const maxBatchSize = 20
+
+// maxBatchRetries is how many times throttled requests inside a batch are
+// resent.
+const maxBatchRetries = 3
+
+// BatchRequest is one request in a JSON $batch call. URL is relative to the
+// API version, e.g. "/me/messages/{id}".
+type BatchRequest struct {
+	ID      string            `json:"id"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    any               `json:"body,omitempty"`
+}
+
+// BatchResponse is the answer to one BatchRequest.
+type BatchResponse struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// OK reports whether the request succeeded.
+func (r BatchResponse) OK() bool {
+	return r.Status >= 200 && r.Status <= 299
+}
+
+// Err describes a failed request, or returns nil if it succeeded.
+func (r BatchResponse) Err() error {
+	if r.OK() {
+		return nil
+	}
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(r.Body, &body) == nil && body.Error.Message != "" {
+		return fmt.Errorf("%d: %s", r.Status, body.Error.Message)
+	}
+	return fmt.Errorf("%d: %s", r.Status, string(r.Body))
+}
+
+// Batch sends requests through Graph's $batch endpoint, maxBatchSize at a
+// time, and returns the responses in request order. Requests that are
+// throttled inside a batch are resent after the wait Graph asks for. The
+// error covers only the batch calls themselves; check each response for
+// per-request failures.
+func Batch(ctx context.Context, client *http.Client, requests []BatchRequest) ([]BatchResponse, error) {
+	for i := range requests {
+		if requests[i].ID == "" {
+			requests[i].ID = strconv.Itoa(i + 1)
+		}
+		if requests[i].Body != nil && requests[i].Headers == nil {
+			requests[i].Headers = map[string]string{"Content-Type": "application/json"}
+		}
+	}
+
+	responses := make([]BatchResponse, len(requests))
+	for start := 0; start < len(requests); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(requests))
+		if err := sendBatch(ctx, client, requests[start:end], responses[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+// sendBatch sends one chunk of requests, filling out with the matching
+// responses and retrying throttled ones.
+func sendBatch(ctx context.Context, client *http.Client, requests []BatchRequest, out []BatchResponse) error {
+	index := make(map[string]int, len(requests))
+	for i, r := range requests {
+		index[r.ID] = i
+	}
+
+	pending := requests
+	for attempt := 0; ; attempt++ {
+		result, err := postBatch(ctx, client, pending)
+		if err != nil {
+			return err
+		}
+
+		var retry []BatchRequest
+		var wait time.Duration
+		for _, resp := range result {
+			i, ok := index[resp.ID]
+			if !ok {
+				continue
+			}
+			out[i] = resp
+			if retryable(requests[i].Method, resp.Status) && attempt < maxBatchRetries {
+				retry = append(retry, requests[i])
+				wait = max(wait, retryAfter(resp.Headers["Retry-After"], attempt))
+			}
+		}
+		if len(retry) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		pending = retry
+	}
+}
+
+func postBatch(ctx context.Context, client *http.Client, requests []BatchRequest) ([]BatchResponse, error) {
+	data, err := json.Marshal(map[string]any{"requests": requests})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", graphBase()+"/$batch", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not send batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("batch failed (%d): %s", resp.StatusCode, string(body))
+	}
+	var result struct {
+		Responses []BatchResponse `json:"responses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("could not parse batch response: %w", err)
+	}
+	return result.Responses, nil
+}
@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	var calls, sizes []int
+	throttled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/$batch" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body struct {
+			Requests []BatchRequest `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		calls = append(calls, len(calls)+1)
+		sizes = append(sizes, len(body.Requests))
+
+		var responses []BatchResponse
+		// Answer in reverse order, as Graph may.
+		for i := len(body.Requests) - 1; i >= 0; i-- {
+			req := body.Requests[i]
+			if req.Headers["Content-Type"] != "application/json" {
+				t.Errorf("request %s without Content-Type", req.ID)
+			}
+			resp := BatchResponse{ID: req.ID, Status: 200, Body: json.RawMessage(fmt.Sprintf(`{"url":%q}`, req.URL))}
+			if req.ID == "3" && !throttled {
+				throttled = true
+				resp = BatchResponse{ID: req.ID, Status: 429, Headers: map[string]string{"Retry-After": "0"}}
+			}
+			responses = append(responses, resp)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"responses": responses})
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}
+	requests := make([]BatchRequest, 25)
+	for i := range requests {
+		requests[i] = BatchRequest{Method: "PATCH", URL: fmt.Sprintf("/me/messages/m%d", i), Body: map[string]any{"isRead": true}}
+	}
+
+	responses, err := Batch(context.Background(), client, requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20 + the throttled request resent + 5
+	if fmt.Sprint(sizes) != "[20 1 5]" {
+		t.Errorf("batch sizes = %v", sizes)
+	}
+	for i, resp := range responses {
+		var body struct{ URL string }
+		json.Unmarshal(resp.Body, &body)
+		if !resp.OK() || body.URL != fmt.Sprintf("/me/messages/m%d", i) {
+			t.Errorf("response %d = %d %s", i, resp.Status, resp.Body)
+		}
+	}
+}
+
+func TestBatchResponseErr(t *testing.T) {
+	resp := BatchResponse{Status: 404, Body: json.RawMessage(`{"error":{"code":"ErrorItemNotFound","message":"The specified object was not found in the store."}}`)}
+	if err := resp.Err(); err == nil || err.Error() != "404: The specified object was not found in the store." {
+		t.Errorf("Err() = %v", err)
+	}
+	if err := (BatchResponse{Status: 204}).Err(); err != nil {
+		t.Errorf("Err() for 204 = %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Event is a calendar event on the signed-in user's calendar.
+type Event struct {
+	ID        string          `json:"id"`
+	Subject   string          `json:"subject"`
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	IsAllDay  bool            `json:"isAllDay,omitempty"`
+	Location  string          `json:"location,omitempty"`
+	Organizer string          `json:"organizer,omitempty"`
+	Attendees []EventAttendee `json:"attendees,omitempty"`
+	// Response is the signed-in user's response: organizer, accepted,
+	// tentativelyAccepted, declined, or notResponded.
+	Response   string `json:"response,omitempty"`
+	ShowAs     string `json:"showAs,omitempty"`
+	IsOnline   bool   `json:"isOnlineMeeting,omitempty"`
+	JoinURL    string `json:"joinUrl,omitempty"`
+	WebLink    string `json:"webLink,omitempty"`
+	IsCanceled bool   `json:"isCancelled,omitempty"`
+}
+
+// EventAttendee is an invited attendee and their response.
+type EventAttendee struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email"`
+	Type     string `json:"type,omitempty"`
+	Response string `json:"response,omitempty"`
+}
+
+// EventRequest describes an event to create.
+type EventRequest struct {
+	Subject   string
+	Start     time.Time
+	Duration  time.Duration
+	Attendees []string // email addresses
+	Body      string   // optional, plain text
+	Location  string
+	// ShowAs is free, tentative, busy, oof, or workingElsewhere (default busy).
+	ShowAs string
+	// Online adds a Teams meeting to the event.
+	Online bool
+}
+
+// Event responses accepted by RespondToEvent.
+const (
+	ResponseAccept    = "accept"
+	ResponseTentative = "tentativelyAccept"
+	ResponseDecline   = "decline"
+)
+
+// Calendar provides Outlook calendar operations via Graph API.
+type Calendar struct {
+	Client *http.Client
+}
+
+// NewCalendar creates a new Calendar client.
+func NewCalendar(client *http.Client) *Calendar {
+	return &Calendar{Client: client}
+}
+
+// graphEvent is an event as Graph returns it.
+type graphEvent struct {
+	ID       string           `json:"id"`
+	Subject  string           `json:"subject"`
+	Start    dateTimeTimeZone `json:"start"`
+	End      dateTimeTimeZone `json:"end"`
+	IsAllDay bool             `json:"isAllDay"`
+	Location struct {
+		DisplayName string `json:"displayName"`
+	} `json:"location"`
+	Organizer      EmailRecipient `json:"organizer"`
+	ResponseStatus struct {
+		Response string `json:"response"`
+	} `json:"responseStatus"`
+	Attendees []struct {
+		EmailAddress EmailAddr `json:"emailAddress"`
+		Type         string    `json:"type"`
+		Status       struct {
+			Response string `json:"response"`
+		} `json:"status"`
+	} `json:"attendees"`
+	ShowAs          string `json:"showAs"`
+	IsOnlineMeeting bool   `json:"isOnlineMeeting"`
+	OnlineMeeting   *struct {
+		JoinURL string `json:"joinUrl"`
+	} `json:"onlineMeeting"`
+	WebLink     string `json:"webLink"`
+	IsCancelled bool   `json:"isCancelled"`
+}
+
+func (g graphEvent) event() Event {
+	e := Event{
+		ID:         g.ID,
+		Subject:    g.Subject,
+		Start:      parseDateTimeTimeZone(g.Start),
+		End:        parseDateTimeTimeZone(g.End),
+		IsAllDay:   g.IsAllDay,
+		Location:   g.Location.DisplayName,
+		Organizer:  g.Organizer.EmailAddress.Address,
+		Response:   g.ResponseStatus.Response,
+		ShowAs:     g.ShowAs,
+		IsOnline:   g.IsOnlineMeeting,
+		WebLink:    g.WebLink,
+		IsCanceled: g.IsCancelled,
+	}
+	if g.OnlineMeeting != nil {
+		e.JoinURL = g.OnlineMeeting.JoinURL
+	}
+	for _, a := range g.Attendees {
+		e.Attendees = append(e.Attendees, EventAttendee{
+			Name:     a.EmailAddress.Name,
+			Email:    a.EmailAddress.Address,
+			Type:     a.Type,
+			Response: a.Status.Response,
+		})
+	}
+	return e
+}
+
+// utcHeader asks calendar endpoints to return times in UTC rather than the
+// mailbox's time zone.
+func utcHeader() http.Header {
+	h := http.Header{}
+	h.Set("Prefer", `outlook.timezone="UTC"`)
+	return h
+}
+
+// ListEvents returns the events between start and end, with recurring
+// events expanded into their occurrences, earliest first. limit <= 0 means
+// no cap.
+func (c *Calendar) ListEvents(ctx context.Context, start, end time.Time, limit int) ([]Event, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("calendar range end must be after its start")
+	}
+	params := url.Values{}
+	params.Set("startDateTime", start.UTC().Format(time.RFC3339))
+	params.Set("endDateTime", end.UTC().Format(time.RFC3339))
+	params.Set("$orderby", "start/dateTime")
+	params.Set("$top", fmt.Sprintf("%d", maxPageSize))
+	endpoint := graphBase() + "/me/calendarView?" + params.Encode()
+
+	var events []Event
+	err := forEach(ctx, c.Client, endpoint, "list events", utcHeader(), func(g graphEvent) error {
+		events = append(events, g.event())
+		if limit > 0 && len(events) >= limit {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetEvent retrieves a single event by ID.
+func (c *Calendar) GetEvent(ctx context.Context, id string) (*Event, error) {
+	var g graphEvent
+	if _, err := doJSON(ctx, c.Client, "GET", graphBase()+"/me/events/"+url.PathEscape(id), "get event", utcHeader(), nil, &g); err != nil {
+		return nil, err
+	}
+	e := g.event()
+	return &e, nil
+}
+
+// CreateEvent adds an event to the signed-in user's calendar and sends
+// invitations to any attendees.
+func (c *Calendar) CreateEvent(ctx context.Context, r EventRequest) (*Event, error) {
+	if r.Subject == "" {
+		return nil, fmt.Errorf("event subject is required")
+	}
+	if r.Duration <= 0 {
+		r.Duration = 30 * time.Minute
+	}
+
+	attendees := make([]map[string]any, 0, len(r.Attendees))
+	for _, email := range r.Attendees {
+		attendees = append(attendees, map[string]any{
+			"emailAddress": map[string]string{"address": email},
+			"type":         "required",
+		})
+	}
+	payload := map[string]any{
+		"subject":   r.Subject,
+		"start":     toDateTimeTimeZone(r.Start),
+		"end":       toDateTimeTimeZone(r.Start.Add(r.Duration)),
+		"attendees": attendees,
+	}
+	if r.Body != "" {
+		payload["body"] = map[string]string{"contentType": "text", "content": r.Body}
+	}
+	if r.Location != "" {
+		payload["location"] = map[string]string{"displayName": r.Location}
+	}
+	if r.ShowAs != "" {
+		payload["showAs"] = r.ShowAs
+	}
+	if r.Online {
+		payload["isOnlineMeeting"] = true
+		payload["onlineMeetingProvider"] = "teamsForBusiness"
+	}
+
+	var g graphEvent
+	if _, err := doJSON(ctx, c.Client, "POST", graphBase()+"/me/events", "create event", utcHeader(), payload, &g); err != nil {
+		return nil, err
+	}
+	e := g.event()
+	return &e, nil
+}
+
+// RespondToEvent accepts, tentatively accepts, or declines an invitation.
+// With notify set the organizer is sent the response and comment.
+func (c *Calendar) RespondToEvent(ctx context.Context, id, response, comment string, notify bool) error {
+	switch response {
+	case ResponseAccept, ResponseTentative, ResponseDecline:
+	default:
+		return fmt.Errorf("unknown event response %q", response)
+	}
+	payload := map[string]any{"sendResponse": notify}
+	if comment != "" {
+		payload["comment"] = comment
+	}
+	endpoint := graphBase() + "/me/events/" + url.PathEscape(id) + "/" + response
+	_, err := doJSON(ctx, c.Client, "POST", endpoint, strings.ToLower(response)+" event", utcHeader(), payload, nil)
+	return err
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/me/calendarView" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("startDateTime") != "2026-10-19T00:00:00Z" || q.Get("endDateTime") != "2026-10-26T00:00:00Z" {
+			t.Errorf("range = %s – %s", q.Get("startDateTime"), q.Get("endDateTime"))
+		}
+		if r.Header.Get("Prefer") != `outlook.timezone="UTC"` {
+			t.Errorf("Prefer = %q", r.Header.Get("Prefer"))
+		}
+		w.Write([]byte(`{"value":[{"id":"e1","subject":"Standup",
+			"start":{"dateTime":"2026-10-19T09:00:00.0000000","timeZone":"UTC"},
+			"end":{"dateTime":"2026-10-19T09:15:00.0000000","timeZone":"UTC"},
+			"location":{"displayName":"Room 4"},
+			"organizer":{"emailAddress":{"address":"alice@contoso.com"}},
+			"responseStatus":{"response":"accepted"},
+			"attendees":[{"emailAddress":{"name":"Bob","address":"bob@contoso.com"},"type":"required","status":{"response":"declined"}}],
+			"isOnlineMeeting":true,"onlineMeeting":{"joinUrl":"https://teams.microsoft.com/l/meetup-join/x"}}]}`))
+	}))
+	defer server.Close()
+
+	c := NewCalendar(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	from := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	events, err := c.ListEvents(context.Background(), from, from.AddDate(0, 0, 7), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events", len(events))
+	}
+	e := events[0]
+	if !e.Start.Equal(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)) || e.End.Sub(e.Start) != 15*time.Minute {
+		t.Errorf("times = %s – %s", e.Start, e.End)
+	}
+	if e.Location != "Room 4" || e.Organizer != "alice@contoso.com" || e.Response != "accepted" || e.JoinURL == "" {
+		t.Errorf("event = %+v", e)
+	}
+	if len(e.Attendees) != 1 || e.Attendees[0].Response != "declined" {
+		t.Errorf("attendees = %+v", e.Attendees)
+	}
+
+	if _, err := c.ListEvents(context.Background(), from, from, 0); err == nil {
+		t.Error("expected error for empty range")
+	}
+}
+
+func TestCreateEvent(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"e2","subject":"Review contract",
+			"start":{"dateTime":"2026-10-20T13:00:00.0000000","timeZone":"UTC"},
+			"end":{"dateTime":"2026-10-20T14:00:00.0000000","timeZone":"UTC"}}`))
+	}))
+	defer server.Close()
+
+	c := NewCalendar(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	e, err := c.CreateEvent(context.Background(), EventRequest{
+		Subject:  "Review contract",
+		Start:    time.Date(2026, 10, 20, 13, 0, 0, 0, time.UTC),
+		Duration: time.Hour,
+		Location: "Desk",
+		ShowAs:   "busy",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "e2" || e.End.Sub(e.Start) != time.Hour {
+		t.Errorf("event = %+v", e)
+	}
+	if _, online := got["isOnlineMeeting"]; online {
+		t.Error("plain event should not request a Teams meeting")
+	}
+	if got["showAs"] != "busy" || got["location"].(map[string]any)["displayName"] != "Desk" {
+		t.Errorf("payload = %v", got)
+	}
+}
+
+func TestRespondToEvent(t *testing.T) {
+	var gotPath string
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	c := NewCalendar(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	if err := c.RespondToEvent(context.Background(), "e1", ResponseDecline, "Out that day", true); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/v1.0/me/events/e1/decline" || got["comment"] != "Out that day" || got["sendResponse"] != true {
+		t.Errorf("request %s %v", gotPath, got)
+	}
+	if err := c.RespondToEvent(context.Background(), "e1", "maybe", "", false); err == nil {
+		t.Error("expected error for unknown response")
+	}
+}
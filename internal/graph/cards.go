@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const adaptiveCardType = "application/vnd.microsoft.card.adaptive"
+
+// CardFact is one name/value row in a card's fact set.
+type CardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// CardButton opens a URL when clicked.
+type CardButton struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// CardSpec is the input to the built-in card templates.
+type CardSpec struct {
+	Title   string
+	Text    string
+	Status  string // status template: success, warning, failure, or info
+	Facts   []CardFact
+	Buttons []CardButton
+}
+
+// statusStyles maps a status to its badge text color and label.
+var statusStyles = map[string][2]string{
+	"success": {"Good", "✅ Success"},
+	"warning": {"Warning", "⚠️ Warning"},
+	"failure": {"Attention", "❌ Failure"},
+	"info":    {"Accent", "ℹ️ Info"},
+}
+
+var cardTemplates = map[string]func(CardSpec) (map[string]any, error){
+	"basic":  basicCard,
+	"status": statusCard,
+}
+
+// CardTemplates returns the names of the built-in card templates.
+func CardTemplates() []string {
+	names := make([]string, 0, len(cardTemplates))
+	for name := range cardTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildCard renders a built-in template as an Adaptive Card.
+func BuildCard(template string, spec CardSpec) (json.RawMessage, error) {
+	build, ok := cardTemplates[template]
+	if !ok {
+		return nil, fmt.Errorf("unknown card template %q — available: %v", template, CardTemplates())
+	}
+	card, err := build(spec)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(card)
+}
+
+func basicCard(spec CardSpec) (map[string]any, error) {
+	if spec.Title == "" {
+		return nil, fmt.Errorf("card needs a title")
+	}
+	body := []map[string]any{{
+		"type": "TextBlock", "text": spec.Title, "size": "Large", "weight": "Bolder", "wrap": true,
+	}}
+	return newCard(append(body, cardDetails(spec)...), spec.Buttons), nil
+}
+
+func statusCard(spec CardSpec) (map[string]any, error) {
+	if spec.Title == "" {
+		return nil, fmt.Errorf("card needs a title")
+	}
+	style, ok := statusStyles[spec.Status]
+	if !ok {
+		return nil, fmt.Errorf("invalid status %q — use success, warning, failure, or info", spec.Status)
+	}
+	body := []map[string]any{
+		{"type": "TextBlock", "text": style[1], "color": style[0], "weight": "Bolder", "spacing": "None"},
+		{"type": "TextBlock", "text": spec.Title, "size": "Large", "weight": "Bolder", "wrap": true, "spacing": "Small"},
+	}
+	return newCard(append(body, cardDetails(spec)...), spec.Buttons), nil
+}
+
+func cardDetails(spec CardSpec) []map[string]any {
+	var body []map[string]any
+	if spec.Text != "" {
+		body = append(body, map[string]any{"type": "TextBlock", "text": spec.Text, "wrap": true})
+	}
+	if len(spec.Facts) > 0 {
+		body = append(body, map[string]any{"type": "FactSet", "facts": spec.Facts})
+	}
+	return body
+}
+
+func newCard(body []map[string]any, buttons []CardButton) map[string]any {
+	card := map[string]any{
+		"type":    "AdaptiveCard",
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"version": "1.4",
+		"body":    body,
+	}
+	if len(buttons) > 0 {
+		actions := make([]map[string]any, 0, len(buttons))
+		for _, b := range buttons {
+			actions = append(actions, map[string]any{"type": "Action.OpenUrl", "title": b.Title, "url": b.URL})
+		}
+		card["actions"] = actions
+	}
+	return card
+}
+
+// ParseCard validates an Adaptive Card read from a file. Besides a bare
+// card it accepts a message in the incoming-webhook format and takes the
+// first Adaptive Card attachment from it.
+func ParseCard(data []byte) (json.RawMessage, error) {
+	var probe struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string          `json:"contentType"`
+			Content     json.RawMessage `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("card is not valid JSON: %w", err)
+	}
+	if probe.Type == "AdaptiveCard" {
+		return json.RawMessage(data), nil
+	}
+	for _, a := range probe.Attachments {
+		if a.ContentType == adaptiveCardType {
+			return ParseCard(a.Content)
+		}
+	}
+	return nil, fmt.Errorf(`card must have "type": "AdaptiveCard"`)
+}
+
+// cardMessage builds a chat message that carries an Adaptive Card, with
+// optional text above it.
+func cardMessage(text string, card json.RawMessage) map[string]any {
+	id := strconv.FormatInt(time.Now().UnixNano(), 36)
+	content := `<attachment id="` + id + `"></attachment>`
+	if text != "" {
+		content = html.EscapeString(text) + "<br>" + content
+	}
+	return map[string]any{
+		"body": map[string]string{
+			"contentType": "html",
+			"content":     content,
+		},
+		"attachments": []map[string]any{{
+			"id":          id,
+			"contentType": adaptiveCardType,
+			"content":     string(card),
+		}},
+	}
+}
+
+// PostCard posts an Adaptive Card to a channel.
+func (t *Teams) PostCard(ctx context.Context, teamID, channelID, text string, card json.RawMessage) (*ChatMessage, error) {
+	endpoint := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "/messages"
+	return t.sendMessage(ctx, endpoint, cardMessage(text, card), "post card")
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuildStatusCard(t *testing.T) {
+	data, err := BuildCard("status", CardSpec{
+		Title:   "Nightly build",
+		Status:  "failure",
+		Facts:   []CardFact{{Title: "Branch", Value: "main"}},
+		Buttons: []CardButton{{Title: "View run", URL: "https://ci.example.com/1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var card struct {
+		Type string `json:"type"`
+		Body []struct {
+			Type  string     `json:"type"`
+			Text  string     `json:"text"`
+			Color string     `json:"color"`
+			Facts []CardFact `json:"facts"`
+		} `json:"body"`
+		Actions []struct {
+			Type string `json:"type"`
+			URL  string `json:"url"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal(data, &card); err != nil {
+		t.Fatal(err)
+	}
+	if card.Type != "AdaptiveCard" || len(card.Body) != 3 {
+		t.Fatalf("card = %s", data)
+	}
+	if card.Body[0].Color != "Attention" || card.Body[1].Text != "Nightly build" {
+		t.Errorf("header = %+v", card.Body[:2])
+	}
+	if card.Body[2].Type != "FactSet" || card.Body[2].Facts[0].Value != "main" {
+		t.Errorf("facts = %+v", card.Body[2])
+	}
+	if len(card.Actions) != 1 || card.Actions[0].Type != "Action.OpenUrl" {
+		t.Errorf("actions = %+v", card.Actions)
+	}
+}
+
+func TestBuildCardErrors(t *testing.T) {
+	if _, err := BuildCard("nope", CardSpec{Title: "x"}); err == nil {
+		t.Error("expected error for unknown template")
+	}
+	if _, err := BuildCard("status", CardSpec{Title: "x", Status: "great"}); err == nil {
+		t.Error("expected error for unknown status")
+	}
+	if _, err := BuildCard("basic", CardSpec{}); err == nil {
+		t.Error("expected error for missing title")
+	}
+}
+
+func TestParseCard(t *testing.T) {
+	if _, err := ParseCard([]byte(`{"type":"AdaptiveCard","body":[]}`)); err != nil {
+		t.Errorf("bare card: %v", err)
+	}
+	webhook := `{"type":"message","attachments":[{"contentType":"application/vnd.microsoft.card.adaptive","content":{"type":"AdaptiveCard","version":"1.4"}}]}`
+	card, err := ParseCard([]byte(webhook))
+	if err != nil || !strings.Contains(string(card), `"version":"1.4"`) {
+		t.Errorf("webhook message: %s, %v", card, err)
+	}
+	if _, err := ParseCard([]byte(`{"title":"x"}`)); err == nil {
+		t.Error("expected error for non-card JSON")
+	}
+	if _, err := ParseCard([]byte(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestPostCard(t *testing.T) {
+	var sent struct {
+		Body        MessageBody `json:"body"`
+		Attachments []struct {
+			ID          string `json:"id"`
+			ContentType string `json:"contentType"`
+			Content     string `json:"content"`
+		} `json:"attachments"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"msg-1"}`))
+	}))
+	defer server.Close()
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	card := json.RawMessage(`{"type":"AdaptiveCard"}`)
+	if _, err := tc.PostCard(context.Background(), "t1", "c1", "Build <done>", card); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent.Attachments) != 1 || sent.Attachments[0].Content != `{"type":"AdaptiveCard"}` {
+		t.Fatalf("attachments = %+v", sent.Attachments)
+	}
+	a := sent.Attachments[0]
+	if a.ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Errorf("content type = %s", a.ContentType)
+	}
+	want := `Build &lt;done&gt;<br><attachment id="` + a.ID + `"></attachment>`
+	if sent.Body.ContentType != "html" || sent.Body.Content != want {
+		t.Errorf("body = %+v, want %s", sent.Body, want)
+	}
+}
@@ -0,0 +1,269 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/formats/convert"
+)
+
+// ChannelMessage is a channel message with its thread, as exported by
+// ExportChannel.
+type ChannelMessage struct {
+	ID          string              `json:"id"`
+	ReplyToID   string              `json:"replyToId,omitempty"`
+	MessageType string              `json:"messageType"`
+	CreatedAt   time.Time           `json:"createdDateTime"`
+	ModifiedAt  *time.Time          `json:"lastModifiedDateTime,omitempty"`
+	DeletedAt   *time.Time          `json:"deletedDateTime,omitempty"`
+	Subject     string              `json:"subject,omitempty"`
+	From        *MessageFrom        `json:"from,omitempty"`
+	Body        MessageBody         `json:"body"`
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	WebURL      string              `json:"webUrl,omitempty"`
+	Replies     []ChannelMessage    `json:"replies,omitempty"`
+	// HostedFiles maps the IDs of images and other hosted content in the
+	// message to the files they were saved as, relative to the export.
+	HostedFiles map[string]string `json:"hostedFiles,omitempty"`
+}
+
+// MessageFrom identifies who sent a message.
+type MessageFrom struct {
+	User        *GraphUser `json:"user,omitempty"`
+	Application *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"application,omitempty"`
+}
+
+// MessageAttachment is a file, card, or other attachment on a message.
+type MessageAttachment struct {
+	ID          string `json:"id"`
+	ContentType string `json:"contentType"`
+	ContentURL  string `json:"contentUrl,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// Sender returns the display name of the user or app that sent m.
+func (m ChannelMessage) Sender() string {
+	switch {
+	case m.From == nil:
+		return "Unknown"
+	case m.From.User != nil:
+		return m.From.User.DisplayName
+	case m.From.Application != nil:
+		return m.From.Application.DisplayName
+	}
+	return "Unknown"
+}
+
+// ExportOptions filters what ExportChannel saves.
+type ExportOptions struct {
+	Since, Until time.Time // zero means unbounded
+	// Dir, if set, is where hosted content (inline images) is saved, in a
+	// "hosted" subfolder.
+	Dir string
+}
+
+// ExportChannel reads the messages of a channel started within the date
+// range, each with its full reply thread, oldest first. With opts.Dir set,
+// inline images and other hosted content are downloaded too.
+func (t *Teams) ExportChannel(ctx context.Context, teamID, channelID string, opts ExportOptions) ([]ChannelMessage, error) {
+	base := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "/messages"
+
+	var messages []ChannelMessage
+	err := ForEach(ctx, t.Client, base+"?$top=50", "channel messages", func(m ChannelMessage) error {
+		if m.MessageType != "message" || !inRange(m.CreatedAt, opts.Since, opts.Until) {
+			return nil
+		}
+		messages = append(messages, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].CreatedAt.Before(messages[j].CreatedAt) })
+
+	for i := range messages {
+		m := &messages[i]
+		msgURL := base + "/" + m.ID
+		replies, err := GetAll[ChannelMessage](ctx, t.Client, msgURL+"/replies?$top=50", "message replies", 0)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(replies, func(i, j int) bool { return replies[i].CreatedAt.Before(replies[j].CreatedAt) })
+		m.Replies = replies
+
+		if opts.Dir == "" {
+			continue
+		}
+		if err := t.saveHostedContents(ctx, msgURL, opts.Dir, m); err != nil {
+			return nil, err
+		}
+		for j := range m.Replies {
+			r := &m.Replies[j]
+			if err := t.saveHostedContents(ctx, msgURL+"/replies/"+r.ID, opts.Dir, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return messages, nil
+}
+
+func inRange(t, since, until time.Time) bool {
+	return (since.IsZero() || !t.Before(since)) && (until.IsZero() || t.Before(until))
+}
+
+// saveHostedContents downloads the hosted content of the message at msgURL
+// into dir/hosted and records the files in m.HostedFiles.
+func (t *Teams) saveHostedContents(ctx context.Context, msgURL, dir string, m *ChannelMessage) error {
+	if !strings.Contains(m.Body.Content, "hostedContents") {
+		return nil // nothing inline; skip the extra request
+	}
+	type hosted struct {
+		ID string `json:"id"`
+	}
+	contents, err := GetAll[hosted](ctx, t.Client, msgURL+"/hostedContents", "hosted contents", 0)
+	if err != nil {
+		return err
+	}
+	if len(contents) > 0 {
+		if err := os.MkdirAll(filepath.Join(dir, "hosted"), 0755); err != nil {
+			return err
+		}
+	}
+	for n, hc := range contents {
+		rel, err := t.saveHostedContent(ctx, msgURL+"/hostedContents/"+hc.ID+"/$value", dir, fmt.Sprintf("%s-%d", m.ID, n+1))
+		if err != nil {
+			return err
+		}
+		if m.HostedFiles == nil {
+			m.HostedFiles = make(map[string]string)
+		}
+		m.HostedFiles[hc.ID] = rel
+	}
+	return nil
+}
+
+func (t *Teams) saveHostedContent(ctx context.Context, endpoint, dir, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download hosted content failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("download hosted content failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	rel := filepath.Join("hosted", name+contentExtension(resp.Header.Get("Content-Type")))
+	f, err := os.Create(filepath.Join(dir, rel))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return "", err
+	}
+	return filepath.ToSlash(rel), f.Close()
+}
+
+func contentExtension(contentType string) string {
+	switch strings.TrimSpace(strings.Split(contentType, ";")[0]) {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	}
+	return ".bin"
+}
+
+var hostedRef = regexp.MustCompile(`hostedContents/([^/]+)/\$value`)
+
+// messageMarkdown renders a message body as Markdown, pointing inline
+// images at their saved files.
+func messageMarkdown(m ChannelMessage) string {
+	if m.Body.ContentType != "html" {
+		return strings.TrimSpace(m.Body.Content)
+	}
+	return convert.HTMLToMarkdownWithImages(m.Body.Content, func(src string) string {
+		if ref := hostedRef.FindStringSubmatch(src); ref != nil {
+			return m.HostedFiles[ref[1]]
+		}
+		return src
+	})
+}
+
+// ChannelTranscript renders exported messages as a Markdown transcript,
+// grouped by day, with replies quoted under their thread.
+func ChannelTranscript(title string, messages []ChannelMessage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "_Exported %s · %d threads_\n", time.Now().Format("2006-01-02 15:04"), len(messages))
+
+	day := ""
+	for _, m := range messages {
+		if d := m.CreatedAt.Local().Format("Monday, 2006-01-02"); d != day {
+			day = d
+			fmt.Fprintf(&b, "\n## %s\n", day)
+		}
+		b.WriteString("\n")
+		writeTranscriptEntry(&b, m, "")
+		for _, r := range m.Replies {
+			b.WriteString(">\n")
+			writeTranscriptEntry(&b, r, "> ")
+		}
+	}
+	return b.String()
+}
+
+func writeTranscriptEntry(b *strings.Builder, m ChannelMessage, prefix string) {
+	header := fmt.Sprintf("**%s** · %s", m.Sender(), m.CreatedAt.Local().Format("15:04"))
+	if m.Subject != "" {
+		header += " · " + m.Subject
+	}
+	if m.ModifiedAt != nil && m.ModifiedAt.Sub(m.CreatedAt) > time.Minute {
+		header += " _(edited)_"
+	}
+	lines := []string{header}
+	if m.DeletedAt != nil {
+		lines = append(lines, "_This message was deleted._")
+	} else {
+		if text := messageMarkdown(m); text != "" {
+			lines = append(lines, strings.Split(text, "\n")...)
+		}
+		for _, a := range m.Attachments {
+			if a.ContentType == "reference" && a.ContentURL != "" {
+				lines = append(lines, fmt.Sprintf("📎 [%s](%s)", a.Name, a.ContentURL))
+			} else if strings.HasPrefix(a.ContentType, "application/vnd.microsoft.card") {
+				lines = append(lines, "_(card)_")
+			}
+		}
+	}
+	// Trailing double spaces keep the lines of a message from being joined
+	// into one Markdown paragraph.
+	for i, l := range lines {
+		l = strings.TrimRight(prefix+l, " ")
+		if i < len(lines)-1 && strings.TrimSpace(l) != strings.TrimSpace(prefix) {
+			l += "  "
+		}
+		b.WriteString(l + "\n")
+	}
+}
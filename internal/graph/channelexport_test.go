@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportChannel(t *testing.T) {
+	const base = "/v1.0/teams/team-1/channels/ch-1/messages"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case base:
+			w.Write([]byte(`{"value":[
+				{"id":"m2","messageType":"message","createdDateTime":"2026-10-02T10:00:00Z","from":{"user":{"displayName":"Bob"}},
+				 "body":{"contentType":"html","content":"<p>See <img src=\"https://graph.microsoft.com/v1.0/teams/team-1/channels/ch-1/messages/m2/hostedContents/hc1/$value\"></p>"}},
+				{"id":"m1","messageType":"message","createdDateTime":"2026-10-01T09:00:00Z","from":{"user":{"displayName":"Alice"}},
+				 "body":{"contentType":"text","content":"hello"}},
+				{"id":"sys","messageType":"systemEventMessage","createdDateTime":"2026-10-01T08:00:00Z","body":{"contentType":"html","content":""}},
+				{"id":"old","messageType":"message","createdDateTime":"2026-01-01T09:00:00Z","body":{"contentType":"text","content":"old"}}
+			]}`))
+		case base + "/m1/replies":
+			w.Write([]byte(`{"value":[
+				{"id":"r2","messageType":"message","createdDateTime":"2026-10-01T09:10:00Z","from":{"user":{"displayName":"Carol"}},"body":{"contentType":"text","content":"second"}},
+				{"id":"r1","messageType":"message","createdDateTime":"2026-10-01T09:05:00Z","from":{"user":{"displayName":"Bob"}},"body":{"contentType":"text","content":"first"}}
+			]}`))
+		case base + "/m2/replies":
+			w.Write([]byte(`{"value":[]}`))
+		case base + "/m2/hostedContents":
+			w.Write([]byte(`{"value":[{"id":"hc1"}]}`))
+		case base + "/m2/hostedContents/hc1/$value":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("PNGDATA"))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	messages, err := tc.ExportChannel(context.Background(), "team-1", "ch-1", ExportOptions{
+		Since: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		Dir:   dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 2 || messages[0].ID != "m1" || messages[1].ID != "m2" {
+		t.Fatalf("messages = %+v", messages)
+	}
+	if r := messages[0].Replies; len(r) != 2 || r[0].ID != "r1" {
+		t.Errorf("replies not in order: %+v", r)
+	}
+	file := messages[1].HostedFiles["hc1"]
+	if file != "hosted/m2-1.png" {
+		t.Fatalf("hosted file = %q", file)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, file)); string(data) != "PNGDATA" {
+		t.Errorf("hosted content = %q", data)
+	}
+
+	md := ChannelTranscript("#general", messages)
+	for _, want := range []string{"**Alice**", "> **Bob**", "> first", "See ![image](hosted/m2-1.png)"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("transcript missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestMessageMarkdownMentionsAndImages(t *testing.T) {
+	content := `<p>Hi <at id="0">Alice</at>, see <a href="https://example.com">the <b>doc</b></a> &amp; notes</p>` +
+		`<attachment id="x"></attachment>` +
+		`<p><img src="https://graph.microsoft.com/v1.0/teams/t/channels/c/messages/m1/hostedContents/h1/$value"></p>`
+	m := ChannelMessage{
+		Body:        MessageBody{ContentType: "html", Content: content},
+		HostedFiles: map[string]string{"h1": "hosted/m1-1.png"},
+	}
+	want := "Hi @Alice, see [the **doc**](https://example.com) & notes\n\n![image](hosted/m1-1.png)"
+	if got := messageMarkdown(m); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
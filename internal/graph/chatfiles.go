@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+)
+
+// chatFilesFolder is the OneDrive folder Teams keeps files shared in chats in.
+const chatFilesFolder = "Microsoft Teams Chat Files"
+
+// DirectChat returns the chat to message the given people in: the 1:1 chat
+// for one person, or for several an existing untitled group chat with
+// exactly those members, creating a new group chat only if there is none.
+func (t *Teams) DirectChat(ctx context.Context, emails []string) (*Chat, error) {
+	if len(emails) == 1 {
+		return t.createChat(ctx, "oneOnOne", "", emails)
+	}
+
+	chats, err := t.ListChats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range chats {
+		// Members include the signed-in user.
+		if c.ChatType == "group" && c.Topic == "" && len(c.Members) == len(emails)+1 && c.hasMembers(emails) {
+			return &chats[i], nil
+		}
+	}
+	return t.CreateGroupChat(ctx, "", emails)
+}
+
+// SendChatFile uploads a file to the signed-in user's OneDrive, in the
+// folder Teams uses for chat files, gives the recipients read access, and
+// posts it to the chat as a file attachment. Files over 4MB are sent
+// through an upload session.
+func (t *Teams) SendChatFile(ctx context.Context, chatID string, recipients []string, message, filePath string) (*ChatMessage, error) {
+	itemURL := graphBase() + "/me/drive/root:/" + url.PathEscape(chatFilesFolder) + "/" + url.PathEscape(filepath.Base(filePath))
+	item, err := uploadFile(ctx, t.Client, itemURL, filePath, t.Progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recipients) > 0 {
+		invitees := make([]map[string]string, len(recipients))
+		for i, e := range recipients {
+			invitees[i] = map[string]string{"email": e}
+		}
+		invite := map[string]any{
+			"recipients":     invitees,
+			"roles":          []string{"read"},
+			"requireSignIn":  true,
+			"sendInvitation": false,
+		}
+		if _, err := doJSON(ctx, t.Client, "POST", graphBase()+"/me/drive/items/"+url.PathEscape(item.ID)+"/invite", "share "+item.Name, nil, invite, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.sendMessage(ctx, graphBase()+"/chats/"+url.PathEscape(chatID)+"/messages", fileMessage(message, item), "send chat message")
+}
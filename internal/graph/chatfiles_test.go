@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirectChatReusesGroup(t *testing.T) {
+	created := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/me/chats":
+			w.Write([]byte(`{"value":[
+				{"id":"19:topic@thread.v2","topic":"Launch","chatType":"group","members":[
+					{"email":"me@contoso.com"},{"email":"alice@contoso.com"},{"email":"bob@contoso.com"}]},
+				{"id":"19:plain@thread.v2","topic":"","chatType":"group","members":[
+					{"email":"me@contoso.com"},{"email":"alice@contoso.com"},{"email":"bob@contoso.com"}]}]}`))
+		case "/v1.0/me":
+			w.Write([]byte(`{"id":"me-id"}`))
+		case "/v1.0/chats":
+			created = true
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"19:new@thread.v2","chatType":"group"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+
+	chat, err := tc.DirectChat(context.Background(), []string{"Bob@contoso.com", "alice@contoso.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.ID != "19:plain@thread.v2" || created {
+		t.Errorf("chat = %s, created = %v", chat.ID, created)
+	}
+
+	chat, err = tc.DirectChat(context.Background(), []string{"alice@contoso.com", "carol@contoso.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.ID != "19:new@thread.v2" || !created {
+		t.Errorf("expected a new group chat, got %s", chat.ID)
+	}
+}
+
+func TestSendChatFile(t *testing.T) {
+	var invite map[string]any
+	var message map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/v1.0/me/drive/root:/Microsoft Teams Chat Files/report.txt:/content":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"item-1","name":"report.txt","webUrl":"https://contoso-my.sharepoint.com/report.txt","eTag":"\"{ABC-123},1\""}`))
+		case r.URL.Path == "/v1.0/me/drive/items/item-1/invite":
+			json.NewDecoder(r.Body).Decode(&invite)
+			w.Write([]byte(`{"value":[]}`))
+		case r.URL.Path == "/v1.0/chats/19:c@thread.v2/messages":
+			json.NewDecoder(r.Body).Decode(&message)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"msg-1"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	os.WriteFile(path, []byte("numbers"), 0644)
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	msg, err := tc.SendChatFile(context.Background(), "19:c@thread.v2", []string{"alice@contoso.com"}, "Q3 numbers", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.ID != "msg-1" {
+		t.Errorf("msg = %+v", msg)
+	}
+	if invite["requireSignIn"] != true || len(invite["recipients"].([]any)) != 1 {
+		t.Errorf("invite = %v", invite)
+	}
+	att := message["attachments"].([]any)[0].(map[string]any)
+	if att["id"] != "ABC-123" || att["contentType"] != "reference" {
+		t.Errorf("attachment = %v", att)
+	}
+	if body := message["body"].(map[string]any)["content"].(string); !strings.Contains(body, "Q3 numbers") {
+		t.Errorf("body = %q", body)
+	}
+}
@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Chat is a 1:1, group, or meeting chat the signed-in user belongs to.
+type Chat struct {
+	ID          string       `json:"id"`
+	Topic       string       `json:"topic"`
+	ChatType    string       `json:"chatType"`
+	WebURL      string       `json:"webUrl,omitempty"`
+	LastUpdated time.Time    `json:"lastUpdatedDateTime"`
+	Members     []ChatMember `json:"members"`
+}
+
+// ChatMember is a participant in a chat.
+type ChatMember struct {
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+}
+
+// Name returns the chat topic, or for untitled chats the member names.
+func (c Chat) Name() string {
+	if c.Topic != "" {
+		return c.Topic
+	}
+	names := make([]string, 0, len(c.Members))
+	for _, m := range c.Members {
+		names = append(names, m.DisplayName)
+	}
+	return strings.Join(names, ", ")
+}
+
+// hasMembers reports whether every email is a member of the chat.
+func (c Chat) hasMembers(emails []string) bool {
+	for _, e := range emails {
+		found := false
+		for _, m := range c.Members {
+			if strings.EqualFold(m.Email, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ListChats returns the signed-in user's chats, most recently active first.
+func (t *Teams) ListChats(ctx context.Context) ([]Chat, error) {
+	endpoint := graphBase() + "/me/chats?$expand=members&$orderby=lastMessagePreview/createdDateTime%20desc"
+	return GetAll[Chat](ctx, t.Client, endpoint, "list chats", t.Limit)
+}
+
+// ResolveChat finds a chat by ID, by topic (exact, then partial match), or
+// by participants given as comma-separated email addresses. For
+// participants, the smallest chat that includes all of them wins.
+func (t *Teams) ResolveChat(ctx context.Context, ref string) (*Chat, error) {
+	if strings.HasPrefix(ref, "19:") {
+		return &Chat{ID: ref}, nil
+	}
+
+	chats, err := t.ListChats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(ref, "@") {
+		var emails []string
+		for _, e := range strings.Split(ref, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				emails = append(emails, e)
+			}
+		}
+		var matches []Chat
+		for _, c := range chats {
+			if c.hasMembers(emails) {
+				matches = append(matches, c)
+			}
+		}
+		if len(matches) > 0 {
+			sort.SliceStable(matches, func(i, j int) bool { return len(matches[i].Members) < len(matches[j].Members) })
+			return &matches[0], nil
+		}
+		return nil, fmt.Errorf("no chat with %s — start one with: kit teams chat create --member <email>", ref)
+	}
+
+	lower := strings.ToLower(ref)
+	for i := range chats {
+		if strings.ToLower(chats[i].Topic) == lower {
+			return &chats[i], nil
+		}
+	}
+	for i := range chats {
+		if chats[i].Topic != "" && strings.Contains(strings.ToLower(chats[i].Topic), lower) {
+			return &chats[i], nil
+		}
+	}
+	return nil, fmt.Errorf("chat %q not found — run: kit teams chat list", ref)
+}
+
+// CreateGroupChat starts a group chat between the signed-in user and the
+// given members.
+func (t *Teams) CreateGroupChat(ctx context.Context, topic string, emails []string) (*Chat, error) {
+	if len(emails) < 2 {
+		return nil, fmt.Errorf("a group chat needs at least two other members — use 'kit teams dm' for one person")
+	}
+	return t.createChat(ctx, "group", topic, emails)
+}
+
+// SendChatMessage posts a text message to an existing chat.
+func (t *Teams) SendChatMessage(ctx context.Context, chatID, text string) (*ChatMessage, error) {
+	return t.sendMessage(ctx, graphBase()+"/chats/"+url.PathEscape(chatID)+"/messages", textMessage(text), "send chat message")
+}
+
+// createChat creates a chat; Graph requires the signed-in user to be listed
+// as a member alongside the others.
+func (t *Teams) createChat(ctx context.Context, chatType, topic string, emails []string) (*Chat, error) {
+	var me struct {
+		ID string `json:"id"`
+	}
+	if err := t.get(ctx, graphBase()+"/me?$select=id", "get signed-in user", &me); err != nil {
+		return nil, err
+	}
+
+	member := func(user string) map[string]any {
+		return map[string]any{
+			"@odata.type":     "#microsoft.graph.aadUserConversationMember",
+			"roles":           []string{"owner"},
+			"user@odata.bind": graphBase() + "/users('" + url.PathEscape(user) + "')",
+		}
+	}
+	members := []map[string]any{member(me.ID)}
+	for _, e := range emails {
+		members = append(members, member(e))
+	}
+	payload := map[string]any{"chatType": chatType, "members": members}
+	if topic != "" {
+		payload["topic"] = topic
+	}
+	data, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", graphBase()+"/chats", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("create chat failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("create chat failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	var chat Chat
+	if err := json.Unmarshal(body, &chat); err != nil {
+		return nil, fmt.Errorf("could not parse chat: %w", err)
+	}
+	return &chat, nil
+}
@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testChats = `{"value":[
+	{"id":"19:war@thread.v2","topic":"Release war room","chatType":"group","members":[
+		{"displayName":"Me","email":"me@contoso.com"},{"displayName":"Alice","email":"alice@contoso.com"},{"displayName":"Bob","email":"bob@contoso.com"}]},
+	{"id":"19:big@thread.v2","topic":"All hands","chatType":"group","members":[
+		{"displayName":"Me","email":"me@contoso.com"},{"displayName":"Alice","email":"alice@contoso.com"},
+		{"displayName":"Bob","email":"bob@contoso.com"},{"displayName":"Carol","email":"carol@contoso.com"}]},
+	{"id":"19:alice@unq.gbl.spaces","topic":"","chatType":"oneOnOne","members":[
+		{"displayName":"Me","email":"me@contoso.com"},{"displayName":"Alice","email":"alice@contoso.com"}]}
+]}`
+
+func chatServer(t *testing.T, created *map[string]any) *Teams {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/me/chats":
+			w.Write([]byte(testChats))
+		case "/v1.0/me":
+			w.Write([]byte(`{"id":"me-id"}`))
+		case "/v1.0/chats":
+			json.NewDecoder(r.Body).Decode(created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"19:new@thread.v2","chatType":"group"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+}
+
+func TestResolveChat(t *testing.T) {
+	tc := chatServer(t, nil)
+	ctx := context.Background()
+
+	for ref, want := range map[string]string{
+		"release war room":                     "19:war@thread.v2",
+		"war":                                  "19:war@thread.v2",
+		"alice@contoso.com":                    "19:alice@unq.gbl.spaces",
+		"alice@contoso.com, carol@contoso.com": "19:big@thread.v2",
+		"19:abc@thread.v2":                     "19:abc@thread.v2",
+	} {
+		chat, err := tc.ResolveChat(ctx, ref)
+		if err != nil {
+			t.Errorf("%s: %v", ref, err)
+			continue
+		}
+		if chat.ID != want {
+			t.Errorf("%s resolved to %s, want %s", ref, chat.ID, want)
+		}
+	}
+
+	if _, err := tc.ResolveChat(ctx, "nonexistent"); err == nil {
+		t.Error("expected error for unknown topic")
+	}
+	if _, err := tc.ResolveChat(ctx, "dave@contoso.com"); err == nil {
+		t.Error("expected error for unknown participant")
+	}
+}
+
+func TestCreateGroupChat(t *testing.T) {
+	var created map[string]any
+	tc := chatServer(t, &created)
+
+	chat, err := tc.CreateGroupChat(context.Background(), "Launch", []string{"alice@contoso.com", "bob@contoso.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.ID != "19:new@thread.v2" {
+		t.Errorf("chat = %+v", chat)
+	}
+	if created["chatType"] != "group" || created["topic"] != "Launch" {
+		t.Errorf("payload = %v", created)
+	}
+	members := created["members"].([]any)
+	if len(members) != 3 {
+		t.Fatalf("members = %v", members)
+	}
+	if bind := members[0].(map[string]any)["user@odata.bind"]; bind != "https://graph.microsoft.com/v1.0/users('me-id')" {
+		t.Errorf("first member = %v, want the signed-in user", bind)
+	}
+
+	if _, err := tc.CreateGroupChat(context.Background(), "", []string{"alice@contoso.com"}); err == nil {
+		t.Error("expected error for a one-person group chat")
+	}
+}
+
+func TestChatName(t *testing.T) {
+	c := Chat{Members: []ChatMember{{DisplayName: "Me"}, {DisplayName: "Alice"}}}
+	if c.Name() != "Me, Alice" {
+		t.Errorf("Name() = %q", c.Name())
+	}
+	c.Topic = "Launch"
+	if c.Name() != "Launch" {
+		t.Errorf("Name() = %q", c.Name())
+	}
+}
@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// contentSearchPageSize is the number of hits requested per search page.
+const contentSearchPageSize = 50
+
+// ContentSearchOptions narrows a SharePoint content search. Filters are
+// added to the query as KQL, so they are applied by the search service.
+type ContentSearchOptions struct {
+	// FileTypes limits results to these extensions ("docx", ".pdf").
+	FileTypes []string
+	// ModifiedAfter and ModifiedBefore bound the last-modified date.
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+	// SiteURL limits results to one site (its web URL).
+	SiteURL string
+	// Limit caps the number of hits (0 = 25).
+	Limit int
+}
+
+// SearchHit is a ranked document returned by SearchContent.
+type SearchHit struct {
+	Rank           int       `json:"rank"`
+	Summary        string    `json:"summary"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	WebURL         string    `json:"webUrl"`
+	Size           int64     `json:"size"`
+	LastModifiedAt time.Time `json:"lastModifiedDateTime"`
+	ModifiedBy     string    `json:"modifiedBy"`
+	DriveID        string    `json:"driveId"`
+	SiteID         string    `json:"siteId"`
+}
+
+// searchHitJSON is the shape of a hit in the /search/query response.
+type searchHitJSON struct {
+	Rank     int    `json:"rank"`
+	Summary  string `json:"summary"`
+	Resource struct {
+		ID                   string    `json:"id"`
+		Name                 string    `json:"name"`
+		WebURL               string    `json:"webUrl"`
+		Size                 int64     `json:"size"`
+		LastModifiedDateTime time.Time `json:"lastModifiedDateTime"`
+		LastModifiedBy       struct {
+			User struct {
+				DisplayName string `json:"displayName"`
+			} `json:"user"`
+		} `json:"lastModifiedBy"`
+		ParentReference struct {
+			DriveID string `json:"driveId"`
+			SiteID  string `json:"siteId"`
+		} `json:"parentReference"`
+	} `json:"resource"`
+}
+
+// ContentQuery builds the KQL query string for a search.
+func ContentQuery(query string, opts ContentSearchOptions) string {
+	parts := []string{}
+	if q := strings.TrimSpace(query); q != "" {
+		parts = append(parts, q)
+	}
+
+	var types []string
+	for _, ext := range opts.FileTypes {
+		if ext = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(ext)), "."); ext != "" {
+			types = append(types, "filetype:"+ext)
+		}
+	}
+	if len(types) == 1 {
+		parts = append(parts, types[0])
+	} else if len(types) > 1 {
+		parts = append(parts, "("+strings.Join(types, " OR ")+")")
+	}
+
+	if !opts.ModifiedAfter.IsZero() {
+		parts = append(parts, "LastModifiedTime>="+opts.ModifiedAfter.UTC().Format("2006-01-02"))
+	}
+	if !opts.ModifiedBefore.IsZero() {
+		parts = append(parts, "LastModifiedTime<="+opts.ModifiedBefore.UTC().Format("2006-01-02"))
+	}
+	if opts.SiteURL != "" {
+		parts = append(parts, `path:"`+strings.TrimRight(opts.SiteURL, "/")+`"`)
+	}
+	return strings.Join(parts, " ")
+}
+
+// SearchContent finds documents across all SharePoint sites and OneDrives
+// the user can access, using the Microsoft Search API. Hits are returned in
+// relevance order.
+func (sp *SharePoint) SearchContent(ctx context.Context, query string, opts ContentSearchOptions) ([]SearchHit, error) {
+	kql := ContentQuery(query, opts)
+	if kql == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 25
+	}
+
+	var hits []SearchHit
+	for from := 0; len(hits) < limit; {
+		size := contentSearchPageSize
+		if limit-len(hits) < size {
+			size = limit - len(hits)
+		}
+		page, more, err := sp.searchPage(ctx, kql, from, size)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, page...)
+		if !more || len(page) == 0 {
+			break
+		}
+		from += len(page)
+	}
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func (sp *SharePoint) searchPage(ctx context.Context, kql string, from, size int) ([]SearchHit, bool, error) {
+	payload, _ := json.Marshal(map[string]any{
+		"requests": []map[string]any{{
+			"entityTypes": []string{"driveItem"},
+			"query":       map[string]string{"queryString": kql},
+			"from":        from,
+			"size":        size,
+		}},
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST", graphBase()+"/search/query", bytes.NewReader(payload))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := sp.Client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("search request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("search failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Value []struct {
+			HitsContainers []struct {
+				Hits                 []searchHitJSON `json:"hits"`
+				MoreResultsAvailable bool            `json:"moreResultsAvailable"`
+			} `json:"hitsContainers"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, false, fmt.Errorf("could not parse search response: %w", err)
+	}
+
+	var hits []SearchHit
+	more := false
+	for _, v := range result.Value {
+		for _, c := range v.HitsContainers {
+			more = more || c.MoreResultsAvailable
+			for _, h := range c.Hits {
+				r := h.Resource
+				hits = append(hits, SearchHit{
+					Rank:           h.Rank,
+					Summary:        h.Summary,
+					ID:             r.ID,
+					Name:           r.Name,
+					WebURL:         r.WebURL,
+					Size:           r.Size,
+					LastModifiedAt: r.LastModifiedDateTime,
+					ModifiedBy:     r.LastModifiedBy.User.DisplayName,
+					DriveID:        r.ParentReference.DriveID,
+					SiteID:         r.ParentReference.SiteID,
+				})
+			}
+		}
+	}
+	return hits, more, nil
+}
+
+// DownloadHit downloads a search hit to localPath.
+func (sp *SharePoint) DownloadHit(ctx context.Context, hit SearchHit, localPath string) (int64, error) {
+	if hit.DriveID == "" || hit.ID == "" {
+		return 0, fmt.Errorf("%s has no drive reference and cannot be downloaded", hit.Name)
+	}
+	item, err := NewDriveByID(sp.Client, hit.DriveID).GetItemByID(ctx, hit.ID)
+	if err != nil {
+		return 0, err
+	}
+	if item.DownloadURL == "" {
+		return 0, fmt.Errorf("no download URL available for %s", hit.Name)
+	}
+	return downloadItem(ctx, sp.Client, item, localPath, sp.Progress)
+}
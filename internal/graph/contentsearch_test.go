@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContentQuery(t *testing.T) {
+	after := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		query string
+		opts  ContentSearchOptions
+		want  string
+	}{
+		{"budget", ContentSearchOptions{}, "budget"},
+		{"budget", ContentSearchOptions{FileTypes: []string{".XLSX"}}, "budget filetype:xlsx"},
+		{"", ContentSearchOptions{FileTypes: []string{"docx", "pdf"}}, "(filetype:docx OR filetype:pdf)"},
+		{"plan", ContentSearchOptions{ModifiedAfter: after}, "plan LastModifiedTime>=2024-03-01"},
+		{"plan", ContentSearchOptions{SiteURL: "https://contoso.sharepoint.com/sites/mkt/"}, `plan path:"https://contoso.sharepoint.com/sites/mkt"`},
+	}
+	for _, tt := range tests {
+		if got := ContentQuery(tt.query, tt.opts); got != tt.want {
+			t.Errorf("ContentQuery(%q, %+v) = %q, want %q", tt.query, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestSearchContentPages(t *testing.T) {
+	var froms []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []struct {
+				Query struct {
+					QueryString string `json:"queryString"`
+				} `json:"query"`
+				From int `json:"from"`
+				Size int `json:"size"`
+			} `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		req := body.Requests[0]
+		froms = append(froms, req.From)
+		if req.Query.QueryString != "budget filetype:xlsx" {
+			t.Errorf("queryString = %q", req.Query.QueryString)
+		}
+
+		var hits []map[string]any
+		for i := 0; i < req.Size; i++ {
+			n := req.From + i + 1
+			hits = append(hits, map[string]any{
+				"rank": n,
+				"resource": map[string]any{
+					"id":              fmt.Sprintf("item-%d", n),
+					"name":            fmt.Sprintf("budget-%d.xlsx", n),
+					"parentReference": map[string]any{"driveId": "b!d", "siteId": "h,1,1"},
+				},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"value": []any{map[string]any{
+			"hitsContainers": []any{map[string]any{"hits": hits, "moreResultsAvailable": true}},
+		}}})
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	hits, err := sp.SearchContent(context.Background(), "budget", ContentSearchOptions{FileTypes: []string{"xlsx"}, Limit: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 60 {
+		t.Fatalf("expected 60 hits, got %d", len(hits))
+	}
+	if fmt.Sprint(froms) != "[0 50]" {
+		t.Errorf("page offsets = %v, want [0 50]", froms)
+	}
+	if hits[59].Rank != 60 || hits[0].DriveID != "b!d" || hits[0].Name != "budget-1.xlsx" {
+		t.Errorf("unexpected hits: first=%+v last=%+v", hits[0], hits[59])
+	}
+}
+
+func TestSearchContentEmptyQuery(t *testing.T) {
+	sp := &SharePoint{Client: http.DefaultClient}
+	if _, err := sp.SearchContent(context.Background(), " ", ContentSearchOptions{}); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
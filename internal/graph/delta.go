@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrDeltaExpired is returned by Delta when Graph no longer accepts the
+// delta link; start again with an empty link.
+var ErrDeltaExpired = errors.New("delta link expired")
+
+type deltaPage struct {
+	Value     []DriveItem `json:"value"`
+	NextLink  string      `json:"@odata.nextLink"`
+	DeltaLink string      `json:"@odata.deltaLink"`
+}
+
+// Delta returns the items created, changed, or deleted anywhere in the
+// drive since deltaLink, and the link to pass to the next call. With an
+// empty deltaLink it returns no items, only a link that tracks changes
+// from now on. Delta items carry ParentID but not ParentPath.
+func (o *OneDrive) Delta(ctx context.Context, deltaLink string) ([]DriveItem, string, error) {
+	endpoint := deltaLink
+	if endpoint == "" {
+		endpoint = graphBase() + o.drivePath() + "/root/delta?token=latest"
+	}
+
+	var items []DriveItem
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		resp, err := o.Client.Do(req)
+		if err != nil {
+			return nil, "", fmt.Errorf("OneDrive delta request failed: %w", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusGone {
+			return nil, "", ErrDeltaExpired
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("OneDrive delta request failed (HTTP %d): %s", resp.StatusCode, string(body))
+		}
+
+		var page deltaPage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, "", fmt.Errorf("could not parse delta response: %w", err)
+		}
+		items = append(items, page.Value...)
+		switch {
+		case page.NextLink != "":
+			endpoint = page.NextLink
+		case page.DeltaLink != "":
+			return items, page.DeltaLink, nil
+		default:
+			return nil, "", fmt.Errorf("delta response has no next or delta link")
+		}
+	}
+}
+
+// DrivePath returns the path of an item within its drive (e.g.
+// "/Incoming/report.xlsx"), from the parent path Graph reports for items
+// fetched directly. It is "/" for the root folder.
+func DrivePath(item DriveItem) string {
+	_, parent, ok := strings.Cut(item.ParentPath, "root:")
+	if !ok {
+		return "/" // the root has no parent
+	}
+	return strings.TrimSuffix("/"+strings.Trim(parent, "/"), "/") + "/" + item.Name
+}
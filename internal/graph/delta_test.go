@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDelta(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1.0/me/drive/root/delta" && r.URL.Query().Get("token") == "latest":
+			w.Write([]byte(`{"value": [], "@odata.deltaLink": "` + server.URL + `/v1.0/me/drive/root/delta?token=t1"}`))
+		case r.URL.Query().Get("token") == "t1":
+			w.Write([]byte(`{"value": [{"id": "f1", "name": "a.xlsx", "file": {}, "parentReference": {"id": "p1"}}],
+				"@odata.nextLink": "` + server.URL + `/v1.0/me/drive/root/delta?token=t1p2"}`))
+		case r.URL.Query().Get("token") == "t1p2":
+			w.Write([]byte(`{"value": [{"id": "f2", "name": "old.docx", "deleted": {"state": "deleted"}}],
+				"@odata.deltaLink": "` + server.URL + `/v1.0/me/drive/root/delta?token=t2"}`))
+		default:
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer server.Close()
+
+	od := NewOneDrive(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	ctx := context.Background()
+
+	items, link, err := od.Delta(ctx, "")
+	if err != nil || len(items) != 0 {
+		t.Fatalf("Delta start = %v, %v", items, err)
+	}
+	items, link, err = od.Delta(ctx, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].ParentID != "p1" || items[0].Deleted || !items[1].Deleted {
+		t.Errorf("items = %+v", items)
+	}
+	if _, _, err := od.Delta(ctx, link); !errors.Is(err, ErrDeltaExpired) {
+		t.Errorf("expired link: err = %v", err)
+	}
+}
+
+func TestDrivePath(t *testing.T) {
+	tests := []struct {
+		item DriveItem
+		want string
+	}{
+		{DriveItem{Name: "a.xlsx", ParentPath: "/drive/root:"}, "/a.xlsx"},
+		{DriveItem{Name: "Q1", ParentPath: "/drives/b!x/root:/Finance/Incoming"}, "/Finance/Incoming/Q1"},
+		{DriveItem{Name: "root"}, "/"},
+	}
+	for _, tt := range tests {
+		if got := DrivePath(tt.item); got != tt.want {
+			t.Errorf("DrivePath(%+v) = %q, want %q", tt.item, got, tt.want)
+		}
+	}
+}
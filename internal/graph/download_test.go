@@ -0,0 +1,216 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestQuickXorHashKnownValues(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "AAAAAAAAAAAAAAAAAAAAAAAAAAA="},
+		{"J", "SgAAAAAAAAAAAAAAAQAAAAAAAAA="},
+	}
+	for _, tt := range tests {
+		if got := QuickXorHashString([]byte(tt.in)); got != tt.want {
+			t.Errorf("QuickXorHashString(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuickXorHashChunkedWrites(t *testing.T) {
+	data := []byte(strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40))
+	want := QuickXorHashString(data)
+
+	h := NewQuickXorHash()
+	for i := 0; i < len(data); i += 37 {
+		end := i + 37
+		if end > len(data) {
+			end = len(data)
+		}
+		h.Write(data[i:end])
+	}
+	h2 := NewQuickXorHash()
+	h2.Write(data)
+	if string(h.Sum(nil)) != string(h2.Sum(nil)) {
+		t.Errorf("chunked hash differs from single write (want %s)", want)
+	}
+}
+
+func rangeServer(t *testing.T, content []byte, sawRange *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rng := r.Header.Get("Range")
+		if sawRange != nil {
+			*sawRange = rng
+		}
+		if rng == "" {
+			w.Write(content)
+			return
+		}
+		start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if err != nil || start > len(content) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(content[start:])
+	}))
+}
+
+func TestDownloadItemVerifiesQuickXor(t *testing.T) {
+	content := []byte("quarterly numbers, final version")
+	server := rangeServer(t, content, nil)
+	defer server.Close()
+
+	item := &DriveItem{
+		Name:         "q1.xlsx",
+		Size:         int64(len(content)),
+		DownloadURL:  server.URL + "/download",
+		QuickXorHash: QuickXorHashString(content),
+	}
+
+	localPath := filepath.Join(t.TempDir(), "q1.xlsx")
+	n, err := downloadItem(context.Background(), server.Client(), item, localPath, nil)
+	if err != nil {
+		t.Fatalf("downloadItem failed: %v", err)
+	}
+	if n != int64(len(content)) {
+		t.Errorf("n = %d, want %d", n, len(content))
+	}
+	data, _ := os.ReadFile(localPath)
+	if string(data) != string(content) {
+		t.Errorf("content = %q", data)
+	}
+	if _, err := os.Stat(localPath + partialSuffix); !os.IsNotExist(err) {
+		t.Error("expected .partial file to be removed")
+	}
+}
+
+func TestDownloadItemResumesPartial(t *testing.T) {
+	content := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	var sawRange string
+	server := rangeServer(t, content, &sawRange)
+	defer server.Close()
+
+	sum := sha256.Sum256(content)
+	item := &DriveItem{
+		Name:        "data.bin",
+		Size:        int64(len(content)),
+		DownloadURL: server.URL + "/download",
+		SHA256Hash:  strings.ToUpper(hex.EncodeToString(sum[:])),
+	}
+
+	localPath := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(localPath+partialSuffix, content[:10], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := downloadItem(context.Background(), server.Client(), item, localPath, nil); err != nil {
+		t.Fatalf("downloadItem failed: %v", err)
+	}
+	if sawRange != "bytes=10-" {
+		t.Errorf("Range header = %q, want bytes=10-", sawRange)
+	}
+	data, _ := os.ReadFile(localPath)
+	if string(data) != string(content) {
+		t.Errorf("content = %q", data)
+	}
+}
+
+func TestDownloadItemIntegrityFailure(t *testing.T) {
+	content := []byte("tampered content")
+	server := rangeServer(t, content, nil)
+	defer server.Close()
+
+	item := &DriveItem{
+		Name:         "doc.docx",
+		Size:         int64(len(content)),
+		DownloadURL:  server.URL + "/download",
+		QuickXorHash: QuickXorHashString([]byte("original content")),
+	}
+
+	localPath := filepath.Join(t.TempDir(), "doc.docx")
+	_, err := downloadItem(context.Background(), server.Client(), item, localPath, nil)
+	if err == nil {
+		t.Fatal("expected integrity error")
+	}
+	if !containsStr(err.Error(), "integrity check failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(localPath); !os.IsNotExist(err) {
+		t.Error("corrupt file should not be moved into place")
+	}
+	if _, err := os.Stat(localPath + partialSuffix); !os.IsNotExist(err) {
+		t.Error("corrupt .partial file should be removed")
+	}
+}
+
+func TestVerifyFileNoHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.txt")
+	os.WriteFile(path, []byte("x"), 0644)
+	if err := VerifyFile(path, &DriveItem{Name: "f.txt"}); err != nil {
+		t.Errorf("expected no error without hash, got %v", err)
+	}
+}
+
+func TestDriveItemUnmarshalHashes(t *testing.T) {
+	raw := `{"id": "1", "name": "a.docx", "file": {"mimeType": "x", "hashes": {"quickXorHash": "abc=", "sha256Hash": "DEF"}}}`
+	var item DriveItem
+	if err := item.UnmarshalJSON([]byte(raw)); err != nil {
+		t.Fatal(err)
+	}
+	if item.QuickXorHash != "abc=" || item.SHA256Hash != "DEF" {
+		t.Errorf("hashes = %q, %q", item.QuickXorHash, item.SHA256Hash)
+	}
+}
+
+type recordingObserver struct {
+	total, done, added int64
+	finished           bool
+}
+
+func (r *recordingObserver) Begin(total, done int64) { r.total, r.done = total, done }
+func (r *recordingObserver) Add(n int64)             { r.added += n }
+func (r *recordingObserver) Done()                   { r.finished = true }
+
+func TestDownloadItemReportsProgress(t *testing.T) {
+	content := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	server := rangeServer(t, content, nil)
+	defer server.Close()
+
+	item := &DriveItem{
+		Name:        "data.bin",
+		Size:        int64(len(content)),
+		DownloadURL: server.URL + "/download",
+	}
+
+	localPath := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(localPath+partialSuffix, content[:10], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	obs := &recordingObserver{}
+	if _, err := downloadItem(context.Background(), server.Client(), item, localPath, obs); err != nil {
+		t.Fatalf("downloadItem failed: %v", err)
+	}
+	if obs.total != int64(len(content)) || obs.done != 10 {
+		t.Errorf("Begin(%d, %d), want Begin(%d, 10)", obs.total, obs.done, len(content))
+	}
+	if obs.added != int64(len(content))-10 {
+		t.Errorf("observed %d bytes, want %d", obs.added, len(content)-10)
+	}
+	if !obs.finished {
+		t.Error("expected Done to be called")
+	}
+}
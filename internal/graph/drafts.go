@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// DraftRequest describes the content of a draft message. Empty fields are
+// left unchanged when updating a draft.
+type DraftRequest struct {
+	To      []string
+	Cc      []string
+	Bcc     []string
+	Subject string
+	Body    string
+	// HTML marks Body as HTML rather than plain text.
+	HTML bool
+}
+
+func recipientList(emails []string) []EmailRecipient {
+	list := make([]EmailRecipient, 0, len(emails))
+	for _, e := range emails {
+		list = append(list, EmailRecipient{EmailAddress: EmailAddr{Address: e}})
+	}
+	return list
+}
+
+func (d DraftRequest) payload() map[string]any {
+	p := map[string]any{}
+	if d.Subject != "" {
+		p["subject"] = d.Subject
+	}
+	if d.Body != "" {
+		contentType := "text"
+		if d.HTML {
+			contentType = "html"
+		}
+		p["body"] = EmailBody{ContentType: contentType, Content: d.Body}
+	}
+	if len(d.To) > 0 {
+		p["toRecipients"] = recipientList(d.To)
+	}
+	if len(d.Cc) > 0 {
+		p["ccRecipients"] = recipientList(d.Cc)
+	}
+	if len(d.Bcc) > 0 {
+		p["bccRecipients"] = recipientList(d.Bcc)
+	}
+	return p
+}
+
+// CreateDraft saves a new message in the Drafts folder without sending it.
+func (o *Outlook) CreateDraft(ctx context.Context, d DraftRequest) (*EmailMessage, error) {
+	var msg EmailMessage
+	if _, err := doJSON(ctx, o.Client, "POST", o.base()+"/messages", "create draft", nil, d.payload(), &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// CreateReplyDraft saves a reply (or reply-all) to a message in the Drafts
+// folder. The draft is addressed and quotes the original as Outlook would;
+// d.Body goes above the quote, and any recipients in d are added.
+func (o *Outlook) CreateReplyDraft(ctx context.Context, messageID string, d DraftRequest, replyAll bool) (*EmailMessage, error) {
+	action := "createReply"
+	if replyAll {
+		action = "createReplyAll"
+	}
+	var draft EmailMessage
+	endpoint := o.base() + "/messages/" + url.PathEscape(messageID) + "/" + action
+	if _, err := doJSON(ctx, o.Client, "POST", endpoint, "create reply draft", nil, map[string]any{}, &draft); err != nil {
+		return nil, err
+	}
+
+	update := DraftRequest{Subject: d.Subject, Cc: d.Cc, Bcc: d.Bcc}
+	if len(d.To) > 0 {
+		update.To = append(emailAddresses(draft.To), d.To...)
+	}
+	if d.Body != "" {
+		body := d.Body
+		if !d.HTML {
+			body = strings.ReplaceAll(html.EscapeString(body), "\n", "<br>\n")
+		}
+		quoted := draft.Body.Content
+		if draft.Body.ContentType != "html" {
+			quoted = strings.ReplaceAll(html.EscapeString(quoted), "\n", "<br>\n")
+		}
+		update.Body = body + "<br>\n" + quoted
+		update.HTML = true
+	}
+	if len(update.payload()) == 0 {
+		return &draft, nil
+	}
+	return o.UpdateDraft(ctx, draft.ID, update)
+}
+
+func emailAddresses(recipients []EmailRecipient) []string {
+	out := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		out = append(out, r.EmailAddress.Address)
+	}
+	return out
+}
+
+// UpdateDraft changes an existing draft. Only the fields set in d are
+// updated; recipient lists replace the draft's existing ones.
+func (o *Outlook) UpdateDraft(ctx context.Context, draftID string, d DraftRequest) (*EmailMessage, error) {
+	payload := d.payload()
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("nothing to update in draft")
+	}
+	var msg EmailMessage
+	if _, err := doJSON(ctx, o.Client, "PATCH", o.base()+"/messages/"+url.PathEscape(draftID), "update draft", nil, payload, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// SendDraft sends a saved draft. The message moves to Sent Items.
+func (o *Outlook) SendDraft(ctx context.Context, draftID string) error {
+	_, err := doJSON(ctx, o.Client, "POST", o.base()+"/messages/"+url.PathEscape(draftID)+"/send", "send draft", nil, nil, nil)
+	return err
+}
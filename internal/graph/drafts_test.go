@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateDraft(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1.0/me/messages" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"d1","subject":"Renewal"}`))
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	msg, err := o.CreateDraft(context.Background(), DraftRequest{
+		To:      []string{"alice@contoso.com"},
+		Subject: "Renewal",
+		Body:    "<p>Hi</p>",
+		HTML:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.ID != "d1" {
+		t.Errorf("ID = %s", msg.ID)
+	}
+	body := got["body"].(map[string]any)
+	if body["contentType"] != "html" || body["content"] != "<p>Hi</p>" {
+		t.Errorf("body = %v", body)
+	}
+	if to := got["toRecipients"].([]any); len(to) != 1 {
+		t.Errorf("toRecipients = %v", to)
+	}
+	if _, ok := got["ccRecipients"]; ok {
+		t.Error("empty cc should be omitted")
+	}
+}
+
+func TestCreateReplyDraft(t *testing.T) {
+	var patch map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1.0/me/messages/m1/createReplyAll":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"d2","subject":"RE: Plan",
+				"toRecipients":[{"emailAddress":{"address":"alice@contoso.com"}}],
+				"body":{"contentType":"html","content":"<div>original</div>"}}`))
+		case r.Method == "PATCH" && r.URL.Path == "/v1.0/me/messages/d2":
+			json.NewDecoder(r.Body).Decode(&patch)
+			w.Write([]byte(`{"id":"d2","subject":"RE: Plan"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	_, err := o.CreateReplyDraft(context.Background(), "m1", DraftRequest{Body: "Sounds good\n<3", To: []string{"bob@contoso.com"}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := patch["body"].(map[string]any)["content"].(string)
+	if !strings.HasPrefix(content, "Sounds good<br>\n&lt;3") || !strings.HasSuffix(content, "<div>original</div>") {
+		t.Errorf("body = %q", content)
+	}
+	if to := patch["toRecipients"].([]any); len(to) != 2 {
+		t.Errorf("toRecipients = %v, want original plus added", to)
+	}
+}
+
+func TestUpdateAndSendDraft(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte(`{"id":"d1"}`))
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	ctx := context.Background()
+	if _, err := o.UpdateDraft(ctx, "d1", DraftRequest{}); err == nil {
+		t.Error("expected error for empty update")
+	}
+	if _, err := o.UpdateDraft(ctx, "d1", DraftRequest{Subject: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.SendDraft(ctx, "d1"); err != nil {
+		t.Fatal(err)
+	}
+	want := "PATCH /v1.0/me/messages/d1,POST /v1.0/me/messages/d1/send"
+	if got := strings.Join(requests, ","); got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// builtinFields are list item columns SharePoint maintains itself. They are
+// hidden by CustomFields so only user-defined columns remain.
+var builtinFields = map[string]bool{
+	"id": true, "ContentType": true, "Created": true, "Modified": true,
+	"AuthorLookupId": true, "EditorLookupId": true, "AppAuthorLookupId": true,
+	"AppEditorLookupId": true, "Attachments": true, "Edit": true,
+	"LinkFilename": true, "LinkFilenameNoMenu": true, "FileLeafRef": true,
+	"DocIcon": true, "FileSizeDisplay": true, "ItemChildCount": true,
+	"FolderChildCount": true, "CheckoutUserLookupId": true,
+}
+
+// GetItemFields returns the list item column values (metadata) of a
+// library file, keyed by column internal name.
+func (sp *SharePoint) GetItemFields(ctx context.Context, siteID, driveID, itemPath string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", sp.itemURL(siteID, driveID, itemPath)+":/listItem/fields", nil)
+	if err != nil {
+		return nil, err
+	}
+	return sp.fieldsRequest(req, "get fields")
+}
+
+// SetItemFields updates column values on a library file and returns the
+// resulting fields. Keys are column internal names; person and lookup
+// columns are set through their "<Name>LookupId" field.
+func (sp *SharePoint) SetItemFields(ctx context.Context, siteID, driveID, itemPath string, fields map[string]any) (map[string]any, error) {
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode fields: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "PATCH", sp.itemURL(siteID, driveID, itemPath)+":/listItem/fields", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return sp.fieldsRequest(req, "set fields")
+}
+
+func (sp *SharePoint) fieldsRequest(req *http.Request, what string) (map[string]any, error) {
+	resp, err := sp.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s failed (HTTP %d): %s", what, resp.StatusCode, string(body))
+	}
+
+	var fields map[string]any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("could not parse fields: %w", err)
+	}
+	return fields, nil
+}
+
+// CustomFields returns fields without SharePoint's built-in and system
+// columns (OData annotations, "_"-prefixed columns, and builtinFields).
+func CustomFields(fields map[string]any) map[string]any {
+	out := make(map[string]any)
+	for k, v := range fields {
+		if strings.HasPrefix(k, "@odata") || strings.HasPrefix(k, "_") || builtinFields[k] {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
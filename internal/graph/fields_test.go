@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetItemFields(t *testing.T) {
+	var gotReq string
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReq = r.Method + " " + r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"@odata.etag":"\"1\"","Status":"Approved","Score":12345678901,"FileLeafRef":"plan.docx"}`))
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	fields, err := sp.SetItemFields(context.Background(), "s", "d", "Specs/plan.docx", map[string]any{"Status": "Approved"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotReq != "PATCH /v1.0/sites/s/drives/d/root:/Specs/plan.docx:/listItem/fields" {
+		t.Errorf("request = %s", gotReq)
+	}
+	if got["Status"] != "Approved" {
+		t.Errorf("payload = %v", got)
+	}
+	if n, ok := fields["Score"].(json.Number); !ok || n.String() != "12345678901" {
+		t.Errorf("Score = %#v, want exact json.Number", fields["Score"])
+	}
+}
+
+func TestCustomFields(t *testing.T) {
+	fields := map[string]any{
+		"@odata.etag":      "x",
+		"_UIVersionString": "1.0",
+		"FileLeafRef":      "plan.docx",
+		"Created":          "2024-01-01T00:00:00Z",
+		"Status":           "Draft",
+		"OwnerLookupId":    "12",
+	}
+	custom := CustomFields(fields)
+	if len(custom) != 2 || custom["Status"] != "Draft" || custom["OwnerLookupId"] != "12" {
+		t.Errorf("CustomFields = %v", custom)
+	}
+}
@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// defaultGroupDepth is how many levels of nested groups are followed.
+const defaultGroupDepth = 3
+
+// directoryObject is a member returned by the group members API.
+type directoryObject struct {
+	Type              string `json:"@odata.type"`
+	ID                string `json:"id"`
+	DisplayName       string `json:"displayName"`
+	Mail              string `json:"mail"`
+	UserPrincipalName string `json:"userPrincipalName"`
+}
+
+func (o directoryObject) isGroup() bool { return o.Type == "#microsoft.graph.group" }
+
+func (o directoryObject) email() string {
+	if o.Mail != "" {
+		return o.Mail
+	}
+	return o.UserPrincipalName
+}
+
+// GroupExpander resolves Microsoft Entra (Azure AD) group members. Each
+// group's member list (or the error reading it) is fetched once and cached.
+type GroupExpander struct {
+	Client *http.Client
+	// MaxDepth is how many levels of nested groups to follow (0 = 3).
+	MaxDepth int
+	cache    map[string][]directoryObject
+	failed   map[string]error
+}
+
+// NewGroupExpander creates a new GroupExpander.
+func NewGroupExpander(client *http.Client) *GroupExpander {
+	return &GroupExpander{Client: client}
+}
+
+// Members returns the users in a group, following nested groups up to
+// MaxDepth levels. Each user appears once. Nested groups found at the last
+// level are returned, unexpanded, in truncated: their members have access
+// too but were not read.
+func (g *GroupExpander) Members(ctx context.Context, groupID string) (users []GraphUser, truncated []GraphGroup, err error) {
+	depth := g.MaxDepth
+	if depth <= 0 {
+		depth = defaultGroupDepth
+	}
+	seen := map[string]bool{groupID: true}
+	byEmail := map[string]GraphUser{}
+	deeper := map[string]GraphGroup{}
+	if err := g.collect(ctx, groupID, depth, seen, byEmail, deeper); err != nil {
+		return nil, nil, err
+	}
+
+	users = make([]GraphUser, 0, len(byEmail))
+	for _, u := range byEmail {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+	for id, grp := range deeper {
+		// Reached by a shorter path as well, so it was expanded
+		if !seen[id] {
+			truncated = append(truncated, grp)
+		}
+	}
+	sort.Slice(truncated, func(i, j int) bool { return truncated[i].DisplayName < truncated[j].DisplayName })
+	return users, truncated, nil
+}
+
+func (g *GroupExpander) collect(ctx context.Context, groupID string, depth int, seen map[string]bool, users map[string]GraphUser, deeper map[string]GraphGroup) error {
+	members, err := g.directMembers(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if !m.isGroup() {
+			if m.email() != "" {
+				users[strings.ToLower(m.email())] = GraphUser{ID: m.ID, DisplayName: m.DisplayName, Email: m.email()}
+			}
+			continue
+		}
+		if seen[m.ID] {
+			continue
+		}
+		if depth <= 1 {
+			deeper[m.ID] = GraphGroup{ID: m.ID, DisplayName: m.DisplayName}
+			continue
+		}
+		seen[m.ID] = true
+		if err := g.collect(ctx, m.ID, depth-1, seen, users, deeper); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *GroupExpander) directMembers(ctx context.Context, groupID string) ([]directoryObject, error) {
+	if g.cache == nil {
+		g.cache = make(map[string][]directoryObject)
+		g.failed = make(map[string]error)
+	}
+	if members, ok := g.cache[groupID]; ok {
+		return members, nil
+	}
+	if err, ok := g.failed[groupID]; ok {
+		return nil, err
+	}
+	endpoint := graphBase() + "/groups/" + url.PathEscape(groupID) + "/members?$select=id,displayName,mail,userPrincipalName"
+	members, err := GetAll[directoryObject](ctx, g.Client, endpoint, "group members", 0)
+	if err != nil {
+		g.failed[groupID] = err
+		return nil, err
+	}
+	g.cache[groupID] = members
+	return members, nil
+}
+
+// Access is a person (or an unexpanded group) with access, and how they
+// got it: "direct", or the names of the groups that grant it. Truncated
+// marks a nested group that was not expanded because it lies deeper than
+// GroupExpander.MaxDepth.
+type Access struct {
+	Email     string   `json:"email,omitempty"`
+	Name      string   `json:"name"`
+	Group     bool     `json:"group,omitempty"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Via       []string `json:"via"`
+}
+
+// Principals returns every user or group a permission grants access to,
+// including the recipients of a sharing link.
+func (p Permission) Principals() []Principal {
+	var out []Principal
+	switch {
+	case p.GrantedToV2 != nil:
+		out = append(out, *p.GrantedToV2)
+	case p.GrantedTo != nil:
+		out = append(out, *p.GrantedTo)
+	}
+	return append(out, p.GrantedToIdentitiesV2...)
+}
+
+// CollectAccess flattens permissions into the people they grant access to.
+// Groups are expanded into their members through g; with a nil g, or when a
+// group's members cannot be read, the group is listed by name instead, as
+// are nested groups too deep to expand (marked Truncated).
+func CollectAccess(ctx context.Context, perms []Permission, g *GroupExpander) []Access {
+	byKey := map[string]*Access{}
+	var order []string
+	add := func(key string, a Access, via string) {
+		existing, ok := byKey[key]
+		if !ok {
+			a.Via = nil
+			existing = &a
+			byKey[key] = existing
+			order = append(order, key)
+		}
+		for _, v := range existing.Via {
+			if v == via {
+				return
+			}
+		}
+		existing.Via = append(existing.Via, via)
+	}
+
+	for _, p := range perms {
+		for _, pr := range p.Principals() {
+			switch {
+			case pr.User != nil && pr.User.Email != "":
+				add(strings.ToLower(pr.User.Email), Access{Email: pr.User.Email, Name: pr.User.DisplayName}, "direct")
+			case pr.Group != nil:
+				var members []GraphUser
+				var truncated []GraphGroup
+				var err error
+				if g != nil {
+					members, truncated, err = g.Members(ctx, pr.Group.ID)
+				}
+				if g == nil || err != nil {
+					add("group:"+pr.Group.ID, Access{Name: pr.Group.DisplayName, Group: true}, "direct")
+					continue
+				}
+				for _, m := range members {
+					add(strings.ToLower(m.Email), Access{Email: m.Email, Name: m.DisplayName}, pr.Group.DisplayName)
+				}
+				for _, t := range truncated {
+					add("group:"+t.ID, Access{Name: t.DisplayName, Group: true, Truncated: true}, pr.Group.DisplayName)
+				}
+			}
+		}
+	}
+
+	out := make([]Access, 0, len(order))
+	for _, key := range order {
+		out = append(out, *byKey[key])
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Group != out[j].Group {
+			return !out[i].Group
+		}
+		return strings.ToLower(out[i].Email+out[i].Name) < strings.ToLower(out[j].Email+out[j].Name)
+	})
+	return out
+}
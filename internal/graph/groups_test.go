@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// groupServer serves nested groups:
+//
+//	eng      → ann, backend
+//	backend  → bob, ann, infra
+//	infra    → cat, eng (cycle)
+//	secret   → 403
+func groupServer(t *testing.T, requests map[string]int) *httptest.Server {
+	t.Helper()
+	user := func(id, mail string) map[string]any {
+		return map[string]any{"@odata.type": "#microsoft.graph.user", "id": id, "displayName": id, "mail": mail}
+	}
+	group := func(id string) map[string]any {
+		return map[string]any{"@odata.type": "#microsoft.graph.group", "id": id, "displayName": id}
+	}
+	members := map[string][]map[string]any{
+		"eng":     {user("ann", "ann@contoso.com"), group("backend")},
+		"backend": {user("bob", "bob@contoso.com"), user("ann", "Ann@contoso.com"), group("infra")},
+		"infra":   {user("cat", "cat@contoso.com"), group("eng")},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1.0/groups/"), "/members")
+		requests[id]++
+		m, ok := members[id]
+		if !ok {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"value": m})
+	}))
+}
+
+func emails(users []GraphUser) string {
+	var out []string
+	for _, u := range users {
+		out = append(out, strings.ToLower(u.Email))
+	}
+	return strings.Join(out, ",")
+}
+
+func TestGroupExpanderMembers(t *testing.T) {
+	requests := map[string]int{}
+	server := groupServer(t, requests)
+	defer server.Close()
+
+	g := NewGroupExpander(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	users, truncated, err := g.Members(context.Background(), "eng")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := emails(users); got != "ann@contoso.com,bob@contoso.com,cat@contoso.com" || len(truncated) != 0 {
+		t.Errorf("members = %s, truncated %v", got, truncated)
+	}
+
+	g.Members(context.Background(), "backend")
+	if requests["eng"] != 1 || requests["backend"] != 1 || requests["infra"] != 1 {
+		t.Errorf("expected each group fetched once, got %v", requests)
+	}
+
+	g.MaxDepth = 2
+	users, truncated, _ = g.Members(context.Background(), "eng")
+	if got := emails(users); got != "ann@contoso.com,bob@contoso.com" {
+		t.Errorf("members at depth 2 = %s", got)
+	}
+	if len(truncated) != 1 || truncated[0].ID != "infra" {
+		t.Errorf("expected infra reported as not expanded, got %v", truncated)
+	}
+}
+
+func TestCollectAccess(t *testing.T) {
+	requests := map[string]int{}
+	server := groupServer(t, requests)
+	defer server.Close()
+
+	perms := []Permission{
+		{GrantedToV2: &Principal{User: &GraphUser{Email: "bob@contoso.com", DisplayName: "Bob"}}},
+		{GrantedToV2: &Principal{Group: &GraphGroup{ID: "backend", DisplayName: "Backend"}}},
+		{GrantedToV2: &Principal{Group: &GraphGroup{ID: "secret", DisplayName: "Secret"}}},
+		{Link: &PermLink{Scope: "users"}, GrantedToIdentitiesV2: []Principal{{User: &GraphUser{Email: "ext@gmail.com"}}}},
+	}
+
+	g := NewGroupExpander(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	access := CollectAccess(context.Background(), perms, g)
+
+	var got []string
+	for _, a := range access {
+		got = append(got, strings.ToLower(a.Email)+"|"+a.Name+"|"+strings.Join(a.Via, "+"))
+	}
+	want := "ann@contoso.com|ann|Backend,bob@contoso.com|Bob|direct+Backend,cat@contoso.com|cat|Backend,ext@gmail.com||direct,|Secret|direct"
+	if strings.Join(got, ",") != want {
+		t.Errorf("access =\n%s\nwant\n%s", strings.Join(got, ","), want)
+	}
+	if !access[len(access)-1].Group {
+		t.Error("unreadable group should be listed as a group")
+	}
+
+	g.MaxDepth = 1
+	access = CollectAccess(context.Background(), perms[1:2], g)
+	if len(access) != 3 || !access[2].Truncated || access[2].Name != "infra" || access[2].Via[0] != "Backend" {
+		t.Errorf("access at depth 1 = %+v", access)
+	}
+
+	access = CollectAccess(context.Background(), perms, nil)
+	if len(access) != 4 || !access[3].Group {
+		t.Errorf("unexpanded access = %+v", access)
+	}
+}
@@ -0,0 +1,322 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HarvestManifestName is the manifest file Harvest writes in its output
+// directory.
+const HarvestManifestName = "manifest.json"
+
+// HarvestOptions configures Harvest.
+type HarvestOptions struct {
+	// Filter selects messages; only messages with attachments are read.
+	// Filter.From may be a full address or a domain such as "vendor.com".
+	// Filter.Limit caps the number of messages (0 = all).
+	Filter     InboxFilter
+	OfficeOnly bool
+	Dir        string
+	// Concurrency is the number of parallel downloads (default 4).
+	Concurrency int
+	// Progress, if set, is called as each attachment is handled.
+	Progress func(HarvestEntry)
+}
+
+// HarvestEntry records one harvested attachment.
+type HarvestEntry struct {
+	MessageID    string    `json:"messageId"`
+	AttachmentID string    `json:"attachmentId"`
+	Subject      string    `json:"subject"`
+	From         string    `json:"from"`
+	Received     time.Time `json:"received"`
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256,omitempty"`
+	// Path is the file written, relative to the output directory. For a
+	// duplicate it is the earlier copy with the same content.
+	Path      string `json:"path,omitempty"`
+	Duplicate bool   `json:"duplicate,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HarvestManifest lists every attachment harvested into a directory.
+type HarvestManifest struct {
+	Updated    time.Time      `json:"updated"`
+	Messages   int            `json:"messages"` // messages attachments came from
+	Downloaded int            `json:"downloaded"`
+	Duplicates int            `json:"duplicates"`
+	Failed     int            `json:"failed"`
+	Files      []HarvestEntry `json:"files"`
+}
+
+// Harvest downloads the attachments of every matching message into
+// opts.Dir, following pages of results. Files with identical content are
+// written once (by SHA-256); later copies are recorded as duplicates of the
+// first. The manifest in opts.Dir is updated, so re-running skips
+// attachments already harvested.
+func (o *Outlook) Harvest(ctx context.Context, opts HarvestOptions) (*HarvestManifest, error) {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create output directory: %w", err)
+	}
+
+	manifest, err := LoadHarvestManifest(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	h := &harvester{
+		dir:    opts.Dir,
+		hashes: map[string]string{},
+		names:  map[string]bool{},
+		seen:   map[string]bool{},
+	}
+	for _, e := range manifest.Files {
+		if e.Error != "" {
+			continue
+		}
+		h.seen[e.MessageID+"/"+e.AttachmentID] = true
+		if !e.Duplicate {
+			h.hashes[e.SHA256] = e.Path
+			h.names[strings.ToLower(e.Path)] = true
+		}
+	}
+	manifest.Files = dropFailed(manifest.Files)
+
+	// A bare domain can't be matched server-side, so it is checked here.
+	filter := opts.Filter
+	filter.HasAttachment = true
+	domain := ""
+	if filter.From != "" && !strings.Contains(filter.From, "@") {
+		domain = strings.ToLower(strings.TrimPrefix(filter.From, "@"))
+		filter.From = ""
+	}
+
+	type job struct {
+		msg EmailMessage
+		att Attachment
+	}
+	jobs := make(chan job)
+	results := make(chan HarvestEntry)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- h.download(ctx, o, j.msg, j.att)
+			}
+		}()
+	}
+
+	var collected []HarvestEntry
+	done := make(chan struct{})
+	go func() {
+		for e := range results {
+			if opts.Progress != nil {
+				opts.Progress(e)
+			}
+			collected = append(collected, e)
+		}
+		close(done)
+	}()
+
+	messages := 0
+	walkErr := ForEach(ctx, o.Client, o.messagesEndpoint("", filter, maxPageSize), "list messages", func(msg EmailMessage) error {
+		if domain != "" && !fromDomain(msg.From.EmailAddress.Address, domain) {
+			return nil
+		}
+		atts, err := o.ListAttachments(ctx, msg.ID)
+		if err != nil {
+			return err
+		}
+		messages++
+		for _, att := range atts {
+			if att.IsInline || (opts.OfficeOnly && !IsOfficeAttachment(att.Name)) {
+				continue
+			}
+			if h.seen[msg.ID+"/"+att.ID] {
+				continue
+			}
+			att.ContentBytes = ""
+			select {
+			case jobs <- job{msg, att}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if filter.Limit > 0 && messages >= filter.Limit {
+			return errStopPaging
+		}
+		return nil
+	})
+	close(jobs)
+	wg.Wait()
+	close(results)
+	<-done
+
+	manifest.Updated = time.Now().UTC()
+	manifest.Files = append(manifest.Files, collected...)
+	manifest.Downloaded, manifest.Duplicates, manifest.Failed = 0, 0, 0
+	fromMessages := map[string]bool{}
+	for _, e := range manifest.Files {
+		fromMessages[e.MessageID] = true
+		switch {
+		case e.Error != "":
+			manifest.Failed++
+		case e.Duplicate:
+			manifest.Duplicates++
+		default:
+			manifest.Downloaded++
+		}
+	}
+	manifest.Messages = len(fromMessages)
+	if err := saveHarvestManifest(opts.Dir, manifest); err != nil {
+		return manifest, err
+	}
+	return manifest, walkErr
+}
+
+// LoadHarvestManifest reads the manifest in dir, or returns an empty one if
+// there is none yet.
+func LoadHarvestManifest(dir string) (*HarvestManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, HarvestManifestName))
+	if os.IsNotExist(err) {
+		return &HarvestManifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+	var m HarvestManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", HarvestManifestName, err)
+	}
+	return &m, nil
+}
+
+func saveHarvestManifest(dir string, m *HarvestManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, HarvestManifestName), data, 0644); err != nil {
+		return fmt.Errorf("could not write manifest: %w", err)
+	}
+	return nil
+}
+
+// dropFailed drops failed entries so they are retried.
+func dropFailed(files []HarvestEntry) []HarvestEntry {
+	kept := files[:0]
+	for _, e := range files {
+		if e.Error == "" {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func fromDomain(address, domain string) bool {
+	address = strings.ToLower(address)
+	return strings.HasSuffix(address, "@"+domain) || strings.HasSuffix(address, "."+domain)
+}
+
+// harvester tracks content hashes and file names shared by the download
+// workers.
+type harvester struct {
+	dir    string
+	mu     sync.Mutex
+	hashes map[string]string // sha256 -> relative path
+	names  map[string]bool   // lower-cased relative paths in use
+	seen   map[string]bool   // message/attachment IDs already in the manifest
+}
+
+func (h *harvester) download(ctx context.Context, o *Outlook, msg EmailMessage, att Attachment) HarvestEntry {
+	e := HarvestEntry{
+		MessageID:    msg.ID,
+		AttachmentID: att.ID,
+		Subject:      msg.Subject,
+		From:         msg.From.EmailAddress.Address,
+		Received:     msg.ReceivedAt,
+		Name:         att.Name,
+		Size:         att.Size,
+	}
+	// Stream into a temporary file while hashing, so large attachments are
+	// never held in memory and duplicates are simply discarded.
+	tmp, err := os.CreateTemp(h.dir, ".harvest-*.part")
+	if err != nil {
+		e.Error = fmt.Sprintf("could not create temporary file: %v", err)
+		return e
+	}
+	hash := sha256.New()
+	n, err := o.streamAttachment(ctx, msg.ID, att, io.MultiWriter(tmp, hash), nil)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		e.Error = err.Error()
+		return e
+	}
+	e.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	e.Size = n
+
+	h.mu.Lock()
+	if path, ok := h.hashes[e.SHA256]; ok {
+		h.mu.Unlock()
+		os.Remove(tmp.Name())
+		e.Path, e.Duplicate = path, true
+		return e
+	}
+	e.Path = h.reserveName(att.Name)
+	h.hashes[e.SHA256] = e.Path
+	h.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), filepath.Join(h.dir, e.Path)); err != nil {
+		os.Remove(tmp.Name())
+		e.Error = fmt.Sprintf("could not write %s: %v", e.Path, err)
+		h.mu.Lock()
+		delete(h.hashes, e.SHA256)
+		h.mu.Unlock()
+	}
+	return e
+}
+
+// reserveName picks a file name not yet used, adding " (2)", " (3)", …
+// before the extension as needed. h.mu must be held.
+func (h *harvester) reserveName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	switch name {
+	case ".", "/":
+		name = "attachment"
+	case HarvestManifestName:
+		name = "attachment-" + name
+	}
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 2; h.names[strings.ToLower(candidate)] || fileExists(filepath.Join(h.dir, candidate)); i++ {
+		candidate = fmt.Sprintf("%s (%d)%s", stem, i, ext)
+	}
+	h.names[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHarvest(t *testing.T) {
+	contents := map[string]string{
+		"m1/a1": "contract v1",
+		"m1/a2": "logo",
+		"m2/a3": "contract v1", // same content as m1/a1
+		"m2/a4": "contract v2", // same name as m1/a1
+		"m3/a5": "ignored sender",
+	}
+	attachments := map[string][]Attachment{
+		"m1": {{ID: "a1", Name: "contract.docx"}, {ID: "a2", Name: "logo.png", IsInline: true}},
+		"m2": {{ID: "a3", Name: "copy.docx"}, {ID: "a4", Name: "contract.docx"}, {ID: "a6", Name: "notes.txt"}},
+		"m3": {{ID: "a5", Name: "other.docx"}},
+	}
+	var downloads atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1.0/me/messages"), "/")
+		switch {
+		case r.URL.Path == "/v1.0/me/messages" && r.URL.Query().Get("page") == "":
+			if !strings.Contains(r.URL.Query().Get("$filter"), "hasAttachments eq true") {
+				t.Errorf("$filter = %q", r.URL.Query().Get("$filter"))
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"value": []EmailMessage{
+					{ID: "m1", From: EmailRecipient{EmailAddr{Address: "ann@vendor.com"}}},
+					{ID: "m3", From: EmailRecipient{EmailAddr{Address: "bob@other.com"}}},
+				},
+				"@odata.nextLink": server.URL + "/v1.0/me/messages?page=2",
+			})
+		case r.URL.Path == "/v1.0/me/messages":
+			json.NewEncoder(w).Encode(map[string]any{"value": []EmailMessage{
+				{ID: "m2", From: EmailRecipient{EmailAddr{Address: "cy@mail.vendor.com"}}},
+			}})
+		case len(parts) == 3 && parts[2] == "attachments":
+			json.NewEncoder(w).Encode(map[string]any{"value": attachments[parts[1]]})
+		case len(parts) == 5 && parts[4] == "$value":
+			downloads.Add(1)
+			w.Write([]byte(contents[parts[1]+"/"+parts[3]]))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	dir := t.TempDir()
+	opts := HarvestOptions{Filter: InboxFilter{From: "vendor.com"}, OfficeOnly: true, Dir: dir, Concurrency: 2}
+	m, err := o.Harvest(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Downloaded != 2 || m.Duplicates != 1 || m.Failed != 0 || m.Messages != 2 {
+		t.Errorf("manifest counts = %d downloaded, %d duplicates, %d failed, %d messages", m.Downloaded, m.Duplicates, m.Failed, m.Messages)
+	}
+	for name, want := range map[string]string{"contract.docx": "contract v1", "contract (2).docx": "contract v2"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "copy.docx")); err == nil {
+		t.Error("duplicate content should not be written again")
+	}
+	for _, e := range m.Files {
+		if e.AttachmentID == "a3" && (!e.Duplicate || e.Path != "contract.docx") {
+			t.Errorf("duplicate entry = %+v", e)
+		}
+	}
+
+	saved, err := LoadHarvestManifest(dir)
+	if err != nil || len(saved.Files) != 3 {
+		t.Fatalf("saved manifest: %v, %d files", err, len(saved.Files))
+	}
+
+	// A second run skips everything already in the manifest.
+	before := downloads.Load()
+	m, err = o.Harvest(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if downloads.Load() != before || len(m.Files) != 3 {
+		t.Errorf("re-run downloaded %d more, manifest has %d files", downloads.Load()-before, len(m.Files))
+	}
+}
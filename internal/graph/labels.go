@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ItemLabels holds the compliance labels applied to a drive item.
+type ItemLabels struct {
+	// Sensitivity is the sensitivity label's name, or its ID when the
+	// name cannot be looked up.
+	Sensitivity   string `json:"sensitivity,omitempty"`
+	SensitivityID string `json:"sensitivityId,omitempty"`
+	Retention     string `json:"retention,omitempty"`
+}
+
+// LabelReader reads sensitivity and retention labels from drive items.
+// Sensitivity label names are looked up once and cached.
+type LabelReader struct {
+	Client *http.Client
+	names  map[string]string
+}
+
+// NewLabelReader creates a new LabelReader.
+func NewLabelReader(client *http.Client) *LabelReader {
+	return &LabelReader{Client: client}
+}
+
+// ItemLabels returns the labels on an item. Folders only carry retention
+// labels; files whose type does not support sensitivity labels report none.
+func (l *LabelReader) ItemLabels(ctx context.Context, driveID string, item DriveItem) (*ItemLabels, error) {
+	base := graphBase() + "/drives/" + driveID + "/items/" + url.PathEscape(item.ID)
+	labels := &ItemLabels{}
+
+	var retention struct {
+		Name string `json:"name"`
+	}
+	found, err := l.labelRequest(ctx, "GET", base+"/retentionLabel", &retention)
+	if err != nil {
+		return nil, fmt.Errorf("retention label for %s: %w", item.Name, err)
+	}
+	if found {
+		labels.Retention = retention.Name
+	}
+
+	if item.IsFolder {
+		return labels, nil
+	}
+	var sensitivity struct {
+		Labels []struct {
+			SensitivityLabelID string `json:"sensitivityLabelId"`
+		} `json:"labels"`
+	}
+	found, err = l.labelRequest(ctx, "POST", base+"/extractSensitivityLabels", &sensitivity)
+	if err != nil {
+		return nil, fmt.Errorf("sensitivity label for %s: %w", item.Name, err)
+	}
+	if found && len(sensitivity.Labels) > 0 {
+		id := sensitivity.Labels[0].SensitivityLabelID
+		labels.SensitivityID = id
+		labels.Sensitivity = id
+		if name := l.labelName(ctx, id); name != "" {
+			labels.Sensitivity = name
+		}
+	}
+	return labels, nil
+}
+
+// labelRequest decodes a label response into v. It reports false when the
+// item has no label or its type does not support one.
+func (l *LabelReader) labelRequest(ctx context.Context, method, endpoint string, v any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusNoContent:
+		return false, nil
+	default:
+		return false, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("could not parse label: %w", err)
+	}
+	return true, nil
+}
+
+// labelName returns a sensitivity label's display name. The label catalog
+// needs extra permissions, so failures just leave names unresolved.
+func (l *LabelReader) labelName(ctx context.Context, id string) string {
+	if l.names == nil {
+		l.names = make(map[string]string)
+		type label struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		endpoint := graphBetaBase() + "/security/informationProtection/sensitivityLabels"
+		if all, err := GetAll[label](ctx, l.Client, endpoint, "sensitivity labels", 0); err == nil {
+			for _, lb := range all {
+				l.names[lb.ID] = lb.Name
+			}
+		}
+	}
+	return l.names[id]
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLabelReader(t *testing.T) {
+	var catalogCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1.0/drives/d/items/f1/retentionLabel":
+			json.NewEncoder(w).Encode(map[string]any{"name": "Keep 7 years"})
+		case "POST /v1.0/drives/d/items/f1/extractSensitivityLabels":
+			json.NewEncoder(w).Encode(map[string]any{"labels": []any{map[string]any{"sensitivityLabelId": "lbl-1"}}})
+		case "GET /v1.0/drives/d/items/dir/retentionLabel":
+			json.NewEncoder(w).Encode(map[string]any{"name": "Project records"})
+		case "POST /v1.0/drives/d/items/dir/extractSensitivityLabels":
+			t.Error("sensitivity labels should not be read for folders")
+		case "POST /v1.0/drives/d/items/txt/extractSensitivityLabels":
+			http.Error(w, `{"error":{"code":"notSupported"}}`, http.StatusBadRequest)
+		case "GET /beta/security/informationProtection/sensitivityLabels":
+			catalogCalls++
+			json.NewEncoder(w).Encode(map[string]any{"value": []any{map[string]any{"id": "lbl-1", "name": "Confidential"}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	l := NewLabelReader(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	ctx := context.Background()
+
+	got, err := l.ItemLabels(ctx, "d", DriveItem{ID: "f1", Name: "plan.docx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Sensitivity != "Confidential" || got.SensitivityID != "lbl-1" || got.Retention != "Keep 7 years" {
+		t.Errorf("file labels = %+v", got)
+	}
+
+	got, err = l.ItemLabels(ctx, "d", DriveItem{ID: "dir", Name: "Contracts", IsFolder: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Retention != "Project records" || got.Sensitivity != "" {
+		t.Errorf("folder labels = %+v", got)
+	}
+
+	got, err = l.ItemLabels(ctx, "d", DriveItem{ID: "txt", Name: "notes.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != (ItemLabels{}) {
+		t.Errorf("unlabeled file = %+v", got)
+	}
+
+	l.ItemLabels(ctx, "d", DriveItem{ID: "f1", Name: "plan.docx"})
+	if catalogCalls != 1 {
+		t.Errorf("label catalog fetched %d times, want 1", catalogCalls)
+	}
+}
+
+func TestLabelReaderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	l := NewLabelReader(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	if _, err := l.ItemLabels(context.Background(), "d", DriveItem{ID: "f1", Name: "plan.docx"}); err == nil {
+		t.Error("expected error for HTTP 403")
+	}
+}
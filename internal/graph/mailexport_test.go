@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMIME = "From: alice@contoso.com\r\nSubject: Hello\r\n\r\nBody\r\n"
+
+func TestSaveMessageMIME(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/me/messages/m1/$value" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(testMIME))
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	path := filepath.Join(t.TempDir(), "out", "msg.eml")
+	n, err := o.SaveMessageMIME(context.Background(), "m1", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != testMIME || n != int64(len(testMIME)) {
+		t.Errorf("wrote %d bytes: %q", n, data)
+	}
+	if _, err := os.Stat(path + ".part"); err == nil {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestExportMail(t *testing.T) {
+	received := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+	var fetched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1.0/me/mailFolders/archive/messages":
+			json.NewEncoder(w).Encode(map[string]any{"value": []EmailMessage{
+				{ID: "m1", Subject: "Q1: report/final", ReceivedAt: received},
+				{ID: "m2", Subject: "", ReceivedAt: received},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/$value"):
+			fetched = append(fetched, r.URL.Path)
+			if strings.Contains(r.URL.Path, "m2") {
+				http.Error(w, "gone", http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(testMIME))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	dir := t.TempDir()
+	opts := MailExportOptions{FolderID: "archive", Dir: dir}
+	results, err := o.ExportMail(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Error != "" || results[1].Error == "" {
+		t.Fatalf("results = %+v", results)
+	}
+	name := filepath.Base(results[0].Path)
+	if !strings.HasPrefix(name, "2026-01-02 0930 Q1_ report_final ") || !strings.HasSuffix(name, ".eml") {
+		t.Errorf("file name = %q", name)
+	}
+
+	// Re-running skips the exported message and retries the failed one.
+	fetched = nil
+	results, _ = o.ExportMail(context.Background(), opts)
+	if !results[0].Skipped || len(fetched) != 1 {
+		t.Errorf("re-run results = %+v, fetched %v", results, fetched)
+	}
+}
+
+func TestEMLFileNameStable(t *testing.T) {
+	a := EMLFileName(EmailMessage{ID: "x", Subject: "Hi"})
+	b := EMLFileName(EmailMessage{ID: "y", Subject: "Hi"})
+	if a == b || a != EMLFileName(EmailMessage{ID: "x", Subject: "Hi"}) {
+		t.Errorf("names %q, %q", a, b)
+	}
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MailFolder is an Outlook mail folder.
+type MailFolder struct {
+	ID               string `json:"id"`
+	DisplayName      string `json:"displayName"`
+	ParentFolderID   string `json:"parentFolderId,omitempty"`
+	ChildFolderCount int    `json:"childFolderCount"`
+	UnreadItemCount  int    `json:"unreadItemCount"`
+	TotalItemCount   int    `json:"totalItemCount"`
+	// Path is the folder's location from the mailbox root, e.g. "Inbox/Projects".
+	Path string `json:"path"`
+}
+
+// wellKnownFolders maps common folder names to Graph's well-known folder
+// names, which work in place of a folder ID whatever the mailbox language.
+var wellKnownFolders = map[string]string{
+	"inbox":         "inbox",
+	"archive":       "archive",
+	"drafts":        "drafts",
+	"sent":          "sentitems",
+	"sent items":    "sentitems",
+	"sentitems":     "sentitems",
+	"deleted":       "deleteditems",
+	"deleted items": "deleteditems",
+	"deleteditems":  "deleteditems",
+	"trash":         "deleteditems",
+	"junk":          "junkemail",
+	"junk email":    "junkemail",
+	"junkemail":     "junkemail",
+	"spam":          "junkemail",
+	"outbox":        "outbox",
+}
+
+// ListFolders returns every mail folder in the mailbox, parents before
+// their children.
+func (o *Outlook) ListFolders(ctx context.Context) ([]MailFolder, error) {
+	return o.listFolders(ctx, o.base()+"/mailFolders?$top=100", "")
+}
+
+func (o *Outlook) listFolders(ctx context.Context, endpoint, parentPath string) ([]MailFolder, error) {
+	folders, err := GetAll[MailFolder](ctx, o.Client, endpoint, "list mail folders", 0)
+	if err != nil {
+		return nil, err
+	}
+	var all []MailFolder
+	for _, f := range folders {
+		f.Path = f.DisplayName
+		if parentPath != "" {
+			f.Path = parentPath + "/" + f.DisplayName
+		}
+		all = append(all, f)
+		if f.ChildFolderCount > 0 {
+			children, err := o.listFolders(ctx, o.base()+"/mailFolders/"+url.PathEscape(f.ID)+"/childFolders?$top=100", f.Path)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, children...)
+		}
+	}
+	return all, nil
+}
+
+// ResolveFolder finds a mail folder by path ("Inbox/Projects"), display name,
+// or ID. Names are matched case-insensitively; common names such as
+// "archive", "sent", or "junk" resolve to the well-known folder even when
+// the mailbox uses another language.
+func (o *Outlook) ResolveFolder(ctx context.Context, name string) (*MailFolder, error) {
+	folders, err := o.ListFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lower := strings.ToLower(strings.Trim(name, "/"))
+	for i := range folders {
+		if strings.ToLower(folders[i].Path) == lower || folders[i].ID == name {
+			return &folders[i], nil
+		}
+	}
+	for i := range folders {
+		if strings.ToLower(folders[i].DisplayName) == lower {
+			return &folders[i], nil
+		}
+	}
+	if wk, ok := wellKnownFolders[lower]; ok {
+		return &MailFolder{ID: wk, DisplayName: name, Path: name}, nil
+	}
+	return nil, fmt.Errorf("mail folder %q not found — run: kit outlook folders", name)
+}
+
+// MoveMessage moves a message to another folder and returns the moved
+// message. Graph gives the moved message a new ID.
+func (o *Outlook) MoveMessage(ctx context.Context, messageID, folderID string) (*EmailMessage, error) {
+	endpoint := o.base() + "/messages/" + url.PathEscape(messageID) + "/move"
+	jsonData, err := json.Marshal(map[string]string{"destinationId": folderID})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not move message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("move message failed (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var msg EmailMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return nil, fmt.Errorf("could not parse moved message: %w", err)
+	}
+	return &msg, nil
+}
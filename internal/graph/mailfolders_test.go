@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func folderTestServer(t *testing.T, extra http.HandlerFunc) *Outlook {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1.0/me/mailFolders":
+			json.NewEncoder(w).Encode(map[string]any{"value": []MailFolder{
+				{ID: "f-inbox", DisplayName: "Inbox", ChildFolderCount: 1, UnreadItemCount: 3},
+				{ID: "f-archive", DisplayName: "Archive"},
+			}})
+		case "/v1.0/me/mailFolders/f-inbox/childFolders":
+			json.NewEncoder(w).Encode(map[string]any{"value": []MailFolder{
+				{ID: "f-projects", DisplayName: "Projects", ParentFolderID: "f-inbox"},
+			}})
+		default:
+			extra(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+}
+
+func TestListFolders(t *testing.T) {
+	o := folderTestServer(t, http.NotFound)
+	folders, err := o.ListFolders(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range folders {
+		paths = append(paths, f.Path)
+	}
+	want := []string{"Inbox", "Inbox/Projects", "Archive"}
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("paths = %v, want %v", paths, want)
+			break
+		}
+	}
+}
+
+func TestResolveFolder(t *testing.T) {
+	o := folderTestServer(t, http.NotFound)
+	ctx := context.Background()
+
+	tests := []struct {
+		name, want string
+	}{
+		{"archive", "f-archive"},
+		{"inbox/projects", "f-projects"},
+		{"Projects", "f-projects"},
+		{"f-inbox", "f-inbox"},
+		{"Sent", "sentitems"},
+	}
+	for _, tt := range tests {
+		f, err := o.ResolveFolder(ctx, tt.name)
+		if err != nil {
+			t.Errorf("ResolveFolder(%q): %v", tt.name, err)
+			continue
+		}
+		if f.ID != tt.want {
+			t.Errorf("ResolveFolder(%q) = %s, want %s", tt.name, f.ID, tt.want)
+		}
+	}
+	if _, err := o.ResolveFolder(ctx, "Nowhere"); err == nil {
+		t.Error("expected error for unknown folder")
+	}
+}
+
+func TestListMessagesInFolder(t *testing.T) {
+	var gotPath string
+	o := folderTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewEncoder(w).Encode(Page[EmailMessage]{Value: []EmailMessage{{ID: "m1"}, {ID: "m2"}}})
+	})
+	msg, err := o.GetMessageByIndexInFolder(context.Background(), "f-projects", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/v1.0/me/mailFolders/f-projects/messages" || msg.ID != "m2" {
+		t.Errorf("got %s from %s", msg.ID, gotPath)
+	}
+}
+
+func TestMoveMessage(t *testing.T) {
+	var gotPath, gotDest string
+	o := folderTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		gotDest = body["destinationId"]
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(EmailMessage{ID: "m1-moved", Subject: "Hello"})
+	})
+	msg, err := o.MoveMessage(context.Background(), "m1", "f-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/v1.0/me/messages/m1/move" || gotDest != "f-archive" {
+		t.Errorf("request %s destination %q", gotPath, gotDest)
+	}
+	if msg.ID != "m1-moved" {
+		t.Errorf("moved ID = %s", msg.ID)
+	}
+}
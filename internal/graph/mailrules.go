@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MessageRule is an inbox rule that runs on incoming mail.
+type MessageRule struct {
+	ID          string         `json:"id,omitempty"`
+	DisplayName string         `json:"displayName"`
+	Sequence    int            `json:"sequence"`
+	IsEnabled   bool           `json:"isEnabled"`
+	Conditions  RuleConditions `json:"conditions"`
+	Actions     RuleActions    `json:"actions"`
+}
+
+// RuleConditions select the messages a rule applies to. All set
+// conditions must match.
+type RuleConditions struct {
+	FromAddresses   []EmailRecipient `json:"fromAddresses,omitempty"`
+	SenderContains  []string         `json:"senderContains,omitempty"`
+	SubjectContains []string         `json:"subjectContains,omitempty"`
+	BodyContains    []string         `json:"bodyContains,omitempty"`
+	HasAttachments  bool             `json:"hasAttachments,omitempty"`
+}
+
+// RuleActions are what a rule does with a matching message.
+type RuleActions struct {
+	MoveToFolder        string   `json:"moveToFolder,omitempty"`
+	AssignCategories    []string `json:"assignCategories,omitempty"`
+	MarkAsRead          bool     `json:"markAsRead,omitempty"`
+	StopProcessingRules bool     `json:"stopProcessingRules,omitempty"`
+}
+
+// Category is an entry in the mailbox's category list.
+type Category struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	Color       string `json:"color"`
+}
+
+func (o *Outlook) rulesEndpoint() string {
+	return o.base() + "/mailFolders/inbox/messageRules"
+}
+
+// ListRules returns the inbox rules in the order they run.
+func (o *Outlook) ListRules(ctx context.Context) ([]MessageRule, error) {
+	return GetAll[MessageRule](ctx, o.Client, o.rulesEndpoint(), "list rules", 0)
+}
+
+// CreateRule adds an inbox rule. A rule without a sequence runs after the
+// existing ones.
+func (o *Outlook) CreateRule(ctx context.Context, rule MessageRule) (*MessageRule, error) {
+	if rule.DisplayName == "" {
+		return nil, fmt.Errorf("rule name is required")
+	}
+	if rule.Sequence <= 0 {
+		rules, err := o.ListRules(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rule.Sequence = 1
+		for _, r := range rules {
+			if r.Sequence >= rule.Sequence {
+				rule.Sequence = r.Sequence + 1
+			}
+		}
+	}
+	var created MessageRule
+	if _, err := doJSON(ctx, o.Client, "POST", o.rulesEndpoint(), "create rule", nil, rule, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteRule removes an inbox rule by ID.
+func (o *Outlook) DeleteRule(ctx context.Context, id string) error {
+	_, err := doJSON(ctx, o.Client, "DELETE", o.rulesEndpoint()+"/"+url.PathEscape(id), "delete rule", nil, nil, nil)
+	return err
+}
+
+// ResolveRule finds an inbox rule by ID or name (case-insensitive).
+func (o *Outlook) ResolveRule(ctx context.Context, ref string) (*MessageRule, error) {
+	rules, err := o.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if rules[i].ID == ref || strings.EqualFold(rules[i].DisplayName, ref) {
+			return &rules[i], nil
+		}
+	}
+	return nil, fmt.Errorf("rule %q not found — run: kit outlook rules list", ref)
+}
+
+// ListCategories returns the mailbox's categories.
+func (o *Outlook) ListCategories(ctx context.Context) ([]Category, error) {
+	return GetAll[Category](ctx, o.Client, o.base()+"/outlook/masterCategories", "list categories", 0)
+}
+
+// EnsureCategories adds any of names missing from the mailbox's category
+// list, so they show up with a color in Outlook. It returns the names that
+// were created.
+func (o *Outlook) EnsureCategories(ctx context.Context, names []string) ([]string, error) {
+	existing, err := o.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	have := map[string]bool{}
+	for _, c := range existing {
+		have[strings.ToLower(c.DisplayName)] = true
+	}
+	var created []string
+	for i, name := range names {
+		if have[strings.ToLower(name)] {
+			continue
+		}
+		// Spread new categories over Outlook's preset colors.
+		color := fmt.Sprintf("preset%d", (len(existing)+i)%25)
+		payload := map[string]string{"displayName": name, "color": color}
+		if _, err := doJSON(ctx, o.Client, "POST", o.base()+"/outlook/masterCategories", "create category", nil, payload, nil); err != nil {
+			return created, err
+		}
+		have[strings.ToLower(name)] = true
+		created = append(created, name)
+	}
+	return created, nil
+}
+
+// SetCategories replaces a message's categories.
+func (o *Outlook) SetCategories(ctx context.Context, messageID string, categories []string) (*EmailMessage, error) {
+	if categories == nil {
+		categories = []string{}
+	}
+	var msg EmailMessage
+	endpoint := o.base() + "/messages/" + url.PathEscape(messageID)
+	if _, err := doJSON(ctx, o.Client, "PATCH", endpoint, "set categories", nil, map[string]any{"categories": categories}, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// MergeCategories returns current with add appended and remove taken out,
+// comparing names case-insensitively and keeping current's order.
+func MergeCategories(current, add, remove []string) []string {
+	drop := map[string]bool{}
+	for _, r := range remove {
+		drop[strings.ToLower(r)] = true
+	}
+	seen := map[string]bool{}
+	out := []string{}
+	for _, c := range append(append([]string{}, current...), add...) {
+		key := strings.ToLower(c)
+		if c == "" || drop[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCreateRule(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/me/mailFolders/inbox/messageRules" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]any{"value": []MessageRule{{ID: "r1", DisplayName: "Old", Sequence: 3}}})
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"r2","displayName":"Invoices","sequence":4,"isEnabled":true}`))
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	rule, err := o.CreateRule(context.Background(), MessageRule{
+		DisplayName: "Invoices",
+		IsEnabled:   true,
+		Conditions:  RuleConditions{SubjectContains: []string{"invoice"}},
+		Actions:     RuleActions{MoveToFolder: "f-invoices", AssignCategories: []string{"Invoices"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.ID != "r2" {
+		t.Errorf("rule = %+v", rule)
+	}
+	if got["sequence"] != float64(4) {
+		t.Errorf("sequence = %v, want after existing rules", got["sequence"])
+	}
+	conditions := got["conditions"].(map[string]any)
+	if _, ok := conditions["fromAddresses"]; ok {
+		t.Errorf("unset conditions should be omitted: %v", conditions)
+	}
+	if got["actions"].(map[string]any)["moveToFolder"] != "f-invoices" {
+		t.Errorf("actions = %v", got["actions"])
+	}
+}
+
+func TestSetCategoriesAndEnsure(t *testing.T) {
+	var patched map[string]any
+	var created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1.0/me/outlook/masterCategories" && r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]any{"value": []Category{{DisplayName: "Red category"}, {DisplayName: "Invoices"}}})
+		case r.URL.Path == "/v1.0/me/outlook/masterCategories":
+			var c Category
+			json.NewDecoder(r.Body).Decode(&c)
+			created = append(created, c.DisplayName)
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/v1.0/me/messages/m1" && r.Method == "PATCH":
+			json.NewDecoder(r.Body).Decode(&patched)
+			w.Write([]byte(`{"id":"m1","categories":["Invoices","Paid"]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	ctx := context.Background()
+	added, err := o.EnsureCategories(ctx, []string{"invoices", "Paid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(added, []string{"Paid"}) || !reflect.DeepEqual(created, []string{"Paid"}) {
+		t.Errorf("created %v / %v", added, created)
+	}
+
+	msg, err := o.SetCategories(ctx, "m1", []string{"Invoices", "Paid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Categories) != 2 || len(patched["categories"].([]any)) != 2 {
+		t.Errorf("categories = %v, sent %v", msg.Categories, patched)
+	}
+}
+
+func TestMergeCategories(t *testing.T) {
+	got := MergeCategories([]string{"Invoices", "Urgent"}, []string{"paid", "invoices"}, []string{"urgent"})
+	if want := []string{"Invoices", "paid"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := MergeCategories(nil, nil, []string{"x"}); got == nil || len(got) != 0 {
+		t.Errorf("clearing should give an empty, non-nil list: %#v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SearchMessages runs a server-side KQL search over the mailbox, e.g.
+// `from:legal hasattachment:true subject:NDA`, or over one folder when
+// folderID is set. Results come back newest first; limit <= 0 defaults
+// to 25.
+func (o *Outlook) SearchMessages(ctx context.Context, query, folderID string, limit int) ([]EmailMessage, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	if limit <= 0 {
+		limit = 25
+	}
+
+	// $search takes the KQL in double quotes, with inner quotes escaped, and
+	// cannot be combined with $orderby.
+	params := url.Values{}
+	params.Set("$search", `"`+strings.ReplaceAll(query, `"`, `\"`)+`"`)
+	params.Set("$top", fmt.Sprintf("%d", min(limit, maxPageSize)))
+	params.Set("$select", messageListFields)
+
+	endpoint := o.base() + "/messages?"
+	if folderID != "" {
+		endpoint = o.base() + "/mailFolders/" + url.PathEscape(folderID) + "/messages?"
+	}
+	endpoint += strings.ReplaceAll(params.Encode(), "+", "%20")
+
+	header := http.Header{}
+	header.Set("ConsistencyLevel", "eventual")
+
+	var messages []EmailMessage
+	err := forEach(ctx, o.Client, endpoint, "search messages", header, func(m EmailMessage) error {
+		messages = append(messages, m)
+		if len(messages) >= limit {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
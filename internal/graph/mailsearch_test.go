@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchMessages(t *testing.T) {
+	var searches []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("ConsistencyLevel"); got != "eventual" {
+			t.Errorf("ConsistencyLevel = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode(map[string]any{"value": []EmailMessage{{ID: "m3"}, {ID: "m4"}}})
+			return
+		}
+		searches = append(searches, r.URL.Query().Get("$search"))
+		if r.URL.Query().Get("$orderby") != "" {
+			t.Error("$search cannot be combined with $orderby")
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"value":           []EmailMessage{{ID: "m1"}, {ID: "m2"}},
+			"@odata.nextLink": server.URL + "/v1.0/me/messages?page=2",
+		})
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	messages, err := o.SearchMessages(context.Background(), `from:legal subject:"NDA draft"`, "", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 3 || messages[2].ID != "m3" {
+		t.Errorf("got %d messages: %+v", len(messages), messages)
+	}
+	if len(searches) != 1 || searches[0] != `"from:legal subject:\"NDA draft\""` {
+		t.Errorf("$search = %q", searches)
+	}
+}
+
+func TestSearchMessagesEmpty(t *testing.T) {
+	o := &Outlook{Client: http.DefaultClient}
+	if _, err := o.SearchMessages(context.Background(), "  ", "", 0); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
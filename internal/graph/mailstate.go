@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Flag states for MessageFlag.FlagStatus.
+const (
+	FlagNotFlagged = "notFlagged"
+	FlagFlagged    = "flagged"
+	FlagComplete   = "complete"
+)
+
+// MessageFlag is a message's follow-up flag.
+type MessageFlag struct {
+	FlagStatus string `json:"flagStatus"`
+}
+
+// FindMessages lists every message in a folder that matches filter, newest
+// first, up to filter.Limit (<= 0 means no cap). Unlike ListMessagesInFolder,
+// filter.From may be part of an address: "notifications@" matches senders
+// starting with it, and "contoso.com" or "@contoso.com" a domain.
+func (o *Outlook) FindMessages(ctx context.Context, folderID string, filter InboxFilter) ([]EmailMessage, error) {
+	from := strings.ToLower(filter.From)
+	partial := from != "" && !isFullAddress(from)
+	if partial {
+		filter.From = ""
+	}
+
+	var found []EmailMessage
+	err := ForEach(ctx, o.Client, o.messagesEndpoint(folderID, filter, maxPageSize), "list messages", func(msg EmailMessage) error {
+		if partial && !matchSender(msg.From.EmailAddress.Address, from) {
+			return nil
+		}
+		found = append(found, msg)
+		if filter.Limit > 0 && len(found) >= filter.Limit {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+func isFullAddress(s string) bool {
+	local, domain, ok := strings.Cut(s, "@")
+	return ok && local != "" && strings.Contains(domain, ".")
+}
+
+// matchSender reports whether address matches a partial sender pattern
+// (lower-cased): a "local@" prefix or a domain.
+func matchSender(address, pattern string) bool {
+	address = strings.ToLower(address)
+	if strings.Contains(pattern, "@") && !strings.HasPrefix(pattern, "@") {
+		return strings.HasPrefix(address, pattern)
+	}
+	return fromDomain(address, strings.TrimPrefix(pattern, "@"))
+}
+
+// SetRead marks messages read or unread. See UpdateMessages.
+func (o *Outlook) SetRead(ctx context.Context, messageIDs []string, read bool) (int, error) {
+	return o.UpdateMessages(ctx, messageIDs, map[string]any{"isRead": read})
+}
+
+// SetFlag sets the follow-up flag of messages to one of FlagFlagged,
+// FlagComplete, or FlagNotFlagged. See UpdateMessages.
+func (o *Outlook) SetFlag(ctx context.Context, messageIDs []string, status string) (int, error) {
+	switch status {
+	case FlagFlagged, FlagComplete, FlagNotFlagged:
+	default:
+		return 0, fmt.Errorf("unknown flag status %q", status)
+	}
+	return o.UpdateMessages(ctx, messageIDs, map[string]any{"flag": MessageFlag{FlagStatus: status}})
+}
+
+// UpdateMessages applies the same PATCH to many messages through $batch,
+// 20 per request. It returns how many were updated; the error, if any,
+// reports how many failed along with the first failure.
+func (o *Outlook) UpdateMessages(ctx context.Context, messageIDs []string, patch map[string]any) (int, error) {
+	if len(messageIDs) == 0 {
+		return 0, nil
+	}
+	prefix := strings.TrimPrefix(o.base(), graphBase())
+	requests := make([]BatchRequest, len(messageIDs))
+	for i, id := range messageIDs {
+		requests[i] = BatchRequest{
+			Method: "PATCH",
+			URL:    prefix + "/messages/" + url.PathEscape(id),
+			Body:   patch,
+		}
+	}
+
+	responses, err := Batch(ctx, o.Client, requests)
+	if err != nil {
+		return 0, err
+	}
+	updated, failed := 0, 0
+	var firstErr error
+	for _, resp := range responses {
+		if resp.OK() {
+			updated++
+			continue
+		}
+		failed++
+		if firstErr == nil {
+			firstErr = resp.Err()
+		}
+	}
+	if failed > 0 {
+		return updated, fmt.Errorf("%d of %d messages could not be updated: %w", failed, len(messageIDs), firstErr)
+	}
+	return updated, nil
+}
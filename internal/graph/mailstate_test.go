@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFindMessagesPartialSender(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("$filter")
+		if strings.Contains(filter, "from/") || !strings.Contains(filter, "isRead eq false") {
+			t.Errorf("$filter = %q", filter)
+		}
+		msg := func(id, from string) map[string]any {
+			return map[string]any{"id": id, "from": map[string]any{"emailAddress": map[string]any{"address": from}}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"value": []any{
+			msg("1", "notifications@github.com"),
+			msg("2", "anna@contoso.com"),
+			msg("3", "Notifications@contoso.com"),
+		}})
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	got, err := o.FindMessages(context.Background(), "", InboxFilter{From: "notifications@", UnreadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "1" || got[1].ID != "3" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestMatchSender(t *testing.T) {
+	tests := []struct {
+		address, pattern string
+		want             bool
+	}{
+		{"notifications@github.com", "notifications@", true},
+		{"no-reply@github.com", "notifications@", false},
+		{"ci@mail.github.com", "github.com", true},
+		{"ci@github.com", "@github.com", true},
+		{"ci@notgithub.com", "github.com", false},
+	}
+	for _, tt := range tests {
+		if got := matchSender(tt.address, tt.pattern); got != tt.want {
+			t.Errorf("matchSender(%q, %q) = %v", tt.address, tt.pattern, got)
+		}
+	}
+}
+
+func TestSetFlag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []BatchRequest `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var responses []BatchResponse
+		for _, req := range body.Requests {
+			if req.Method != "PATCH" || !strings.HasPrefix(req.URL, "/users/shared@contoso.com/messages/") {
+				t.Errorf("request = %s %s", req.Method, req.URL)
+			}
+			data, _ := json.Marshal(req.Body)
+			if string(data) != `{"flag":{"flagStatus":"flagged"}}` {
+				t.Errorf("body = %s", data)
+			}
+			status := 200
+			if strings.HasSuffix(req.URL, "/gone") {
+				status = 404
+			}
+			responses = append(responses, BatchResponse{ID: req.ID, Status: status})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"responses": responses})
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}, Mailbox: "shared@contoso.com"}
+	n, err := o.SetFlag(context.Background(), []string{"m1", "gone", "m2"}, FlagFlagged)
+	if n != 2 || err == nil || !strings.Contains(err.Error(), "1 of 3 messages") {
+		t.Errorf("SetFlag = %d, %v", n, err)
+	}
+
+	if _, err := o.SetFlag(context.Background(), []string{"m1"}, "red"); err == nil {
+		t.Error("expected error for unknown flag status")
+	}
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MeetingRequest describes a Teams meeting to create.
+type MeetingRequest struct {
+	Subject   string
+	Start     time.Time
+	Duration  time.Duration
+	Attendees []string // email addresses
+	Body      string   // optional agenda, plain text
+}
+
+// Meeting is a created Teams meeting.
+type Meeting struct {
+	ID        string    `json:"id"`
+	Subject   string    `json:"subject"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	JoinURL   string    `json:"joinUrl"`
+	WebLink   string    `json:"webLink,omitempty"`
+	Attendees []string  `json:"attendees,omitempty"`
+}
+
+// graphTimeLayout is the dateTime format in Graph dateTimeTimeZone values.
+const graphTimeLayout = "2006-01-02T15:04:05.9999999"
+
+type dateTimeTimeZone struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+func toDateTimeTimeZone(t time.Time) dateTimeTimeZone {
+	return dateTimeTimeZone{DateTime: t.UTC().Format(graphTimeLayout), TimeZone: "UTC"}
+}
+
+// parseDateTimeTimeZone reads a dateTimeTimeZone value. Requests ask for
+// UTC, so other zones are only looked up as a fallback.
+func parseDateTimeTimeZone(d dateTimeTimeZone) time.Time {
+	loc := time.UTC
+	if d.TimeZone != "" && d.TimeZone != "UTC" {
+		if l, err := time.LoadLocation(d.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	t, _ := time.ParseInLocation(graphTimeLayout, d.DateTime, loc)
+	return t
+}
+
+// CreateMeeting creates a Teams meeting on the signed-in user's calendar
+// and sends invitations to the attendees. The meeting is created as a
+// calendar event with an online meeting, so it shows up in everyone's
+// calendar with the join link.
+func (t *Teams) CreateMeeting(ctx context.Context, m MeetingRequest) (*Meeting, error) {
+	if m.Subject == "" {
+		return nil, fmt.Errorf("meeting subject is required")
+	}
+	event, err := NewCalendar(t.Client).CreateEvent(ctx, EventRequest{
+		Subject:   m.Subject,
+		Start:     m.Start,
+		Duration:  m.Duration,
+		Attendees: m.Attendees,
+		Body:      m.Body,
+		Online:    true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create meeting: %w", err)
+	}
+
+	meeting := &Meeting{
+		ID:        event.ID,
+		Subject:   event.Subject,
+		Start:     event.Start,
+		End:       event.End,
+		JoinURL:   event.JoinURL,
+		WebLink:   event.WebLink,
+		Attendees: m.Attendees,
+	}
+	if meeting.JoinURL == "" {
+		return meeting, fmt.Errorf("meeting created but no Teams join link was returned — online meetings may be disabled for your calendar")
+	}
+	return meeting, nil
+}
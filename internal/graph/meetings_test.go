@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateMeeting(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1.0/me/events" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Prefer") != `outlook.timezone="UTC"` {
+			t.Errorf("Prefer = %q", r.Header.Get("Prefer"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt1","subject":"Review",
+			"start":{"dateTime":"2026-10-20T09:00:00.0000000","timeZone":"UTC"},
+			"end":{"dateTime":"2026-10-20T09:45:00.0000000","timeZone":"UTC"},
+			"onlineMeeting":{"joinUrl":"https://teams.microsoft.com/l/meetup-join/abc"}}`))
+	}))
+	defer server.Close()
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	start := time.Date(2026, 10, 20, 11, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	m, err := tc.CreateMeeting(context.Background(), MeetingRequest{
+		Subject:   "Review",
+		Start:     start,
+		Duration:  45 * time.Minute,
+		Attendees: []string{"alice@contoso.com", "bob@contoso.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.JoinURL != "https://teams.microsoft.com/l/meetup-join/abc" {
+		t.Errorf("JoinURL = %q", m.JoinURL)
+	}
+	if !m.Start.Equal(start) || m.End.Sub(m.Start) != 45*time.Minute {
+		t.Errorf("times = %s – %s", m.Start, m.End)
+	}
+	if got["isOnlineMeeting"] != true || got["onlineMeetingProvider"] != "teamsForBusiness" {
+		t.Errorf("meeting not requested as online: %v", got)
+	}
+	if s := got["start"].(map[string]any); s["dateTime"] != "2026-10-20T09:00:00" || s["timeZone"] != "UTC" {
+		t.Errorf("start = %v", s)
+	}
+	if a := got["attendees"].([]any); len(a) != 2 {
+		t.Errorf("attendees = %v", a)
+	}
+}
+
+func TestCreateMeetingWithoutJoinURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt1","subject":"Review"}`))
+	}))
+	defer server.Close()
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	if _, err := tc.CreateMeeting(context.Background(), MeetingRequest{Subject: "Review", Start: time.Now()}); err == nil {
+		t.Error("expected error when no join link is returned")
+	}
+}
@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// get fetches a single Graph object into v.
+func (t *Teams) get(ctx context.Context, endpoint, what string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed (HTTP %d): %s", what, resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, v)
+}
+
+// post sends payload as JSON and decodes the created object into v.
+func (t *Teams) post(ctx context.Context, endpoint, what string, payload, v any) error {
+	data, _ := json.Marshal(payload)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Calendar endpoints then return times in UTC, not the mailbox's zone.
+	req.Header.Set("Prefer", `outlook.timezone="UTC"`)
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed (HTTP %d): %s", what, resp.StatusCode, string(body))
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(body, v)
+}
+
+// LookupUser finds a user by email address or user principal name.
+func (t *Teams) LookupUser(ctx context.Context, email string) (*GraphUser, error) {
+	var u struct {
+		ID                string `json:"id"`
+		DisplayName       string `json:"displayName"`
+		Mail              string `json:"mail"`
+		UserPrincipalName string `json:"userPrincipalName"`
+	}
+	endpoint := graphBase() + "/users/" + url.PathEscape(email) + "?$select=id,displayName,mail,userPrincipalName"
+	if err := t.get(ctx, endpoint, "look up "+email, &u); err != nil {
+		return nil, err
+	}
+	user := &GraphUser{ID: u.ID, DisplayName: u.DisplayName, Email: u.Mail}
+	if user.Email == "" {
+		user.Email = u.UserPrincipalName
+	}
+	return user, nil
+}
+
+// mention is one entry in a message's mentions array.
+type mention struct {
+	ID          int            `json:"id"`
+	MentionText string         `json:"mentionText"`
+	Mentioned   map[string]any `json:"mentioned"`
+}
+
+// mentionMessage builds an HTML message that @mentions users and, with a
+// channel name, the whole channel. A mention replaces "@<email>" or
+// "@channel" where it appears in text and is prepended otherwise.
+func mentionMessage(text string, users []GraphUser, channelID, channelName string) map[string]any {
+	content := html.EscapeString(text)
+	var mentions []mention
+	var prefix []string
+
+	place := func(token, name string, mentioned map[string]any) {
+		id := len(mentions)
+		tag := `<at id="` + strconv.Itoa(id) + `">` + html.EscapeString(name) + `</at>`
+		mentions = append(mentions, mention{ID: id, MentionText: name, Mentioned: mentioned})
+		escaped := html.EscapeString(token)
+		if strings.Contains(content, escaped) {
+			content = strings.Replace(content, escaped, tag, 1)
+		} else {
+			prefix = append(prefix, tag)
+		}
+	}
+
+	if channelName != "" {
+		place("@channel", channelName, map[string]any{
+			"conversation": map[string]string{
+				"id":                       channelID,
+				"displayName":              channelName,
+				"conversationIdentityType": "channel",
+			},
+		})
+	}
+	for _, u := range users {
+		name := u.DisplayName
+		if name == "" {
+			name = u.Email
+		}
+		place("@"+u.Email, name, map[string]any{
+			"user": map[string]string{
+				"id":               u.ID,
+				"displayName":      name,
+				"userIdentityType": "aadUser",
+			},
+		})
+	}
+
+	if len(prefix) > 0 {
+		content = strings.TrimSpace(strings.Join(prefix, " ") + " " + content)
+	}
+	return map[string]any{
+		"body": map[string]string{
+			"contentType": "html",
+			"content":     strings.ReplaceAll(content, "\n", "<br>"),
+		},
+		"mentions": mentions,
+	}
+}
+
+// PostMentionMessage posts a channel message that notifies the given users
+// (by email) and, with mentionChannel, everyone following the channel.
+func (t *Teams) PostMentionMessage(ctx context.Context, teamID, channelID, text string, emails []string, mentionChannel bool) (*ChatMessage, error) {
+	users := make([]GraphUser, 0, len(emails))
+	for _, email := range emails {
+		u, err := t.LookupUser(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("cannot mention %s: %w", email, err)
+		}
+		users = append(users, *u)
+	}
+
+	var channelName string
+	if mentionChannel {
+		var ch Channel
+		endpoint := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "?$select=id,displayName"
+		if err := t.get(ctx, endpoint, "get channel", &ch); err != nil {
+			return nil, err
+		}
+		channelName = ch.DisplayName
+	}
+
+	endpoint := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "/messages"
+	return t.sendMessage(ctx, endpoint, mentionMessage(text, users, channelID, channelName), "post message")
+}
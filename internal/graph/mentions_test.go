@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMentionMessage(t *testing.T) {
+	users := []GraphUser{
+		{ID: "u1", DisplayName: "Alice", Email: "alice@contoso.com"},
+		{ID: "u2", DisplayName: "Bob", Email: "bob@contoso.com"},
+	}
+	msg := mentionMessage("Build <red>, @alice@contoso.com please look", users, "c1", "CI")
+
+	body := msg["body"].(map[string]string)
+	want := `<at id="0">CI</at> <at id="2">Bob</at> Build &lt;red&gt;, <at id="1">Alice</at> please look`
+	if body["contentType"] != "html" || body["content"] != want {
+		t.Errorf("content = %s\nwant      %s", body["content"], want)
+	}
+
+	mentions := msg["mentions"].([]mention)
+	if len(mentions) != 3 {
+		t.Fatalf("mentions = %+v", mentions)
+	}
+	if conv := mentions[0].Mentioned["conversation"].(map[string]string); conv["conversationIdentityType"] != "channel" || conv["id"] != "c1" {
+		t.Errorf("channel mention = %+v", mentions[0])
+	}
+	if user := mentions[1].Mentioned["user"].(map[string]string); user["id"] != "u1" || mentions[1].MentionText != "Alice" {
+		t.Errorf("user mention = %+v", mentions[1])
+	}
+}
+
+func TestPostMentionMessage(t *testing.T) {
+	var sent struct {
+		Body     MessageBody `json:"body"`
+		Mentions []struct {
+			ID        int `json:"id"`
+			Mentioned struct {
+				User struct {
+					ID string `json:"id"`
+				} `json:"user"`
+			} `json:"mentioned"`
+		} `json:"mentions"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/users/alice@contoso.com":
+			w.Write([]byte(`{"id":"u1","displayName":"Alice","mail":"alice@contoso.com"}`))
+		case "/v1.0/teams/t1/channels/c1/messages":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"m1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	if _, err := tc.PostMentionMessage(context.Background(), "t1", "c1", "Ready", []string{"alice@contoso.com"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Body.Content != `<at id="0">Alice</at> Ready` || len(sent.Mentions) != 1 || sent.Mentions[0].Mentioned.User.ID != "u1" {
+		t.Errorf("sent = %+v", sent)
+	}
+
+	if _, err := tc.PostMentionMessage(context.Background(), "t1", "c1", "Ready", []string{"nobody@contoso.com"}, false); err == nil {
+		t.Error("expected error for unknown user")
+	}
+}
@@ -8,13 +8,18 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/klytics/m365kit/internal/cloud"
 )
 
-const graphBase = "https://graph.microsoft.com/v1.0"
+// graphBase() returns the Graph v1.0 endpoint of the configured cloud
+// (azure.cloud or KIT_AZURE_CLOUD).
+func graphBase() string {
+	return cloud.Current().GraphV1()
+}
 
 // DriveItem represents a file or folder in OneDrive.
 type DriveItem struct {
@@ -29,7 +34,15 @@ type DriveItem struct {
 	MimeType         string    `json:"-"`
 	DownloadURL      string    `json:"-"`
 	ParentPath       string    `json:"-"`
+	ParentID         string    `json:"-"`
+	Deleted          bool      `json:"-"` // set in delta results for removed items
 	SharingLink      string    `json:"-"`
+	QuickXorHash     string    `json:"-"`
+	SHA256Hash       string    `json:"-"`
+	RemoteDriveID    string    `json:"-"`
+	RemoteID         string    `json:"-"`
+	SharedBy         string    `json:"-"`
+	ETag             string    `json:"-"`
 }
 
 // UnmarshalJSON implements custom unmarshalling for DriveItem.
@@ -42,13 +55,34 @@ func (d *DriveItem) UnmarshalJSON(data []byte) error {
 		} `json:"folder"`
 		File *struct {
 			MimeType string `json:"mimeType"`
+			Hashes   struct {
+				QuickXorHash string `json:"quickXorHash"`
+				SHA256Hash   string `json:"sha256Hash"`
+			} `json:"hashes"`
 		} `json:"file"`
 		DownloadURL      string `json:"@microsoft.graph.downloadUrl"`
 		ParentReference  *struct {
+			ID   string `json:"id"`
 			Path string `json:"path"`
 		} `json:"parentReference"`
+		Deleted *struct{} `json:"deleted"`
+		RemoteItem *struct {
+			ID              string    `json:"id"`
+			Folder          *struct{} `json:"folder"`
+			ParentReference struct {
+				DriveID string `json:"driveId"`
+			} `json:"parentReference"`
+			Shared struct {
+				Owner struct {
+					User struct {
+						DisplayName string `json:"displayName"`
+					} `json:"user"`
+				} `json:"owner"`
+			} `json:"shared"`
+		} `json:"remoteItem"`
 		LastModified string `json:"lastModifiedDateTime"`
 		Created      string `json:"createdDateTime"`
+		ETag         string `json:"eTag"`
 	}{
 		Alias: (*Alias)(d),
 	}
@@ -63,11 +97,24 @@ func (d *DriveItem) UnmarshalJSON(data []byte) error {
 	}
 	if aux.File != nil {
 		d.MimeType = aux.File.MimeType
+		d.QuickXorHash = aux.File.Hashes.QuickXorHash
+		d.SHA256Hash = aux.File.Hashes.SHA256Hash
 	}
 	d.DownloadURL = aux.DownloadURL
+	d.ETag = aux.ETag
+	if aux.RemoteItem != nil {
+		d.RemoteID = aux.RemoteItem.ID
+		d.RemoteDriveID = aux.RemoteItem.ParentReference.DriveID
+		d.SharedBy = aux.RemoteItem.Shared.Owner.User.DisplayName
+		if aux.RemoteItem.Folder != nil {
+			d.IsFolder = true
+		}
+	}
 	if aux.ParentReference != nil {
+		d.ParentID = aux.ParentReference.ID
 		d.ParentPath = aux.ParentReference.Path
 	}
+	d.Deleted = aux.Deleted != nil
 	if aux.LastModified != "" {
 		if t, err := time.Parse(time.RFC3339, aux.LastModified); err == nil {
 			d.LastModifiedAt = t
@@ -82,14 +129,16 @@ func (d *DriveItem) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-type driveItemsResponse struct {
-	Value    []DriveItem `json:"value"`
-	NextLink string      `json:"@odata.nextLink"`
-}
-
 // OneDrive provides operations on Microsoft OneDrive.
 type OneDrive struct {
 	Client *http.Client
+	// Drive is the Graph path of the target drive, e.g. "/users/{id}/drive"
+	// or "/drives/{id}". Empty means the signed-in user's drive.
+	Drive string
+	// Progress, if set, receives byte counts for uploads and downloads.
+	Progress TransferObserver
+	// Limit caps the number of items returned by listings (0 = all).
+	Limit int
 }
 
 // NewOneDrive creates a new OneDrive client with an authenticated HTTP client.
@@ -97,52 +146,50 @@ func NewOneDrive(client *http.Client) *OneDrive {
 	return &OneDrive{Client: client}
 }
 
+// NewUserOneDrive creates a OneDrive client for another user's drive.
+// userID may be an object ID or user principal name.
+func NewUserOneDrive(client *http.Client, userID string) *OneDrive {
+	return &OneDrive{Client: client, Drive: "/users/" + url.PathEscape(userID) + "/drive"}
+}
+
+// NewDriveByID creates a OneDrive client for a drive addressed by its ID.
+func NewDriveByID(client *http.Client, driveID string) *OneDrive {
+	return &OneDrive{Client: client, Drive: "/drives/" + url.PathEscape(driveID)}
+}
+
+func (o *OneDrive) drivePath() string {
+	if o.Drive == "" {
+		return "/me/drive"
+	}
+	return o.Drive
+}
+
 // ListFolder lists items in a OneDrive folder by path.
 // Use "/" or "" for root.
 func (o *OneDrive) ListFolder(ctx context.Context, folderPath string) ([]DriveItem, error) {
 	var endpoint string
 	folderPath = strings.TrimRight(folderPath, "/")
 	if folderPath == "" || folderPath == "/" {
-		endpoint = graphBase + "/me/drive/root/children"
+		endpoint = graphBase() + o.drivePath() + "/root/children"
 	} else {
-		endpoint = graphBase + "/me/drive/root:/" + url.PathEscape(folderPath) + ":/children"
+		endpoint = graphBase() + o.drivePath() + "/root:/" + url.PathEscape(folderPath) + ":/children"
 	}
 
-	var allItems []DriveItem
-	for endpoint != "" {
-		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		resp, err := o.Client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("OneDrive list request failed: %w", err)
-		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("OneDrive API returned %d: %s", resp.StatusCode, string(body))
-		}
-
-		var result driveItemsResponse
-		if err := json.Unmarshal(body, &result); err != nil {
-			return nil, fmt.Errorf("could not parse OneDrive response: %w", err)
-		}
-
-		allItems = append(allItems, result.Value...)
-		endpoint = result.NextLink
-	}
-
-	return allItems, nil
+	return GetAll[DriveItem](ctx, o.Client, endpoint, "OneDrive list", o.Limit)
 }
 
 // GetItem returns metadata for a single item by path.
 func (o *OneDrive) GetItem(ctx context.Context, itemPath string) (*DriveItem, error) {
 	itemPath = strings.TrimRight(itemPath, "/")
-	endpoint := graphBase + "/me/drive/root:/" + url.PathEscape(itemPath)
+	return o.getItem(ctx, graphBase()+o.drivePath()+"/root:/"+url.PathEscape(itemPath))
+}
+
+// GetItemByID returns metadata for a single item by ID.
+func (o *OneDrive) GetItemByID(ctx context.Context, itemID string) (*DriveItem, error) {
+	return o.getItem(ctx, graphBase()+o.drivePath()+"/items/"+url.PathEscape(itemID))
+}
 
+func (o *OneDrive) getItem(ctx context.Context, endpoint string) (*DriveItem, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
@@ -168,6 +215,8 @@ func (o *OneDrive) GetItem(ctx context.Context, itemPath string) (*DriveItem, er
 }
 
 // DownloadFile downloads a file from OneDrive to a local path.
+// Interrupted downloads resume from <localPath>.partial, and the result is
+// verified against the hash reported by Graph.
 func (o *OneDrive) DownloadFile(ctx context.Context, remotePath, localPath string) (int64, error) {
 	item, err := o.GetItem(ctx, remotePath)
 	if err != nil {
@@ -178,177 +227,173 @@ func (o *OneDrive) DownloadFile(ctx context.Context, remotePath, localPath strin
 		return 0, fmt.Errorf("no download URL available for %s", remotePath)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", item.DownloadURL, nil)
-	if err != nil {
-		return 0, err
-	}
+	return downloadItem(ctx, o.Client, item, localPath, o.Progress)
+}
 
-	resp, err := o.Client.Do(req)
-	if err != nil {
-		return 0, fmt.Errorf("download request failed: %w", err)
-	}
-	defer resp.Body.Close()
+// UploadFile uploads a local file to OneDrive. Files over 4MB are sent
+// through an upload session.
+func (o *OneDrive) UploadFile(ctx context.Context, localPath, remotePath string) (*DriveItem, error) {
+	return uploadFile(ctx, o.Client, graphBase()+o.drivePath()+"/root:/"+url.PathEscape(remotePath), localPath, o.Progress)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("download failed with HTTP %d", resp.StatusCode)
-	}
+// ListSharedWithMe returns items other users have shared with the signed-in user.
+// Use RemoteDriveID with NewDriveByID to browse or download them.
+func (o *OneDrive) ListSharedWithMe(ctx context.Context) ([]DriveItem, error) {
+	return GetAll[DriveItem](ctx, o.Client, graphBase()+"/me/drive/sharedWithMe", "shared with me", o.Limit)
+}
 
-	dir := filepath.Dir(localPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return 0, fmt.Errorf("could not create directory: %w", err)
-	}
+// RecentFiles returns recently accessed files.
+func (o *OneDrive) RecentFiles(ctx context.Context) ([]DriveItem, error) {
+	return GetAll[DriveItem](ctx, o.Client, graphBase()+o.drivePath()+"/recent", "recent files", o.Limit)
+}
 
-	f, err := os.Create(localPath)
-	if err != nil {
-		return 0, fmt.Errorf("could not create local file: %w", err)
-	}
-	defer f.Close()
+// SearchFiles searches for files in OneDrive by query string.
+func (o *OneDrive) SearchFiles(ctx context.Context, query string) ([]DriveItem, error) {
+	return o.Search(ctx, query, SearchOptions{})
+}
 
-	n, err := io.Copy(f, resp.Body)
-	if err != nil {
-		return 0, fmt.Errorf("download copy failed: %w", err)
-	}
+// Sharing link scopes accepted by CreateShareLink.
+const (
+	ShareScopeAnonymous    = "anonymous"
+	ShareScopeOrganization = "organization"
+	ShareScopeUsers        = "users"
+)
 
-	return n, nil
+// ShareOptions configures a sharing link.
+type ShareOptions struct {
+	Type      string    // "view" (default) or "edit"
+	Scope     string    // anonymous (default), organization, or users
+	ExpiresAt time.Time // zero means no expiration
+	Password  string    // only honoured for anonymous links
 }
 
-// UploadFile uploads a local file to OneDrive (files up to 4MB).
-func (o *OneDrive) UploadFile(ctx context.Context, localPath, remotePath string) (*DriveItem, error) {
-	f, err := os.Open(localPath)
-	if err != nil {
-		return nil, fmt.Errorf("could not open local file: %w", err)
+// NormalizeShareScope maps user-facing aliases ("anon", "org") to Graph scope names.
+func NormalizeShareScope(scope string) (string, error) {
+	switch strings.ToLower(scope) {
+	case "", "anon", ShareScopeAnonymous:
+		return ShareScopeAnonymous, nil
+	case "org", ShareScopeOrganization:
+		return ShareScopeOrganization, nil
+	case ShareScopeUsers:
+		return ShareScopeUsers, nil
+	default:
+		return "", fmt.Errorf("invalid share scope %q — use anonymous, organization, or users", scope)
 	}
-	defer f.Close()
+}
 
-	info, err := f.Stat()
+// CreateShareLink creates a sharing link for a file.
+func (o *OneDrive) CreateShareLink(ctx context.Context, itemPath string, opts ShareOptions) (string, error) {
+	if opts.Type == "" {
+		opts.Type = "view"
+	}
+	scope, err := NormalizeShareScope(opts.Scope)
 	if err != nil {
-		return nil, fmt.Errorf("could not stat local file: %w", err)
+		return "", err
 	}
-
-	if info.Size() > 4*1024*1024 {
-		return nil, fmt.Errorf("file too large for simple upload (%d bytes, max 4MB) — use OneDrive web for large files", info.Size())
+	if opts.Password != "" && scope != ShareScopeAnonymous {
+		return "", fmt.Errorf("link passwords are only supported for anonymous links")
 	}
 
-	endpoint := graphBase + "/me/drive/root:/" + url.PathEscape(remotePath) + ":/content"
-	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, f)
+	item, err := o.GetItem(ctx, itemPath)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	req.Header.Set("Content-Type", "application/octet-stream")
 
-	resp, err := o.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("upload request failed: %w", err)
+	reqBody := map[string]any{
+		"type":  opts.Type,
+		"scope": scope,
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("upload failed (HTTP %d): %s", resp.StatusCode, string(body))
+	if !opts.ExpiresAt.IsZero() {
+		reqBody["expirationDateTime"] = opts.ExpiresAt.UTC().Format(time.RFC3339)
 	}
-
-	var item DriveItem
-	if err := json.Unmarshal(body, &item); err != nil {
-		return nil, fmt.Errorf("could not parse upload response: %w", err)
+	if opts.Password != "" {
+		reqBody["password"] = opts.Password
+	}
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
 	}
 
-	return &item, nil
-}
-
-// RecentFiles returns recently accessed files.
-func (o *OneDrive) RecentFiles(ctx context.Context) ([]DriveItem, error) {
-	endpoint := graphBase + "/me/drive/recent"
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	endpoint := graphBase() + o.drivePath() + "/items/" + item.ID + "/createLink"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(string(payload)))
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := o.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("recent files request failed: %w", err)
+		return "", fmt.Errorf("share link request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OneDrive API returned %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("share link failed (HTTP %d): %s", resp.StatusCode, string(body))
 	}
 
-	var result driveItemsResponse
+	var result struct {
+		Link struct {
+			WebURL string `json:"webUrl"`
+		} `json:"link"`
+	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not parse recent files: %w", err)
+		return "", fmt.Errorf("could not parse share link response: %w", err)
 	}
 
-	return result.Value, nil
+	return result.Link.WebURL, nil
 }
 
-// SearchFiles searches for files in OneDrive by query string.
-func (o *OneDrive) SearchFiles(ctx context.Context, query string) ([]DriveItem, error) {
-	endpoint := graphBase + "/me/drive/root/search(q='" + url.QueryEscape(query) + "')"
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+// ListShareLinks returns the sharing links on a file.
+func (o *OneDrive) ListShareLinks(ctx context.Context, itemPath string) ([]Permission, error) {
+	item, err := o.GetItem(ctx, itemPath)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := o.Client.Do(req)
+	endpoint := graphBase() + o.drivePath() + "/items/" + item.ID + "/permissions"
+	perms, err := GetAll[Permission](ctx, o.Client, endpoint, "permissions", 0)
 	if err != nil {
-		return nil, fmt.Errorf("search request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OneDrive API returned %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result driveItemsResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not parse search results: %w", err)
+		return nil, err
 	}
-
-	return result.Value, nil
+	return FilterLinks(perms), nil
 }
 
-// CreateShareLink creates a sharing link for a file.
-func (o *OneDrive) CreateShareLink(ctx context.Context, itemPath, linkType string) (string, error) {
-	if linkType == "" {
-		linkType = "view"
-	}
-
+// RevokeShareLink deletes a sharing link (permission) from a file.
+func (o *OneDrive) RevokeShareLink(ctx context.Context, itemPath, permissionID string) error {
 	item, err := o.GetItem(ctx, itemPath)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	endpoint := graphBase + "/me/drive/items/" + item.ID + "/createLink"
-	payload := fmt.Sprintf(`{"type":"%s","scope":"anonymous"}`, linkType)
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(payload))
+	endpoint := graphBase() + o.drivePath() + "/items/" + item.ID + "/permissions/" + url.PathEscape(permissionID)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", endpoint, nil)
 	if err != nil {
-		return "", err
+		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := o.Client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("share link request failed: %w", err)
+		return fmt.Errorf("revoke request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("share link failed (HTTP %d): %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("revoke failed (HTTP %d): %s", resp.StatusCode, string(body))
 	}
+	return nil
+}
 
-	var result struct {
-		Link struct {
-			WebURL string `json:"webUrl"`
-		} `json:"link"`
-	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("could not parse share link response: %w", err)
+// FilterLinks returns only the permissions that are sharing links.
+func FilterLinks(perms []Permission) []Permission {
+	var links []Permission
+	for _, p := range perms {
+		if p.Link != nil {
+			links = append(links, p)
+		}
 	}
-
-	return result.Link.WebURL, nil
+	return links
 }
 
 // FormatSize returns a human-readable file size string.
@@ -364,3 +409,36 @@ func FormatSize(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
+
+// ParseSince reads a point in the past given as an age counted back from
+// now ("30d", "12h") or as a date ("2006-01-02" or RFC 3339). Every --since,
+// --modified-after and --modified-before flag is read with it.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	return parseWhen(s, now, -1)
+}
+
+// ParseUntil is ParseSince looking forward: "7d" is a week from now. The
+// duration must be positive.
+func ParseUntil(s string, now time.Time) (time.Time, error) {
+	return parseWhen(s, now, 1)
+}
+
+// parseWhen reads a duration from now, in direction dir (-1 or 1), or a
+// date.
+func parseWhen(s string, now time.Time, dir int) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && (n > 0 || n == 0 && dir < 0) {
+			return now.AddDate(0, 0, dir*n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && (d > 0 || d == 0 && dir < 0) {
+		return now.Add(time.Duration(dir) * d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a duration like 7d or 12h, or a date like 2006-01-02", s)
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestFormatSize(t *testing.T) {
@@ -30,6 +31,52 @@ func TestFormatSize(t *testing.T) {
 	}
 }
 
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"30d", now.AddDate(0, 0, -30)},
+		{"0d", now},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"2025-12-01", time.Date(2025, 12, 1, 0, 0, 0, 0, time.Local)},
+		{"2025-12-01T00:00:00Z", time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseSince("last week", now); err == nil {
+		t.Error("expected error for unparseable value")
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7d", now.AddDate(0, 0, 7)},
+		{"12h", now.Add(12 * time.Hour)},
+		{"2026-03-15", time.Date(2026, 3, 15, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := ParseUntil(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseUntil(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"soon", "-3d", "0d", "-1h"} {
+		if _, err := ParseUntil(in, now); err == nil {
+			t.Errorf("ParseUntil(%q) expected error", in)
+		}
+	}
+}
+
 func TestDriveItemUnmarshalFolder(t *testing.T) {
 	raw := `{
 		"id": "folder-1",
@@ -122,7 +169,7 @@ func TestListFolderRoot(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result driveItemsResponse
+	var result Page[DriveItem]
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		t.Fatalf("decode failed: %v", err)
 	}
@@ -132,32 +179,6 @@ func TestListFolderRoot(t *testing.T) {
 	}
 }
 
-func TestUploadFileTooLarge(t *testing.T) {
-	// Create a file larger than 4MB
-	dir := t.TempDir()
-	largePath := filepath.Join(dir, "large.bin")
-	f, err := os.Create(largePath)
-	if err != nil {
-		t.Fatal(err)
-	}
-	// Write 5MB of zeros
-	buf := make([]byte, 5*1024*1024)
-	if _, err := f.Write(buf); err != nil {
-		t.Fatal(err)
-	}
-	f.Close()
-
-	od := &OneDrive{Client: http.DefaultClient}
-	ctx := context.Background()
-	_, err = od.UploadFile(ctx, largePath, "large.bin")
-	if err == nil {
-		t.Fatal("expected error for file > 4MB")
-	}
-	if !containsStr(err.Error(), "too large") {
-		t.Errorf("expected 'too large' error, got: %s", err.Error())
-	}
-}
-
 func TestUploadFileNotExist(t *testing.T) {
 	od := &OneDrive{Client: http.DefaultClient}
 	ctx := context.Background()
@@ -236,7 +257,7 @@ func TestSearchFilesResponse(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result driveItemsResponse
+	var result Page[DriveItem]
 	json.NewDecoder(resp.Body).Decode(&result)
 	if len(result.Value) != 2 {
 		t.Errorf("expected 2 results, got %d", len(result.Value))
@@ -295,3 +316,114 @@ func containsStr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestNormalizeShareScope(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ShareScopeAnonymous},
+		{"anon", ShareScopeAnonymous},
+		{"org", ShareScopeOrganization},
+		{"Organization", ShareScopeOrganization},
+		{"users", ShareScopeUsers},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeShareScope(tt.in)
+		if err != nil {
+			t.Errorf("NormalizeShareScope(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeShareScope(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := NormalizeShareScope("public"); err == nil {
+		t.Error("expected error for invalid scope")
+	}
+}
+
+func TestCreateShareLinkPasswordRequiresAnonymous(t *testing.T) {
+	od := &OneDrive{Client: http.DefaultClient}
+	_, err := od.CreateShareLink(context.Background(), "doc.docx", ShareOptions{
+		Scope:    "org",
+		Password: "secret",
+	})
+	if err == nil {
+		t.Fatal("expected error for password on organization link")
+	}
+	if !containsStr(err.Error(), "anonymous") {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+}
+
+func TestFilterLinks(t *testing.T) {
+	raw := `{"value": [
+		{"id": "p1", "roles": ["owner"], "grantedToV2": {"user": {"email": "me@company.com"}}},
+		{"id": "p2", "roles": ["read"], "link": {"scope": "anonymous", "type": "view", "webUrl": "https://1drv.ms/x"},
+		 "expirationDateTime": "2026-03-01T00:00:00Z", "hasPassword": true}
+	]}`
+
+	var result Page[Permission]
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	links := FilterLinks(result.Value)
+	if len(links) != 1 {
+		t.Fatalf("expected 1 link, got %d", len(links))
+	}
+	if links[0].ID != "p2" {
+		t.Errorf("ID = %q", links[0].ID)
+	}
+	if links[0].ExpiresAt == nil || links[0].ExpiresAt.Year() != 2026 {
+		t.Errorf("ExpiresAt = %v", links[0].ExpiresAt)
+	}
+	if !links[0].HasPassword {
+		t.Error("expected HasPassword=true")
+	}
+}
+
+func TestOneDriveDrivePath(t *testing.T) {
+	if got := NewOneDrive(nil).drivePath(); got != "/me/drive" {
+		t.Errorf("default drivePath = %q", got)
+	}
+	if got := NewUserOneDrive(nil, "jane@company.com").drivePath(); got != "/users/jane@company.com/drive" {
+		t.Errorf("user drivePath = %q", got)
+	}
+	if got := NewDriveByID(nil, "b!abc123").drivePath(); got != "/drives/b%21abc123" {
+		t.Errorf("drive drivePath = %q", got)
+	}
+}
+
+func TestDriveItemUnmarshalRemoteItem(t *testing.T) {
+	raw := `{
+		"id": "local-1",
+		"name": "Budget",
+		"size": 2048,
+		"remoteItem": {
+			"id": "remote-9",
+			"folder": {"childCount": 3},
+			"parentReference": {"driveId": "b!drive-42", "driveType": "business"},
+			"shared": {"owner": {"user": {"displayName": "Alex Wilber"}}}
+		}
+	}`
+
+	var item DriveItem
+	if err := json.Unmarshal([]byte(raw), &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.RemoteID != "remote-9" {
+		t.Errorf("RemoteID = %q", item.RemoteID)
+	}
+	if item.RemoteDriveID != "b!drive-42" {
+		t.Errorf("RemoteDriveID = %q", item.RemoteDriveID)
+	}
+	if item.SharedBy != "Alex Wilber" {
+		t.Errorf("SharedBy = %q", item.SharedBy)
+	}
+	if !item.IsFolder {
+		t.Error("expected IsFolder=true from remoteItem.folder")
+	}
+}
@@ -26,6 +26,8 @@ type EmailMessage struct {
 	IsRead         bool           `json:"isRead"`
 	HasAttachments bool           `json:"hasAttachments"`
 	WebLink        string         `json:"webLink,omitempty"`
+	Categories     []string       `json:"categories,omitempty"`
+	Flag           *MessageFlag   `json:"flag,omitempty"`
 }
 
 // EmailRecipient holds an email address with display name.
@@ -47,6 +49,7 @@ type EmailBody struct {
 
 // Attachment represents an email attachment.
 type Attachment struct {
+	ODataType      string `json:"@odata.type,omitempty"` // file, item, or reference attachment
 	ID             string `json:"id"`
 	Name           string `json:"name"`
 	ContentType    string `json:"contentType"`
@@ -61,6 +64,7 @@ type InboxFilter struct {
 	Subject       string
 	HasAttachment bool
 	UnreadOnly    bool
+	FlaggedOnly   bool
 	Since         time.Time
 	Limit         int
 }
@@ -68,6 +72,11 @@ type InboxFilter struct {
 // Outlook provides Microsoft Outlook operations via Graph API.
 type Outlook struct {
 	Client *http.Client
+	// Mailbox is the address or user ID of a shared or delegated mailbox to
+	// work in. Empty means the signed-in user's own mailbox.
+	Mailbox string
+	// Progress, if set, is told about attachment download progress.
+	Progress TransferObserver
 }
 
 // NewOutlook creates a new Outlook client.
@@ -75,28 +84,54 @@ func NewOutlook(client *http.Client) *Outlook {
 	return &Outlook{Client: client}
 }
 
-type messagesResponse struct {
-	Value []EmailMessage `json:"value"`
+// base returns the Graph URL of the mailbox's owner: /me, or /users/{id}
+// when Mailbox is set.
+func (o *Outlook) base() string {
+	if o.Mailbox == "" {
+		return graphBase() + "/me"
+	}
+	return graphBase() + "/users/" + url.PathEscape(o.Mailbox)
 }
 
-type attachmentsResponse struct {
-	Value []Attachment `json:"value"`
-}
+// messageListFields are the message properties fetched for listings.
+const messageListFields = "id,subject,from,toRecipients,receivedDateTime,isRead,hasAttachments,webLink,categories,flag"
 
 // ListInbox returns recent emails with optional filters.
 func (o *Outlook) ListInbox(ctx context.Context, filter InboxFilter) ([]EmailMessage, error) {
+	return o.ListMessagesInFolder(ctx, "", filter)
+}
+
+// ListMessagesInFolder returns recent emails in one mail folder, given by ID
+// or well-known name (see ResolveFolder). An empty folder lists the default
+// messages view, as ListInbox does.
+func (o *Outlook) ListMessagesInFolder(ctx context.Context, folderID string, filter InboxFilter) ([]EmailMessage, error) {
 	limit := filter.Limit
 	if limit <= 0 {
 		limit = 20
 	}
-	if limit > 50 {
-		limit = 50
+
+	// Larger limits are fetched across pages
+	what := "inbox"
+	if folderID != "" {
+		what = "list folder messages"
 	}
+	return GetAll[EmailMessage](ctx, o.Client, o.messagesEndpoint(folderID, filter, min(limit, maxPageSize)), what, limit)
+}
+
+// EachMessageInFolder streams every message in a folder that matches
+// filter, newest first, following pages until the listing ends or fn
+// returns an error. filter.Limit is ignored.
+func (o *Outlook) EachMessageInFolder(ctx context.Context, folderID string, filter InboxFilter, fn func(EmailMessage) error) error {
+	return ForEach(ctx, o.Client, o.messagesEndpoint(folderID, filter, maxPageSize), "list folder messages", fn)
+}
 
+// messagesEndpoint builds the newest-first message listing URL for a folder
+// ("" for the default messages view) with filter applied as OData $filter.
+func (o *Outlook) messagesEndpoint(folderID string, filter InboxFilter, top int) string {
 	params := url.Values{}
-	params.Set("$top", fmt.Sprintf("%d", limit))
+	params.Set("$top", fmt.Sprintf("%d", top))
 	params.Set("$orderby", "receivedDateTime desc")
-	params.Set("$select", "id,subject,from,toRecipients,receivedDateTime,isRead,hasAttachments,webLink")
+	params.Set("$select", messageListFields)
 
 	// Build OData filter
 	var filters []string
@@ -112,6 +147,9 @@ func (o *Outlook) ListInbox(ctx context.Context, filter InboxFilter) ([]EmailMes
 	if filter.UnreadOnly {
 		filters = append(filters, "isRead eq false")
 	}
+	if filter.FlaggedOnly {
+		filters = append(filters, "flag/flagStatus eq 'flagged'")
+	}
 	if !filter.Since.IsZero() {
 		filters = append(filters, fmt.Sprintf("receivedDateTime ge %s", filter.Since.Format(time.RFC3339)))
 	}
@@ -119,33 +157,15 @@ func (o *Outlook) ListInbox(ctx context.Context, filter InboxFilter) ([]EmailMes
 		params.Set("$filter", strings.Join(filters, " and "))
 	}
 
-	endpoint := graphBase + "/me/messages?" + params.Encode()
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := o.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("could not list inbox: %w", err)
+	if folderID != "" {
+		return o.base() + "/mailFolders/" + url.PathEscape(folderID) + "/messages?" + params.Encode()
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("inbox request failed (%d): %s", resp.StatusCode, string(body))
-	}
-
-	var result messagesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("could not parse inbox response: %w", err)
-	}
-	return result.Value, nil
+	return o.base() + "/messages?" + params.Encode()
 }
 
 // GetMessage retrieves a single email by ID.
 func (o *Outlook) GetMessage(ctx context.Context, id string) (*EmailMessage, error) {
-	endpoint := graphBase + "/me/messages/" + url.PathEscape(id)
+	endpoint := o.base() + "/messages/" + url.PathEscape(id)
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
@@ -171,93 +191,94 @@ func (o *Outlook) GetMessage(ctx context.Context, id string) (*EmailMessage, err
 
 // GetMessageByIndex retrieves the Nth message from inbox (1-indexed).
 func (o *Outlook) GetMessageByIndex(ctx context.Context, n int) (*EmailMessage, error) {
+	return o.GetMessageByIndexInFolder(ctx, "", n)
+}
+
+// GetMessageByIndexInFolder retrieves the Nth message (1-indexed) as listed
+// by ListMessagesInFolder.
+func (o *Outlook) GetMessageByIndexInFolder(ctx context.Context, folderID string, n int) (*EmailMessage, error) {
 	if n < 1 {
 		return nil, fmt.Errorf("message index must be >= 1, got %d", n)
 	}
-	messages, err := o.ListInbox(ctx, InboxFilter{Limit: n})
+	messages, err := o.ListMessagesInFolder(ctx, folderID, InboxFilter{Limit: n})
 	if err != nil {
 		return nil, err
 	}
 	if n > len(messages) {
-		return nil, fmt.Errorf("message index %d out of range (inbox has %d messages)", n, len(messages))
+		where := "inbox"
+		if folderID != "" {
+			where = "folder"
+		}
+		return nil, fmt.Errorf("message index %d out of range (%s has %d messages)", n, where, len(messages))
 	}
 	return &messages[n-1], nil
 }
 
-// ListAttachments returns attachments for a message.
+// ListAttachments returns attachments for a message, without their content.
 func (o *Outlook) ListAttachments(ctx context.Context, messageID string) ([]Attachment, error) {
-	endpoint := graphBase + "/me/messages/" + url.PathEscape(messageID) + "/attachments"
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := o.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("could not list attachments: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("list attachments failed (%d): %s", resp.StatusCode, string(body))
-	}
-
-	var result attachmentsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("could not parse attachments: %w", err)
-	}
-	return result.Value, nil
+	endpoint := o.base() + "/messages/" + url.PathEscape(messageID) + "/attachments?$select=" + attachmentInfoFields
+	return GetAll[Attachment](ctx, o.Client, endpoint, "list attachments", 0)
 }
 
-// DownloadAttachment downloads an attachment to a local directory.
-// Returns the local file path written.
-func (o *Outlook) DownloadAttachment(ctx context.Context, messageID, attachmentID, destDir string) (string, error) {
-	endpoint := graphBase + "/me/messages/" + url.PathEscape(messageID) + "/attachments/" + url.PathEscape(attachmentID)
+// GetAttachment retrieves an attachment with its decoded content, held in
+// memory. Use StreamAttachment or DownloadAttachment for large files.
+func (o *Outlook) GetAttachment(ctx context.Context, messageID, attachmentID string) (*Attachment, []byte, error) {
+	endpoint := o.attachmentEndpoint(messageID, attachmentID)
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
 	resp, err := o.Client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("could not download attachment: %w", err)
+		return nil, nil, fmt.Errorf("could not download attachment: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("download attachment failed (%d): %s", resp.StatusCode, string(body))
+		return nil, nil, fmt.Errorf("download attachment failed (%d): %s", resp.StatusCode, string(body))
 	}
 
 	var att Attachment
 	if err := json.NewDecoder(resp.Body).Decode(&att); err != nil {
-		return "", fmt.Errorf("could not parse attachment: %w", err)
+		return nil, nil, fmt.Errorf("could not parse attachment: %w", err)
 	}
 
 	if att.ContentBytes == "" {
-		return "", fmt.Errorf("attachment %s has no content", att.Name)
+		return nil, nil, fmt.Errorf("attachment %s has no content", att.Name)
 	}
 
 	decoded, err := base64.StdEncoding.DecodeString(att.ContentBytes)
 	if err != nil {
-		return "", fmt.Errorf("could not decode attachment content: %w", err)
+		return nil, nil, fmt.Errorf("could not decode attachment content: %w", err)
+	}
+	att.ContentBytes = ""
+	return &att, decoded, nil
+}
+
+// DownloadAttachment streams an attachment into a local directory.
+// Returns the local file path written.
+func (o *Outlook) DownloadAttachment(ctx context.Context, messageID, attachmentID, destDir string) (string, error) {
+	att, err := o.GetAttachmentInfo(ctx, messageID, attachmentID)
+	if err != nil {
+		return "", err
 	}
 
 	if err := os.MkdirAll(destDir, 0755); err != nil {
 		return "", fmt.Errorf("could not create output directory: %w", err)
 	}
 
-	outPath := filepath.Join(destDir, att.Name)
-	if err := os.WriteFile(outPath, decoded, 0644); err != nil {
-		return "", fmt.Errorf("could not write attachment: %w", err)
+	outPath := filepath.Join(destDir, AttachmentFileName(*att))
+	if _, err := o.SaveAttachment(ctx, messageID, *att, outPath); err != nil {
+		return "", err
 	}
 	return outPath, nil
 }
 
 // MarkAsRead marks a message as read.
 func (o *Outlook) MarkAsRead(ctx context.Context, messageID string) error {
-	endpoint := graphBase + "/me/messages/" + url.PathEscape(messageID)
+	endpoint := o.base() + "/messages/" + url.PathEscape(messageID)
 	body := []byte(`{"isRead": true}`)
 	req, err := http.NewRequestWithContext(ctx, "PATCH", endpoint, bytes.NewReader(body))
 	if err != nil {
@@ -280,7 +301,31 @@ func (o *Outlook) MarkAsRead(ctx context.Context, messageID string) error {
 
 // Reply sends a reply to a message.
 func (o *Outlook) Reply(ctx context.Context, messageID, bodyText string) error {
-	endpoint := graphBase + "/me/messages/" + url.PathEscape(messageID) + "/reply"
+	return o.reply(ctx, messageID, "reply", bodyText)
+}
+
+// ReplyAll sends a reply to the sender and all recipients of a message.
+func (o *Outlook) ReplyAll(ctx context.Context, messageID, bodyText string) error {
+	return o.reply(ctx, messageID, "replyAll", bodyText)
+}
+
+// Forward forwards a message to the given recipients, with an optional
+// comment above the forwarded content.
+func (o *Outlook) Forward(ctx context.Context, messageID string, to []string, comment string) error {
+	if len(to) == 0 {
+		return fmt.Errorf("forward needs at least one recipient")
+	}
+	payload := map[string]any{
+		"comment":      comment,
+		"toRecipients": recipientList(to),
+	}
+	endpoint := o.base() + "/messages/" + url.PathEscape(messageID) + "/forward"
+	_, err := doJSON(ctx, o.Client, "POST", endpoint, "forward", nil, payload, nil)
+	return err
+}
+
+func (o *Outlook) reply(ctx context.Context, messageID, action, bodyText string) error {
+	endpoint := o.base() + "/messages/" + url.PathEscape(messageID) + "/" + action
 	payload := map[string]any{
 		"message": map[string]any{
 			"body": map[string]string{
@@ -289,28 +334,8 @@ func (o *Outlook) Reply(ctx context.Context, messageID, bodyText string) error {
 			},
 		},
 	}
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonData))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := o.Client.Do(req)
-	if err != nil {
-		return fmt.Errorf("could not reply: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("reply failed (%d): %s", resp.StatusCode, string(respBody))
-	}
-	return nil
+	_, err := doJSON(ctx, o.Client, "POST", endpoint, "reply", nil, payload, nil)
+	return err
 }
 
 // IsOfficeAttachment returns true if the attachment is an Office document.
@@ -39,7 +39,7 @@ func TestListInboxResponse(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result messagesResponse
+	var result Page[EmailMessage]
 	json.NewDecoder(resp.Body).Decode(&result)
 	if len(result.Value) != 5 {
 		t.Errorf("expected 5 messages, got %d", len(result.Value))
@@ -53,7 +53,7 @@ func TestListInboxFromFilter(t *testing.T) {
 		decoded, _ := url.QueryUnescape(r.URL.String())
 		receivedURL = decoded
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(messagesResponse{Value: []EmailMessage{}})
+		json.NewEncoder(w).Encode(Page[EmailMessage]{Value: []EmailMessage{}})
 	}))
 	defer server.Close()
 
@@ -72,7 +72,7 @@ func TestListInboxHasAttachmentFilter(t *testing.T) {
 		decoded, _ := url.QueryUnescape(r.URL.String())
 		receivedURL = decoded
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(messagesResponse{Value: []EmailMessage{}})
+		json.NewEncoder(w).Encode(Page[EmailMessage]{Value: []EmailMessage{}})
 	}))
 	defer server.Close()
 
@@ -150,6 +150,10 @@ func TestDownloadAttachmentWritesFile(t *testing.T) {
 	}
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$value") {
+			w.Write(content)
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(att)
 	}))
@@ -234,6 +238,68 @@ func TestReplyRequest(t *testing.T) {
 	}
 }
 
+func TestReplyAllAndForward(t *testing.T) {
+	var paths []string
+	var forwarded map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/forward") {
+			json.NewDecoder(r.Body).Decode(&forwarded)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	ctx := context.Background()
+
+	if err := o.ReplyAll(ctx, "msg-1", "Thanks all"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Forward(ctx, "msg-1", []string{"carol@contoso.com"}, "FYI"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Forward(ctx, "msg-1", nil, "FYI"); err == nil {
+		t.Error("expected error forwarding without recipients")
+	}
+
+	if len(paths) != 2 || paths[0] != "/v1.0/me/messages/msg-1/replyAll" || paths[1] != "/v1.0/me/messages/msg-1/forward" {
+		t.Errorf("paths = %v", paths)
+	}
+	if forwarded["comment"] != "FYI" || len(forwarded["toRecipients"].([]any)) != 1 {
+		t.Errorf("forward payload = %v", forwarded)
+	}
+}
+
+func TestSharedMailboxEndpoints(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]any{"value": []EmailMessage{{ID: "m1"}}})
+	}))
+	defer server.Close()
+
+	o := &Outlook{
+		Client:  &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}},
+		Mailbox: "finance@contoso.com",
+	}
+	ctx := context.Background()
+	if _, err := o.ListMessagesInFolder(ctx, "archive", InboxFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.ListRules(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"/v1.0/users/finance@contoso.com/mailFolders/archive/messages",
+		"/v1.0/users/finance@contoso.com/mailFolders/inbox/messageRules",
+	}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
 func TestIsOfficeAttachment(t *testing.T) {
 	tests := []struct {
 		name string
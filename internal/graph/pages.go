@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SitePage is a SharePoint modern page.
+type SitePage struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Title           string `json:"title"`
+	WebURL          string `json:"webUrl"`
+	PublishingState struct {
+		Level string `json:"level"`
+	} `json:"publishingState"`
+}
+
+// Pages creates and publishes SharePoint modern pages.
+type Pages struct {
+	Client *http.Client
+}
+
+// NewPages creates a new Pages client.
+func NewPages(client *http.Client) *Pages {
+	return &Pages{Client: client}
+}
+
+// PageName returns the page file name for a title, e.g.
+// "Release Notes 2.0" → "release-notes-2-0.aspx".
+func PageName(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		name = "page"
+	}
+	return name + ".aspx"
+}
+
+// Find returns the page with the given file name, or nil if there is none.
+func (p *Pages) Find(ctx context.Context, siteID, name string) (*SitePage, error) {
+	endpoint := graphBase() + "/sites/" + siteID + "/pages/microsoft.graph.sitePage?$select=id,name,title,webUrl,publishingState"
+	pages, err := GetAll[SitePage](ctx, p.Client, endpoint, "list pages", 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pages {
+		if strings.EqualFold(pages[i].Name, name) {
+			return &pages[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Create adds a draft page with one text web part per HTML section.
+func (p *Pages) Create(ctx context.Context, siteID, name, title string, sections []string) (*SitePage, error) {
+	payload := map[string]any{
+		"@odata.type":  "#microsoft.graph.sitePage",
+		"name":         name,
+		"title":        title,
+		"pageLayout":   "article",
+		"showComments": true,
+		"canvasLayout": pageCanvas(sections),
+	}
+	return p.send(ctx, "POST", graphBase()+"/sites/"+siteID+"/pages", payload, http.StatusCreated, "create page")
+}
+
+// Update replaces a page's title and content. The page stays a draft
+// until it is published again.
+func (p *Pages) Update(ctx context.Context, siteID, pageID, title string, sections []string) (*SitePage, error) {
+	payload := map[string]any{
+		"@odata.type":  "#microsoft.graph.sitePage",
+		"title":        title,
+		"canvasLayout": pageCanvas(sections),
+	}
+	endpoint := graphBase() + "/sites/" + siteID + "/pages/" + pageID + "/microsoft.graph.sitePage"
+	return p.send(ctx, "PATCH", endpoint, payload, http.StatusOK, "update page")
+}
+
+// Publish makes the current draft of a page visible to readers.
+func (p *Pages) Publish(ctx context.Context, siteID, pageID string) error {
+	endpoint := graphBase() + "/sites/" + siteID + "/pages/" + pageID + "/microsoft.graph.sitePage/publish"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("publish request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("publish page failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func (p *Pages) send(ctx context.Context, method, endpoint string, payload any, want int, what string) (*SitePage, error) {
+	data, _ := json.Marshal(payload)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != want && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s failed (HTTP %d): %s", what, resp.StatusCode, string(body))
+	}
+
+	var page SitePage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("could not parse page: %w", err)
+	}
+	return &page, nil
+}
+
+// pageCanvas lays out each HTML section as a full-width text web part.
+func pageCanvas(sections []string) map[string]any {
+	horizontal := make([]map[string]any, 0, len(sections))
+	for i, html := range sections {
+		id := strconv.Itoa(i + 1)
+		horizontal = append(horizontal, map[string]any{
+			"id":       id,
+			"layout":   "oneColumn",
+			"emphasis": "none",
+			"columns": []map[string]any{{
+				"id":    "1",
+				"width": 12,
+				"webparts": []map[string]any{{
+					"@odata.type": "#microsoft.graph.textWebPart",
+					"innerHtml":   html,
+				}},
+			}},
+		})
+	}
+	return map[string]any{"horizontalSections": horizontal}
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPageName(t *testing.T) {
+	tests := map[string]string{
+		"Release Notes 2.0":    "release-notes-2-0.aspx",
+		"  Q3: Plan & Review!": "q3-plan-review.aspx",
+		"???":                  "page.aspx",
+	}
+	for in, want := range tests {
+		if got := PageName(in); got != want {
+			t.Errorf("PageName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPagesCreateAndPublish(t *testing.T) {
+	var created map[string]any
+	var published bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1.0/sites/s/pages":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]any{"id": "p1", "name": created["name"], "title": created["title"]})
+		case "POST /v1.0/sites/s/pages/p1/microsoft.graph.sitePage/publish":
+			published = true
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /v1.0/sites/s/pages/microsoft.graph.sitePage":
+			json.NewEncoder(w).Encode(map[string]any{"value": []any{
+				map[string]any{"id": "p0", "name": "Home.aspx"},
+				map[string]any{"id": "p1", "name": "release-notes.aspx"},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	p := NewPages(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	ctx := context.Background()
+
+	page, err := p.Create(ctx, "s", "release-notes.aspx", "Release Notes", []string{"<p>a</p>", "<h2>B</h2>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.ID != "p1" || page.Title != "Release Notes" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	sections := created["canvasLayout"].(map[string]any)["horizontalSections"].([]any)
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(sections))
+	}
+	part := sections[1].(map[string]any)["columns"].([]any)[0].(map[string]any)["webparts"].([]any)[0].(map[string]any)
+	if part["@odata.type"] != "#microsoft.graph.textWebPart" || part["innerHtml"] != "<h2>B</h2>" {
+		t.Errorf("unexpected web part: %v", part)
+	}
+
+	if err := p.Publish(ctx, "s", page.ID); err != nil {
+		t.Fatal(err)
+	}
+	if !published {
+		t.Error("publish endpoint was not called")
+	}
+
+	found, err := p.Find(ctx, "s", "Release-Notes.aspx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.ID != "p1" {
+		t.Errorf("Find = %+v", found)
+	}
+	if found, _ := p.Find(ctx, "s", "missing.aspx"); found != nil {
+		t.Errorf("expected nil for missing page, got %+v", found)
+	}
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxPageSize is the largest $top requested when a caller sets $top itself.
+const maxPageSize = 100
+
+// Page is one page of a Graph collection response.
+type Page[T any] struct {
+	Value    []T    `json:"value"`
+	NextLink string `json:"@odata.nextLink"`
+}
+
+// errStopPaging ends ForEach early without reporting an error.
+var errStopPaging = fmt.Errorf("stop paging")
+
+// GetAll fetches a Graph collection and follows @odata.nextLink until every
+// page has been read or limit items have been collected (limit <= 0 means
+// no cap). what names the collection in error messages, e.g. "sites".
+func GetAll[T any](ctx context.Context, client *http.Client, endpoint, what string, limit int) ([]T, error) {
+	var all []T
+	err := ForEach(ctx, client, endpoint, what, func(item T) error {
+		all = append(all, item)
+		if limit > 0 && len(all) >= limit {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// ForEach streams a Graph collection, calling fn for each item and following
+// @odata.nextLink, so only one page is held in memory at a time. Returning
+// errStopPaging from fn ends the walk early; any other error is returned.
+func ForEach[T any](ctx context.Context, client *http.Client, endpoint, what string, fn func(T) error) error {
+	return forEach(ctx, client, endpoint, what, nil, fn)
+}
+
+// forEach is ForEach with extra request headers sent on every page, for
+// queries such as $search that Graph only honors with a header set.
+func forEach[T any](ctx context.Context, client *http.Client, endpoint, what string, header http.Header, fn func(T) error) error {
+	for endpoint != "" {
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("%s request failed: %w", what, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s request failed (HTTP %d): %s", what, resp.StatusCode, string(body))
+		}
+
+		var page Page[T]
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("could not parse %s response: %w", what, err)
+		}
+
+		for _, item := range page.Value {
+			if err := fn(item); err == errStopPaging {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+		endpoint = page.NextLink
+	}
+	return nil
+}
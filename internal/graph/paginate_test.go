@@ -0,0 +1,144 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// pagedServer serves pages in order, linking them with @odata.nextLink.
+func pagedServer(t *testing.T, pages [][]Team, requests *int) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		idx, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		resp := map[string]any{"value": pages[idx]}
+		if idx+1 < len(pages) {
+			resp["@odata.nextLink"] = server.URL + r.URL.Path + "?page=" + strconv.Itoa(idx+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return server
+}
+
+func TestGetAllFollowsNextLink(t *testing.T) {
+	pages := [][]Team{
+		{{ID: "1"}, {ID: "2"}},
+		{{ID: "3"}, {ID: "4"}},
+		{{ID: "5"}},
+	}
+	var requests int
+	server := pagedServer(t, pages, &requests)
+	defer server.Close()
+
+	tc := &Teams{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	teams, err := tc.ListTeams(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(teams) != 5 || teams[4].ID != "5" {
+		t.Errorf("expected 5 teams across 3 pages, got %v", teams)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestGetAllStopsAtLimit(t *testing.T) {
+	pages := [][]Team{
+		{{ID: "1"}, {ID: "2"}},
+		{{ID: "3"}, {ID: "4"}},
+		{{ID: "5"}},
+	}
+	var requests int
+	server := pagedServer(t, pages, &requests)
+	defer server.Close()
+
+	tc := &Teams{
+		Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}},
+		Limit:  3,
+	}
+	teams, err := tc.ListTeams(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(teams) != 3 {
+		t.Errorf("expected 3 teams, got %d", len(teams))
+	}
+	if requests != 2 {
+		t.Errorf("expected to stop after 2 requests, got %d", requests)
+	}
+}
+
+func TestGetAllError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":"accessDenied"}}`))
+	}))
+	defer server.Close()
+
+	_, err := GetAll[Site](context.Background(), server.Client(), server.URL+"/sites", "SharePoint sites", 0)
+	if err == nil {
+		t.Fatal("expected error for HTTP 403")
+	}
+	if !strings.Contains(err.Error(), "SharePoint sites") || !strings.Contains(err.Error(), "403") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestListInboxPagesBeyondPageSize(t *testing.T) {
+	var tops []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tops = append(tops, r.URL.Query().Get("$top"))
+		msgs := make([]EmailMessage, maxPageSize)
+		resp := map[string]any{"value": msgs, "@odata.nextLink": server.URL + "/v1.0/me/messages?$skip=100"}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	o := &Outlook{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	msgs, err := o.ListInbox(context.Background(), InboxFilter{Limit: 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 150 {
+		t.Errorf("expected 150 messages, got %d", len(msgs))
+	}
+	if len(tops) != 2 || tops[0] != "100" {
+		t.Errorf("expected 2 requests starting with $top=100, got %v", tops)
+	}
+}
+
+func TestForEachStopsEarly(t *testing.T) {
+	pages := [][]Team{
+		{{ID: "1"}, {ID: "2"}},
+		{{ID: "3"}, {ID: "4"}},
+		{{ID: "5"}},
+	}
+	var requests int
+	server := pagedServer(t, pages, &requests)
+	defer server.Close()
+
+	client := &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}
+	var seen []string
+	err := ForEach(context.Background(), client, graphBase()+"/teams", "teams", func(team Team) error {
+		seen = append(seen, team.ID)
+		if team.ID == "3" {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(seen, ",") != "1,2,3" || requests != 2 {
+		t.Errorf("seen %v after %d requests, want 1,2,3 after 2", seen, requests)
+	}
+}
@@ -0,0 +1,267 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Person is someone found by a people or contacts search.
+type Person struct {
+	ID          string   `json:"id"`
+	DisplayName string   `json:"displayName"`
+	Email       string   `json:"email,omitempty"`
+	Phones      []string `json:"phones,omitempty"`
+	JobTitle    string   `json:"jobTitle,omitempty"`
+	Department  string   `json:"department,omitempty"`
+	Company     string   `json:"company,omitempty"`
+	// Source is "people" (the directory and people you work with) or
+	// "contacts" (your Outlook contacts).
+	Source string `json:"source"`
+}
+
+// People searches the signed-in user's relevant people and Outlook contacts.
+type People struct {
+	Client *http.Client
+	// Limit caps the number of results from each source (default 10).
+	Limit int
+}
+
+// NewPeople creates a new People client.
+func NewPeople(client *http.Client) *People {
+	return &People{Client: client}
+}
+
+func (p *People) limit() int {
+	if p.Limit <= 0 {
+		return 10
+	}
+	return p.Limit
+}
+
+// Search finds people by name or email: first those Graph ranks as relevant
+// to the user (colleagues and frequent correspondents), then matching Outlook
+// contacts not already found.
+func (p *People) Search(ctx context.Context, query string) ([]Person, error) {
+	found, err := p.SearchPeople(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := p.SearchContacts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, person := range found {
+		seen[strings.ToLower(person.Email)] = true
+	}
+	for _, c := range contacts {
+		if c.Email == "" || !seen[strings.ToLower(c.Email)] {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+// SearchPeople searches /me/people, which ranks the directory and the
+// user's contacts by how much they work with the user.
+func (p *People) SearchPeople(ctx context.Context, query string) ([]Person, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+
+	params := url.Values{}
+	params.Set("$search", `"`+strings.ReplaceAll(query, `"`, `\"`)+`"`)
+	params.Set("$top", fmt.Sprintf("%d", min(p.limit(), maxPageSize)))
+	params.Set("$select", "id,displayName,scoredEmailAddresses,phones,jobTitle,department,companyName")
+	endpoint := graphBase() + "/me/people?" + strings.ReplaceAll(params.Encode(), "+", "%20")
+
+	type graphPerson struct {
+		ID                   string `json:"id"`
+		DisplayName          string `json:"displayName"`
+		ScoredEmailAddresses []struct {
+			Address string `json:"address"`
+		} `json:"scoredEmailAddresses"`
+		Phones []struct {
+			Number string `json:"number"`
+		} `json:"phones"`
+		JobTitle    string `json:"jobTitle"`
+		Department  string `json:"department"`
+		CompanyName string `json:"companyName"`
+	}
+	found, err := GetAll[graphPerson](ctx, p.Client, endpoint, "search people", p.limit())
+	if err != nil {
+		return nil, err
+	}
+	people := make([]Person, 0, len(found))
+	for _, g := range found {
+		person := Person{
+			ID:          g.ID,
+			DisplayName: g.DisplayName,
+			JobTitle:    g.JobTitle,
+			Department:  g.Department,
+			Company:     g.CompanyName,
+			Source:      "people",
+		}
+		if len(g.ScoredEmailAddresses) > 0 {
+			person.Email = g.ScoredEmailAddresses[0].Address
+		}
+		for _, ph := range g.Phones {
+			if ph.Number != "" {
+				person.Phones = append(person.Phones, ph.Number)
+			}
+		}
+		people = append(people, person)
+	}
+	return people, nil
+}
+
+// SearchContacts finds Outlook contacts whose name or email address starts
+// with query.
+func (p *People) SearchContacts(ctx context.Context, query string) ([]Person, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+
+	q := strings.ReplaceAll(query, "'", "''")
+	filter := fmt.Sprintf("startswith(displayName,'%s') or startswith(givenName,'%s') or startswith(surname,'%s') or emailAddresses/any(a:startswith(a/address,'%s'))", q, q, q, q)
+	params := url.Values{}
+	params.Set("$filter", filter)
+	params.Set("$top", fmt.Sprintf("%d", min(p.limit(), maxPageSize)))
+	params.Set("$select", "id,displayName,emailAddresses,businessPhones,mobilePhone,homePhones,jobTitle,department,companyName")
+	endpoint := graphBase() + "/me/contacts?" + strings.ReplaceAll(params.Encode(), "+", "%20")
+
+	type contact struct {
+		ID             string      `json:"id"`
+		DisplayName    string      `json:"displayName"`
+		EmailAddresses []EmailAddr `json:"emailAddresses"`
+		BusinessPhones []string    `json:"businessPhones"`
+		MobilePhone    string      `json:"mobilePhone"`
+		HomePhones     []string    `json:"homePhones"`
+		JobTitle       string      `json:"jobTitle"`
+		Department     string      `json:"department"`
+		CompanyName    string      `json:"companyName"`
+	}
+	found, err := GetAll[contact](ctx, p.Client, endpoint, "search contacts", p.limit())
+	if err != nil {
+		return nil, err
+	}
+	people := make([]Person, 0, len(found))
+	for _, c := range found {
+		person := Person{
+			ID:          c.ID,
+			DisplayName: c.DisplayName,
+			JobTitle:    c.JobTitle,
+			Department:  c.Department,
+			Company:     c.CompanyName,
+			Source:      "contacts",
+		}
+		if len(c.EmailAddresses) > 0 {
+			person.Email = c.EmailAddresses[0].Address
+		}
+		if c.MobilePhone != "" {
+			person.Phones = append(person.Phones, c.MobilePhone)
+		}
+		person.Phones = append(person.Phones, c.BusinessPhones...)
+		person.Phones = append(person.Phones, c.HomePhones...)
+		people = append(people, person)
+	}
+	return people, nil
+}
+
+// NeedsLookup reports whether any recipient is a name rather than an email
+// address, so ResolveRecipients has to search for it.
+func NeedsLookup(recipients []string) bool {
+	for _, r := range recipients {
+		if !strings.Contains(r, "@") {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveRecipients replaces names in recipients (anything without an @)
+// with the email address of the one person they match. Addresses are
+// returned unchanged.
+func (p *People) ResolveRecipients(ctx context.Context, recipients []string) ([]string, error) {
+	out := make([]string, len(recipients))
+	for i, r := range recipients {
+		if strings.Contains(r, "@") {
+			out[i] = r
+			continue
+		}
+		person, err := p.ResolveName(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = person.Email
+	}
+	return out, nil
+}
+
+// ResolveAddresses resolves the names among recipients with
+// People.ResolveRecipients, writing a line to notes for each one (e.g.
+// Resolved "Anna K" → anna.k@contoso.com). It makes no requests when every
+// recipient is already an address.
+func ResolveAddresses(ctx context.Context, client *http.Client, recipients []string, notes io.Writer) ([]string, error) {
+	if !NeedsLookup(recipients) {
+		return recipients, nil
+	}
+	resolved, err := NewPeople(client).ResolveRecipients(ctx, recipients)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range recipients {
+		if resolved[i] != r {
+			fmt.Fprintf(notes, "Resolved %q → %s\n", r, resolved[i])
+		}
+	}
+	return resolved, nil
+}
+
+// ResolveName finds the one person with an email address that name refers
+// to: an exact display-name match, or the only search result.
+func (p *People) ResolveName(ctx context.Context, name string) (*Person, error) {
+	found, err := p.Search(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Person
+	seen := map[string]bool{}
+	for _, person := range found {
+		key := strings.ToLower(person.Email)
+		if person.Email != "" && !seen[key] {
+			seen[key] = true
+			candidates = append(candidates, person)
+		}
+	}
+
+	var exact []Person
+	for _, c := range candidates {
+		if strings.EqualFold(c.DisplayName, strings.TrimSpace(name)) {
+			exact = append(exact, c)
+		}
+	}
+	switch {
+	case len(exact) == 1:
+		return &exact[0], nil
+	case len(candidates) == 1:
+		return &candidates[0], nil
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("no one with an email address matches %q — use an email address", name)
+	}
+
+	if len(exact) > 1 {
+		candidates = exact
+	}
+	names := make([]string, 0, min(len(candidates), 5))
+	for _, c := range candidates[:min(len(candidates), 5)] {
+		names = append(names, fmt.Sprintf("%s <%s>", c.DisplayName, c.Email))
+	}
+	return nil, fmt.Errorf("%q matches several people (%s) — use an email address or a fuller name", name, strings.Join(names, ", "))
+}
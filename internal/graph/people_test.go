@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// peopleServer serves /me/people and /me/contacts results for tests.
+func peopleServer(t *testing.T, people, contacts []map[string]any) *People {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/me/people":
+			if !strings.HasPrefix(r.URL.Query().Get("$search"), `"`) {
+				t.Errorf("$search = %q, want a quoted query", r.URL.Query().Get("$search"))
+			}
+			json.NewEncoder(w).Encode(map[string]any{"value": people})
+		case "/v1.0/me/contacts":
+			if !strings.Contains(r.URL.Query().Get("$filter"), "startswith(displayName,") {
+				t.Errorf("$filter = %q", r.URL.Query().Get("$filter"))
+			}
+			json.NewEncoder(w).Encode(map[string]any{"value": contacts})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &People{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+}
+
+func TestPeopleSearch(t *testing.T) {
+	p := peopleServer(t,
+		[]map[string]any{{
+			"id": "p1", "displayName": "Anna Kowalski", "jobTitle": "Controller",
+			"scoredEmailAddresses": []map[string]any{{"address": "anna.k@contoso.com"}},
+			"phones":               []map[string]any{{"type": "business", "number": "+1 555 0100"}},
+		}},
+		[]map[string]any{
+			{"id": "c1", "displayName": "Anna K.", "emailAddresses": []map[string]any{{"address": "ANNA.K@contoso.com"}}},
+			{"id": "c2", "displayName": "Anna Berg", "emailAddresses": []map[string]any{{"address": "anna@fabrikam.com"}}, "mobilePhone": "+46 70 000"},
+		})
+
+	got, err := p.Search(context.Background(), "Anna")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d people, want the duplicate contact dropped: %+v", len(got), got)
+	}
+	if got[0].Email != "anna.k@contoso.com" || got[0].JobTitle != "Controller" || !reflect.DeepEqual(got[0].Phones, []string{"+1 555 0100"}) || got[0].Source != "people" {
+		t.Errorf("person = %+v", got[0])
+	}
+	if got[1].Email != "anna@fabrikam.com" || got[1].Source != "contacts" || got[1].Phones[0] != "+46 70 000" {
+		t.Errorf("contact = %+v", got[1])
+	}
+}
+
+func TestResolveRecipients(t *testing.T) {
+	p := peopleServer(t,
+		[]map[string]any{
+			{"id": "p1", "displayName": "Anna Kowalski", "scoredEmailAddresses": []map[string]any{{"address": "anna.k@contoso.com"}}},
+			{"id": "p2", "displayName": "Anna Kim", "scoredEmailAddresses": []map[string]any{{"address": "anna.kim@contoso.com"}}},
+		}, nil)
+	ctx := context.Background()
+
+	got, err := p.ResolveRecipients(ctx, []string{"bob@contoso.com", "anna kowalski"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"bob@contoso.com", "anna.k@contoso.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	_, err = p.ResolveRecipients(ctx, []string{"Anna K"})
+	if err == nil || !strings.Contains(err.Error(), "several people") || !strings.Contains(err.Error(), "anna.kim@contoso.com") {
+		t.Errorf("ambiguous name: err = %v", err)
+	}
+}
+
+func TestNeedsLookup(t *testing.T) {
+	if NeedsLookup([]string{"a@b.com", "c@d.com"}) {
+		t.Error("addresses should not need lookup")
+	}
+	if !NeedsLookup([]string{"a@b.com", "Anna K"}) {
+		t.Error("a name should need lookup")
+	}
+}
+
+func TestResolveAddresses(t *testing.T) {
+	p := peopleServer(t, []map[string]any{
+		{"id": "p1", "displayName": "Anna Kowalski", "scoredEmailAddresses": []map[string]any{{"address": "anna.k@contoso.com"}}},
+	}, nil)
+	ctx := context.Background()
+
+	var notes strings.Builder
+	got, err := ResolveAddresses(ctx, p.Client, []string{"bob@contoso.com", "Anna Kowalski"}, &notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"bob@contoso.com", "anna.k@contoso.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if notes.String() != "Resolved \"Anna Kowalski\" → anna.k@contoso.com\n" {
+		t.Errorf("notes = %q", notes.String())
+	}
+
+	// Addresses alone never reach Graph
+	got, err = ResolveAddresses(ctx, nil, []string{"bob@contoso.com"}, &notes)
+	if err != nil || !reflect.DeepEqual(got, []string{"bob@contoso.com"}) {
+		t.Errorf("got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// QueuedPost is one message for PostQueue to send.
+type QueuedPost struct {
+	Team    string          `json:"team"`
+	Channel string          `json:"channel"`
+	Message string          `json:"message,omitempty"`
+	Card    json.RawMessage `json:"card,omitempty"`
+}
+
+// PostResult reports what happened to one queued post.
+type PostResult struct {
+	Index     int    `json:"index"`
+	Team      string `json:"team"`
+	Channel   string `json:"channel"`
+	Status    string `json:"status"` // "posted" or "failed"
+	MessageID string `json:"messageId,omitempty"`
+	Attempts  int    `json:"attempts"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PostQueue sends many channel posts without tripping Graph throttling:
+// posts to the same channel are spaced Interval apart and sent in order,
+// different channels are posted to in parallel, and throttled requests are
+// retried as Graph asks.
+type PostQueue struct {
+	Client *http.Client
+	// Interval is the minimum gap between posts to one channel (default 1s).
+	Interval time.Duration
+	// MaxRetries is how often a throttled post is retried (default 5).
+	MaxRetries int
+	// Parallel caps how many channels are posted to at once (default 4).
+	Parallel int
+}
+
+type channelKey struct{ team, channel string }
+
+// Run sends the posts and returns a result for each, in input order.
+// progress, if set, is called as each post finishes.
+func (q *PostQueue) Run(ctx context.Context, posts []QueuedPost, progress func(PostResult)) []PostResult {
+	interval := q.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	parallel := q.Parallel
+	if parallel <= 0 {
+		parallel = 4
+	}
+
+	// Group by channel, keeping each channel's posts in order.
+	var order []channelKey
+	groups := make(map[channelKey][]int)
+	for i, p := range posts {
+		k := channelKey{p.Team, p.Channel}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	results := make([]PostResult, len(posts))
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, parallel)
+	)
+	report := func(r PostResult) {
+		mu.Lock()
+		results[r.Index] = r
+		if progress != nil {
+			progress(r)
+		}
+		mu.Unlock()
+	}
+
+	for _, k := range order {
+		wg.Add(1)
+		go func(k channelKey, indexes []int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			q.runChannel(ctx, k, indexes, posts, interval, report)
+		}(k, groups[k])
+	}
+	wg.Wait()
+	return results
+}
+
+// runChannel posts one channel's messages in order, Interval apart.
+func (q *PostQueue) runChannel(ctx context.Context, k channelKey, indexes []int, posts []QueuedPost, interval time.Duration, report func(PostResult)) {
+	retries := 0
+	base := q.Client.Transport
+	tc := NewTeams(&http.Client{
+		Transport: &RetryTransport{
+			Base:       base,
+			MaxRetries: q.MaxRetries,
+			OnRetry:    func(int, time.Duration, int) { retries++ },
+		},
+		Timeout: q.Client.Timeout,
+	})
+
+	fail := func(i int, err error, attempts int) {
+		report(PostResult{Index: i, Team: k.team, Channel: k.channel, Status: "failed", Attempts: attempts, Error: err.Error()})
+	}
+
+	teamID, err := tc.ResolveTeamID(ctx, k.team)
+	var channelID string
+	if err == nil {
+		channelID, err = tc.ResolveChannelID(ctx, teamID, k.channel)
+	}
+	if err != nil {
+		for _, i := range indexes {
+			fail(i, err, 0)
+		}
+		return
+	}
+
+	var last time.Time
+	for _, i := range indexes {
+		if wait := interval - time.Since(last); !last.IsZero() && wait > 0 {
+			select {
+			case <-ctx.Done():
+				fail(i, ctx.Err(), 0)
+				continue
+			case <-time.After(wait):
+			}
+		}
+		if ctx.Err() != nil {
+			fail(i, ctx.Err(), 0)
+			continue
+		}
+
+		retries = 0
+		p := posts[i]
+		var msg *ChatMessage
+		if p.Card != nil {
+			msg, err = tc.PostCard(ctx, teamID, channelID, p.Message, p.Card)
+		} else {
+			msg, err = tc.PostMessage(ctx, teamID, channelID, p.Message)
+		}
+		last = time.Now()
+
+		if err != nil {
+			fail(i, err, retries+1)
+			continue
+		}
+		report(PostResult{Index: i, Team: k.team, Channel: k.channel, Status: "posted", MessageID: msg.ID, Attempts: retries + 1})
+	}
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPostQueue(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		posted    = map[string][]string{}
+		throttled bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1.0/me/joinedTeams":
+			w.Write([]byte(`{"value":[{"id":"11111111-1111-1111-1111-111111111111","displayName":"Eng"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/channels") && r.Method == "GET":
+			w.Write([]byte(`{"value":[{"id":"c-general","displayName":"general"},{"id":"c-ops","displayName":"ops"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/messages"):
+			mu.Lock()
+			defer mu.Unlock()
+			parts := strings.Split(r.URL.Path, "/")
+			channel := parts[len(parts)-2]
+			if channel == "c-ops" && !throttled {
+				throttled = true
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			var body struct {
+				Body MessageBody `json:"body"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			posted[channel] = append(posted[channel], body.Body.Content)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"m-` + body.Body.Content + `"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	q := &PostQueue{
+		Client:   &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}},
+		Interval: time.Millisecond,
+	}
+	results := q.Run(context.Background(), []QueuedPost{
+		{Team: "Eng", Channel: "general", Message: "1"},
+		{Team: "Eng", Channel: "ops", Message: "2"},
+		{Team: "Eng", Channel: "general", Message: "3"},
+		{Team: "Eng", Channel: "nope", Message: "4"},
+	}, nil)
+
+	if got := strings.Join(posted["c-general"], ","); got != "1,3" {
+		t.Errorf("general got %q, want posts in order", got)
+	}
+	if results[0].Status != "posted" || results[0].MessageID != "m-1" || results[0].Attempts != 1 {
+		t.Errorf("result 0 = %+v", results[0])
+	}
+	if results[1].Status != "posted" || results[1].Attempts != 2 {
+		t.Errorf("throttled post = %+v", results[1])
+	}
+	if results[3].Status != "failed" || results[3].Error == "" || results[3].Index != 3 {
+		t.Errorf("unknown channel = %+v", results[3])
+	}
+}
@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// presenceBatchSize is the most user IDs getPresencesByUserId accepts.
+const presenceBatchSize = 650
+
+// UserPresence is a user's Teams availability.
+type UserPresence struct {
+	Email        string `json:"email"`
+	DisplayName  string `json:"displayName"`
+	Availability string `json:"availability"` // Available, Busy, DoNotDisturb, BeRightBack, Away, Offline, ...
+	Activity     string `json:"activity"`     // InACall, InAMeeting, Presenting, ...
+}
+
+// IsAvailable reports whether the user is online and free to chat.
+func (p UserPresence) IsAvailable() bool {
+	return p.Availability == "Available" || p.Availability == "AvailableIdle"
+}
+
+// GetPresence returns the presence of each user, in the order given.
+func (t *Teams) GetPresence(ctx context.Context, emails []string) ([]UserPresence, error) {
+	result := make([]UserPresence, len(emails))
+	index := make(map[string]int, len(emails))
+	var ids []string
+	for i, email := range emails {
+		u, err := t.LookupUser(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = UserPresence{Email: u.Email, DisplayName: u.DisplayName, Availability: "PresenceUnknown"}
+		index[u.ID] = i
+		ids = append(ids, u.ID)
+	}
+
+	for start := 0; start < len(ids); start += presenceBatchSize {
+		batch := ids[start:min(start+presenceBatchSize, len(ids))]
+		var resp struct {
+			Value []struct {
+				ID           string `json:"id"`
+				Availability string `json:"availability"`
+				Activity     string `json:"activity"`
+			} `json:"value"`
+		}
+		_, err := doJSON(ctx, t.Client, "POST", graphBase()+"/communications/getPresencesByUserId", "get presence",
+			nil, map[string]any{"ids": batch}, &resp)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range resp.Value {
+			if i, ok := index[p.ID]; ok {
+				result[i].Availability = p.Availability
+				result[i].Activity = p.Activity
+			}
+		}
+	}
+	return result, nil
+}
+
+// SearchUsers finds people in the directory whose name or email starts
+// with query.
+func (t *Teams) SearchUsers(ctx context.Context, query string) ([]GraphUser, error) {
+	q := strings.ReplaceAll(query, "'", "''")
+	filter := fmt.Sprintf("startswith(displayName,'%s') or startswith(givenName,'%s') or startswith(surname,'%s') or startswith(mail,'%s')", q, q, q, q)
+	endpoint := graphBase() + "/users?$select=id,displayName,mail,userPrincipalName&$filter=" + strings.ReplaceAll(url.QueryEscape(filter), "+", "%20")
+
+	type user struct {
+		ID                string `json:"id"`
+		DisplayName       string `json:"displayName"`
+		Mail              string `json:"mail"`
+		UserPrincipalName string `json:"userPrincipalName"`
+	}
+	found, err := GetAll[user](ctx, t.Client, endpoint, "user search", t.Limit)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]GraphUser, 0, len(found))
+	for _, u := range found {
+		email := u.Mail
+		if email == "" {
+			email = u.UserPrincipalName
+		}
+		users = append(users, GraphUser{ID: u.ID, DisplayName: u.DisplayName, Email: email})
+	}
+	return users, nil
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetPresence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1.0/users/alice@contoso.com":
+			w.Write([]byte(`{"id":"u-alice","displayName":"Alice","mail":"alice@contoso.com"}`))
+		case r.URL.Path == "/v1.0/users/bob@contoso.com":
+			w.Write([]byte(`{"id":"u-bob","displayName":"Bob","mail":"bob@contoso.com"}`))
+		case r.URL.Path == "/v1.0/communications/getPresencesByUserId":
+			var req struct {
+				IDs []string `json:"ids"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if len(req.IDs) != 2 {
+				t.Errorf("ids = %v", req.IDs)
+			}
+			w.Write([]byte(`{"value":[
+				{"id":"u-bob","availability":"Busy","activity":"InAMeeting"},
+				{"id":"u-alice","availability":"Available","activity":"Available"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	presence, err := tc.GetPresence(context.Background(), []string{"alice@contoso.com", "bob@contoso.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(presence) != 2 || presence[0].DisplayName != "Alice" || !presence[0].IsAvailable() {
+		t.Errorf("alice = %+v", presence[0])
+	}
+	if presence[1].Availability != "Busy" || presence[1].Activity != "InAMeeting" || presence[1].IsAvailable() {
+		t.Errorf("bob = %+v", presence[1])
+	}
+
+	if _, err := tc.GetPresence(context.Background(), []string{"nobody@contoso.com"}); err == nil {
+		t.Error("expected error for unknown user")
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	var filter string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("$filter")
+		w.Write([]byte(`{"value":[
+			{"id":"1","displayName":"Sean O'Brien","mail":"sean@contoso.com"},
+			{"id":"2","displayName":"Sean Guest","userPrincipalName":"guest_ext#EXT#@contoso.onmicrosoft.com"}]}`))
+	}))
+	defer server.Close()
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	users, err := tc.SearchUsers(context.Background(), "O'Brien")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(filter, "startswith(displayName,'O''Brien')") {
+		t.Errorf("filter = %q", filter)
+	}
+	if len(users) != 2 || users[0].Email != "sean@contoso.com" || users[1].Email == "" {
+		t.Errorf("users = %+v", users)
+	}
+}
@@ -0,0 +1,245 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// copyPollInterval is how often CopyItem checks an asynchronous copy.
+var copyPollInterval = time.Second
+
+// errItemExists is returned by createFolder when the folder already exists.
+var errItemExists = fmt.Errorf("item already exists")
+
+// CreateFolder creates folderPath in a library. The parent folder must
+// exist. If the folder already exists it is returned unchanged.
+func (sp *SharePoint) CreateFolder(ctx context.Context, siteID, driveID, folderPath string) (*DriveItem, error) {
+	item, err := sp.createFolder(ctx, siteID, driveID, folderPath)
+	if err == errItemExists {
+		return sp.GetLibraryItem(ctx, siteID, driveID, folderPath)
+	}
+	return item, err
+}
+
+func (sp *SharePoint) createFolder(ctx context.Context, siteID, driveID, folderPath string) (*DriveItem, error) {
+	folderPath = strings.Trim(folderPath, "/")
+	if folderPath == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+	parent, name := path.Split(folderPath)
+
+	payload, _ := json.Marshal(map[string]any{
+		"name":                              name,
+		"folder":                            map[string]any{},
+		"@microsoft.graph.conflictBehavior": "fail",
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST", libraryChildrenURL(siteID, driveID, parent), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := sp.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("create folder request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errItemExists
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("create folder %s failed (HTTP %d): %s", folderPath, resp.StatusCode, string(body))
+	}
+
+	var item DriveItem
+	if err := json.Unmarshal(body, &item); err != nil {
+		return nil, fmt.Errorf("could not parse folder: %w", err)
+	}
+	return &item, nil
+}
+
+// CopyItem copies a file or folder (with its contents) into dstFolder of
+// another library and waits for the copy to finish. Drive IDs are global,
+// so the destination may be on a different site.
+func (sp *SharePoint) CopyItem(ctx context.Context, srcDriveID, srcPath, dstDriveID, dstFolder string) error {
+	parent, err := sp.driveItem(ctx, dstDriveID, dstFolder)
+	if err != nil {
+		return fmt.Errorf("destination folder: %w", err)
+	}
+
+	payload, _ := json.Marshal(map[string]any{
+		"parentReference": map[string]string{"driveId": dstDriveID, "id": parent.ID},
+	})
+	endpoint := driveItemURL(srcDriveID, srcPath) + ":/copy?@microsoft.graph.conflictBehavior=fail"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := sp.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("copy request failed: %w", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("copy %s failed (HTTP %d): %s", srcPath, resp.StatusCode, string(body))
+	}
+
+	monitor := resp.Header.Get("Location")
+	if monitor == "" {
+		return nil
+	}
+	return sp.waitForCopy(ctx, monitor, srcPath)
+}
+
+// waitForCopy polls an asynchronous copy's monitor URL until it completes.
+func (sp *SharePoint) waitForCopy(ctx context.Context, monitor, srcPath string) error {
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", monitor, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := sp.Client.Do(req)
+		if err != nil {
+			return fmt.Errorf("copy status request failed: %w", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var status struct {
+			Status string `json:"status"`
+			Error  struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		// The monitor redirects to the new item (303) once the copy is done,
+		// which the client follows and answers with the item itself.
+		if resp.StatusCode == http.StatusOK {
+			json.Unmarshal(body, &status)
+		}
+		switch {
+		case resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted:
+			return fmt.Errorf("copy status for %s failed (HTTP %d): %s", srcPath, resp.StatusCode, string(body))
+		case status.Status == "failed":
+			return fmt.Errorf("copy %s failed: %s %s", srcPath, status.Error.Code, status.Error.Message)
+		case status.Status == "completed" || (resp.StatusCode == http.StatusOK && status.Status == ""):
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(copyPollInterval):
+		}
+	}
+}
+
+// driveItem returns an item by path in any drive ("" or "/" is the root).
+func (sp *SharePoint) driveItem(ctx context.Context, driveID, itemPath string) (*DriveItem, error) {
+	endpoint := graphBase() + "/drives/" + driveID + "/root"
+	if p := strings.Trim(itemPath, "/"); p != "" {
+		endpoint = driveItemURL(driveID, p)
+	}
+	return NewDriveByID(sp.Client, driveID).getItem(ctx, endpoint)
+}
+
+// driveItemURL returns the path-addressed endpoint for an item in a drive.
+func driveItemURL(driveID, itemPath string) string {
+	return graphBase() + "/drives/" + driveID + "/root:/" + url.PathEscape(strings.Trim(itemPath, "/"))
+}
+
+// ScaffoldOptions configures Scaffold.
+type ScaffoldOptions struct {
+	// Folder limits the copy to this folder of the template library.
+	Folder string
+	// WithFiles also copies the template's documents, not just folders.
+	WithFiles bool
+	// DryRun reports what would be created without changing anything.
+	DryRun bool
+}
+
+// ScaffoldStep records what Scaffold did for one template item.
+type ScaffoldStep struct {
+	Path   string `json:"path"`
+	Type   string `json:"type"`   // "folder" or "file"
+	Action string `json:"action"` // "created", "copied", "exists", "planned", or "failed"
+	Error  string `json:"error,omitempty"`
+}
+
+// Scaffold replicates the folder hierarchy of a template library into a
+// destination library, optionally copying the template's files too.
+// Existing folders and files in the destination are left untouched, so
+// running it again only fills in what is missing. Per-item failures are
+// recorded in the steps; the returned error covers only the template walk.
+func (sp *SharePoint) Scaffold(ctx context.Context, srcSiteID, srcDriveID, dstSiteID, dstDriveID string, opts ScaffoldOptions) ([]ScaffoldStep, error) {
+	limit := sp.Limit
+	sp.Limit = 0
+	entries, err := sp.WalkLibrary(ctx, srcSiteID, srcDriveID, opts.Folder)
+	sp.Limit = limit
+	if err != nil {
+		return nil, fmt.Errorf("could not read template library: %w", err)
+	}
+
+	root := strings.Trim(opts.Folder, "/")
+	var steps []ScaffoldStep
+	if root != "" && !opts.DryRun {
+		// The template folder itself is recreated at the same path.
+		var dir string
+		for _, seg := range strings.Split(root, "/") {
+			dir = joinRemote(dir, seg)
+			if _, err := sp.createFolder(ctx, dstSiteID, dstDriveID, dir); err != nil && err != errItemExists {
+				return nil, fmt.Errorf("could not create %s: %w", dir, err)
+			}
+		}
+	}
+	for _, e := range entries {
+		if !e.IsFolder && !opts.WithFiles {
+			continue
+		}
+		full := joinRemote(root, e.Path)
+		step := ScaffoldStep{Path: full, Type: "file"}
+		if e.IsFolder {
+			step.Type = "folder"
+		}
+
+		switch {
+		case opts.DryRun:
+			step.Action = "planned"
+		case e.IsFolder:
+			_, err := sp.createFolder(ctx, dstSiteID, dstDriveID, full)
+			switch err {
+			case nil:
+				step.Action = "created"
+			case errItemExists:
+				step.Action = "exists"
+			default:
+				step.Action, step.Error = "failed", err.Error()
+			}
+		default:
+			if _, err := sp.driveItem(ctx, dstDriveID, full); err == nil {
+				step.Action = "exists"
+				break
+			}
+			if err := sp.CopyItem(ctx, srcDriveID, full, dstDriveID, path.Dir("/"+full)); err != nil {
+				step.Action, step.Error = "failed", err.Error()
+			} else {
+				step.Action = "copied"
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
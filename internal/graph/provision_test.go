@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// provisionServer serves libraryHandler's tree as the template library
+// (site s, drive d) and an empty destination library (site t, drive e)
+// that already contains the folder "Docs".
+type provisionServer struct {
+	mu      sync.Mutex
+	created []string
+	copied  []string
+	polls   int
+}
+
+func (p *provisionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/v1.0")
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == "POST" && strings.HasPrefix(path, "/sites/t/drives/e/root"):
+		var body struct {
+			Name     string `json:"name"`
+			Conflict string `json:"@microsoft.graph.conflictBehavior"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		parent := strings.TrimSuffix(strings.TrimPrefix(path, "/sites/t/drives/e/root"), "/children")
+		parent = strings.Trim(strings.TrimSuffix(strings.TrimPrefix(parent, ":/"), ":"), "/")
+		full := joinRemote(parent, body.Name)
+		if body.Conflict != "fail" {
+			http.Error(w, "expected conflictBehavior fail", http.StatusBadRequest)
+			return
+		}
+		if full == "Docs" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		p.created = append(p.created, full)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"id": "new-" + body.Name, "name": body.Name, "folder": map[string]any{}})
+	case r.Method == "GET" && path == "/drives/e/root":
+		json.NewEncoder(w).Encode(map[string]any{"id": "root-e", "folder": map[string]any{}})
+	case r.Method == "GET" && path == "/drives/e/root:/Docs":
+		json.NewEncoder(w).Encode(map[string]any{"id": "docs-e", "folder": map[string]any{}})
+	case r.Method == "GET" && path == "/drives/e/root:/Docs/b.txt":
+		json.NewEncoder(w).Encode(map[string]any{"id": "b-e", "file": map[string]any{}})
+	case r.Method == "GET" && path == "/drives/e/root:/Docs/Deep":
+		json.NewEncoder(w).Encode(map[string]any{"id": "deep-e", "folder": map[string]any{}})
+	case r.Method == "POST" && strings.HasPrefix(path, "/drives/d/root:/") && strings.HasSuffix(path, ":/copy"):
+		var body struct {
+			ParentReference struct {
+				DriveID string `json:"driveId"`
+				ID      string `json:"id"`
+			} `json:"parentReference"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.ParentReference.DriveID != "e" {
+			http.Error(w, "wrong destination drive", http.StatusBadRequest)
+			return
+		}
+		src := strings.TrimSuffix(strings.TrimPrefix(path, "/drives/d/root:/"), ":/copy")
+		p.copied = append(p.copied, src+"→"+body.ParentReference.ID)
+		w.Header().Set("Location", "https://contoso.sharepoint.com/monitor/"+src)
+		w.WriteHeader(http.StatusAccepted)
+	case strings.HasPrefix(path, "/monitor/"):
+		p.polls++
+		status := "inProgress"
+		if p.polls%2 == 0 {
+			status = "completed"
+		}
+		json.NewEncoder(w).Encode(map[string]any{"status": status})
+	default:
+		if r.Method == "GET" && strings.HasPrefix(path, "/drives/e/") {
+			http.NotFound(w, r)
+			return
+		}
+		libraryHandler()(w, r)
+	}
+}
+
+func TestCreateFolder(t *testing.T) {
+	p := &provisionServer{}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	item, err := sp.CreateFolder(context.Background(), "t", "e", "/Reports/2024/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "new-2024" || !item.IsFolder {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if strings.Join(p.created, ",") != "Reports/2024" {
+		t.Errorf("created = %v", p.created)
+	}
+
+	if _, err := sp.CreateFolder(context.Background(), "t", "e", ""); err == nil {
+		t.Error("expected error for empty path")
+	}
+}
+
+func TestCopyItemWaits(t *testing.T) {
+	copyPollInterval = time.Millisecond
+	defer func() { copyPollInterval = time.Second }()
+
+	p := &provisionServer{}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	if err := sp.CopyItem(context.Background(), "d", "a.txt", "e", "/"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(p.copied, ",") != "a.txt→root-e" {
+		t.Errorf("copied = %v", p.copied)
+	}
+	if p.polls != 2 {
+		t.Errorf("expected 2 status polls, got %d", p.polls)
+	}
+}
+
+func TestCopyItemFailed(t *testing.T) {
+	copyPollInterval = time.Millisecond
+	defer func() { copyPollInterval = time.Second }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":/copy"):
+			w.Header().Set("Location", "https://contoso.sharepoint.com/monitor/x")
+			w.WriteHeader(http.StatusAccepted)
+		case strings.HasPrefix(r.URL.Path, "/monitor/"):
+			json.NewEncoder(w).Encode(map[string]any{"status": "failed", "error": map[string]string{"code": "nameAlreadyExists", "message": "exists"}})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{"id": "root-e"})
+		}
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	err := sp.CopyItem(context.Background(), "d", "a.txt", "e", "")
+	if err == nil || !strings.Contains(err.Error(), "nameAlreadyExists") {
+		t.Errorf("expected copy failure, got %v", err)
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	copyPollInterval = time.Millisecond
+	defer func() { copyPollInterval = time.Second }()
+
+	p := &provisionServer{}
+	server := httptest.NewServer(p)
+	defer server.Close()
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+
+	steps, err := sp.Scaffold(context.Background(), "s", "d", "t", "e", ScaffoldOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range steps {
+		got = append(got, s.Path+":"+s.Action)
+	}
+	if want := "Docs:exists,Docs/Deep:created"; strings.Join(got, ",") != want {
+		t.Errorf("folders only = %s, want %s", strings.Join(got, ","), want)
+	}
+	if len(p.copied) != 0 {
+		t.Errorf("expected no copies without WithFiles, got %v", p.copied)
+	}
+
+	p.created = nil
+	steps, err = sp.Scaffold(context.Background(), "s", "d", "t", "e", ScaffoldOptions{WithFiles: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, s := range steps {
+		got = append(got, s.Path+":"+s.Action)
+	}
+	want := "a.txt:copied,Docs:exists,Docs/b.txt:exists,Docs/Deep:created,Docs/Deep/c.txt:copied"
+	if strings.Join(got, ",") != want {
+		t.Errorf("with files = %s, want %s", strings.Join(got, ","), want)
+	}
+	if strings.Join(p.copied, ",") != "a.txt→root-e,Docs/Deep/c.txt→deep-e" {
+		t.Errorf("copied = %v", p.copied)
+	}
+}
+
+func TestScaffoldDryRun(t *testing.T) {
+	p := &provisionServer{}
+	server := httptest.NewServer(p)
+	defer server.Close()
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+
+	steps, err := sp.Scaffold(context.Background(), "s", "d", "t", "e", ScaffoldOptions{Folder: "Docs", WithFiles: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 3 || steps[0].Action != "planned" || steps[1].Path != "Docs/Deep" {
+		t.Errorf("unexpected steps: %+v", steps)
+	}
+	if len(p.created)+len(p.copied) != 0 {
+		t.Errorf("dry run changed the destination: created=%v copied=%v", p.created, p.copied)
+	}
+}
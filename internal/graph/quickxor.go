@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"hash"
+)
+
+// QuickXorHash is the content hash OneDrive for Business and SharePoint report
+// for every file. See:
+// https://learn.microsoft.com/onedrive/developer/code-snippets/quickxorhash
+const (
+	quickXorSize       = 20
+	quickXorBlockSize  = 64
+	quickXorShift      = 11
+	quickXorWidth      = 8 * quickXorSize // 160 bits
+	quickXorCells      = (quickXorWidth-1)/64 + 1
+	quickXorBitsInLast = quickXorWidth - 64*(quickXorCells-1)
+)
+
+type quickXorHash struct {
+	data   [quickXorCells]uint64
+	length uint64
+	shift  int
+}
+
+// NewQuickXorHash returns a hash.Hash computing the OneDrive QuickXorHash.
+func NewQuickXorHash() hash.Hash {
+	return &quickXorHash{}
+}
+
+func (q *quickXorHash) Write(p []byte) (int, error) {
+	cell := q.shift / 64
+	offset := q.shift % 64
+
+	iterations := len(p)
+	if iterations > quickXorWidth {
+		iterations = quickXorWidth
+	}
+
+	for i := 0; i < iterations; i++ {
+		isLast := cell == quickXorCells-1
+		bits := 64
+		if isLast {
+			bits = quickXorBitsInLast
+		}
+
+		// Every byte that lands on the same bit position is XORed together first.
+		var x byte
+		for j := i; j < len(p); j += quickXorWidth {
+			x ^= p[j]
+		}
+
+		if offset <= bits-8 {
+			q.data[cell] ^= uint64(x) << uint(offset)
+		} else {
+			next := cell + 1
+			if isLast {
+				next = 0
+			}
+			q.data[cell] ^= uint64(x) << uint(offset)
+			q.data[next] ^= uint64(x) >> uint(bits-offset)
+		}
+
+		offset += quickXorShift
+		for offset >= bits {
+			if isLast {
+				cell = 0
+			} else {
+				cell++
+			}
+			offset -= bits
+		}
+	}
+
+	q.shift = (q.shift + quickXorShift*(len(p)%quickXorWidth)) % quickXorWidth
+	q.length += uint64(len(p))
+	return len(p), nil
+}
+
+func (q *quickXorHash) Sum(b []byte) []byte {
+	var out [quickXorCells * 8]byte
+	for i, v := range q.data {
+		binary.LittleEndian.PutUint64(out[i*8:], v)
+	}
+	sum := out[:quickXorSize]
+
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], q.length)
+	for i := range length {
+		sum[quickXorSize-8+i] ^= length[i]
+	}
+	return append(b, sum...)
+}
+
+func (q *quickXorHash) Reset() {
+	*q = quickXorHash{}
+}
+
+func (q *quickXorHash) Size() int {
+	return quickXorSize
+}
+
+func (q *quickXorHash) BlockSize() int {
+	return quickXorBlockSize
+}
+
+// QuickXorHashString returns the base64 QuickXorHash of data, as reported by Graph.
+func QuickXorHashString(data []byte) string {
+	h := NewQuickXorHash()
+	h.Write(data)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
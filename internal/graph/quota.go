@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Quota describes storage usage for a drive.
+type Quota struct {
+	Total     int64  `json:"total"`
+	Used      int64  `json:"used"`
+	Remaining int64  `json:"remaining"`
+	Deleted   int64  `json:"deleted"`
+	State     string `json:"state"`
+}
+
+// UsedPct returns used space as a percentage of the total.
+func (q *Quota) UsedPct() float64 {
+	if q.Total == 0 {
+		return 0
+	}
+	return float64(q.Used) / float64(q.Total) * 100
+}
+
+// Quota returns storage usage for the user's OneDrive.
+func (o *OneDrive) Quota(ctx context.Context) (*Quota, error) {
+	endpoint := graphBase() + o.drivePath() + "?$select=quota"
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("quota request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OneDrive API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Quota Quota `json:"quota"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("could not parse quota response: %w", err)
+	}
+
+	return &result.Quota, nil
+}
+
+// UsageNode is the aggregated size of a folder and its descendants.
+type UsageNode struct {
+	Path     string       `json:"path"`
+	Size     int64        `json:"size"`
+	Files    int          `json:"files"`
+	Folders  int          `json:"folders"`
+	Children []*UsageNode `json:"children,omitempty"`
+}
+
+// DiskUsage walks a OneDrive folder recursively and totals file sizes.
+// Child folders are sorted largest first.
+func (o *OneDrive) DiskUsage(ctx context.Context, folderPath string) (*UsageNode, error) {
+	folderPath = strings.Trim(folderPath, "/")
+	node := &UsageNode{Path: "/" + folderPath}
+
+	items, err := o.ListFolder(ctx, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if !item.IsFolder {
+			node.Size += item.Size
+			node.Files++
+			continue
+		}
+
+		childPath := item.Name
+		if folderPath != "" {
+			childPath = folderPath + "/" + item.Name
+		}
+		child, err := o.DiskUsage(ctx, childPath)
+		if err != nil {
+			return nil, err
+		}
+		node.Size += child.Size
+		node.Files += child.Files
+		node.Folders += child.Folders + 1
+		node.Children = append(node.Children, child)
+	}
+
+	sort.Slice(node.Children, func(i, j int) bool {
+		return node.Children[i].Size > node.Children[j].Size
+	})
+
+	return node, nil
+}
+
+// Flatten returns the node and its descendants down to maxDepth levels
+// (0 = the node only, negative = unlimited), in depth-first order.
+func (n *UsageNode) Flatten(maxDepth int) []*UsageNode {
+	out := []*UsageNode{n}
+	if maxDepth == 0 {
+		return out
+	}
+	for _, c := range n.Children {
+		out = append(out, c.Flatten(maxDepth-1)...)
+	}
+	return out
+}
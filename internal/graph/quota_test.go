@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestQuotaJSON(t *testing.T) {
+	raw := `{"quota": {"total": 1099511627776, "used": 274877906944, "remaining": 824633720832, "deleted": 1048576, "state": "normal"}}`
+
+	var result struct {
+		Quota Quota `json:"quota"`
+	}
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	q := result.Quota
+	if q.Total != 1099511627776 {
+		t.Errorf("Total = %d", q.Total)
+	}
+	if q.Deleted != 1048576 {
+		t.Errorf("Deleted = %d", q.Deleted)
+	}
+	if q.UsedPct() != 25 {
+		t.Errorf("UsedPct() = %f, want 25", q.UsedPct())
+	}
+	if (&Quota{}).UsedPct() != 0 {
+		t.Error("UsedPct() of empty quota should be 0")
+	}
+}
+
+func TestUsageNodeFlatten(t *testing.T) {
+	root := &UsageNode{
+		Path: "/",
+		Children: []*UsageNode{
+			{Path: "/a", Children: []*UsageNode{{Path: "/a/x"}}},
+			{Path: "/b"},
+		},
+	}
+
+	if got := len(root.Flatten(0)); got != 1 {
+		t.Errorf("Flatten(0) = %d nodes, want 1", got)
+	}
+	if got := len(root.Flatten(1)); got != 3 {
+		t.Errorf("Flatten(1) = %d nodes, want 3", got)
+	}
+
+	all := root.Flatten(-1)
+	if len(all) != 4 {
+		t.Fatalf("Flatten(-1) = %d nodes, want 4", len(all))
+	}
+	if all[2].Path != "/a/x" {
+		t.Errorf("expected depth-first order, got %q at index 2", all[2].Path)
+	}
+}
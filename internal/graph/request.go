@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// doJSON sends a Graph request, with payload as its JSON body when non-nil,
+// and decodes the response into v when v is non-nil. Any 2xx status is
+// success. header adds request headers such as Prefer and may be nil. The
+// response headers are returned for calls that need them, e.g. Location on
+// a 202 Accepted.
+func doJSON(ctx context.Context, client *http.Client, method, endpoint, what string, header http.Header, payload, v any) (http.Header, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vals := range header {
+		req.Header[k] = vals
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s failed (HTTP %d): %s", what, resp.StatusCode, string(respBody))
+	}
+	if v == nil {
+		return resp.Header, nil
+	}
+	if err := json.Unmarshal(respBody, v); err != nil {
+		return nil, fmt.Errorf("could not parse %s response: %w", what, err)
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"message":"not found"}}`))
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if p := r.Header.Get("Prefer"); p != "respond-async" {
+			t.Errorf("Prefer = %q", p)
+		}
+		var in map[string]string
+		json.NewDecoder(r.Body).Decode(&in)
+		w.Header().Set("Location", "/operations/1")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"echo": in["name"]})
+	}))
+	defer server.Close()
+	client := server.Client()
+
+	var out struct {
+		Echo string `json:"echo"`
+	}
+	header := http.Header{"Prefer": {"respond-async"}}
+	got, err := doJSON(context.Background(), client, "POST", server.URL+"/things", "create thing", header, map[string]string{"name": "x"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Echo != "x" || got.Get("Location") != "/operations/1" {
+		t.Errorf("out = %+v, Location = %q", out, got.Get("Location"))
+	}
+
+	_, err = doJSON(context.Background(), client, "GET", server.URL+"/missing", "get thing", nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "get thing failed (HTTP 404)") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// SearchOptions narrows a OneDrive search.
+//
+// Folder is sent to Graph as the search root. The drive search endpoint does
+// not honour $filter on extension or modification date, so those are applied
+// to each page of results as it arrives.
+type SearchOptions struct {
+	Extensions     []string  // e.g. "docx" or ".docx"; any match passes
+	ModifiedAfter  time.Time // zero means no lower bound
+	ModifiedBefore time.Time // zero means no upper bound
+	Folder         string    // restrict to this folder and its subfolders
+	Limit          int       // stop after this many matches (0 = all)
+}
+
+// Matches reports whether item passes the extension and date filters.
+func (o SearchOptions) Matches(item *DriveItem) bool {
+	if len(o.Extensions) > 0 {
+		if item.IsFolder {
+			return false
+		}
+		ext := strings.TrimPrefix(strings.ToLower(path.Ext(item.Name)), ".")
+		found := false
+		for _, want := range o.Extensions {
+			if strings.TrimPrefix(strings.ToLower(want), ".") == ext {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !o.ModifiedAfter.IsZero() && item.LastModifiedAt.Before(o.ModifiedAfter) {
+		return false
+	}
+	if !o.ModifiedBefore.IsZero() && !item.LastModifiedAt.Before(o.ModifiedBefore) {
+		return false
+	}
+	return true
+}
+
+// Search runs a drive search, following pagination and applying opts.
+// opts.Limit counts the items that pass the filters, so pages are fetched
+// until enough do.
+func (o *OneDrive) Search(ctx context.Context, query string, opts SearchOptions) ([]DriveItem, error) {
+	endpoint := graphBase() + o.drivePath() + searchRoot(opts.Folder) +
+		"/search(q='" + url.QueryEscape(strings.ReplaceAll(query, "'", "''")) + "')"
+
+	var matches []DriveItem
+	err := ForEach(ctx, o.Client, endpoint, "search", func(item DriveItem) error {
+		if !opts.Matches(&item) {
+			return nil
+		}
+		matches = append(matches, item)
+		if opts.Limit > 0 && len(matches) >= opts.Limit {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// searchRoot returns the drive-relative item to search under.
+func searchRoot(folder string) string {
+	folder = strings.Trim(folder, "/")
+	if folder == "" {
+		return "/root"
+	}
+	return "/root:/" + url.PathEscape(folder) + ":"
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSearchOptionsMatchesExtension(t *testing.T) {
+	opts := SearchOptions{Extensions: []string{"docx", ".PDF"}}
+
+	tests := []struct {
+		item DriveItem
+		want bool
+	}{
+		{DriveItem{Name: "Report.DOCX"}, true},
+		{DriveItem{Name: "scan.pdf"}, true},
+		{DriveItem{Name: "data.xlsx"}, false},
+		{DriveItem{Name: "noext"}, false},
+		{DriveItem{Name: "docx", IsFolder: true}, false},
+	}
+	for _, tt := range tests {
+		if got := opts.Matches(&tt.item); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.item.Name, got, tt.want)
+		}
+	}
+}
+
+func TestSearchOptionsMatchesDates(t *testing.T) {
+	jan := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	opts := SearchOptions{
+		ModifiedAfter:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ModifiedBefore: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	if !opts.Matches(&DriveItem{Name: "a", LastModifiedAt: jan}) {
+		t.Error("expected item inside the window to match")
+	}
+	if opts.Matches(&DriveItem{Name: "b", LastModifiedAt: jan.AddDate(0, -1, 0)}) {
+		t.Error("expected item before ModifiedAfter to be excluded")
+	}
+	if opts.Matches(&DriveItem{Name: "c", LastModifiedAt: opts.ModifiedBefore}) {
+		t.Error("expected item at ModifiedBefore to be excluded")
+	}
+}
+
+func TestSearchOptionsZeroMatchesAll(t *testing.T) {
+	if !(SearchOptions{}).Matches(&DriveItem{Name: "folder", IsFolder: true}) {
+		t.Error("expected empty options to match everything")
+	}
+}
+
+func TestSearchLimitCountsFilteredResults(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			fmt.Fprintf(w, `{"value":[{"id":"1","name":"a.txt","file":{}},{"id":"2","name":"b.docx","file":{}},{"id":"3","name":"c.txt","file":{}}],
+				"@odata.nextLink":"%s/v1.0/me/drive/root/search?page=2"}`, server.URL)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"id":"4","name":"d.pdf","file":{}},{"id":"5","name":"e.docx","file":{}},{"id":"6","name":"f.docx","file":{}}]}`)
+	}))
+	defer server.Close()
+
+	od := &OneDrive{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	items, err := od.Search(context.Background(), "report", SearchOptions{Extensions: []string{"docx"}, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].ID != "2" || items[1].ID != "5" {
+		t.Errorf("got %+v, want the first two .docx files", items)
+	}
+}
+
+func TestSearchRoot(t *testing.T) {
+	tests := map[string]string{
+		"":               "/root",
+		"/":              "/root",
+		"/Projects":      "/root:/Projects:",
+		"Projects/2026/": "/root:/Projects%2F2026:",
+	}
+	for in, want := range tests {
+		if got := searchRoot(in); got != want {
+			t.Errorf("searchRoot(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -7,8 +7,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -38,6 +36,7 @@ type DocumentLibrary struct {
 type AuditEntry struct {
 	Action     string    `json:"action"`
 	Actor      string    `json:"actor"`
+	Library    string    `json:"library"`
 	ItemName   string    `json:"itemName"`
 	ItemPath   string    `json:"itemPath"`
 	OccurredAt time.Time `json:"occurredAt"`
@@ -46,6 +45,10 @@ type AuditEntry struct {
 // SharePoint provides operations on Microsoft SharePoint.
 type SharePoint struct {
 	Client *http.Client
+	// Progress, if set, receives byte counts for uploads and downloads.
+	Progress TransferObserver
+	// Limit caps the number of sites or files returned by listings (0 = all).
+	Limit int
 }
 
 // NewSharePoint creates a new SharePoint client with an authenticated HTTP client.
@@ -53,45 +56,16 @@ func NewSharePoint(client *http.Client) *SharePoint {
 	return &SharePoint{Client: client}
 }
 
-type sitesResponse struct {
-	Value []Site `json:"value"`
-}
-
-type drivesResponse struct {
-	Value []DocumentLibrary `json:"value"`
-}
-
 // ListSites returns SharePoint sites the user has access to.
 func (sp *SharePoint) ListSites(ctx context.Context, query string) ([]Site, error) {
 	var endpoint string
 	if query != "" {
-		endpoint = graphBase + "/sites?search=" + url.QueryEscape(query)
+		endpoint = graphBase() + "/sites?search=" + url.QueryEscape(query)
 	} else {
-		endpoint = graphBase + "/sites?search=*"
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := sp.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("SharePoint sites request failed: %w", err)
+		endpoint = graphBase() + "/sites?search=*"
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("SharePoint API returned %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result sitesResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not parse sites response: %w", err)
-	}
-
-	return result.Value, nil
+	return GetAll[Site](ctx, sp.Client, endpoint, "SharePoint sites", sp.Limit)
 }
 
 // GetSite returns a specific site by hostname and path.
@@ -99,9 +73,9 @@ func (sp *SharePoint) ListSites(ctx context.Context, query string) ([]Site, erro
 func (sp *SharePoint) GetSite(ctx context.Context, siteRef string) (*Site, error) {
 	var endpoint string
 	if strings.Contains(siteRef, ":") || strings.Contains(siteRef, ".") {
-		endpoint = graphBase + "/sites/" + siteRef
+		endpoint = graphBase() + "/sites/" + siteRef
 	} else {
-		endpoint = graphBase + "/sites/" + siteRef
+		endpoint = graphBase() + "/sites/" + siteRef
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
@@ -128,229 +102,252 @@ func (sp *SharePoint) GetSite(ctx context.Context, siteRef string) (*Site, error
 	return &site, nil
 }
 
-// ListLibraries returns document libraries for a site.
-func (sp *SharePoint) ListLibraries(ctx context.Context, siteID string) ([]DocumentLibrary, error) {
-	endpoint := graphBase + "/sites/" + siteID + "/drives"
-
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
+// ResolveSite returns the site ID for a display name, URL name, web URL, or
+// ID. Names match case-insensitively, exactly first and then partially; a
+// partial match that fits several sites is an error rather than a guess.
+// Composite IDs ("host,guid,guid") and "host:/sites/path" references are
+// returned unchanged.
+func (sp *SharePoint) ResolveSite(ctx context.Context, nameOrID string) (string, error) {
+	if u, err := url.Parse(nameOrID); err == nil && u.Scheme == "https" && u.Host != "" {
+		ref := u.Host
+		if p := strings.TrimRight(u.Path, "/"); p != "" {
+			ref += ":" + p
+		}
+		site, err := sp.GetSite(ctx, ref)
+		if err != nil {
+			return "", err
+		}
+		return site.ID, nil
+	}
+	if strings.Contains(nameOrID, ",") || strings.Contains(nameOrID, ":/") || isUUID(nameOrID) {
+		return nameOrID, nil
 	}
 
-	resp, err := sp.Client.Do(req)
+	sites, err := GetAll[Site](ctx, sp.Client, graphBase()+"/sites?search="+url.QueryEscape(nameOrID), "SharePoint sites", 0)
 	if err != nil {
-		return nil, fmt.Errorf("SharePoint libraries request failed: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("SharePoint API returned %d: %s", resp.StatusCode, string(body))
+	lower := strings.ToLower(nameOrID)
+	for _, s := range sites {
+		if strings.ToLower(s.DisplayName) == lower || strings.ToLower(s.Name) == lower {
+			return s.ID, nil
+		}
 	}
-
-	var result drivesResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not parse libraries response: %w", err)
+	var partial []Site
+	for _, s := range sites {
+		if strings.Contains(strings.ToLower(s.DisplayName), lower) || strings.Contains(strings.ToLower(s.Name), lower) {
+			partial = append(partial, s)
+		}
 	}
-
-	return result.Value, nil
+	switch len(partial) {
+	case 0:
+		return "", fmt.Errorf("site %q not found — run: kit sharepoint sites", nameOrID)
+	case 1:
+		return partial[0].ID, nil
+	}
+	names := make([]string, len(partial))
+	for i, s := range partial {
+		names[i] = s.DisplayName
+	}
+	return "", fmt.Errorf("site %q is ambiguous (%s) — use the full name or site ID", nameOrID, strings.Join(names, ", "))
 }
 
-// ListLibraryFiles lists files in a specific document library.
-func (sp *SharePoint) ListLibraryFiles(ctx context.Context, siteID, driveID, folderPath string) ([]DriveItem, error) {
-	var endpoint string
-	folderPath = strings.TrimRight(folderPath, "/")
-	if folderPath == "" || folderPath == "/" {
-		endpoint = graphBase + "/sites/" + siteID + "/drives/" + driveID + "/root/children"
-	} else {
-		endpoint = graphBase + "/sites/" + siteID + "/drives/" + driveID + "/root:/" + url.PathEscape(folderPath) + ":/children"
+// ResolveLibrary returns the drive ID for a library display name (e.g.
+// "Documents"), URL name (e.g. "Shared Documents"), or drive ID, using the
+// same matching as ResolveSite. An empty name selects the site's first library.
+func (sp *SharePoint) ResolveLibrary(ctx context.Context, siteID, nameOrID string) (string, error) {
+	if strings.HasPrefix(nameOrID, "b!") {
+		return nameOrID, nil
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	libs, err := sp.ListLibraries(ctx, siteID)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-
-	resp, err := sp.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("SharePoint list files request failed: %w", err)
+	if len(libs) == 0 {
+		return "", fmt.Errorf("no document libraries found on this site")
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("SharePoint API returned %d: %s", resp.StatusCode, string(body))
+	if nameOrID == "" {
+		return libs[0].ID, nil
 	}
 
-	var result driveItemsResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not parse library files: %w", err)
+	lower := strings.ToLower(nameOrID)
+	for _, lib := range libs {
+		if lib.ID == nameOrID || strings.ToLower(lib.DisplayName) == lower || strings.ToLower(lib.Name) == lower {
+			return lib.ID, nil
+		}
 	}
-
-	return result.Value, nil
-}
-
-// DownloadFromLibrary downloads a file from a SharePoint document library.
-func (sp *SharePoint) DownloadFromLibrary(ctx context.Context, siteID, driveID, itemPath, localPath string) (int64, error) {
-	endpoint := graphBase + "/sites/" + siteID + "/drives/" + driveID + "/root:/" + url.PathEscape(itemPath) + ":/content"
-
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return 0, err
+	var partial []DocumentLibrary
+	for _, lib := range libs {
+		if strings.Contains(strings.ToLower(lib.DisplayName), lower) || strings.Contains(strings.ToLower(lib.Name), lower) {
+			partial = append(partial, lib)
+		}
 	}
-
-	resp, err := sp.Client.Do(req)
-	if err != nil {
-		return 0, fmt.Errorf("SharePoint download failed: %w", err)
+	switch len(partial) {
+	case 0:
+		return "", fmt.Errorf("library %q not found — run: kit sharepoint libs %s", nameOrID, siteID)
+	case 1:
+		return partial[0].ID, nil
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusFound {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("download failed (HTTP %d): %s", resp.StatusCode, string(body))
+	names := make([]string, len(partial))
+	for i, lib := range partial {
+		names[i] = lib.DisplayName
 	}
+	return "", fmt.Errorf("library %q is ambiguous (%s) — use the full name or drive ID", nameOrID, strings.Join(names, ", "))
+}
 
-	f, err := createLocalFile(localPath)
-	if err != nil {
-		return 0, err
-	}
-	defer f.Close()
+// ListLibraries returns document libraries for a site.
+func (sp *SharePoint) ListLibraries(ctx context.Context, siteID string) ([]DocumentLibrary, error) {
+	return GetAll[DocumentLibrary](ctx, sp.Client, graphBase()+"/sites/"+siteID+"/drives", "SharePoint libraries", 0)
+}
 
-	return io.Copy(f, resp.Body)
+// ListLibraryFiles lists files in a specific document library.
+func (sp *SharePoint) ListLibraryFiles(ctx context.Context, siteID, driveID, folderPath string) ([]DriveItem, error) {
+	return GetAll[DriveItem](ctx, sp.Client, libraryChildrenURL(siteID, driveID, folderPath), "SharePoint list files", sp.Limit)
 }
 
-// UploadToLibrary uploads a file to a SharePoint document library (up to 4MB).
-func (sp *SharePoint) UploadToLibrary(ctx context.Context, siteID, driveID, remotePath, localPath string) (*DriveItem, error) {
-	f, err := openAndValidateFile(localPath)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
+// GetLibraryItem returns metadata for a single item in a document library by path.
+func (sp *SharePoint) GetLibraryItem(ctx context.Context, siteID, driveID, itemPath string) (*DriveItem, error) {
+	itemPath = strings.Trim(itemPath, "/")
+	endpoint := graphBase() + "/sites/" + siteID + "/drives/" + driveID + "/root:/" + url.PathEscape(itemPath)
 
-	endpoint := graphBase + "/sites/" + siteID + "/drives/" + driveID + "/root:/" + url.PathEscape(remotePath) + ":/content"
-	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, f)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/octet-stream")
 
 	resp, err := sp.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("upload request failed: %w", err)
+		return nil, fmt.Errorf("SharePoint get item failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("upload failed (HTTP %d): %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("SharePoint API returned %d: %s", resp.StatusCode, string(body))
 	}
 
 	var item DriveItem
 	if err := json.Unmarshal(body, &item); err != nil {
-		return nil, fmt.Errorf("could not parse upload response: %w", err)
+		return nil, fmt.Errorf("could not parse item: %w", err)
 	}
 
 	return &item, nil
 }
 
-// AuditSite returns recent activity for a site's primary drive.
-func (sp *SharePoint) AuditSite(ctx context.Context, siteID string) ([]AuditEntry, error) {
-	// First get the default drive
-	libs, err := sp.ListLibraries(ctx, siteID)
+// DownloadFromLibrary downloads a file from a SharePoint document library.
+// Like OneDrive downloads, it resumes from a .partial file and verifies the hash.
+func (sp *SharePoint) DownloadFromLibrary(ctx context.Context, siteID, driveID, itemPath, localPath string) (int64, error) {
+	item, err := sp.GetLibraryItem(ctx, siteID, driveID, itemPath)
 	if err != nil {
-		return nil, fmt.Errorf("could not list libraries for audit: %w", err)
+		return 0, err
 	}
-	if len(libs) == 0 {
-		return nil, fmt.Errorf("no document libraries found on site")
+
+	if item.DownloadURL == "" {
+		return 0, fmt.Errorf("no download URL available for %s", itemPath)
 	}
 
-	driveID := libs[0].ID
-	endpoint := graphBase + "/sites/" + siteID + "/drives/" + driveID + "/activities"
+	return downloadItem(ctx, sp.Client, item, localPath, sp.Progress)
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
+// UploadToLibrary uploads a file to a SharePoint document library. Files over
+// 4MB are sent through an upload session.
+func (sp *SharePoint) UploadToLibrary(ctx context.Context, siteID, driveID, remotePath, localPath string) (*DriveItem, error) {
+	return uploadFile(ctx, sp.Client, sp.itemURL(siteID, driveID, remotePath), localPath, sp.Progress)
+}
 
-	resp, err := sp.Client.Do(req)
+// AuditSite streams activity from every document library on a site to fn,
+// newest first within each library. Paging stops at the first entry older
+// than since (zero means no cutoff) or once sp.Limit entries have been sent.
+func (sp *SharePoint) AuditSite(ctx context.Context, siteID string, since time.Time, fn func(AuditEntry) error) error {
+	libs, err := sp.ListLibraries(ctx, siteID)
 	if err != nil {
-		return nil, fmt.Errorf("audit request failed: %w", err)
+		return fmt.Errorf("could not list libraries for audit: %w", err)
+	}
+	if len(libs) == 0 {
+		return fmt.Errorf("no document libraries found on site")
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		// Activities API may not be available — return helpful message
-		return nil, fmt.Errorf("activities API returned %d — this requires SharePoint admin permissions", resp.StatusCode)
-	}
-
-	var result struct {
-		Value []struct {
-			Action json.RawMessage `json:"action"`
-			Actor  struct {
-				User struct {
-					DisplayName string `json:"displayName"`
-				} `json:"user"`
-			} `json:"actor"`
-			Times struct {
-				Recorded string `json:"recordedDateTime"`
-			} `json:"times"`
-			DriveItem struct {
-				Name string `json:"name"`
-			} `json:"driveItem"`
-		} `json:"value"`
-	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not parse audit response: %w", err)
-	}
-
-	var entries []AuditEntry
-	for _, v := range result.Value {
-		action := "unknown"
-		// Parse action type from JSON keys
-		var actionMap map[string]any
-		if err := json.Unmarshal(v.Action, &actionMap); err == nil {
-			for k := range actionMap {
-				action = k
-				break
+	sent := 0
+	for _, lib := range libs {
+		endpoint := graphBase() + "/sites/" + siteID + "/drives/" + lib.ID + "/activities"
+		err := ForEach(ctx, sp.Client, endpoint, "activities", func(v driveActivity) error {
+			entry := v.entry(lib.Name)
+			if !since.IsZero() && !entry.OccurredAt.IsZero() && entry.OccurredAt.Before(since) {
+				return errStopPaging
 			}
-		}
-
-		recorded, _ := time.Parse(time.RFC3339, v.Times.Recorded)
-		entries = append(entries, AuditEntry{
-			Action:     action,
-			Actor:      v.Actor.User.DisplayName,
-			ItemName:   v.DriveItem.Name,
-			OccurredAt: recorded,
+			if err := fn(entry); err != nil {
+				return err
+			}
+			sent++
+			if sp.Limit > 0 && sent >= sp.Limit {
+				return errAuditLimit
+			}
+			return nil
 		})
+		if err == errAuditLimit {
+			return nil
+		}
+		if err != nil {
+			// Activities API may not be available — return helpful message
+			return fmt.Errorf("%s: %w\nthe activities API requires SharePoint admin permissions", lib.Name, err)
+		}
 	}
+	return nil
+}
 
-	return entries, nil
+// errAuditLimit stops AuditSite once sp.Limit entries have been sent.
+var errAuditLimit = fmt.Errorf("audit limit reached")
+
+// driveActivity is an item from the drive activities API.
+type driveActivity struct {
+	Action json.RawMessage `json:"action"`
+	Actor  struct {
+		User struct {
+			DisplayName string `json:"displayName"`
+		} `json:"user"`
+	} `json:"actor"`
+	Times struct {
+		Recorded string `json:"recordedDateTime"`
+	} `json:"times"`
+	DriveItem struct {
+		Name            string `json:"name"`
+		ParentReference struct {
+			Path string `json:"path"`
+		} `json:"parentReference"`
+	} `json:"driveItem"`
 }
 
-// helper: open file and validate size for upload
-func openAndValidateFile(path string) (*os.File, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("could not open file: %w", err)
-	}
-	info, err := f.Stat()
-	if err != nil {
-		f.Close()
-		return nil, fmt.Errorf("could not stat file: %w", err)
+func (v driveActivity) entry(library string) AuditEntry {
+	action := "unknown"
+	// Parse action type from JSON keys
+	var actionMap map[string]any
+	if err := json.Unmarshal(v.Action, &actionMap); err == nil {
+		for k := range actionMap {
+			action = k
+			break
+		}
 	}
-	if info.Size() > 4*1024*1024 {
-		f.Close()
-		return nil, fmt.Errorf("file too large (%d bytes, max 4MB)", info.Size())
+
+	recorded, _ := time.Parse(time.RFC3339, v.Times.Recorded)
+	return AuditEntry{
+		Action:     action,
+		Actor:      v.Actor.User.DisplayName,
+		Library:    library,
+		ItemName:   v.DriveItem.Name,
+		ItemPath:   itemPath(v.DriveItem.ParentReference.Path, v.DriveItem.Name),
+		OccurredAt: recorded,
 	}
-	return f, nil
 }
 
-// helper: create local file for download
-func createLocalFile(path string) (*os.File, error) {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("could not create directory: %w", err)
+// itemPath joins a Graph parentReference.path ("/drives/{id}/root:/Docs")
+// and a name into a library-relative path ("/Docs/name").
+func itemPath(parent, name string) string {
+	if i := strings.Index(parent, "root:"); i >= 0 {
+		parent = parent[i+len("root:"):]
+	} else {
+		parent = ""
 	}
-	return os.Create(path)
+	return strings.TrimSuffix(parent, "/") + "/" + name
 }
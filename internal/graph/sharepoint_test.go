@@ -3,11 +3,12 @@ package graph
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewSharePoint(t *testing.T) {
@@ -107,7 +108,7 @@ func TestListSitesServer(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result sitesResponse
+	var result Page[Site]
 	json.NewDecoder(resp.Body).Decode(&result)
 	if len(result.Value) != 2 {
 		t.Errorf("expected 2 sites, got %d", len(result.Value))
@@ -138,7 +139,7 @@ func TestListLibrariesServer(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result drivesResponse
+	var result Page[DocumentLibrary]
 	json.NewDecoder(resp.Body).Decode(&result)
 	if len(result.Value) != 2 {
 		t.Errorf("expected 2 libraries, got %d", len(result.Value))
@@ -166,47 +167,166 @@ func TestListLibraryFilesServer(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result driveItemsResponse
+	var result Page[DriveItem]
 	json.NewDecoder(resp.Body).Decode(&result)
 	if len(result.Value) != 2 {
 		t.Errorf("expected 2 items, got %d", len(result.Value))
 	}
 }
 
-func TestOpenAndValidateFileTooLarge(t *testing.T) {
-	dir := t.TempDir()
-	largePath := filepath.Join(dir, "large.bin")
-	f, _ := os.Create(largePath)
-	buf := make([]byte, 5*1024*1024)
-	f.Write(buf)
-	f.Close()
+func resolveServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1.0/sites":
+			json.NewEncoder(w).Encode(map[string]any{"value": []Site{
+				{ID: "h,1,1", DisplayName: "Marketing", Name: "marketing"},
+				{ID: "h,2,2", DisplayName: "Marketing Archive", Name: "mkt-archive"},
+				{ID: "h,3,3", DisplayName: "Legal", Name: "legal"},
+			}})
+		case r.URL.Path == "/v1.0/sites/contoso.sharepoint.com:/sites/legal":
+			json.NewEncoder(w).Encode(Site{ID: "h,3,3", DisplayName: "Legal"})
+		case r.URL.Path == "/v1.0/sites/h,1,1/drives":
+			json.NewEncoder(w).Encode(map[string]any{"value": []DocumentLibrary{
+				{ID: "b!docs", DisplayName: "Documents", Name: "Shared Documents"},
+				{ID: "b!assets", DisplayName: "Site Assets", Name: "SiteAssets"},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestResolveSite(t *testing.T) {
+	server := resolveServer(t)
+	defer server.Close()
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	ctx := context.Background()
 
-	_, err := openAndValidateFile(largePath)
-	if err == nil {
-		t.Fatal("expected error for file > 4MB")
+	tests := []struct {
+		in, want, wantErr string
+	}{
+		{in: "marketing", want: "h,1,1"},
+		{in: "LEGAL", want: "h,3,3"},
+		{in: "archive", want: "h,2,2"},
+		{in: "h,9,9", want: "h,9,9"},
+		{in: "contoso.sharepoint.com:/sites/hr", want: "contoso.sharepoint.com:/sites/hr"},
+		{in: "https://contoso.sharepoint.com/sites/legal/", want: "h,3,3"},
+		{in: "mark", wantErr: "ambiguous"},
+		{in: "finance", wantErr: "not found"},
 	}
-	if !containsStr(err.Error(), "too large") {
-		t.Errorf("expected 'too large' error, got: %s", err.Error())
+	for _, tt := range tests {
+		got, err := sp.ResolveSite(ctx, tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResolveSite(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveSite(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
 	}
 }
 
-func TestOpenAndValidateFileNotExist(t *testing.T) {
-	_, err := openAndValidateFile("/nonexistent/file.txt")
-	if err == nil {
-		t.Fatal("expected error for nonexistent file")
+func TestResolveLibrary(t *testing.T) {
+	server := resolveServer(t)
+	defer server.Close()
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	ctx := context.Background()
+
+	for in, want := range map[string]string{
+		"":                 "b!docs",
+		"shared documents": "b!docs",
+		"Documents":        "b!docs",
+		"assets":           "b!assets",
+		"b!other":          "b!other",
+	} {
+		got, err := sp.ResolveLibrary(ctx, "h,1,1", in)
+		if err != nil || got != want {
+			t.Errorf("ResolveLibrary(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := sp.ResolveLibrary(ctx, "h,1,1", "Images"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
 	}
 }
 
-func TestCreateLocalFile(t *testing.T) {
-	dir := t.TempDir()
-	path := filepath.Join(dir, "sub", "file.txt")
-	f, err := createLocalFile(path)
+func TestAuditSiteAllLibraries(t *testing.T) {
+	activity := func(day int, name, parent string) map[string]any {
+		return map[string]any{
+			"action": map[string]any{"edit": map[string]any{}},
+			"actor":  map[string]any{"user": map[string]any{"displayName": "Ann"}},
+			"times":  map[string]any{"recordedDateTime": fmt.Sprintf("2024-06-%02dT10:00:00Z", day)},
+			"driveItem": map[string]any{
+				"name":            name,
+				"parentReference": map[string]any{"path": parent},
+			},
+		}
+	}
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		var resp map[string]any
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/v1.0/sites/s/drives?":
+			resp = map[string]any{"value": []any{
+				map[string]any{"id": "d1", "name": "Documents"},
+				map[string]any{"id": "d2", "name": "Archive"},
+			}}
+		case "/v1.0/sites/s/drives/d1/activities?":
+			resp = map[string]any{
+				"value":           []any{activity(20, "a.docx", "/drives/d1/root:/Plans"), activity(18, "b.docx", "/drives/d1/root:")},
+				"@odata.nextLink": server.URL + "/v1.0/sites/s/drives/d1/activities?page=2",
+			}
+		case "/v1.0/sites/s/drives/d1/activities?page=2":
+			resp = map[string]any{
+				"value":           []any{activity(15, "c.docx", "/drives/d1/root:/Old"), activity(1, "d.docx", "/drives/d1/root:")},
+				"@odata.nextLink": server.URL + "/v1.0/sites/s/drives/d1/activities?page=3",
+			}
+		case "/v1.0/sites/s/drives/d2/activities?":
+			resp = map[string]any{"value": []any{activity(19, "e.docx", "/drives/d2/root:/X")}}
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	since := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	var got []string
+	err := sp.AuditSite(context.Background(), "s", since, func(e AuditEntry) error {
+		got = append(got, e.Library+":"+e.ItemPath)
+		return nil
+	})
 	if err != nil {
-		t.Fatalf("createLocalFile failed: %v", err)
+		t.Fatal(err)
+	}
+	want := "Documents:/Plans/a.docx,Documents:/b.docx,Documents:/Old/c.docx,Archive:/X/e.docx"
+	if strings.Join(got, ",") != want {
+		t.Errorf("entries = %s, want %s", strings.Join(got, ","), want)
+	}
+	for _, r := range requests {
+		if strings.Contains(r, "page=3") {
+			t.Error("paging should stop at the first entry older than since")
+		}
 	}
-	f.Close()
 
-	if _, err := os.Stat(path); err != nil {
-		t.Errorf("file not created: %v", err)
+	sp.Limit = 3
+	got = nil
+	sp.AuditSite(context.Background(), "s", time.Time{}, func(e AuditEntry) error {
+		got = append(got, e.ItemName)
+		return nil
+	})
+	if len(got) != 3 {
+		t.Errorf("expected 3 entries with limit, got %v", got)
 	}
 }
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MailSubscriptionLifetime is how long a mail subscription is requested
+// for. Graph allows just under 7 days for messages; callers renew well
+// before it runs out.
+const MailSubscriptionLifetime = 3 * 24 * time.Hour
+
+// Subscription is a Graph change-notification subscription.
+type Subscription struct {
+	ID                 string    `json:"id,omitempty"`
+	Resource           string    `json:"resource"`
+	ChangeType         string    `json:"changeType"`
+	NotificationURL    string    `json:"notificationUrl"`
+	ExpirationDateTime time.Time `json:"expirationDateTime"`
+	ClientState        string    `json:"clientState,omitempty"`
+}
+
+// ChangeNotification is one change delivered to a subscription's
+// notification URL.
+type ChangeNotification struct {
+	SubscriptionID string `json:"subscriptionId"`
+	ClientState    string `json:"clientState"`
+	ChangeType     string `json:"changeType"`
+	Resource       string `json:"resource"`
+	ResourceData   struct {
+		ID string `json:"id"`
+	} `json:"resourceData"`
+}
+
+// Subscriptions manages change-notification subscriptions.
+type Subscriptions struct {
+	Client *http.Client
+}
+
+// NewSubscriptions creates a new Subscriptions client.
+func NewSubscriptions(client *http.Client) *Subscriptions {
+	return &Subscriptions{Client: client}
+}
+
+// MailSubscription returns a subscription for messages created in a mail
+// folder (ID or well-known name, default inbox) of o's mailbox.
+func (o *Outlook) MailSubscription(folderID, notificationURL, clientState string) Subscription {
+	if folderID == "" {
+		folderID = "inbox"
+	}
+	resource := "me"
+	if o.Mailbox != "" {
+		resource = "users/" + o.Mailbox
+	}
+	return Subscription{
+		Resource:           resource + "/mailFolders('" + folderID + "')/messages",
+		ChangeType:         "created",
+		NotificationURL:    notificationURL,
+		ExpirationDateTime: time.Now().Add(MailSubscriptionLifetime).UTC(),
+		ClientState:        clientState,
+	}
+}
+
+// Create registers a subscription. Graph validates the notification URL
+// before answering, so a NotificationHandler must already be serving it.
+func (s *Subscriptions) Create(ctx context.Context, sub Subscription) (*Subscription, error) {
+	var created Subscription
+	if _, err := doJSON(ctx, s.Client, "POST", graphBase()+"/subscriptions", "create subscription", nil, sub, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// List returns the app's active subscriptions for the signed-in user.
+func (s *Subscriptions) List(ctx context.Context) ([]Subscription, error) {
+	return GetAll[Subscription](ctx, s.Client, graphBase()+"/subscriptions", "list subscriptions", 0)
+}
+
+// Renew extends a subscription until expires.
+func (s *Subscriptions) Renew(ctx context.Context, id string, expires time.Time) (*Subscription, error) {
+	var renewed Subscription
+	payload := map[string]any{"expirationDateTime": expires.UTC()}
+	if _, err := doJSON(ctx, s.Client, "PATCH", graphBase()+"/subscriptions/"+url.PathEscape(id), "renew subscription", nil, payload, &renewed); err != nil {
+		return nil, err
+	}
+	return &renewed, nil
+}
+
+// Delete removes a subscription.
+func (s *Subscriptions) Delete(ctx context.Context, id string) error {
+	_, err := doJSON(ctx, s.Client, "DELETE", graphBase()+"/subscriptions/"+url.PathEscape(id), "delete subscription", nil, nil, nil)
+	return err
+}
+
+// NotificationHandler returns an http.Handler for a subscription's
+// notification URL. It answers Graph's validation request, and passes each
+// notification carrying clientState to fn. fn runs after the response is
+// sent, since Graph expects an answer within a few seconds.
+func NotificationHandler(clientState string, fn func(ChangeNotification)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("validationToken"); token != "" {
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, token)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var payload struct {
+			Value []ChangeNotification `json:"value"`
+		}
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&payload); err != nil {
+			http.Error(w, "invalid notification", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+
+		go func() {
+			for _, n := range payload.Value {
+				// Anyone can post to the URL; the client state proves it's Graph.
+				if n.ClientState == clientState {
+					fn(n)
+				}
+			}
+		}()
+	})
+}
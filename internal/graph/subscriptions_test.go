@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionsLifecycle(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "POST":
+			var sub Subscription
+			json.NewDecoder(r.Body).Decode(&sub)
+			if sub.Resource != "users/shared@contoso.com/mailFolders('inbox')/messages" || sub.ChangeType != "created" || sub.ClientState != "secret" {
+				t.Errorf("subscription = %+v", sub)
+			}
+			sub.ID = "s1"
+			json.NewEncoder(w).Encode(sub)
+		case "PATCH":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["expirationDateTime"] == "" {
+				t.Error("renewal without expirationDateTime")
+			}
+			json.NewEncoder(w).Encode(Subscription{ID: "s1"})
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}
+	o := &Outlook{Client: client, Mailbox: "shared@contoso.com"}
+	s := NewSubscriptions(client)
+	ctx := context.Background()
+
+	sub, err := s.Create(ctx, o.MailSubscription("", "https://example.com/hook", "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Renew(ctx, sub.ID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, sub.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "POST /v1.0/subscriptions,PATCH /v1.0/subscriptions/s1,DELETE /v1.0/subscriptions/s1"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("calls = %s", got)
+	}
+}
+
+func TestNotificationHandler(t *testing.T) {
+	got := make(chan string, 2)
+	h := NotificationHandler("secret", func(n ChangeNotification) {
+		got <- n.ResourceData.ID
+	})
+
+	// Graph validates the URL by posting a token it expects echoed back.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/?validationToken=abc%20123", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "abc 123" || rec.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("validation: %d %q", rec.Code, rec.Body.String())
+	}
+
+	body := `{"value":[
+		{"clientState":"wrong","resourceData":{"id":"forged"}},
+		{"clientState":"secret","changeType":"created","resourceData":{"id":"m1"}}
+	]}`
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d", rec.Code)
+	}
+
+	select {
+	case id := <-got:
+		if id != "m1" {
+			t.Errorf("dispatched %q", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notification was not dispatched")
+	}
+	select {
+	case id := <-got:
+		t.Errorf("dispatched notification with the wrong client state: %q", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// teamPollInterval is how often CreateTeam checks team provisioning.
+var teamPollInterval = 2 * time.Second
+
+// TeamRequest describes a team to create.
+type TeamRequest struct {
+	Name        string
+	Description string
+	Visibility  string // "private" (default) or "public"
+}
+
+// ChannelRequest describes a channel to create.
+type ChannelRequest struct {
+	Name        string
+	Description string
+	Private     bool
+	// Owners and Members are added to a private channel. Standard channels
+	// are open to every member of the team.
+	Owners  []string
+	Members []string
+}
+
+// TeamMember is a member of a team or private channel.
+type TeamMember struct {
+	ID          string   `json:"id"` // membership ID
+	UserID      string   `json:"userId"`
+	DisplayName string   `json:"displayName"`
+	Email       string   `json:"email"`
+	Roles       []string `json:"roles"`
+}
+
+// IsOwner reports whether the member has the owner role.
+func (m TeamMember) IsOwner() bool {
+	for _, r := range m.Roles {
+		if r == "owner" {
+			return true
+		}
+	}
+	return false
+}
+
+// memberPayload builds a conversationMember for a user ID or email.
+func memberPayload(user string, owner bool) map[string]any {
+	roles := []string{}
+	if owner {
+		roles = []string{"owner"}
+	}
+	return map[string]any{
+		"@odata.type":     "#microsoft.graph.aadUserConversationMember",
+		"roles":           roles,
+		"user@odata.bind": graphBase() + "/users('" + url.PathEscape(user) + "')",
+	}
+}
+
+var teamIDPattern = regexp.MustCompile(`teams\('([^']+)'\)`)
+
+// CreateTeam creates a team (with its Microsoft 365 group and SharePoint
+// site) and waits until Teams has finished provisioning it. The signed-in
+// user becomes the owner.
+func (t *Teams) CreateTeam(ctx context.Context, r TeamRequest) (*Team, error) {
+	visibility := r.Visibility
+	if visibility == "" {
+		visibility = "private"
+	}
+	if visibility != "private" && visibility != "public" {
+		return nil, fmt.Errorf("invalid visibility %q — use private or public", r.Visibility)
+	}
+	payload := map[string]any{
+		"template@odata.bind": graphBase() + "/teamsTemplates('standard')",
+		"displayName":         r.Name,
+		"description":         r.Description,
+		"visibility":          visibility,
+	}
+	if r.Description == "" {
+		payload["description"] = r.Name
+	}
+
+	header, err := doJSON(ctx, t.Client, "POST", graphBase()+"/teams", "create team", nil, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	m := teamIDPattern.FindStringSubmatch(header.Get("Content-Location"))
+	if m == nil {
+		return nil, fmt.Errorf("create team: no team ID in response")
+	}
+	team := &Team{ID: m[1], DisplayName: r.Name, Description: r.Description}
+
+	if op := header.Get("Location"); op != "" {
+		if !strings.HasPrefix(op, "http") {
+			op = graphBase() + op
+		}
+		if err := t.waitForTeam(ctx, op); err != nil {
+			return team, err
+		}
+	}
+	return team, nil
+}
+
+// waitForTeam polls a team provisioning operation until it finishes.
+func (t *Teams) waitForTeam(ctx context.Context, operation string) error {
+	for {
+		var status struct {
+			Status string `json:"status"`
+			Error  *struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := t.get(ctx, operation, "team provisioning status", &status); err != nil {
+			return err
+		}
+		switch status.Status {
+		case "succeeded":
+			return nil
+		case "failed":
+			if status.Error != nil {
+				return fmt.Errorf("team provisioning failed: %s %s", status.Error.Code, status.Error.Message)
+			}
+			return fmt.Errorf("team provisioning failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(teamPollInterval):
+		}
+	}
+}
+
+// ArchiveTeam makes a team read-only, or with unarchive restores it.
+func (t *Teams) ArchiveTeam(ctx context.Context, teamID string, unarchive bool) error {
+	action := "archive"
+	if unarchive {
+		action = "unarchive"
+	}
+	_, err := doJSON(ctx, t.Client, "POST", graphBase()+"/teams/"+teamID+"/"+action, action+" team", nil, nil, nil)
+	return err
+}
+
+// ListMembers returns the members of a team.
+func (t *Teams) ListMembers(ctx context.Context, teamID string) ([]TeamMember, error) {
+	return GetAll[TeamMember](ctx, t.Client, graphBase()+"/teams/"+teamID+"/members", "Teams members", t.Limit)
+}
+
+// AddMember adds a user to a team by email, optionally as an owner.
+func (t *Teams) AddMember(ctx context.Context, teamID, email string, owner bool) (*TeamMember, error) {
+	var m TeamMember
+	if _, err := doJSON(ctx, t.Client, "POST", graphBase()+"/teams/"+teamID+"/members", "add "+email, nil, memberPayload(email, owner), &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// RemoveMember removes a user from a team by email.
+func (t *Teams) RemoveMember(ctx context.Context, teamID, email string) error {
+	members, err := t.ListMembers(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if strings.EqualFold(m.Email, email) {
+			_, err := doJSON(ctx, t.Client, "DELETE", graphBase()+"/teams/"+teamID+"/members/"+url.PathEscape(m.ID), "remove "+email, nil, nil, nil)
+			return err
+		}
+	}
+	return fmt.Errorf("%s is not a member of this team — run: kit teams members list --team <team>", email)
+}
+
+// CreateChannel creates a standard or private channel in a team.
+func (t *Teams) CreateChannel(ctx context.Context, teamID string, r ChannelRequest) (*Channel, error) {
+	if !r.Private && (len(r.Owners) > 0 || len(r.Members) > 0) {
+		return nil, fmt.Errorf("owners and members can only be set on private channels — standard channels include the whole team")
+	}
+	payload := map[string]any{
+		"displayName":    r.Name,
+		"description":    r.Description,
+		"membershipType": "standard",
+	}
+	if r.Private {
+		payload["membershipType"] = "private"
+		var members []map[string]any
+		for _, e := range r.Owners {
+			members = append(members, memberPayload(e, true))
+		}
+		for _, e := range r.Members {
+			members = append(members, memberPayload(e, false))
+		}
+		if len(members) > 0 {
+			payload["members"] = members
+		}
+	}
+
+	var ch Channel
+	if _, err := doJSON(ctx, t.Client, "POST", graphBase()+"/teams/"+teamID+"/channels", "create channel", nil, payload, &ch); err != nil {
+		return nil, err
+	}
+	return &ch, nil
+}
+
+// DeleteChannel deletes a channel. The General channel cannot be deleted.
+func (t *Teams) DeleteChannel(ctx context.Context, teamID, channelID string) error {
+	_, err := doJSON(ctx, t.Client, "DELETE", graphBase()+"/teams/"+teamID+"/channels/"+url.PathEscape(channelID), "delete channel", nil, nil, nil)
+	return err
+}
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func adminServer(t *testing.T, handler http.HandlerFunc) *Teams {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+}
+
+func TestCreateTeam(t *testing.T) {
+	teamPollInterval = time.Millisecond
+	defer func() { teamPollInterval = 2 * time.Second }()
+
+	var created map[string]any
+	polls := 0
+	tc := adminServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/teams":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Header().Set("Location", "/teams('team-1')/operations('op-1')")
+			w.Header().Set("Content-Location", "/teams('team-1')")
+			w.WriteHeader(http.StatusAccepted)
+		case "/v1.0/teams('team-1')/operations('op-1')":
+			polls++
+			if polls < 2 {
+				w.Write([]byte(`{"status":"inProgress"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"succeeded"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+
+	team, err := tc.CreateTeam(context.Background(), TeamRequest{Name: "Project Falcon"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.ID != "team-1" || polls != 2 {
+		t.Errorf("team = %+v after %d polls", team, polls)
+	}
+	if created["visibility"] != "private" || created["displayName"] != "Project Falcon" {
+		t.Errorf("payload = %v", created)
+	}
+
+	if _, err := tc.CreateTeam(context.Background(), TeamRequest{Name: "x", Visibility: "secret"}); err == nil {
+		t.Error("expected error for invalid visibility")
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	var deleted string
+	tc := adminServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1.0/teams/team-1/members":
+			w.Write([]byte(`{"value":[
+				{"id":"m-1","displayName":"Alice","email":"alice@contoso.com","roles":["owner"]},
+				{"id":"m-2","displayName":"Bob","email":"bob@contoso.com","roles":[]}]}`))
+		case r.Method == "DELETE":
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+
+	if err := tc.RemoveMember(context.Background(), "team-1", "BOB@contoso.com"); err != nil {
+		t.Fatal(err)
+	}
+	if deleted != "/v1.0/teams/team-1/members/m-2" {
+		t.Errorf("deleted %q", deleted)
+	}
+	if err := tc.RemoveMember(context.Background(), "team-1", "carol@contoso.com"); err == nil {
+		t.Error("expected error for non-member")
+	}
+}
+
+func TestCreatePrivateChannel(t *testing.T) {
+	var created map[string]any
+	tc := adminServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&created)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"19:abc@thread.tacv2","displayName":"Leads"}`))
+	})
+
+	ch, err := tc.CreateChannel(context.Background(), "team-1", ChannelRequest{
+		Name:    "Leads",
+		Private: true,
+		Owners:  []string{"alice@contoso.com"},
+		Members: []string{"bob@contoso.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.ID != "19:abc@thread.tacv2" {
+		t.Errorf("channel = %+v", ch)
+	}
+	if created["membershipType"] != "private" {
+		t.Errorf("membershipType = %v", created["membershipType"])
+	}
+	members := created["members"].([]any)
+	if len(members) != 2 {
+		t.Fatalf("members = %v", members)
+	}
+	if roles := members[0].(map[string]any)["roles"].([]any); len(roles) != 1 || roles[0] != "owner" {
+		t.Errorf("first member roles = %v", roles)
+	}
+
+	if _, err := tc.CreateChannel(context.Background(), "team-1", ChannelRequest{Name: "x", Members: []string{"a@b.c"}}); err == nil {
+		t.Error("expected error for members on a standard channel")
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
-	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -43,17 +44,13 @@ type MessageBody struct {
 	Content     string `json:"content"`
 }
 
-type teamsResponse struct {
-	Value []Team `json:"value"`
-}
-
-type channelsResponse struct {
-	Value []Channel `json:"value"`
-}
-
 // Teams provides operations on Microsoft Teams.
 type Teams struct {
 	Client *http.Client
+	// Limit caps the number of teams or channels returned by listings (0 = all).
+	Limit int
+	// Progress, if set, is notified as file uploads proceed.
+	Progress TransferObserver
 }
 
 // NewTeams creates a new Teams client with an authenticated HTTP client.
@@ -63,56 +60,12 @@ func NewTeams(client *http.Client) *Teams {
 
 // ListTeams returns all Teams the user is a member of.
 func (t *Teams) ListTeams(ctx context.Context) ([]Team, error) {
-	endpoint := graphBase + "/me/joinedTeams"
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := t.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("Teams list request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Teams API returned %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result teamsResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not parse Teams response: %w", err)
-	}
-
-	return result.Value, nil
+	return GetAll[Team](ctx, t.Client, graphBase()+"/me/joinedTeams", "Teams list", t.Limit)
 }
 
 // ListChannels returns channels in a team.
 func (t *Teams) ListChannels(ctx context.Context, teamID string) ([]Channel, error) {
-	endpoint := graphBase + "/teams/" + teamID + "/channels"
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := t.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("Teams channels request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Teams API returned %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result channelsResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not parse channels response: %w", err)
-	}
-
-	return result.Value, nil
+	return GetAll[Channel](ctx, t.Client, graphBase()+"/teams/"+teamID+"/channels", "Teams channels", t.Limit)
 }
 
 // ResolveTeamID looks up a team by display name (case-insensitive, partial match).
@@ -174,14 +127,38 @@ func (t *Teams) ResolveChannelID(ctx context.Context, teamID, nameOrID string) (
 
 // PostMessage sends a text message to a channel.
 func (t *Teams) PostMessage(ctx context.Context, teamID, channelID, text string) (*ChatMessage, error) {
-	endpoint := graphBase + "/teams/" + teamID + "/channels/" + channelID + "/messages"
+	endpoint := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "/messages"
+	return t.sendMessage(ctx, endpoint, textMessage(text), "post message")
+}
+
+// ReplyToMessage posts a reply in the thread of a channel message.
+func (t *Teams) ReplyToMessage(ctx context.Context, teamID, channelID, messageID, text string) (*ChatMessage, error) {
+	endpoint := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "/messages/" + url.PathEscape(messageID) + "/replies"
+	return t.sendMessage(ctx, endpoint, textMessage(text), "reply")
+}
+
+// PostHTMLMessage sends an HTML-formatted message to a channel.
+func (t *Teams) PostHTMLMessage(ctx context.Context, teamID, channelID, content string) (*ChatMessage, error) {
+	endpoint := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "/messages"
+	return t.sendMessage(ctx, endpoint, map[string]any{
+		"body": map[string]string{
+			"contentType": "html",
+			"content":     content,
+		},
+	}, "post message")
+}
 
-	payload := map[string]any{
+func textMessage(text string) map[string]any {
+	return map[string]any{
 		"body": map[string]string{
 			"contentType": "text",
 			"content":     text,
 		},
 	}
+}
+
+// sendMessage POSTs a chat message payload and returns the created message.
+func (t *Teams) sendMessage(ctx context.Context, endpoint string, payload any, what string) (*ChatMessage, error) {
 	jsonData, _ := json.Marshal(payload)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonData))
@@ -192,13 +169,13 @@ func (t *Teams) PostMessage(ctx context.Context, teamID, channelID, text string)
 
 	resp, err := t.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("post message failed: %w", err)
+		return nil, fmt.Errorf("%s failed: %w", what, err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("post message failed (HTTP %d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%s failed (HTTP %d): %s", what, resp.StatusCode, string(body))
 	}
 
 	var msg ChatMessage
@@ -209,154 +186,121 @@ func (t *Teams) PostMessage(ctx context.Context, teamID, channelID, text string)
 	return &msg, nil
 }
 
-// PostMessageWithFile uploads a file to the channel's Files tab and posts a message referencing it.
-func (t *Teams) PostMessageWithFile(ctx context.Context, teamID, channelID, message, filePath string) (*ChatMessage, error) {
-	// Step 1: Upload file to the team's drive
-	f, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("could not open file: %w", err)
-	}
-	defer f.Close()
-
-	info, err := f.Stat()
-	if err != nil {
-		return nil, fmt.Errorf("could not stat file: %w", err)
-	}
-	if info.Size() > 4*1024*1024 {
-		return nil, fmt.Errorf("file too large for upload (%d bytes, max 4MB)", info.Size())
-	}
-
-	fileName := info.Name()
-	uploadEndpoint := graphBase + "/teams/" + teamID + "/drive/root:/" + url.PathEscape(fileName) + ":/content"
-
-	uploadReq, err := http.NewRequestWithContext(ctx, "PUT", uploadEndpoint, f)
-	if err != nil {
-		return nil, err
-	}
-	uploadReq.Header.Set("Content-Type", "application/octet-stream")
-
-	uploadResp, err := t.Client.Do(uploadReq)
-	if err != nil {
-		return nil, fmt.Errorf("file upload failed: %w", err)
-	}
-	defer uploadResp.Body.Close()
-
-	uploadBody, _ := io.ReadAll(uploadResp.Body)
-	if uploadResp.StatusCode != http.StatusOK && uploadResp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("file upload failed (HTTP %d): %s", uploadResp.StatusCode, string(uploadBody))
-	}
+// reactionAliases maps the classic Teams reaction names to the emoji Graph
+// expects.
+var reactionAliases = map[string]string{
+	"like":      "👍",
+	"heart":     "❤️",
+	"laugh":     "😆",
+	"surprised": "😮",
+	"sad":       "😢",
+	"angry":     "😡",
+}
 
-	var uploadResult struct {
-		ID     string `json:"id"`
-		Name   string `json:"name"`
-		WebURL string `json:"webUrl"`
-		ETag   string `json:"eTag"`
+// ReactionEmoji returns the emoji for a reaction given as an emoji or as
+// one of the names like, heart, laugh, surprised, sad, or angry.
+func ReactionEmoji(reaction string) string {
+	if emoji, ok := reactionAliases[strings.ToLower(reaction)]; ok {
+		return emoji
 	}
-	json.Unmarshal(uploadBody, &uploadResult)
+	return reaction
+}
 
-	// Step 2: Post message with file reference
-	if message == "" {
-		message = "Shared: " + fileName
+// SetReaction adds (or with remove, takes back) the signed-in user's
+// reaction on a channel message. With a replyID, the reaction goes on that
+// reply in the message's thread.
+func (t *Teams) SetReaction(ctx context.Context, teamID, channelID, messageID, replyID, reaction string, remove bool) error {
+	endpoint := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "/messages/" + url.PathEscape(messageID)
+	if replyID != "" {
+		endpoint += "/replies/" + url.PathEscape(replyID)
 	}
-
-	htmlContent := fmt.Sprintf(`%s<br><a href="%s">%s</a>`, message, uploadResult.WebURL, fileName)
-	payload := map[string]any{
-		"body": map[string]string{
-			"contentType": "html",
-			"content":     htmlContent,
-		},
+	action := "setReaction"
+	if remove {
+		action = "unsetReaction"
 	}
-	jsonData, _ := json.Marshal(payload)
 
-	endpoint := graphBase + "/teams/" + teamID + "/channels/" + channelID + "/messages"
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonData))
+	jsonData, _ := json.Marshal(map[string]string{"reactionType": ReactionEmoji(reaction)})
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint+"/"+action, bytes.NewReader(jsonData))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := t.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("post message failed: %w", err)
+		return fmt.Errorf("%s failed: %w", action, err)
 	}
 	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("post message failed (HTTP %d): %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s failed (HTTP %d): %s", action, resp.StatusCode, string(body))
 	}
-
-	var msg ChatMessage
-	json.Unmarshal(body, &msg)
-	return &msg, nil
+	return nil
 }
 
-// SendDirectMessage sends a DM to a user by email address.
-func (t *Teams) SendDirectMessage(ctx context.Context, toEmail, message string) (*ChatMessage, error) {
-	// Step 1: Create or get 1:1 chat
-	chatPayload := map[string]any{
-		"chatType": "oneOnOne",
-		"members": []map[string]any{
-			{
-				"@odata.type":     "#microsoft.graph.aadUserConversationMember",
-				"roles":           []string{"owner"},
-				"user@odata.bind": graphBase + "/users/" + url.PathEscape(toEmail),
-			},
-		},
+// PostMessageWithFile uploads a file to the channel's Files tab and posts
+// it as a file attachment, which Teams shows as a file card. Files over 4MB
+// are sent through an upload session.
+func (t *Teams) PostMessageWithFile(ctx context.Context, teamID, channelID, message, filePath string) (*ChatMessage, error) {
+	var folder struct {
+		ID              string `json:"id"`
+		ParentReference struct {
+			DriveID string `json:"driveId"`
+		} `json:"parentReference"`
 	}
-	chatJSON, _ := json.Marshal(chatPayload)
-
-	chatReq, err := http.NewRequestWithContext(ctx, "POST", graphBase+"/chats", bytes.NewReader(chatJSON))
-	if err != nil {
+	if err := t.get(ctx, graphBase()+"/teams/"+teamID+"/channels/"+channelID+"/filesFolder", "get channel files folder", &folder); err != nil {
 		return nil, err
 	}
-	chatReq.Header.Set("Content-Type", "application/json")
 
-	chatResp, err := t.Client.Do(chatReq)
+	itemURL := graphBase() + "/drives/" + folder.ParentReference.DriveID + "/items/" + folder.ID + ":/" + url.PathEscape(filepath.Base(filePath))
+	item, err := uploadFile(ctx, t.Client, itemURL, filePath, t.Progress)
 	if err != nil {
-		return nil, fmt.Errorf("create chat failed: %w", err)
+		return nil, err
 	}
-	defer chatResp.Body.Close()
 
-	chatBody, _ := io.ReadAll(chatResp.Body)
-	if chatResp.StatusCode != http.StatusCreated && chatResp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("create chat failed (HTTP %d): %s", chatResp.StatusCode, string(chatBody))
-	}
+	endpoint := graphBase() + "/teams/" + teamID + "/channels/" + channelID + "/messages"
+	return t.sendMessage(ctx, endpoint, fileMessage(message, item), "post message")
+}
 
-	var chatResult struct {
-		ID string `json:"id"`
+// fileMessage builds a message with a reference attachment to an uploaded
+// file. Teams requires the attachment ID to be the GUID from the item's
+// eTag.
+func fileMessage(text string, item *DriveItem) map[string]any {
+	id := attachmentID(item.ETag)
+	content := `<attachment id="` + id + `"></attachment>`
+	if text != "" {
+		content = html.EscapeString(text) + "<br>" + content
 	}
-	json.Unmarshal(chatBody, &chatResult)
-
-	// Step 2: Send message to chat
-	msgPayload := map[string]any{
+	return map[string]any{
 		"body": map[string]string{
-			"contentType": "text",
-			"content":     message,
+			"contentType": "html",
+			"content":     content,
 		},
+		"attachments": []map[string]string{{
+			"id":          id,
+			"contentType": "reference",
+			"contentUrl":  item.WebURL,
+			"name":        item.Name,
+		}},
 	}
-	msgJSON, _ := json.Marshal(msgPayload)
+}
 
-	msgReq, err := http.NewRequestWithContext(ctx, "POST", graphBase+"/chats/"+chatResult.ID+"/messages", bytes.NewReader(msgJSON))
-	if err != nil {
-		return nil, err
-	}
-	msgReq.Header.Set("Content-Type", "application/json")
+// attachmentID extracts the GUID from an eTag such as "{1A2B...},3".
+func attachmentID(etag string) string {
+	id := strings.Trim(etag, `"`)
+	id, _, _ = strings.Cut(id, ",")
+	return strings.Trim(id, "{}")
+}
 
-	msgResp, err := t.Client.Do(msgReq)
+// SendDirectMessage sends a DM to a user by email address. Graph returns
+// the existing 1:1 chat if there already is one.
+func (t *Teams) SendDirectMessage(ctx context.Context, toEmail, message string) (*ChatMessage, error) {
+	chat, err := t.DirectChat(ctx, []string{toEmail})
 	if err != nil {
-		return nil, fmt.Errorf("send DM failed: %w", err)
-	}
-	defer msgResp.Body.Close()
-
-	msgBody, _ := io.ReadAll(msgResp.Body)
-	if msgResp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("send DM failed (HTTP %d): %s", msgResp.StatusCode, string(msgBody))
+		return nil, err
 	}
-
-	var msg ChatMessage
-	json.Unmarshal(msgBody, &msg)
-	return &msg, nil
+	return t.SendChatMessage(ctx, chat.ID, message)
 }
 
 // isUUID checks if a string looks like a UUID.
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -44,7 +46,7 @@ func TestListTeamsResponse(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result teamsResponse
+	var result Page[Team]
 	json.NewDecoder(resp.Body).Decode(&result)
 	if len(result.Value) != 3 {
 		t.Errorf("expected 3 teams, got %d", len(result.Value))
@@ -75,7 +77,7 @@ func TestListChannelsResponse(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result channelsResponse
+	var result Page[Channel]
 	json.NewDecoder(resp.Body).Decode(&result)
 	if len(result.Value) != 2 {
 		t.Errorf("expected 2 channels, got %d", len(result.Value))
@@ -106,7 +108,7 @@ func TestResolveTeamIDExact(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	var result teamsResponse
+	var result Page[Team]
 	json.NewDecoder(resp.Body).Decode(&result)
 
 	// Simulate ResolveTeamID logic
@@ -304,3 +306,108 @@ func TestIsUUID(t *testing.T) {
 		}
 	}
 }
+
+func TestReplyToMessage(t *testing.T) {
+	var gotPath, gotContent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		var sent struct {
+			Body MessageBody `json:"body"`
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		gotContent = sent.Body.Content
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"reply-1"}`))
+	}))
+	defer server.Close()
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	msg, err := tc.ReplyToMessage(context.Background(), "t1", "c1", "1700000000000", "On it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/v1.0/teams/t1/channels/c1/messages/1700000000000/replies" {
+		t.Errorf("path = %s", gotPath)
+	}
+	if gotContent != "On it" || msg.ID != "reply-1" {
+		t.Errorf("content = %q, id = %q", gotContent, msg.ID)
+	}
+}
+
+func TestSetReaction(t *testing.T) {
+	var gotPath, gotReaction string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		var sent map[string]string
+		json.NewDecoder(r.Body).Decode(&sent)
+		gotReaction = sent["reactionType"]
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	ctx := context.Background()
+
+	if err := tc.SetReaction(ctx, "t1", "c1", "m1", "", "like", false); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/v1.0/teams/t1/channels/c1/messages/m1/setReaction" || gotReaction != "👍" {
+		t.Errorf("path = %s, reaction = %q", gotPath, gotReaction)
+	}
+
+	if err := tc.SetReaction(ctx, "t1", "c1", "m1", "r1", "🎉", true); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/v1.0/teams/t1/channels/c1/messages/m1/replies/r1/unsetReaction" || gotReaction != "🎉" {
+		t.Errorf("path = %s, reaction = %q", gotPath, gotReaction)
+	}
+}
+
+func TestPostMessageWithFile(t *testing.T) {
+	var uploaded []byte
+	var sent struct {
+		Body        MessageBody         `json:"body"`
+		Attachments []map[string]string `json:"attachments"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/teams/t1/channels/c1/filesFolder":
+			w.Write([]byte(`{"id":"folder1","parentReference":{"driveId":"d1"}}`))
+		case "/v1.0/drives/d1/items/folder1:/report v2.pdf:/content":
+			uploaded, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"i1","name":"report v2.pdf","webUrl":"https://contoso.sharepoint.com/report%20v2.pdf","eTag":"\"{5D1E7F3A-0000-4C3B-9E2A-1234567890AB},1\""}`))
+		case "/v1.0/teams/t1/channels/c1/messages":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"m1"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "report v2.pdf")
+	os.WriteFile(path, []byte("%PDF"), 0644)
+
+	tc := NewTeams(&http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}})
+	if _, err := tc.PostMessageWithFile(context.Background(), "t1", "c1", "Q3 report", path); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(uploaded) != "%PDF" {
+		t.Errorf("uploaded %q", uploaded)
+	}
+	const id = "5D1E7F3A-0000-4C3B-9E2A-1234567890AB"
+	if len(sent.Attachments) != 1 {
+		t.Fatalf("attachments = %+v", sent.Attachments)
+	}
+	a := sent.Attachments[0]
+	if a["id"] != id || a["contentType"] != "reference" || a["name"] != "report v2.pdf" || a["contentUrl"] == "" {
+		t.Errorf("attachment = %+v", a)
+	}
+	if sent.Body.Content != `Q3 report<br><attachment id="`+id+`"></attachment>` {
+		t.Errorf("body = %s", sent.Body.Content)
+	}
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryBaseDelay is the first backoff when a throttled response has no
+// Retry-After header; it doubles with each retry.
+var retryBaseDelay = time.Second
+
+// maxRetryWait caps how long a single Retry-After is honored.
+const maxRetryWait = 2 * time.Minute
+
+// RetryTransport retries requests that Graph throttled (429) or that hit a
+// temporarily unavailable service (503, 504). It waits as long as the
+// Retry-After header asks, or backs off exponentially without one.
+//
+// A 503 or 504 can arrive after Graph has carried out the request, so
+// POST and PATCH requests, which would be applied twice, are retried on
+// 429 only.
+type RetryTransport struct {
+	Base http.RoundTripper
+	// MaxRetries is how many times a request is retried (default 5).
+	MaxRetries int
+	// OnRetry, if set, is called before each retry with the attempt
+	// number (1 for the first retry), the wait, and the status received.
+	OnRetry func(attempt int, wait time.Duration, status int)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	maxRetries := t.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 5
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if err != nil || !retryable(req.Method, resp.StatusCode) || attempt >= maxRetries {
+			return resp, err
+		}
+		// A body that cannot be replayed cannot be retried.
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		wait := retryAfter(resp.Header.Get("Retry-After"), attempt)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if t.OnRetry != nil {
+			t.OnRetry(attempt+1, wait, resp.StatusCode)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryable reports whether a request can be sent again after status.
+func retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(method)
+	}
+	return false
+}
+
+// idempotent reports whether sending a request with method twice has the
+// same effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the wait a Retry-After header asks for, in seconds,
+// or an exponential backoff when there is none.
+func retryAfter(header string, attempt int) time.Duration {
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return min(time.Duration(secs)*time.Second, maxRetryWait)
+	}
+	return min(retryBaseDelay<<attempt, maxRetryWait)
+}
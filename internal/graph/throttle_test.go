@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("attempt %d body = %q", calls, body)
+		}
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var retried []int
+	client := &http.Client{Transport: &RetryTransport{OnRetry: func(attempt int, wait time.Duration, status int) {
+		retried = append(retried, status)
+	}}}
+	req, _ := http.NewRequest("POST", server.URL, bytes.NewReader([]byte("payload")))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated || calls != 3 || len(retried) != 2 {
+		t.Errorf("status %d after %d calls, retries %v", resp.StatusCode, calls, retried)
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = time.Second }()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryTransport{MaxRetries: 2}}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls != 3 {
+		t.Errorf("status %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+func TestRetryTransportDoesNotReplayPostOnGatewayErrors(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = time.Second }()
+
+	for _, tt := range []struct {
+		method string
+		calls  int
+	}{
+		{"POST", 1},
+		{"PATCH", 1},
+		{"PUT", 3},
+		{"DELETE", 3},
+	} {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}))
+
+		client := &http.Client{Transport: &RetryTransport{MaxRetries: 2}}
+		req, _ := http.NewRequest(tt.method, server.URL, bytes.NewReader([]byte("payload")))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		server.Close()
+		if calls != tt.calls {
+			t.Errorf("%s: %d calls after 504, want %d", tt.method, calls, tt.calls)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d := retryAfter("7", 0); d != 7*time.Second {
+		t.Errorf("Retry-After 7 = %s", d)
+	}
+	if d := retryAfter("", 2); d != 4*retryBaseDelay {
+		t.Errorf("backoff = %s", d)
+	}
+	if d := retryAfter("99999", 0); d != maxRetryWait {
+		t.Errorf("capped = %s", d)
+	}
+}
@@ -0,0 +1,35 @@
+package graph
+
+import "io"
+
+// TransferObserver is notified as bytes move during uploads and downloads.
+// progress.Transfer satisfies it.
+type TransferObserver interface {
+	// Begin is called once the transfer size is known. done is the number
+	// of bytes already present locally (e.g. a resumed download).
+	Begin(total, done int64)
+	Add(n int64)
+	Done()
+}
+
+// observedReader reports every read to a TransferObserver.
+type observedReader struct {
+	r   io.Reader
+	obs TransferObserver
+}
+
+func (o *observedReader) Read(p []byte) (int, error) {
+	n, err := o.r.Read(p)
+	if n > 0 {
+		o.obs.Add(int64(n))
+	}
+	return n, err
+}
+
+// observe wraps r so reads are reported to obs. A nil observer returns r.
+func observe(r io.Reader, obs TransferObserver) io.Reader {
+	if obs == nil {
+		return r
+	}
+	return &observedReader{r: r, obs: obs}
+}
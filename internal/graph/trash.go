@@ -0,0 +1,262 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/cloud"
+)
+
+// graphBetaBase() returns the configured cloud's beta endpoint, which hosts
+// the recycle bin endpoints that are not yet in v1.0.
+func graphBetaBase() string {
+	return cloud.Current().GraphBeta()
+}
+
+// TrashItem is an entry in a drive's recycle bin.
+type TrashItem struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Size        int64     `json:"size"`
+	DeletedAt   time.Time `json:"deletedAt"`
+	DeletedFrom string    `json:"deletedFrom"`
+	DeletedBy   string    `json:"deletedBy"`
+}
+
+// UnmarshalJSON maps Graph's recycleBinItem onto TrashItem.
+func (t *TrashItem) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		ID                  string    `json:"id"`
+		Title               string    `json:"title"`
+		Name                string    `json:"name"`
+		Size                int64     `json:"size"`
+		DeletedDateTime     time.Time `json:"deletedDateTime"`
+		DeletedFromLocation string    `json:"deletedFromLocation"`
+		DeletedBy           *struct {
+			User *struct {
+				DisplayName string `json:"displayName"`
+			} `json:"user"`
+		} `json:"deletedBy"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	t.ID = aux.ID
+	t.Name = aux.Title
+	if t.Name == "" {
+		t.Name = aux.Name
+	}
+	t.Size = aux.Size
+	t.DeletedAt = aux.DeletedDateTime
+	t.DeletedFrom = aux.DeletedFromLocation
+	if aux.DeletedBy != nil && aux.DeletedBy.User != nil {
+		t.DeletedBy = aux.DeletedBy.User.DisplayName
+	}
+	return nil
+}
+
+// DeleteItem removes an item by path. Without permanent, the item moves to
+// the recycle bin and can be restored with RestoreTrash.
+func (o *OneDrive) DeleteItem(ctx context.Context, itemPath string, permanent bool) error {
+	item, err := o.GetItem(ctx, itemPath)
+	if err != nil {
+		return err
+	}
+
+	method, endpoint := "DELETE", graphBase()+o.drivePath()+"/items/"+item.ID
+	if permanent {
+		method, endpoint = "POST", endpoint+"/permanentDelete"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("delete request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("delete failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// ListTrash returns the items in the drive's recycle bin, newest first.
+// Only OneDrive for work or school drives have a recycle bin API.
+func (o *OneDrive) ListTrash(ctx context.Context) ([]TrashItem, error) {
+	site, err := o.siteForDrive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return listRecycleBin(ctx, o.Client, site, o.Limit)
+}
+
+// ListSiteTrash returns the items in a site's recycle bin, newest first.
+// It covers files, folders, and list items deleted from any library.
+func (sp *SharePoint) ListSiteTrash(ctx context.Context, siteID string) ([]TrashItem, error) {
+	return listRecycleBin(ctx, sp.Client, siteID, sp.Limit)
+}
+
+// RestoreSiteTrash restores site recycle bin items to their original location.
+func (sp *SharePoint) RestoreSiteTrash(ctx context.Context, siteID string, ids []string) error {
+	return recycleBinAction(ctx, sp.Client, siteID, "restore", ids)
+}
+
+func listRecycleBin(ctx context.Context, client *http.Client, siteID string, limit int) ([]TrashItem, error) {
+	endpoint := graphBetaBase() + "/sites/" + siteID + "/recycleBin/items"
+	items, err := GetAll[TrashItem](ctx, client, endpoint, "recycle bin", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// RestoreTrash restores recycle bin items to their original location.
+func (o *OneDrive) RestoreTrash(ctx context.Context, ids []string) error {
+	return o.trashAction(ctx, "restore", ids)
+}
+
+// PurgeTrash permanently deletes recycle bin items.
+func (o *OneDrive) PurgeTrash(ctx context.Context, ids []string) error {
+	return o.trashAction(ctx, "delete", ids)
+}
+
+func (o *OneDrive) trashAction(ctx context.Context, action string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	site, err := o.siteForDrive(ctx)
+	if err != nil {
+		return err
+	}
+	return recycleBinAction(ctx, o.Client, site, action, ids)
+}
+
+func recycleBinAction(ctx context.Context, client *http.Client, site, action string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	payload, _ := json.Marshal(map[string]any{"ids": ids})
+	endpoint := graphBetaBase() + "/sites/" + site + "/recycleBin/items/" + action
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("recycle bin %s request failed: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("recycle bin %s failed (HTTP %d): %s", action, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// siteForDrive returns the SharePoint site ID ("host,siteId,webId") that
+// backs a OneDrive for work or school drive.
+func (o *OneDrive) siteForDrive(ctx context.Context) (string, error) {
+	endpoint := graphBase() + o.drivePath() + "?$select=sharePointIds"
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("drive request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OneDrive API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		SharePointIDs *sharePointIDs `json:"sharePointIds"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("could not parse drive: %w", err)
+	}
+	return result.SharePointIDs.siteID()
+}
+
+type sharePointIDs struct {
+	SiteID  string `json:"siteId"`
+	WebID   string `json:"webId"`
+	SiteURL string `json:"siteUrl"`
+}
+
+func (s *sharePointIDs) siteID() (string, error) {
+	if s == nil || s.SiteID == "" {
+		return "", fmt.Errorf("recycle bin is only available for OneDrive for work or school — use the OneDrive website for personal accounts")
+	}
+	u, err := url.Parse(s.SiteURL)
+	if err != nil || u.Host == "" {
+		return s.SiteID, nil
+	}
+	return u.Host + "," + s.SiteID + "," + s.WebID, nil
+}
+
+// FindTrash returns the recycle bin items whose ID equals ref, or whose
+// name matches ref case-insensitively.
+func FindTrash(items []TrashItem, ref string) []TrashItem {
+	for _, item := range items {
+		if item.ID == ref {
+			return []TrashItem{item}
+		}
+	}
+	var matches []TrashItem
+	for _, item := range items {
+		if strings.EqualFold(item.Name, ref) {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+// ResolveTrash maps each ID-or-name reference to exactly one recycle bin
+// item. listCmd is the command that lists the recycle bin, suggested in
+// errors for references that match no item or several.
+func ResolveTrash(items []TrashItem, refs []string, listCmd string) ([]TrashItem, error) {
+	var out []TrashItem
+	for _, ref := range refs {
+		matches := FindTrash(items, ref)
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("%q is not in the recycle bin — run: %s", ref, listCmd)
+		case 1:
+			out = append(out, matches[0])
+		default:
+			return nil, fmt.Errorf("%q matches %d deleted items — use the ID from: %s", ref, len(matches), listCmd)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTrashItemUnmarshal(t *testing.T) {
+	data := `{
+		"id": "abc-123",
+		"title": "Budget.xlsx",
+		"size": 2048,
+		"deletedDateTime": "2026-03-01T10:00:00Z",
+		"deletedFromLocation": "personal/alice_contoso_com/Documents/Finance",
+		"deletedBy": {"user": {"displayName": "Alice"}}
+	}`
+
+	var item TrashItem
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "abc-123" || item.Name != "Budget.xlsx" || item.Size != 2048 {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if item.DeletedBy != "Alice" {
+		t.Errorf("DeletedBy = %q", item.DeletedBy)
+	}
+	if item.DeletedFrom != "personal/alice_contoso_com/Documents/Finance" {
+		t.Errorf("DeletedFrom = %q", item.DeletedFrom)
+	}
+	if item.DeletedAt.IsZero() {
+		t.Error("expected DeletedAt to be parsed")
+	}
+}
+
+func TestFindTrash(t *testing.T) {
+	items := []TrashItem{
+		{ID: "1", Name: "notes.txt"},
+		{ID: "2", Name: "Report.docx"},
+		{ID: "3", Name: "report.docx"},
+	}
+
+	if got := FindTrash(items, "1"); len(got) != 1 || got[0].Name != "notes.txt" {
+		t.Errorf("FindTrash by ID = %v", got)
+	}
+	if got := FindTrash(items, "NOTES.TXT"); len(got) != 1 {
+		t.Errorf("FindTrash by name = %v", got)
+	}
+	if got := FindTrash(items, "report.docx"); len(got) != 2 {
+		t.Errorf("expected 2 matches for duplicate name, got %d", len(got))
+	}
+	if got := FindTrash(items, "missing"); len(got) != 0 {
+		t.Errorf("expected no matches, got %v", got)
+	}
+}
+
+func TestResolveTrash(t *testing.T) {
+	items := []TrashItem{
+		{ID: "1", Name: "a.txt"},
+		{ID: "2", Name: "dup.txt"},
+		{ID: "3", Name: "dup.txt"},
+	}
+
+	got, err := ResolveTrash(items, []string{"A.txt", "3"}, "kit onedrive trash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "1" || got[1].ID != "3" {
+		t.Errorf("ResolveTrash = %v", got)
+	}
+
+	if _, err := ResolveTrash(items, []string{"dup.txt"}, "kit onedrive trash"); err == nil || !strings.Contains(err.Error(), "matches 2") {
+		t.Errorf("expected error for ambiguous name, got %v", err)
+	}
+	if _, err := ResolveTrash(items, []string{"missing"}, `kit sp trash "Marketing"`); err == nil || !strings.Contains(err.Error(), `run: kit sp trash "Marketing"`) {
+		t.Errorf("expected error for missing item, got %v", err)
+	}
+}
+
+func TestSharePointIDsSiteID(t *testing.T) {
+	ids := &sharePointIDs{
+		SiteID:  "site-guid",
+		WebID:   "web-guid",
+		SiteURL: "https://contoso-my.sharepoint.com/personal/alice_contoso_com",
+	}
+	got, err := ids.siteID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "contoso-my.sharepoint.com,site-guid,web-guid" {
+		t.Errorf("siteID() = %q", got)
+	}
+
+	var personal *sharePointIDs
+	if _, err := personal.siteID(); err == nil {
+		t.Error("expected error for drive without SharePoint IDs")
+	}
+}
+
+func TestSiteTrash(t *testing.T) {
+	var restored []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/beta/sites/s/recycleBin/items":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"value": []any{
+				map[string]any{"id": "1", "title": "old.docx", "deletedDateTime": "2024-01-01T00:00:00Z"},
+				map[string]any{"id": "2", "title": "new.docx", "deletedDateTime": "2024-06-01T00:00:00Z"},
+				map[string]any{"id": "3", "title": "mid.docx", "deletedDateTime": "2024-03-01T00:00:00Z"},
+			}})
+		case r.Method == "POST" && r.URL.Path == "/beta/sites/s/recycleBin/items/restore":
+			var body struct {
+				IDs []string `json:"ids"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			restored = body.IDs
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}, Limit: 2}
+	items, err := sp.ListSiteTrash(context.Background(), "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Name != "new.docx" || items[1].Name != "mid.docx" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+
+	if err := sp.RestoreSiteTrash(context.Background(), "s", []string{"1", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(restored, ",") != "1,3" {
+		t.Errorf("restored = %v", restored)
+	}
+}
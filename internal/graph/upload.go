@@ -0,0 +1,267 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// simpleUploadLimit is the largest file sent in a single PUT; larger
+	// files go through an upload session.
+	simpleUploadLimit = 4 * 1024 * 1024
+	// uploadChunkSize is the upload session fragment size. Graph requires a
+	// multiple of 320 KiB.
+	uploadChunkSize = 10 * 320 * 1024
+)
+
+// uploadFile uploads localPath to itemURL, the path-addressed item endpoint
+// (".../root:/<path>"). Files up to 4MB use a single PUT; larger files use a
+// resumable upload session sent in fragments. Existing files are replaced.
+func uploadFile(ctx context.Context, client *http.Client, itemURL, localPath string, obs TransferObserver) (*DriveItem, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open local file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("could not stat local file: %w", err)
+	}
+
+	if obs != nil {
+		obs.Begin(info.Size(), 0)
+		defer obs.Done()
+	}
+
+	if info.Size() <= simpleUploadLimit {
+		return simpleUpload(ctx, client, itemURL+":/content", f, info.Size(), obs)
+	}
+	return sessionUpload(ctx, client, itemURL, f, info.Size(), obs)
+}
+
+func simpleUpload(ctx context.Context, client *http.Client, endpoint string, r io.Reader, size int64, obs TransferObserver) (*DriveItem, error) {
+	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, observe(r, obs))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("upload request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("upload failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	var item DriveItem
+	if err := json.Unmarshal(body, &item); err != nil {
+		return nil, fmt.Errorf("could not parse upload response: %w", err)
+	}
+	return &item, nil
+}
+
+type uploadSession struct {
+	UploadURL          string   `json:"uploadUrl"`
+	NextExpectedRanges []string `json:"nextExpectedRanges"`
+}
+
+func sessionUpload(ctx context.Context, client *http.Client, itemURL string, f io.ReaderAt, size int64, obs TransferObserver) (*DriveItem, error) {
+	payload, _ := json.Marshal(map[string]any{
+		"item": map[string]any{"@microsoft.graph.conflictBehavior": "replace"},
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST", itemURL+":/createUploadSession", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not create upload session: %w", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not create upload session (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	var session uploadSession
+	if err := json.Unmarshal(body, &session); err != nil || session.UploadURL == "" {
+		return nil, fmt.Errorf("could not parse upload session response: %s", string(body))
+	}
+
+	item, err := sendFragments(ctx, client, session.UploadURL, f, size, obs)
+	if err != nil {
+		// Best effort: release the partially uploaded session
+		if del, derr := http.NewRequestWithContext(context.Background(), "DELETE", session.UploadURL, nil); derr == nil {
+			if resp, derr := client.Do(del); derr == nil {
+				resp.Body.Close()
+			}
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// sendFragments PUTs the file to an upload session, following the ranges
+// the service asks for next.
+func sendFragments(ctx context.Context, client *http.Client, uploadURL string, f io.ReaderAt, size int64, obs TransferObserver) (*DriveItem, error) {
+	var offset int64
+	for {
+		end := offset + uploadChunkSize
+		if end > size {
+			end = size
+		}
+
+		section := io.NewSectionReader(f, offset, end-offset)
+		req, err := http.NewRequestWithContext(ctx, "PUT", uploadURL, observe(section, obs))
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = end - offset
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, end-1, size))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("upload request failed: %w", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusCreated:
+			var item DriveItem
+			if err := json.Unmarshal(body, &item); err != nil {
+				return nil, fmt.Errorf("could not parse upload response: %w", err)
+			}
+			return &item, nil
+		case http.StatusAccepted:
+			var session uploadSession
+			json.Unmarshal(body, &session)
+			next, ok := nextRangeStart(session.NextExpectedRanges)
+			if !ok {
+				next = end
+			}
+			if next <= offset || next > size {
+				return nil, fmt.Errorf("upload failed: unexpected next range %v", session.NextExpectedRanges)
+			}
+			offset = next
+		default:
+			return nil, fmt.Errorf("upload failed at byte %d (HTTP %d): %s", offset, resp.StatusCode, string(body))
+		}
+	}
+}
+
+// nextRangeStart parses the start of the first "start-end" range.
+func nextRangeStart(ranges []string) (int64, bool) {
+	if len(ranges) == 0 {
+		return 0, false
+	}
+	start, _, _ := strings.Cut(ranges[0], "-")
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil
+}
+
+// FolderUpload is the outcome of one file in a folder upload.
+type FolderUpload struct {
+	Local  string `json:"local"`
+	Remote string `json:"remote"`
+	Bytes  int64  `json:"bytes"`
+	ID     string `json:"id,omitempty"`
+	WebURL string `json:"webUrl,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// UploadFolderToLibrary uploads every file under localDir into remoteFolder,
+// recreating the subfolder structure (Graph creates missing folders; empty
+// local folders are skipped). Up to workers files are sent at once
+// (0 = default). A failed file is recorded in its result rather than
+// stopping the batch. sp.Progress, if set, receives the combined byte count.
+func (sp *SharePoint) UploadFolderToLibrary(ctx context.Context, siteID, driveID, localDir, remoteFolder string, workers int) ([]FolderUpload, error) {
+	var files []FolderUpload
+	var total int64
+	err := filepath.WalkDir(localDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, FolderUpload{
+			Local:  p,
+			Remote: path.Join(strings.Trim(remoteFolder, "/"), filepath.ToSlash(rel)),
+			Bytes:  info.Size(),
+		})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read local folder: %w", err)
+	}
+	if workers <= 0 {
+		workers = defaultTransferWorkers
+	}
+
+	var obs TransferObserver
+	if sp.Progress != nil {
+		sp.Progress.Begin(total, 0)
+		defer sp.Progress.Done()
+		obs = batchObserver{sp.Progress}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := &files[i]
+				item, err := uploadFile(ctx, sp.Client, sp.itemURL(siteID, driveID, r.Remote), r.Local, obs)
+				if err != nil {
+					r.Error = err.Error()
+					continue
+				}
+				r.ID, r.WebURL = item.ID, item.WebURL
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return files, nil
+}
+
+// itemURL returns the path-addressed endpoint for an item in a library.
+func (sp *SharePoint) itemURL(siteID, driveID, itemPath string) string {
+	return graphBase() + "/sites/" + siteID + "/drives/" + driveID + "/root:/" + url.PathEscape(strings.Trim(itemPath, "/"))
+}
@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUploadFileLargeUsesSession(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 5*1024*1024/16)
+	localPath := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(localPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var received bytes.Buffer
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/root:/big.bin:/createUploadSession"):
+			json.NewEncoder(w).Encode(map[string]any{"uploadUrl": "https://contoso.sharepoint.com/upload/s1"})
+		case r.Method == "PUT" && r.URL.Path == "/upload/s1":
+			ranges = append(ranges, r.Header.Get("Content-Range"))
+			n, _ := io.Copy(&received, r.Body)
+			if int64(received.Len()) < int64(len(content)) {
+				w.WriteHeader(http.StatusAccepted)
+				json.NewEncoder(w).Encode(map[string]any{
+					"nextExpectedRanges": []string{fmt.Sprintf("%d-", received.Len())},
+				})
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]any{"id": "item-1", "name": "big.bin", "size": n})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	od := &OneDrive{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	obs := &recordingObserver{}
+	od.Progress = obs
+
+	item, err := od.UploadFile(context.Background(), localPath, "big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "item-1" {
+		t.Errorf("ID = %q", item.ID)
+	}
+	if !bytes.Equal(received.Bytes(), content) {
+		t.Errorf("server received %d bytes, want %d", received.Len(), len(content))
+	}
+	want := []string{
+		fmt.Sprintf("bytes 0-%d/%d", uploadChunkSize-1, len(content)),
+		fmt.Sprintf("bytes %d-%d/%d", uploadChunkSize, len(content)-1, len(content)),
+	}
+	if strings.Join(ranges, ",") != strings.Join(want, ",") {
+		t.Errorf("ranges = %v, want %v", ranges, want)
+	}
+	if obs.total != int64(len(content)) || obs.added != int64(len(content)) || !obs.finished {
+		t.Errorf("progress total=%d added=%d finished=%v", obs.total, obs.added, obs.finished)
+	}
+}
+
+func TestNextRangeStart(t *testing.T) {
+	if n, ok := nextRangeStart([]string{"3276800-"}); !ok || n != 3276800 {
+		t.Errorf("got %d, %v", n, ok)
+	}
+	if n, ok := nextRangeStart([]string{"26-41", "62-"}); !ok || n != 26 {
+		t.Errorf("got %d, %v", n, ok)
+	}
+	if _, ok := nextRangeStart(nil); ok {
+		t.Error("expected no range for empty list")
+	}
+}
+
+func TestUploadFolderToLibrary(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("aaa"), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("bb"), 0644)
+
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if strings.Contains(r.URL.Path, "b.txt") {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":"accessDenied"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"id": "new-id", "webUrl": "https://contoso/a.txt"})
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	results, err := sp.UploadFolderToLibrary(context.Background(), "s", "d", dir, "/Migrated", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(paths)
+	want := []string{
+		"PUT /v1.0/sites/s/drives/d/root:/Migrated/a.txt:/content",
+		"PUT /v1.0/sites/s/drives/d/root:/Migrated/sub/b.txt:/content",
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", paths, want)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, r := range results {
+		switch r.Remote {
+		case "Migrated/a.txt":
+			if r.Error != "" || r.ID != "new-id" {
+				t.Errorf("a.txt: %+v", r)
+			}
+		case "Migrated/sub/b.txt":
+			if !strings.Contains(r.Error, "HTTP 403") {
+				t.Errorf("b.txt: expected HTTP 403 error, got %+v", r)
+			}
+		default:
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+}
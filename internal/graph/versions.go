@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// FileVersion is a previous or current version of a library file.
+type FileVersion struct {
+	ID             string    `json:"id"`
+	Size           int64     `json:"size"`
+	LastModifiedAt time.Time `json:"lastModifiedDateTime"`
+	ModifiedBy     string    `json:"modifiedBy"`
+}
+
+// UnmarshalJSON flattens lastModifiedBy.user.displayName into ModifiedBy.
+func (v *FileVersion) UnmarshalJSON(data []byte) error {
+	type Alias FileVersion
+	aux := &struct {
+		*Alias
+		LastModifiedBy struct {
+			User struct {
+				DisplayName string `json:"displayName"`
+			} `json:"user"`
+		} `json:"lastModifiedBy"`
+	}{Alias: (*Alias)(v)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	v.ModifiedBy = aux.LastModifiedBy.User.DisplayName
+	return nil
+}
+
+// Checkout checks out a library file so only the current user can edit it.
+func (sp *SharePoint) Checkout(ctx context.Context, siteID, driveID, itemPath string) error {
+	return sp.itemAction(ctx, sp.itemURL(siteID, driveID, itemPath)+":/checkout", nil, "checkout")
+}
+
+// Checkin checks in a checked-out file with an optional comment. When
+// publish is true the file is checked in as a new major (published) version.
+func (sp *SharePoint) Checkin(ctx context.Context, siteID, driveID, itemPath, comment string, publish bool) error {
+	payload := map[string]any{"comment": comment}
+	if publish {
+		payload["checkInAs"] = "published"
+	}
+	return sp.itemAction(ctx, sp.itemURL(siteID, driveID, itemPath)+":/checkin", payload, "checkin")
+}
+
+// DiscardCheckout releases a checkout without keeping the changes made
+// while the file was checked out.
+func (sp *SharePoint) DiscardCheckout(ctx context.Context, siteID, driveID, itemPath string) error {
+	return sp.itemAction(ctx, sp.itemURL(siteID, driveID, itemPath)+":/discardCheckout", nil, "discard checkout")
+}
+
+// ListVersions returns the versions of a library file, newest first.
+func (sp *SharePoint) ListVersions(ctx context.Context, siteID, driveID, itemPath string) ([]FileVersion, error) {
+	versions, err := GetAll[FileVersion](ctx, sp.Client, sp.itemURL(siteID, driveID, itemPath)+":/versions", "versions", sp.Limit)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].LastModifiedAt.After(versions[j].LastModifiedAt)
+	})
+	return versions, nil
+}
+
+// RestoreVersion makes a previous version the current version of a file.
+// The restore itself is recorded as a new version.
+func (sp *SharePoint) RestoreVersion(ctx context.Context, siteID, driveID, itemPath, versionID string) error {
+	endpoint := sp.itemURL(siteID, driveID, itemPath) + ":/versions/" + url.PathEscape(versionID) + "/restoreVersion"
+	return sp.itemAction(ctx, endpoint, nil, "restore version")
+}
+
+// itemAction POSTs an optional JSON payload to a driveItem action endpoint.
+func (sp *SharePoint) itemAction(ctx context.Context, endpoint string, payload map[string]any, what string) error {
+	var body io.Reader
+	if payload != nil {
+		data, _ := json.Marshal(payload)
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := sp.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s failed (HTTP %d): %s", what, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckinPayload(t *testing.T) {
+	var gotPath string
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.Method + " " + r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	if err := sp.Checkin(context.Background(), "s", "d", "/Specs/plan.docx", "final review", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "POST /v1.0/sites/s/drives/d/root:/Specs/plan.docx:/checkin" {
+		t.Errorf("request = %s", gotPath)
+	}
+	if got["comment"] != "final review" || got["checkInAs"] != "published" {
+		t.Errorf("payload = %v", got)
+	}
+}
+
+func TestCheckoutError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusLocked)
+		w.Write([]byte(`{"error":{"code":"resourceLocked"}}`))
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	err := sp.Checkout(context.Background(), "s", "d", "plan.docx")
+	if err == nil || !strings.Contains(err.Error(), "checkout failed (HTTP 423)") {
+		t.Errorf("expected HTTP 423 error, got %v", err)
+	}
+}
+
+func TestListVersionsNewestFirst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"value":[
+			{"id":"1.0","size":10,"lastModifiedDateTime":"2024-01-01T10:00:00Z","lastModifiedBy":{"user":{"displayName":"Alice"}}},
+			{"id":"3.0","size":30,"lastModifiedDateTime":"2024-03-01T10:00:00Z","lastModifiedBy":{"user":{"displayName":"Carol"}}},
+			{"id":"2.0","size":20,"lastModifiedDateTime":"2024-02-01T10:00:00Z","lastModifiedBy":{"user":{"displayName":"Bob"}}}
+		]}`))
+	}))
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	versions, err := sp.ListVersions(context.Background(), "s", "d", "plan.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 3 || versions[0].ID != "3.0" || versions[2].ID != "1.0" {
+		t.Fatalf("unexpected order: %+v", versions)
+	}
+	if versions[0].ModifiedBy != "Carol" {
+		t.Errorf("ModifiedBy = %q", versions[0].ModifiedBy)
+	}
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// libraryServer serves a small library tree:
+//
+//	/a.txt
+//	/Docs/b.txt
+//	/Docs/Deep/c.txt
+func libraryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(libraryHandler())
+}
+
+func libraryHandler() http.HandlerFunc {
+	file := func(name, content string) map[string]any {
+		return map[string]any{
+			"name":                         name,
+			"size":                         len(content),
+			"file":                         map[string]any{},
+			"@microsoft.graph.downloadUrl": "https://contoso.sharepoint.com/dl/" + name,
+		}
+	}
+	folder := func(name string) map[string]any {
+		return map[string]any{"name": name, "folder": map[string]any{"childCount": 1}}
+	}
+	children := map[string][]map[string]any{
+		"/sites/s/drives/d/root/children":             {file("a.txt", "aaa"), folder("Docs")},
+		"/sites/s/drives/d/root:/Docs:/children":      {file("b.txt", "bb"), folder("Deep")},
+		"/sites/s/drives/d/root:/Docs/Deep:/children": {file("c.txt", "c")},
+	}
+	contents := map[string]string{"a.txt": "aaa", "b.txt": "bb", "c.txt": "c"}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if name, ok := strings.CutPrefix(r.URL.Path, "/dl/"); ok {
+			w.Write([]byte(contents[name]))
+			return
+		}
+		items, ok := children[strings.TrimPrefix(r.URL.Path, "/v1.0")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"value": items})
+	}
+}
+
+func TestWalkLibrary(t *testing.T) {
+	server := libraryServer(t)
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	entries, err := sp.WalkLibrary(context.Background(), "s", "d", "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	want := "a.txt,Docs,Docs/b.txt,Docs/Deep,Docs/Deep/c.txt"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("paths = %s, want %s", got, want)
+	}
+
+	sp.Limit = 3
+	entries, err = sp.WalkLibrary(context.Background(), "s", "d", "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected 3 entries with limit, got %d", len(entries))
+	}
+}
+
+func TestDownloadLibraryFolder(t *testing.T) {
+	server := libraryServer(t)
+	defer server.Close()
+
+	sp := &SharePoint{Client: &http.Client{Transport: &rewriteTransport{base: server.URL, wrapped: http.DefaultTransport}}}
+	obs := &recordingObserver{}
+	sp.Progress = obs
+
+	dir := t.TempDir()
+	// One worker: recordingObserver is not safe for concurrent use.
+	results, err := sp.DownloadLibraryFolder(context.Background(), "s", "d", "/Docs", dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Error != "" {
+			t.Errorf("%s: %s", r.Path, r.Error)
+		}
+	}
+
+	for rel, want := range map[string]string{"b.txt": "bb", "Deep/c.txt": "c"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Errorf("%s: %v", rel, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", rel, got, want)
+		}
+	}
+
+	if obs.total != 3 || obs.added != 3 {
+		t.Errorf("progress total=%d added=%d, want 3/3", obs.total, obs.added)
+	}
+}
@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PostWebhook posts a message to a Teams incoming webhook or a Workflows
+// ("Post to a channel when a webhook request is received") URL. No Graph
+// sign-in is needed; the URL is the credential. Both kinds accept Adaptive
+// Cards, so plain text is sent as a card with a single text block.
+func PostWebhook(ctx context.Context, client *http.Client, webhookURL, text string, card json.RawMessage) error {
+	if card == nil {
+		if text == "" {
+			return fmt.Errorf("nothing to post")
+		}
+		card, _ = json.Marshal(newCard([]map[string]any{{"type": "TextBlock", "text": text, "wrap": true}}, nil))
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": adaptiveCardType,
+			"contentUrl":  nil,
+			"content":     card,
+		}},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL carries the webhook's secret; keep it out of the error.
+		if ue, ok := err.(*url.Error); ok {
+			err = ue.Err
+		}
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	// Office 365 connector webhooks can answer 200 with an error message.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || strings.Contains(string(body), "delivery failed") {
+		return fmt.Errorf("webhook post failed (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
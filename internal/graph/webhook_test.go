@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPostWebhook(t *testing.T) {
+	var sent struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Text string `json:"text"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	if err := PostWebhook(context.Background(), http.DefaultClient, server.URL, "Deploy finished", nil); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Type != "message" || len(sent.Attachments) != 1 {
+		t.Fatalf("payload = %+v", sent)
+	}
+	a := sent.Attachments[0]
+	if a.ContentType != "application/vnd.microsoft.card.adaptive" || a.Content.Type != "AdaptiveCard" || a.Content.Body[0].Text != "Deploy finished" {
+		t.Errorf("attachment = %+v", a)
+	}
+}
+
+func TestPostWebhookErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			http.Error(w, "Bad webhook URL", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("Webhook message delivery failed with error: Microsoft Teams endpoint returned HTTP error 413"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	card := json.RawMessage(`{"type":"AdaptiveCard"}`)
+	if err := PostWebhook(ctx, http.DefaultClient, server.URL+"/gone", "", card); err == nil {
+		t.Error("expected error for 404")
+	}
+	if err := PostWebhook(ctx, http.DefaultClient, server.URL, "", card); err == nil {
+		t.Error("expected error for a failed delivery reported with 200")
+	}
+	if err := PostWebhook(ctx, http.DefaultClient, server.URL, "", nil); err == nil {
+		t.Error("expected error for an empty message")
+	}
+}
@@ -0,0 +1,192 @@
+// Package httpclient builds the HTTP client used for Microsoft Graph and
+// Microsoft identity platform requests, applying proxy, CA bundle, timeout,
+// and TLS settings from config.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/klytics/m365kit/internal/cache"
+	"github.com/klytics/m365kit/internal/config"
+)
+
+// Settings configures the HTTP client.
+type Settings struct {
+	// ProxyURL routes all requests through an HTTP(S) proxy. When empty,
+	// the standard HTTPS_PROXY/HTTP_PROXY/NO_PROXY variables apply.
+	ProxyURL string
+	// CABundle is a PEM file of extra root certificates to trust, e.g. a
+	// corporate TLS-inspection CA. System roots are still trusted.
+	CABundle string
+	// Timeout limits each request, including reading the body. Zero means
+	// no limit, which is the default so large transfers are not cut off.
+	Timeout time.Duration
+	// MinTLSVersion is "1.2" or "1.3". Empty uses Go's default.
+	MinTLSVersion string
+	// InsecureSkipVerify disables certificate verification. For debugging only.
+	InsecureSkipVerify bool
+	// Cache enables the on-disk Graph response cache (see package cache).
+	Cache bool
+	// CacheTTL serves cached responses younger than this without revalidating.
+	CacheTTL time.Duration
+}
+
+// Load reads settings from ~/.kit/config.yaml (http.* and cache.* keys),
+// with KIT_HTTP_PROXY, KIT_CA_BUNDLE, KIT_HTTP_TIMEOUT, KIT_TLS_MIN_VERSION,
+// KIT_TLS_INSECURE, KIT_CACHE, and KIT_CACHE_TTL taking precedence.
+func Load() (Settings, error) {
+	config.Load()
+
+	s := Settings{
+		ProxyURL:           envOr("KIT_HTTP_PROXY", viper.GetString("http.proxy")),
+		CABundle:           envOr("KIT_CA_BUNDLE", viper.GetString("http.ca_bundle")),
+		MinTLSVersion:      envOr("KIT_TLS_MIN_VERSION", viper.GetString("http.tls_min_version")),
+		InsecureSkipVerify: viper.GetBool("http.insecure_skip_verify"),
+		Cache:              viper.GetBool("cache.enabled"),
+	}
+	if err := envBool("KIT_TLS_INSECURE", &s.InsecureSkipVerify); err != nil {
+		return s, err
+	}
+	if err := envBool("KIT_CACHE", &s.Cache); err != nil {
+		return s, err
+	}
+
+	if t := envOr("KIT_HTTP_TIMEOUT", viper.GetString("http.timeout")); t != "" {
+		d, err := parseTimeout(t)
+		if err != nil {
+			return s, fmt.Errorf("invalid HTTP timeout: %w", err)
+		}
+		s.Timeout = d
+	}
+
+	if t := envOr("KIT_CACHE_TTL", viper.GetString("cache.ttl")); t != "" {
+		d, err := parseTimeout(t)
+		if err != nil {
+			return s, fmt.Errorf("invalid cache TTL: %w", err)
+		}
+		s.CacheTTL = d
+	}
+
+	return s, nil
+}
+
+// New returns an HTTP client built from the configured settings.
+func New() (*http.Client, error) {
+	s, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return s.Client()
+}
+
+// Client builds an HTTP client from the settings.
+func (s Settings) Client() (*http.Client, error) {
+	t, err := s.Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	var rt http.RoundTripper = t
+	if s.Cache {
+		rt = &cache.Transport{Base: t, Dir: cache.DefaultDir(), TTL: s.CacheTTL}
+	}
+	return &http.Client{Transport: rt, Timeout: s.Timeout}, nil
+}
+
+// Transport builds an HTTP transport from the settings.
+func (s Settings) Transport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if s.ProxyURL != "" {
+		u, err := url.Parse(s.ProxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q — expected e.g. http://proxy.corp:8080", s.ProxyURL)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	tlsCfg := &tls.Config{InsecureSkipVerify: s.InsecureSkipVerify}
+
+	switch s.MinTLSVersion {
+	case "":
+	case "1.2":
+		tlsCfg.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsCfg.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS version %q (valid: 1.2, 1.3)", s.MinTLSVersion)
+	}
+
+	if s.CABundle != "" {
+		pool, err := certPool(s.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	t.TLSClientConfig = tlsCfg
+	return t, nil
+}
+
+// certPool returns the system roots plus the certificates in path.
+func certPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", path)
+	}
+	return pool, nil
+}
+
+// parseTimeout accepts a Go duration ("90s", "2m") or a number of seconds.
+func parseTimeout(s string) (time.Duration, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("invalid duration %q — must not be negative", s)
+		}
+		return time.Duration(n) * time.Second, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q — use e.g. 60s or 2m", s)
+	}
+	return d, nil
+}
+
+// envBool overrides *dst when the environment variable is set.
+func envBool(key string, dst *bool) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", key, v, err)
+	}
+	*dst = b
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,167 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/klytics/m365kit/internal/cache"
+)
+
+func setupHome(t *testing.T) {
+	t.Helper()
+	viper.Reset()
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(viper.Reset)
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	setupHome(t)
+	t.Setenv("KIT_HTTP_PROXY", "http://proxy.corp:8080")
+	t.Setenv("KIT_HTTP_TIMEOUT", "90")
+	t.Setenv("KIT_TLS_MIN_VERSION", "1.3")
+
+	s, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ProxyURL != "http://proxy.corp:8080" {
+		t.Errorf("ProxyURL = %q", s.ProxyURL)
+	}
+	if s.Timeout != 90*time.Second {
+		t.Errorf("Timeout = %v, want 90s", s.Timeout)
+	}
+	if s.MinTLSVersion != "1.3" {
+		t.Errorf("MinTLSVersion = %q", s.MinTLSVersion)
+	}
+}
+
+func TestLoadFromConfig(t *testing.T) {
+	setupHome(t)
+	viper.Set("http.timeout", "2m")
+	viper.Set("http.insecure_skip_verify", true)
+
+	s, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Timeout != 2*time.Minute {
+		t.Errorf("Timeout = %v, want 2m", s.Timeout)
+	}
+	if !s.InsecureSkipVerify {
+		t.Error("expected InsecureSkipVerify from config")
+	}
+}
+
+func TestLoadInvalidTimeout(t *testing.T) {
+	setupHome(t)
+	t.Setenv("KIT_HTTP_TIMEOUT", "soon")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for invalid timeout")
+	}
+}
+
+func TestTransportProxy(t *testing.T) {
+	tr, err := Settings{ProxyURL: "http://proxy.corp:8080"}.Transport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("GET", "https://graph.microsoft.com/v1.0/me", nil)
+	u, err := tr.Proxy(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u == nil || u.Host != "proxy.corp:8080" {
+		t.Errorf("proxy = %v, want proxy.corp:8080", u)
+	}
+}
+
+func TestTransportInvalidProxy(t *testing.T) {
+	if _, err := (Settings{ProxyURL: "proxy.corp"}).Transport(); err == nil {
+		t.Error("expected error for proxy URL without scheme")
+	}
+}
+
+func TestTransportTLSVersion(t *testing.T) {
+	tr, err := Settings{MinTLSVersion: "1.2"}.Transport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.TLSClientConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x", tr.TLSClientConfig.MinVersion)
+	}
+	if _, err := (Settings{MinTLSVersion: "1.0"}).Transport(); err == nil {
+		t.Error("expected error for TLS 1.0")
+	}
+}
+
+func TestCABundleTrustsCustomRoot(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// Without the bundle, the test server's self-signed cert is rejected
+	plain, err := Settings{}.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plain.Get(server.URL); err == nil {
+		t.Fatal("expected certificate error without CA bundle")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(bundle, pem.EncodeToMemory(block), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := Settings{CABundle: bundle}.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with CA bundle failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestCABundleWithoutCertificates(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(bundle, []byte("not a certificate"), 0644)
+	if _, err := (Settings{CABundle: bundle}).Transport(); err == nil {
+		t.Error("expected error for bundle without certificates")
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	client, err := Settings{Timeout: 5 * time.Second}.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v", client.Timeout)
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	client, err := Settings{Cache: true, CacheTTL: time.Minute}.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, ok := client.Transport.(*cache.Transport)
+	if !ok {
+		t.Fatalf("Transport = %T, want *cache.Transport", client.Transport)
+	}
+	if ct.TTL != time.Minute {
+		t.Errorf("TTL = %v", ct.TTL)
+	}
+}
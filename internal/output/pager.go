@@ -17,13 +17,14 @@ func ShouldPage(content string, termHeight int) bool {
 }
 
 // Page pipes content through the user's preferred pager (PAGER env, or "less").
+// PAGER may include arguments, like "less -R".
 func Page(content string) error {
-	pager := os.Getenv("PAGER")
-	if pager == "" {
-		pager = "less"
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less"}
 	}
 
-	cmd := exec.Command(pager)
+	cmd := exec.Command(pager[0], pager[1:]...)
 	cmd.Stdin = strings.NewReader(content)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
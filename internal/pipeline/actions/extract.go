@@ -2,21 +2,28 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/config"
 	"github.com/klytics/m365kit/internal/pipeline"
 )
 
 const defaultPipelineExtractPrompt = "You are a precise entity extractor. Extract the requested fields from the following document. Return the results as a JSON object."
 
+const pipelineAnalyzePartPrompt = "You are a data analyst. Note the trends, anomalies, and key findings in this part of a longer data set or document, with the figures that support them."
+
+const pipelineExtractPartPrompt = "You are a precise entity extractor. Extract the requested fields from this part of a longer document as a JSON object, setting fields that don't appear in this part to null."
+
 // AIAnalyzeAction performs structured analysis using AI.
 func AIAnalyzeAction(ctx context.Context, step pipeline.Step, input string) (string, error) {
 	if input == "" {
 		return "", fmt.Errorf("ai.analyze requires input text")
 	}
 
-	provider, err := ai.NewProvider("anthropic", "")
+	provider, err := ai.NewProvider(config.AIProvider(), "")
 	if err != nil {
 		return "", err
 	}
@@ -26,8 +33,16 @@ func AIAnalyzeAction(ctx context.Context, step pipeline.Step, input string) (str
 		prompt = p
 	}
 
+	condensed, err := condense(ctx, provider, step, input, pipelineAnalyzePartPrompt)
+	if err != nil {
+		return "", fmt.Errorf("AI analyze failed: %w", err)
+	}
+	if condensed.Chunks > 1 {
+		prompt += ai.ReduceNote
+	}
+
 	messages := []ai.Message{
-		{Role: "user", Content: input},
+		{Role: "user", Content: condensed.Text},
 	}
 
 	result, inferErr := provider.Infer(ctx, prompt, messages, ai.InferOptions{})
@@ -44,18 +59,31 @@ func AIExtractAction(ctx context.Context, step pipeline.Step, input string) (str
 		return "", fmt.Errorf("ai.extract requires input text")
 	}
 
-	provider, err := ai.NewProvider("anthropic", "")
+	provider, err := ai.NewProvider(config.AIProvider(), "")
 	if err != nil {
 		return "", err
 	}
 
-	prompt := defaultPipelineExtractPrompt
+	if path, ok := step.Options["schema"]; ok {
+		return extractSchema(ctx, provider, step, input, path)
+	}
+
+	prompt, partPrompt := defaultPipelineExtractPrompt, pipelineExtractPartPrompt
 	if fields, ok := step.Options["fields"]; ok {
 		prompt += fmt.Sprintf("\n\nFields to extract: %s", fields)
+		partPrompt += fmt.Sprintf("\n\nFields to extract: %s", fields)
+	}
+
+	condensed, err := condense(ctx, provider, step, input, partPrompt)
+	if err != nil {
+		return "", fmt.Errorf("AI extract failed: %w", err)
+	}
+	if condensed.Chunks > 1 {
+		prompt += ai.ReduceNote + " Merge the parts' values into one JSON object, preferring non-null values."
 	}
 
 	messages := []ai.Message{
-		{Role: "user", Content: input},
+		{Role: "user", Content: condensed.Text},
 	}
 
 	result, inferErr := provider.Infer(ctx, prompt, messages, ai.InferOptions{})
@@ -65,3 +93,40 @@ func AIExtractAction(ctx context.Context, step pipeline.Step, input string) (str
 
 	return result.Content, nil
 }
+
+// extractSchema extracts input to the JSON Schema at path, validating the
+// result (see ai.ExtractSchema); the retries option overrides how many
+// times an invalid response is retried.
+func extractSchema(ctx context.Context, provider ai.Provider, step pipeline.Step, input, path string) (string, error) {
+	schema, raw, err := ai.LoadSchema(path)
+	if err != nil {
+		return "", err
+	}
+	retries := ai.DefaultExtractRetries
+	if v, ok := step.Options["retries"]; ok {
+		if retries, err = strconv.Atoi(v); err != nil || retries < 0 {
+			return "", fmt.Errorf("invalid retries %q", v)
+		}
+	}
+
+	prompt := ai.SchemaPrompt(raw)
+	condensed, err := condense(ctx, provider, step, input, ai.SchemaPartPrompt(raw))
+	if err != nil {
+		return "", fmt.Errorf("AI extract failed: %w", err)
+	}
+	if condensed.Chunks > 1 {
+		prompt += ai.ReduceNote + " Merge the parts' values into one JSON object, preferring non-null values."
+	}
+
+	ex, err := ai.ExtractSchema(ctx, provider, schema, prompt, []ai.Message{
+		{Role: "user", Content: condensed.Text},
+	}, retries)
+	if err != nil {
+		return "", fmt.Errorf("AI extract failed: %w", err)
+	}
+	data, err := json.MarshalIndent(ex.Data, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
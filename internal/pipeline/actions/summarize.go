@@ -3,20 +3,24 @@ package actions
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/config"
 	"github.com/klytics/m365kit/internal/pipeline"
 )
 
 const defaultPipelineSummarizePrompt = "You are a precise document analyst. Summarize the following document concisely, capturing key points, decisions, dates, and action items."
 
+const pipelineSummarizePartPrompt = "You are a precise document analyst. Summarize this part of a longer document, keeping its key points, decisions, dates, names, figures, and action items."
+
 // AISummarizeAction summarizes text content using AI.
 func AISummarizeAction(ctx context.Context, step pipeline.Step, input string) (string, error) {
 	if input == "" {
 		return "", fmt.Errorf("ai.summarize requires input text")
 	}
 
-	provider, err := ai.NewProvider("anthropic", "")
+	provider, err := ai.NewProvider(config.AIProvider(), "")
 	if err != nil {
 		return "", err
 	}
@@ -26,8 +30,16 @@ func AISummarizeAction(ctx context.Context, step pipeline.Step, input string) (s
 		prompt = p
 	}
 
+	condensed, err := condense(ctx, provider, step, input, pipelineSummarizePartPrompt)
+	if err != nil {
+		return "", fmt.Errorf("AI summarize failed: %w", err)
+	}
+	if condensed.Chunks > 1 {
+		prompt += ai.ReduceNote
+	}
+
 	messages := []ai.Message{
-		{Role: "user", Content: input},
+		{Role: "user", Content: condensed.Text},
 	}
 
 	result, inferErr := provider.Infer(ctx, prompt, messages, ai.InferOptions{})
@@ -37,3 +49,25 @@ func AISummarizeAction(ctx context.Context, step pipeline.Step, input string) (s
 
 	return result.Content, nil
 }
+
+// condense splits input that is too long for one prompt and runs partPrompt
+// on each part (see ai.Condense). The chunk_size and chunk_overlap options
+// set the sizes in tokens.
+func condense(ctx context.Context, provider ai.Provider, step pipeline.Step, input, partPrompt string) (*ai.Condensed, error) {
+	chunk := ai.ChunkOptions{}
+	if v, ok := step.Options["chunk_size"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid chunk_size %q", v)
+		}
+		chunk.MaxChunkSize = ai.TokensToChars(n)
+	}
+	if v, ok := step.Options["chunk_overlap"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid chunk_overlap %q", v)
+		}
+		chunk.Overlap = ai.TokensToChars(n)
+	}
+	return ai.Condense(ctx, provider, input, ai.MapReduceOptions{Chunk: chunk, MapPrompt: partPrompt})
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/klytics/m365kit/cmd/version"
+	"github.com/klytics/m365kit/internal/auth"
+)
+
+// Requirements are what a plugin needs from its environment, declared
+// under requires: in plugin.yaml. kit checks them before running the
+// plugin, so a missing key or sign-in fails with a clear message instead
+// of somewhere inside the plugin.
+type Requirements struct {
+	Env    []string `yaml:"env" json:"env,omitempty"`       // environment variables that must be set
+	Auth   bool     `yaml:"auth" json:"auth,omitempty"`     // a Microsoft 365 sign-in
+	Scopes []string `yaml:"scopes" json:"scopes,omitempty"` // Graph permissions the sign-in must have; implies auth
+}
+
+// kitVersion is the running kit version; tests replace it.
+var kitVersion = func() string { return version.Version }
+
+// CheckCompatible reports whether the running kit satisfies the plugin's
+// min_version. Development builds satisfy any version.
+func (p *Plugin) CheckCompatible() error {
+	if p.Manifest == nil || p.Manifest.MinVersion == "" {
+		return nil
+	}
+	current := kitVersion()
+	if current == "dev" || current == "" {
+		return nil
+	}
+	if CompareVersions(current, p.Manifest.MinVersion) < 0 {
+		return fmt.Errorf("plugin %s needs kit %s or newer, but this is kit %s — run: kit update install",
+			p.Name, strings.TrimPrefix(p.Manifest.MinVersion, "v"), strings.TrimPrefix(current, "v"))
+	}
+	return nil
+}
+
+// CheckRequirements validates the plugin's declared requirements, signing
+// in silently (refreshing the token) when it needs Microsoft 365 access.
+func (p *Plugin) CheckRequirements(ctx context.Context) error {
+	if p.Manifest == nil {
+		return nil
+	}
+	req := p.Manifest.Requires
+
+	var missing []string
+	for _, name := range req.Env {
+		if os.Getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("plugin %s needs these environment variables set: %s", p.Name, strings.Join(missing, ", "))
+	}
+
+	if !req.Auth && len(req.Scopes) == 0 {
+		return nil
+	}
+	if _, err := auth.RequireAuth(ctx); err != nil {
+		return fmt.Errorf("plugin %s needs Microsoft 365 access: %w", p.Name, err)
+	}
+	token, err := auth.LoadToken()
+	if err != nil {
+		return fmt.Errorf("plugin %s needs Microsoft 365 access: %w", p.Name, err)
+	}
+	if scopes := token.MissingScopes(req.Scopes); len(scopes) > 0 {
+		return fmt.Errorf("plugin %s needs the %s permission — run: kit auth login --add-scope %s",
+			p.Name, strings.Join(scopes, ", "), strings.Join(scopes, ","))
+	}
+	return nil
+}
+
+// Check runs CheckCompatible and CheckRequirements.
+func (p *Plugin) Check(ctx context.Context) error {
+	if err := p.CheckCompatible(); err != nil {
+		return err
+	}
+	return p.CheckRequirements(ctx)
+}
@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klytics/m365kit/internal/auth"
+)
+
+func withKitVersion(t *testing.T, v string) {
+	t.Helper()
+	old := kitVersion
+	kitVersion = func() string { return v }
+	t.Cleanup(func() { kitVersion = old })
+}
+
+func TestCheckCompatible(t *testing.T) {
+	p := &Plugin{Name: "review", Manifest: &Manifest{MinVersion: "1.4.0"}}
+
+	withKitVersion(t, "v1.3.2")
+	err := p.CheckCompatible()
+	if err == nil || !strings.Contains(err.Error(), "needs kit 1.4.0 or newer, but this is kit 1.3.2") {
+		t.Errorf("err = %v", err)
+	}
+
+	withKitVersion(t, "v1.10.0")
+	if err := p.CheckCompatible(); err != nil {
+		t.Errorf("1.10.0 should satisfy 1.4.0: %v", err)
+	}
+
+	withKitVersion(t, "dev")
+	if err := p.CheckCompatible(); err != nil {
+		t.Errorf("dev builds should run any plugin: %v", err)
+	}
+}
+
+func TestRunRefusesIncompatiblePlugin(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	withKitVersion(t, "1.2.0")
+
+	dir := filepath.Join(tmp, ".kit", "plugins", "future")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "plugin.yaml"), []byte("name: future\nmin_version: \"2.0.0\"\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "kit-future"), []byte("#!/bin/sh\nexit 0\n"), 0755)
+
+	err := Run(context.Background(), "future", nil, Invocation{})
+	if err == nil || !strings.Contains(err.Error(), "needs kit 2.0.0") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestCheckRequirements(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KIT_PROFILE", "")
+	t.Setenv("KIT_AZURE_CLIENT_ID", "app")
+	t.Setenv("ACME_API_KEY", "")
+	ctx := context.Background()
+
+	p := &Plugin{Name: "sync", Manifest: &Manifest{Requires: Requirements{Env: []string{"ACME_API_KEY"}}}}
+	if err := p.CheckRequirements(ctx); err == nil || !strings.Contains(err.Error(), "ACME_API_KEY") {
+		t.Errorf("err = %v", err)
+	}
+	t.Setenv("ACME_API_KEY", "k")
+	if err := p.CheckRequirements(ctx); err != nil {
+		t.Errorf("with the variable set: %v", err)
+	}
+
+	// Not signed in
+	p.Manifest.Requires.Auth = true
+	if err := p.CheckRequirements(ctx); err == nil || !strings.Contains(err.Error(), "needs Microsoft 365 access") {
+		t.Errorf("err = %v", err)
+	}
+
+	// Signed in, but without a permission the plugin needs
+	auth.SaveToken(&auth.Token{AccessToken: "at", ExpiresAt: time.Now().Add(time.Hour), Scope: "User.Read Files.Read"})
+	if err := p.CheckRequirements(ctx); err != nil {
+		t.Errorf("signed in: %v", err)
+	}
+	p.Manifest.Requires.Scopes = []string{"Sites.Read.All"}
+	if err := p.CheckRequirements(ctx); err == nil || !strings.Contains(err.Error(), "--add-scope Sites.Read.All") {
+		t.Errorf("err = %v", err)
+	}
+}
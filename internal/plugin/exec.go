@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// goos is the platform plugins are discovered and run for; tests replace
+// it to exercise Windows behavior.
+var goos = runtime.GOOS
+
+// windowsExts are the plugin executable extensions recognized on Windows,
+// in the order they are preferred.
+var windowsExts = []string{".exe", ".cmd", ".bat", ".ps1"}
+
+// execExt returns the Windows executable extension of fileName, lowercased,
+// or "" when it has none.
+func execExt(fileName string) string {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, e := range windowsExts {
+		if ext == e {
+			return ext
+		}
+	}
+	return ""
+}
+
+// pluginName returns the plugin name of an executable file name:
+// kit-<name>, or on Windows kit-<name>.exe/.cmd/.bat/.ps1.
+func pluginName(fileName string) (string, bool) {
+	name, ok := strings.CutPrefix(fileName, "kit-")
+	if !ok {
+		return "", false
+	}
+	if goos == "windows" {
+		name = name[:len(name)-len(execExt(name))]
+	}
+	return name, name != ""
+}
+
+// executableNames returns the file names the plugin name may have, in the
+// order they are preferred.
+func executableNames(name string) []string {
+	base := "kit-" + name
+	if goos != "windows" {
+		return []string{base}
+	}
+	names := make([]string, 0, len(windowsExts)+1)
+	for _, ext := range windowsExts {
+		names = append(names, base+ext)
+	}
+	// A script with a #! line, run through sh (e.g. from Git for Windows)
+	return append(names, base)
+}
+
+// findExecutable returns the executable of plugin name in dir, or "".
+func findExecutable(dir, name string) string {
+	for _, n := range executableNames(name) {
+		if path := filepath.Join(dir, n); isExecutable(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// lookPath finds plugin name on $PATH. exec.LookPath honors PATHEXT on
+// Windows, which does not include .ps1, so PowerShell scripts are looked
+// for separately.
+func lookPath(name string) (string, error) {
+	path, err := exec.LookPath("kit-" + name)
+	if err == nil || goos != "windows" {
+		return path, err
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if ps1 := filepath.Join(dir, "kit-"+name+".ps1"); isExecutable(ps1) {
+			return ps1, nil
+		}
+	}
+	return "", err
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if goos == "windows" {
+		// Windows has no executable bit: go by extension, or a #! line
+		return execExt(path) != "" || isScript(path)
+	}
+	return info.Mode()&0111 != 0
+}
+
+// isScript reports whether the file at path starts with a #! line.
+func isScript(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	buf := make([]byte, 2)
+	n, _ := f.Read(buf)
+	return n == 2 && string(buf) == "#!"
+}
+
+// stopGrace is how long a plugin has to exit after being interrupted
+// before it is killed.
+const stopGrace = 5 * time.Second
+
+// command returns the command that runs the plugin executable at path.
+// Windows cannot run PowerShell or #! scripts directly, so they are run
+// through powershell and sh. When ctx is done the plugin is interrupted,
+// so it can clean up, and killed if it has not exited after stopGrace.
+func command(ctx context.Context, path string, args ...string) *exec.Cmd {
+	var cmd *exec.Cmd
+	switch {
+	case goos == "windows" && execExt(path) == ".ps1":
+		args = append([]string{"-NoLogo", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", path}, args...)
+		cmd = exec.CommandContext(ctx, powerShell(), args...)
+	case goos == "windows" && execExt(path) == "" && isScript(path):
+		cmd = exec.CommandContext(ctx, "sh", append([]string{path}, args...)...)
+	default:
+		cmd = exec.CommandContext(ctx, path, args...)
+	}
+	cmd.Cancel = func() error {
+		// Windows has no interrupt signal for another process
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = stopGrace
+	return cmd
+}
+
+// powerShell returns PowerShell 7 (pwsh) when installed, or Windows
+// PowerShell.
+func powerShell() string {
+	if path, err := exec.LookPath("pwsh"); err == nil {
+		return path
+	}
+	return "powershell"
+}
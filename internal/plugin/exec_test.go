@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func withGOOS(t *testing.T, v string) {
+	t.Helper()
+	old := goos
+	goos = v
+	t.Cleanup(func() { goos = old })
+}
+
+func TestDiscoverWindowsExtensions(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	withGOOS(t, "windows")
+
+	pluginDir := filepath.Join(tmp, ".kit", "plugins")
+	os.MkdirAll(filepath.Join(pluginDir, "review"), 0755)
+	// No executable bit: Windows goes by extension
+	os.WriteFile(filepath.Join(pluginDir, "kit-sync.ps1"), []byte("Write-Output sync\n"), 0644)
+	os.WriteFile(filepath.Join(pluginDir, "kit-acl.cmd"), []byte("@echo acl\r\n"), 0644)
+	os.WriteFile(filepath.Join(pluginDir, "kit-acl.exe"), []byte("MZ\x90\x00"), 0644)
+	os.WriteFile(filepath.Join(pluginDir, "kit-notes.txt"), []byte("not a plugin"), 0644)
+	os.WriteFile(filepath.Join(pluginDir, "review", "kit-review.bat"), []byte("@echo review\r\n"), 0644)
+
+	plugins, err := Discover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]Plugin)
+	for _, p := range plugins {
+		got[p.Name] = p
+	}
+	if len(got) != 3 || len(plugins) != 3 {
+		t.Fatalf("plugins = %+v", plugins)
+	}
+	if p := got["acl"]; filepath.Base(p.Path) != "kit-acl.exe" || p.Type != "go" {
+		t.Errorf("acl = %+v, want kit-acl.exe preferred over .cmd", p)
+	}
+	if p := got["sync"]; p.Type != "powershell" {
+		t.Errorf("sync type = %q", p.Type)
+	}
+	if _, ok := got["review"]; !ok {
+		t.Error("kit-review.bat in a subdirectory not found")
+	}
+
+	// Remove takes every extension of the plugin
+	if err := Remove("acl"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(pluginDir, "kit-acl.cmd")); !os.IsNotExist(err) {
+		t.Error("kit-acl.cmd should have been removed")
+	}
+}
+
+func TestPluginName(t *testing.T) {
+	withGOOS(t, "windows")
+	tests := []struct {
+		file, name string
+		ok         bool
+	}{
+		{"kit-sync.ps1", "sync", true},
+		{"kit-acl.EXE", "acl", true},
+		{"kit-acl", "acl", true},
+		{"kit-v1.2", "v1.2", true},
+		{"kit-.exe", "", false},
+		{"sync.exe", "", false},
+	}
+	for _, tt := range tests {
+		name, ok := pluginName(tt.file)
+		if name != tt.name || ok != tt.ok {
+			t.Errorf("pluginName(%q) = %q, %v", tt.file, name, ok)
+		}
+	}
+
+	withGOOS(t, "linux")
+	if name, _ := pluginName("kit-sync.ps1"); name != "sync.ps1" {
+		t.Errorf("extensions are only stripped on Windows, got %q", name)
+	}
+}
+
+func TestCommandWindows(t *testing.T) {
+	withGOOS(t, "windows")
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	ps1 := filepath.Join(dir, "kit-sync.ps1")
+	os.WriteFile(ps1, []byte("Write-Output sync\n"), 0644)
+	args := command(ctx, ps1, "a.docx").Args
+	if !strings.Contains(strings.ToLower(filepath.Base(args[0])), "powershell") && !strings.Contains(args[0], "pwsh") {
+		t.Errorf("ps1 runs through %q", args[0])
+	}
+	if got := strings.Join(args[1:], " "); !strings.HasSuffix(got, "-File "+ps1+" a.docx") {
+		t.Errorf("args = %q", got)
+	}
+
+	script := filepath.Join(dir, "kit-acl")
+	os.WriteFile(script, []byte("#!/bin/sh\necho acl\n"), 0644)
+	if args := command(ctx, script, "x").Args; args[0] != "sh" || args[1] != script || args[2] != "x" {
+		t.Errorf("#! script args = %q", args)
+	}
+
+	exe := filepath.Join(dir, "kit-acl.exe")
+	if args := command(ctx, exe, "x").Args; args[0] != exe {
+		t.Errorf("exe args = %q", args)
+	}
+}
+
+func TestNewScaffoldPowerShell(t *testing.T) {
+	dir, err := NewScaffold("my-sync", "powershell", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "kit-my-sync.ps1"))
+	if err != nil {
+		t.Fatalf("script not created: %v", err)
+	}
+	if !strings.Contains(string(data), "$args.Count") {
+		t.Errorf("script = %s", data)
+	}
+
+	withGOOS(t, "windows")
+	if name := ScaffoldExecutable("my-tool", "go"); name != "kit-my-tool.cmd" {
+		t.Errorf("Go scaffold on Windows = %q", name)
+	}
+}
@@ -0,0 +1,140 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Lifecycle events a plugin can hook by listing them under hooks: in its
+// plugin.yaml. kit runs the plugin as "kit-<name> hook <event>" with the
+// event as JSON on stdin.
+//
+// A pre- hook can veto: if it exits non-zero, kit stops and reports what
+// the hook wrote to stderr. Other hooks are notifications, and a failure
+// is only a warning.
+const (
+	HookPreConvert        = "pre-convert"
+	HookPostConvert       = "post-convert"
+	HookPreTemplateApply  = "pre-template-apply"
+	HookPostTemplateApply = "post-template-apply"
+	HookOnWatchEvent      = "on-watch-event"
+)
+
+// HookEvents are the events plugins can hook.
+var HookEvents = []string{HookPreConvert, HookPostConvert, HookPreTemplateApply, HookPostTemplateApply, HookOnWatchEvent}
+
+// hookTimeout bounds each hook, so a stuck plugin cannot hang a command.
+const hookTimeout = 30 * time.Second
+
+// HookPayload is what a hook receives on stdin.
+type HookPayload struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data"`
+}
+
+// HookError is a hook that exited non-zero: for a pre- hook, a veto.
+type HookError struct {
+	Plugin  string
+	Event   string
+	Message string
+}
+
+func (e *HookError) Error() string {
+	verb := "failed"
+	if strings.HasPrefix(e.Event, "pre-") {
+		verb = "refused"
+	}
+	return fmt.Sprintf("%s hook of plugin %s %s: %s", e.Event, e.Plugin, verb, e.Message)
+}
+
+// Hooked returns the installed plugins that hook event.
+func Hooked(event string) []Plugin {
+	plugins, err := Discover()
+	if err != nil {
+		return nil
+	}
+	var result []Plugin
+	for _, p := range plugins {
+		if p.Manifest == nil {
+			continue
+		}
+		for _, h := range p.Manifest.Hooks {
+			if h == event {
+				result = append(result, p)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// RunHooks runs the hooks for event in plugin name order, passing data.
+// For a pre- event it stops at, and returns, the first veto; other events
+// print failures to stderr and return nil. KIT_NO_HOOKS=1 skips hooks.
+func RunHooks(ctx context.Context, event string, data any) error {
+	if boolEnv(os.Getenv("KIT_NO_HOOKS")) == "true" {
+		return nil
+	}
+	plugins := Hooked(event)
+	if len(plugins) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(HookPayload{Event: event, Time: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	veto := strings.HasPrefix(event, "pre-")
+	for _, p := range plugins {
+		if err := runHook(ctx, p, event, payload); err != nil {
+			if veto {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return nil
+}
+
+func runHook(ctx context.Context, p Plugin, event string, payload []byte) error {
+	if err := p.Check(ctx); err != nil {
+		return &HookError{Plugin: p.Name, Event: event, Message: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+
+	workspace, err := os.MkdirTemp("", "kit-"+p.Name+"-*")
+	if err != nil {
+		return &HookError{Plugin: p.Name, Event: event, Message: err.Error()}
+	}
+	defer os.RemoveAll(workspace)
+
+	var stderr bytes.Buffer
+	cmd := command(ctx, p.Path, "hook", event)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stderr // keep the command's own stdout (e.g. --json) clean
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), pluginEnv(NewInvocation(ctx), workspace)...)
+	cmd.Env = append(cmd.Env, "KIT_HOOK="+event)
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if ctx.Err() == context.DeadlineExceeded {
+			msg = fmt.Sprintf("timed out after %s", hookTimeout)
+		} else if msg == "" {
+			msg = err.Error()
+		}
+		return &HookError{Plugin: p.Name, Event: event, Message: msg}
+	}
+	if stderr.Len() > 0 {
+		os.Stderr.Write(stderr.Bytes())
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// installHookPlugin installs a plugin hooking events whose script saves
+// its stdin to <name>.json in out and exits with status.
+func installHookPlugin(t *testing.T, home, out, name string, status int, events ...string) {
+	t.Helper()
+	dir := filepath.Join(home, ".kit", "plugins", name)
+	os.MkdirAll(dir, 0755)
+	manifest := "name: " + name + "\nversion: 1.0.0\nhooks:\n"
+	for _, e := range events {
+		manifest += "  - " + e + "\n"
+	}
+	os.WriteFile(filepath.Join(dir, "plugin.yaml"), []byte(manifest), 0644)
+	script := "#!/bin/sh\n" +
+		"cat > " + filepath.Join(out, name+".json") + "\n" +
+		"echo \"$KIT_HOOK says no\" >&2\n" +
+		"exit " + strconv.Itoa(status) + "\n"
+	os.WriteFile(filepath.Join(dir, "kit-"+name), []byte(script), 0755)
+}
+
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	home, out := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KIT_NO_HOOKS", "")
+	ctx := context.Background()
+
+	installHookPlugin(t, home, out, "audit", 0, HookPostConvert, HookOnWatchEvent)
+	installHookPlugin(t, home, out, "policy", 1, HookPreConvert)
+
+	if got := Hooked(HookPreConvert); len(got) != 1 || got[0].Name != "policy" {
+		t.Errorf("Hooked(pre-convert) = %+v", got)
+	}
+
+	// A failing pre- hook vetoes
+	err := RunHooks(ctx, HookPreConvert, map[string]string{"input": "a.docx"})
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) || hookErr.Plugin != "policy" || hookErr.Message != "pre-convert says no" {
+		t.Fatalf("err = %v", err)
+	}
+
+	// Other hooks get the payload, and never fail the command
+	if err := RunHooks(ctx, HookPostConvert, map[string]string{"output": "a.md"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "audit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload struct {
+		Event string            `json:"event"`
+		Data  map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Event != HookPostConvert || payload.Data["output"] != "a.md" {
+		t.Errorf("payload = %s", data)
+	}
+
+	// KIT_NO_HOOKS turns them off
+	t.Setenv("KIT_NO_HOOKS", "1")
+	if err := RunHooks(ctx, HookPreConvert, nil); err != nil {
+		t.Errorf("with KIT_NO_HOOKS: %v", err)
+	}
+}
@@ -1,5 +1,6 @@
 // Package plugin provides plugin discovery, installation, and execution.
-// Plugins are executables named kit-<name> in ~/.kit/plugins/ or $PATH.
+// Plugins are executables named kit-<name> (on Windows, kit-<name>.exe,
+// .cmd, .bat, or .ps1) in ~/.kit/plugins/ or $PATH.
 package plugin
 
 import (
@@ -8,11 +9,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/shell"
 )
 
 // Plugin represents a discovered plugin.
@@ -22,7 +26,7 @@ type Plugin struct {
 	Description string    `json:"description"`
 	Author      string    `json:"author"`
 	Path        string    `json:"path"`
-	Type        string    `json:"type"`   // "shell" | "go" | "script"
+	Type        string    `json:"type"`   // "shell" | "powershell" | "go" | "script"
 	Source      string    `json:"source"` // install source or "local"
 	InstalledAt time.Time `json:"installed_at"`
 	Manifest    *Manifest `json:"-"`
@@ -30,12 +34,14 @@ type Plugin struct {
 
 // Manifest is the metadata file for a plugin (plugin.yaml alongside executable).
 type Manifest struct {
-	Name        string   `yaml:"name" json:"name"`
-	Version     string   `yaml:"version" json:"version"`
-	Description string   `yaml:"description" json:"description"`
-	Author      string   `yaml:"author" json:"author"`
-	MinVersion  string   `yaml:"min_version" json:"min_version"`
-	Commands    []string `yaml:"commands" json:"commands"`
+	Name        string       `yaml:"name" json:"name"`
+	Version     string       `yaml:"version" json:"version"`
+	Description string       `yaml:"description" json:"description"`
+	Author      string       `yaml:"author" json:"author"`
+	MinVersion  string       `yaml:"min_version" json:"min_version"`
+	Commands    []string     `yaml:"commands" json:"commands"`
+	Hooks       []string     `yaml:"hooks" json:"hooks,omitempty"` // lifecycle events to run on; see HookEvents
+	Requires    Requirements `yaml:"requires" json:"requires"`
 }
 
 // Dir returns the plugin directory (~/.kit/plugins/).
@@ -67,8 +73,9 @@ func Discover() ([]Plugin, error) {
 	}
 
 	var plugins []Plugin
+	seen := make(map[string]bool) // kit-x.exe and kit-x.cmd are one plugin
 
-	// 1. Direct executables: ~/.kit/plugins/kit-<name>
+	// 1. Direct executables: ~/.kit/plugins/kit-<name>[.exe|.cmd|.bat|.ps1]
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -81,18 +88,16 @@ func Discover() ([]Plugin, error) {
 		name := entry.Name()
 		if entry.IsDir() {
 			// Check subdirectory: ~/.kit/plugins/<name>/kit-<name>
-			subExec := filepath.Join(dir, name, "kit-"+name)
-			if isExecutable(subExec) {
+			if subExec := findExecutable(filepath.Join(dir, name), name); subExec != "" {
 				p := pluginFromPath(subExec, name)
 				plugins = append(plugins, p)
 			}
 			continue
 		}
-		if strings.HasPrefix(name, "kit-") {
-			fullPath := filepath.Join(dir, name)
-			if isExecutable(fullPath) {
-				pluginName := strings.TrimPrefix(name, "kit-")
-				p := pluginFromPath(fullPath, pluginName)
+		if base, ok := pluginName(name); ok && !seen[base] {
+			if fullPath := findExecutable(dir, base); fullPath != "" {
+				seen[base] = true
+				p := pluginFromPath(fullPath, base)
 				plugins = append(plugins, p)
 			}
 		}
@@ -114,8 +119,7 @@ func Get(name string) (*Plugin, error) {
 	}
 
 	// Also check $PATH for kit-<name>
-	binName := "kit-" + name
-	pathExec, err := exec.LookPath(binName)
+	pathExec, err := lookPath(name)
 	if err == nil {
 		p := pluginFromPath(pathExec, name)
 		return &p, nil
@@ -124,24 +128,61 @@ func Get(name string) (*Plugin, error) {
 	return nil, fmt.Errorf("plugin %q not found", name)
 }
 
-// Install installs a plugin from a local directory.
-func Install(source string) (*Plugin, error) {
+// Install installs a plugin from a local directory or executable, the
+// latest (or @tag) release of a GitHub repository, or the plugin registry
+// by name[@version], and records the source for later updates.
+func Install(ctx context.Context, source string) (*Plugin, error) {
 	dir, err := EnsureDir()
 	if err != nil {
 		return nil, err
 	}
 
-	// Local install: copy the directory contents
-	source = strings.TrimPrefix(source, "file://")
-	info, err := os.Stat(source)
-	if err != nil {
-		return nil, fmt.Errorf("source not found: %w", err)
+	var (
+		p       *Plugin
+		version string
+	)
+	if name, ver, ok := registryRef(source); ok {
+		p, version, err = installFromRegistry(ctx, name, ver, dir)
+		if err != nil {
+			return nil, err
+		}
+		// Updates follow the registry's latest version
+		source = registrySource + name
+	} else if repo, tag, ok := githubRepo(source); ok {
+		p, version, err = installFromGitHub(ctx, repo, tag, dir)
+		if err != nil {
+			return nil, err
+		}
+		// Updates follow the latest release, even from a pinned install
+		source = "github.com/" + repo
+	} else {
+		// Local install: copy the directory contents
+		source = strings.TrimPrefix(source, "file://")
+		info, err := os.Stat(source)
+		if err != nil {
+			return nil, fmt.Errorf("source not found: %w", err)
+		}
+		if abs, err := filepath.Abs(source); err == nil {
+			source = abs
+		}
+		if info.IsDir() {
+			p, err = installFromDir(source, dir)
+		} else {
+			p, err = installFromFile(source, dir)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.Version == "" {
+		p.Version = version
 	}
 
-	if info.IsDir() {
-		return installFromDir(source, dir)
+	if err := recordInstall(p.Name, InstallRecord{Source: source, Version: p.Version, InstalledAt: time.Now()}); err != nil {
+		return nil, fmt.Errorf("installed %s but could not record its source: %w", p.Name, err)
 	}
-	return installFromFile(source, dir)
+	p.Source = source
+	return p, nil
 }
 
 func installFromDir(source, pluginDir string) (*Plugin, error) {
@@ -177,9 +218,11 @@ func installFromDir(source, pluginDir string) (*Plugin, error) {
 		}
 	}
 
-	execPath := filepath.Join(destDir, "kit-"+manifest.Name)
+	execPath := findExecutable(destDir, manifest.Name)
+	if execPath == "" {
+		execPath = filepath.Join(destDir, "kit-"+manifest.Name)
+	}
 	p := pluginFromPath(execPath, manifest.Name)
-	p.Source = "local"
 	return &p, nil
 }
 
@@ -188,13 +231,15 @@ func installFromFile(source, pluginDir string) (*Plugin, error) {
 	if err != nil {
 		return nil, err
 	}
-	name := strings.TrimPrefix(filepath.Base(source), "kit-")
+	name, ok := pluginName(filepath.Base(source))
+	if !ok {
+		name = strings.TrimSuffix(filepath.Base(source), execExt(source))
+	}
 	dest := filepath.Join(pluginDir, filepath.Base(source))
 	if err := os.WriteFile(dest, data, 0755); err != nil {
 		return nil, err
 	}
 	p := pluginFromPath(dest, name)
-	p.Source = "local"
 	return &p, nil
 }
 
@@ -205,37 +250,87 @@ func Remove(name string) error {
 		return err
 	}
 
-	// Check direct executable
-	direct := filepath.Join(dir, "kit-"+name)
-	if _, err := os.Stat(direct); err == nil {
-		return os.Remove(direct)
+	// Check direct executables
+	removed := false
+	for _, n := range executableNames(name) {
+		direct := filepath.Join(dir, n)
+		if _, err := os.Stat(direct); err == nil {
+			if err := os.Remove(direct); err != nil {
+				return err
+			}
+			removed = true
+		}
+	}
+	if removed {
+		return forgetInstall(name)
 	}
 
 	// Check subdirectory
 	subDir := filepath.Join(dir, name)
 	if _, err := os.Stat(subDir); err == nil {
-		return os.RemoveAll(subDir)
+		if err := os.RemoveAll(subDir); err != nil {
+			return err
+		}
+		return forgetInstall(name)
 	}
 
 	return fmt.Errorf("plugin %q not found", name)
 }
 
-// Run executes a plugin with args, forwarding stdin/stdout/stderr.
-func Run(ctx context.Context, name string, args []string) error {
+// Invocation is the context of one kit command that a plugin runs under,
+// passed to it in KIT_* environment variables so it behaves like a
+// built-in command.
+type Invocation struct {
+	Site    string // default SharePoint site, from the shell session
+	Team    string // default team, from the shell session
+	JSON    bool   // --json was given
+	Verbose bool   // --verbose was given
+}
+
+// NewInvocation returns the invocation for ctx: the shell session's
+// defaults, and KIT_JSON and KIT_VERBOSE from the environment. Commands
+// set JSON and Verbose from their flags.
+func NewInvocation(ctx context.Context) Invocation {
+	d := shell.DefaultsFrom(ctx)
+	return Invocation{
+		Site:    d.Site,
+		Team:    d.Team,
+		JSON:    boolEnv(os.Getenv("KIT_JSON")) == "true",
+		Verbose: boolEnv(os.Getenv("KIT_VERBOSE")) == "true",
+	}
+}
+
+// Run executes a plugin with args, forwarding stdin/stdout/stderr. The
+// plugin gets a temporary workspace directory, removed when it exits, and
+// is interrupted when ctx is cancelled.
+func Run(ctx context.Context, name string, args []string, inv Invocation) error {
 	p, err := Get(name)
 	if err != nil {
 		return err
 	}
+	if err := p.Check(ctx); err != nil {
+		return err
+	}
 
-	cmd := exec.CommandContext(ctx, p.Path, args...)
+	workspace, err := os.MkdirTemp("", "kit-"+p.Name+"-*")
+	if err != nil {
+		return fmt.Errorf("cannot create plugin workspace: %w", err)
+	}
+
+	cmd := command(ctx, p.Path, args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	// Set M365Kit environment variables
-	cmd.Env = append(os.Environ(), pluginEnv()...)
+	cmd.Env = append(os.Environ(), pluginEnv(inv, workspace)...)
 
-	if err := cmd.Run(); err != nil {
+	err = cmd.Run()
+	os.RemoveAll(workspace)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("plugin %s stopped: %w", p.Name, ctx.Err())
+		}
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			os.Exit(exitErr.ExitCode())
 		}
@@ -261,7 +356,20 @@ func LoadManifest(dir string) (*Manifest, error) {
 	return &m, nil
 }
 
-// NewScaffold creates a new plugin scaffold in outputDir.
+// ScaffoldExecutable returns the file name of the executable NewScaffold
+// writes for a plugin.
+func ScaffoldExecutable(name, pluginType string) string {
+	switch {
+	case pluginType == "powershell":
+		return "kit-" + name + ".ps1"
+	case pluginType == "go" && goos == "windows":
+		return "kit-" + name + ".cmd"
+	}
+	return "kit-" + name
+}
+
+// NewScaffold creates a new plugin scaffold in outputDir. pluginType is
+// shell, powershell, or go.
 func NewScaffold(name, pluginType, outputDir string) (string, error) {
 	dir := filepath.Join(outputDir, name)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -281,7 +389,7 @@ commands:
 		return "", err
 	}
 
-	execName := "kit-" + name
+	execName := ScaffoldExecutable(name, pluginType)
 	switch pluginType {
 	case "shell":
 		script := fmt.Sprintf(`#!/bin/bash
@@ -301,6 +409,27 @@ if [ $# -eq 0 ]; then
 fi
 
 echo "Plugin %s processing: $1"
+`, name, name, name, name)
+		if err := os.WriteFile(filepath.Join(dir, execName), []byte(script), 0755); err != nil {
+			return "", err
+		}
+	case "powershell":
+		script := fmt.Sprintf(`# M365Kit plugin: %s
+# Usage: kit %s <args>
+#
+# Environment variables provided by M365Kit:
+#   $env:KIT_VERSION     — current M365Kit version
+#   $env:KIT_CONFIG_PATH — path to config.yaml
+#   $env:KIT_JSON        — "true" if --json output requested
+
+$ErrorActionPreference = 'Stop'
+
+if ($args.Count -eq 0) {
+    [Console]::Error.WriteLine("Usage: kit %s <args>")
+    exit 1
+}
+
+Write-Output "Plugin %s processing: $($args[0])"
 `, name, name, name, name)
 		if err := os.WriteFile(filepath.Join(dir, execName), []byte(script), 0755); err != nil {
 			return "", err
@@ -333,11 +462,15 @@ func main() {
 			return "", err
 		}
 		// Write a placeholder executable
-		if err := os.WriteFile(filepath.Join(dir, execName), []byte("#!/bin/bash\ngo run . \"$@\"\n"), 0755); err != nil {
+		placeholder := "#!/bin/bash\ngo run . \"$@\"\n"
+		if goos == "windows" {
+			placeholder = "@go run . %*\r\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, execName), []byte(placeholder), 0755); err != nil {
 			return "", err
 		}
 	default:
-		return "", fmt.Errorf("unsupported plugin type: %s (use shell or go)", pluginType)
+		return "", fmt.Errorf("unsupported plugin type: %s (use shell, powershell, or go)", pluginType)
 	}
 
 	// Write README.md
@@ -370,10 +503,32 @@ func pluginFromPath(path, name string) Plugin {
 		p.InstalledAt = info.ModTime()
 	}
 
+	if records, err := loadInstalls(); err == nil {
+		if rec, ok := records[name]; ok && isInstalledCopy(path) {
+			p.Source, p.InstalledAt = rec.Source, rec.InstalledAt
+			if p.Version == "" {
+				p.Version = rec.Version
+			}
+		}
+	}
+
 	return p
 }
 
+// isInstalledCopy reports whether path is in the plugin directory, as
+// opposed to a kit-<name> found on $PATH.
+func isInstalledCopy(path string) bool {
+	dir, err := Dir()
+	return err == nil && strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
+
 func detectType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ps1":
+		return "powershell"
+	case ".cmd", ".bat":
+		return "script"
+	}
 	data, err := os.ReadFile(path)
 	if err != nil || len(data) == 0 {
 		return "script"
@@ -381,7 +536,10 @@ func detectType(path string) string {
 	if strings.HasPrefix(string(data), "#!/") {
 		return "shell"
 	}
-	// Check for ELF or Mach-O magic bytes (compiled binary)
+	// Check for ELF, Mach-O, or PE magic bytes (compiled binary)
+	if len(data) >= 2 && data[0] == 'M' && data[1] == 'Z' {
+		return "go"
+	}
 	if len(data) >= 4 {
 		if data[0] == 0x7f && data[1] == 'E' && data[2] == 'L' && data[3] == 'F' {
 			return "go"
@@ -397,27 +555,22 @@ func detectType(path string) string {
 	return "script"
 }
 
-func isExecutable(path string) bool {
-	info, err := os.Stat(path)
+func pluginEnv(inv Invocation, workspace string) []string {
+	home, _ := os.UserHomeDir()
+	tokenPath, err := auth.TokenPath()
 	if err != nil {
-		return false
+		tokenPath = filepath.Join(home, ".kit", "token.json")
 	}
-	if runtime.GOOS == "windows" {
-		// On Windows, check for common executable extensions
-		ext := strings.ToLower(filepath.Ext(path))
-		return ext == ".exe" || ext == ".bat" || ext == ".cmd"
-	}
-	return info.Mode()&0111 != 0
-}
-
-func pluginEnv() []string {
-	home, _ := os.UserHomeDir()
 	return []string{
-		"KIT_VERSION=1.2.0",
+		"KIT_VERSION=" + kitVersion(),
 		"KIT_CONFIG_PATH=" + filepath.Join(home, ".kit", "config.yaml"),
-		"KIT_TOKEN_PATH=" + filepath.Join(home, ".kit", "token.json"),
-		"KIT_JSON=" + boolEnv(os.Getenv("KIT_JSON")),
-		"KIT_VERBOSE=" + boolEnv(os.Getenv("KIT_VERBOSE")),
+		"KIT_TOKEN_PATH=" + tokenPath,
+		"KIT_PROFILE=" + auth.CurrentProfile(),
+		"KIT_SITE=" + inv.Site,
+		"KIT_TEAM=" + inv.Team,
+		"KIT_JSON=" + strconv.FormatBool(inv.JSON),
+		"KIT_VERBOSE=" + strconv.FormatBool(inv.Verbose),
+		"KIT_WORKSPACE=" + workspace,
 	}
 }
 
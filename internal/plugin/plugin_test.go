@@ -4,8 +4,12 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/klytics/m365kit/internal/shell"
 )
 
 func TestDiscoverEmptyDir(t *testing.T) {
@@ -150,7 +154,8 @@ func TestRunSetsEnv(t *testing.T) {
 
 	// We can't easily capture stdout from Run (it goes to os.Stdout),
 	// so we verify the plugin is found and env is set correctly.
-	env := pluginEnv()
+	withKitVersion(t, "1.2.0")
+	env := pluginEnv(Invocation{}, t.TempDir())
 	found := false
 	for _, e := range env {
 		if strings.HasPrefix(e, "KIT_VERSION=") {
@@ -289,7 +294,7 @@ func TestInstallLocal(t *testing.T) {
 	os.WriteFile(filepath.Join(srcDir, "plugin.yaml"), []byte("name: localtest\nversion: 1.0.0\n"), 0644)
 	os.WriteFile(filepath.Join(srcDir, "kit-localtest"), []byte("#!/bin/bash\necho local\n"), 0755)
 
-	p, err := Install(srcDir)
+	p, err := Install(context.Background(), srcDir)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -311,8 +316,78 @@ func TestRunContext(t *testing.T) {
 	os.MkdirAll(filepath.Join(tmp, ".kit", "plugins"), 0755)
 
 	ctx := context.Background()
-	err := Run(ctx, "nonexistent", nil)
+	err := Run(ctx, "nonexistent", nil, Invocation{})
 	if err == nil {
 		t.Error("expected error running nonexistent plugin")
 	}
 }
+
+func TestRunPassesInvocation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugin needs a POSIX shell")
+	}
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("KIT_PROFILE", "work")
+
+	pluginDir := filepath.Join(tmp, ".kit", "plugins")
+	os.MkdirAll(pluginDir, 0755)
+	out := filepath.Join(tmp, "env.txt")
+	script := "#!/bin/sh\n" +
+		"test -d \"$KIT_WORKSPACE\" && echo workspace=ok >> " + out + "\n" +
+		"env | grep '^KIT_' >> " + out + "\n"
+	os.WriteFile(filepath.Join(pluginDir, "kit-ctx"), []byte(script), 0755)
+
+	ctx := shell.WithDefaults(context.Background(), shell.Defaults{Site: "Marketing", Team: "Engineering"})
+	inv := NewInvocation(ctx)
+	inv.JSON = true
+	if err := Run(ctx, "ctx", nil, inv); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(out)
+	env := string(data)
+	for _, want := range []string{"workspace=ok", "KIT_PROFILE=work", "KIT_SITE=Marketing", "KIT_TEAM=Engineering", "KIT_JSON=true", "KIT_VERBOSE=false"} {
+		if !strings.Contains(env, want+"\n") {
+			t.Errorf("plugin env missing %s:\n%s", want, env)
+		}
+	}
+	for _, line := range strings.Split(env, "\n") {
+		if ws, ok := strings.CutPrefix(line, "KIT_WORKSPACE="); ok {
+			if _, err := os.Stat(ws); !os.IsNotExist(err) {
+				t.Errorf("workspace %s should be removed after the run", ws)
+			}
+		}
+	}
+}
+
+func TestRunInterruptedOnCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugin needs a POSIX shell")
+	}
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+
+	pluginDir := filepath.Join(tmp, ".kit", "plugins")
+	os.MkdirAll(pluginDir, 0755)
+	marker := filepath.Join(tmp, "interrupted")
+	script := "#!/bin/sh\n" +
+		"sleep 10 &\n" +
+		"trap 'touch " + marker + "; kill $!; exit 130' INT\n" +
+		"wait\n"
+	os.WriteFile(filepath.Join(pluginDir, "kit-slow"), []byte(script), 0755)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := Run(ctx, "slow", nil, Invocation{})
+	if err == nil || !strings.Contains(err.Error(), "plugin slow stopped") {
+		t.Fatalf("err = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > stopGrace {
+		t.Errorf("Run took %s after the timeout", elapsed)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("plugin should get an interrupt it can clean up on")
+	}
+}
@@ -0,0 +1,277 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/klytics/m365kit/internal/config"
+	"github.com/klytics/m365kit/internal/httpclient"
+)
+
+// A registry is a JSON index of plugins, served over HTTPS or read from a
+// file, so a company can run its own plugin catalog. Set its location with
+// plugins.registry in ~/.kit/config.yaml or KIT_PLUGIN_REGISTRY:
+//
+//	{
+//	  "plugins": [{
+//	    "name": "acl-report",
+//	    "description": "Weekly external-sharing report",
+//	    "tags": ["acl", "sharepoint"],
+//	    "versions": [
+//	      {"version": "1.2.0", "min_version": "1.3.0", "source": "github.com/contoso/kit-acl-report@v1.2.0"},
+//	      {"version": "1.1.0", "url": "https://plugins.contoso.com/acl-report-1.1.0-{os}-{arch}.tar.gz",
+//	       "sha256": {"linux-amd64": "9f86d0…", "darwin-arm64": "60303a…", "windows-amd64": "fd61a0…"}}
+//	    ]
+//	  }]
+//	}
+//
+// A version is installed from its source (anything kit plugin install
+// accepts) or downloaded from its url, where {os} and {arch} are replaced
+// with the platform's GOOS and GOARCH. A download must match its sha256:
+// a hex digest, or for a url with {os} and {arch}, one per "<os>-<arch>".
+
+// registrySource is the recorded source of a plugin installed through the
+// registry; updates follow the registry's latest version.
+const registrySource = "registry:"
+
+// Index is a plugin registry.
+type Index struct {
+	Plugins []IndexEntry `json:"plugins"`
+}
+
+// IndexEntry is a plugin in the registry.
+type IndexEntry struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Author      string         `json:"author,omitempty"`
+	Homepage    string         `json:"homepage,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Versions    []IndexVersion `json:"versions"`
+}
+
+// IndexVersion is one published version of a plugin.
+type IndexVersion struct {
+	Version    string   `json:"version"`
+	MinVersion string   `json:"min_version,omitempty"` // oldest kit release it supports
+	Source     string   `json:"source,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	SHA256     Checksum `json:"sha256,omitempty"` // required with url
+	Released   string   `json:"released,omitempty"`
+}
+
+// Checksum is the SHA-256 of a version's download: one hex digest, or one
+// per platform keyed by "<os>-<arch>".
+type Checksum map[string]string
+
+// anyPlatform keys the digest of a download that is the same everywhere.
+const anyPlatform = "*"
+
+// UnmarshalJSON accepts a hex digest or an object of them.
+func (c *Checksum) UnmarshalJSON(data []byte) error {
+	var digest string
+	if json.Unmarshal(data, &digest) == nil {
+		*c = Checksum{anyPlatform: digest}
+		return nil
+	}
+	var byPlatform map[string]string
+	if err := json.Unmarshal(data, &byPlatform); err != nil {
+		return fmt.Errorf("sha256 must be a hex digest or an object of them by platform")
+	}
+	*c = byPlatform
+	return nil
+}
+
+// MarshalJSON writes a single digest as a string.
+func (c Checksum) MarshalJSON() ([]byte, error) {
+	if digest, ok := c[anyPlatform]; ok && len(c) == 1 {
+		return json.Marshal(digest)
+	}
+	return json.Marshal(map[string]string(c))
+}
+
+// For returns the digest for a platform, or "" if there is none.
+func (c Checksum) For(goos, goarch string) string {
+	if digest, ok := c[goos+"-"+goarch]; ok {
+		return digest
+	}
+	return c[anyPlatform]
+}
+
+// RegistryURL returns the configured registry: KIT_PLUGIN_REGISTRY, or
+// plugins.registry in ~/.kit/config.yaml.
+func RegistryURL() string {
+	if u := os.Getenv("KIT_PLUGIN_REGISTRY"); u != "" {
+		return u
+	}
+	config.Load()
+	return viper.GetString("plugins.registry")
+}
+
+// FetchIndex reads the configured registry.
+func FetchIndex(ctx context.Context) (*Index, error) {
+	url := RegistryURL()
+	if url == "" {
+		return nil, fmt.Errorf("no plugin registry configured — run: kit config set plugins.registry <url>")
+	}
+
+	var data []byte
+	if strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://") {
+		client, err := httpclient.New()
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("could not reach the plugin registry: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("plugin registry %s returned HTTP %d", url, resp.StatusCode)
+		}
+		if data, err = io.ReadAll(resp.Body); err != nil {
+			return nil, fmt.Errorf("could not read the plugin registry: %w", err)
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(strings.TrimPrefix(url, "file://")); err != nil {
+			return nil, fmt.Errorf("could not read the plugin registry: %w", err)
+		}
+	}
+
+	var ix Index
+	if err := json.Unmarshal(data, &ix); err != nil {
+		return nil, fmt.Errorf("invalid plugin registry %s: %w", url, err)
+	}
+	return &ix, nil
+}
+
+// Search returns the plugins whose name, description, or tags contain
+// term, ignoring case, sorted by name. An empty term matches all.
+func (ix *Index) Search(term string) []IndexEntry {
+	term = strings.ToLower(term)
+	var result []IndexEntry
+	for _, e := range ix.Plugins {
+		text := strings.ToLower(e.Name + " " + e.Description + " " + strings.Join(e.Tags, " "))
+		if strings.Contains(text, term) {
+			result = append(result, e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Find returns the plugin called name.
+func (ix *Index) Find(name string) (*IndexEntry, error) {
+	for i := range ix.Plugins {
+		if ix.Plugins[i].Name == name {
+			return &ix.Plugins[i], nil
+		}
+	}
+	return nil, fmt.Errorf("plugin %q is not in the registry — try: kit plugin search %s", name, name)
+}
+
+// Latest returns the newest version that runs on this kit release.
+func (e *IndexEntry) Latest() (*IndexVersion, error) {
+	var best *IndexVersion
+	current := kitVersion()
+	for i, v := range e.Versions {
+		if v.MinVersion != "" && current != "dev" && current != "" && CompareVersions(current, v.MinVersion) < 0 {
+			continue
+		}
+		if best == nil || CompareVersions(v.Version, best.Version) > 0 {
+			best = &e.Versions[i]
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no version of plugin %s supports kit %s — run: kit update install", e.Name, current)
+	}
+	return best, nil
+}
+
+// Resolve returns the given version, or the latest when version is "".
+func (e *IndexEntry) Resolve(version string) (*IndexVersion, error) {
+	if version == "" {
+		return e.Latest()
+	}
+	for i, v := range e.Versions {
+		if CompareVersions(v.Version, version) == 0 {
+			return &e.Versions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("plugin %s has no version %s in the registry", e.Name, version)
+}
+
+// registryName matches a bare plugin reference like acl-report or
+// acl-report@1.2.0, as opposed to a path or URL.
+var registryName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*(@[^/\\]+)?$`)
+
+// registryRef returns the plugin name and version of a registry source:
+// registry:<name>[@version], or a bare name that is not a local path.
+func registryRef(source string) (name, version string, ok bool) {
+	if rest, found := strings.CutPrefix(source, registrySource); found {
+		name, version, _ = strings.Cut(rest, "@")
+		return name, version, name != ""
+	}
+	if !registryName.MatchString(source) {
+		return "", "", false
+	}
+	if _, err := os.Stat(source); err == nil {
+		return "", "", false // a local file or directory of that name
+	}
+	name, version, _ = strings.Cut(source, "@")
+	return name, version, true
+}
+
+// installFromRegistry installs version (or the latest) of the plugin name
+// and returns the version installed.
+func installFromRegistry(ctx context.Context, name, version, pluginDir string) (*Plugin, string, error) {
+	ix, err := FetchIndex(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	entry, err := ix.Find(name)
+	if err != nil {
+		return nil, "", err
+	}
+	v, err := entry.Resolve(version)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var p *Plugin
+	switch {
+	case v.URL != "":
+		url := strings.NewReplacer("{os}", runtime.GOOS, "{arch}", runtime.GOARCH).Replace(v.URL)
+		sum := v.SHA256.For(runtime.GOOS, runtime.GOARCH)
+		if sum == "" {
+			return nil, "", fmt.Errorf("plugin %s %s has no sha256 for %s/%s in the registry — refusing to install an unverified download", name, v.Version, runtime.GOOS, runtime.GOARCH)
+		}
+		p, err = installFromURL(ctx, url, path.Base(url), name, sum, pluginDir)
+	case v.Source != "":
+		if _, _, isRegistry := registryRef(v.Source); isRegistry {
+			return nil, "", fmt.Errorf("plugin %s %s: source %q must be a path or GitHub repository", name, v.Version, v.Source)
+		}
+		p, err = Install(ctx, v.Source)
+	default:
+		err = fmt.Errorf("plugin %s %s has neither a source nor a url in the registry", name, v.Version)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return p, v.Version, nil
+}
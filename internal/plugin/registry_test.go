@@ -0,0 +1,198 @@
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeIndex writes a registry index to dir and points KIT_PLUGIN_REGISTRY
+// at it.
+func writeIndex(t *testing.T, dir string, ix Index) {
+	t.Helper()
+	data, _ := json.Marshal(ix)
+	path := filepath.Join(dir, "index.json")
+	os.WriteFile(path, data, 0644)
+	t.Setenv("KIT_PLUGIN_REGISTRY", path)
+}
+
+func TestIndexSearchAndLatest(t *testing.T) {
+	withKitVersion(t, "1.3.0")
+	ix := &Index{Plugins: []IndexEntry{
+		{Name: "sync", Description: "Sync files to a share"},
+		{Name: "acl-report", Description: "External sharing report", Tags: []string{"sharepoint"}, Versions: []IndexVersion{
+			{Version: "1.1.0"},
+			{Version: "1.10.0", MinVersion: "1.2.0"},
+			{Version: "2.0.0", MinVersion: "2.0.0"}, // too new for this kit
+		}},
+	}}
+
+	if got := ix.Search("SHAREPOINT"); len(got) != 1 || got[0].Name != "acl-report" {
+		t.Errorf("Search(SHAREPOINT) = %+v", got)
+	}
+	if got := ix.Search(""); len(got) != 2 || got[0].Name != "acl-report" {
+		t.Errorf("Search() = %+v", got)
+	}
+
+	e, err := ix.Find("acl-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := e.Latest(); err != nil || v.Version != "1.10.0" {
+		t.Errorf("Latest() = %+v, %v", v, err)
+	}
+	if v, err := e.Resolve("2.0.0"); err != nil || v.Version != "2.0.0" {
+		t.Errorf("Resolve(2.0.0) = %+v, %v", v, err)
+	}
+	if _, err := e.Resolve("3.0.0"); err == nil {
+		t.Error("expected error for a missing version")
+	}
+	if _, err := ix.Find("nope"); err == nil {
+		t.Error("expected error for a missing plugin")
+	}
+}
+
+func TestRegistryRef(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local")
+	os.Mkdir(local, 0755)
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	tests := []struct {
+		source, name, version string
+		ok                    bool
+	}{
+		{"acl-report", "acl-report", "", true},
+		{"acl-report@1.2.0", "acl-report", "1.2.0", true},
+		{"registry:acl-report", "acl-report", "", true},
+		{"local", "", "", false}, // a directory here
+		{"./acl-report", "", "", false},
+		{"github.com/contoso/kit-acl", "", "", false},
+	}
+	for _, tt := range tests {
+		name, version, ok := registryRef(tt.source)
+		if name != tt.name || version != tt.version || ok != tt.ok {
+			t.Errorf("registryRef(%q) = %q, %q, %v", tt.source, name, version, ok)
+		}
+	}
+}
+
+func TestInstallFromRegistry(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	withKitVersion(t, "dev")
+	ctx := context.Background()
+
+	release := func(version string) string {
+		dir := filepath.Join(tmp, "releases", version)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "plugin.yaml"), []byte("name: acl-report\nversion: "+version+"\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "kit-acl-report"), []byte("#!/bin/sh\n"), 0755)
+		return dir
+	}
+	writeIndex(t, tmp, Index{Plugins: []IndexEntry{{Name: "acl-report", Versions: []IndexVersion{
+		{Version: "1.0.0", Source: release("1.0.0")},
+	}}}})
+
+	p, err := Install(ctx, "acl-report@1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Version != "1.0.0" || p.Source != "registry:acl-report" {
+		t.Fatalf("plugin = %+v", p)
+	}
+
+	// A new version in the registry makes it outdated, and update follows it
+	writeIndex(t, tmp, Index{Plugins: []IndexEntry{{Name: "acl-report", Versions: []IndexVersion{
+		{Version: "1.0.0", Source: release("1.0.0")},
+		{Version: "1.1.0", Source: release("1.1.0")},
+	}}}})
+	plugins, _ := Discover()
+	if outdated := CheckOutdated(ctx, plugins); len(outdated) != 1 || outdated[0].Latest != "1.1.0" {
+		t.Fatalf("outdated = %+v", outdated)
+	}
+	p, from, err := Update(ctx, "acl-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != "1.0.0" || p.Version != "1.1.0" || p.Source != "registry:acl-report" {
+		t.Errorf("updated %s → %+v", from, p)
+	}
+}
+
+func TestInstallFromRegistryVerifiesDownload(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	withKitVersion(t, "dev")
+
+	binary := []byte("#!/bin/sh\necho report\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(binary)
+	}))
+	defer server.Close()
+	sum := sha256.Sum256(binary)
+	digest := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name    string
+		sha256  Checksum
+		wantErr string
+	}{
+		{"missing", nil, "no sha256"},
+		{"other platform only", Checksum{"plan9-mips": digest}, "no sha256"},
+		{"mismatch", Checksum{anyPlatform: strings.Repeat("0", 64)}, "failed verification"},
+		{"match", Checksum{runtime.GOOS + "-" + runtime.GOARCH: digest}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeIndex(t, tmp, Index{Plugins: []IndexEntry{{Name: "report", Versions: []IndexVersion{
+				{Version: "1.0.0", URL: server.URL + "/kit-report-{os}-{arch}", SHA256: tt.sha256},
+			}}}})
+			p, err := Install(context.Background(), "report")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				if plugins, _ := Discover(); len(plugins) != 0 {
+					t.Errorf("installed %+v despite the error", plugins)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Name != "report" || !isExecutable(p.Path) {
+				t.Errorf("plugin = %+v", p)
+			}
+		})
+	}
+}
+
+func TestChecksumJSON(t *testing.T) {
+	var v IndexVersion
+	if err := json.Unmarshal([]byte(`{"version":"1.0.0","sha256":"ABC"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.SHA256.For("linux", "amd64") != "ABC" {
+		t.Errorf("single digest = %v", v.SHA256)
+	}
+	if err := json.Unmarshal([]byte(`{"sha256":{"linux-amd64":"a","darwin-arm64":"b"}}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.SHA256.For("darwin", "arm64") != "b" || v.SHA256.For("windows", "amd64") != "" {
+		t.Errorf("by platform = %v", v.SHA256)
+	}
+	if data, _ := json.Marshal(Checksum{anyPlatform: "abc"}); string(data) != `"abc"` {
+		t.Errorf("marshalled %s", data)
+	}
+}
@@ -0,0 +1,512 @@
+package plugin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/httpclient"
+)
+
+// A plugin is installed from a source, which kit records so it can later
+// tell whether the plugin is outdated and update it:
+//
+//   - a local directory with plugin.yaml, whose latest version is the one
+//     in its manifest
+//   - a local kit-<name> executable, which has no version to compare
+//   - a GitHub repository, github.com/<owner>/<repo>[@<tag>], whose
+//     latest version is its latest release
+//   - a name in the plugin registry, <name>[@<version>] (see registry.go),
+//     whose latest version is the newest the running kit supports
+
+// installsFile records each installed plugin's source and version, in the
+// plugin directory.
+const installsFile = "installs.json"
+
+// InstallRecord is where, and at which version, a plugin was installed.
+type InstallRecord struct {
+	Source      string    `json:"source"`
+	Version     string    `json:"version"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+func loadInstalls() (map[string]InstallRecord, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	records := map[string]InstallRecord{}
+	data, err := os.ReadFile(filepath.Join(dir, installsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", installsFile, err)
+	}
+	return records, nil
+}
+
+func saveInstalls(records map[string]InstallRecord) error {
+	dir, err := EnsureDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, installsFile), data, 0644)
+}
+
+func recordInstall(name string, rec InstallRecord) error {
+	records, err := loadInstalls()
+	if err != nil {
+		return err
+	}
+	records[name] = rec
+	return saveInstalls(records)
+}
+
+func forgetInstall(name string) error {
+	records, err := loadInstalls()
+	if err != nil {
+		return err
+	}
+	if _, ok := records[name]; !ok {
+		return nil
+	}
+	delete(records, name)
+	return saveInstalls(records)
+}
+
+// githubRepo returns owner/repo and the pinned tag of a GitHub source, and
+// whether source is one.
+func githubRepo(source string) (repo, tag string, ok bool) {
+	s := strings.TrimPrefix(strings.TrimPrefix(source, "https://"), "http://")
+	s, ok = strings.CutPrefix(s, "github.com/")
+	if !ok {
+		return "", "", false
+	}
+	s, tag, _ = strings.Cut(s, "@")
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	if strings.Count(s, "/") != 1 {
+		return "", "", false
+	}
+	return s, tag, true
+}
+
+// githubAPI is the GitHub REST API base URL; tests point it elsewhere.
+var githubAPI = "https://api.github.com"
+
+type release struct {
+	Tag    string `json:"tag_name"`
+	Assets []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// fetchRelease returns the release of repo with tag, or the latest.
+func fetchRelease(ctx context.Context, repo, tag string) (*release, error) {
+	url := githubAPI + "/repos/" + repo + "/releases/latest"
+	if tag != "" {
+		url = githubAPI + "/repos/" + repo + "/releases/tags/" + tag
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client, err := httpclient.New()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not reach GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && tag != "":
+		return nil, fmt.Errorf("github.com/%s has no release %s", repo, tag)
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("github.com/%s has no releases", repo)
+	case resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("GitHub API rate limited — try again later, or set GITHUB_TOKEN")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("GitHub API returned %d", resp.StatusCode)
+	}
+
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("could not parse release info: %w", err)
+	}
+	return &r, nil
+}
+
+// pickAsset returns the download URL of the release asset for this OS and
+// architecture, preferring a .tar.gz plugin directory over a bare binary.
+func pickAsset(r *release) (name, url string, err error) {
+	for _, archive := range []bool{true, false} {
+		for _, a := range r.Assets {
+			n := strings.ToLower(a.Name)
+			if strings.Contains(n, runtime.GOOS) && strings.Contains(n, runtime.GOARCH) &&
+				strings.HasSuffix(n, ".tar.gz") == archive {
+				return a.Name, a.URL, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("release %s has no asset for %s/%s", r.Tag, runtime.GOOS, runtime.GOARCH)
+}
+
+// installFromGitHub downloads the release asset of repo and installs it,
+// returning the release tag.
+func installFromGitHub(ctx context.Context, repo, tag, pluginDir string) (*Plugin, string, error) {
+	r, err := fetchRelease(ctx, repo, tag)
+	if err != nil {
+		return nil, "", err
+	}
+	assetName, assetURL, err := pickAsset(r)
+	if err != nil {
+		return nil, "", err
+	}
+	sum, err := releaseChecksum(ctx, r, assetName)
+	if err != nil {
+		return nil, "", err
+	}
+	name := strings.TrimPrefix(repo[strings.Index(repo, "/")+1:], "kit-")
+	p, err := installFromURL(ctx, assetURL, assetName, name, sum, pluginDir)
+	if err != nil {
+		return nil, "", err
+	}
+	return p, r.Tag, nil
+}
+
+// checksumFiles are the release assets that may list the SHA-256 of the
+// others, one "<digest>  <file>" line each.
+var checksumFiles = []string{"checksums.txt", "SHA256SUMS", "sha256sums.txt"}
+
+// releaseChecksum returns the SHA-256 of the release asset assetName, from
+// a checksums file or an <asset>.sha256 file published with the release.
+func releaseChecksum(ctx context.Context, r *release, assetName string) (string, error) {
+	for _, a := range r.Assets {
+		single := strings.EqualFold(a.Name, assetName+".sha256")
+		listed := false
+		for _, name := range checksumFiles {
+			listed = listed || strings.EqualFold(a.Name, name)
+		}
+		if !single && !listed {
+			continue
+		}
+
+		data, err := download(ctx, a.URL, a.Name)
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			// "*" marks a binary-mode entry in sha256sum output
+			if single || (len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == assetName) {
+				return fields[0], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("release %s publishes no checksum for %s — refusing to install an unverified download", r.Tag, assetName)
+}
+
+// download returns the body of url, which is small enough to hold in
+// memory.
+func download(ctx context.Context, url, fileName string) ([]byte, error) {
+	resp, err := get(ctx, url, fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %w", fileName, err)
+	}
+	return data, nil
+}
+
+func get(ctx context.Context, url, fileName string) (*http.Response, error) {
+	client, err := httpclient.New()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %w", fileName, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("could not download %s: HTTP %d", fileName, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// installFromURL downloads a plugin, checks it against the hex SHA-256
+// digest sum, and installs it. A .tar.gz holds a plugin directory with
+// plugin.yaml; anything else is the plugin executable itself, installed as
+// kit-<name> (keeping a Windows extension such as .exe).
+func installFromURL(ctx context.Context, url, fileName, name, sum, pluginDir string) (*Plugin, error) {
+	if sum == "" {
+		return nil, fmt.Errorf("no checksum for %s — refusing to install an unverified download", fileName)
+	}
+	tmp, err := os.MkdirTemp("", "kit-plugin-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	resp, err := get(ctx, url, fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Nothing is unpacked or made executable until the download matches
+	archive := filepath.Join(tmp, "download")
+	f, err := os.OpenFile(archive, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %w", fileName, err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, sum) {
+		return nil, fmt.Errorf("%s failed verification: sha256 is %s, expected %s — refusing to install it", fileName, got, strings.ToLower(sum))
+	}
+
+	if strings.HasSuffix(strings.ToLower(fileName), ".tar.gz") {
+		f, err := os.Open(archive)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		dir := filepath.Join(tmp, "plugin")
+		if err := extractTarGz(f, dir); err != nil {
+			return nil, fmt.Errorf("could not unpack %s: %w", fileName, err)
+		}
+		src, err := findManifestDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fileName, err)
+		}
+		return installFromDir(src, pluginDir)
+	}
+
+	bin := filepath.Join(tmp, "kit-"+name+execExt(fileName))
+	if err := os.Rename(archive, bin); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(bin, 0755); err != nil {
+		return nil, err
+	}
+	return installFromFile(bin, pluginDir)
+}
+
+// extractTarGz unpacks regular files from a gzipped tarball into dir.
+func extractTarGz(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("%s is outside the archive", hdr.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode)&0755|0600)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// findManifestDir returns the directory holding plugin.yaml: dir itself or
+// its single top-level directory.
+func findManifestDir(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "plugin.yaml")); err == nil {
+		return dir, nil
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.IsDir() {
+			if _, err := os.Stat(filepath.Join(dir, e.Name(), "plugin.yaml")); err == nil {
+				return filepath.Join(dir, e.Name()), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("plugin.yaml not found in archive")
+}
+
+// LatestVersion returns the newest version of p available from the source
+// it was installed from, or "" if the source has no version to compare
+// (a single executable, or a plugin kit did not install).
+func LatestVersion(ctx context.Context, p Plugin) (string, error) {
+	if name, ok := strings.CutPrefix(p.Source, registrySource); ok {
+		ix, err := FetchIndex(ctx)
+		if err != nil {
+			return "", err
+		}
+		entry, err := ix.Find(name)
+		if err != nil {
+			return "", err
+		}
+		v, err := entry.Latest()
+		if err != nil {
+			return "", err
+		}
+		return v.Version, nil
+	}
+	if repo, _, ok := githubRepo(p.Source); ok {
+		r, err := fetchRelease(ctx, repo, "")
+		if err != nil {
+			return "", err
+		}
+		return r.Tag, nil
+	}
+	if info, err := os.Stat(p.Source); err == nil && info.IsDir() {
+		m, err := LoadManifest(p.Source)
+		if err != nil {
+			return "", err
+		}
+		return m.Version, nil
+	}
+	return "", nil
+}
+
+// Outdated is an installed plugin with a newer version at its source.
+type Outdated struct {
+	Name      string `json:"name"`
+	Installed string `json:"installed"`
+	Latest    string `json:"latest"`
+	Source    string `json:"source"`
+	Error     string `json:"error,omitempty"` // the source could not be checked
+}
+
+// CheckOutdated compares each plugin with its source and returns those
+// with a newer version, and those whose source could not be checked.
+func CheckOutdated(ctx context.Context, plugins []Plugin) []Outdated {
+	var result []Outdated
+	for _, p := range plugins {
+		if p.Source == "" || p.Source == "local" {
+			continue
+		}
+		latest, err := LatestVersion(ctx, p)
+		o := Outdated{Name: p.Name, Installed: p.Version, Latest: latest, Source: p.Source}
+		switch {
+		case err != nil:
+			o.Error = err.Error()
+		case latest == "" || CompareVersions(latest, p.Version) <= 0:
+			continue
+		}
+		result = append(result, o)
+	}
+	return result
+}
+
+// Update reinstalls the plugin name from its recorded source, at the
+// source's latest version, and returns it with the version it replaced.
+func Update(ctx context.Context, name string) (*Plugin, string, error) {
+	records, err := loadInstalls()
+	if err != nil {
+		return nil, "", err
+	}
+	rec, ok := records[name]
+	if !ok {
+		return nil, "", fmt.Errorf("plugin %q was not installed with kit plugin install — reinstall it to enable updates", name)
+	}
+	p, err := Install(ctx, rec.Source)
+	if err != nil {
+		return nil, "", err
+	}
+	return p, rec.Version, nil
+}
+
+// CompareVersions compares two versions like 1.2.3 or v1.10.0-beta.1 by
+// their numeric parts, returning -1, 0, or 1. A pre-release sorts before
+// its release.
+func CompareVersions(a, b string) int {
+	a, preA, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(a), "v"), "-")
+	b, preB, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(b), "v"), "-")
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	case preA < preB:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,165 @@
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "1.2.0", 0},
+		{"v1.2.0", "1.2", 0},
+		{"1.10.0", "1.9.3", 1},
+		{"1.2.0", "v1.3.0", -1},
+		{"2.0.0-beta.1", "2.0.0", -1},
+		{"2.0.0-rc.1", "2.0.0-beta.2", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestGithubRepo(t *testing.T) {
+	tests := []struct {
+		source, repo, tag string
+		ok                bool
+	}{
+		{"github.com/contoso/kit-acl", "contoso/kit-acl", "", true},
+		{"https://github.com/contoso/kit-acl.git", "contoso/kit-acl", "", true},
+		{"github.com/contoso/kit-acl@v1.2.0", "contoso/kit-acl", "v1.2.0", true},
+		{"github.com/contoso", "", "", false},
+		{"./github.com/contoso/kit-acl", "", "", false},
+	}
+	for _, tt := range tests {
+		repo, tag, ok := githubRepo(tt.source)
+		if repo != tt.repo || tag != tt.tag || ok != tt.ok {
+			t.Errorf("githubRepo(%q) = %q, %q, %v", tt.source, repo, tag, ok)
+		}
+	}
+}
+
+func TestUpdateFromLocalSource(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	ctx := context.Background()
+
+	srcDir := filepath.Join(tmp, "src-plugin")
+	os.MkdirAll(srcDir, 0755)
+	os.WriteFile(filepath.Join(srcDir, "plugin.yaml"), []byte("name: report\nversion: 1.0.0\n"), 0644)
+	os.WriteFile(filepath.Join(srcDir, "kit-report"), []byte("#!/bin/bash\necho v1\n"), 0755)
+	if _, err := Install(ctx, srcDir); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, _ := Discover()
+	if len(plugins) != 1 || plugins[0].Source != srcDir {
+		t.Fatalf("plugins = %+v", plugins)
+	}
+	if outdated := CheckOutdated(ctx, plugins); len(outdated) != 0 {
+		t.Errorf("fresh install is outdated: %+v", outdated)
+	}
+
+	// The source moves on
+	os.WriteFile(filepath.Join(srcDir, "plugin.yaml"), []byte("name: report\nversion: 1.1.0\n"), 0644)
+	os.WriteFile(filepath.Join(srcDir, "kit-report"), []byte("#!/bin/bash\necho v2\n"), 0755)
+	outdated := CheckOutdated(ctx, plugins)
+	if len(outdated) != 1 || outdated[0].Installed != "1.0.0" || outdated[0].Latest != "1.1.0" {
+		t.Fatalf("outdated = %+v", outdated)
+	}
+
+	p, from, err := Update(ctx, "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != "1.0.0" || p.Version != "1.1.0" {
+		t.Errorf("updated %s → %s", from, p.Version)
+	}
+	data, _ := os.ReadFile(p.Path)
+	if string(data) != "#!/bin/bash\necho v2\n" {
+		t.Errorf("executable = %q", data)
+	}
+
+	// Removing a plugin forgets its source
+	if err := Remove("report"); err != nil {
+		t.Fatal(err)
+	}
+	if records, _ := loadInstalls(); len(records) != 0 {
+		t.Errorf("records = %+v", records)
+	}
+}
+
+func TestInstallFromGitHub(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+
+	// A release with a plugin directory for this platform
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{
+		"kit-acl/plugin.yaml": "name: acl\nversion: 2.0.0\n",
+		"kit-acl/kit-acl":     "#!/bin/bash\necho acl\n",
+	} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(body)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/contoso/kit-acl/releases/latest":
+			asset := "kit-acl_" + runtime.GOOS + "_" + runtime.GOARCH + ".tar.gz"
+			json.NewEncoder(w).Encode(map[string]any{
+				"tag_name": "v2.0.0",
+				"assets": []map[string]string{
+					{"name": "checksums.txt", "browser_download_url": server.URL + "/checksums.txt"},
+					{"name": asset, "browser_download_url": server.URL + "/download/" + asset},
+				},
+			})
+		case "/checksums.txt":
+			sum := sha256.Sum256(archive.Bytes())
+			fmt.Fprintf(w, "%x  kit-acl_%s_%s.tar.gz\n", sum, runtime.GOOS, runtime.GOARCH)
+		case "/download/kit-acl_" + runtime.GOOS + "_" + runtime.GOARCH + ".tar.gz":
+			w.Write(archive.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer func(api string) { githubAPI = api }(githubAPI)
+	githubAPI = server.URL
+
+	p, err := Install(context.Background(), "https://github.com/contoso/kit-acl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "acl" || p.Version != "2.0.0" || p.Source != "github.com/contoso/kit-acl" {
+		t.Errorf("plugin = %+v", p)
+	}
+	if !isExecutable(p.Path) {
+		t.Errorf("%s is not executable", p.Path)
+	}
+
+	plugins, _ := Discover()
+	if outdated := CheckOutdated(context.Background(), plugins); len(outdated) != 0 {
+		t.Errorf("outdated = %+v", outdated)
+	}
+}
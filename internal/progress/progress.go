@@ -167,6 +167,24 @@ func (s *Spinner) Stop(result string) {
 	}
 }
 
+// Clear stops the spinner and erases it, for when output follows on the
+// same terminal.
+func (s *Spinner) Clear() {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+
+	if s.Enabled {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
+
 // Update changes the spinner label while it's running.
 func (s *Spinner) Update(label string) {
 	s.mu.Lock()
@@ -1,7 +1,10 @@
 package progress
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -162,3 +165,121 @@ func TestNewSpinnerDisabled(t *testing.T) {
 		t.Error("expected spinner to be disabled")
 	}
 }
+
+func newTestTransfer(out *bytes.Buffer, clock *time.Time) *Transfer {
+	return &Transfer{
+		Label:   "file.bin",
+		Width:   10,
+		Enabled: true,
+		Out:     out,
+		now:     func() time.Time { return *clock },
+	}
+}
+
+func TestTransferRateAndETA(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Unix(0, 0)
+	tr := newTestTransfer(&out, &clock)
+
+	tr.Begin(1000, 0)
+	clock = clock.Add(2 * time.Second)
+	tr.Add(400)
+
+	if got := tr.Rate(); got != 200 {
+		t.Errorf("expected rate 200 B/s, got %v", got)
+	}
+	if got := tr.ETA(); got != 3*time.Second {
+		t.Errorf("expected ETA 3s, got %v", got)
+	}
+	if !strings.Contains(tr.Line(), " 40%") {
+		t.Errorf("expected 40%% in line, got %q", tr.Line())
+	}
+}
+
+func TestTransferResumedBytesExcludedFromRate(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Unix(0, 0)
+	tr := newTestTransfer(&out, &clock)
+
+	tr.Begin(1000, 500)
+	clock = clock.Add(time.Second)
+	tr.Add(100)
+
+	if got := tr.Rate(); got != 100 {
+		t.Errorf("expected rate 100 B/s, got %v", got)
+	}
+	if tr.Current != 600 {
+		t.Errorf("expected current 600, got %d", tr.Current)
+	}
+}
+
+func TestTransferThrottlesRedraw(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Unix(0, 0)
+	tr := newTestTransfer(&out, &clock)
+
+	tr.Begin(100, 0)
+	tr.Add(10)
+	tr.Add(10)
+	if n := strings.Count(out.String(), "\r"); n != 1 {
+		t.Errorf("expected 1 draw within redraw interval, got %d", n)
+	}
+
+	clock = clock.Add(time.Second)
+	tr.Add(10)
+	if n := strings.Count(out.String(), "\r"); n != 2 {
+		t.Errorf("expected 2 draws, got %d", n)
+	}
+}
+
+func TestTransferJSONEvents(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Unix(0, 0)
+	tr := newTestTransfer(&out, &clock)
+	tr.JSON = true
+
+	tr.Begin(100, 0)
+	clock = clock.Add(time.Second)
+	tr.Add(100)
+	tr.Done()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 events, got %d: %q", len(lines), out.String())
+	}
+	var last map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &last); err != nil {
+		t.Fatalf("invalid JSON event: %v", err)
+	}
+	if last["event"] != "done" || last["bytes"] != float64(100) || last["percent"] != float64(100) {
+		t.Errorf("unexpected final event: %v", last)
+	}
+}
+
+func TestNewTransferJSONEnv(t *testing.T) {
+	t.Setenv("KIT_PROGRESS", "json")
+	tr := NewTransfer("x")
+	if !tr.Enabled || !tr.JSON {
+		t.Error("expected JSON transfer progress with KIT_PROGRESS=json")
+	}
+
+	t.Setenv("KIT_NO_PROGRESS", "1")
+	if NewTransfer("x").Enabled {
+		t.Error("expected KIT_NO_PROGRESS=1 to win over KIT_PROGRESS=json")
+	}
+}
+
+func TestForTransfer(t *testing.T) {
+	t.Setenv("KIT_PROGRESS", "json")
+	if ForTransfer("x", true, false) != nil {
+		t.Error("expected no reporter with quiet")
+	}
+	if ForTransfer("x", false, true) == nil {
+		t.Error("expected JSON events alongside --json output")
+	}
+
+	t.Setenv("KIT_PROGRESS", "")
+	if ForTransfer("x", false, false) != nil {
+		t.Error("expected no reporter when stderr is not a TTY")
+	}
+}
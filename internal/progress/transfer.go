@@ -0,0 +1,216 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// Transfer reports byte progress (size, percentage, rate, ETA) for uploads
+// and downloads. It renders a bar on a TTY, or newline-delimited JSON events
+// when KIT_PROGRESS=json.
+type Transfer struct {
+	Label   string
+	Total   int64
+	Current int64
+	Width   int
+	Enabled bool
+	JSON    bool
+	Out     io.Writer
+
+	mu       sync.Mutex
+	start    time.Time
+	resumed  int64
+	lastDraw time.Time
+	now      func() time.Time
+}
+
+// redrawInterval limits how often the bar is redrawn; JSON events are
+// emitted at most once per jsonInterval.
+const (
+	redrawInterval = 100 * time.Millisecond
+	jsonInterval   = time.Second
+)
+
+// NewTransfer creates a transfer reporter writing to stderr.
+// Like Bar, it is disabled when stderr is not a TTY, KIT_JSON=true, or
+// KIT_NO_PROGRESS=1 — unless KIT_PROGRESS=json requests JSON events.
+// Commands with --quiet and --json flags should use ForTransfer instead.
+func NewTransfer(label string) *Transfer {
+	t := &Transfer{
+		Label: label,
+		Width: 30,
+		Out:   os.Stderr,
+		now:   time.Now,
+	}
+	if os.Getenv("KIT_NO_PROGRESS") == "1" {
+		return t
+	}
+	if os.Getenv("KIT_PROGRESS") == "json" {
+		t.Enabled = true
+		t.JSON = true
+		return t
+	}
+	t.Enabled = shouldEnable()
+	return t
+}
+
+// ForTransfer returns a reporter for an upload or download, or nil when
+// quiet is set or nothing would be shown. With jsonOutput (--json), only
+// JSON events are reported: a TTY bar is noise next to JSON output.
+func ForTransfer(label string, quiet, jsonOutput bool) graph.TransferObserver {
+	if quiet {
+		return nil
+	}
+	t := NewTransfer(label)
+	if !t.Enabled || (jsonOutput && !t.JSON) {
+		return nil
+	}
+	return t
+}
+
+// Begin starts the clock. done is the number of bytes already transferred
+// (e.g. a resumed download); it counts toward the percentage but not the rate.
+func (t *Transfer) Begin(total, done int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.now == nil {
+		t.now = time.Now
+	}
+	t.Total = total
+	t.Current = done
+	t.resumed = done
+	t.start = t.now()
+	t.lastDraw = time.Time{}
+	t.render(false)
+}
+
+// Add records n more bytes transferred.
+func (t *Transfer) Add(n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.Current += n
+	t.render(false)
+}
+
+// Done prints the final line (or a "done" JSON event).
+func (t *Transfer) Done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.render(true)
+}
+
+// Rate returns the average transfer rate in bytes per second.
+func (t *Transfer) Rate() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rate()
+}
+
+// ETA returns the estimated time remaining, or 0 if unknown.
+func (t *Transfer) ETA() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.eta()
+}
+
+func (t *Transfer) rate() float64 {
+	if t.start.IsZero() {
+		return 0
+	}
+	elapsed := t.now().Sub(t.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(t.Current-t.resumed) / elapsed
+}
+
+func (t *Transfer) eta() time.Duration {
+	r := t.rate()
+	if r <= 0 || t.Total <= 0 || t.Current >= t.Total {
+		return 0
+	}
+	return time.Duration(float64(t.Total-t.Current) / r * float64(time.Second))
+}
+
+func (t *Transfer) pct() float64 {
+	if t.Total <= 0 {
+		return 0
+	}
+	p := float64(t.Current) / float64(t.Total) * 100
+	if p > 100 {
+		p = 100
+	}
+	return p
+}
+
+func (t *Transfer) render(final bool) {
+	if !t.Enabled {
+		return
+	}
+
+	now := t.now()
+	interval := redrawInterval
+	if t.JSON {
+		interval = jsonInterval
+	}
+	if !final && !t.lastDraw.IsZero() && now.Sub(t.lastDraw) < interval {
+		return
+	}
+	t.lastDraw = now
+
+	if t.JSON {
+		event := "progress"
+		if final {
+			event = "done"
+		}
+		line, _ := json.Marshal(map[string]any{
+			"event":       event,
+			"label":       t.Label,
+			"bytes":       t.Current,
+			"total":       t.Total,
+			"percent":     t.pct(),
+			"rate":        int64(t.rate()),
+			"eta_seconds": int64(t.eta().Seconds()),
+		})
+		fmt.Fprintln(t.Out, string(line))
+		return
+	}
+
+	if final {
+		fmt.Fprintf(t.Out, "\r\033[K✓ %s  %s  %s/s\n",
+			t.Label, graph.FormatSize(t.Current), graph.FormatSize(int64(t.rate())))
+		return
+	}
+	fmt.Fprintf(t.Out, "\r\033[K%s", t.Line())
+}
+
+// Line formats the current state as a single status line.
+func (t *Transfer) Line() string {
+	size := graph.FormatSize(t.Current)
+	if t.Total > 0 {
+		size += "/" + graph.FormatSize(t.Total)
+	}
+	rate := graph.FormatSize(int64(t.rate())) + "/s"
+
+	if t.Total <= 0 {
+		return fmt.Sprintf("%s  %s  %s", t.Label, size, rate)
+	}
+
+	filled := int(t.pct() / 100 * float64(t.Width))
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", t.Width-filled)
+	eta := "--"
+	if d := t.eta(); d > 0 {
+		eta = d.Round(time.Second).String()
+	}
+	return fmt.Sprintf("%s [%s] %3.0f%%  %s  %s  ETA %s", t.Label, bar, t.pct(), size, rate, eta)
+}
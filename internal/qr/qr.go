@@ -0,0 +1,463 @@
+// Package qr encodes short text, such as a sign-in URL, as a QR code and
+// renders it for a terminal. It supports byte mode at error correction
+// level L in versions 1 to 10 — up to 271 bytes — which is all kit needs.
+package qr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// version describes the layout of one QR code version at level L.
+type version struct {
+	codewords  int   // total data and error correction codewords
+	ecPerBlock int   // error correction codewords per block
+	blocks     int   // number of blocks the data is split into
+	alignment  []int // alignment pattern centre coordinates
+}
+
+var versions = []version{
+	1:  {26, 7, 1, nil},
+	2:  {44, 10, 1, []int{6, 18}},
+	3:  {70, 15, 1, []int{6, 22}},
+	4:  {100, 20, 1, []int{6, 26}},
+	5:  {134, 26, 1, []int{6, 30}},
+	6:  {172, 18, 2, []int{6, 34}},
+	7:  {196, 20, 2, []int{6, 22, 38}},
+	8:  {242, 24, 2, []int{6, 24, 42}},
+	9:  {292, 30, 2, []int{6, 26, 46}},
+	10: {346, 18, 4, []int{6, 28, 50}},
+}
+
+// dataCodewords returns the number of data codewords in version v.
+func dataCodewords(v int) int {
+	return versions[v].codewords - versions[v].ecPerBlock*versions[v].blocks
+}
+
+// Code is an encoded QR code.
+type Code struct {
+	Size    int      // modules per side
+	modules [][]bool // [y][x], true is dark
+}
+
+// Dark reports whether the module at column x, row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode encodes text in the smallest version that holds it.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	v := 1
+	for ; v < len(versions); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*dataCodewords(v) {
+			break
+		}
+	}
+	if v == len(versions) {
+		return nil, fmt.Errorf("text too long for a QR code (%d bytes, at most %d)", len(data), dataCodewords(10)-3)
+	}
+
+	b := newEncoder(v)
+	b.drawFunctionPatterns()
+	b.drawCodewords(b.interleave(b.dataCodewords(data)))
+
+	// Pick the mask with the lowest penalty, as the standard asks
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		b.applyMask(mask)
+		b.drawFormatBits(mask)
+		if p := b.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		b.applyMask(mask) // masking twice undoes it
+	}
+	b.applyMask(best)
+	b.drawFormatBits(best)
+
+	return &Code{Size: b.size, modules: b.modules}, nil
+}
+
+type encoder struct {
+	version    int
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+func newEncoder(v int) *encoder {
+	size := 17 + 4*v
+	e := &encoder{version: v, size: size}
+	e.modules = make([][]bool, size)
+	e.isFunction = make([][]bool, size)
+	for i := range e.modules {
+		e.modules[i] = make([]bool, size)
+		e.isFunction[i] = make([]bool, size)
+	}
+	return e
+}
+
+func (e *encoder) setFunction(x, y int, dark bool) {
+	e.modules[y][x] = dark
+	e.isFunction[y][x] = true
+}
+
+func (e *encoder) drawFunctionPatterns() {
+	for i := 0; i < e.size; i++ {
+		e.setFunction(6, i, i%2 == 0)
+		e.setFunction(i, 6, i%2 == 0)
+	}
+
+	e.drawFinder(3, 3)
+	e.drawFinder(e.size-4, 3)
+	e.drawFinder(3, e.size-4)
+
+	pos := versions[e.version].alignment
+	for i, x := range pos {
+		for j, y := range pos {
+			// Skip the three corners taken by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == len(pos)-1) || (i == len(pos)-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					e.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; the real bits are drawn once the mask is
+	// chosen
+	e.drawFormatBits(0)
+	e.drawVersion()
+}
+
+// drawFinder draws a finder pattern centred on x, y with its separator.
+func (e *encoder) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= e.size || yy < 0 || yy >= e.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			e.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// formatBits returns the 15-bit format information for level L and mask.
+func formatBits(mask int) int {
+	data := 1<<3 | mask // level L is 01
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (e *encoder) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		e.setFunction(8, i, bit(i))
+	}
+	e.setFunction(8, 7, bit(6))
+	e.setFunction(8, 8, bit(7))
+	e.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		e.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		e.setFunction(e.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		e.setFunction(8, e.size-15+i, bit(i))
+	}
+	e.setFunction(8, e.size-8, true) // always dark
+}
+
+// versionBits returns the 18-bit version information for v (7 and up).
+func versionBits(v int) int {
+	rem := v
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return v<<12 | rem
+}
+
+func (e *encoder) drawVersion() {
+	if e.version < 7 {
+		return
+	}
+	bits := versionBits(e.version)
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 != 0
+		a, b := e.size-11+i%3, i/3
+		e.setFunction(a, b, dark)
+		e.setFunction(b, a, dark)
+	}
+}
+
+// dataCodewords lays out data in byte mode and pads it to the version's
+// capacity.
+func (e *encoder) dataCodewords(data []byte) []byte {
+	var bits []bool
+	appendBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, v>>i&1 != 0)
+		}
+	}
+	countBits := 8
+	if e.version >= 10 {
+		countBits = 16
+	}
+	appendBits(0b0100, 4)
+	appendBits(len(data), countBits)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	capacity := 8 * dataCodewords(e.version)
+	appendBits(0, min(4, capacity-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		appendBits(pad, 8)
+	}
+
+	out := make([]byte, len(bits)/8)
+	for i, b := range bits {
+		if b {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+// interleave splits data into blocks, appends each block's error
+// correction codewords, and interleaves the result.
+func (e *encoder) interleave(data []byte) []byte {
+	v := versions[e.version]
+	shortLen := v.codewords / v.blocks // data and error correction
+	numShort := v.blocks - v.codewords%v.blocks
+	divisor := rsDivisor(v.ecPerBlock)
+
+	var blocks, ecc [][]byte
+	for i, k := 0, 0; i < v.blocks; i++ {
+		n := shortLen - v.ecPerBlock
+		if i >= numShort {
+			n++
+		}
+		blocks = append(blocks, data[k:k+n])
+		ecc = append(ecc, rsRemainder(data[k:k+n], divisor))
+		k += n
+	}
+
+	out := make([]byte, 0, v.codewords)
+	for i := 0; i <= shortLen-v.ecPerBlock; i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, c := range ecc {
+			out = append(out, c[i])
+		}
+	}
+	return out
+}
+
+// drawCodewords places the codewords in the two-column zigzag, skipping
+// function modules.
+func (e *encoder) drawCodewords(codewords []byte) {
+	i := 0
+	for right := e.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		for vert := 0; vert < e.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = e.size - 1 - vert // upward column pair
+				}
+				if !e.isFunction[y][x] && i < len(codewords)*8 {
+					e.modules[y][x] = codewords[i/8]>>(7-i%8)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (e *encoder) applyMask(mask int) {
+	for y := 0; y < e.size; y++ {
+		for x := 0; x < e.size; x++ {
+			if !e.isFunction[y][x] && maskBit(mask, x, y) {
+				e.modules[y][x] = !e.modules[y][x]
+			}
+		}
+	}
+}
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores the symbol by the standard's rules for runs of one colour,
+// 2x2 blocks, and dark/light balance; lower is easier to scan. (The rule
+// for finder-like patterns is left out.)
+func (e *encoder) penalty() int {
+	score := 0
+	for y := 0; y < e.size; y++ {
+		row := func(i int) bool { return e.modules[y][i] }
+		column := func(i int) bool { return e.modules[i][y] }
+		for _, line := range []func(int) bool{row, column} {
+			run := 1
+			for i := 1; i < e.size; i++ {
+				if line(i) == line(i-1) {
+					run++
+					if run == 5 {
+						score += 3
+					} else if run > 5 {
+						score++
+					}
+				} else {
+					run = 1
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < e.size; y++ {
+		for x := 0; x < e.size; x++ {
+			if e.modules[y][x] {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				c := e.modules[y][x]
+				if c == e.modules[y-1][x] && c == e.modules[y][x-1] && c == e.modules[y-1][x-1] {
+					score += 3
+				}
+			}
+		}
+	}
+	total := e.size * e.size
+	score += abs(dark*20-total*10) / total * 10
+	return score
+}
+
+// rsDivisor returns the generator polynomial for degree error correction
+// codewords, highest coefficient first, without the leading 1.
+func rsDivisor(degree int) []byte {
+	divisor := make([]byte, degree)
+	divisor[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range divisor {
+			divisor[j] = gfMul(divisor[j], root)
+			if j+1 < len(divisor) {
+				divisor[j] ^= divisor[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return divisor
+}
+
+// rsRemainder returns the error correction codewords for data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMul multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// quietZone is the light border around the symbol, in modules. The
+// standard asks for 4; 2 scans reliably off a screen and keeps the code
+// small.
+const quietZone = 2
+
+// Terminal renders the code with Unicode half blocks, two module rows per
+// line. Light modules are drawn as blocks, so the code reads correctly on
+// the usual light-on-dark terminal.
+func (c *Code) Terminal() string {
+	light := func(x, y int) bool {
+		x, y = x-quietZone, y-quietZone
+		if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+			return true
+		}
+		return !c.modules[y][x]
+	}
+
+	var sb strings.Builder
+	n := c.Size + 2*quietZone
+	for y := 0; y < n; y += 2 {
+		for x := 0; x < n; x++ {
+			top, bottom := light(x, y), y+1 < n && light(x, y+1)
+			switch {
+			case top && bottom:
+				sb.WriteString("█")
+			case top:
+				sb.WriteString("▀")
+			case bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
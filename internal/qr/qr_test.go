@@ -0,0 +1,176 @@
+package qr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatAndVersionBits(t *testing.T) {
+	// From the tables in ISO/IEC 18004
+	if got := formatBits(0); got != 0b111011111000100 {
+		t.Errorf("formatBits(0) = %015b", got)
+	}
+	if got := formatBits(3); got != 0b111100010011101 {
+		t.Errorf("formatBits(3) = %015b", got)
+	}
+	if got := versionBits(7); got != 0x07C94 {
+		t.Errorf("versionBits(7) = %#x", got)
+	}
+}
+
+func TestRSRemainder(t *testing.T) {
+	// A codeword built from data and its remainder is divisible by the
+	// generator, so it evaluates to zero at each of its roots.
+	data := []byte("https://microsoft.com/devicelogin")
+	ecc := rsRemainder(data, rsDivisor(10))
+	codeword := append(append([]byte(nil), data...), ecc...)
+	root := byte(1)
+	for i := 0; i < 10; i++ {
+		var sum byte
+		for _, c := range codeword {
+			sum = gfMul(sum, root) ^ c
+		}
+		if sum != 0 {
+			t.Errorf("codeword is not zero at root %d", i)
+		}
+		root = gfMul(root, 2)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"ABCD-EFGH",
+		"https://microsoft.com/devicelogin",
+		"https://login.microsoftonline.com/common/oauth2/deviceauth?otc=ABCD-EFGH",
+		strings.Repeat("x", 200), // version 10, four blocks
+	} {
+		code, err := Encode(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decode(t, code); got != text {
+			t.Errorf("decoded %q, want %q", got, text)
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("x", 300)); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	code, err := Encode("ABCD-EFGH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(code.Terminal(), "\n"), "\n")
+	width := code.Size + 2*quietZone
+	if len(lines) != (width+1)/2 {
+		t.Fatalf("%d lines for %d modules", len(lines), width)
+	}
+	for _, l := range lines {
+		if n := len([]rune(l)); n != width {
+			t.Fatalf("line is %d wide, want %d", n, width)
+		}
+	}
+	// The quiet zone is light, so the first line is solid
+	if strings.Trim(lines[0], "█") != "" {
+		t.Errorf("first line = %q", lines[0])
+	}
+}
+
+// decode reads the text back out of code: it finds the mask from the
+// format bits, reads the codewords in placement order, de-interleaves
+// them, checks the error correction, and parses the byte-mode segment.
+func decode(t *testing.T, code *Code) string {
+	t.Helper()
+	v := (code.Size - 17) / 4
+	e := newEncoder(v)
+	e.drawFunctionPatterns()
+
+	var formatRead int
+	for i := 0; i <= 5; i++ {
+		if code.Dark(8, i) {
+			formatRead |= 1 << i
+		}
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatBits(m)&0x3F == formatRead {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("no mask matches format bits %06b", formatRead)
+	}
+
+	var codewords []byte
+	var bit int
+	for right := code.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < code.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = code.Size - 1 - vert
+				}
+				if e.isFunction[y][x] {
+					continue
+				}
+				if bit%8 == 0 {
+					codewords = append(codewords, 0)
+				}
+				if code.Dark(x, y) != maskBit(mask, x, y) {
+					codewords[bit/8] |= 1 << (7 - bit%8)
+				}
+				bit++
+			}
+		}
+	}
+
+	ver := versions[v]
+	codewords = codewords[:ver.codewords]
+	blocks := make([][]byte, ver.blocks)
+	numShort := ver.blocks - ver.codewords%ver.blocks
+	dataLen := ver.codewords/ver.blocks - ver.ecPerBlock
+	k := 0
+	for i := 0; i <= dataLen; i++ {
+		for b := range blocks {
+			if i < dataLen || b >= numShort {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	divisor := rsDivisor(ver.ecPerBlock)
+	for b := range blocks {
+		var ecc []byte
+		for i := 0; i < ver.ecPerBlock; i++ {
+			ecc = append(ecc, codewords[k+i*ver.blocks+b])
+		}
+		if string(rsRemainder(blocks[b], divisor)) != string(ecc) {
+			t.Errorf("block %d error correction does not match", b)
+		}
+		data = append(data, blocks[b]...)
+	}
+
+	if data[0]>>4 != 0b0100 {
+		t.Fatalf("mode = %04b", data[0]>>4)
+	}
+	// Shift out the mode indicator, then read the count and the bytes
+	shifted := make([]byte, len(data)-1)
+	for i := range shifted {
+		shifted[i] = data[i]<<4 | data[i+1]>>4
+	}
+	if v >= 10 {
+		n := int(shifted[0])<<8 | int(shifted[1])
+		return string(shifted[2 : 2+n])
+	}
+	n := int(shifted[0])
+	return string(shifted[1 : 1+n])
+}
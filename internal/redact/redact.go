@@ -0,0 +1,203 @@
+// Package redact masks personal data and secrets in text before it is sent
+// to an AI provider: email addresses, phone numbers, US Social Security
+// numbers, credit card numbers, API keys and private keys, and any custom
+// patterns from ~/.kit/config.yaml. Each distinct value is replaced by a
+// numbered placeholder, like [EMAIL_1], so the model can still tell values
+// apart and refer to them.
+package redact
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klytics/m365kit/internal/config"
+)
+
+// Enabled reports whether text sent to AI providers should be redacted:
+// KIT_REDACT (set by --redact) if it is set, else redact.enabled in
+// ~/.kit/config.yaml.
+func Enabled() bool {
+	switch os.Getenv("KIT_REDACT") {
+	case "1", "true":
+		return true
+	case "0", "false":
+		return false
+	}
+	cfg, err := config.Load()
+	return err == nil && cfg.Redact.Enabled
+}
+
+// rule is one kind of value to mask.
+type rule struct {
+	kind string
+	re   *regexp.Regexp
+	// valid, if set, filters out matches that aren't really of this kind
+	valid func(string) bool
+}
+
+// builtinRules are applied in order, after custom patterns: secrets first,
+// since keys can contain runs of digits, and cards before phone numbers.
+var builtinRules = []rule{
+	{kind: "secret", re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{kind: "secret", re: regexp.MustCompile(`\b(?:sk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|xox[abprs]-[A-Za-z0-9-]{10,}|AIza[0-9A-Za-z_-]{35})\b`)},
+	{kind: "email", re: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	{kind: "card", re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: luhn},
+	{kind: "ssn", re: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), valid: validSSN},
+	{kind: "phone", re: regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{3}\)[ .-]?|\b\d{3}[ .-])\d{3}[ .-]\d{4}\b|\+\d{1,3}(?:[ .-]\d{2,5}){2,5}\b`)},
+}
+
+// Redactor masks text. It remembers the values it has seen, so a value
+// gets the same placeholder every time, across calls.
+type Redactor struct {
+	rules []rule
+
+	mu           sync.Mutex
+	placeholders map[string]string // value → placeholder
+	values       map[string]int    // kind → distinct values masked
+	occurrences  map[string]int    // kind → matches masked
+}
+
+// New creates a Redactor with the built-in rules and custom, which maps
+// kind names to regular expressions.
+func New(custom map[string]string) (*Redactor, error) {
+	r := &Redactor{
+		placeholders: make(map[string]string),
+		values:       make(map[string]int),
+		occurrences:  make(map[string]int),
+	}
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(custom[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", name, err)
+		}
+		r.rules = append(r.rules, rule{kind: name, re: re})
+	}
+	r.rules = append(r.rules, builtinRules...)
+	return r, nil
+}
+
+// Load creates a Redactor with the custom patterns from redact.patterns in
+// ~/.kit/config.yaml.
+func Load() (*Redactor, error) {
+	var custom map[string]string
+	if cfg, err := config.Load(); err == nil {
+		custom = cfg.Redact.Patterns
+	}
+	return New(custom)
+}
+
+// Redact returns text with the values the rules match replaced by
+// placeholders.
+func (r *Redactor) Redact(text string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ru := range r.rules {
+		text = ru.re.ReplaceAllStringFunc(text, func(value string) string {
+			if ru.valid != nil && !ru.valid(value) {
+				return value
+			}
+			r.occurrences[ru.kind]++
+			if p, ok := r.placeholders[value]; ok {
+				return p
+			}
+			r.values[ru.kind]++
+			p := fmt.Sprintf("[%s_%d]", placeholderName(ru.kind), r.values[ru.kind])
+			r.placeholders[value] = p
+			return p
+		})
+	}
+	return text
+}
+
+// placeholderName turns a kind into a placeholder prefix: "employee id"
+// becomes EMPLOYEE_ID.
+func placeholderName(kind string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return '_'
+		}
+		return r
+	}, kind))
+}
+
+// Finding is how much of one kind of value was masked.
+type Finding struct {
+	Kind string `json:"kind"`
+	// Values is how many distinct values were masked, and Occurrences how
+	// many times they appeared.
+	Values      int `json:"values"`
+	Occurrences int `json:"occurrences"`
+}
+
+// Findings returns what has been masked so far, most frequent first.
+func (r *Redactor) Findings() []Finding {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	findings := make([]Finding, 0, len(r.values))
+	for kind, n := range r.values {
+		findings = append(findings, Finding{Kind: kind, Values: n, Occurrences: r.occurrences[kind]})
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Occurrences != findings[j].Occurrences {
+			return findings[i].Occurrences > findings[j].Occurrences
+		}
+		return findings[i].Kind < findings[j].Kind
+	})
+	return findings
+}
+
+// Summary describes findings in one line, like "3 email (5 occurrences),
+// 1 phone", or "" if nothing was masked.
+func Summary(findings []Finding) string {
+	parts := make([]string, 0, len(findings))
+	for _, f := range findings {
+		part := fmt.Sprintf("%d %s", f.Values, f.Kind)
+		if f.Occurrences > f.Values {
+			part += fmt.Sprintf(" (%d occurrences)", f.Occurrences)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// luhn reports whether a card number (with spaces or dashes) passes the
+// Luhn checksum.
+func luhn(s string) bool {
+	var digits []int
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validSSN rules out numbers the SSA never issues: area 000, 666, or 9xx,
+// group 00, or serial 0000.
+func validSSN(s string) bool {
+	area, group, serial := s[:3], s[4:6], s[7:]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
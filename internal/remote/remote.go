@@ -0,0 +1,323 @@
+// Package remote resolves cloud document paths so commands can read and write
+// OneDrive and SharePoint files as if they were local.
+//
+// Supported forms:
+//
+//	onedrive:/Documents/report.docx
+//	sp:<site>/<library>/Folder/report.docx
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// Scheme prefixes recognised by Parse.
+const (
+	SchemeOneDrive   = "onedrive"
+	SchemeSharePoint = "sp"
+)
+
+// Path is a parsed remote location.
+type Path struct {
+	Scheme  string `json:"scheme"`
+	Site    string `json:"site,omitempty"`
+	Library string `json:"library,omitempty"`
+	Path    string `json:"path"`
+	Raw     string `json:"raw"`
+}
+
+// String returns the original remote reference.
+func (p *Path) String() string {
+	return p.Raw
+}
+
+// Base returns the final element of the remote path.
+func (p *Path) Base() string {
+	return filepath.Base(p.Path)
+}
+
+// IsRemote reports whether s uses a remote scheme.
+func IsRemote(s string) bool {
+	return strings.HasPrefix(s, SchemeOneDrive+":") || strings.HasPrefix(s, SchemeSharePoint+":")
+}
+
+// Parse parses a remote reference. It returns an error for local paths.
+func Parse(s string) (*Path, error) {
+	switch {
+	case strings.HasPrefix(s, SchemeOneDrive+":"):
+		rest := strings.TrimPrefix(s, SchemeOneDrive+":")
+		return &Path{
+			Scheme: SchemeOneDrive,
+			Path:   cleanPath(rest),
+			Raw:    s,
+		}, nil
+
+	case strings.HasPrefix(s, SchemeSharePoint+":"):
+		rest := strings.TrimPrefix(strings.TrimPrefix(s, SchemeSharePoint+":"), "/")
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid SharePoint path %q — expected sp:<site>/<library>/<path>", s)
+		}
+		p := &Path{
+			Scheme:  SchemeSharePoint,
+			Site:    parts[0],
+			Library: parts[1],
+			Path:    "/",
+			Raw:     s,
+		}
+		if len(parts) == 3 {
+			p.Path = cleanPath(parts[2])
+		}
+		return p, nil
+
+	default:
+		return nil, fmt.Errorf("%q is not a remote path (expected onedrive:/... or sp:<site>/<library>/...)", s)
+	}
+}
+
+func cleanPath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return "/"
+	}
+	return p
+}
+
+// Resolver performs file operations against remote paths.
+type Resolver struct {
+	Client *http.Client
+}
+
+// NewResolver creates a Resolver with an authenticated HTTP client.
+func NewResolver(client *http.Client) *Resolver {
+	return &Resolver{Client: client}
+}
+
+// Download copies the remote file to localPath and returns the bytes written.
+func (r *Resolver) Download(ctx context.Context, p *Path, localPath string) (int64, error) {
+	switch p.Scheme {
+	case SchemeOneDrive:
+		return graph.NewOneDrive(r.Client).DownloadFile(ctx, p.Path, localPath)
+	case SchemeSharePoint:
+		sp := graph.NewSharePoint(r.Client)
+		siteID, driveID, err := r.resolveLibrary(ctx, sp, p)
+		if err != nil {
+			return 0, err
+		}
+		return sp.DownloadFromLibrary(ctx, siteID, driveID, p.Path, localPath)
+	default:
+		return 0, fmt.Errorf("unsupported remote scheme %q", p.Scheme)
+	}
+}
+
+// Upload copies localPath to the remote location.
+func (r *Resolver) Upload(ctx context.Context, localPath string, p *Path) (*graph.DriveItem, error) {
+	switch p.Scheme {
+	case SchemeOneDrive:
+		return graph.NewOneDrive(r.Client).UploadFile(ctx, localPath, p.Path)
+	case SchemeSharePoint:
+		sp := graph.NewSharePoint(r.Client)
+		siteID, driveID, err := r.resolveLibrary(ctx, sp, p)
+		if err != nil {
+			return nil, err
+		}
+		return sp.UploadToLibrary(ctx, siteID, driveID, p.Path, localPath)
+	default:
+		return nil, fmt.Errorf("unsupported remote scheme %q", p.Scheme)
+	}
+}
+
+// List returns the items in a remote folder.
+func (r *Resolver) List(ctx context.Context, p *Path) ([]graph.DriveItem, error) {
+	switch p.Scheme {
+	case SchemeOneDrive:
+		return graph.NewOneDrive(r.Client).ListFolder(ctx, p.Path)
+	case SchemeSharePoint:
+		sp := graph.NewSharePoint(r.Client)
+		siteID, driveID, err := r.resolveLibrary(ctx, sp, p)
+		if err != nil {
+			return nil, err
+		}
+		return sp.ListLibraryFiles(ctx, siteID, driveID, p.Path)
+	default:
+		return nil, fmt.Errorf("unsupported remote scheme %q", p.Scheme)
+	}
+}
+
+// Drive returns a client for the drive that holds p: the signed-in
+// user's OneDrive, or the SharePoint library p names.
+func (r *Resolver) Drive(ctx context.Context, p *Path) (*graph.OneDrive, error) {
+	switch p.Scheme {
+	case SchemeOneDrive:
+		return graph.NewOneDrive(r.Client), nil
+	case SchemeSharePoint:
+		_, driveID, err := r.resolveLibrary(ctx, graph.NewSharePoint(r.Client), p)
+		if err != nil {
+			return nil, err
+		}
+		return graph.NewDriveByID(r.Client, driveID), nil
+	default:
+		return nil, fmt.Errorf("unsupported remote scheme %q", p.Scheme)
+	}
+}
+
+// resolveLibrary maps the site and library of p (names, URLs, or IDs) to a
+// site ID and drive ID.
+func (r *Resolver) resolveLibrary(ctx context.Context, sp *graph.SharePoint, p *Path) (string, string, error) {
+	siteID, err := sp.ResolveSite(ctx, p.Site)
+	if err != nil {
+		return "", "", err
+	}
+	driveID, err := sp.ResolveLibrary(ctx, siteID, p.Library)
+	if err != nil {
+		return "", "", fmt.Errorf("site %s: %w", p.Site, err)
+	}
+	return siteID, driveID, nil
+}
+
+// Open returns a local path for arg. Local paths are returned unchanged;
+// remote paths are downloaded into a temporary directory, keeping the
+// original file name. The cleanup function removes any temporary files.
+func Open(ctx context.Context, arg string) (string, func(), error) {
+	noop := func() {}
+	if !IsRemote(arg) {
+		return arg, noop, nil
+	}
+
+	p, err := Parse(arg)
+	if err != nil {
+		return "", noop, err
+	}
+
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return "", noop, err
+	}
+
+	dir, err := os.MkdirTemp("", "kit-remote-*")
+	if err != nil {
+		return "", noop, fmt.Errorf("could not create temp directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	localPath := filepath.Join(dir, p.Base())
+	if _, err := NewResolver(client).Download(ctx, p, localPath); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("could not fetch %s: %w", arg, err)
+	}
+
+	return localPath, cleanup, nil
+}
+
+// OpenDir downloads the files of a remote folder into a temporary directory.
+// Subfolders are included when recursive is true. The returned map relates
+// each local file to its remote path.
+func OpenDir(ctx context.Context, arg string, recursive bool) (string, map[string]string, func(), error) {
+	noop := func() {}
+	p, err := Parse(arg)
+	if err != nil {
+		return "", nil, noop, err
+	}
+
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return "", nil, noop, err
+	}
+
+	dir, err := os.MkdirTemp("", "kit-remote-*")
+	if err != nil {
+		return "", nil, noop, fmt.Errorf("could not create temp directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	files := make(map[string]string)
+	if err := fetchDir(ctx, NewResolver(client), p, dir, recursive, files); err != nil {
+		cleanup()
+		return "", nil, noop, err
+	}
+
+	return dir, files, cleanup, nil
+}
+
+func fetchDir(ctx context.Context, r *Resolver, p *Path, localDir string, recursive bool, files map[string]string) error {
+	items, err := r.List(ctx, p)
+	if err != nil {
+		return fmt.Errorf("could not list %s: %w", p, err)
+	}
+
+	for _, item := range items {
+		child := *p
+		child.Path = cleanPath(p.Path + "/" + item.Name)
+		child.Raw = strings.TrimSuffix(p.Raw, "/") + "/" + item.Name
+		localPath := filepath.Join(localDir, item.Name)
+
+		if item.IsFolder {
+			if !recursive {
+				continue
+			}
+			if err := fetchDir(ctx, r, &child, localPath, recursive, files); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := r.Download(ctx, &child, localPath); err != nil {
+			return fmt.Errorf("could not fetch %s: %w", child.Raw, err)
+		}
+		files[localPath] = child.Raw
+	}
+	return nil
+}
+
+// Save uploads localPath to dest when dest is a remote path.
+// It is a no-op for local destinations.
+func Save(ctx context.Context, localPath, dest string) (*graph.DriveItem, error) {
+	if !IsRemote(dest) {
+		return nil, nil
+	}
+
+	p, err := Parse(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := NewResolver(client).Upload(ctx, localPath, p)
+	if err != nil {
+		return nil, fmt.Errorf("could not upload to %s: %w", dest, err)
+	}
+	return item, nil
+}
+
+// StagingPath returns a local path to write output destined for dest.
+// For remote destinations it is a file in a new temporary directory with the
+// same base name; call cleanup once Save has completed.
+func StagingPath(dest string) (string, func(), error) {
+	noop := func() {}
+	if !IsRemote(dest) {
+		return dest, noop, nil
+	}
+
+	p, err := Parse(dest)
+	if err != nil {
+		return "", noop, err
+	}
+
+	dir, err := os.MkdirTemp("", "kit-remote-*")
+	if err != nil {
+		return "", noop, fmt.Errorf("could not create temp directory: %w", err)
+	}
+	return filepath.Join(dir, p.Base()), func() { os.RemoveAll(dir) }, nil
+}
@@ -0,0 +1,121 @@
+package remote
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIsRemote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"onedrive:/doc.docx", true},
+		{"sp:site-1/Documents/doc.docx", true},
+		{"doc.docx", false},
+		{"./onedrive/doc.docx", false},
+		{"C:/Users/me/doc.docx", false},
+	}
+	for _, tt := range tests {
+		if got := IsRemote(tt.in); got != tt.want {
+			t.Errorf("IsRemote(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseOneDrive(t *testing.T) {
+	p, err := Parse("onedrive:/Reports/Q1/summary.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Scheme != SchemeOneDrive {
+		t.Errorf("Scheme = %q", p.Scheme)
+	}
+	if p.Path != "Reports/Q1/summary.docx" {
+		t.Errorf("Path = %q", p.Path)
+	}
+	if p.Base() != "summary.docx" {
+		t.Errorf("Base() = %q", p.Base())
+	}
+}
+
+func TestParseOneDriveRoot(t *testing.T) {
+	p, err := Parse("onedrive:/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Path != "/" {
+		t.Errorf("Path = %q, want /", p.Path)
+	}
+}
+
+func TestParseSharePoint(t *testing.T) {
+	p, err := Parse("sp:contoso.sharepoint.com,abc,def/Documents/Contracts/nda.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Scheme != SchemeSharePoint {
+		t.Errorf("Scheme = %q", p.Scheme)
+	}
+	if p.Site != "contoso.sharepoint.com,abc,def" {
+		t.Errorf("Site = %q", p.Site)
+	}
+	if p.Library != "Documents" {
+		t.Errorf("Library = %q", p.Library)
+	}
+	if p.Path != "Contracts/nda.docx" {
+		t.Errorf("Path = %q", p.Path)
+	}
+}
+
+func TestParseSharePointLibraryRoot(t *testing.T) {
+	p, err := Parse("sp:site-1/Documents/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Path != "/" {
+		t.Errorf("Path = %q, want /", p.Path)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{"sp:site-only", "sp:/", "report.docx"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) expected error", in)
+		}
+	}
+}
+
+func TestOpenLocalPassthrough(t *testing.T) {
+	path, cleanup, err := Open(context.Background(), "local.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if path != "local.docx" {
+		t.Errorf("path = %q", path)
+	}
+}
+
+func TestStagingPath(t *testing.T) {
+	local, cleanup, err := StagingPath("onedrive:/out/report.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if local == "onedrive:/out/report.md" {
+		t.Error("expected a local staging path")
+	}
+	if !strings.HasSuffix(local, "report.md") {
+		t.Errorf("staging path %q does not keep base name", local)
+	}
+
+	local, _, err = StagingPath("out.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local != "out.md" {
+		t.Errorf("local destination changed: %q", local)
+	}
+}
@@ -0,0 +1,104 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxLateness is how overdue a run may be and still execute, e.g. after the
+// daemon was stopped. Older runs are skipped and the job is rescheduled.
+var MaxLateness = time.Hour
+
+// Executor performs one job.
+type Executor func(ctx context.Context, job Job) error
+
+// RunResult reports one due job that was run or skipped.
+type RunResult struct {
+	Job     Job
+	Skipped bool
+	Err     error
+}
+
+// RunDue runs every job whose time has come, then reschedules recurring
+// jobs and drops finished one-time jobs. The store is re-read each call so
+// jobs added from another kit process are picked up. Jobs run without the
+// schedule's lock held, since posts can take a while; the results are then
+// merged by ID into the store as it is at that point, so jobs added or
+// removed meanwhile are kept that way.
+func (s *Store) RunDue(ctx context.Context, now time.Time, exec Executor) ([]RunResult, error) {
+	jobs, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []RunResult
+	done := make(map[string]*Job) // ID → rescheduled job, or nil when finished
+	for _, job := range jobs {
+		if job.NextRun.After(now) {
+			continue
+		}
+
+		result := RunResult{Job: job}
+		if now.Sub(job.NextRun) > MaxLateness {
+			result.Skipped = true
+			job.LastError = fmt.Sprintf("missed run at %s", job.NextRun.Format("2006-01-02 15:04"))
+		} else {
+			ran := now
+			job.LastRun = &ran
+			job.LastError = ""
+			if err := exec(ctx, job); err != nil {
+				result.Err = err
+				job.LastError = err.Error()
+			}
+		}
+		results = append(results, result)
+
+		done[job.ID] = nil
+		if job.Cron == "" {
+			continue // one-time job is done
+		}
+		if c, err := ParseCron(job.Cron); err != nil {
+			job.LastError = err.Error()
+		} else {
+			job.NextRun = c.Next(now)
+			done[job.ID] = &job
+		}
+	}
+
+	if len(done) == 0 {
+		return results, nil
+	}
+	err = s.update(func(current []Job) ([]Job, error) {
+		var kept []Job
+		for _, job := range current {
+			next, ran := done[job.ID]
+			switch {
+			case !ran:
+				kept = append(kept, job)
+			case next != nil:
+				kept = append(kept, *next)
+			}
+		}
+		return kept, nil
+	})
+	return results, err
+}
+
+// Loop calls RunDue every interval until ctx is cancelled, passing each
+// batch of results to report.
+func (s *Store) Loop(ctx context.Context, interval time.Duration, exec Executor, report func([]RunResult, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		results, err := s.RunDue(ctx, time.Now(), exec)
+		if report != nil && (len(results) > 0 || err != nil) {
+			report(results, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseCronNext(t *testing.T) {
+	loc := time.UTC
+	// Friday 2026-10-16 10:30
+	now := time.Date(2026, 10, 16, 10, 30, 0, 0, loc)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 10, 16, 10, 45, 0, 0, loc)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, loc)},
+		{"0 9 * * mon,thu", time.Date(2026, 10, 19, 9, 0, 0, 0, loc)},
+		{"30 16 * * 7", time.Date(2026, 10, 18, 16, 30, 0, 0, loc)},
+		{"0 0 1 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, loc)},
+		{"0 12 1 * fri", time.Date(2026, 10, 16, 12, 0, 0, 0, loc)}, // day of month OR weekday
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := c.Next(now); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * * * funday", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q): expected error", expr)
+		}
+	}
+}
+
+func TestParseAt(t *testing.T) {
+	loc := time.UTC
+	tests := []struct {
+		at   string
+		cron string
+	}{
+		{"09:00", "0 9 * * *"},
+		{"daily 09:00", "0 9 * * *"},
+		{"weekdays 08:15", "15 8 * * 1-5"},
+		{"Mon 09:00", "0 9 * * mon"},
+		{"mon,thu 16:30", "30 16 * * mon,thu"},
+	}
+	for _, tt := range tests {
+		c, _, err := ParseAt(tt.at, loc)
+		if err != nil {
+			t.Fatalf("ParseAt(%q): %v", tt.at, err)
+		}
+		if c == nil || c.String() != tt.cron {
+			t.Errorf("ParseAt(%q) = %v, want %q", tt.at, c, tt.cron)
+		}
+	}
+
+	c, when, err := ParseAt("2026-10-20 09:00", loc)
+	if err != nil || c != nil {
+		t.Fatalf("one-time ParseAt: cron=%v err=%v", c, err)
+	}
+	if !when.Equal(time.Date(2026, 10, 20, 9, 0, 0, 0, loc)) {
+		t.Errorf("one-time ParseAt = %s", when)
+	}
+
+	for _, bad := range []string{"", "9am", "someday 09:00", "Mon 25:00"} {
+		if _, _, err := ParseAt(bad, loc); err == nil {
+			t.Errorf("ParseAt(%q): expected error", bad)
+		}
+	}
+}
+
+func TestStoreRunDue(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "schedule.json")}
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.Local)
+
+	daily, err := store.Add(Job{Kind: KindTeamsPost, Cron: "0 9 * * *", Post: &TeamsPost{Team: "Eng", Channel: "general", Message: "standup"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Add(Job{Kind: KindTeamsPost, NextRun: now.Add(30 * time.Minute), Post: &TeamsPost{Webhook: "https://example.com/hook", Message: "once"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Job{Kind: KindTeamsPost, NextRun: now.Add(-time.Minute)}, now); err == nil {
+		t.Error("expected error for a time in the past")
+	}
+
+	var ran []string
+	exec := func(ctx context.Context, job Job) error {
+		ran = append(ran, job.ID)
+		if job.ID == daily.ID {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	// Nothing is due yet.
+	results, err := store.RunDue(context.Background(), now.Add(10*time.Minute), exec)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("early RunDue: %v, %v", results, err)
+	}
+
+	results, err = store.RunDue(context.Background(), now.Add(time.Hour), exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || len(ran) != 2 {
+		t.Fatalf("expected both jobs to run, got %v", ran)
+	}
+
+	jobs, _ := store.Load()
+	if len(jobs) != 1 || jobs[0].ID != daily.ID {
+		t.Fatalf("expected only the recurring job to remain, got %+v", jobs)
+	}
+	if jobs[0].LastError != "boom" || jobs[0].LastRun == nil {
+		t.Errorf("last run not recorded: %+v", jobs[0])
+	}
+	if want := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local); !jobs[0].NextRun.Equal(want) {
+		t.Errorf("NextRun = %s, want %s", jobs[0].NextRun, want)
+	}
+
+	// A run missed by more than MaxLateness is skipped, not executed.
+	ran = nil
+	results, _ = store.RunDue(context.Background(), jobs[0].NextRun.Add(3*time.Hour), exec)
+	if len(results) != 1 || !results[0].Skipped || len(ran) != 0 {
+		t.Errorf("expected a skipped run, got %+v (ran %v)", results, ran)
+	}
+
+	if err := store.Remove(daily.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Remove(daily.ID); err == nil {
+		t.Error("expected error removing a missing job")
+	}
+}
+
+func TestRunDueKeepsJobsAddedDuringRun(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "schedule.json")}
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.Local)
+
+	due, err := store.Add(Job{Kind: KindTeamsPost, NextRun: now.Add(time.Minute), Post: &TeamsPost{Message: "due"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var added *Job
+	exec := func(ctx context.Context, job Job) error {
+		// Another process schedules a post while this one is running
+		added, err = store.Add(Job{Kind: KindTeamsPost, NextRun: now.Add(time.Hour), Post: &TeamsPost{Message: "later"}}, now)
+		return err
+	}
+	if _, err := store.RunDue(context.Background(), now.Add(2*time.Minute), exec); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, _ := store.Load()
+	if len(jobs) != 1 || jobs[0].ID != added.ID || jobs[0].ID == due.ID {
+		t.Errorf("expected only the job added during the run to remain, got %+v", jobs)
+	}
+}
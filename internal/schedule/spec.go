@@ -0,0 +1,191 @@
+// Package schedule stores jobs that run at a set time or on a recurring
+// schedule, such as Teams posts, and runs them when they are due.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month,
+// month, and day of week. Fields accept *, lists, ranges, and steps
+// (e.g. "*/15", "1-5", "mon,wed"). Times are in the local time zone.
+type Cron struct {
+	expr                          string
+	minute, hour, dom, month, dow []bool
+	domStar, dowStar              bool
+}
+
+var dowNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+// ParseCron parses a five-field cron expression.
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q — expected 5 fields: minute hour day month weekday", expr)
+	}
+	c := &Cron{expr: strings.Join(fields, " ")}
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron minute: %w", err)
+	}
+	if c.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron hour: %w", err)
+	}
+	if c.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron day of month: %w", err)
+	}
+	if c.month, err = parseField(fields[3], 1, 12, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron month: %w", err)
+	}
+	if c.dow, err = parseField(fields[4], 0, 7, dowNames); err != nil {
+		return nil, fmt.Errorf("invalid cron weekday: %w", err)
+	}
+	c.dow[0] = c.dow[0] || c.dow[7] // 7 is also Sunday
+	c.domStar, c.dowStar = fields[2] == "*", fields[4] == "*"
+	return c, nil
+}
+
+func parseField(field string, min, max int, names map[string]int) ([]bool, error) {
+	set := make([]bool, max+1)
+	value := func(s string) (int, error) {
+		if n, ok := names[strings.ToLower(s)]; ok {
+			return n, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return 0, fmt.Errorf("%q is not between %d and %d", s, min, max)
+		}
+		return n, nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = value(from); err != nil {
+				return nil, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = value(to); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				hi = max
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("invalid range %q", rng)
+			}
+		}
+		for n := lo; n <= hi; n += step {
+			set[n] = true
+		}
+	}
+	return set, nil
+}
+
+// String returns the expression.
+func (c *Cron) String() string { return c.expr }
+
+// Next returns the first matching minute after t.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches follows cron's rule: when both day of month and weekday are
+// restricted, either one matching is enough.
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dow
+	case c.dowStar:
+		return dom
+	}
+	return dom || dow
+}
+
+// ParseAt converts the friendly --at forms into either a recurring cron
+// schedule or a single time:
+//
+//	"09:00" or "daily 09:00"   every day
+//	"weekdays 09:00"            Monday to Friday
+//	"Mon 09:00", "mon,thu 16:30" on those weekdays
+//	"2026-10-20 09:00"          once, at that date and time
+func ParseAt(s string, loc *time.Location) (*Cron, time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, loc); err == nil {
+		return nil, t, nil
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, time.Time{}, fmt.Errorf("invalid --at %q — use e.g. \"09:00\", \"Mon 09:00\", \"weekdays 09:00\", or \"2026-10-20 09:00\"", s)
+	}
+	clock, err := time.Parse("15:04", fields[len(fields)-1])
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid time in --at %q — use 24-hour HH:MM", s)
+	}
+
+	days := "*"
+	if len(fields) == 2 {
+		switch d := strings.ToLower(fields[0]); d {
+		case "daily", "everyday":
+		case "weekdays":
+			days = "1-5"
+		case "weekends":
+			days = "0,6"
+		default:
+			for _, name := range strings.Split(d, ",") {
+				if _, ok := dowNames[name[:min(3, len(name))]]; !ok {
+					return nil, time.Time{}, fmt.Errorf("invalid day %q in --at — use Mon..Sun, weekdays, weekends, or daily", name)
+				}
+			}
+			var short []string
+			for _, name := range strings.Split(d, ",") {
+				short = append(short, name[:3])
+			}
+			days = strings.Join(short, ",")
+		}
+	}
+
+	c, err := ParseCron(fmt.Sprintf("%d %d * * %s", clock.Minute(), clock.Hour(), days))
+	return c, time.Time{}, err
+}
@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/klytics/m365kit/internal/filelock"
+)
+
+// Job kinds.
+const KindTeamsPost = "teams.post"
+
+// TeamsPost is what a teams.post job sends.
+type TeamsPost struct {
+	Team           string          `json:"team,omitempty"`
+	Channel        string          `json:"channel,omitempty"`
+	Webhook        string          `json:"webhook,omitempty"`
+	Message        string          `json:"message,omitempty"`
+	HTML           string          `json:"html,omitempty"` // rendered from a message template
+	Card           json.RawMessage `json:"card,omitempty"`
+	Attach         string          `json:"attach,omitempty"`
+	Mentions       []string        `json:"mentions,omitempty"`
+	MentionChannel bool            `json:"mentionChannel,omitempty"`
+}
+
+// Job is a scheduled piece of work. Recurring jobs have a Cron expression;
+// one-time jobs have only NextRun and are removed after they run.
+type Job struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Cron      string     `json:"cron,omitempty"`
+	NextRun   time.Time  `json:"nextRun"`
+	LastRun   *time.Time `json:"lastRun,omitempty"`
+	LastError string     `json:"lastError,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Post      *TeamsPost `json:"post,omitempty"`
+}
+
+// Describe summarizes the job for listings.
+func (j Job) Describe() string {
+	if j.Post == nil {
+		return j.Kind
+	}
+	target := "#" + j.Post.Channel + " in " + j.Post.Team
+	if j.Post.Webhook != "" {
+		target = "webhook"
+	}
+	text := j.Post.Message
+	switch {
+	case j.Post.Card != nil:
+		text = "(card)"
+	case j.Post.HTML != "":
+		text = "(template)"
+	case j.Post.Attach != "":
+		text = filepath.Base(j.Post.Attach)
+	}
+	if len(text) > 40 {
+		text = text[:37] + "..."
+	}
+	return target + ": " + text
+}
+
+// Store is the schedule file, ~/.kit/schedule.json by default.
+type Store struct {
+	Path string
+}
+
+// DefaultStore returns the store in ~/.kit.
+func DefaultStore() *Store {
+	home, _ := os.UserHomeDir()
+	return &Store{Path: filepath.Join(home, ".kit", "schedule.json")}
+}
+
+// Load returns all jobs, soonest first. A missing file means no jobs.
+func (s *Store) Load() ([]Job, error) {
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var jobs []Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("invalid schedule file %s: %w", s.Path, err)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].NextRun.Before(jobs[j].NextRun) })
+	return jobs, nil
+}
+
+// Save replaces the stored jobs. The file is written atomically and is
+// private to the user, since jobs may hold webhook URLs. Save doesn't take
+// the schedule's lock; Add, Remove, and RunDue do.
+func (s *Store) Save(jobs []Job) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	if jobs == nil {
+		jobs = []Job{}
+	}
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+// Add stores a new job and returns it with its ID and first run time set.
+func (s *Store) Add(job Job, now time.Time) (*Job, error) {
+	if job.Cron != "" {
+		c, err := ParseCron(job.Cron)
+		if err != nil {
+			return nil, err
+		}
+		job.NextRun = c.Next(now)
+	} else if !job.NextRun.After(now) {
+		return nil, fmt.Errorf("scheduled time %s is in the past", job.NextRun.Format("2006-01-02 15:04"))
+	}
+
+	job.ID = newID()
+	job.CreatedAt = now
+	err := s.update(func(jobs []Job) ([]Job, error) {
+		return append(jobs, job), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Remove deletes a job by ID.
+func (s *Store) Remove(id string) error {
+	return s.update(func(jobs []Job) ([]Job, error) {
+		for i, j := range jobs {
+			if j.ID == id {
+				return append(jobs[:i], jobs[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("no scheduled job %q — run: kit teams schedule list", id)
+	})
+}
+
+// update replaces the stored jobs with what fn makes of them, holding the
+// schedule's lock so changes made by other kit processes at the same time
+// aren't lost.
+func (s *Store) update(fn func([]Job) ([]Job, error)) error {
+	unlock, err := filelock.Lock(s.Path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	jobs, err := s.Load()
+	if err != nil {
+		return err
+	}
+	if jobs, err = fn(jobs); err != nil {
+		return err
+	}
+	return s.Save(jobs)
+}
+
+func newID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,64 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/httpclient"
+)
+
+// Execute runs a job with the signed-in account. It is the Executor used
+// by "kit teams schedule run" and "kit watch start".
+func Execute(ctx context.Context, job Job) error {
+	switch job.Kind {
+	case KindTeamsPost:
+		if job.Post == nil {
+			return fmt.Errorf("job %s has nothing to post", job.ID)
+		}
+		return postToTeams(ctx, job.Post)
+	default:
+		return fmt.Errorf("unknown job kind %q", job.Kind)
+	}
+}
+
+// postToTeams sends a scheduled post. Team and channel names are resolved
+// at run time, so renamed channels are picked up.
+func postToTeams(ctx context.Context, p *TeamsPost) error {
+	if p.Webhook != "" {
+		client, err := httpclient.New()
+		if err != nil {
+			return err
+		}
+		return graph.PostWebhook(ctx, client, p.Webhook, p.Message, p.Card)
+	}
+
+	client, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return err
+	}
+	tc := graph.NewTeams(client)
+	teamID, err := tc.ResolveTeamID(ctx, p.Team)
+	if err != nil {
+		return err
+	}
+	channelID, err := tc.ResolveChannelID(ctx, teamID, p.Channel)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case p.Card != nil:
+		_, err = tc.PostCard(ctx, teamID, channelID, p.Message, p.Card)
+	case len(p.Mentions) > 0 || p.MentionChannel:
+		_, err = tc.PostMentionMessage(ctx, teamID, channelID, p.Message, p.Mentions, p.MentionChannel)
+	case p.HTML != "":
+		_, err = tc.PostHTMLMessage(ctx, teamID, channelID, p.HTML)
+	case p.Attach != "":
+		_, err = tc.PostMessageWithFile(ctx, teamID, channelID, p.Message, p.Attach)
+	default:
+		_, err = tc.PostMessage(ctx, teamID, channelID, p.Message)
+	}
+	return err
+}
@@ -0,0 +1,364 @@
+// Package semantic keeps a local index of document text embeddings in
+// ~/.kit/index, so kit ai find can rank files and sections by meaning
+// rather than by keyword. Files are split into sections, each embedded
+// once; reindexing only embeds files that changed.
+package semantic
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/ai"
+	"github.com/klytics/m365kit/internal/formats/convert"
+)
+
+// indexVersion changes when the index format does; older indexes are
+// rebuilt.
+const indexVersion = 1
+
+// sectionSize and sectionOverlap are the size in characters of the
+// sections files are embedded in, and how much consecutive ones share.
+const (
+	sectionSize    = 2000
+	sectionOverlap = 200
+)
+
+// Extensions are the file types indexed: documents, not data.
+var Extensions = map[string]bool{
+	".docx": true, ".xlsx": true, ".pptx": true, ".html": true, ".htm": true,
+	".txt": true, ".md": true, ".markdown": true,
+}
+
+// Index is the set of indexed files and their section embeddings. It is
+// stored with encoding/gob rather than JSON, since vectors make up nearly
+// all of it.
+type Index struct {
+	// Path is where the index is stored.
+	Path string
+
+	Version int
+	// Embedder is the ai.Embedder name the vectors came from; queries
+	// must use the same one.
+	Embedder string
+	Files    map[string]*File
+}
+
+// File is an indexed file.
+type File struct {
+	Path      string
+	ModTime   time.Time
+	Size      int64
+	IndexedAt time.Time
+	Sections  []Section
+}
+
+// Section is a part of a file and its embedding.
+type Section struct {
+	Heading string
+	Text    string
+	Vector  []float32
+}
+
+// DefaultPath returns ~/.kit/index/semantic.gob.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".kit", "index", "semantic.gob")
+	}
+	return filepath.Join(home, ".kit", "index", "semantic.gob")
+}
+
+// Load reads the index at path. A missing file, or one in an older
+// format, gives an empty index.
+func Load(path string) (*Index, error) {
+	ix := &Index{Path: path, Version: indexVersion, Files: make(map[string]*File)}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read index at %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var stored Index
+	if err := gob.NewDecoder(f).Decode(&stored); err != nil {
+		return nil, fmt.Errorf("invalid index %s (rebuild it with kit ai index --rebuild): %w", path, err)
+	}
+	if stored.Version != indexVersion {
+		return ix, nil
+	}
+	stored.Path = path
+	if stored.Files == nil {
+		stored.Files = make(map[string]*File)
+	}
+	return &stored, nil
+}
+
+// Save writes the index back to Path, replacing it atomically.
+func (ix *Index) Save() error {
+	if err := os.MkdirAll(filepath.Dir(ix.Path), 0700); err != nil {
+		return fmt.Errorf("could not create index directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(ix.Path), ".semantic-*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not write index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(ix); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not write index: %w", err)
+	}
+	return os.Rename(tmp.Name(), ix.Path)
+}
+
+// Sections returns how many sections are indexed.
+func (ix *Index) Sections() int {
+	n := 0
+	for _, f := range ix.Files {
+		n += len(f.Sections)
+	}
+	return n
+}
+
+// EmbedderFor returns the embedder an index was built with.
+func EmbedderFor(ix *Index) (ai.Embedder, error) {
+	if ix.Embedder == "" {
+		return nil, fmt.Errorf("the index is empty — build it with kit ai index <directory>")
+	}
+	name, model, _ := strings.Cut(ix.Embedder, "/")
+	return ai.NewEmbedder(name, model)
+}
+
+// UpdateOptions configures Update.
+type UpdateOptions struct {
+	Recursive bool
+	// Progress, if set, is called before each file that is (re)indexed.
+	Progress func(path string, done, total int)
+}
+
+// UpdateResult summarizes an Update.
+type UpdateResult struct {
+	Indexed   int       `json:"indexed"`
+	Unchanged int       `json:"unchanged"`
+	Removed   int       `json:"removed"`
+	Sections  int       `json:"sections"`
+	Failed    []Failure `json:"failed,omitempty"`
+}
+
+// Failure is a file that couldn't be indexed.
+type Failure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// Update indexes the documents under roots (files or directories) with e:
+// new and changed files are embedded, unchanged ones kept, and indexed
+// files that are gone from the directories removed. Files that can't be
+// read are reported in the result rather than failing the update; an
+// embedder error stops it, keeping what was indexed so far.
+func (ix *Index) Update(ctx context.Context, e ai.Embedder, roots []string, opts UpdateOptions) (*UpdateResult, error) {
+	if ix.Embedder != "" && ix.Embedder != e.Name() && len(ix.Files) > 0 {
+		return nil, fmt.Errorf("the index was built with %s, not %s — use --rebuild to re-embed everything with %s", ix.Embedder, e.Name(), e.Name())
+	}
+	ix.Embedder = e.Name()
+
+	result := &UpdateResult{}
+	var todo []string
+	for _, root := range roots {
+		root, err := filepath.Abs(root)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve path: %w", err)
+		}
+		files, err := documents(root, opts.Recursive)
+		if err != nil {
+			return nil, err
+		}
+		result.Removed += ix.removeMissing(root, opts.Recursive, files)
+
+		for _, path := range files {
+			info, err := os.Stat(path)
+			if err != nil {
+				result.Failed = append(result.Failed, Failure{Path: path, Error: err.Error()})
+				continue
+			}
+			if f, ok := ix.Files[path]; ok && f.ModTime.Equal(info.ModTime()) && f.Size == info.Size() {
+				result.Unchanged++
+				continue
+			}
+			todo = append(todo, path)
+		}
+	}
+
+	for i, path := range todo {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if opts.Progress != nil {
+			opts.Progress(path, i, len(todo))
+		}
+		f, err := indexFile(ctx, e, path)
+		if err != nil {
+			var readErr *readError
+			if errors.As(err, &readErr) {
+				result.Failed = append(result.Failed, Failure{Path: path, Error: readErr.Error()})
+				continue
+			}
+			return result, fmt.Errorf("could not embed %s: %w", filepath.Base(path), err)
+		}
+		ix.Files[path] = f
+		result.Indexed++
+		result.Sections += len(f.Sections)
+	}
+	return result, nil
+}
+
+// removeMissing drops indexed files under root that weren't found there,
+// and returns how many.
+func (ix *Index) removeMissing(root string, recursive bool, found []string) int {
+	seen := make(map[string]bool, len(found))
+	for _, p := range found {
+		seen[p] = true
+	}
+	removed := 0
+	for path := range ix.Files {
+		if seen[path] || !under(path, root, recursive) {
+			continue
+		}
+		delete(ix.Files, path)
+		removed++
+	}
+	return removed
+}
+
+// under reports whether path is root, or in it (directly, unless
+// recursive).
+func under(path, root string, recursive bool) bool {
+	if path == root {
+		return true
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return recursive || !strings.Contains(rel, string(filepath.Separator))
+}
+
+// documents returns the indexable files at root: root itself if it is a
+// file, or else the documents in it, skipping hidden directories and
+// Office lock files.
+func documents(root string, recursive bool) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("could not access %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		if !Extensions[strings.ToLower(filepath.Ext(root))] {
+			return nil, fmt.Errorf("cannot index %s files", filepath.Ext(root))
+		}
+		return []string{root}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip inaccessible
+		}
+		if d.IsDir() {
+			if path != root && (!recursive || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), "~$") || !Extensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s: %w", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readError is a file that couldn't be read, as opposed to an embedder
+// failure.
+type readError struct{ err error }
+
+func (e *readError) Error() string { return e.err.Error() }
+
+// indexFile reads path, splits it into sections, and embeds them.
+func indexFile(ctx context.Context, e ai.Embedder, path string) (*File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, &readError{err}
+	}
+	text, err := convert.FileToMarkdown(path)
+	if err != nil {
+		return nil, &readError{err}
+	}
+
+	f := &File{Path: path, ModTime: info.ModTime(), Size: info.Size(), IndexedAt: time.Now()}
+	var inputs []string
+	for _, s := range splitSections(text) {
+		f.Sections = append(f.Sections, s)
+		// The file name and heading help place a section that doesn't
+		// say what it is about
+		inputs = append(inputs, filepath.Base(path)+"\n"+s.Heading+"\n"+s.Text)
+	}
+	if len(inputs) == 0 {
+		return f, nil
+	}
+
+	vectors, err := e.Embed(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range f.Sections {
+		ai.Normalize(vectors[i])
+		f.Sections[i].Vector = vectors[i]
+	}
+	return f, nil
+}
+
+var headingLine = regexp.MustCompile(`(?m)^#{1,6} +(.*)$`)
+
+// splitSections splits Markdown text at its headings, then splits sections
+// longer than sectionSize into overlapping parts. A heading with no text
+// before the next one is kept as the start of that section.
+func splitSections(text string) []Section {
+	var sections []Section
+	add := func(heading, body string) {
+		for _, c := range ai.ChunkDocument(body, ai.ChunkOptions{MaxChunkSize: sectionSize, Overlap: sectionOverlap}) {
+			if t := strings.TrimSpace(c.Text); t != "" {
+				sections = append(sections, Section{Heading: heading, Text: t})
+			}
+		}
+	}
+
+	heading, start := "", 0
+	for _, m := range headingLine.FindAllStringSubmatchIndex(text, -1) {
+		body := text[start:m[0]]
+		if strings.TrimSpace(headingLine.ReplaceAllString(body, "")) == "" && start > 0 {
+			continue // only headings so far: keep them with what follows
+		}
+		add(heading, body)
+		heading, start = strings.TrimSpace(text[m[2]:m[3]]), m[0]
+	}
+	add(heading, text[start:])
+	return sections
+}
@@ -0,0 +1,147 @@
+package semantic
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klytics/m365kit/internal/ai"
+)
+
+func writeDocs(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func localEmbedder(t *testing.T) ai.Embedder {
+	t.Helper()
+	e, err := ai.NewEmbedder("local", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestIndexAndSearch(t *testing.T) {
+	dir := t.TempDir()
+	writeDocs(t, dir, map[string]string{
+		"contract.md": "# Payment\n\nInvoices are due within thirty days of receipt.\n\n" +
+			"# Indemnification\n\nThe Supplier shall indemnify the Customer against all claims, and these indemnification obligations survive termination.\n",
+		"finance/forecast.txt": "Quarterly revenue forecast for EMEA, with growth expected in the third quarter.",
+		"notes.csv":            "not,a,document",
+		".git/HEAD":            "ref: refs/heads/main",
+	})
+	indexPath := filepath.Join(t.TempDir(), "semantic.gob")
+	e := localEmbedder(t)
+	ctx := context.Background()
+
+	ix, err := Load(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ix.Update(ctx, e, []string{dir}, UpdateOptions{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Indexed != 2 || len(result.Failed) != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reloaded, unchanged files are skipped and deleted ones dropped
+	ix, err = Load(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ix.Files) != 2 || ix.Embedder != "local/hash-512" {
+		t.Fatalf("loaded %d files from %s", len(ix.Files), ix.Embedder)
+	}
+	os.Remove(filepath.Join(dir, "finance", "forecast.txt"))
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(dir, "contract.md"), later, later)
+	result, err = ix.Update(ctx, e, []string{dir}, UpdateOptions{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Indexed != 1 || result.Removed != 1 || result.Unchanged != 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	writeDocs(t, dir, map[string]string{"finance/forecast.txt": "Quarterly revenue forecast for EMEA."})
+	if _, err := ix.Update(ctx, e, []string{dir}, UpdateOptions{Recursive: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ix.Search(ctx, e, "indemnification obligations", SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 || !strings.HasSuffix(results[0].Path, "contract.md") || results[0].Heading != "Indemnification" {
+		t.Fatalf("results = %+v", results)
+	}
+	if !strings.Contains(results[0].Snippet, "indemnif") {
+		t.Errorf("snippet = %q", results[0].Snippet)
+	}
+
+	results, err = ix.Search(ctx, e, "revenue forecast", SearchOptions{PerFile: true, Under: filepath.Join(dir, "finance")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !strings.HasSuffix(results[0].Path, "forecast.txt") {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestUpdateNonRecursive(t *testing.T) {
+	dir := t.TempDir()
+	writeDocs(t, dir, map[string]string{"a.md": "alpha", "sub/b.md": "beta"})
+	ix, _ := Load(filepath.Join(t.TempDir(), "semantic.gob"))
+	result, err := ix.Update(context.Background(), localEmbedder(t), []string{dir}, UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Indexed != 1 {
+		t.Errorf("indexed %d files, want 1", result.Indexed)
+	}
+}
+
+func TestUpdateEmbedderMismatch(t *testing.T) {
+	dir := t.TempDir()
+	writeDocs(t, dir, map[string]string{"a.md": "alpha"})
+	ix, _ := Load(filepath.Join(t.TempDir(), "semantic.gob"))
+	ix.Embedder = "openai/text-embedding-3-small"
+	ix.Files["/elsewhere/b.md"] = &File{Path: "/elsewhere/b.md"}
+
+	_, err := ix.Update(context.Background(), localEmbedder(t), []string{dir}, UpdateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "--rebuild") {
+		t.Errorf("expected error suggesting --rebuild, got %v", err)
+	}
+	if _, err := ix.Search(context.Background(), localEmbedder(t), "alpha", SearchOptions{}); err == nil {
+		t.Error("expected error searching with another embedder")
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("Background text about other matters. ", 20) +
+		"The Supplier shall indemnify the Customer. " + strings.Repeat("More text. ", 40)
+	got := Snippet(text, "indemnify")
+	if !strings.HasPrefix(got, "…The Supplier shall indemnify") || !strings.HasSuffix(got, "…") {
+		t.Errorf("Snippet = %q", got)
+	}
+	if got := Snippet("# Title\n\nShort   text.", "x"); got != "Title Short text." {
+		t.Errorf("Snippet = %q", got)
+	}
+}
@@ -0,0 +1,183 @@
+package semantic
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/klytics/m365kit/internal/ai"
+)
+
+// snippetLength is about how many characters of a section a result shows.
+const snippetLength = 240
+
+var headingMarker = regexp.MustCompile(`(?m)^#{1,6}\s+`)
+
+// SearchOptions configures Search.
+type SearchOptions struct {
+	// Limit is the most results returned; 0 means 10.
+	Limit int
+	// PerFile returns only the best section of each file.
+	PerFile bool
+	// Under limits results to files in this directory, if set.
+	Under string
+}
+
+// Result is a section that matched a query.
+type Result struct {
+	Path    string  `json:"path"`
+	Heading string  `json:"heading,omitempty"`
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+}
+
+// Search ranks the indexed sections by how close they are to query, using
+// e, which must be the embedder the index was built with.
+func (ix *Index) Search(ctx context.Context, e ai.Embedder, query string, opts SearchOptions) ([]Result, error) {
+	if ix.Embedder != e.Name() {
+		return nil, fmt.Errorf("the index was built with %s; search it with the same embedder", ix.Embedder)
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 10
+	}
+	under := ""
+	if opts.Under != "" {
+		abs, err := filepath.Abs(opts.Under)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve path: %w", err)
+		}
+		under = abs
+	}
+
+	vectors, err := e.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("could not embed the query: %w", err)
+	}
+	q := vectors[0]
+	ai.Normalize(q)
+
+	type hit struct {
+		file    *File
+		section int
+		score   float64
+	}
+	var hits []hit
+	for path, f := range ix.Files {
+		if under != "" && !isUnder(path, under) {
+			continue
+		}
+		var best *hit
+		for i, s := range f.Sections {
+			h := hit{f, i, dot(q, s.Vector)}
+			if h.score <= 0 {
+				continue // nothing in common
+			}
+			if !opts.PerFile {
+				hits = append(hits, h)
+			} else if best == nil || h.score > best.score {
+				best = &h
+			}
+		}
+		if best != nil {
+			hits = append(hits, *best)
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		if hits[i].file.Path != hits[j].file.Path {
+			return hits[i].file.Path < hits[j].file.Path
+		}
+		return hits[i].section < hits[j].section
+	})
+	if len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+
+	results := make([]Result, len(hits))
+	for i, h := range hits {
+		s := h.file.Sections[h.section]
+		results[i] = Result{
+			Path:    h.file.Path,
+			Heading: s.Heading,
+			Snippet: Snippet(s.Text, query),
+			Score:   h.score,
+		}
+	}
+	return results, nil
+}
+
+// isUnder reports whether path is dir or anywhere in it.
+func isUnder(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// dot is the dot product of two unit vectors: their cosine similarity.
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// Snippet returns about snippetLength characters of text on one line,
+// starting near the first word it shares with query.
+func Snippet(text, query string) string {
+	text = strings.Join(strings.Fields(headingMarker.ReplaceAllString(text, "")), " ")
+	if len(text) <= snippetLength {
+		return text
+	}
+
+	start := -1
+	lower := strings.ToLower(text)
+	for _, w := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		if len(w) < 4 {
+			continue
+		}
+		if i := strings.Index(lower, w); i >= 0 && (start < 0 || i < start) {
+			start = i
+		}
+	}
+	// Back up to the start of the sentence, or a little context
+	if start <= 0 {
+		start = 0
+	} else {
+		if i := strings.LastIndex(text[:start], ". "); i >= 0 && start-i < snippetLength/2 {
+			start = i + 2
+		} else if start > 40 {
+			start -= 40
+			if i := strings.IndexByte(text[start:], ' '); i >= 0 {
+				start += i + 1
+			}
+		} else {
+			start = 0
+		}
+	}
+
+	end := start + snippetLength
+	if end >= len(text) {
+		end = len(text)
+	} else if i := strings.LastIndexByte(text[start:end], ' '); i > 0 {
+		end = start + i
+	}
+	snippet := text[start:end]
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
@@ -0,0 +1,69 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/klytics/m365kit/internal/alias"
+)
+
+// Aliases are shared with the top-level CLI (kit <alias>) and saved to
+// ~/.kit/aliases.yaml; "alias standup = teams post --team Eng --stdin"
+// defines one.
+
+// evalAlias runs an "alias" line, after "alias": "name = command" defines
+// an alias, "name" shows it, and nothing lists them all. The kit alias
+// subcommands (alias set, alias remove, ...) run as they are.
+func (s *Session) evalAlias(ctx context.Context, rest string) (string, error) {
+	if s.Aliases == nil {
+		return "", fmt.Errorf("aliases are not available")
+	}
+	switch sub, _, _ := strings.Cut(rest, " "); sub {
+	case "list", "set", "remove", "rm":
+		out, err := s.Eval(ctx, "alias "+rest)
+		if reloaded, loadErr := alias.Load(s.Aliases.Path); loadErr == nil {
+			s.Aliases = reloaded
+		}
+		return out, err
+	}
+	name, command, define := strings.Cut(rest, "=")
+	name = strings.TrimSpace(name)
+	if !define {
+		if name == "" {
+			var b strings.Builder
+			for _, n := range s.Aliases.Names() {
+				fmt.Fprintf(&b, "  %s = %s\n", n, s.Aliases.Entries[n])
+			}
+			return b.String(), nil
+		}
+		command, ok := s.Aliases.Entries[name]
+		if !ok {
+			return "", fmt.Errorf("no alias %q", name)
+		}
+		return fmt.Sprintf("%s = %s", name, command), nil
+	}
+
+	for _, known := range s.KnownCommands {
+		if name == known {
+			return "", fmt.Errorf("%s is a command; pick another name", name)
+		}
+	}
+	if err := s.Aliases.Set(name, command); err != nil {
+		return "", err
+	}
+	if err := s.Aliases.Save(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s = %s", name, s.Aliases.Entries[name]), nil
+}
+
+func (s *Session) unalias(name string) error {
+	if s.Aliases == nil {
+		return fmt.Errorf("aliases are not available")
+	}
+	if err := s.Aliases.Remove(name); err != nil {
+		return err
+	}
+	return s.Aliases.Save()
+}
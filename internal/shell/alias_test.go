@@ -0,0 +1,45 @@
+package shell
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klytics/m365kit/internal/alias"
+)
+
+func TestAliasInShell(t *testing.T) {
+	var ran []string
+	DefaultRunner = func(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+		ran = append(ran, strings.Join(args, " "))
+		return nil
+	}
+	defer func() { DefaultRunner = nil }()
+
+	aliases, _ := alias.Load(filepath.Join(t.TempDir(), "aliases.yaml"))
+	s := &Session{Aliases: aliases, KnownCommands: []string{"word", "teams"}}
+	ctx := context.Background()
+
+	if err := s.Exec(ctx, "alias standup = teams post --team Eng --stdin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Exec(ctx, "alias word = version"); err == nil {
+		t.Error("expected an error shadowing a command")
+	}
+	if err := s.Exec(ctx, "word read a.docx | standup --channel general"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "teams post --team Eng --stdin --channel general"; len(ran) != 2 || ran[1] != want {
+		t.Errorf("ran %q, want %q last", ran, want)
+	}
+
+	reloaded, _ := alias.Load(aliases.Path)
+	if reloaded.Entries["standup"] == "" {
+		t.Error("alias was not saved")
+	}
+	if err := s.Exec(ctx, "unalias standup"); err != nil || len(s.Aliases.Names()) != 0 {
+		t.Errorf("unalias: %v, left %v", err, s.Aliases.Names())
+	}
+}
@@ -0,0 +1,256 @@
+package shell
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tab completion goes past command words to the values commands take:
+// team, channel, and site names, template names, and OneDrive paths. Those
+// come from DefaultLister, and are cached for completionTTL so that
+// pressing Tab again doesn't call Microsoft Graph again.
+
+// Kinds of values DefaultLister lists.
+const (
+	ListTeams     = "teams"
+	ListChannels  = "channels" // arg is the team
+	ListSites     = "sites"
+	ListTemplates = "templates"
+	ListOneDrive  = "onedrive" // arg is the folder; folder names end in /
+)
+
+// Lister returns the values of a kind for completion. It is set by the
+// cmd package, like DefaultRunner.
+type Lister func(ctx context.Context, kind, arg string) ([]string, error)
+
+// DefaultLister lists completion values; when it is nil only command words
+// complete.
+var DefaultLister Lister
+
+// completionTTL is how long listed values are reused; lookups that fail
+// are retried sooner.
+const (
+	completionTTL      = 5 * time.Minute
+	completionRetry    = 30 * time.Second
+	completionDeadline = 5 * time.Second
+)
+
+// completionCache holds listed values by kind and argument.
+type completionCache struct {
+	mu      sync.Mutex
+	entries map[string]completionEntry
+	now     func() time.Time
+}
+
+type completionEntry struct {
+	values  []string
+	expires time.Time
+}
+
+// list returns the values of kind for arg, from the cache when it can.
+func (c *completionCache) list(ctx context.Context, kind, arg string) []string {
+	if DefaultLister == nil {
+		return nil
+	}
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	key := kind + "\x00" + arg
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now().Before(e.expires) {
+		return e.values
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, completionDeadline)
+	defer cancel()
+	values, err := DefaultLister(ctx, kind, arg)
+	e = completionEntry{values: values, expires: now().Add(completionTTL)}
+	if err != nil {
+		e = completionEntry{expires: now().Add(completionRetry)}
+	}
+
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]completionEntry)
+	}
+	c.entries[key] = e
+	c.mu.Unlock()
+	return e.values
+}
+
+// Flags whose values complete from live data.
+var valueFlags = map[string]string{
+	"--team":     ListTeams,
+	"--channel":  ListChannels,
+	"--site":     ListSites,
+	"--template": ListTemplates,
+}
+
+// onedrivePathCommands are the onedrive subcommands whose arguments are
+// OneDrive paths.
+var onedrivePathCommands = map[string]bool{
+	"ls": true, "get": true, "share": true, "links": true, "revoke": true, "du": true, "rm": true,
+}
+
+// sharepointSiteCommands are the sharepoint subcommands whose first
+// argument is a site.
+var sharepointSiteCommands = map[string]bool{
+	"libs": true, "ls": true, "get": true, "put": true, "audit": true, "trash": true,
+	"checkout": true, "checkin": true, "discard-checkout": true, "versions": true, "restore-version": true,
+}
+
+// completeValue returns the candidates for cur when it is a value that
+// completes from live data, given the words before it.
+func (s *Session) completeValue(ctx context.Context, words []string, cur string) ([]string, bool) {
+	prefix, partial := "", cur
+	kind := ""
+	if flag, v, ok := strings.Cut(cur, "="); ok && strings.HasPrefix(cur, "--") {
+		kind, prefix, partial = valueFlags[flag], flag+"=", v
+	} else if k, ok := valueFlags[words[len(words)-1]]; ok {
+		kind = k
+	}
+
+	if kind == "" {
+		switch {
+		case strings.HasPrefix(cur, "onedrive:"):
+			kind = ListOneDrive
+		case (words[0] == "use" || words[0] == "set") && len(words) == 2 && words[1] == "team":
+			kind = ListTeams
+		case (words[0] == "use" || words[0] == "set") && len(words) == 2 && words[1] == "site":
+			kind = ListSites
+		case words[0] == "cd" && len(words) == 1:
+			kind = ListOneDrive
+		case words[0] == "template" && len(words) == 2 && (words[1] == "show" || words[1] == "apply" || words[1] == "remove"):
+			kind = ListTemplates
+		case words[0] == "sharepoint" && len(words) == 2 && sharepointSiteCommands[words[1]]:
+			kind = ListSites
+		case words[0] == "onedrive" && len(words) >= 2 && onedrivePathCommands[words[1]] && !strings.HasPrefix(cur, "-"):
+			kind = ListOneDrive
+		}
+	}
+
+	var values []string
+	switch kind {
+	case "":
+		return nil, false
+	case ListChannels:
+		team := s.DefaultTeam
+		for i, w := range words {
+			if w == "--team" && i+1 < len(words) {
+				team = words[i+1]
+			} else if t, ok := strings.CutPrefix(w, "--team="); ok {
+				team = t
+			}
+		}
+		if team == "" {
+			return nil, true
+		}
+		values = s.completions.list(ctx, kind, team)
+	case ListOneDrive:
+		return s.completePath(ctx, partial, words[0] == "cd"), true
+	default:
+		values = s.completions.list(ctx, kind, "")
+	}
+
+	var matches []string
+	for _, v := range values {
+		if strings.HasPrefix(v, partial) {
+			matches = append(matches, prefix+v)
+		}
+	}
+	sort.Strings(matches)
+	return matches, true
+}
+
+// completePath completes a OneDrive path, relative to the current folder
+// unless it starts with / or onedrive:/.
+func (s *Session) completePath(ctx context.Context, partial string, foldersOnly bool) []string {
+	dir, base := "", partial
+	if i := strings.LastIndex(partial, "/"); i >= 0 {
+		dir, base = partial[:i+1], partial[i+1:]
+	} else if p, ok := strings.CutPrefix(partial, "onedrive:"); ok {
+		dir, base = "onedrive:", p
+	}
+
+	var matches []string
+	for _, name := range s.completions.list(ctx, ListOneDrive, JoinFolder(s.Folder, dir)) {
+		if foldersOnly && !strings.HasSuffix(name, "/") {
+			continue
+		}
+		if strings.HasPrefix(name, base) {
+			matches = append(matches, dir+name)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// completionWords splits the last pipeline stage of a partial line into
+// the complete words before the cursor and the word being typed (cur),
+// unquoted, and returns how long cur is as typed.
+func completionWords(line string) (words []string, cur string, typed int) {
+	var (
+		b     strings.Builder
+		quote rune
+		start = 0
+	)
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && quote != '\'' && i+1 < len(runes):
+			i++
+			b.WriteRune(runes[i])
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '|':
+			words, start = nil, i+1
+			b.Reset()
+		case r == ' ' || r == '\t':
+			if b.Len() > 0 {
+				words = append(words, b.String())
+				b.Reset()
+			}
+			start = i + 1
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return words, b.String(), len(runes) - start
+}
+
+// completer adapts Complete to readline.
+type completer struct {
+	s *Session
+}
+
+// Do returns the text to add after the word at pos for each candidate,
+// and the length of the word as typed.
+func (c completer) Do(line []rune, pos int) ([][]rune, int) {
+	_, cur, typed := completionWords(string(line[:pos]))
+	var out [][]rune
+	for _, cand := range c.s.Complete(string(line[:pos])) {
+		if !strings.HasPrefix(cand, cur) {
+			continue
+		}
+		rest := escapeArg(cand[len(cur):])
+		if !strings.HasSuffix(cand, "/") && !strings.HasSuffix(cand, "=") {
+			rest += " "
+		}
+		out = append(out, []rune(rest))
+	}
+	return out, typed
+}
@@ -0,0 +1,80 @@
+package shell
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompleteLiveValues(t *testing.T) {
+	calls := map[string]int{}
+	DefaultLister = func(ctx context.Context, kind, arg string) ([]string, error) {
+		calls[kind+" "+arg]++
+		switch kind {
+		case ListTeams:
+			return []string{"Engineering", "Marketing Team", "Legal"}, nil
+		case ListChannels:
+			if arg == "Legal" {
+				return []string{"general", "contracts"}, nil
+			}
+		case ListSites:
+			return []string{"Marketing", "Legal"}, nil
+		case ListTemplates:
+			return []string{"nda", "invoice"}, nil
+		case ListOneDrive:
+			if arg == "/Projects" {
+				return []string{"Q3/", "Report.docx", "Roadmap/"}, nil
+			}
+		}
+		return nil, nil
+	}
+	defer func() { DefaultLister = nil }()
+
+	s := &Session{Folder: "/Projects"}
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"teams post --team E", []string{"Engineering"}},
+		{"teams post --team=M", []string{"--team=Marketing Team"}},
+		{"teams post --team Legal --channel c", []string{"contracts"}},
+		{"teams post --channel g", nil}, // no team to list channels of
+		{"use site ", []string{"Legal", "Marketing"}},
+		{"sharepoint ls L", []string{"Legal"}},
+		{"template apply ", []string{"invoice", "nda"}},
+		{"onedrive get R", []string{"Report.docx", "Roadmap/"}},
+		{"cd R", []string{"Roadmap/"}},
+		{"word read onedrive:/Projects/Q", []string{"onedrive:/Projects/Q3/"}},
+		{"word read a.docx | teams post --team L", []string{"Legal"}},
+		{"word re", []string{"read"}},
+	}
+	for _, tt := range tests {
+		if got := s.Complete(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+	if n := calls[ListTeams+" "]; n != 1 {
+		t.Errorf("teams listed %d times, want 1 (cached)", n)
+	}
+}
+
+func TestCompleterDo(t *testing.T) {
+	DefaultLister = func(ctx context.Context, kind, arg string) ([]string, error) {
+		return []string{"Marketing Team", "Reports/"}, nil
+	}
+	defer func() { DefaultLister = nil }()
+
+	s := &Session{}
+	line := []rune(`use team Mar`)
+	got, n := completer{s}.Do(line, len(line))
+	if n != 3 || len(got) != 1 || string(got[0]) != `keting\ Team ` {
+		t.Errorf("Do = %q, %d", got, n)
+	}
+
+	line = []rune(`onedrive ls Rep`)
+	got, _ = completer{s}.Do(line, len(line))
+	if len(got) != 1 || !strings.HasSuffix(string(got[0]), "/") {
+		t.Errorf("folders should complete without a trailing space: %q", got)
+	}
+}
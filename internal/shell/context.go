@@ -0,0 +1,130 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// The session context — default team and site ("use team Engineering",
+// "use site Marketing") and current OneDrive folder ("cd
+// onedrive:/Projects") — is saved to ContextFile, so the next session
+// starts where this one left off. The kit runner fills them in for
+// commands that leave out --team, --site, or a site argument, and resolves
+// relative OneDrive paths against the folder.
+
+// sessionContext is the saved form of the session context.
+type sessionContext struct {
+	Site   string `json:"site,omitempty"`
+	Team   string `json:"team,omitempty"`
+	Folder string `json:"folder,omitempty"`
+}
+
+// LoadContext reads the saved session context from ContextFile. A missing
+// file means none.
+func (s *Session) LoadContext() error {
+	data, err := os.ReadFile(s.ContextFile)
+	if os.IsNotExist(err) || s.ContextFile == "" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var c sessionContext
+	if err := json.Unmarshal(data, &c); err != nil {
+		return fmt.Errorf("invalid shell context file %s: %w", s.ContextFile, err)
+	}
+	s.DefaultSite, s.DefaultTeam, s.Folder = c.Site, c.Team, c.Folder
+	return nil
+}
+
+func (s *Session) saveContext() error {
+	if s.ContextFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(sessionContext{Site: s.DefaultSite, Team: s.DefaultTeam, Folder: s.Folder}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.ContextFile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.ContextFile, data, 0644)
+}
+
+// evalUse runs a "use" line, after "use ": "team <name>" or "site
+// <name>" sets the default, "team" or "site" alone clears it, and nothing
+// shows the current context.
+func (s *Session) evalUse(rest string) (string, error) {
+	kind, value, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	switch kind {
+	case "":
+		return s.describeContext(), nil
+	case "team":
+		s.DefaultTeam = value
+	case "site":
+		s.DefaultSite = value
+	default:
+		return "", fmt.Errorf("usage: use team <name>, use site <name>, or use (to show them)")
+	}
+	if err := s.saveContext(); err != nil {
+		return "", err
+	}
+	if value == "" {
+		return fmt.Sprintf("No default %s", kind), nil
+	}
+	return fmt.Sprintf("Using %s %s", kind, value), nil
+}
+
+// evalCd changes the current OneDrive folder: an absolute path
+// (onedrive:/Projects or /Projects), one relative to the current folder,
+// or ".."; nothing goes back to the root.
+func (s *Session) evalCd(arg string) (string, error) {
+	arg = strings.Trim(strings.TrimSpace(arg), `"'`)
+	if strings.HasPrefix(arg, "sp:") {
+		return "", fmt.Errorf("cd works with OneDrive folders; use 'use site <name>' for SharePoint")
+	}
+	folder := JoinFolder(s.Folder, arg)
+	if arg == "" {
+		folder = "/"
+	}
+	if folder == "/" {
+		folder = ""
+	}
+	s.Folder = folder
+	if err := s.saveContext(); err != nil {
+		return "", err
+	}
+	return "onedrive:" + s.folderOrRoot(), nil
+}
+
+func (s *Session) folderOrRoot() string {
+	if s.Folder == "" {
+		return "/"
+	}
+	return s.Folder
+}
+
+func (s *Session) describeContext() string {
+	show := func(v string) string {
+		if v == "" {
+			return "(none)"
+		}
+		return v
+	}
+	return fmt.Sprintf("Team:   %s\nSite:   %s\nFolder: onedrive:%s", show(s.DefaultTeam), show(s.DefaultSite), s.folderOrRoot())
+}
+
+// JoinFolder resolves the OneDrive path p against folder. Paths starting
+// with / or onedrive: are already absolute.
+func JoinFolder(folder, p string) string {
+	p = strings.TrimPrefix(p, "onedrive:")
+	if !strings.HasPrefix(p, "/") {
+		p = path.Join("/", folder, p)
+	}
+	return path.Clean(p)
+}
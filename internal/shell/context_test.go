@@ -0,0 +1,51 @@
+package shell
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestUseAndCd(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "context.json")
+	s := &Session{ContextFile: file}
+
+	if _, err := s.evalUse(" team Engineering"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.evalUse(` site "Marketing Hub"`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.evalUse(" channel general"); err == nil {
+		t.Error("expected an error for an unknown default")
+	}
+	for _, step := range []struct{ arg, want string }{
+		{" onedrive:/Projects", "/Projects"},
+		{" Q3/reports", "/Projects/Q3/reports"},
+		{" ..", "/Projects/Q3"},
+		{" /Archive", "/Archive"},
+		{"", ""},
+	} {
+		if _, err := s.evalCd(step.arg); err != nil {
+			t.Fatal(err)
+		}
+		if s.Folder != step.want {
+			t.Errorf("cd%s: folder = %q, want %q", step.arg, s.Folder, step.want)
+		}
+	}
+	if _, err := s.evalCd(" sp:Marketing/Documents"); err == nil {
+		t.Error("expected an error for a SharePoint folder")
+	}
+	s.evalCd(" /Projects")
+
+	next := &Session{ContextFile: file}
+	if err := next.LoadContext(); err != nil {
+		t.Fatal(err)
+	}
+	if next.DefaultTeam != "Engineering" || next.DefaultSite != "Marketing Hub" || next.Folder != "/Projects" {
+		t.Errorf("loaded %+v", next)
+	}
+
+	if _, err := next.evalUse(" team"); err != nil || next.DefaultTeam != "" {
+		t.Errorf("clearing the team: %v, %q", err, next.DefaultTeam)
+	}
+}
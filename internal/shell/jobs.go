@@ -0,0 +1,260 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A line ending in & runs in the background as a job, so the prompt comes
+// back at once. Each job is its own kit process, since commands write to
+// the process's stdout; the session's variables are expanded first, and
+// its defaults are passed on. "jobs" lists the jobs, "wait" waits for them
+// and shows their output, and the REPL says when one finishes.
+
+// JobCommand returns the command that runs line in the background with
+// the defaults d. It is set by the cmd package, like DefaultRunner.
+var JobCommand func(line string, d Defaults) *exec.Cmd
+
+// Job is a line running in the background.
+type Job struct {
+	ID      int
+	Line    string
+	Started time.Time
+	Ended   time.Time
+
+	cmd    *exec.Cmd
+	to     *redirect
+	output lockedBuffer
+	errOut lockedBuffer
+	done   chan struct{}
+	err    error
+	shown  bool // its output has been shown (or written to its file)
+	waited bool // wait has reported how it ended
+}
+
+// lockedBuffer is a bytes.Buffer a job's process writes to while the
+// shell reads it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Running reports whether the job hasn't finished.
+func (j *Job) Running() bool {
+	select {
+	case <-j.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// state describes the job for jobs and notifications.
+func (j *Job) state() string {
+	switch {
+	case j.Running():
+		return "Running " + formatDuration(time.Since(j.Started))
+	case j.err != nil:
+		return "Failed"
+	default:
+		return "Done"
+	}
+}
+
+func (j *Job) String() string {
+	return fmt.Sprintf("[%d] %-12s %s", j.ID, j.state(), j.Line)
+}
+
+// cutBackground reports whether line ends in an unquoted, unescaped &,
+// and returns it without it.
+func cutBackground(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && quote != '\'' && i+1 < len(line):
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '&' && i == len(line)-1:
+			return strings.TrimSpace(line[:i]), true
+		}
+	}
+	return line, false
+}
+
+// startJob runs line in the background and returns the job.
+func (s *Session) startJob(ctx context.Context, line string) (*Job, error) {
+	if JobCommand == nil {
+		return nil, fmt.Errorf("background jobs are not available")
+	}
+	line, to, err := s.redirection(ctx, line)
+	if err != nil {
+		return nil, err
+	}
+	if word, _, _ := strings.Cut(line, " "); builtins[word] {
+		return nil, fmt.Errorf("only kit commands can run in the background, not %s", word)
+	}
+	expanded, err := s.expand(ctx, line)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := splitLine(expanded); err != nil {
+		return nil, err
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	j := &Job{
+		ID:      len(s.jobs) + 1,
+		Line:    line,
+		Started: time.Now(),
+		to:      to,
+		done:    make(chan struct{}),
+		cmd:     JobCommand(expanded, Defaults{Site: s.DefaultSite, Team: s.DefaultTeam, Folder: s.Folder}),
+	}
+	j.cmd.Stdout = &j.output
+	j.cmd.Stderr = &j.errOut
+	if err := j.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start job: %w", err)
+	}
+	s.jobs = append(s.jobs, j)
+
+	go func() {
+		err := j.cmd.Wait()
+		if err != nil {
+			if msg := strings.TrimSpace(j.errOut.String()); msg != "" {
+				err = fmt.Errorf("%s", strings.TrimPrefix(msg, "Error: "))
+			}
+		}
+		if err == nil && j.to != nil {
+			err = j.to.write(j.output.String())
+			j.shown = true
+		}
+		j.err, j.Ended = err, time.Now()
+		msg := fmt.Sprintf("[%d] %-12s %s", j.ID, "Done", j.Line)
+		if err != nil {
+			msg = fmt.Sprintf("[%d] %-12s %s", j.ID, "Failed", j.Line)
+		}
+		if !j.shown && j.output.String() != "" {
+			msg += fmt.Sprintf(" ('wait %d' shows its output)", j.ID)
+		}
+		close(j.done)
+		if s.notify != nil {
+			s.notify(msg)
+		}
+	}()
+	return j, nil
+}
+
+// Jobs returns the session's jobs, oldest first.
+func (s *Session) Jobs() []*Job {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	return append([]*Job(nil), s.jobs...)
+}
+
+// RunningJobs returns the jobs that haven't finished.
+func (s *Session) RunningJobs() []*Job {
+	var running []*Job
+	for _, j := range s.Jobs() {
+		if j.Running() {
+			running = append(running, j)
+		}
+	}
+	return running
+}
+
+// StopJobs kills the jobs that are still running.
+func (s *Session) StopJobs() {
+	for _, j := range s.RunningJobs() {
+		j.cmd.Process.Kill()
+		<-j.done
+	}
+}
+
+// evalJobs runs a "jobs" line: it lists the jobs.
+func (s *Session) evalJobs() string {
+	var b strings.Builder
+	for _, j := range s.Jobs() {
+		fmt.Fprintln(&b, j)
+	}
+	return b.String()
+}
+
+// evalWait runs a "wait" line, after "wait": it waits for the given jobs,
+// or all those it hasn't waited for, and returns the output they made that
+// hasn't been shown. It fails if any of them failed.
+func (s *Session) evalWait(ctx context.Context, rest string) (string, error) {
+	var jobs []*Job
+	if fields := strings.Fields(rest); len(fields) > 0 {
+		all := s.Jobs()
+		for _, f := range fields {
+			id, err := strconv.Atoi(strings.TrimPrefix(f, "%"))
+			if err != nil || id < 1 || id > len(all) {
+				return "", fmt.Errorf("no job %s (see 'jobs')", f)
+			}
+			jobs = append(jobs, all[id-1])
+		}
+	} else {
+		for _, j := range s.Jobs() {
+			if !j.waited {
+				jobs = append(jobs, j)
+			}
+		}
+	}
+
+	var (
+		b      strings.Builder
+		failed []string
+	)
+	for _, j := range jobs {
+		select {
+		case <-j.done:
+		case <-ctx.Done():
+			return b.String(), ctx.Err()
+		}
+		j.waited = true
+		if j.err != nil {
+			failed = append(failed, fmt.Sprintf("[%d] %s", j.ID, j.err))
+		}
+		if !j.shown {
+			j.shown = true
+			if out := j.output.String(); out != "" {
+				fmt.Fprintf(&b, "[%d] %s\n", j.ID, j.Line)
+				if IsBinary([]byte(out)) {
+					fmt.Fprintf(&b, "(%d bytes of binary output not shown; use > file &)\n", len(out))
+				} else {
+					b.WriteString(out)
+				}
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return b.String(), fmt.Errorf("%s", strings.Join(failed, "\n"))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,91 @@
+package shell
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCutBackground(t *testing.T) {
+	tests := []struct {
+		line, want string
+		bg         bool
+	}{
+		{"onedrive get big.zip &", "onedrive get big.zip", true},
+		{"onedrive get big.zip&", "onedrive get big.zip", true},
+		{`teams post --message "R&D"`, `teams post --message "R&D"`, false},
+		{`teams post --message "a &"`, `teams post --message "a &"`, false},
+		{`echo R\&`, `echo R\&`, false},
+		{"teams list", "teams list", false},
+	}
+	for _, tt := range tests {
+		got, bg := cutBackground(tt.line)
+		if got != tt.want || bg != tt.bg {
+			t.Errorf("cutBackground(%q) = %q, %v", tt.line, got, bg)
+		}
+	}
+}
+
+func TestJobs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	// Each job echoes its line and team, and fails for "fail"
+	JobCommand = func(line string, d Defaults) *exec.Cmd {
+		return exec.Command("sh", "-c", `echo "$0 team=$1"; [ "$0" != fail ] || { echo "Error: it failed" >&2; exit 1; }`, line, d.Team)
+	}
+	defer func() { JobCommand = nil }()
+
+	dir := t.TempDir()
+	notified := make(chan string, 3)
+	s := &Session{
+		DefaultTeam: "Eng",
+		Vars:        map[string]string{"dir": dir, "file": "big.zip"},
+		notify:      func(msg string) { notified <- msg },
+	}
+	ctx := context.Background()
+
+	for _, line := range []string{"onedrive get $file &", "fail &", "teams list > $dir/out.txt &"} {
+		if err := s.Exec(ctx, line); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+	}
+	if err := s.Exec(ctx, "set x = 1 &"); err == nil {
+		t.Error("expected an error running a builtin in the background")
+	}
+
+	out, err := s.evalWait(ctx, "")
+	if err == nil || !strings.Contains(err.Error(), "[2] it failed") {
+		t.Errorf("wait error = %v", err)
+	}
+	if !strings.Contains(out, "[1] onedrive get $file\nonedrive get big.zip team=Eng\n") || strings.Contains(out, "teams list") {
+		t.Errorf("wait output = %q", out)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "out.txt")); string(data) != "teams list team=Eng\n" {
+		t.Errorf("redirected job wrote %q", data)
+	}
+
+	// wait again has nothing left to report
+	if out, err := s.evalWait(ctx, ""); out != "" || err != nil {
+		t.Errorf("second wait = %q, %v", out, err)
+	}
+	if _, err := s.evalWait(ctx, "7"); err == nil {
+		t.Error("expected an error waiting for a missing job")
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-notified:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no notification")
+		}
+	}
+	if listing := s.evalJobs(); !strings.Contains(listing, "[2] Failed") || !strings.Contains(listing, "[1] Done") {
+		t.Errorf("jobs = %q", listing)
+	}
+}
@@ -0,0 +1,168 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Stdio is how Eval connects a command to the others in a pipeline
+// (word read a.docx | ai summarize). Commands read os.Stdin and write
+// os.Stdout, so the runner swaps them for the command (see Redirect).
+type Stdio struct {
+	Stdin   []byte // the previous command's output; nil reads the terminal
+	Capture bool   // the output goes to the next command, not the terminal
+}
+
+type stdioKey struct{}
+
+// WithStdio returns a context carrying the pipeline connections s.
+func WithStdio(ctx context.Context, s Stdio) context.Context {
+	return context.WithValue(ctx, stdioKey{}, s)
+}
+
+// StdioFrom returns the pipeline connections in ctx; outside a pipeline
+// they are zero.
+func StdioFrom(ctx context.Context) Stdio {
+	s, _ := ctx.Value(stdioKey{}).(Stdio)
+	return s
+}
+
+// Redirect points os.Stdin at s.Stdin and, with s.Capture, os.Stdout at
+// stdout, until restore is called. Piped input goes through a temporary
+// file, so commands see a regular file rather than a terminal and binary
+// data (like a .docx) passes through unchanged.
+func Redirect(s Stdio, stdout io.Writer) (restore func(), err error) {
+	var undo []func()
+	restore = func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+
+	if s.Stdin != nil {
+		f, err := os.CreateTemp("", "kit-pipe-*")
+		if err != nil {
+			return restore, fmt.Errorf("could not create pipe: %w", err)
+		}
+		undo = append(undo, func() { f.Close(); os.Remove(f.Name()) })
+		if _, err := f.Write(s.Stdin); err != nil {
+			restore()
+			return func() {}, fmt.Errorf("could not write pipe: %w", err)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			restore()
+			return func() {}, err
+		}
+		old := os.Stdin
+		os.Stdin = f
+		undo = append(undo, func() { os.Stdin = old })
+	}
+
+	if s.Capture {
+		r, wr, err := os.Pipe()
+		if err != nil {
+			restore()
+			return func() {}, fmt.Errorf("could not create pipe: %w", err)
+		}
+		copied := make(chan struct{})
+		go func() {
+			io.Copy(stdout, r)
+			r.Close()
+			close(copied)
+		}()
+		old := os.Stdout
+		os.Stdout = wr
+		undo = append(undo, func() {
+			os.Stdout = old
+			wr.Close()
+			<-copied
+		})
+	}
+	return restore, nil
+}
+
+// IsBinary reports whether data looks like binary output (a document
+// rather than text), which the shell doesn't print to the terminal.
+func IsBinary(data []byte) bool {
+	sample := data[:min(len(data), 8000)]
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+	if len(sample) < len(data) {
+		// Don't count a character cut off by the sample
+		for i := 0; i < utf8.UTFMax-1 && len(sample) > 0 && !utf8.Valid(sample); i++ {
+			sample = sample[:len(sample)-1]
+		}
+	}
+	return !utf8.Valid(sample)
+}
+
+// escapable are the characters a backslash escapes outside single
+// quotes; before anything else it is kept, so Windows paths work.
+const escapable = "\\\"' |\t$>&"
+
+// splitLine splits a command line into the argument lists of its
+// pipeline stages. Arguments may be quoted with single or double quotes,
+// and an unquoted | separates stages.
+func splitLine(line string) ([][]string, error) {
+	var (
+		stages [][]string
+		args   []string
+		cur    strings.Builder
+		inArg  bool
+		quote  rune
+	)
+	endArg := func() {
+		if inArg {
+			args = append(args, cur.String())
+			cur.Reset()
+			inArg = false
+		}
+	}
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && quote != '\'' && i+1 < len(runes) && strings.ContainsRune(escapable, runes[i+1]):
+			i++
+			cur.WriteRune(runes[i])
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '|':
+			endArg()
+			if len(args) == 0 {
+				return nil, fmt.Errorf("empty command in pipeline")
+			}
+			stages = append(stages, args)
+			args = nil
+		case r == ' ' || r == '\t':
+			endArg()
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	endArg()
+	if len(args) == 0 {
+		if len(stages) > 0 {
+			return nil, fmt.Errorf("empty command in pipeline")
+		}
+		return nil, nil
+	}
+	return append(stages, args), nil
+}
@@ -0,0 +1,108 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    [][]string
+		wantErr bool
+	}{
+		{"word read a.docx", [][]string{{"word", "read", "a.docx"}}, false},
+		{"word read a.docx | ai summarize|teams post --stdin", [][]string{{"word", "read", "a.docx"}, {"ai", "summarize"}, {"teams", "post", "--stdin"}}, false},
+		{`teams post --message "hello | world" --team 'R&D'`, [][]string{{"teams", "post", "--message", "hello | world", "--team", "R&D"}}, false},
+		{`word read C:\docs\a.docx`, [][]string{{"word", "read", `C:\docs\a.docx`}}, false},
+		{`word read my\ file.docx`, [][]string{{"word", "read", "my file.docx"}}, false},
+		{`teams post --message ""`, [][]string{{"teams", "post", "--message", ""}}, false},
+		{"   ", nil, false},
+		{"word read | ", nil, true},
+		{"| ai summarize", nil, true},
+		{`teams post --message "oops`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := splitLine(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitLine(%q) error = %v", tt.line, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestEvalPipeline(t *testing.T) {
+	var stdins []Stdio
+	DefaultRunner = func(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+		in := StdioFrom(ctx)
+		stdins = append(stdins, in)
+		switch args[0] {
+		case "word":
+			fmt.Fprint(stdout, "contract text")
+		case "upper":
+			stdout.Write(bytes.ToUpper(in.Stdin))
+		case "fail":
+			fmt.Fprint(stderr, "no input")
+			return fmt.Errorf("exit 1")
+		}
+		return nil
+	}
+	defer func() { DefaultRunner = nil }()
+
+	s, _ := NewSession()
+	out, err := s.Eval(context.Background(), "word read a.docx | upper | upper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "CONTRACT TEXT" || s.LastOutput != out {
+		t.Errorf("output = %q, last = %q", out, s.LastOutput)
+	}
+	if len(stdins) != 3 || stdins[0].Stdin != nil || !stdins[0].Capture || string(stdins[1].Stdin) != "contract text" || stdins[2].Capture {
+		t.Errorf("stages got %+v", stdins)
+	}
+
+	if _, err := s.Eval(context.Background(), "word read a.docx | fail | upper"); err == nil || err.Error() != "fail: no input" {
+		t.Errorf("err = %v, want the failing stage's error", err)
+	}
+}
+
+func TestRedirect(t *testing.T) {
+	var out bytes.Buffer
+	doc := []byte("PK\x03\x04\x00binary")
+	restore, err := Redirect(Stdio{Stdin: doc, Capture: true}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(os.Stdin)
+	fmt.Fprint(os.Stdout, "captured")
+	restore()
+
+	if !bytes.Equal(data, doc) {
+		t.Errorf("stdin = %q", data)
+	}
+	if out.String() != "captured" {
+		t.Errorf("stdout = %q", out.String())
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	if IsBinary([]byte("plain text — with UTF-8\n")) {
+		t.Error("text reported as binary")
+	}
+	if !IsBinary([]byte("PK\x03\x04\x00\x00")) {
+		t.Error("zip data reported as text")
+	}
+	long := []byte(strings.Repeat("a", 7999) + "é and more")
+	if IsBinary(long) {
+		t.Error("text cut mid-character reported as binary")
+	}
+}
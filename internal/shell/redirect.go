@@ -0,0 +1,163 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chzyer/readline"
+
+	"github.com/klytics/m365kit/internal/output"
+)
+
+// A line may end in "> file" to write its output to file instead of the
+// terminal, or ">> file" to append to it. Output that doesn't fit on the
+// screen goes through $PAGER when the session pages (see Session.Paging).
+
+// redirect is where a line's output goes when it isn't the terminal.
+type redirect struct {
+	path     string
+	appendTo bool
+}
+
+// cutRedirect splits a trailing "> file" or ">> file" off line. A > inside
+// quotes, escaped with \, or inside $(...) doesn't count.
+func cutRedirect(line string) (string, *redirect, string, error) {
+	var (
+		quote byte
+		depth int
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && quote != '\'' && i+1 < len(line):
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '$' && i+1 < len(line) && line[i+1] == '(':
+			depth++
+			i++
+		case c == '(' && depth > 0:
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == '>' && depth == 0:
+			r := &redirect{}
+			target := line[i+1:]
+			if strings.HasPrefix(target, ">") {
+				r.appendTo, target = true, target[1:]
+			}
+			target = strings.TrimSpace(target)
+			if target == "" {
+				return "", nil, "", fmt.Errorf("> needs a file name")
+			}
+			if strings.ContainsAny(target, ">|") {
+				return "", nil, "", fmt.Errorf("> must come last, with one file name")
+			}
+			return strings.TrimSpace(line[:i]), r, target, nil
+		}
+	}
+	return line, nil, "", nil
+}
+
+// redirection cuts a redirect off line and expands its file name.
+func (s *Session) redirection(ctx context.Context, line string) (string, *redirect, error) {
+	line, r, target, err := cutRedirect(line)
+	if err != nil || r == nil {
+		return line, nil, err
+	}
+	expanded, err := s.expand(ctx, target)
+	if err != nil {
+		return "", nil, err
+	}
+	args, err := splitLine(expanded)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(args) != 1 || len(args[0]) != 1 {
+		return "", nil, fmt.Errorf("> needs one file name (quote names with spaces)")
+	}
+	r.path = args[0][0]
+	return line, r, nil
+}
+
+// write writes out to the file, creating it if needed.
+func (r *redirect) write(out string) error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if r.appendTo {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(r.path, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(out); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// show writes a line's output: to the redirect when there is one, else to
+// the terminal, through the pager when it is longer than the screen.
+// Binary output only goes to files.
+func (s *Session) show(out string, r *redirect) error {
+	binary := IsBinary([]byte(out))
+	if out != "" && !binary && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	switch {
+	case r != nil:
+		return r.write(out)
+	case binary:
+		fmt.Printf("(%d bytes of binary output not shown; use > file to save it)\n", len(out))
+	case s.Paging && output.ShouldPage(out, screenHeight()-1):
+		if err := output.Page(out); err != nil {
+			fmt.Print(out)
+		}
+	default:
+		fmt.Print(out)
+	}
+	return nil
+}
+
+// screenHeight returns the terminal's height in lines, or a large number
+// when it is unknown, so nothing is paged.
+func screenHeight() int {
+	_, h, err := readline.GetSize(int(os.Stdout.Fd()))
+	if err != nil || h <= 0 {
+		return 1 << 30
+	}
+	return h
+}
+
+// evalLast runs a "last" line, after "last": the last command's output,
+// or with --save <file>, saves it there.
+func (s *Session) evalLast(ctx context.Context, rest string) (string, *redirect, error) {
+	expanded, err := s.expand(ctx, rest)
+	if err != nil {
+		return "", nil, err
+	}
+	args, err := splitLine(expanded)
+	if err != nil {
+		return "", nil, err
+	}
+	var words []string
+	if len(args) == 1 {
+		words = args[0]
+	}
+	switch {
+	case len(args) == 0:
+		return s.LastOutput, nil, nil
+	case len(args) == 1 && len(words) == 2 && words[0] == "--save":
+		return s.LastOutput, &redirect{path: words[1]}, nil
+	case len(args) == 1 && len(words) == 1 && strings.HasPrefix(words[0], "--save="):
+		return s.LastOutput, &redirect{path: strings.TrimPrefix(words[0], "--save=")}, nil
+	}
+	return "", nil, fmt.Errorf("usage: last [--save <file>]")
+}
@@ -0,0 +1,76 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCutRedirect(t *testing.T) {
+	tests := []struct {
+		line, cmd, target string
+		appendTo, found   bool
+		wantErr           bool
+	}{
+		{"outlook inbox --json > inbox.json", "outlook inbox --json", "inbox.json", false, true, false},
+		{"word read a.docx | ai summarize >>notes.txt", "word read a.docx | ai summarize", "notes.txt", true, true, false},
+		{`teams post --message "a > b"`, `teams post --message "a > b"`, "", false, false, false},
+		{`teams post --message a\>b`, `teams post --message a\>b`, "", false, false, false},
+		{"echo $(word read a.docx > x) > out", "echo $(word read a.docx > x)", "out", false, true, false},
+		{"outlook inbox >", "", "", false, false, true},
+		{"outlook inbox > a | ai summarize", "", "", false, false, true},
+	}
+	for _, tt := range tests {
+		cmd, r, target, err := cutRedirect(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("cutRedirect(%q) error = %v", tt.line, err)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if cmd != tt.cmd || target != tt.target || (r != nil) != tt.found || (r != nil && r.appendTo != tt.appendTo) {
+			t.Errorf("cutRedirect(%q) = %q, %+v, %q", tt.line, cmd, r, target)
+		}
+	}
+}
+
+func TestExecRedirect(t *testing.T) {
+	DefaultRunner = func(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+		fmt.Fprintf(stdout, "%s", args[len(args)-1])
+		return nil
+	}
+	defer func() { DefaultRunner = nil }()
+
+	dir := t.TempDir()
+	s := &Session{Vars: map[string]string{"dir": dir}}
+	ctx := context.Background()
+	file := filepath.Join(dir, "out.txt")
+
+	for _, line := range []string{"say one > $dir/out.txt", "say two >> ${dir}/out.txt", `echo "three > four" >> $dir/out.txt`} {
+		if err := s.Exec(ctx, line); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+	}
+	data, _ := os.ReadFile(file)
+	if string(data) != "one\ntwo\nthree > four\n" {
+		t.Errorf("file = %q", data)
+	}
+
+	if err := s.Exec(ctx, "say five"); err != nil {
+		t.Fatal(err)
+	}
+	saved := filepath.Join(dir, "last.json")
+	if err := s.Exec(ctx, "last --save "+saved); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(saved); string(data) != "five\n" {
+		t.Errorf("last --save wrote %q", data)
+	}
+	if err := s.Exec(ctx, "last --keep"); err == nil {
+		t.Error("expected a usage error for last --keep")
+	}
+}
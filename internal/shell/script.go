@@ -0,0 +1,198 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A .kit script is a file of shell lines run in order, as if typed at
+// the kit> prompt. Lines starting with # are comments (so a #! line makes
+// the file executable), and a line ending in \ continues on the next.
+// Besides the shell commands, scripts have:
+//
+//	if <line>      run the block when <line> succeeds ("if ! <line>": fails)
+//	else
+//	end
+//	exit [status]  stop the script
+//
+// and $? is 0 or 1 for whether the last line succeeded. A script waits for
+// its background jobs before it ends.
+
+// ScriptOptions control RunScript.
+type ScriptOptions struct {
+	FailFast bool // stop at the first line that fails
+}
+
+// scriptLine is a line of a script, with its line number in the file.
+type scriptLine struct {
+	n    int
+	text string
+}
+
+// ExitError is returned by RunScript for "exit <status>" with a status
+// other than 0.
+type ExitError struct {
+	Status int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("script exited with status %d", e.Status)
+}
+
+// RunScript runs the script read from r; name is used in messages. Lines
+// that fail are reported on stderr with their line number; without
+// FailFast the script carries on, and RunScript returns an error at the
+// end if any line failed. Conditions of if lines don't count as failures.
+func (s *Session) RunScript(ctx context.Context, r io.Reader, name string, opts ScriptOptions) error {
+	if DefaultRunner == nil {
+		return fmt.Errorf("shell runner not configured")
+	}
+	lines, err := readScript(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := checkBlocks(lines); err != nil {
+		return fmt.Errorf("%s:%w", name, err)
+	}
+
+	// Each open if block: whether its lines run, and whether its
+	// condition held (for else)
+	type block struct{ running, taken, outer bool }
+	var (
+		blocks []block
+		failed int
+	)
+	running := func() bool { return len(blocks) == 0 || blocks[len(blocks)-1].running }
+
+	for _, l := range lines {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		word, rest, _ := strings.Cut(l.text, " ")
+		rest = strings.TrimSpace(rest)
+		switch word {
+		case "if":
+			b := block{outer: running()}
+			if b.outer {
+				cond, negate := rest, false
+				if c, ok := strings.CutPrefix(rest, "!"); ok {
+					cond, negate = strings.TrimSpace(c), true
+				}
+				err := s.Exec(ctx, cond)
+				b.taken = (err == nil) != negate
+				b.running = b.taken
+			}
+			blocks = append(blocks, b)
+			continue
+		case "else":
+			b := &blocks[len(blocks)-1]
+			b.running = b.outer && !b.taken
+			continue
+		case "end":
+			blocks = blocks[:len(blocks)-1]
+			continue
+		}
+		if !running() {
+			continue
+		}
+
+		if word == "exit" {
+			status := 0
+			if rest != "" {
+				expanded, err := s.expand(ctx, rest)
+				if err == nil {
+					status, err = strconv.Atoi(expanded)
+				}
+				if err != nil {
+					return fmt.Errorf("%s:%d: exit needs a number", name, l.n)
+				}
+			}
+			if status != 0 {
+				return &ExitError{Status: status}
+			}
+			return nil
+		}
+
+		if err := s.Exec(ctx, l.text); err != nil {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s\n", name, l.n, err)
+			failed++
+			if opts.FailFast {
+				return fmt.Errorf("%s:%d: stopped at the first failure (--fail-fast)", name, l.n)
+			}
+		}
+	}
+	if len(s.RunningJobs()) > 0 {
+		if err := s.Exec(ctx, "wait"); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s: %d line(s) failed", name, failed)
+	}
+	return nil
+}
+
+// readScript reads the lines of a script, dropping blank lines and
+// comments and joining continued lines.
+func readScript(r io.Reader) ([]scriptLine, error) {
+	var (
+		lines []scriptLine
+		cont  *scriptLine
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		text := strings.TrimSpace(sc.Text())
+		if cont == nil && (text == "" || strings.HasPrefix(text, "#")) {
+			continue
+		}
+		more := strings.HasSuffix(text, `\`)
+		text = strings.TrimSuffix(text, `\`)
+		if cont != nil {
+			cont.text = strings.TrimSpace(cont.text + " " + text)
+		} else {
+			cont = &scriptLine{n: n, text: text}
+		}
+		if !more {
+			lines = append(lines, *cont)
+			cont = nil
+		}
+	}
+	if cont != nil {
+		lines = append(lines, *cont)
+	}
+	return lines, sc.Err()
+}
+
+// checkBlocks reports an else or end without an if, or an if without an
+// end, before anything runs.
+func checkBlocks(lines []scriptLine) error {
+	var open []scriptLine
+	for _, l := range lines {
+		word, rest, _ := strings.Cut(l.text, " ")
+		switch word {
+		case "if":
+			if strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), "!")) == "" {
+				return fmt.Errorf("%d: if needs a command", l.n)
+			}
+			open = append(open, l)
+		case "else", "end":
+			if len(open) == 0 {
+				return fmt.Errorf("%d: %s without if", l.n, word)
+			}
+			if word == "end" {
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%d: if without end", open[len(open)-1].n)
+	}
+	return nil
+}
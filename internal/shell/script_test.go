@@ -0,0 +1,81 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// scriptSession returns a session whose runner records each command and
+// fails the ones named "fail".
+func scriptSession(t *testing.T) (*Session, *[]string) {
+	t.Helper()
+	var ran []string
+	DefaultRunner = func(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+		ran = append(ran, strings.Join(args, " "))
+		if args[0] == "fail" {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	}
+	t.Cleanup(func() { DefaultRunner = nil })
+	return &Session{}, &ran
+}
+
+func TestRunScript(t *testing.T) {
+	s, ran := scriptSession(t)
+	script := `#!/usr/bin/env -S kit shell run
+# comment
+set team = Docs
+if ok check
+  run one --team $team
+  if ! fail
+    run nested
+  else
+    run skipped
+  end
+else
+  run skipped
+end
+if fail
+  run skipped
+end
+run status $?
+run long \
+  --flag x
+fail here
+run after
+`
+	err := s.RunScript(context.Background(), strings.NewReader(script), "t.kit", ScriptOptions{})
+	if err == nil || err.Error() != "t.kit: 1 line(s) failed" {
+		t.Errorf("err = %v", err)
+	}
+	want := []string{"ok check", "run one --team Docs", "fail", "run nested", "fail", "run status 1", "run long --flag x", "fail here", "run after"}
+	if strings.Join(*ran, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran:\n%s\nwant:\n%s", strings.Join(*ran, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRunScriptFailFastAndExit(t *testing.T) {
+	s, ran := scriptSession(t)
+	err := s.RunScript(context.Background(), strings.NewReader("run a\nfail b\nrun c\n"), "t.kit", ScriptOptions{FailFast: true})
+	if err == nil || !strings.Contains(err.Error(), "t.kit:2") || len(*ran) != 2 {
+		t.Errorf("fail-fast: err = %v, ran %q", err, *ran)
+	}
+
+	*ran = nil
+	err = s.RunScript(context.Background(), strings.NewReader("run a\nexit 3\nrun b\n"), "t.kit", ScriptOptions{})
+	var exit *ExitError
+	if !errors.As(err, &exit) || exit.Status != 3 || len(*ran) != 1 {
+		t.Errorf("exit: err = %v, ran %q", err, *ran)
+	}
+
+	for _, bad := range []string{"if run a\nrun b\n", "end\n", "else\n", "if\nend\n"} {
+		if err := s.RunScript(context.Background(), strings.NewReader(bad), "t.kit", ScriptOptions{}); err == nil {
+			t.Errorf("%q: expected a block error", bad)
+		}
+	}
+}
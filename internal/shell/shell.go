@@ -10,9 +10,12 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/chzyer/readline"
+
+	"github.com/klytics/m365kit/internal/alias"
 )
 
 // CommandRunner executes a kit command and returns its output.
@@ -22,6 +25,29 @@ type CommandRunner func(ctx context.Context, args []string, stdout, stderr io.Wr
 // DefaultRunner is the command runner used by the shell session.
 var DefaultRunner CommandRunner
 
+// Defaults are a session's default SharePoint site and team, and its
+// current OneDrive folder. Eval passes them to commands through their
+// context.
+type Defaults struct {
+	Site   string
+	Team   string
+	Folder string
+}
+
+type defaultsKey struct{}
+
+// WithDefaults returns a context carrying the session defaults d.
+func WithDefaults(ctx context.Context, d Defaults) context.Context {
+	return context.WithValue(ctx, defaultsKey{}, d)
+}
+
+// DefaultsFrom returns the session defaults in ctx; outside the shell
+// they are empty.
+func DefaultsFrom(ctx context.Context) Defaults {
+	d, _ := ctx.Value(defaultsKey{}).(Defaults)
+	return d
+}
+
 // Session manages an interactive kit shell session.
 type Session struct {
 	DefaultSite    string
@@ -31,6 +57,32 @@ type Session struct {
 	HistoryFile    string
 	StartTime      time.Time
 
+	// Vars are the session variables (see SetVar), saved to VarsFile.
+	Vars     map[string]string
+	VarsFile string
+
+	// Folder is the current OneDrive folder ("" for the root). It and the
+	// defaults above are saved to ContextFile.
+	Folder      string
+	ContextFile string
+
+	// Aliases are the user's command shortcuts (see alias), expanded at
+	// the start of each command in a pipeline.
+	Aliases *alias.Aliases
+
+	// Background jobs (line &), and how the REPL says one finished
+	jobs   []*Job
+	jobsMu sync.Mutex
+	notify func(msg string)
+
+	status int // $?: 0 if the last line succeeded, 1 if it failed
+
+	// Paging sends output longer than the screen through $PAGER; the REPL
+	// turns it on.
+	Paging bool
+
+	completions completionCache
+
 	// KnownCommands is the list of top-level commands for completion.
 	KnownCommands []string
 }
@@ -43,19 +95,32 @@ func NewSession() (*Session, error) {
 	// Ensure parent dir exists
 	os.MkdirAll(filepath.Dir(histFile), 0755)
 
-	return &Session{
+	s := &Session{
 		HistoryFile: histFile,
+		VarsFile:    filepath.Join(home, ".kit", "shell_vars.json"),
+		ContextFile: filepath.Join(home, ".kit", "shell_context.json"),
 		StartTime:   time.Now(),
 		KnownCommands: []string{
 			"word", "excel", "pptx", "ai", "pipeline", "batch",
-			"auth", "onedrive", "sharepoint", "teams", "outlook", "acl",
+			"auth", "onedrive", "sharepoint", "teams", "outlook", "calendar", "contacts", "acl",
 			"fs", "template", "report", "watch",
 			"send", "diff", "convert",
-			"config", "completion", "update", "doctor", "version",
+			"config", "cache", "completion", "update", "doctor", "version",
 			"org", "audit", "admin", "plugin", "shell",
-			"help", "exit", "quit", "history", "set",
+			"help", "exit", "quit", "history", "set", "unset", "vars", "use", "cd", "pwd", "echo", "last", "alias", "unalias", "jobs", "wait",
 		},
-	}, nil
+	}
+	err := s.LoadVars()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.LoadContext(); err != nil {
+		return nil, err
+	}
+	if s.Aliases, err = alias.Load(alias.DefaultPath()); err != nil {
+		return nil, err
+	}
+	return s, nil
 }
 
 // Run starts the REPL loop. Blocks until 'exit' or Ctrl+D.
@@ -64,12 +129,10 @@ func (s *Session) Run(ctx context.Context) error {
 		return fmt.Errorf("shell runner not configured")
 	}
 
-	completer := readline.NewPrefixCompleter(s.buildCompleter()...)
-
 	rl, err := readline.NewEx(&readline.Config{
 		Prompt:          "kit> ",
 		HistoryFile:     s.HistoryFile,
-		AutoComplete:    completer,
+		AutoComplete:    completer{s},
 		InterruptPrompt: "^C",
 		EOFPrompt:       "exit",
 	})
@@ -77,6 +140,9 @@ func (s *Session) Run(ctx context.Context) error {
 		return err
 	}
 	defer rl.Close()
+	s.Paging = true
+	s.notify = func(msg string) { fmt.Fprintln(rl.Stdout(), msg) }
+	warnedJobs := false
 
 	fmt.Printf("M365Kit — Interactive Shell\n")
 	fmt.Println("Type 'help' for commands, 'exit' to quit.")
@@ -96,6 +162,12 @@ func (s *Session) Run(ctx context.Context) error {
 
 		switch {
 		case line == "exit" || line == "quit":
+			if n := len(s.RunningJobs()); n > 0 && !warnedJobs {
+				fmt.Printf("%d job(s) still running; 'wait' for them, or exit again to stop them.\n", n)
+				warnedJobs = true
+				continue
+			}
+			s.StopJobs()
 			elapsed := time.Since(s.StartTime)
 			fmt.Printf("\nSession ended. %d commands run in %s.\n",
 				len(s.CommandHistory)-1, formatDuration(elapsed))
@@ -106,24 +178,9 @@ func (s *Session) Run(ctx context.Context) error {
 			for i, cmd := range s.CommandHistory {
 				fmt.Printf("  %d  %s\n", i+1, cmd)
 			}
-		case strings.HasPrefix(line, "set site "):
-			s.DefaultSite = strings.TrimPrefix(line, "set site ")
-			fmt.Printf("Default SharePoint site: %s\n", s.DefaultSite)
-		case strings.HasPrefix(line, "set team "):
-			s.DefaultTeam = strings.TrimPrefix(line, "set team ")
-			fmt.Printf("Default team: %s\n", s.DefaultTeam)
-		case strings.HasPrefix(line, "-- "):
-			// Raw shell passthrough — not supported in this implementation
-			fmt.Println("Shell passthrough not supported. Use standard kit commands.")
 		default:
-			output, err := s.Eval(ctx, line)
-			if err != nil {
+			if err := s.Exec(ctx, line); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-			} else if output != "" {
-				fmt.Print(output)
-				if !strings.HasSuffix(output, "\n") {
-					fmt.Println()
-				}
 			}
 		}
 	}
@@ -131,74 +188,223 @@ func (s *Session) Run(ctx context.Context) error {
 	return nil
 }
 
-// Eval runs a single command string and returns its output.
+// Exec runs a line as the REPL does: a shell command (set, use, cd, ...)
+// or kit commands, whose output it prints. It sets $? to 0 when the line
+// succeeds and 1 when it fails.
+func (s *Session) Exec(ctx context.Context, line string) error {
+	err := s.exec(ctx, line)
+	s.status = 0
+	if err != nil {
+		s.status = 1
+	}
+	return err
+}
+
+func (s *Session) exec(ctx context.Context, line string) error {
+	if line, bg := cutBackground(line); bg {
+		j, err := s.startJob(ctx, line)
+		if err != nil {
+			return err
+		}
+		fmt.Println(j)
+		return nil
+	}
+	line, to, err := s.redirection(ctx, line)
+	if err != nil {
+		return err
+	}
+	out, err := s.execLine(ctx, line, &to)
+	if err != nil {
+		return err
+	}
+	return s.show(out, to)
+}
+
+// builtins are the shell's own commands, which run in the session rather
+// than as kit commands.
+var builtins = map[string]bool{
+	"set": true, "unset": true, "use": true, "cd": true, "pwd": true, "vars": true, "echo": true,
+	"last": true, "alias": true, "unalias": true, "jobs": true, "wait": true, "--": true,
+	"help": true, "history": true, "exit": true, "quit": true,
+}
+
+// execLine runs a line without its redirect and returns its output. "last
+// --save" sets the redirect.
+func (s *Session) execLine(ctx context.Context, line string, to **redirect) (string, error) {
+	word, rest, _ := strings.Cut(line, " ")
+	switch word {
+	case "set":
+		return s.evalSet(ctx, strings.TrimSpace(rest))
+	case "unset":
+		return "", s.UnsetVar(strings.TrimSpace(rest))
+	case "use":
+		return s.evalUse(rest)
+	case "cd":
+		_, err := s.evalCd(rest)
+		return "", err
+	case "pwd":
+		return "onedrive:" + s.folderOrRoot(), nil
+	case "vars":
+		var b strings.Builder
+		for _, name := range s.VarNames() {
+			fmt.Fprintf(&b, "  %s = %s\n", name, s.Vars[name])
+		}
+		return b.String(), nil
+	case "alias":
+		return s.evalAlias(ctx, strings.TrimSpace(rest))
+	case "unalias":
+		return "", s.unalias(strings.TrimSpace(rest))
+	case "jobs":
+		return s.evalJobs(), nil
+	case "wait":
+		out, err := s.evalWait(ctx, rest)
+		if err != nil {
+			s.show(out, nil)
+			return "", err
+		}
+		return out, nil
+	case "last":
+		out, r, err := s.evalLast(ctx, rest)
+		if r != nil {
+			*to = r
+		}
+		return out, err
+	case "echo":
+		text, err := s.expand(ctx, rest)
+		if err != nil {
+			return "", err
+		}
+		args, err := splitLine(text)
+		if err != nil || len(args) > 1 {
+			return "", fmt.Errorf("echo: quote text with a |")
+		}
+		var words []string
+		if len(args) == 1 {
+			words = args[0]
+		}
+		return strings.Join(words, " ") + "\n", nil
+	case "--":
+		// Raw shell passthrough — not supported in this implementation
+		return "Shell passthrough not supported. Use standard kit commands.", nil
+	}
+	return s.Eval(ctx, line)
+}
+
+// Eval runs a command line and returns its output. Commands separated by
+// | form a pipeline: each one's output is the next one's stdin. Session
+// variables and $(command) substitutions are expanded first.
 func (s *Session) Eval(ctx context.Context, command string) (string, error) {
+	return s.eval(ctx, command, false)
+}
+
+// eval runs command like Eval. With capture, the output of the last
+// command is only returned, not shown.
+func (s *Session) eval(ctx context.Context, command string, capture bool) (string, error) {
 	if DefaultRunner == nil {
 		return "", fmt.Errorf("shell runner not configured")
 	}
 
-	args := strings.Fields(command)
-	if len(args) == 0 {
+	command, err := s.expand(ctx, command)
+	if err != nil {
+		return "", err
+	}
+	stages, err := splitLine(command)
+	if err != nil {
+		return "", err
+	}
+	if len(stages) == 0 {
 		return "", nil
 	}
 
-	var stdout, stderr bytes.Buffer
-	err := DefaultRunner(ctx, args, &stdout, &stderr)
+	ctx = WithDefaults(ctx, Defaults{Site: s.DefaultSite, Team: s.DefaultTeam, Folder: s.Folder})
+
+	var input []byte
+	for i, args := range stages {
+		last := i == len(stages)-1
+		stageCtx := ctx
+		if len(stages) > 1 || capture {
+			stageCtx = WithStdio(ctx, Stdio{Stdin: input, Capture: !last || capture})
+		}
 
-	output := stdout.String()
-	s.LastOutput = output
+		if s.Aliases != nil {
+			if args, err = s.Aliases.Expand(args); err != nil {
+				return "", err
+			}
+		}
 
-	if errOut := stderr.String(); errOut != "" && err != nil {
-		return output, fmt.Errorf("%s", strings.TrimSpace(errOut))
+		var stdout, stderr bytes.Buffer
+		err := DefaultRunner(stageCtx, args, &stdout, &stderr)
+		if last {
+			s.LastOutput = stdout.String()
+		}
+		if err != nil {
+			if errOut := stderr.String(); errOut != "" {
+				err = fmt.Errorf("%s", strings.TrimSpace(errOut))
+			}
+			if len(stages) > 1 {
+				err = fmt.Errorf("%s: %w", args[0], err)
+			}
+			return stdout.String(), err
+		}
+		input = stdout.Bytes()
+		if input == nil {
+			input = []byte{}
+		}
 	}
-
-	return output, err
+	return s.LastOutput, nil
 }
 
-// Complete returns tab-completion candidates for the given input.
+// Complete returns tab-completion candidates for the word being typed at
+// the end of input: command words, subcommands, flags, and live values
+// like team names and OneDrive paths (see DefaultLister).
 func (s *Session) Complete(input string) []string {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return s.KnownCommands
+	words, cur, _ := completionWords(input)
+	if len(words) == 0 {
+		if cur == "" {
+			return s.KnownCommands
+		}
+		var matches []string
+		for _, cmd := range s.KnownCommands {
+			if strings.HasPrefix(cmd, cur) {
+				matches = append(matches, cmd)
+			}
+		}
+		if s.Aliases != nil {
+			for _, name := range s.Aliases.Names() {
+				if strings.HasPrefix(name, cur) {
+					matches = append(matches, name)
+				}
+			}
+		}
+		sort.Strings(matches)
+		return matches
 	}
 
-	parts := strings.Fields(input)
-	if len(parts) == 0 {
-		return s.KnownCommands
+	if values, ok := s.completeValue(context.Background(), words, cur); ok {
+		return values
 	}
 
-	// Complete top-level command
-	if len(parts) == 1 && !strings.HasSuffix(input, " ") {
-		prefix := parts[0]
+	// For flags
+	if strings.HasPrefix(cur, "-") {
 		var matches []string
-		for _, cmd := range s.KnownCommands {
-			if strings.HasPrefix(cmd, prefix) {
-				matches = append(matches, cmd)
+		for _, flag := range []string{"--json", "--verbose", "--help", "--output"} {
+			if strings.HasPrefix(flag, cur) {
+				matches = append(matches, flag)
 			}
 		}
-		sort.Strings(matches)
 		return matches
 	}
 
 	// For subcommands, return common subcommands based on parent
-	parent := parts[0]
-	subcommands := s.subcommandsFor(parent)
-	if len(parts) == 2 && !strings.HasSuffix(input, " ") {
-		prefix := parts[1]
+	if len(words) == 1 {
 		var matches []string
-		for _, sub := range subcommands {
-			if strings.HasPrefix(sub, prefix) {
+		for _, sub := range s.subcommandsFor(words[0]) {
+			if strings.HasPrefix(sub, cur) {
 				matches = append(matches, sub)
 			}
 		}
 		return matches
 	}
-
-	// For flags
-	if strings.HasSuffix(input, " -") || (len(parts) > 0 && strings.HasPrefix(parts[len(parts)-1], "-")) {
-		return []string{"--json", "--verbose", "--help", "--output"}
-	}
-
 	return nil
 }
 
@@ -208,21 +414,24 @@ func (s *Session) subcommandsFor(parent string) []string {
 		"excel":      {"read", "write", "analyze"},
 		"pptx":       {"read", "generate"},
 		"ai":         {"summarize", "analyze", "extract", "ask"},
-		"auth":       {"login", "whoami", "status", "logout"},
-		"onedrive":   {"ls", "get", "put", "recent", "search", "share"},
-		"sharepoint": {"sites", "libs", "ls", "get", "put", "audit"},
-		"teams":      {"list", "channels", "post", "dm"},
-		"outlook":    {"inbox", "read", "download", "reply"},
-		"acl":        {"audit", "external", "broken", "users"},
+		"auth":       {"login", "whoami", "status", "logout", "refresh", "list"},
+		"onedrive":   {"ls", "get", "put", "recent", "search", "share", "links", "revoke", "quota", "du", "shared", "rm", "trash"},
+		"sharepoint": {"sites", "libs", "ls", "get", "put", "audit", "checkout", "checkin", "discard-checkout", "versions", "restore-version", "meta", "search", "scaffold", "trash", "page"},
+		"teams":      {"list", "channels", "post", "share", "dm", "reply", "react", "chat", "schedule", "meeting", "create", "archive", "members", "channel", "presence", "users", "export", "bulk"},
+		"outlook":    {"inbox", "read", "attachments", "download", "preview", "harvest", "export", "mark-read", "mark-unread", "flag", "unflag", "reply", "forward", "folders", "move", "search", "draft", "categorize", "categories", "rules"},
+		"calendar":   {"list", "create", "accept", "tentative", "decline"},
+		"contacts":   {"search"},
+		"acl":        {"audit", "external", "broken", "users", "check", "revoke", "remove-link", "log", "diff", "policy"},
 		"fs":         {"scan", "rename", "dedupe", "stale", "organize", "manifest"},
 		"template":   {"list", "show", "apply", "add", "vars"},
 		"report":     {"generate", "preview"},
-		"watch":      {"start", "stop", "status"},
+		"watch":      {"start", "stop", "status", "config", "log", "mail"},
 		"config":     {"init", "show", "set", "validate"},
+		"cache":      {"status", "clear"},
 		"org":        {"show", "validate", "init", "status"},
-		"audit":      {"log", "clear", "status"},
+		"audit":      {"log", "self", "clear", "status"},
 		"admin":      {"stats", "users", "telemetry"},
-		"plugin":     {"list", "install", "remove", "run", "show", "new"},
+		"plugin":     {"list", "search", "info", "install", "update", "remove", "run", "show", "new"},
 	}
 	return subs[parent]
 }
@@ -232,36 +441,31 @@ func (s *Session) printHelp() {
 	fmt.Println()
 	fmt.Println("  Documents:  word, excel, pptx, convert, diff")
 	fmt.Println("  AI:         ai summarize/analyze/extract/ask")
-	fmt.Println("  Cloud:      auth, onedrive, sharepoint, teams, outlook, acl")
+	fmt.Println("  Cloud:      auth, onedrive, sharepoint, teams, outlook, calendar, contacts, acl")
 	fmt.Println("  Files:      fs, template, report, batch, pipeline")
-	fmt.Println("  Admin:      org, audit, admin, config, plugin")
+	fmt.Println("  Admin:      org, audit, admin, config, cache, plugin")
 	fmt.Println("  System:     doctor, version, update")
 	fmt.Println()
 	fmt.Println("Shell commands:")
 	fmt.Println("  help       — show this help")
 	fmt.Println("  history    — show command history")
-	fmt.Println("  set site <url> — set default SharePoint site")
-	fmt.Println("  set team <name> — set default Teams team")
+	fmt.Println("  use site <name> — default site for commands that leave it out")
+	fmt.Println("  use team <name> — default team for commands that leave out --team")
+	fmt.Println("  use        — show the defaults and current folder (kept across sessions)")
+	fmt.Println("  cd onedrive:/<path> — OneDrive folder relative paths start from; pwd shows it")
+	fmt.Println("  set x = <value> — set a variable; use it later as $x")
+	fmt.Println("  set x = $(cmd) — set a variable to a command's output")
+	fmt.Println("  set x from-last [.path] — set a variable to the last output (or a JSON field of it)")
+	fmt.Println("  vars / unset x — list or remove variables (kept across sessions)")
+	fmt.Println("  a | b      — pipe the output of one kit command into the next")
+	fmt.Println("  a > file / a >> file — write or append the output to a file")
+	fmt.Println("  last [--save <file>] — show the last command's output, or save it")
+	fmt.Println("  (long output goes through $PAGER; PAGER=cat turns paging off)")
+	fmt.Println("  alias x = <command> — define a shortcut (kit x works too); alias lists them, unalias x removes one")
+	fmt.Println("  <command> & — run a kit command in the background; jobs lists them, wait [n] waits and shows output")
 	fmt.Println("  exit       — exit the shell")
 }
 
-func (s *Session) buildCompleter() []readline.PrefixCompleterInterface {
-	var items []readline.PrefixCompleterInterface
-	for _, cmd := range s.KnownCommands {
-		subs := s.subcommandsFor(cmd)
-		if len(subs) > 0 {
-			var subItems []readline.PrefixCompleterInterface
-			for _, sub := range subs {
-				subItems = append(subItems, readline.PcItem(sub))
-			}
-			items = append(items, readline.PcItem(cmd, subItems...))
-		} else {
-			items = append(items, readline.PcItem(cmd))
-		}
-	}
-	return items
-}
-
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
 		return fmt.Sprintf("%.0fs", d.Seconds())
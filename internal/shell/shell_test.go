@@ -209,6 +209,27 @@ func TestLastOutputUpdated(t *testing.T) {
 	}
 }
 
+func TestEvalPassesDefaults(t *testing.T) {
+	var got Defaults
+	DefaultRunner = func(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+		got = DefaultsFrom(ctx)
+		return nil
+	}
+	defer func() { DefaultRunner = nil }()
+
+	s, _ := NewSession()
+	s.DefaultSite = "Marketing"
+	s.DefaultTeam = "Engineering"
+	s.Eval(context.Background(), "plugin run sync")
+	if got.Site != "Marketing" || got.Team != "Engineering" {
+		t.Errorf("defaults = %+v", got)
+	}
+
+	if d := DefaultsFrom(context.Background()); d != (Defaults{}) {
+		t.Errorf("outside the shell = %+v", d)
+	}
+}
+
 func TestFormatDuration(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,305 @@
+package shell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Session variables are set with "set name = value", "set name =
+// $(command)", or "set name from-last [.json.path]", and used in later
+// commands as $name or ${name}. They are saved to VarsFile, so they
+// outlive the session.
+
+// LoadVars reads the saved variables from VarsFile. A missing file means
+// none.
+func (s *Session) LoadVars() error {
+	s.Vars = make(map[string]string)
+	data, err := os.ReadFile(s.VarsFile)
+	if os.IsNotExist(err) || s.VarsFile == "" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.Vars); err != nil {
+		return fmt.Errorf("invalid shell variables file %s: %w", s.VarsFile, err)
+	}
+	return nil
+}
+
+func (s *Session) saveVars() error {
+	if s.VarsFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.Vars, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.VarsFile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.VarsFile, data, 0600)
+}
+
+// SetVar sets the variable name to value and saves the variables.
+func (s *Session) SetVar(name, value string) error {
+	if !validVarName(name) {
+		return fmt.Errorf("invalid variable name %q (use letters, digits, and _)", name)
+	}
+	if s.Vars == nil {
+		s.Vars = make(map[string]string)
+	}
+	s.Vars[name] = value
+	return s.saveVars()
+}
+
+// UnsetVar removes the variable name and saves the variables.
+func (s *Session) UnsetVar(name string) error {
+	if _, ok := s.Vars[name]; !ok {
+		return fmt.Errorf("no variable %q", name)
+	}
+	delete(s.Vars, name)
+	return s.saveVars()
+}
+
+// VarNames returns the names of the session variables, sorted.
+func (s *Session) VarNames() []string {
+	names := make([]string, 0, len(s.Vars))
+	for name := range s.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validVarName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// evalSet runs a "set" line, after "set ": a default site or team
+// (set site <url>, set team <name>, like use) or a variable. It returns what to
+// tell the user.
+func (s *Session) evalSet(ctx context.Context, rest string) (string, error) {
+	name, value, isVar := strings.Cut(rest, "=")
+	if !isVar {
+		if n, path, ok := strings.Cut(rest, " from-last"); ok {
+			return s.setFromLast(strings.TrimSpace(n), strings.TrimSpace(path))
+		}
+		if kind, _, _ := strings.Cut(rest, " "); kind == "site" || kind == "team" {
+			return s.evalUse(rest)
+		}
+		return "", fmt.Errorf("usage: set <name> = <value>, set <name> = $(command), or set <name> from-last [.path]")
+	}
+
+	name = strings.TrimSpace(name)
+	expanded, err := s.expand(ctx, strings.TrimSpace(value))
+	if err != nil {
+		return "", err
+	}
+	args, err := splitLine(expanded)
+	if err != nil {
+		return "", err
+	}
+	if len(args) > 1 {
+		return "", fmt.Errorf("set %s: value has a |; quote it", name)
+	}
+	var words []string
+	if len(args) == 1 {
+		words = args[0]
+	}
+	if err := s.SetVar(name, strings.Join(words, " ")); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s = %s", name, s.Vars[name]), nil
+}
+
+// setFromLast sets name to the last command's output, or with path, to
+// the value at path in it, read as JSON.
+func (s *Session) setFromLast(name, path string) (string, error) {
+	value := strings.TrimRight(s.LastOutput, "\r\n")
+	if path != "" {
+		var doc any
+		if err := json.Unmarshal([]byte(s.LastOutput), &doc); err != nil {
+			return "", fmt.Errorf("last output is not JSON (run the command with --json)")
+		}
+		v, err := jsonPath(doc, path)
+		if err != nil {
+			return "", err
+		}
+		value = v
+	}
+	if err := s.SetVar(name, value); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s = %s", name, value), nil
+}
+
+// jsonPath returns the value at path in doc: fields and indexes like
+// .value[0].id. Strings are returned as they are, anything else as JSON.
+func jsonPath(doc any, path string) (string, error) {
+	cur := doc
+	rest := path
+	for rest != "" && rest != "." {
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return "", fmt.Errorf("invalid path %s", path)
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			list, ok := cur.([]any)
+			if err != nil || !ok {
+				return "", fmt.Errorf("%s: %s is not a list index", path, rest[:end+1])
+			}
+			if i < 0 {
+				i += len(list)
+			}
+			if i < 0 || i >= len(list) {
+				return "", fmt.Errorf("%s: index %s out of range (%d items)", path, rest[1:end], len(list))
+			}
+			cur, rest = list[i], rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			obj, ok := cur.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("%s: .%s is not an object field", path, rest[:end])
+			}
+			v, ok := obj[rest[:end]]
+			if !ok {
+				return "", fmt.Errorf("%s: no field %q", path, rest[:end])
+			}
+			cur, rest = v, rest[end:]
+		default:
+			return "", fmt.Errorf("invalid path %s (use e.g. .value[0].id)", path)
+		}
+	}
+	if str, ok := cur.(string); ok {
+		return str, nil
+	}
+	data, err := json.Marshal(cur)
+	return string(data), err
+}
+
+// expand replaces $name, ${name}, and $(command) in line, outside single
+// quotes. Values are escaped so each stays one argument. A name that is
+// not a session variable is looked up in the environment.
+func (s *Session) expand(ctx context.Context, line string) (string, error) {
+	if !strings.Contains(line, "$") {
+		return line, nil
+	}
+	var (
+		out      strings.Builder
+		inSingle bool
+		inDouble bool
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && !inSingle && i+1 < len(line):
+			out.WriteByte(c)
+			out.WriteByte(line[i+1])
+			i++
+			continue
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case c == '$' && !inSingle && i+1 < len(line):
+			value, n, err := s.expandAt(ctx, line[i+1:])
+			if err != nil {
+				return "", err
+			}
+			if n > 0 {
+				out.WriteString(escapeArg(value))
+				i += n
+				continue
+			}
+		}
+		out.WriteByte(c)
+	}
+	return out.String(), nil
+}
+
+// expandAt expands the reference after a $ at the start of rest, and
+// returns its value and length; 0 when rest doesn't start one.
+func (s *Session) expandAt(ctx context.Context, rest string) (string, int, error) {
+	switch rest[0] {
+	case '?':
+		return strconv.Itoa(s.status), 1, nil
+	case '(':
+		depth := 0
+		for i := 0; i < len(rest); i++ {
+			switch rest[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					out, err := s.eval(ctx, rest[1:i], true)
+					if err != nil {
+						return "", 0, fmt.Errorf("$(%s): %w", rest[1:i], err)
+					}
+					return strings.TrimRight(out, "\r\n"), i + 1, nil
+				}
+			}
+		}
+		return "", 0, fmt.Errorf("unterminated $(")
+	case '{':
+		end := strings.Index(rest, "}")
+		if end < 0 {
+			return "", 0, fmt.Errorf("unterminated ${")
+		}
+		v, err := s.lookupVar(rest[1:end])
+		return v, end + 1, err
+	}
+	n := 0
+	for n < len(rest) && validVarName(rest[:n+1]) {
+		n++
+	}
+	if n == 0 {
+		return "", 0, nil
+	}
+	v, err := s.lookupVar(rest[:n])
+	return v, n, err
+}
+
+func (s *Session) lookupVar(name string) (string, error) {
+	if v, ok := s.Vars[name]; ok {
+		return v, nil
+	}
+	if v, ok := os.LookupEnv(name); ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("undefined variable $%s (see 'vars')", name)
+}
+
+// escapeArg escapes value so splitLine reads it back as it is, within a
+// single argument.
+func escapeArg(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(escapable, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
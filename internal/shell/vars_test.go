@@ -0,0 +1,109 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// varSession returns a session whose variables are saved in a temporary
+// directory, and a runner that echoes its arguments.
+func varSession(t *testing.T) (*Session, *[][]string) {
+	t.Helper()
+	var calls [][]string
+	DefaultRunner = func(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+		calls = append(calls, args)
+		switch args[0] {
+		case "sites":
+			fmt.Fprintln(stdout, `{"value": [{"id": "site-1", "name": "Marketing Hub"}]}`)
+		default:
+			fmt.Fprintln(stdout, strings.Join(args, " "))
+		}
+		return nil
+	}
+	t.Cleanup(func() { DefaultRunner = nil })
+	return &Session{VarsFile: filepath.Join(t.TempDir(), "vars.json")}, &calls
+}
+
+func TestSetAndExpandVars(t *testing.T) {
+	s, calls := varSession(t)
+	ctx := context.Background()
+
+	if _, err := s.evalSet(ctx, `team = "Project Alpha"`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.evalSet(ctx, "id = $(sites --json)"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(s.Vars["id"], `{"value"`) {
+		t.Errorf("id = %q", s.Vars["id"])
+	}
+	s.Eval(ctx, "sites --json")
+	if _, err := s.evalSet(ctx, "site from-last .value[0].name"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Vars["site"] != "Marketing Hub" {
+		t.Errorf("site = %q", s.Vars["site"])
+	}
+
+	*calls = nil
+	s.Eval(ctx, `teams post --team $team --message "hi ${site}" --raw '$team' \$team`)
+	want := []string{"teams", "post", "--team", "Project Alpha", "--message", "hi Marketing Hub", "--raw", "$team", "$team"}
+	if len(*calls) != 1 || !reflect.DeepEqual((*calls)[0], want) {
+		t.Errorf("args = %q, want %q", *calls, want)
+	}
+
+	if _, err := s.Eval(ctx, "echo $nope_not_set"); err == nil || !strings.Contains(err.Error(), "undefined variable $nope_not_set") {
+		t.Errorf("err = %v", err)
+	}
+
+	// Saved for the next session
+	next := &Session{VarsFile: s.VarsFile}
+	if err := next.LoadVars(); err != nil {
+		t.Fatal(err)
+	}
+	if next.Vars["team"] != "Project Alpha" || next.Vars["site"] != "Marketing Hub" {
+		t.Errorf("loaded %v", next.Vars)
+	}
+	if err := next.UnsetVar("team"); err != nil || len(next.VarNames()) != 2 {
+		t.Errorf("unset: %v, %v", err, next.VarNames())
+	}
+}
+
+func TestSetDefaultsAndErrors(t *testing.T) {
+	s, _ := varSession(t)
+	ctx := context.Background()
+	if _, err := s.evalSet(ctx, "site https://co.sharepoint.com/sites/Legal"); err != nil || s.DefaultSite != "https://co.sharepoint.com/sites/Legal" {
+		t.Errorf("set site: %v, %q", err, s.DefaultSite)
+	}
+	if _, err := s.evalSet(ctx, "1x = y"); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+	s.LastOutput = "not json"
+	if _, err := s.evalSet(ctx, "x from-last .id"); err == nil {
+		t.Error("expected an error for output that is not JSON")
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	doc := map[string]any{"value": []any{map[string]any{"id": "a", "n": 2.0}, map[string]any{"id": "b"}}}
+	for path, want := range map[string]string{
+		".value[0].id":  "a",
+		".value[-1].id": "b",
+		".value[0].n":   "2",
+		".value[1]":     `{"id":"b"}`,
+	} {
+		if got, err := jsonPath(doc, path); err != nil || got != want {
+			t.Errorf("jsonPath(%s) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	for _, path := range []string{".missing", ".value[5]", ".value.id", "value"} {
+		if _, err := jsonPath(doc, path); err == nil {
+			t.Errorf("jsonPath(%s): expected an error", path)
+		}
+	}
+}
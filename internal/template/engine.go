@@ -50,13 +50,17 @@ type Library struct {
 // varPattern matches {{variableName}} with optional whitespace inside braces.
 var varPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.]*)\s*\}\}`)
 
-// ExtractVariables scans a .docx file and returns all unique template variables found.
+// ExtractVariables scans a .docx file (or a text or Markdown template) and returns all
+// unique template variables found.
 // It handles Word XML run-splitting by merging text across <w:r> elements before scanning.
 func ExtractVariables(path string) ([]Variable, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not read %s: %w", path, err)
 	}
+	if IsTextTemplate(path) {
+		return ExtractTextVariables(string(data)), nil
+	}
 	return ExtractVariablesFromBytes(data)
 }
 
@@ -102,16 +106,36 @@ func ExtractVariablesFromBytes(data []byte) ([]Variable, error) {
 	return vars, nil
 }
 
-// Apply substitutes template variables in a .docx file and writes the result.
+// Apply substitutes template variables in a .docx file (or a text or Markdown template)
+// and writes the result.
 // It handles Word XML run-splitting by consolidating split runs before replacement.
 func Apply(templatePath string, values map[string]string, outputPath string) (*ApplyResult, error) {
 	data, err := os.ReadFile(templatePath)
 	if err != nil {
 		return nil, fmt.Errorf("could not read template %s: %w", templatePath, err)
 	}
+	if IsTextTemplate(templatePath) {
+		return applyTextFile(string(data), values, outputPath)
+	}
 	return ApplyFromBytes(data, values, outputPath)
 }
 
+func applyTextFile(text string, values map[string]string, outputPath string) (*ApplyResult, error) {
+	out, applied, missing := ApplyText(text, values)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return nil, fmt.Errorf("could not create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(out), 0644); err != nil {
+		return nil, fmt.Errorf("could not write output %s: %w", outputPath, err)
+	}
+	return &ApplyResult{
+		OutputPath:       outputPath,
+		VariablesApplied: applied,
+		VariablesMissing: len(missing),
+		MissingNames:     missing,
+	}, nil
+}
+
 // ApplyFromBytes substitutes variables in raw .docx bytes and writes the result.
 func ApplyFromBytes(data []byte, values map[string]string, outputPath string) (*ApplyResult, error) {
 	result, err := ApplyToBytes(data, values)
@@ -465,3 +465,45 @@ func TestTemplateJSON(t *testing.T) {
 	}
 }
 
+
+func TestApplyText(t *testing.T) {
+	text := "## Weekly update {{ week }}\n\n**Wins:** {{wins}}\n**Risks:** {{risks}}\n{{wins}}"
+	vars := ExtractTextVariables(text)
+	if len(vars) != 3 || vars[0].Name != "risks" || vars[2].Name != "wins" {
+		t.Fatalf("ExtractTextVariables = %+v", vars)
+	}
+
+	out, applied, missing := ApplyText(text, map[string]string{"week": "42", "wins": "shipped"})
+	if applied != 3 {
+		t.Errorf("applied = %d, want 3", applied)
+	}
+	if len(missing) != 1 || missing[0] != "risks" {
+		t.Errorf("missing = %v", missing)
+	}
+	if !strings.Contains(out, "Weekly update 42") || !strings.Contains(out, "{{risks}}") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestLibraryAddTextTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "weekly.md")
+	os.WriteFile(path, []byte("Wins: {{wins}}"), 0644)
+
+	lib, _ := LoadLibrary(filepath.Join(dir, "lib"))
+	tmpl, err := lib.Add("weekly-update", "", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tmpl.Variables) != 1 || tmpl.Variables[0].Name != "wins" {
+		t.Errorf("variables = %+v", tmpl.Variables)
+	}
+
+	out := filepath.Join(dir, "out.md")
+	if _, err := Apply(path, map[string]string{"wins": "launch"}, out); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "Wins: launch" {
+		t.Errorf("applied = %q", data)
+	}
+}
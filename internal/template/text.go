@@ -0,0 +1,55 @@
+package template
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// textExtensions are the file types treated as text or Markdown templates
+// rather than Word documents.
+var textExtensions = map[string]bool{".md": true, ".markdown": true, ".txt": true}
+
+// IsTextTemplate reports whether path is a text or Markdown template.
+func IsTextTemplate(path string) bool {
+	return textExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// ExtractTextVariables returns the unique variables in a text template.
+func ExtractTextVariables(text string) []Variable {
+	seen := make(map[string]bool)
+	var vars []Variable
+	for _, m := range varPattern.FindAllStringSubmatch(text, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			vars = append(vars, Variable{Name: m[1], Required: true})
+		}
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].Name < vars[j].Name
+	})
+	return vars
+}
+
+// ApplyText substitutes variables in a text template. Variables without a
+// value are left in place and returned as missing.
+func ApplyText(text string, values map[string]string) (string, int, []string) {
+	applied := 0
+	missing := make(map[string]bool)
+	out := varPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := varPattern.FindStringSubmatch(placeholder)[1]
+		if v, ok := values[name]; ok {
+			applied++
+			return v
+		}
+		missing[name] = true
+		return placeholder
+	})
+
+	var missingNames []string
+	for name := range missing {
+		missingNames = append(missingNames, name)
+	}
+	sort.Strings(missingNames)
+	return out, applied, missingNames
+}
@@ -0,0 +1,139 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/config"
+)
+
+// Price is what a model costs, in US dollars per million tokens.
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Prices maps model names, or prefixes of them, to prices.
+type Prices map[string]Price
+
+// DefaultPrices are the providers' published list prices for their common
+// models. Versioned names (claude-sonnet-4-20250514, gpt-4o-2024-08-06)
+// match by prefix. They are estimates: check the provider's bill.
+var DefaultPrices = Prices{
+	"claude-opus-4":     {15, 75},
+	"claude-sonnet-4":   {3, 15},
+	"claude-3-7-sonnet": {3, 15},
+	"claude-3-5-sonnet": {3, 15},
+	"claude-3-5-haiku":  {0.8, 4},
+	"claude-haiku-4":    {1, 5},
+	"claude-3-haiku":    {0.25, 1.25},
+	"gpt-4o":            {2.5, 10},
+	"gpt-4o-mini":       {0.15, 0.6},
+	"gpt-4.1":           {2, 8},
+	"gpt-4.1-mini":      {0.4, 1.6},
+	"gpt-4.1-nano":      {0.1, 0.4},
+	"gpt-4-turbo":       {10, 30},
+	"o3-mini":           {1.1, 4.4},
+	"o4-mini":           {1.1, 4.4},
+	"gemini-2.5-pro":    {1.25, 10},
+	"gemini-2.5-flash":  {0.3, 2.5},
+	"gemini-2.0-flash":  {0.1, 0.4},
+	"gemini-1.5-pro":    {1.25, 5},
+	"gemini-1.5-flash":  {0.075, 0.3},
+
+	"text-embedding-3-small": {0.02, 0},
+	"text-embedding-3-large": {0.13, 0},
+	"text-embedding-ada-002": {0.1, 0},
+}
+
+// LoadPrices returns DefaultPrices with usage.prices from ~/.kit/config.yaml
+// added over them.
+func LoadPrices() Prices {
+	prices := make(Prices, len(DefaultPrices))
+	for m, p := range DefaultPrices {
+		prices[m] = p
+	}
+	if cfg, err := config.Load(); err == nil {
+		for m, p := range cfg.Usage.Prices {
+			prices[m] = Price{Input: p.Input, Output: p.Output}
+		}
+	}
+	return prices
+}
+
+// Lookup returns the price of model: an exact entry, or else the longest
+// entry it starts with.
+func (p Prices) Lookup(model string) (Price, bool) {
+	if price, ok := p[model]; ok {
+		return price, true
+	}
+	best := ""
+	for m := range p {
+		if strings.HasPrefix(model, m) && len(m) > len(best) {
+			best = m
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return p[best], true
+}
+
+// Cost estimates what a call cost. Ollama runs locally, so is free;
+// otherwise ok is false if the model's price is unknown.
+func (p Prices) Cost(provider, model string, inputTokens, outputTokens int) (cost float64, ok bool) {
+	price, ok := p.Lookup(model)
+	if !ok {
+		return 0, provider == "ollama"
+	}
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1e6, true
+}
+
+// Budget is a monthly spending limit.
+type Budget struct {
+	// Monthly is the limit in US dollars; 0 means none.
+	Monthly float64 `json:"monthly_usd"`
+	// WarnAt is the fraction of Monthly at which to warn.
+	WarnAt float64 `json:"warn_at"`
+}
+
+// DefaultWarnAt is when to warn if usage.warn_at isn't set: at 80% of the
+// budget.
+const DefaultWarnAt = 0.8
+
+// LoadBudget reads usage.monthly_budget and usage.warn_at from
+// ~/.kit/config.yaml.
+func LoadBudget() Budget {
+	b := Budget{WarnAt: DefaultWarnAt}
+	if cfg, err := config.Load(); err == nil {
+		b.Monthly = cfg.Usage.MonthlyBudget
+		if cfg.Usage.WarnAt > 0 {
+			b.WarnAt = cfg.Usage.WarnAt
+		}
+	}
+	return b
+}
+
+// Warning returns a warning if spent has reached the point where b warns,
+// or "" if not (or if there is no budget).
+func (b Budget) Warning(spent float64, now time.Time) string {
+	if b.Monthly <= 0 || spent < b.Monthly*b.WarnAt {
+		return ""
+	}
+	month := now.Format("January")
+	if spent >= b.Monthly {
+		return fmt.Sprintf("estimated AI spend for %s is $%.2f, over the $%.2f monthly budget", month, spent, b.Monthly)
+	}
+	return fmt.Sprintf("estimated AI spend for %s is $%.2f, %.0f%% of the $%.2f monthly budget", month, spent, spent/b.Monthly*100, b.Monthly)
+}
+
+// MonthSpend returns the estimated cost of this month's records in the
+// ledger at path.
+func MonthSpend(path string, prices Prices, now time.Time) (float64, error) {
+	records, err := Read(path)
+	if err != nil {
+		return 0, err
+	}
+	return Summarize(Between(records, MonthStart(now), time.Time{}), prices).Cost, nil
+}
@@ -0,0 +1,175 @@
+// Package usage meters AI provider calls: each one is appended to a local
+// ledger (~/.kit/usage.jsonl) with its provider, model, command, and token
+// counts, so kit ai usage can report tokens and estimated cost, and warn
+// when spending nears a monthly budget.
+package usage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/cmdlog"
+)
+
+// Record is one AI call.
+type Record struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model"`
+	Command      string    `json:"command,omitempty"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	// Estimated is set when the provider didn't report token counts (as
+	// with streamed responses) and they were estimated from the text.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// LogPath returns ~/.kit/usage.jsonl, or KIT_USAGE_LOG if set.
+// KIT_USAGE_LOG=off turns metering off.
+func LogPath() string {
+	if p := os.Getenv("KIT_USAGE_LOG"); p != "" {
+		if p == "off" {
+			return ""
+		}
+		return p
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".kit", "usage.jsonl")
+}
+
+// Append adds r to the ledger at path. It is best-effort: a ledger that
+// cannot be written never fails the call being metered.
+func Append(path string, r Record) {
+	if path == "" {
+		return
+	}
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now()
+	}
+	if r.Command == "" {
+		r.Command = cmdlog.Command()
+	}
+	cmdlog.Append(path, r)
+}
+
+// Read reads all records from the ledger at path.
+func Read(path string) ([]Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []Record
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			continue // skip malformed lines
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// MonthStart returns the first instant of t's month, in t's location.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// Between returns the records from start up to (not including) end; a zero
+// end means no limit.
+func Between(records []Record, start, end time.Time) []Record {
+	var result []Record
+	for _, r := range records {
+		if r.Timestamp.Before(start) || (!end.IsZero() && !r.Timestamp.Before(end)) {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// Line is the total usage of one provider, model, and command.
+type Line struct {
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	Command      string  `json:"command"`
+	Calls        int     `json:"calls"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost_usd"`
+	// Priced is false when the model's price is unknown, so Cost is 0.
+	Priced bool `json:"priced"`
+	// Estimated is set if any of the token counts were estimated.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// Report totals records by provider, model, and command.
+type Report struct {
+	Lines        []Line  `json:"lines"`
+	Calls        int     `json:"calls"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost_usd"`
+	// Unpriced lists the models whose cost isn't included in Cost.
+	Unpriced []string `json:"unpriced,omitempty"`
+}
+
+// Summarize totals records, pricing them with prices.
+func Summarize(records []Record, prices Prices) *Report {
+	type key struct{ provider, model, command string }
+	lines := make(map[key]*Line)
+	for _, r := range records {
+		k := key{r.Provider, r.Model, r.Command}
+		l, ok := lines[k]
+		if !ok {
+			l = &Line{Provider: r.Provider, Model: r.Model, Command: r.Command}
+			lines[k] = l
+		}
+		l.Calls++
+		l.InputTokens += r.InputTokens
+		l.OutputTokens += r.OutputTokens
+		l.Estimated = l.Estimated || r.Estimated
+	}
+
+	report := &Report{Lines: []Line{}}
+	unpriced := make(map[string]bool)
+	for _, l := range lines {
+		l.Cost, l.Priced = prices.Cost(l.Provider, l.Model, l.InputTokens, l.OutputTokens)
+		if !l.Priced {
+			unpriced[l.Provider+"/"+l.Model] = true
+		}
+		report.Lines = append(report.Lines, *l)
+		report.Calls += l.Calls
+		report.InputTokens += l.InputTokens
+		report.OutputTokens += l.OutputTokens
+		report.Cost += l.Cost
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Command < b.Command
+	})
+	for m := range unpriced {
+		report.Unpriced = append(report.Unpriced, m)
+	}
+	sort.Strings(report.Unpriced)
+	return report
+}
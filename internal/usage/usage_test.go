@@ -0,0 +1,119 @@
+package usage
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klytics/m365kit/internal/cmdlog"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	cmdlog.SetCommand("kit ai summarize")
+	defer cmdlog.SetCommand("")
+
+	Append(path, Record{Provider: "anthropic", Model: "claude-sonnet-4-20250514", InputTokens: 1000, OutputTokens: 200})
+	Append(path, Record{Provider: "openai", Model: "gpt-4o", Command: "kit ai ask", InputTokens: 10, OutputTokens: 5, Estimated: true})
+
+	records, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].Command != "kit ai summarize" || records[0].Timestamp.IsZero() {
+		t.Errorf("record = %+v", records[0])
+	}
+	if records[1].Command != "kit ai ask" || !records[1].Estimated {
+		t.Errorf("record = %+v", records[1])
+	}
+
+	if records, err := Read(filepath.Join(t.TempDir(), "missing.jsonl")); err != nil || records != nil {
+		t.Errorf("missing ledger: %v, %v", records, err)
+	}
+}
+
+func TestPricesLookup(t *testing.T) {
+	tests := []struct {
+		model string
+		want  Price
+		ok    bool
+	}{
+		{"claude-sonnet-4-20250514", Price{3, 15}, true},
+		{"gpt-4o-mini-2024-07-18", Price{0.15, 0.6}, true}, // not gpt-4o
+		{"gpt-4o", Price{2.5, 10}, true},
+		{"gpt4o-prod", Price{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := DefaultPrices.Lookup(tt.model)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Lookup(%q) = %v, %v; want %v, %v", tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if cost, ok := DefaultPrices.Cost("ollama", "llama3.1", 1e6, 1e6); cost != 0 || !ok {
+		t.Errorf("ollama cost = %v, %v", cost, ok)
+	}
+	if cost, _ := DefaultPrices.Cost("anthropic", "claude-sonnet-4-20250514", 1e6, 1e5); math.Abs(cost-4.5) > 1e-9 {
+		t.Errorf("cost = %v, want 4.5", cost)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records := []Record{
+		{Provider: "anthropic", Model: "claude-sonnet-4", Command: "kit ai summarize", InputTokens: 1e6, OutputTokens: 0},
+		{Provider: "anthropic", Model: "claude-sonnet-4", Command: "kit ai summarize", InputTokens: 0, OutputTokens: 1e5, Estimated: true},
+		{Provider: "openai", Model: "gpt-4o-mini", Command: "kit ai ask", InputTokens: 1e6, OutputTokens: 0},
+		{Provider: "azure-openai", Model: "gpt4o-prod", Command: "kit ai ask", InputTokens: 500, OutputTokens: 50},
+	}
+	report := Summarize(records, DefaultPrices)
+	if len(report.Lines) != 3 || report.Calls != 4 {
+		t.Fatalf("report = %+v", report)
+	}
+	first := report.Lines[0]
+	if first.Model != "claude-sonnet-4" || first.Calls != 2 || !first.Estimated || math.Abs(first.Cost-4.5) > 1e-9 {
+		t.Errorf("first line = %+v", first)
+	}
+	if math.Abs(report.Cost-4.65) > 1e-9 {
+		t.Errorf("total cost = %v, want 4.65", report.Cost)
+	}
+	if len(report.Unpriced) != 1 || report.Unpriced[0] != "azure-openai/gpt4o-prod" {
+		t.Errorf("unpriced = %v", report.Unpriced)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Timestamp: time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC)},
+		{Timestamp: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{Timestamp: now},
+	}
+	if got := Between(records, MonthStart(now), time.Time{}); len(got) != 2 {
+		t.Errorf("this month: %d records, want 2", len(got))
+	}
+	if got := Between(records, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), MonthStart(now)); len(got) != 1 {
+		t.Errorf("last month: %d records, want 1", len(got))
+	}
+}
+
+func TestBudgetWarning(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	b := Budget{Monthly: 100, WarnAt: DefaultWarnAt}
+	if w := b.Warning(79, now); w != "" {
+		t.Errorf("warning below threshold: %q", w)
+	}
+	if w := b.Warning(85, now); !strings.Contains(w, "85% of the $100.00 monthly budget") {
+		t.Errorf("warning = %q", w)
+	}
+	if w := b.Warning(120, now); !strings.Contains(w, "over the $100.00 monthly budget") {
+		t.Errorf("warning = %q", w)
+	}
+	if w := (Budget{}).Warning(1e6, now); w != "" {
+		t.Errorf("warning without a budget: %q", w)
+	}
+}
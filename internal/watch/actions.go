@@ -0,0 +1,445 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/auth"
+	"github.com/klytics/m365kit/internal/formats/convert"
+	"github.com/klytics/m365kit/internal/graph"
+	tmpl "github.com/klytics/m365kit/internal/template"
+)
+
+// Action types. Options are strings; those named below as paths or
+// messages may use the file placeholders {path}, {dir}, {name}, {stem},
+// {ext}, {date}, and {time}.
+const (
+	ActionLog            = "log"             // log the match; no options
+	ActionTemplate       = "template"        // template (default: the file), values, var.<name>, output
+	ActionConvert        = "convert"         // to, output
+	ActionCopy           = "copy"            // dest, overwrite
+	ActionMove           = "move"            // dest, overwrite
+	ActionCommand        = "command"         // run, timeout
+	ActionTeamsPost      = "teams-post"      // team, channel, message, attach
+	ActionOneDriveUpload = "onedrive-upload" // dest
+)
+
+// actionSpec lists the options of an action type.
+type actionSpec struct {
+	required []string
+	optional []string
+	prefixes []string // option families, like var.<name>
+}
+
+var actionSpecs = map[string]actionSpec{
+	ActionLog:            {},
+	ActionTemplate:       {required: []string{"output"}, optional: []string{"template", "values"}, prefixes: []string{"var."}},
+	ActionConvert:        {required: []string{"to"}, optional: []string{"output"}},
+	ActionCopy:           {required: []string{"dest"}, optional: []string{"overwrite"}},
+	ActionMove:           {required: []string{"dest"}, optional: []string{"overwrite"}},
+	ActionCommand:        {required: []string{"run"}, optional: []string{"timeout"}},
+	ActionTeamsPost:      {required: []string{"team", "channel"}, optional: []string{"message", "attach"}},
+	ActionOneDriveUpload: {required: []string{"dest"}},
+}
+
+// ActionTypes returns the built-in action types, sorted.
+func ActionTypes() []string {
+	types := make([]string, 0, len(actionSpecs))
+	for t := range actionSpecs {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Validate checks the action's type and options.
+func (a Action) Validate() error {
+	spec, ok := actionSpecs[a.kind()]
+	if !ok {
+		return fmt.Errorf("unknown action type %q (use one of: %s)", a.Type, strings.Join(ActionTypes(), ", "))
+	}
+
+	var missing []string
+	for _, name := range spec.required {
+		if strings.TrimSpace(a.Options[name]) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s action needs option(s): %s", a.kind(), strings.Join(missing, ", "))
+	}
+	if a.Retries < 0 {
+		return fmt.Errorf("%s action: retries must not be negative", a.kind())
+	}
+	if a.Backoff != "" {
+		if d, err := time.ParseDuration(a.Backoff); err != nil || d < 0 {
+			return fmt.Errorf("%s action: backoff must be a duration like 5s", a.kind())
+		}
+	}
+
+	for name, value := range a.Options {
+		if !spec.allows(name) {
+			return fmt.Errorf("%s action has no option %q", a.kind(), name)
+		}
+		switch name {
+		case "overwrite", "attach":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("%s action: option %s must be true or false", a.kind(), name)
+			}
+		case "timeout":
+			if _, err := time.ParseDuration(value); err != nil {
+				return fmt.Errorf("%s action: option timeout must be a duration like 30s", a.kind())
+			}
+		}
+	}
+	return nil
+}
+
+func (s actionSpec) allows(name string) bool {
+	for _, n := range append(s.required, s.optional...) {
+		if n == name {
+			return true
+		}
+	}
+	for _, p := range s.prefixes {
+		if strings.HasPrefix(name, p) && len(name) > len(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// steps returns the actions rule runs, in order.
+func (r Rule) steps() []Action {
+	if len(r.Actions) > 0 {
+		return r.Actions
+	}
+	return []Action{r.Action}
+}
+
+// ActionNames names the actions rule runs, for events and logs.
+func (r Rule) ActionNames() string {
+	names := make([]string, len(r.steps()))
+	for i, a := range r.steps() {
+		names[i] = a.Name
+		if names[i] == "" {
+			names[i] = a.kind()
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// kind returns the action type, defaulting to log.
+func (a Action) kind() string {
+	if a.Type == "" {
+		return ActionLog
+	}
+	return a.Type
+}
+
+// graphClient signs in for the Graph actions; tests replace it.
+var graphClient = auth.RequireAuth
+
+// RunAction performs the action for the file at path and returns a short
+// description of what it did.
+func RunAction(ctx context.Context, a Action, path string) (string, error) {
+	did, _, err := performAction(ctx, a, path)
+	return did, err
+}
+
+// RunRule performs the actions of rule in order for the file at path,
+// stopping at the first that fails once its Retries are used up. Each
+// action works on the file the previous one produced: convert and template
+// pass on their output, and move the moved file; the others pass on the
+// file they were given.
+func RunRule(ctx context.Context, rule Rule, path string) ([]string, error) {
+	var done []string
+	for i, a := range rule.steps() {
+		did, out, err := performAction(ctx, a, path)
+		for attempt := 0; err != nil && attempt < a.Retries; attempt++ {
+			if !sleepCtx(ctx, a.backoff(attempt)) {
+				break
+			}
+			did, out, err = performAction(ctx, a, path)
+		}
+		if err != nil {
+			if len(rule.steps()) > 1 {
+				err = fmt.Errorf("step %d (%s): %w", i+1, a.kind(), err)
+			}
+			return done, err
+		}
+		done = append(done, did)
+		if out != "" {
+			path = out
+		}
+	}
+	return done, nil
+}
+
+// defaultBackoff is the wait before an action's first retry, and
+// maxBackoff caps the wait as it doubles.
+const (
+	defaultBackoff = 2 * time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
+// backoff returns the wait before retry number attempt+1 of a.
+func (a Action) backoff(attempt int) time.Duration {
+	d, err := time.ParseDuration(a.Backoff)
+	if err != nil || a.Backoff == "" {
+		d = defaultBackoff
+	}
+	return min(d<<min(attempt, 20), maxBackoff)
+}
+
+// sleepCtx waits for d, and reports false if ctx ends first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// performAction runs a and returns what it did and the file it produced,
+// if any.
+func performAction(ctx context.Context, a Action, path string) (did, out string, err error) {
+	if err := a.Validate(); err != nil {
+		return "", "", err
+	}
+	opt := func(name string) string { return expand(a.Options[name], path) }
+	flag := func(name string) bool { b, _ := strconv.ParseBool(a.Options[name]); return b }
+
+	switch a.kind() {
+	case ActionTemplate:
+		out := opt("output")
+		did, err := applyTemplate(a, path)
+		return did, out, err
+	case ActionConvert:
+		out := opt("output")
+		if out == "" {
+			out = strings.TrimSuffix(path, filepath.Ext(path)) + "." + a.Options["to"]
+		}
+		if _, err := convert.Convert(path, out, a.Options["to"]); err != nil {
+			return "", "", err
+		}
+		return "converted to " + out, out, nil
+	case ActionCopy, ActionMove:
+		dest, err := copyFile(path, opt("dest"), flag("overwrite"))
+		if err != nil {
+			return "", "", err
+		}
+		if a.kind() == ActionMove {
+			if err := os.Remove(path); err != nil {
+				return "", "", fmt.Errorf("copied to %s but could not remove the original: %w", dest, err)
+			}
+			return "moved to " + dest, dest, nil
+		}
+		return "copied to " + dest, "", nil
+	case ActionCommand:
+		return "ran command", "", runCommand(ctx, a, path)
+	case ActionTeamsPost:
+		did, err := postToTeams(ctx, a, path)
+		return did, "", err
+	case ActionOneDriveUpload:
+		client, err := graphClient(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		remote := strings.TrimSuffix(opt("dest"), "/") + "/" + filepath.Base(path)
+		if _, err := graph.NewOneDrive(client).UploadFile(ctx, path, strings.TrimPrefix(remote, "/")); err != nil {
+			return "", "", err
+		}
+		return "uploaded to onedrive:" + remote, "", nil
+	}
+	return "matched", "", nil
+}
+
+// expand replaces the file placeholders in s.
+func expand(s, path string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	return placeholders(path, func(v string) string { return v }).Replace(s)
+}
+
+func placeholders(path string, quote func(string) string) *strings.Replacer {
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	now := time.Now()
+	return strings.NewReplacer(
+		"{path}", quote(path),
+		"{dir}", quote(filepath.Dir(path)),
+		"{name}", quote(name),
+		"{stem}", quote(strings.TrimSuffix(name, ext)),
+		"{ext}", quote(strings.TrimPrefix(ext, ".")),
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("150405"),
+	)
+}
+
+// applyTemplate fills a template with the action's values. Without a
+// template option the watched file is the template; with one, a watched
+// .json file supplies values.
+func applyTemplate(a Action, path string) (string, error) {
+	values := make(map[string]string)
+	readValues := func(file string) error {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("%s must be a JSON object of strings: %w", filepath.Base(file), err)
+		}
+		return nil
+	}
+
+	templatePath := expand(a.Options["template"], path)
+	switch {
+	case templatePath == "":
+		templatePath = path
+	case strings.EqualFold(filepath.Ext(path), ".json"):
+		if err := readValues(path); err != nil {
+			return "", err
+		}
+	}
+	if _, err := os.Stat(templatePath); err != nil {
+		// Not a file: a template from the library
+		lib, err := tmpl.LoadLibrary(tmpl.DefaultLibraryDir())
+		if err != nil {
+			return "", err
+		}
+		t, err := lib.Get(templatePath)
+		if err != nil {
+			return "", err
+		}
+		templatePath = t.Path
+	}
+	if file := expand(a.Options["values"], path); file != "" {
+		if err := readValues(file); err != nil {
+			return "", err
+		}
+	}
+	for name, v := range a.Options {
+		if key, ok := strings.CutPrefix(name, "var."); ok {
+			values[key] = expand(v, path)
+		}
+	}
+
+	out := expand(a.Options["output"], path)
+	result, err := tmpl.Apply(templatePath, values, out)
+	if err != nil {
+		return "", err
+	}
+	if result.VariablesMissing > 0 {
+		return "", fmt.Errorf("template %s: no value for %s", filepath.Base(templatePath), strings.Join(result.MissingNames, ", "))
+	}
+	return "filled " + out, nil
+}
+
+// copyFile copies path to dest, a directory (existing, or ending in a
+// separator) or a file path, and returns the file written.
+func copyFile(path, dest string, overwrite bool) (string, error) {
+	if info, err := os.Stat(dest); (err == nil && info.IsDir()) || strings.HasSuffix(dest, "/") || strings.HasSuffix(dest, string(os.PathSeparator)) {
+		dest = filepath.Join(dest, filepath.Base(path))
+	}
+	if !overwrite {
+		if _, err := os.Stat(dest); err == nil {
+			return "", fmt.Errorf("%s already exists (set overwrite=true to replace it)", dest)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return "", err
+	}
+	return dest, out.Close()
+}
+
+// runCommand runs the action's command line through the shell, with the
+// file placeholders quoted for it. KIT_WATCH_PATH is set to the file.
+func runCommand(ctx context.Context, a Action, path string) error {
+	if d, err := time.ParseDuration(a.Options["timeout"]); err == nil && d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	line := placeholders(path, shellQuote).Replace(a.Options["run"])
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", line)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", line)
+	}
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), "KIT_WATCH_PATH="+path)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("command %q: %w", line, err)
+	}
+	return nil
+}
+
+// shellQuote quotes s as one word for sh, or cmd on Windows.
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func postToTeams(ctx context.Context, a Action, path string) (string, error) {
+	client, err := graphClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	tc := graph.NewTeams(client)
+	teamID, err := tc.ResolveTeamID(ctx, a.Options["team"])
+	if err != nil {
+		return "", err
+	}
+	channelID, err := tc.ResolveChannelID(ctx, teamID, a.Options["channel"])
+	if err != nil {
+		return "", err
+	}
+
+	message := expand(a.Options["message"], path)
+	if message == "" {
+		message = "New file: " + filepath.Base(path)
+	}
+	if attach, _ := strconv.ParseBool(a.Options["attach"]); attach {
+		_, err = tc.PostMessageWithFile(ctx, teamID, channelID, message, path)
+	} else {
+		_, err = tc.PostMessage(ctx, teamID, channelID, message)
+	}
+	if err != nil {
+		return "", err
+	}
+	return "posted to " + a.Options["team"] + "/" + a.Options["channel"], nil
+}
@@ -0,0 +1,308 @@
+package watch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestActionValidate(t *testing.T) {
+	tests := []struct {
+		action  Action
+		wantErr string
+	}{
+		{Action{}, ""},
+		{Action{Type: "log"}, ""},
+		{Action{Type: "convert", Options: map[string]string{"to": "md"}}, ""},
+		{Action{Type: "template", Options: map[string]string{"output": "out.docx", "var.client": "Contoso"}}, ""},
+		{Action{Type: "ai"}, `unknown action type "ai"`},
+		{Action{Type: "teams-post", Options: map[string]string{"team": "Eng"}}, "needs option(s): channel"},
+		{Action{Type: "copy", Options: map[string]string{"dest": "x", "to": "md"}}, `no option "to"`},
+		{Action{Type: "move", Options: map[string]string{"dest": "x", "overwrite": "maybe"}}, "overwrite must be true or false"},
+		{Action{Type: "command", Options: map[string]string{"run": "true", "timeout": "soon"}}, "timeout must be a duration"},
+		{Action{Type: "template", Options: map[string]string{"output": "o", "var.": "x"}}, `no option "var."`},
+		{Action{Type: "log", Retries: 3, Backoff: "500ms"}, ""},
+		{Action{Type: "log", Retries: -1}, "retries must not be negative"},
+		{Action{Type: "log", Retries: 1, Backoff: "later"}, "backoff must be a duration"},
+	}
+	for _, tt := range tests {
+		err := tt.action.Validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%+v: %v", tt.action, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%+v: err = %v, want %q", tt.action, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRunActionCopyAndMove(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in", "q1.docx")
+	os.MkdirAll(filepath.Dir(src), 0755)
+	os.WriteFile(src, []byte("report"), 0644)
+	ctx := context.Background()
+
+	copyTo := Action{Type: ActionCopy, Options: map[string]string{"dest": filepath.Join(dir, "archive", "{stem}-copy.{ext}")}}
+	if _, err := RunAction(ctx, copyTo, src); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "archive", "q1-copy.docx")); string(data) != "report" {
+		t.Errorf("copy = %q", data)
+	}
+	if _, err := RunAction(ctx, copyTo, src); err == nil || !strings.Contains(err.Error(), "overwrite=true") {
+		t.Errorf("second copy: %v", err)
+	}
+
+	done := filepath.Join(dir, "done")
+	os.Mkdir(done, 0755)
+	did, err := RunAction(ctx, Action{Type: ActionMove, Options: map[string]string{"dest": done}}, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if did != "moved to "+filepath.Join(done, "q1.docx") {
+		t.Errorf("did = %q", did)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("move should remove the original")
+	}
+}
+
+func TestWatcherRunsAction(t *testing.T) {
+	dir, dest := t.TempDir(), t.TempDir()
+	w, err := New(WatchConfig{
+		Directories: []string{dir},
+		Rules: []Rule{{
+			ID:         "notes",
+			Extensions: []string{"md"},
+			Action:     Action{Type: ActionCopy, Options: map[string]string{"dest": dest}},
+			Enabled:    true,
+		}},
+		Debounce: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Log = NewEventLog(t.TempDir())
+	events := make(chan Event, 1)
+	w.OnEvent = func(e Event) { events <- e }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Start(ctx)
+	time.Sleep(100 * time.Millisecond)
+
+	os.WriteFile(filepath.Join(dir, "todo.md"), []byte("- ship"), 0644)
+	select {
+	case e := <-events:
+		if e.Status != "processed" {
+			t.Fatalf("event = %+v", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for the action")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "todo.md")); string(data) != "- ship" {
+		t.Errorf("copied = %q", data)
+	}
+	if logged, _ := w.Log.Read(); len(logged) != 1 || logged[0].RuleID != "notes" {
+		t.Errorf("logged = %+v", logged)
+	}
+}
+
+func TestRunActionConvertAndTemplate(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	md := filepath.Join(dir, "notes.md")
+	os.WriteFile(md, []byte("# Notes\n\nHello.\n"), 0644)
+	if _, err := RunAction(ctx, Action{Type: ActionConvert, Options: map[string]string{"to": "docx"}}, md); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.docx")); err != nil {
+		t.Errorf("converted file: %v", err)
+	}
+
+	// A watched .json file fills the template
+	tpl := filepath.Join(dir, "letter.md")
+	os.WriteFile(tpl, []byte("Dear {{client}}, from {{sender}}."), 0644)
+	data := filepath.Join(dir, "acme.json")
+	os.WriteFile(data, []byte(`{"client": "Acme"}`), 0644)
+	fill := Action{Type: ActionTemplate, Options: map[string]string{
+		"template":   tpl,
+		"output":     filepath.Join(dir, "out", "{stem}.md"),
+		"var.sender": "Contoso",
+	}}
+	if _, err := RunAction(ctx, fill, data); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "out", "acme.md")); string(got) != "Dear Acme, from Contoso." {
+		t.Errorf("filled = %q", got)
+	}
+
+	delete(fill.Options, "var.sender")
+	if _, err := RunAction(ctx, fill, data); err == nil || !strings.Contains(err.Error(), "no value for sender") {
+		t.Errorf("missing value: %v", err)
+	}
+}
+
+func TestRunActionCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test command needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "it's here.docx")
+	os.WriteFile(src, nil, 0644)
+	out := filepath.Join(dir, "out.txt")
+
+	a := Action{Type: ActionCommand, Options: map[string]string{"run": "echo {name} $KIT_WATCH_PATH > " + out}}
+	if _, err := RunAction(context.Background(), a, src); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != "it's here.docx "+src+"\n" {
+		t.Errorf("output = %q", got)
+	}
+
+	a.Options["run"] = "exit 3"
+	if _, err := RunAction(context.Background(), a, src); err == nil {
+		t.Error("expected an error for a failing command")
+	}
+}
+
+func TestRunRuleRetries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test command needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "a.docx")
+	os.WriteFile(src, nil, 0644)
+	marker := filepath.Join(dir, "tried")
+
+	// Fails the first time, succeeds the second
+	a := Action{Type: ActionCommand, Options: map[string]string{"run": "test -f " + marker + " || { touch " + marker + "; exit 1; }"}, Backoff: "1ms"}
+	if _, err := RunRule(context.Background(), Rule{Action: a}, src); err == nil {
+		t.Fatal("expected an error without retries")
+	}
+	os.Remove(marker)
+	a.Retries = 2
+	if _, err := RunRule(context.Background(), Rule{Action: a}, src); err != nil {
+		t.Errorf("with retries: %v", err)
+	}
+
+	if got := (Action{}).backoff(0); got != defaultBackoff {
+		t.Errorf("default backoff = %s", got)
+	}
+	if got := (Action{Backoff: "1s"}).backoff(3); got != 8*time.Second {
+		t.Errorf("fourth backoff = %s, want 8s", got)
+	}
+	if got := (Action{Backoff: "1m"}).backoff(30); got != maxBackoff {
+		t.Errorf("backoff = %s, want the cap", got)
+	}
+}
+
+// graphServer points the Graph actions at a test server and records the
+// requests it gets.
+func graphServer(t *testing.T) *[]string {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		reqs []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, r.Method+" "+r.URL.EscapedPath()+" "+string(body))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	old := graphClient
+	graphClient = func(context.Context) (*http.Client, error) {
+		return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+			return http.DefaultTransport.RoundTrip(r)
+		})}, nil
+	}
+	t.Cleanup(func() { graphClient = old })
+	return &reqs
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestRunActionGraph(t *testing.T) {
+	t.Setenv("KIT_AZURE_CLOUD", "")
+	reqs := graphServer(t)
+	src := filepath.Join(t.TempDir(), "scan.pdf")
+	os.WriteFile(src, []byte("%PDF"), 0644)
+	ctx := context.Background()
+
+	team, channel := "11111111-2222-3333-4444-555555555555", "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
+	post := Action{Type: ActionTeamsPost, Options: map[string]string{"team": team, "channel": channel, "message": "Filed {name}"}}
+	if _, err := RunAction(ctx, post, src); err != nil {
+		t.Fatal(err)
+	}
+	upload := Action{Type: ActionOneDriveUpload, Options: map[string]string{"dest": "/Scans"}}
+	if _, err := RunAction(ctx, upload, src); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*reqs) != 2 {
+		t.Fatalf("requests = %q", *reqs)
+	}
+	if r := (*reqs)[0]; !strings.HasPrefix(r, "POST /v1.0/teams/"+team+"/channels/"+channel+"/messages") || !strings.Contains(r, "Filed scan.pdf") {
+		t.Errorf("post = %q", r)
+	}
+	if r := (*reqs)[1]; !strings.HasPrefix(r, "PUT /v1.0/me/drive/root:/Scans") || !strings.HasSuffix(r, "%PDF") {
+		t.Errorf("upload = %q", r)
+	}
+}
+
+func TestRunRuleChain(t *testing.T) {
+	dir := t.TempDir()
+	md := filepath.Join(dir, "notes.md")
+	os.WriteFile(md, []byte("# Notes\n"), 0644)
+	archive := filepath.Join(dir, "archive") + string(os.PathSeparator)
+
+	// The move gets the converted file, and the copy the moved one
+	rule := Rule{ID: "chain", Actions: []Action{
+		{Type: ActionConvert, Options: map[string]string{"to": "docx"}},
+		{Type: ActionMove, Options: map[string]string{"dest": archive}},
+		{Type: ActionCopy, Options: map[string]string{"dest": filepath.Join(dir, "{name}.bak")}},
+	}}
+	done, err := RunRule(context.Background(), rule, md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 3 {
+		t.Errorf("done = %q", done)
+	}
+	for _, want := range []string{"archive/notes.docx", "notes.docx.bak", "notes.md"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(want))); err != nil {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if got := rule.ActionNames(); got != "convert,move,copy" {
+		t.Errorf("ActionNames = %q", got)
+	}
+
+	// A failing step stops the chain
+	rule.Actions[0].Options["to"] = "bogus"
+	done, err = RunRule(context.Background(), rule, md)
+	if err == nil || !strings.HasPrefix(err.Error(), "step 1 (convert)") || len(done) != 0 {
+		t.Errorf("done = %q, err = %v", done, err)
+	}
+}
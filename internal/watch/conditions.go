@@ -0,0 +1,117 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/klytics/m365kit/internal/formats/convert"
+)
+
+// ByteSize is a file size in bytes. In JSON it is a number of bytes or a
+// string such as "50KB" or "2.5MB" (see ParseSize).
+type ByteSize int64
+
+// UnmarshalJSON accepts a number of bytes or a size string.
+func (s *ByteSize) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*s = ByteSize(n)
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("size must be a number of bytes or a string like \"50KB\"")
+	}
+	n, err := ParseSize(str)
+	if err != nil {
+		return err
+	}
+	*s = ByteSize(n)
+	return nil
+}
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  float64
+}{
+	{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"B", 1},
+}
+
+// ParseSize parses a size such as "512", "50KB", "2.5MB", or "1G". Units
+// are binary (1KB = 1024 bytes) and case-insensitive.
+func ParseSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	mult := 1.0
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(str, u.suffix) {
+			str, mult = strings.TrimSpace(strings.TrimSuffix(str, u.suffix)), u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 512, 50KB, 2.5MB)", s)
+	}
+	return int64(n * mult), nil
+}
+
+// validateConditions checks the size bounds and the name pattern of r.
+func (r Rule) validateConditions() error {
+	if r.MinSize < 0 || r.MaxSize < 0 {
+		return fmt.Errorf("sizes cannot be negative")
+	}
+	if r.MaxSize > 0 && r.MinSize > r.MaxSize {
+		return fmt.Errorf("minSize (%d) is larger than maxSize (%d)", r.MinSize, r.MaxSize)
+	}
+	if r.Name != "" {
+		if _, err := regexp.Compile(r.Name); err != nil {
+			return fmt.Errorf("invalid name pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// matchesConditions reports whether the file at path meets r's size, name,
+// and content conditions. The document is only read when r has a Contains
+// condition and everything else matched.
+func (r Rule) matchesConditions(path string) (bool, error) {
+	if r.MinSize > 0 || r.MaxSize > 0 {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		if info.Size() < int64(r.MinSize) || (r.MaxSize > 0 && info.Size() > int64(r.MaxSize)) {
+			return false, nil
+		}
+	}
+	if r.Name != "" {
+		if ok, _ := regexp.MatchString(r.Name, filepath.Base(path)); !ok {
+			return false, nil
+		}
+	}
+	if r.Contains != "" {
+		text, err := documentText(path)
+		if err != nil {
+			return false, fmt.Errorf("could not read %s for its contains condition: %w", filepath.Base(path), err)
+		}
+		if !strings.Contains(strings.ToLower(text), strings.ToLower(r.Contains)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// documentText returns the text of a document for Contains conditions:
+// Word, Excel, PowerPoint, and HTML files are parsed, text files read as
+// they are.
+func documentText(path string) (string, error) {
+	if convert.CanPreview(path) {
+		return convert.FileToMarkdown(path)
+	}
+	return "", fmt.Errorf("cannot read text from %s files", filepath.Ext(path))
+}
@@ -0,0 +1,97 @@
+package watch
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klytics/m365kit/internal/formats/convert"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"512", 512},
+		{"512B", 512},
+		{"50KB", 50 * 1024},
+		{"50k", 50 * 1024},
+		{"2.5MB", 2.5 * 1024 * 1024},
+		{"1 GB", 1 << 30},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "KB", "-1", "ten"} {
+		if _, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q) should fail", bad)
+		}
+	}
+
+	var r Rule
+	if err := json.Unmarshal([]byte(`{"minSize": "50KB", "maxSize": 1000000}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.MinSize != 50*1024 || r.MaxSize != 1000000 {
+		t.Errorf("sizes = %d, %d", r.MinSize, r.MaxSize)
+	}
+}
+
+func TestRuleConditionsValidate(t *testing.T) {
+	bad := []Rule{
+		{ID: "r", MinSize: 100, MaxSize: 10},
+		{ID: "r", Name: "report_(\\d+"},
+	}
+	for _, r := range bad {
+		if err := (WatchConfig{Rules: []Rule{r}}).Validate(); err == nil {
+			t.Errorf("%+v should not validate", r)
+		}
+	}
+	if err := (WatchConfig{Rules: []Rule{{ID: "r", MinSize: 10, Name: "^inv"}}}).Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMatchesRuleConditions(t *testing.T) {
+	w, _ := New(WatchConfig{})
+	defer w.watcher.Close()
+	dir := t.TempDir()
+
+	invoice := filepath.Join(dir, "inv_001.docx")
+	if err := convert.MarkdownToDocx("# INVOICE\n\nTotal due: 1,200 EUR\n\n"+strings.Repeat("Line item.\n\n", 200), invoice); err != nil {
+		t.Fatal(err)
+	}
+	memo := filepath.Join(dir, "memo.docx")
+	if err := convert.MarkdownToDocx("# Memo\n\nLunch is at noon.\n", memo); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(invoice)
+
+	tests := []struct {
+		name string
+		rule Rule
+		path string
+		want bool
+	}{
+		{"contains", Rule{Contains: "invoice"}, invoice, true},
+		{"does not contain", Rule{Contains: "invoice"}, memo, false},
+		{"min size", Rule{MinSize: ByteSize(info.Size())}, invoice, true},
+		{"below min size", Rule{MinSize: ByteSize(info.Size() + 1)}, invoice, false},
+		{"above max size", Rule{MaxSize: ByteSize(info.Size() - 1)}, invoice, false},
+		{"name", Rule{Name: `^inv_\d+\.docx$`}, invoice, true},
+		{"other name", Rule{Name: `^inv_\d+\.docx$`}, memo, false},
+		{"all", Rule{Extensions: []string{"docx"}, Contains: "INVOICE", MinSize: 1024, Name: "^inv"}, invoice, true},
+		{"unreadable", Rule{Contains: "x"}, filepath.Join(dir, "scan.pdf"), false},
+	}
+	for _, tt := range tests {
+		if got := w.matchesRule(tt.path, tt.rule); got != tt.want {
+			t.Errorf("%s: matchesRule = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// ControlSocket returns the path of the socket a running watcher answers
+// status and stop requests on.
+func ControlSocket(dir string) string {
+	return filepath.Join(dir, "watch.sock")
+}
+
+// LogFile returns the path a background watcher logs to.
+func LogFile(dir string) string {
+	return filepath.Join(dir, "watch.log")
+}
+
+// ServeControl answers status and stop requests on the control socket in
+// dir until ctx is done. A stop request calls stop.
+func (w *Watcher) ServeControl(ctx context.Context, dir string, stop func()) error {
+	if st, err := QueryStatus(dir); err == nil {
+		return fmt.Errorf("a watcher is already running (PID %d)", st.PID)
+	}
+	path := ControlSocket(dir)
+	os.Remove(path) // left behind by a watcher that didn't shut down
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("could not open control socket: %w", err)
+	}
+	defer os.Remove(path)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(w.GetStatus())
+	})
+	mux.HandleFunc("/stop", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(rw, "use POST", http.StatusMethodNotAllowed)
+			return
+		}
+		rw.WriteHeader(http.StatusAccepted)
+		w.Logger.Println("Stop requested")
+		go stop()
+	})
+
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// controlClient talks to the watcher whose control socket is in dir.
+func controlClient(dir string) *http.Client {
+	return &http.Client{
+		Timeout: 3 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", ControlSocket(dir))
+			},
+		},
+	}
+}
+
+// QueryStatus asks the watcher running from dir for its live status. It
+// returns an error when no watcher answers.
+func QueryStatus(dir string) (*Status, error) {
+	resp, err := controlClient(dir).Get("http://kit-watch/status")
+	if err != nil {
+		return nil, fmt.Errorf("no watcher running: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("watcher status failed (HTTP %d)", resp.StatusCode)
+	}
+	var st Status
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return nil, fmt.Errorf("invalid watcher status: %w", err)
+	}
+	return &st, nil
+}
+
+// RequestStop asks the watcher running from dir to stop, and waits up to
+// timeout for it to close its control socket.
+func RequestStop(dir string, timeout time.Duration) error {
+	resp, err := controlClient(dir).Post("http://kit-watch/stop", "", nil)
+	if err != nil {
+		return fmt.Errorf("no watcher running: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("watcher stop failed (HTTP %d)", resp.StatusCode)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, err := QueryStatus(dir); err != nil {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("watcher did not stop within %s", timeout)
+}
+
+// Detach runs kit with args as a background process, detached from the
+// terminal and logging to LogFile(dir). It returns the process ID once
+// the new watcher answers on its control socket.
+func Detach(dir string, args []string) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("could not locate kit: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	logPath := LogFile(dir)
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("could not open log: %w", err)
+	}
+	defer logFile.Close()
+
+	c := exec.Command(exe, args...)
+	c.Stdout = logFile
+	c.Stderr = logFile
+	c.SysProcAttr = detachAttr()
+	if err := c.Start(); err != nil {
+		return 0, fmt.Errorf("could not start watcher: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- c.Wait() }()
+
+	deadline := time.After(10 * time.Second)
+	for {
+		if st, err := QueryStatus(dir); err == nil && st.PID == c.Process.Pid {
+			return st.PID, nil
+		}
+		select {
+		case err := <-exited:
+			return 0, fmt.Errorf("watcher exited during startup (%v); see %s", err, logPath)
+		case <-deadline:
+			return c.Process.Pid, fmt.Errorf("watcher (PID %d) did not report in; see %s", c.Process.Pid, logPath)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestControlSocket(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := QueryStatus(dir); err == nil {
+		t.Fatal("expected an error with no watcher running")
+	}
+
+	w, _ := New(WatchConfig{Directories: []string{"/data/in"}, Rules: []Rule{{ID: "r1"}}, Recursive: true})
+	defer w.watcher.Close()
+	w.Events = []Event{{Path: "/data/in/a.docx", Status: "processed"}, {Path: "/data/in/b.docx", Status: "error"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error, 1)
+	go func() { served <- w.ServeControl(ctx, dir, cancel) }()
+
+	var st *Status
+	var err error
+	for i := 0; i < 50; i++ {
+		if st, err = QueryStatus(dir); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.PID != os.Getpid() || !st.Recursive || st.EventCount != 2 || st.ErrorCount != 1 || st.LastEvent == nil || st.LastEvent.Path != "/data/in/b.docx" {
+		t.Errorf("status = %+v", st)
+	}
+
+	// A second watcher must not take over the socket
+	if err := w.ServeControl(context.Background(), dir, func() {}); err == nil {
+		t.Error("expected an error for a second watcher")
+	}
+
+	if err := RequestStop(dir, 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Error("stop should call the stop function")
+	}
+	if err := <-served; err != nil {
+		t.Errorf("ServeControl = %v", err)
+	}
+	if _, err := os.Stat(ControlSocket(dir)); !os.IsNotExist(err) {
+		t.Error("control socket should be removed")
+	}
+}
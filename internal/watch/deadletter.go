@@ -0,0 +1,144 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DeadLetter is a file whose rule failed, even after the retries of its
+// actions, and that is waiting to be reprocessed.
+type DeadLetter struct {
+	Path     string    `json:"path"`
+	Source   string    `json:"source,omitempty"`
+	RuleID   string    `json:"ruleId"`
+	Action   string    `json:"action,omitempty"`
+	Error    string    `json:"error"`
+	Failures int       `json:"failures"` // times the file has failed, counting reprocessing
+	FirstAt  time.Time `json:"firstFailedAt"`
+	LastAt   time.Time `json:"lastFailedAt"`
+}
+
+// DeadLetters is the list of failed files, kept as JSON. A file is in it
+// once per rule.
+type DeadLetters struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewDeadLetters returns the dead-letter list in dir (watch-failed.json).
+func NewDeadLetters(dir string) *DeadLetters {
+	return &DeadLetters{Path: filepath.Join(dir, "watch-failed.json")}
+}
+
+// Load returns the failed files, oldest first. A missing file means none.
+func (d *DeadLetters) Load() ([]DeadLetter, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.load()
+}
+
+func (d *DeadLetters) load() ([]DeadLetter, error) {
+	data, err := os.ReadFile(d.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []DeadLetter
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid dead-letter file %s: %w", d.Path, err)
+	}
+	return list, nil
+}
+
+func (d *DeadLetters) save(list []DeadLetter) error {
+	if err := os.MkdirAll(filepath.Dir(d.Path), 0755); err != nil {
+		return err
+	}
+	if list == nil {
+		list = []DeadLetter{}
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := d.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.Path)
+}
+
+// Add records the failed event e, or counts another failure when its file
+// and rule are already listed.
+func (d *DeadLetters) Add(e Event) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	list, err := d.load()
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		if list[i].Path == e.Path && list[i].RuleID == e.RuleID {
+			list[i].Error, list[i].LastAt = e.Error, e.Time
+			list[i].Failures++
+			return d.save(list)
+		}
+	}
+	list = append(list, DeadLetter{
+		Path:     e.Path,
+		Source:   e.Source,
+		RuleID:   e.RuleID,
+		Action:   e.Action,
+		Error:    e.Error,
+		Failures: 1,
+		FirstAt:  e.Time,
+		LastAt:   e.Time,
+	})
+	return d.save(list)
+}
+
+// Remove drops the file of dl from the list.
+func (d *DeadLetters) Remove(dl DeadLetter) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	list, err := d.load()
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		if list[i].Path == dl.Path && list[i].RuleID == dl.RuleID {
+			return d.save(append(list[:i], list[i+1:]...))
+		}
+	}
+	return nil
+}
+
+// Reprocess runs the rule of dl in config again. A file that succeeds
+// leaves the list; one that fails again has its failure counted. It
+// returns the resulting event.
+func (d *DeadLetters) Reprocess(ctx context.Context, config WatchConfig, dl DeadLetter) (Event, error) {
+	evt := Retry(ctx, config, Event{Path: dl.Path, RuleID: dl.RuleID, Action: dl.Action})
+	evt.Source = dl.Source
+	if evt.Status == "error" {
+		return evt, d.Add(evt)
+	}
+	return evt, d.Remove(dl)
+}
+
+// deadLetter adds the failed event to w.Failed, if set.
+func (w *Watcher) deadLetter(evt Event) {
+	if w.Failed == nil {
+		return
+	}
+	if err := w.Failed.Add(evt); err != nil {
+		w.Logger.Printf("Dead-letter list: %v", err)
+	}
+}
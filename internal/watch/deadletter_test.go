@@ -0,0 +1,58 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeadLetters(t *testing.T) {
+	dir := t.TempDir()
+	failed := NewDeadLetters(dir)
+
+	w, err := New(WatchConfig{Rules: []Rule{{ID: "r1", Extensions: []string{".docx"}, Enabled: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.watcher.Close()
+	w.Failed = failed
+	w.Handler = func(path string, rule Rule) error { return errors.New("graph is down") }
+
+	src := filepath.Join(dir, "a.docx")
+	os.WriteFile(src, nil, 0644)
+	w.dispatch(src, "CREATE", "")
+	waitIdle(t, w)
+	w.dispatch(src, "WRITE", "")
+	waitIdle(t, w)
+
+	list, err := failed.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Path != src || list[0].RuleID != "r1" || list[0].Failures != 2 || list[0].Error != "graph is down" {
+		t.Fatalf("dead letters = %+v", list)
+	}
+
+	// Reprocessing with a rule that now works takes the file off the list
+	config := WatchConfig{Rules: []Rule{{ID: "r1", Action: Action{Type: ActionCopy, Options: map[string]string{"dest": filepath.Join(dir, "out") + "/"}}}}}
+	evt, err := failed.Reprocess(context.Background(), config, list[0])
+	if err != nil || evt.Status != "processed" {
+		t.Fatalf("reprocess = %+v, %v", evt, err)
+	}
+	if list, _ := failed.Load(); len(list) != 0 {
+		t.Errorf("after reprocessing: %+v", list)
+	}
+
+	// One that still fails stays, with the failure counted
+	failed.Add(Event{Time: time.Now(), Path: src, RuleID: "gone", Status: "error", Error: "x"})
+	list, _ = failed.Load()
+	if evt, _ := failed.Reprocess(context.Background(), config, list[0]); evt.Status != "error" {
+		t.Errorf("reprocess of a removed rule = %+v", evt)
+	}
+	if list, _ := failed.Load(); len(list) != 1 || list[0].Failures != 2 {
+		t.Errorf("after failed reprocessing: %+v", list)
+	}
+}
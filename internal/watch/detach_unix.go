@@ -0,0 +1,11 @@
+//go:build !windows
+
+package watch
+
+import "syscall"
+
+// detachAttr starts the background watcher in its own session, so it
+// outlives the terminal that started it.
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
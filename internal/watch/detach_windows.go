@@ -0,0 +1,12 @@
+package watch
+
+import "syscall"
+
+// detachedProcess is DETACHED_PROCESS: the watcher gets no console.
+const detachedProcess = 0x00000008
+
+// detachAttr starts the background watcher without a console, in its own
+// process group, so it outlives the terminal that started it.
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -0,0 +1,177 @@
+package watch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventLog is the persistent record of watch events, one JSON object per
+// line. When the file passes MaxSize it moves to <path>.1 (and older
+// files to .2, .3, ...), keeping Backups old files.
+type EventLog struct {
+	Path    string
+	MaxSize int64 // default 5MB
+	Backups int   // default 3
+
+	mu sync.Mutex
+}
+
+// NewEventLog returns the event log in dir (watch-events.jsonl).
+func NewEventLog(dir string) *EventLog {
+	return &EventLog{
+		Path:    filepath.Join(dir, "watch-events.jsonl"),
+		MaxSize: 5 * 1024 * 1024,
+		Backups: 3,
+	}
+}
+
+// Append adds e to the log, rotating it first when it is full.
+func (l *EventLog) Append(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0755); err != nil {
+		return err
+	}
+	if info, err := os.Stat(l.Path); err == nil && l.MaxSize > 0 && info.Size()+int64(len(data)) > l.MaxSize {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("could not rotate event log: %w", err)
+		}
+	}
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return err
+}
+
+func (l *EventLog) rotate() error {
+	if l.Backups <= 0 {
+		return os.Truncate(l.Path, 0)
+	}
+	os.Remove(l.backup(l.Backups))
+	for i := l.Backups - 1; i >= 1; i-- {
+		if err := os.Rename(l.backup(i), l.backup(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(l.Path, l.backup(1))
+}
+
+func (l *EventLog) backup(n int) string {
+	return fmt.Sprintf("%s.%d", l.Path, n)
+}
+
+// Read returns the logged events, oldest first, including those in
+// rotated files. Lines that don't parse are skipped.
+func (l *EventLog) Read() ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var events []Event
+	for n := l.Backups; n >= 0; n-- {
+		path := l.Path
+		if n > 0 {
+			path = l.backup(n)
+		}
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 64*1024), 1024*1024)
+		for sc.Scan() {
+			var e Event
+			if json.Unmarshal(sc.Bytes(), &e) == nil {
+				events = append(events, e)
+			}
+		}
+		f.Close()
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// EventFilter selects logged events. Zero fields match everything.
+type EventFilter struct {
+	Since  time.Time
+	Status string // "processed", "error", or "skipped"
+	RuleID string
+	Path   string // substring of the file path
+}
+
+// FilterEvents returns the events that match f.
+func FilterEvents(events []Event, f EventFilter) []Event {
+	var out []Event
+	for _, e := range events {
+		switch {
+		case !f.Since.IsZero() && e.Time.Before(f.Since):
+		case f.Status != "" && e.Status != f.Status:
+		case f.RuleID != "" && e.RuleID != f.RuleID:
+		case f.Path != "" && !strings.Contains(e.Path, f.Path):
+		default:
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Unresolved returns the failed events that no later event for the same
+// file and rule has processed, once per file and rule.
+func Unresolved(events []Event) []Event {
+	latest := make(map[string]int)
+	for i, e := range events {
+		if e.Status == "error" || e.Status == "processed" {
+			latest[e.RuleID+"\x00"+e.Path] = i
+		}
+	}
+	var out []Event
+	for i, e := range events {
+		if e.Status == "error" && latest[e.RuleID+"\x00"+e.Path] == i {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Retry runs the actions of e's rule in config again for e's file, and
+// returns the resulting event.
+func Retry(ctx context.Context, config WatchConfig, e Event) Event {
+	evt := Event{
+		Time:      time.Now(),
+		Path:      e.Path,
+		Operation: "retry",
+		RuleID:    e.RuleID,
+		Action:    e.Action,
+		Status:    "processed",
+	}
+	for _, rule := range config.Rules {
+		if rule.ID == e.RuleID {
+			if _, err := RunRule(ctx, rule, e.Path); err != nil {
+				evt.Status, evt.Error = "error", err.Error()
+			}
+			return evt
+		}
+	}
+	evt.Status, evt.Error = "error", fmt.Sprintf("rule %s is no longer configured", e.RuleID)
+	return evt
+}
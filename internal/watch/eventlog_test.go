@@ -0,0 +1,85 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventLogRotation(t *testing.T) {
+	l := NewEventLog(t.TempDir())
+	l.MaxSize, l.Backups = 200, 2
+
+	for i := 0; i < 10; i++ {
+		if err := l.Append(Event{Path: "/in/" + string(rune('a'+i)) + ".docx", Status: "processed"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(l.Path + ".2"); err != nil {
+		t.Errorf("expected two rotated files: %v", err)
+	}
+	if _, err := os.Stat(l.Path + ".3"); !os.IsNotExist(err) {
+		t.Error("only Backups rotated files should be kept")
+	}
+
+	events, err := l.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 || len(events) >= 10 {
+		t.Fatalf("read %d events, want some but not all", len(events))
+	}
+	if last := events[len(events)-1].Path; last != "/in/j.docx" {
+		t.Errorf("last event = %s, want the newest", last)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Path <= events[i-1].Path {
+			t.Errorf("events out of order: %s after %s", events[i].Path, events[i-1].Path)
+		}
+	}
+}
+
+func TestFilterAndUnresolved(t *testing.T) {
+	now := time.Now()
+	events := []Event{
+		{Time: now.Add(-3 * time.Hour), Path: "/in/a.docx", RuleID: "r1", Status: "error"},
+		{Time: now.Add(-2 * time.Hour), Path: "/in/b.docx", RuleID: "r1", Status: "error"},
+		{Time: now.Add(-90 * time.Minute), Path: "/in/a.docx", RuleID: "r1", Status: "processed", Operation: "retry"},
+		{Time: now.Add(-30 * time.Minute), Path: "/in/c.pdf", RuleID: "r2", Status: "error"},
+		{Time: now.Add(-10 * time.Minute), Path: "/in/c.pdf", RuleID: "r2", Status: "error", Operation: "retry"},
+	}
+
+	if got := FilterEvents(events, EventFilter{Since: now.Add(-time.Hour), Status: "error"}); len(got) != 2 {
+		t.Errorf("since 1h errors = %+v", got)
+	}
+	if got := FilterEvents(events, EventFilter{RuleID: "r1", Path: "a.docx"}); len(got) != 2 {
+		t.Errorf("r1 a.docx = %+v", got)
+	}
+
+	got := Unresolved(events)
+	if len(got) != 2 || got[0].Path != "/in/b.docx" || got[1].Operation != "retry" {
+		t.Errorf("unresolved = %+v", got)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "a.docx")
+	os.WriteFile(src, []byte("x"), 0644)
+	config := WatchConfig{Rules: []Rule{{ID: "r1", Action: Action{Name: "copy", Type: ActionCopy, Options: map[string]string{"dest": filepath.Join(dir, "out") + "/"}}}}}
+
+	evt := Retry(context.Background(), config, Event{Path: src, RuleID: "r1", Action: "copy", Status: "error"})
+	if evt.Status != "processed" || evt.Operation != "retry" {
+		t.Errorf("retry = %+v", evt)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out", "a.docx")); err != nil {
+		t.Error(err)
+	}
+
+	evt = Retry(context.Background(), config, Event{Path: src, RuleID: "gone", Status: "error"})
+	if evt.Status != "error" || evt.Error != "rule gone is no longer configured" {
+		t.Errorf("retry of a removed rule = %+v", evt)
+	}
+}
@@ -0,0 +1,297 @@
+package watch
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+// MailFilter selects which new messages trigger a mail watch. All of its
+// terms must match.
+type MailFilter struct {
+	From          []string `json:"from,omitempty"`    // Substrings of the sender's address or name
+	Subject       []string `json:"subject,omitempty"` // Substrings of the subject
+	HasAttachment bool     `json:"hasAttachment,omitempty"`
+	Unread        bool     `json:"unread,omitempty"`
+}
+
+// ParseMailFilter parses search-style terms such as
+// `from:alerts@ subject:"daily report" has:attachment is:unread`.
+// Bare words match the subject.
+func ParseMailFilter(exprs []string) (MailFilter, error) {
+	var f MailFilter
+	for _, expr := range exprs {
+		for _, term := range splitTerms(expr) {
+			key, value, ok := strings.Cut(term, ":")
+			if !ok {
+				f.Subject = append(f.Subject, strings.ToLower(term))
+				continue
+			}
+			value = strings.ToLower(value)
+			switch strings.ToLower(key) {
+			case "from":
+				f.From = append(f.From, value)
+			case "subject":
+				f.Subject = append(f.Subject, value)
+			case "has":
+				if value != "attachment" && value != "attachments" {
+					return f, fmt.Errorf("unknown filter %q (use has:attachment)", term)
+				}
+				f.HasAttachment = true
+			case "is":
+				if value != "unread" {
+					return f, fmt.Errorf("unknown filter %q (use is:unread)", term)
+				}
+				f.Unread = true
+			default:
+				return f, fmt.Errorf("unknown filter %q (use from:, subject:, has:attachment, or is:unread)", term)
+			}
+		}
+	}
+	return f, nil
+}
+
+// splitTerms splits on spaces, keeping double-quoted text together.
+func splitTerms(s string) []string {
+	var terms []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if cur.Len() > 0 {
+				terms = append(terms, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		terms = append(terms, cur.String())
+	}
+	return terms
+}
+
+// Matches reports whether msg satisfies every term of the filter.
+func (f MailFilter) Matches(msg graph.EmailMessage) bool {
+	from := strings.ToLower(msg.From.EmailAddress.Address + " " + msg.From.EmailAddress.Name)
+	for _, s := range f.From {
+		if !strings.Contains(from, s) {
+			return false
+		}
+	}
+	subject := strings.ToLower(msg.Subject)
+	for _, s := range f.Subject {
+		if !strings.Contains(subject, s) {
+			return false
+		}
+	}
+	if f.HasAttachment && !msg.HasAttachments {
+		return false
+	}
+	if f.Unread && msg.IsRead {
+		return false
+	}
+	return true
+}
+
+// MailHandler is called for each new message that matches a MailWatcher's
+// filter.
+type MailHandler func(ctx context.Context, msg graph.EmailMessage) error
+
+// MailWatcher triggers a handler as new mail arrives in a folder, either
+// from Graph change notifications (Listen) or by polling (Poll).
+type MailWatcher struct {
+	Outlook       *graph.Outlook
+	Subscriptions *graph.Subscriptions
+	FolderID      string // Empty for the whole mailbox when polling, inbox for notifications
+	Filter        MailFilter
+	Interval      time.Duration // Polling interval (default 30s)
+	Handler       MailHandler
+	Logger        *log.Logger
+
+	mu      sync.Mutex
+	run     sync.Mutex // Serializes handler calls
+	started time.Time
+	since   time.Time
+	seen    map[string]time.Time
+}
+
+// pollOverlap re-reads a little before the last message seen, since mail
+// can become visible to queries slightly out of order.
+const pollOverlap = 2 * time.Minute
+
+// NewMailWatcher creates a MailWatcher for mail arriving from now on.
+func NewMailWatcher(outlook *graph.Outlook, folderID string, filter MailFilter) *MailWatcher {
+	now := time.Now().UTC()
+	return &MailWatcher{
+		Outlook:       outlook,
+		Subscriptions: graph.NewSubscriptions(outlook.Client),
+		FolderID:      folderID,
+		Filter:        filter,
+		Interval:      30 * time.Second,
+		Logger:        log.New(os.Stderr, "[watch] ", log.LstdFlags),
+		started:       now,
+		since:         now,
+		seen:          make(map[string]time.Time),
+	}
+}
+
+// Run polls for new mail every Interval until ctx is cancelled.
+func (m *MailWatcher) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Poll(ctx); err != nil && ctx.Err() == nil {
+			m.Logger.Printf("Poll failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll handles messages received since the last poll.
+func (m *MailWatcher) Poll(ctx context.Context) error {
+	m.mu.Lock()
+	since := m.since.Add(-pollOverlap)
+	m.mu.Unlock()
+
+	// Every page is read before acting, so a burst of mail larger than one
+	// page is handled in full
+	var messages []graph.EmailMessage
+	err := m.Outlook.EachMessageInFolder(ctx, m.FolderID, graph.InboxFilter{Since: since}, func(msg graph.EmailMessage) error {
+		messages = append(messages, msg)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Oldest first, so actions run in arrival order
+	for i := len(messages) - 1; i >= 0; i-- {
+		m.handle(ctx, messages[i])
+	}
+	return nil
+}
+
+// Listen serves Graph change notifications on addr and subscribes to new
+// mail with publicURL, the address Graph can reach addr at. The
+// subscription is renewed while running and deleted on exit. If Graph
+// won't create it (e.g. publicURL is unreachable), Listen falls back to
+// polling.
+func (m *MailWatcher) Listen(ctx context.Context, addr, publicURL string) error {
+	state, err := clientState()
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on %s: %w", addr, err)
+	}
+	server := &http.Server{
+		Handler: graph.NotificationHandler(state, func(n graph.ChangeNotification) {
+			m.handleNotification(ctx, n)
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(ln)
+	defer server.Shutdown(context.Background())
+
+	sub, err := m.Subscriptions.Create(ctx, m.Outlook.MailSubscription(m.FolderID, publicURL, state))
+	if err != nil {
+		m.Logger.Printf("Could not subscribe to mail notifications: %v", err)
+		m.Logger.Printf("Polling every %s instead", m.Interval)
+		return m.Run(ctx)
+	}
+	m.Logger.Printf("Subscribed to new mail (subscription %s, expires %s)", sub.ID, sub.ExpirationDateTime.Local().Format("2006-01-02 15:04"))
+	defer func() {
+		if err := m.Subscriptions.Delete(context.Background(), sub.ID); err != nil {
+			m.Logger.Printf("Could not delete subscription %s: %v", sub.ID, err)
+		}
+	}()
+
+	renew := time.NewTicker(graph.MailSubscriptionLifetime / 2)
+	defer renew.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-renew.C:
+			if _, err := m.Subscriptions.Renew(ctx, sub.ID, time.Now().Add(graph.MailSubscriptionLifetime)); err != nil {
+				m.Logger.Printf("Could not renew subscription %s: %v", sub.ID, err)
+			}
+		}
+	}
+}
+
+func (m *MailWatcher) handleNotification(ctx context.Context, n graph.ChangeNotification) {
+	if n.ResourceData.ID == "" {
+		return
+	}
+	msg, err := m.Outlook.GetMessage(ctx, n.ResourceData.ID)
+	if err != nil {
+		m.Logger.Printf("Could not fetch new message: %v", err)
+		return
+	}
+	m.handle(ctx, *msg)
+}
+
+// handle runs the handler once per message that matches the filter.
+func (m *MailWatcher) handle(ctx context.Context, msg graph.EmailMessage) {
+	m.mu.Lock()
+	if _, ok := m.seen[msg.ID]; ok {
+		m.mu.Unlock()
+		return
+	}
+	m.seen[msg.ID] = msg.ReceivedAt
+	if msg.ReceivedAt.After(m.since) {
+		m.since = msg.ReceivedAt
+	}
+	// Forget messages too old to come back from a poll
+	for id, received := range m.seen {
+		if received.Before(m.since.Add(-2 * pollOverlap)) {
+			delete(m.seen, id)
+		}
+	}
+	m.mu.Unlock()
+
+	if msg.ReceivedAt.Before(m.started) || !m.Filter.Matches(msg) || m.Handler == nil {
+		return
+	}
+
+	m.run.Lock()
+	defer m.run.Unlock()
+	if err := m.Handler(ctx, msg); err != nil {
+		m.Logger.Printf("Action failed for %q: %v", msg.Subject, err)
+	}
+}
+
+// clientState returns a random secret that Graph echoes in notifications.
+func clientState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,137 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/klytics/m365kit/internal/graph"
+)
+
+func TestParseMailFilter(t *testing.T) {
+	f, err := ParseMailFilter([]string{`from:Alerts@ subject:"daily report"`, "has:attachment"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := func(from, subject string, attachments bool) graph.EmailMessage {
+		return graph.EmailMessage{
+			Subject:        subject,
+			From:           graph.EmailRecipient{EmailAddress: graph.EmailAddr{Address: from}},
+			HasAttachments: attachments,
+		}
+	}
+	tests := []struct {
+		msg  graph.EmailMessage
+		want bool
+	}{
+		{msg("alerts@contoso.com", "Daily Report 2026-10-16", true), true},
+		{msg("alerts@contoso.com", "Daily Report", false), false},
+		{msg("bob@contoso.com", "Daily Report", true), false},
+		{msg("alerts@contoso.com", "Weekly report", true), false},
+	}
+	for _, tt := range tests {
+		if got := f.Matches(tt.msg); got != tt.want {
+			t.Errorf("Matches(%s, %q) = %v, want %v", tt.msg.From.EmailAddress.Address, tt.msg.Subject, got, tt.want)
+		}
+	}
+
+	if _, err := ParseMailFilter([]string{"to:me"}); err == nil {
+		t.Error("expected error for unknown filter")
+	}
+}
+
+// redirect sends Graph requests to a test server.
+type redirect struct{ base string }
+
+func (r redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(r.base)
+	req.URL.Scheme, req.URL.Host = u.Scheme, u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestMailWatcherPoll(t *testing.T) {
+	now := time.Now().UTC()
+	messages := []graph.EmailMessage{
+		{ID: "new2", Subject: "Alert 2", ReceivedAt: now.Add(2 * time.Second), From: graph.EmailRecipient{EmailAddress: graph.EmailAddr{Address: "alerts@contoso.com"}}},
+		{ID: "other", Subject: "Lunch", ReceivedAt: now.Add(time.Second), From: graph.EmailRecipient{EmailAddress: graph.EmailAddr{Address: "bob@contoso.com"}}},
+		{ID: "new1", Subject: "Alert 1", ReceivedAt: now.Add(time.Second), From: graph.EmailRecipient{EmailAddress: graph.EmailAddr{Address: "alerts@contoso.com"}}},
+		{ID: "old", Subject: "Alert 0", ReceivedAt: now.Add(-time.Minute), From: graph.EmailRecipient{EmailAddress: graph.EmailAddr{Address: "alerts@contoso.com"}}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("$filter") == "" {
+			t.Error("poll without a receivedDateTime filter")
+		}
+		json.NewEncoder(w).Encode(map[string]any{"value": messages})
+	}))
+	defer server.Close()
+
+	o := graph.NewOutlook(&http.Client{Transport: redirect{server.URL}})
+	f, _ := ParseMailFilter([]string{"from:alerts@"})
+	m := NewMailWatcher(o, "", f)
+	m.Logger = log.New(io.Discard, "", 0)
+	m.started = now
+
+	var handled []string
+	m.Handler = func(ctx context.Context, msg graph.EmailMessage) error {
+		handled = append(handled, msg.ID)
+		return nil
+	}
+
+	// The second poll sees the same messages and must not rerun actions.
+	for i := 0; i < 2; i++ {
+		if err := m.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(handled) != 2 || handled[0] != "new1" || handled[1] != "new2" {
+		t.Errorf("handled = %v, want [new1 new2]", handled)
+	}
+}
+
+func TestMailWatcherPollReadsEveryPage(t *testing.T) {
+	now := time.Now().UTC()
+	var page1, page2 []graph.EmailMessage
+	for i := 0; i < 120; i++ {
+		msg := graph.EmailMessage{ID: fmt.Sprintf("m%d", i), ReceivedAt: now.Add(time.Duration(120-i) * time.Second)}
+		if i < 100 {
+			page1 = append(page1, msg)
+		} else {
+			page2 = append(page2, msg)
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode(map[string]any{"value": page2})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"value":           page1,
+			"@odata.nextLink": "https://graph.microsoft.com/v1.0/me/messages?page=2",
+		})
+	}))
+	defer server.Close()
+
+	m := NewMailWatcher(graph.NewOutlook(&http.Client{Transport: redirect{server.URL}}), "", MailFilter{})
+	m.Logger = log.New(io.Discard, "", 0)
+	m.started = now
+
+	var handled []string
+	m.Handler = func(ctx context.Context, msg graph.EmailMessage) error {
+		handled = append(handled, msg.ID)
+		return nil
+	}
+	if err := m.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(handled) != 120 || handled[0] != "m119" || handled[119] != "m0" {
+		t.Errorf("handled %d messages, first %q; want 120 oldest first", len(handled), handled[0])
+	}
+}
@@ -0,0 +1,169 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the action latency
+// histogram.
+var latencyBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300}
+
+// metrics counts what the watcher has done, for ServeMetrics. Its fields
+// are guarded by the watcher's mu.
+type metrics struct {
+	seen      int
+	processed map[string]int // by rule ID
+	failed    map[string]int // by rule ID; "" for files that failed before a rule ran
+	skipped   int
+	latency   map[string]*histogram // by rule ID
+}
+
+type histogram struct {
+	counts []int // per bucket, not cumulative; the last is +Inf
+	sum    float64
+	total  int
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		processed: make(map[string]int),
+		failed:    make(map[string]int),
+		latency:   make(map[string]*histogram),
+	}
+}
+
+// observe records an action of rule that took d.
+func (m *metrics) observe(rule string, d time.Duration) {
+	h := m.latency[rule]
+	if h == nil {
+		h = &histogram{counts: make([]int, len(latencyBuckets)+1)}
+		m.latency[rule] = h
+	}
+	secs := d.Seconds()
+	i := sort.SearchFloat64s(latencyBuckets, secs)
+	h.counts[i]++
+	h.sum += secs
+	h.total++
+}
+
+// count records the outcome of evt.
+func (m *metrics) count(evt Event) {
+	switch evt.Status {
+	case "processed":
+		m.processed[evt.RuleID]++
+	case "error":
+		m.failed[evt.RuleID]++
+	}
+}
+
+// ServeMetrics serves the watcher's counters in the Prometheus text format
+// at /metrics on addr until ctx is done. An address without a host (":9464"
+// or "9464") listens on localhost only.
+func (w *Watcher) ServeMetrics(ctx context.Context, addr string) error {
+	addr = metricsAddr(addr)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("could not open metrics port: %w", err)
+	}
+	w.mu.Lock()
+	w.metricsURL = "http://" + ln.Addr().String() + "/metrics"
+	w.mu.Unlock()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.WriteMetrics(rw)
+	})
+
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// metricsAddr fills in localhost for an address without a host.
+func metricsAddr(addr string) string {
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+	if strings.HasPrefix(addr, ":") {
+		addr = "127.0.0.1" + addr
+	}
+	return addr
+}
+
+// WriteMetrics writes the watcher's counters in the Prometheus text format.
+func (w *Watcher) WriteMetrics(out io.Writer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	m := w.metrics
+
+	gauge := func(name, help string, v float64) {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, formatFloat(v))
+	}
+	counter := func(name, help string, v int) {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+	}
+	byRule := func(name, help string, values map[string]int) {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		for _, rule := range sortedKeys(values) {
+			fmt.Fprintf(out, "%s{rule=%s} %d\n", name, quoteLabel(rule), values[rule])
+		}
+	}
+
+	if !w.started.IsZero() {
+		gauge("kit_watch_start_time_seconds", "When the watcher started, as a Unix time.", float64(w.started.Unix()))
+	}
+	counter("kit_watch_events_seen_total", "Files the watcher has queued for its rules.", m.seen)
+	byRule("kit_watch_events_processed_total", "Files a rule handled successfully.", m.processed)
+	byRule("kit_watch_events_failed_total", "Files a rule failed on; rule=\"\" for files that failed before a rule ran.", m.failed)
+	counter("kit_watch_events_skipped_total", "Files no rule matched.", m.skipped)
+	gauge("kit_watch_queued_files", "Files waiting for a worker.", float64(len(w.queued)+w.parkedCount()))
+	gauge("kit_watch_active_files", "Files being handled.", float64(w.active))
+
+	name := "kit_watch_action_duration_seconds"
+	fmt.Fprintf(out, "# HELP %s How long a rule's actions took for a file.\n# TYPE %s histogram\n", name, name)
+	for _, rule := range sortedKeys(m.latency) {
+		h, label := m.latency[rule], quoteLabel(rule)
+		cum := 0
+		for i, le := range latencyBuckets {
+			cum += h.counts[i]
+			fmt.Fprintf(out, "%s_bucket{rule=%s,le=\"%s\"} %d\n", name, label, formatFloat(le), cum)
+		}
+		fmt.Fprintf(out, "%s_bucket{rule=%s,le=\"+Inf\"} %d\n", name, label, h.total)
+		fmt.Fprintf(out, "%s_sum{rule=%s} %s\n", name, label, formatFloat(h.sum))
+		fmt.Fprintf(out, "%s_count{rule=%s} %d\n", name, label, h.total)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// quoteLabel quotes a label value as the text format expects.
+func quoteLabel(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
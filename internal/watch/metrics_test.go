@@ -0,0 +1,78 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	w, err := New(WatchConfig{Rules: []Rule{
+		{ID: "ok", Extensions: []string{".docx"}, Enabled: true},
+		{ID: "bad", Extensions: []string{".xlsx"}, Enabled: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.watcher.Close()
+	w.Handler = func(path string, rule Rule) error {
+		if rule.ID == "bad" {
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	w.dispatch(filepath.Join("in", "a.docx"), "CREATE", "")
+	w.dispatch(filepath.Join("in", "b.docx"), "CREATE", "")
+	w.dispatch(filepath.Join("in", "c.xlsx"), "CREATE", "")
+	w.dispatch(filepath.Join("in", "d.pdf"), "CREATE", "")
+	waitIdle(t, w)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.ServeMetrics(ctx, "127.0.0.1:0")
+	var url string
+	for deadline := time.Now().Add(5 * time.Second); url == "" && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		url = w.GetStatus().Metrics
+	}
+	if url == "" {
+		t.Fatal("metrics were not served")
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	for _, want := range []string{
+		"kit_watch_events_seen_total 4\n",
+		`kit_watch_events_processed_total{rule="ok"} 2` + "\n",
+		`kit_watch_events_failed_total{rule="bad"} 1` + "\n",
+		"kit_watch_events_skipped_total 1\n",
+		"kit_watch_queued_files 0\n",
+		`kit_watch_action_duration_seconds_bucket{rule="ok",le="0.1"} 2` + "\n",
+		`kit_watch_action_duration_seconds_count{rule="bad"} 1` + "\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsAddr(t *testing.T) {
+	for in, want := range map[string]string{
+		"9464":         "127.0.0.1:9464",
+		":9464":        "127.0.0.1:9464",
+		"0.0.0.0:9464": "0.0.0.0:9464",
+	} {
+		if got := metricsAddr(in); got != want {
+			t.Errorf("metricsAddr(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package watch
+
+// Settled files wait in a queue for one of Config.Workers workers, so a
+// bulk copy doesn't start an action for every file at once. Workers start
+// as files arrive and exit when the queue is empty. A file whose rule is
+// already handling Rule.Concurrency files is parked on the rule and goes
+// back to the front of the queue when the rule has room, so it doesn't
+// hold up files for other rules.
+
+// job is a file waiting for a worker.
+type job struct {
+	path      string
+	operation string
+	source    string
+
+	matched bool   // rules has been worked out
+	rules   []Rule // the rules that handle the file
+	next    int    // index of the next rule to run
+}
+
+// dispatch queues the file at path for the worker pool.
+func (w *Watcher) dispatch(path, operation, source string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.metrics.seen++
+	w.queued = append(w.queued, job{path: path, operation: operation, source: source})
+	w.spawn()
+}
+
+// spawn starts a worker if the pool has room. w.mu must be held.
+func (w *Watcher) spawn() {
+	if w.workers < w.Config.Workers {
+		w.workers++
+		go w.work()
+	}
+}
+
+// work handles queued files until the queue is empty or the watcher
+// stops.
+func (w *Watcher) work() {
+	for {
+		w.mu.Lock()
+		if len(w.queued) == 0 || (w.ctx != nil && w.ctx.Err() != nil) {
+			if n := len(w.queued) + w.parkedCount(); n > 0 && w.workers == 1 {
+				w.Logger.Printf("Dropping %d queued file(s)", n)
+				w.queued = nil
+				clear(w.parked)
+			}
+			w.workers--
+			w.mu.Unlock()
+			return
+		}
+		j := w.queued[0]
+		w.queued = w.queued[1:]
+		w.active++
+		w.mu.Unlock()
+
+		w.processFile(&j)
+
+		w.mu.Lock()
+		w.active--
+		w.mu.Unlock()
+	}
+}
+
+// takeSlot counts j's file against rule's Concurrency, or parks j and
+// returns false when the rule has no room.
+func (w *Watcher) takeSlot(rule Rule, j *job) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if rule.Concurrency > 0 && w.ruleActive[rule.ID] >= rule.Concurrency {
+		w.parked[rule.ID] = append(w.parked[rule.ID], *j)
+		return false
+	}
+	w.ruleActive[rule.ID]++
+	return true
+}
+
+// freeSlot gives back the place takeSlot took, and requeues the first
+// file waiting for it.
+func (w *Watcher) freeSlot(rule Rule) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.ruleActive[rule.ID]--
+	if waiting := w.parked[rule.ID]; len(waiting) > 0 {
+		w.parked[rule.ID] = waiting[1:]
+		w.queued = append([]job{waiting[0]}, w.queued...)
+		w.spawn()
+	}
+}
+
+// parkedCount returns how many files wait for a rule. w.mu must be held.
+func (w *Watcher) parkedCount() int {
+	n := 0
+	for _, jobs := range w.parked {
+		n += len(jobs)
+	}
+	return n
+}
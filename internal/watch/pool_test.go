@@ -0,0 +1,75 @@
+package watch
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitIdle waits for w's queue to drain and its workers to finish.
+func waitIdle(t *testing.T, w *Watcher) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		w.mu.Lock()
+		idle := w.workers == 0
+		w.mu.Unlock()
+		if idle {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timeout waiting for the workers")
+}
+
+func TestWorkerPool(t *testing.T) {
+	w, err := New(WatchConfig{
+		Rules: []Rule{
+			{ID: "slow", Extensions: []string{".docx"}, Concurrency: 1, Enabled: true},
+			{ID: "fast", Extensions: []string{".xlsx"}, Enabled: true},
+		},
+		Workers: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.watcher.Close()
+
+	var (
+		mu      sync.Mutex
+		running = map[string]int{}
+		peak    = map[string]int{}
+	)
+	w.Handler = func(path string, rule Rule) error {
+		mu.Lock()
+		running[rule.ID]++
+		running["all"]++
+		peak[rule.ID] = max(peak[rule.ID], running[rule.ID])
+		peak["all"] = max(peak["all"], running["all"])
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		running[rule.ID]--
+		running["all"]--
+		mu.Unlock()
+		return nil
+	}
+
+	for i := 0; i < 10; i++ {
+		w.dispatch(filepath.Join("in", fmt.Sprintf("%d.docx", i)), "CREATE", "")
+		w.dispatch(filepath.Join("in", fmt.Sprintf("%d.xlsx", i)), "CREATE", "")
+	}
+	if st := w.GetStatus(); st.Queued+st.Active == 0 {
+		t.Errorf("status = %+v, want queued files", st)
+	}
+	waitIdle(t, w)
+
+	if got := len(w.GetEvents()); got != 20 {
+		t.Errorf("events = %d, want 20", got)
+	}
+	if peak["all"] > 3 || peak["slow"] != 1 || peak["fast"] < 2 {
+		t.Errorf("peak = %v, want all ≤ 3, slow = 1, fast ≥ 2", peak)
+	}
+}
@@ -0,0 +1,19 @@
+package watch
+
+import "syscall"
+
+// fileReady reports whether the file at path can be opened without
+// sharing, which fails while Office or a copy still has it open for
+// writing.
+func fileReady(path string) bool {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false
+	}
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ, 0, nil, syscall.OPEN_EXISTING, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return false
+	}
+	syscall.CloseHandle(h)
+	return true
+}
@@ -0,0 +1,204 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/remote"
+)
+
+// Remote folders (onedrive:/Incoming, sp:<site>/<library>/Incoming) are
+// watched by polling their drive's delta query. New and changed files a
+// rule matches are downloaded and handled like local files.
+
+// driveFor returns the drive holding a remote folder; tests replace it.
+var driveFor = func(ctx context.Context, p *remote.Path) (*graph.OneDrive, error) {
+	client, err := graphClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return remote.NewResolver(client).Drive(ctx, p)
+}
+
+// remoteFolder is the polling state of one watched remote folder.
+type remoteFolder struct {
+	path      *remote.Path
+	drive     *graph.OneDrive
+	since     time.Time
+	deltaLink string
+	folders   map[string]string // folder item ID → path in the drive
+	seen      map[string]string // file item ID → content fingerprint
+}
+
+// openRemote opens the remote folder p and starts tracking its changes.
+func openRemote(ctx context.Context, p *remote.Path) (*remoteFolder, error) {
+	drive, err := driveFor(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %w", p, err)
+	}
+	f := &remoteFolder{
+		path:    p,
+		drive:   drive,
+		since:   time.Now(),
+		folders: make(map[string]string),
+		seen:    make(map[string]string),
+	}
+	if _, f.deltaLink, err = drive.Delta(ctx, ""); err != nil {
+		return nil, fmt.Errorf("could not watch %s: %w", p, err)
+	}
+	return f, nil
+}
+
+// watchRemote opens the remote folder p, then polls it every PollInterval
+// until ctx is done.
+func (w *Watcher) watchRemote(ctx context.Context, p *remote.Path) error {
+	f, err := openRemote(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	interval := time.Duration(w.Config.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.pollRemote(ctx, f); err != nil && ctx.Err() == nil {
+					w.Logger.Printf("Polling %s failed: %v", f.path, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// pollRemote handles the files changed in f since the last poll.
+func (w *Watcher) pollRemote(ctx context.Context, f *remoteFolder) error {
+	items, link, err := f.drive.Delta(ctx, f.deltaLink)
+	if errors.Is(err, graph.ErrDeltaExpired) {
+		w.Logger.Printf("Change tracking for %s expired; changes since the last poll may be missed", f.path)
+		_, link, err = f.drive.Delta(ctx, "")
+	}
+	if err != nil {
+		return err
+	}
+	f.deltaLink = link
+
+	for _, item := range items {
+		switch {
+		case item.Deleted:
+			delete(f.seen, item.ID)
+			delete(f.folders, item.ID)
+		case item.IsFolder:
+			// A renamed or moved folder changes the paths below it
+			clear(f.folders)
+		default:
+			w.handleRemoteFile(ctx, f, item)
+		}
+	}
+	return nil
+}
+
+// handleRemoteFile downloads item and runs the rules for it, if it is in
+// the watched folder and a rule wants it.
+func (w *Watcher) handleRemoteFile(ctx context.Context, f *remoteFolder, item graph.DriveItem) {
+	fingerprint := item.QuickXorHash + item.SHA256Hash + "/" + strconv.FormatInt(item.Size, 10) + "/" + item.LastModifiedAt.String()
+	previous, known := f.seen[item.ID]
+	if previous == fingerprint {
+		return // only metadata changed
+	}
+
+	dir, err := f.folderPath(ctx, item.ParentID)
+	if err != nil {
+		w.Logger.Printf("Could not locate %s in %s: %v", item.Name, f.path, err)
+		return
+	}
+	rel, ok := f.relative(dir, w.Config.Recursive)
+	if !ok || !w.wantsFile(item.Name) {
+		return
+	}
+	f.seen[item.ID] = fingerprint
+
+	operation := "modify"
+	if !known && !item.CreatedAt.Before(f.since) {
+		operation = "create"
+	}
+	remotePath := strings.TrimSuffix(dir, "/") + "/" + item.Name
+	source := f.ref(remotePath)
+	local := filepath.Join(w.downloadDir(), safeName(f.path.Raw), filepath.FromSlash(rel), item.Name)
+
+	if _, err := f.drive.DownloadFile(ctx, strings.TrimPrefix(remotePath, "/"), local); err != nil {
+		w.Logger.Printf("Could not download %s: %v", source, err)
+		w.record(Event{
+			Time:      time.Now(),
+			Path:      local,
+			Source:    source,
+			Operation: operation,
+			Status:    "error",
+			Error:     "download failed: " + err.Error(),
+		})
+		return
+	}
+	w.dispatch(local, operation, source)
+}
+
+// folderPath returns the drive path of the folder with the given item ID.
+func (f *remoteFolder) folderPath(ctx context.Context, id string) (string, error) {
+	if p, ok := f.folders[id]; ok {
+		return p, nil
+	}
+	item, err := f.drive.GetItemByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	p := graph.DrivePath(*item)
+	f.folders[id] = p
+	return p, nil
+}
+
+// relative returns where dir, a folder in the drive, lies below the watched
+// folder ("" for the folder itself). Subfolders count only when recursive.
+func (f *remoteFolder) relative(dir string, recursive bool) (string, bool) {
+	base := strings.TrimSuffix("/"+strings.Trim(f.path.Path, "/"), "/")
+	if strings.EqualFold(dir, base) || (base == "" && dir == "/") {
+		return "", true
+	}
+	if !recursive || len(dir) <= len(base)+1 || !strings.EqualFold(dir[:len(base)+1], base+"/") {
+		return "", false
+	}
+	return dir[len(base)+1:], true
+}
+
+// ref returns the remote reference of a path in f's drive, in the form
+// the folder was given (onedrive:/... or sp:<site>/<library>/...).
+func (f *remoteFolder) ref(drivePath string) string {
+	if f.path.Scheme == remote.SchemeSharePoint {
+		return remote.SchemeSharePoint + ":" + f.path.Site + "/" + f.path.Library + drivePath
+	}
+	return remote.SchemeOneDrive + ":" + drivePath
+}
+
+func (w *Watcher) downloadDir() string {
+	if w.Config.DownloadDir != "" {
+		return w.Config.DownloadDir
+	}
+	return filepath.Join(DefaultConfigDir(), "watch-downloads")
+}
+
+// safeName turns a remote reference into a directory name.
+func safeName(ref string) string {
+	parts := strings.FieldsFunc(ref, func(r rune) bool { return r == ':' || r == '/' || r == '\\' })
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,167 @@
+package watch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/klytics/m365kit/internal/graph"
+	"github.com/klytics/m365kit/internal/remote"
+)
+
+// driveServer fakes a drive with an Incoming folder (and Incoming/2024
+// below it) whose delta query reports items as changed on every poll.
+func driveServer(t *testing.T, items string) (downloads *[]string) {
+	t.Helper()
+	var (
+		mu  sync.Mutex
+		got []string
+		srv *httptest.Server
+	)
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.EscapedPath()
+		switch {
+		case strings.HasSuffix(path, "/root/delta"):
+			value := items
+			if r.URL.Query().Get("token") == "latest" {
+				value = "[]"
+			}
+			w.Write([]byte(`{"value": ` + value + `, "@odata.deltaLink": "` + srv.URL + `/v1.0/me/drive/root/delta?token=next"}`))
+		case path == "/v1.0/me/drive/items/in":
+			w.Write([]byte(`{"id": "in", "name": "Incoming", "folder": {}, "parentReference": {"path": "/drive/root:"}}`))
+		case path == "/v1.0/me/drive/items/sub":
+			w.Write([]byte(`{"id": "sub", "name": "2024", "folder": {}, "parentReference": {"path": "/drive/root:/Incoming"}}`))
+		case path == "/v1.0/me/drive/items/other":
+			w.Write([]byte(`{"id": "other", "name": "Other", "folder": {}, "parentReference": {"path": "/drive/root:"}}`))
+		case strings.HasPrefix(path, "/v1.0/me/drive/root:/"):
+			name, _ := url.PathUnescape(strings.TrimPrefix(path, "/v1.0/me/drive/root:/"))
+			mu.Lock()
+			got = append(got, name)
+			mu.Unlock()
+			w.Write([]byte(`{"id": "x", "name": "` + filepath.Base(name) + `", "@microsoft.graph.downloadUrl": "` + srv.URL + `/content/` + url.PathEscape(name) + `"}`))
+		case strings.HasPrefix(path, "/content/"):
+			w.Write([]byte("data"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	old := driveFor
+	driveFor = func(ctx context.Context, p *remote.Path) (*graph.OneDrive, error) {
+		return graph.NewOneDrive(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+			return http.DefaultTransport.RoundTrip(r)
+		})}), nil
+	}
+	t.Cleanup(func() { driveFor = old })
+	return &got
+}
+
+func TestRemoteWatch(t *testing.T) {
+	t.Setenv("KIT_AZURE_CLOUD", "")
+	downloads := driveServer(t, `[
+		{"id": "1", "name": "q1.xlsx", "file": {}, "parentReference": {"id": "in"}},
+		{"id": "2", "name": "photo.png", "file": {}, "parentReference": {"id": "in"}},
+		{"id": "3", "name": "q2.xlsx", "file": {}, "parentReference": {"id": "other"}},
+		{"id": "4", "name": "q3.xlsx", "file": {}, "parentReference": {"id": "sub"}},
+		{"id": "5", "name": "gone.xlsx", "deleted": {}},
+		{"id": "sub", "name": "2024", "folder": {}, "parentReference": {"id": "in"}}
+	]`)
+
+	dir := t.TempDir()
+	w, err := New(WatchConfig{
+		Directories: []string{"onedrive:/Incoming"},
+		Rules:       []Rule{{ID: "sheets", Extensions: []string{".xlsx"}, Enabled: true}},
+		Recursive:   true,
+		DownloadDir: dir,
+		Workers:     1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.watcher.Close()
+	var handled []string
+	w.Handler = func(path string, rule Rule) error {
+		handled = append(handled, path)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p, _ := remote.Parse("onedrive:/Incoming")
+	f, err := openRemote(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.pollRemote(ctx, f); err != nil {
+		t.Fatal(err)
+	}
+	waitIdle(t, w)
+
+	if got := strings.Join(*downloads, ","); got != "Incoming/q1.xlsx,Incoming/2024/q3.xlsx" {
+		t.Errorf("downloaded %s", got)
+	}
+	want := []string{
+		filepath.Join(dir, "onedrive_Incoming", "q1.xlsx"),
+		filepath.Join(dir, "onedrive_Incoming", "2024", "q3.xlsx"),
+	}
+	if strings.Join(handled, ",") != strings.Join(want, ",") {
+		t.Errorf("handled %q, want %q", handled, want)
+	}
+	if data, _ := os.ReadFile(want[0]); string(data) != "data" {
+		t.Errorf("downloaded content = %q", data)
+	}
+	events := w.GetEvents()
+	if len(events) != 2 || events[0].Source != "onedrive:/Incoming/q1.xlsx" || events[0].Status != "processed" {
+		t.Errorf("events = %+v", events)
+	}
+
+	// The same content again is not handled twice
+	if err := w.pollRemote(ctx, f); err != nil {
+		t.Fatal(err)
+	}
+	waitIdle(t, w)
+	if len(handled) != 2 {
+		t.Errorf("handled again: %q", handled)
+	}
+}
+
+func TestRemoteFolderRelative(t *testing.T) {
+	f := &remoteFolder{path: &remote.Path{Path: "Incoming"}}
+	tests := []struct {
+		dir       string
+		recursive bool
+		want      string
+		ok        bool
+	}{
+		{"/Incoming", false, "", true},
+		{"/incoming", false, "", true},
+		{"/Incoming/2024", false, "", false},
+		{"/Incoming/2024", true, "2024", true},
+		{"/IncomingOld", true, "", false},
+		{"/", true, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := f.relative(tt.dir, tt.recursive)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("relative(%q, %v) = %q, %v", tt.dir, tt.recursive, got, ok)
+		}
+	}
+
+	root := &remoteFolder{path: &remote.Path{Path: "/"}}
+	if rel, ok := root.relative("/", false); !ok || rel != "" {
+		t.Errorf("root relative = %q, %v", rel, ok)
+	}
+	if rel, ok := root.relative("/Docs", true); !ok || rel != "Docs" {
+		t.Errorf("root subfolder relative = %q, %v", rel, ok)
+	}
+}
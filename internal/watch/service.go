@@ -0,0 +1,172 @@
+package watch
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ServiceKinds are the service managers kit writes definitions for:
+// a systemd user unit, a launchd agent, and a Windows Task Scheduler
+// logon task.
+var ServiceKinds = []string{"systemd", "launchd", "windows"}
+
+// Service is a definition that runs a watcher when the user logs in.
+type Service struct {
+	Kind    string `json:"kind"`
+	Path    string `json:"path"`    // where the definition goes
+	Content string `json:"-"`       // the definition itself
+	Enable  string `json:"enable"`  // command that registers and starts it
+	Disable string `json:"disable"` // command that stops and removes it
+}
+
+// NewService builds a service definition for kind that runs exe with args
+// from workDir. It logs to LogFile(dir) where the service manager allows.
+func NewService(kind, dir, exe string, args []string, workDir string) (*Service, error) {
+	home, _ := os.UserHomeDir()
+	switch kind {
+	case "systemd":
+		path := filepath.Join(home, ".config", "systemd", "user", "kit-watch.service")
+		return &Service{
+			Kind:    kind,
+			Path:    path,
+			Content: systemdUnit(exe, args, workDir),
+			Enable:  "systemctl --user daemon-reload && systemctl --user enable --now kit-watch",
+			Disable: "systemctl --user disable --now kit-watch && rm " + path,
+		}, nil
+	case "launchd":
+		path := filepath.Join(home, "Library", "LaunchAgents", "com.klytics.kit.watch.plist")
+		return &Service{
+			Kind:    kind,
+			Path:    path,
+			Content: launchdPlist(exe, args, workDir, LogFile(dir)),
+			Enable:  "launchctl load -w " + path,
+			Disable: "launchctl unload -w " + path + " && rm " + path,
+		}, nil
+	case "windows":
+		path := filepath.Join(dir, "kit-watch-task.xml")
+		return &Service{
+			Kind:    kind,
+			Path:    path,
+			Content: windowsTask(exe, args, workDir),
+			Enable:  `schtasks /Create /TN kit-watch /XML "` + path + `" && schtasks /Run /TN kit-watch`,
+			Disable: "schtasks /End /TN kit-watch & schtasks /Delete /TN kit-watch /F",
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown service kind %q (use one of: %s)", kind, strings.Join(ServiceKinds, ", "))
+}
+
+// Write writes the definition to its path.
+func (s *Service) Write() error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, []byte(s.Content), 0644)
+}
+
+func systemdUnit(exe string, args []string, workDir string) string {
+	words := make([]string, 0, len(args)+1)
+	for _, a := range append([]string{exe}, args...) {
+		// systemd expands %specifiers; quote words with spaces or quotes
+		a = strings.ReplaceAll(a, "%", "%%")
+		if strings.ContainsAny(a, " \t\"'\\") {
+			a = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(a) + `"`
+		}
+		words = append(words, a)
+	}
+	return fmt.Sprintf(`[Unit]
+Description=kit file watcher
+After=network-online.target
+
+[Service]
+ExecStart=%s
+WorkingDirectory=%s
+Restart=on-failure
+RestartSec=10
+
+[Install]
+WantedBy=default.target
+`, strings.Join(words, " "), workDir)
+}
+
+func launchdPlist(exe string, args []string, workDir, logPath string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>com.klytics.kit.watch</string>
+	<key>ProgramArguments</key>
+	<array>
+`)
+	for _, a := range append([]string{exe}, args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(a))
+	}
+	fmt.Fprintf(&b, `	</array>
+	<key>WorkingDirectory</key>
+	<string>%s</string>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, xmlEscape(workDir), xmlEscape(logPath), xmlEscape(logPath))
+	return b.String()
+}
+
+func windowsTask(exe string, args []string, workDir string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\"") {
+			a = `"` + strings.ReplaceAll(a, `"`, `\"`) + `"`
+		}
+		quoted[i] = a
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<Task version="1.2" xmlns="http://schemas.microsoft.com/windows/2004/02/mit/task">
+  <RegistrationInfo>
+    <Description>kit file watcher</Description>
+  </RegistrationInfo>
+  <Triggers>
+    <LogonTrigger>
+      <Enabled>true</Enabled>
+    </LogonTrigger>
+  </Triggers>
+  <Settings>
+    <MultipleInstancesPolicy>IgnoreNew</MultipleInstancesPolicy>
+    <DisallowStartIfOnBatteries>false</DisallowStartIfOnBatteries>
+    <StopIfGoingOnBatteries>false</StopIfGoingOnBatteries>
+    <ExecutionTimeLimit>PT0S</ExecutionTimeLimit>
+    <RestartOnFailure>
+      <Interval>PT1M</Interval>
+      <Count>3</Count>
+    </RestartOnFailure>
+  </Settings>
+  <Actions Context="Author">
+    <Exec>
+      <Command>%s</Command>
+      <Arguments>%s</Arguments>
+      <WorkingDirectory>%s</WorkingDirectory>
+    </Exec>
+  </Actions>
+</Task>
+`, xmlEscape(exe), xmlEscape(strings.Join(quoted, " ")), xmlEscape(workDir))
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
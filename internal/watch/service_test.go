@@ -0,0 +1,59 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewService(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	dir := filepath.Join(home, ".kit")
+	args := []string{"watch", "start", "/data/in", "--option=run=echo 100% done"}
+
+	tests := []struct {
+		kind string
+		path string
+		want []string
+	}{
+		{"systemd", filepath.Join(home, ".config", "systemd", "user", "kit-watch.service"), []string{
+			`ExecStart=/usr/local/bin/kit watch start /data/in "--option=run=echo 100%% done"`,
+			"WorkingDirectory=/work",
+		}},
+		{"launchd", filepath.Join(home, "Library", "LaunchAgents", "com.klytics.kit.watch.plist"), []string{
+			"<string>--option=run=echo 100% done</string>",
+			"<string>" + LogFile(dir) + "</string>",
+		}},
+		{"windows", filepath.Join(dir, "kit-watch-task.xml"), []string{
+			`<Arguments>watch start /data/in &#34;--option=run=echo 100% done&#34;</Arguments>`,
+			"<LogonTrigger>",
+		}},
+	}
+	for _, tt := range tests {
+		svc, err := NewService(tt.kind, dir, "/usr/local/bin/kit", args, "/work")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if svc.Path != tt.path {
+			t.Errorf("%s path = %s", tt.kind, svc.Path)
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(svc.Content, w) {
+				t.Errorf("%s definition lacks %q:\n%s", tt.kind, w, svc.Content)
+			}
+		}
+		if err := svc.Write(); err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := os.ReadFile(svc.Path); string(data) != svc.Content {
+			t.Errorf("%s written = %q", tt.kind, data)
+		}
+	}
+
+	if _, err := NewService("upstart", dir, "kit", args, "/work"); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
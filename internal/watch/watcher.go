@@ -9,18 +9,27 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/klytics/m365kit/internal/remote"
 )
 
 // Action defines what to do when a file event is detected.
 type Action struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"`    // "template", "ai", "copy", "command"
+	Name    string            `json:"name"`
+	Type    string            `json:"type"` // see ActionTypes; default "log"
 	Options map[string]string `json:"options,omitempty"`
+
+	// Retries is how many more times the action is tried when it fails,
+	// waiting Backoff (a duration, default 2s) before the first retry and
+	// twice as long before each one after.
+	Retries int    `json:"retries,omitempty"`
+	Backoff string `json:"backoff,omitempty"`
 }
 
 // Rule defines a watch rule: which files to match and what action to take.
@@ -30,6 +39,27 @@ type Rule struct {
 	Extensions []string `json:"extensions"` // File extensions to match
 	Action     Action   `json:"action"`
 	Enabled    bool     `json:"enabled"`
+
+	// Actions, when set, replaces Action with several actions run in
+	// order (see RunRule). Rules are tried from the highest Priority down,
+	// in file order for equal priorities; the first that matches handles
+	// the file, unless it has Continue set, in which case later rules may
+	// handle it too.
+	Actions  []Action `json:"actions,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+	Continue bool     `json:"continue,omitempty"`
+
+	// Concurrency caps how many files the rule handles at once, within
+	// the watcher's Workers (0: no cap of its own).
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// Conditions on the file itself. Name is a regular expression matched
+	// against the file name; Contains is text (case-insensitive) the
+	// document must contain.
+	MinSize  ByteSize `json:"minSize,omitempty"`
+	MaxSize  ByteSize `json:"maxSize,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Contains string   `json:"contains,omitempty"`
 }
 
 // WatchConfig holds the complete watcher configuration.
@@ -38,13 +68,58 @@ type WatchConfig struct {
 	Rules       []Rule   `json:"rules"`
 	Recursive   bool     `json:"recursive"`
 	Debounce    int      `json:"debounceMs"` // Milliseconds to wait before processing
+
+	// StableChecks is how many debounce intervals in a row a file's size
+	// and modification time must stay the same before it is handled
+	// (default 1), so files still being saved or copied are left alone.
+	StableChecks int `json:"stableChecks,omitempty"`
+
+	// Workers is how many files are handled at once (default 4); the
+	// rest wait their turn.
+	Workers int `json:"workers,omitempty"`
+
+	// Remote folders (onedrive:/..., sp:...) are polled every PollInterval
+	// seconds (default 60), and matching files downloaded to DownloadDir
+	// (default ~/.kit/watch-downloads).
+	PollInterval int    `json:"pollSeconds,omitempty"`
+	DownloadDir  string `json:"downloadDir,omitempty"`
+}
+
+// Validate checks the remote directories, and the conditions and action of
+// each rule.
+func (c WatchConfig) Validate() error {
+	for _, dir := range c.Directories {
+		if remote.IsRemote(dir) {
+			if _, err := remote.Parse(dir); err != nil {
+				return err
+			}
+		}
+	}
+	for _, rule := range c.Rules {
+		if err := rule.validateConditions(); err != nil {
+			return fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+		if len(rule.Actions) > 0 && (rule.Action.Type != "" || len(rule.Action.Options) > 0) {
+			return fmt.Errorf("rule %s: use action or actions, not both", rule.ID)
+		}
+		for i, a := range rule.steps() {
+			if err := a.Validate(); err != nil {
+				if len(rule.Actions) > 1 {
+					return fmt.Errorf("rule %s, action %d: %w", rule.ID, i+1, err)
+				}
+				return fmt.Errorf("rule %s: %w", rule.ID, err)
+			}
+		}
+	}
+	return nil
 }
 
 // Event represents a file event that was detected and processed.
 type Event struct {
 	Time      time.Time `json:"time"`
 	Path      string    `json:"path"`
-	Operation string    `json:"operation"` // "create", "modify", "rename"
+	Source    string    `json:"source,omitempty"` // remote file Path was downloaded from
+	Operation string    `json:"operation"`        // "create", "modify", "rename"
 	RuleID    string    `json:"ruleId,omitempty"`
 	Action    string    `json:"action,omitempty"`
 	Status    string    `json:"status"` // "processed", "error", "skipped"
@@ -56,10 +131,25 @@ type Watcher struct {
 	Config   WatchConfig
 	Logger   *log.Logger
 	Events   []Event
-	Handler  EventHandler
+	Handler  EventHandler // replaces the built-in actions (RunAction) when set
+	OnEvent  func(Event)  // called after each matched file is handled
+	Log      *EventLog    // when set, matched events are appended to it
+	Failed   *DeadLetters // when set, files whose rule fails are added to it
+	ctx      context.Context
+	started  time.Time
 	mu       sync.Mutex
 	watcher  *fsnotify.Watcher
 	debounce map[string]*time.Timer
+	dirs     map[string]bool // directories registered with fsnotify
+
+	queued     []job            // settled files waiting for a worker
+	parked     map[string][]job // files waiting for a rule's Concurrency
+	ruleActive map[string]int   // files each rule is handling
+	workers    int              // running workers
+	active     int              // files being handled
+
+	metrics    *metrics
+	metricsURL string // set by ServeMetrics
 }
 
 // EventHandler is called when a matching file event occurs.
@@ -68,10 +158,17 @@ type EventHandler func(path string, rule Rule) error
 // Status represents the current watcher status.
 type Status struct {
 	Running     bool     `json:"running"`
+	PID         int      `json:"pid,omitempty"`
 	Directories []string `json:"directories"`
 	Rules       int      `json:"rules"`
+	Recursive   bool     `json:"recursive"`
+	Queued      int      `json:"queued"`
+	Active      int      `json:"active"`
 	EventCount  int      `json:"eventCount"`
+	ErrorCount  int      `json:"errorCount"`
 	StartedAt   string   `json:"startedAt,omitempty"`
+	Metrics     string   `json:"metrics,omitempty"` // URL of the Prometheus metrics, if served
+	LastEvent   *Event   `json:"lastEvent,omitempty"`
 }
 
 // officeExtensions are the standard Office file extensions.
@@ -91,12 +188,23 @@ func New(config WatchConfig) (*Watcher, error) {
 	if config.Debounce <= 0 {
 		config.Debounce = 500
 	}
+	if config.StableChecks <= 0 {
+		config.StableChecks = 1
+	}
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
 
 	w := &Watcher{
 		Config:   config,
 		Logger:   log.New(os.Stderr, "[watch] ", log.LstdFlags),
 		watcher:  fsw,
 		debounce: make(map[string]*time.Timer),
+		dirs:     make(map[string]bool),
+
+		parked:     make(map[string][]job),
+		ruleActive: make(map[string]int),
+		metrics:    newMetrics(),
 	}
 
 	return w, nil
@@ -104,15 +212,36 @@ func New(config WatchConfig) (*Watcher, error) {
 
 // Start begins watching the configured directories. It blocks until the context is cancelled.
 func (w *Watcher) Start(ctx context.Context) error {
+	if w.Handler == nil {
+		if err := w.Config.Validate(); err != nil {
+			return err
+		}
+	}
+	w.mu.Lock()
+	w.ctx = ctx
+	w.started = time.Now()
+	w.mu.Unlock()
+
 	// Add directories
 	for _, dir := range w.Config.Directories {
+		if remote.IsRemote(dir) {
+			p, err := remote.Parse(dir)
+			if err != nil {
+				return err
+			}
+			if err := w.watchRemote(ctx, p); err != nil {
+				return err
+			}
+			continue
+		}
+
 		absDir, err := filepath.Abs(dir)
 		if err != nil {
 			return fmt.Errorf("could not resolve %s: %w", dir, err)
 		}
 
 		if w.Config.Recursive {
-			if err := w.addRecursive(absDir); err != nil {
+			if err := w.addRecursive(absDir, false); err != nil {
 				return err
 			}
 		} else {
@@ -144,7 +273,10 @@ func (w *Watcher) Start(ctx context.Context) error {
 	}
 }
 
-func (w *Watcher) addRecursive(dir string) error {
+// addRecursive watches dir and the directories below it. With
+// queueFiles, files already in them are handled too: a directory created
+// while the watcher runs may have been filled before it was registered.
+func (w *Watcher) addRecursive(dir string, queueFiles bool) error {
 	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors
@@ -154,90 +286,277 @@ func (w *Watcher) addRecursive(dir string) error {
 			if strings.HasPrefix(filepath.Base(path), ".") && path != dir {
 				return filepath.SkipDir
 			}
-			return w.watcher.Add(path)
+			if err := w.watcher.Add(path); err != nil {
+				return err
+			}
+			w.mu.Lock()
+			w.dirs[path] = true
+			w.mu.Unlock()
+			return nil
+		}
+		if queueFiles {
+			w.queue(path, fsnotify.Create.String())
 		}
 		return nil
 	})
 }
 
-func (w *Watcher) handleEvent(event fsnotify.Event) {
-	// Only process create and write events
-	if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
-		return
+// removeDirs stops watching dir and the directories below it, and drops
+// pending work for files in them.
+func (w *Watcher) removeDirs(dir string) {
+	prefix := dir + string(filepath.Separator)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for d := range w.dirs {
+		if d == dir || strings.HasPrefix(d, prefix) {
+			w.watcher.Remove(d) // fsnotify may have dropped it already
+			delete(w.dirs, d)
+		}
+	}
+	for path, timer := range w.debounce {
+		if strings.HasPrefix(path, prefix) {
+			timer.Stop()
+			delete(w.debounce, path)
+		}
 	}
+}
 
-	path := event.Name
-	ext := strings.ToLower(filepath.Ext(path))
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	// In recursive mode, follow directories as they come and go
+	if w.Config.Recursive {
+		switch {
+		case event.Has(fsnotify.Create):
+			if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+				if strings.HasPrefix(filepath.Base(event.Name), ".") {
+					return
+				}
+				if err := w.addRecursive(event.Name, true); err != nil {
+					w.Logger.Printf("Could not watch %s: %v", event.Name, err)
+				}
+				return
+			}
+		case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+			w.removeDirs(event.Name)
+			return
+		}
+	}
 
-	// Check if it's an office-type file
-	if !officeExtensions[ext] {
+	// Only process create and write events
+	if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
 		return
 	}
+	w.queue(event.Name, event.Op.String())
+}
 
-	// Skip temp files
-	base := filepath.Base(path)
-	if strings.HasPrefix(base, "~$") || strings.HasPrefix(base, ".~") {
+// queue handles the file at path after the debounce interval, if it is
+// one the watcher is interested in.
+func (w *Watcher) queue(path, operation string) {
+	if !w.wantsFile(filepath.Base(path)) {
 		return
 	}
 
 	// Debounce: wait before processing to avoid rapid fire
 	w.mu.Lock()
+	defer w.mu.Unlock()
 	if timer, ok := w.debounce[path]; ok {
 		timer.Stop()
 	}
-	w.debounce[path] = time.AfterFunc(time.Duration(w.Config.Debounce)*time.Millisecond, func() {
-		w.processFile(path, event.Op.String())
-	})
-	w.mu.Unlock()
+	w.settleAfter(path, operation, statFile(path), 0, time.Now())
 }
 
-func (w *Watcher) processFile(path string, operation string) {
-	// Find matching rule
-	for _, rule := range w.Config.Rules {
-		if !rule.Enabled {
-			continue
+// settleTimeout is how long a file may keep changing before the watcher
+// gives up on it.
+const settleTimeout = 10 * time.Minute
+
+// fileState is what the stability check compares between intervals.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{size: -1}
+	}
+	return fileState{info.Size(), info.ModTime()}
+}
+
+// settleAfter checks the file at path again after the debounce interval,
+// and handles it once it has stayed the same for StableChecks intervals
+// and can be opened (see fileReady). Each check is a timer in w.debounce,
+// so a new event for the file, or Stop, cancels it. w.mu must be held.
+func (w *Watcher) settleAfter(path, operation string, last fileState, stable int, since time.Time) {
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Duration(w.Config.Debounce)*time.Millisecond, func() {
+		w.mu.Lock()
+		if w.debounce[path] != timer {
+			w.mu.Unlock()
+			return // superseded by a newer event
 		}
-		if !w.matchesRule(path, rule) {
-			continue
+		current := statFile(path)
+		switch {
+		case current.size < 0:
+			delete(w.debounce, path) // gone, like an editor's temp file
+		case current == last && stable+1 >= w.Config.StableChecks && fileReady(path):
+			delete(w.debounce, path)
+			w.mu.Unlock()
+			w.dispatch(path, operation, "")
+			return
+		case time.Since(since) > settleTimeout:
+			delete(w.debounce, path)
+			w.mu.Unlock()
+			w.Logger.Printf("Skipping %s: still changing or in use after %s", path, settleTimeout)
+			w.record(Event{
+				Time:      time.Now(),
+				Path:      path,
+				Operation: operation,
+				Status:    "error",
+				Error:     fmt.Sprintf("file was still changing or in use after %s", settleTimeout),
+			})
+			return
+		case current == last:
+			w.settleAfter(path, operation, current, stable+1, since)
+		default:
+			w.settleAfter(path, operation, current, 0, since)
 		}
+		w.mu.Unlock()
+	})
+	w.debounce[path] = timer
+}
+
+// wantsFile reports whether the watcher handles files with this name: an
+// office-type file or one a rule asks for, and not an editor's temp file.
+func (w *Watcher) wantsFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if !officeExtensions[ext] && !w.ruleExtension(ext) {
+		return false
+	}
+	return !strings.HasPrefix(name, "~$") && !strings.HasPrefix(name, ".~")
+}
 
+// matchingRules returns the enabled rules that handle path, by priority:
+// the first that matches, and the ones after it while each has Continue.
+func (w *Watcher) matchingRules(path string) []Rule {
+	rules := make([]Rule, len(w.Config.Rules))
+	copy(rules, w.Config.Rules)
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Priority > rules[j].Priority })
+
+	var matched []Rule
+	for _, rule := range rules {
+		if rule.Enabled && w.matchesRule(path, rule) {
+			matched = append(matched, rule)
+			if !rule.Continue {
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// processFile runs the matching rules for the file of j, from j.next on.
+// It stops early when a rule is at its Concurrency limit; j is then
+// parked until the rule has room (see takeSlot).
+func (w *Watcher) processFile(j *job) {
+	if !j.matched {
+		j.rules, j.matched = w.matchingRules(j.path), true
+	}
+	if len(j.rules) == 0 {
+		// No rule matched — still log
+		w.mu.Lock()
+		w.metrics.skipped++
+		w.Events = append(w.Events, Event{
+			Time:      time.Now(),
+			Path:      j.path,
+			Source:    j.source,
+			Operation: j.operation,
+			Status:    "skipped",
+		})
+		w.mu.Unlock()
+		return
+	}
+
+	handler := w.Handler
+	if handler == nil {
+		handler = w.runAction
+	}
+	for ; j.next < len(j.rules); j.next++ {
+		rule := j.rules[j.next]
+		if !w.takeSlot(rule, j) {
+			return
+		}
 		evt := Event{
 			Time:      time.Now(),
-			Path:      path,
-			Operation: operation,
+			Path:      j.path,
+			Source:    j.source,
+			Operation: j.operation,
 			RuleID:    rule.ID,
-			Action:    rule.Action.Name,
+			Action:    rule.ActionNames(),
 		}
-
-		if w.Handler != nil {
-			if err := w.Handler(path, rule); err != nil {
-				evt.Status = "error"
-				evt.Error = err.Error()
-				w.Logger.Printf("Error processing %s: %v", path, err)
-			} else {
-				evt.Status = "processed"
-				w.Logger.Printf("Processed %s (rule: %s, action: %s)", path, rule.ID, rule.Action.Name)
-			}
+		began := time.Now()
+		err := handler(j.path, rule)
+		w.freeSlot(rule)
+		w.mu.Lock()
+		w.metrics.observe(rule.ID, time.Since(began))
+		w.mu.Unlock()
+		if err != nil {
+			evt.Status = "error"
+			evt.Error = err.Error()
+			w.Logger.Printf("Error processing %s (rule: %s): %v", j.path, rule.ID, err)
+			w.deadLetter(evt)
 		} else {
 			evt.Status = "processed"
-			w.Logger.Printf("Matched %s (rule: %s, action: %s) [no handler]", path, rule.ID, rule.Action.Name)
+			w.Logger.Printf("Processed %s (rule: %s, action: %s)", j.path, rule.ID, evt.Action)
 		}
+		w.record(evt)
+	}
+}
 
-		w.mu.Lock()
-		w.Events = append(w.Events, evt)
-		w.mu.Unlock()
-		return
+// record keeps a handled event, appends it to the event log, and passes
+// it to OnEvent.
+func (w *Watcher) record(evt Event) {
+	w.mu.Lock()
+	w.Events = append(w.Events, evt)
+	w.metrics.count(evt)
+	w.mu.Unlock()
+	if w.Log != nil {
+		if err := w.Log.Append(evt); err != nil {
+			w.Logger.Printf("Event log: %v", err)
+		}
+	}
+	if w.OnEvent != nil {
+		w.OnEvent(evt)
 	}
+}
 
-	// No rule matched — still log
+// runAction is the default handler: it runs the rule's built-in action.
+func (w *Watcher) runAction(path string, rule Rule) error {
 	w.mu.Lock()
-	w.Events = append(w.Events, Event{
-		Time:      time.Now(),
-		Path:      path,
-		Operation: operation,
-		Status:    "skipped",
-	})
+	ctx := w.ctx
 	w.mu.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(rule.Actions) == 0 && rule.Action.kind() == ActionLog {
+		return nil
+	}
+	done, err := RunRule(ctx, rule, path)
+	for _, did := range done {
+		w.Logger.Printf("%s: %s", filepath.Base(path), did)
+	}
+	return err
+}
+
+// ruleExtension reports whether an enabled rule lists ext.
+func (w *Watcher) ruleExtension(ext string) bool {
+	for _, rule := range w.Config.Rules {
+		for _, e := range rule.Extensions {
+			if rule.Enabled && strings.EqualFold("."+strings.TrimPrefix(e, "."), ext) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (w *Watcher) matchesRule(path string, rule Rule) bool {
@@ -268,19 +587,41 @@ func (w *Watcher) matchesRule(path string, rule Rule) bool {
 		}
 	}
 
-	return true
+	ok, err := rule.matchesConditions(path)
+	if err != nil {
+		w.Logger.Printf("Rule %s: %v", rule.ID, err)
+	}
+	return ok
 }
 
 // GetStatus returns the current watcher status.
 func (w *Watcher) GetStatus() Status {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	return Status{
+	st := Status{
 		Running:     true,
+		PID:         os.Getpid(),
 		Directories: w.Config.Directories,
 		Rules:       len(w.Config.Rules),
+		Recursive:   w.Config.Recursive,
+		Queued:      len(w.queued) + w.parkedCount(),
+		Active:      w.active,
 		EventCount:  len(w.Events),
+		Metrics:     w.metricsURL,
+	}
+	if !w.started.IsZero() {
+		st.StartedAt = w.started.Format(time.RFC3339)
+	}
+	for _, e := range w.Events {
+		if e.Status == "error" {
+			st.ErrorCount++
+		}
+	}
+	if n := len(w.Events); n > 0 {
+		last := w.Events[n-1]
+		st.LastEvent = &last
 	}
+	return st
 }
 
 // GetEvents returns all recorded events.
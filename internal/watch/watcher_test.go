@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -153,6 +154,59 @@ func TestWatcherEvents(t *testing.T) {
 	cancel()
 }
 
+func TestWatcherWaitsForWrites(t *testing.T) {
+	dir := t.TempDir()
+	w, err := New(WatchConfig{
+		Rules:        []Rule{{ID: "r", Extensions: []string{".docx"}, Enabled: true}},
+		Debounce:     50,
+		StableChecks: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.watcher.Close()
+	sizes := make(chan int64, 2)
+	w.Handler = func(path string, rule Rule) error {
+		info, _ := os.Stat(path)
+		sizes <- info.Size()
+		return nil
+	}
+
+	// A save that keeps growing the file without further events, as on
+	// shares where notifications are coalesced
+	path := filepath.Join(dir, "big.docx")
+	os.WriteFile(path, []byte("x"), 0644)
+	w.queue(path, "CREATE")
+	for i := 2; i <= 10; i++ {
+		time.Sleep(30 * time.Millisecond)
+		os.WriteFile(path, []byte(strings.Repeat("x", i)), 0644)
+	}
+	select {
+	case size := <-sizes:
+		if size != 10 {
+			t.Errorf("handled at %d bytes, want 10", size)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for the file to settle")
+	}
+
+	// A file that goes away before it settles is dropped
+	gone := filepath.Join(dir, "~tmp.docx")
+	os.WriteFile(gone, nil, 0644)
+	w.queue(gone, "CREATE")
+	os.Remove(gone)
+	select {
+	case <-sizes:
+		t.Error("a removed file should not be handled")
+	case <-time.After(300 * time.Millisecond):
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.debounce) != 0 {
+		t.Errorf("pending = %v", w.debounce)
+	}
+}
+
 func TestWatcherSkipsNonOffice(t *testing.T) {
 	dir := t.TempDir()
 
@@ -190,6 +244,65 @@ func TestWatcherSkipsNonOffice(t *testing.T) {
 	cancel()
 }
 
+func TestWatcherNewSubdirectories(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := New(WatchConfig{
+		Directories: []string{dir},
+		Rules:       []Rule{{ID: "r1", Extensions: []string{".docx"}, Enabled: true}},
+		Recursive:   true,
+		Debounce:    50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan string, 10)
+	w.Handler = func(path string, rule Rule) error {
+		handled <- path
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Start(ctx)
+	time.Sleep(100 * time.Millisecond)
+
+	// A nested directory created after start, with a file written at once
+	sub := filepath.Join(dir, "2024", "q1")
+	os.MkdirAll(sub, 0755)
+	first := filepath.Join(sub, "first.docx")
+	os.WriteFile(first, []byte("test"), 0644)
+
+	waitFor := func(want string) {
+		t.Helper()
+		select {
+		case path := <-handled:
+			if path != want {
+				t.Errorf("handled %q, want %q", path, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for %s", want)
+		}
+	}
+	waitFor(first)
+
+	// Once registered, later files in it are seen as well
+	second := filepath.Join(sub, "second.docx")
+	os.WriteFile(second, []byte("test"), 0644)
+	waitFor(second)
+
+	os.RemoveAll(filepath.Join(dir, "2024"))
+	time.Sleep(200 * time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for d := range w.dirs {
+		if d != dir {
+			t.Errorf("still watching removed directory %s", d)
+		}
+	}
+}
+
 func TestPIDFile(t *testing.T) {
 	dir := t.TempDir()
 
@@ -300,3 +413,43 @@ func TestDefaultDebounce(t *testing.T) {
 		t.Errorf("expected default debounce 500, got %d", w.Config.Debounce)
 	}
 }
+
+func TestMatchingRulesPriorityAndContinue(t *testing.T) {
+	w, _ := New(WatchConfig{Rules: []Rule{
+		{ID: "all", Enabled: true},
+		{ID: "docx", Extensions: []string{"docx"}, Priority: 10, Continue: true, Enabled: true},
+		{ID: "reports", Pattern: "report*", Priority: 5, Enabled: true},
+		{ID: "off", Priority: 20, Enabled: false},
+	}})
+	defer w.watcher.Close()
+
+	ids := func(path string) string {
+		var out []string
+		for _, r := range w.matchingRules(path) {
+			out = append(out, r.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	tests := map[string]string{
+		"/tmp/report.docx": "docx,reports", // reports stops the chain before "all"
+		"/tmp/memo.docx":   "docx,all",
+		"/tmp/report.xlsx": "reports",
+		"/tmp/data.csv":    "all",
+	}
+	for path, want := range tests {
+		if got := ids(path); got != want {
+			t.Errorf("%s: rules = %s, want %s", path, got, want)
+		}
+	}
+}
+
+func TestValidateRuleActions(t *testing.T) {
+	both := Rule{ID: "r", Action: Action{Type: ActionLog}, Actions: []Action{{Type: ActionLog}}}
+	if err := (WatchConfig{Rules: []Rule{both}}).Validate(); err == nil {
+		t.Error("a rule with both action and actions should not validate")
+	}
+	bad := Rule{ID: "r", Actions: []Action{{Type: ActionLog}, {Type: ActionCopy}}}
+	if err := (WatchConfig{Rules: []Rule{bad}}).Validate(); err == nil || !strings.Contains(err.Error(), "action 2") {
+		t.Errorf("err = %v", err)
+	}
+}
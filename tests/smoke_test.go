@@ -230,7 +230,7 @@ func TestAllCommandsHaveHelp(t *testing.T) {
 		{"fs", "scan"}, {"fs", "rename"}, {"fs", "dedupe"}, {"fs", "stale"},
 		{"template", "list"}, {"template", "show"}, {"template", "apply"},
 		{"report", "generate"},
-		{"watch", "status"}, {"watch", "stop"},
+		{"watch", "status"}, {"watch", "stop"}, {"watch", "log"},
 		{"send"}, {"diff"}, {"convert"},
 		{"config", "init"}, {"config", "show"}, {"config", "validate"},
 		{"completion", "bash"}, {"completion", "zsh"},
@@ -238,12 +238,12 @@ func TestAllCommandsHaveHelp(t *testing.T) {
 		{"doctor"}, {"version"},
 		// Enterprise (v1.1)
 		{"org", "show"}, {"org", "validate"}, {"org", "init"}, {"org", "status"},
-		{"audit", "log"}, {"audit", "clear"}, {"audit", "status"},
+		{"audit", "log"}, {"audit", "self"}, {"audit", "clear"}, {"audit", "status"},
 		{"admin", "stats"}, {"admin", "users"},
 		{"admin", "telemetry", "status"}, {"admin", "telemetry", "clear"},
 		// Platform (v1.2)
-		{"plugin", "list"}, {"plugin", "new"}, {"plugin", "install"},
-		{"plugin", "remove"}, {"plugin", "run"}, {"plugin", "show"},
+		{"plugin", "list"}, {"plugin", "new"}, {"plugin", "install"}, {"plugin", "update"},
+		{"plugin", "remove"}, {"plugin", "run"}, {"plugin", "show"}, {"plugin", "search"}, {"plugin", "info"},
 		{"shell"},
 	}
 